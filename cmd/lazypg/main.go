@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -13,7 +14,17 @@ import (
 )
 
 func main() {
-	cfg, err := config.Load()
+	configDirFlag := flag.String("config-dir", "", "directory for config, history, favorites and saved connections (default ~/.config/lazypg, or $"+config.DirEnv+")")
+	flag.Parse()
+
+	override := config.Override(*configDirFlag)
+	configDir, err := config.Dir(override)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(override)
 	if err != nil {
 		log.Printf("Warning: Could not load config: %v (using defaults)\n", err)
 		cfg = config.GetDefaults()
@@ -26,7 +37,7 @@ func main() {
 	ctx := context.Background()
 	_ = ctx // Context will be used in later tasks for discovery
 
-	app := app.New(cfg)
+	app := app.New(cfg, configDir)
 
 	opts := []tea.ProgramOption{tea.WithAltScreen()}
 	if cfg.UI.MouseEnabled {
@@ -34,7 +45,13 @@ func main() {
 	}
 
 	p := tea.NewProgram(app, opts...)
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	if err == nil {
+		// A clean exit leaves nothing to recover
+		app.ClearRecovery()
+	}
+	app.Close()
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
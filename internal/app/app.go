@@ -3,10 +3,12 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -26,11 +28,12 @@ import (
 	"github.com/rebelice/lazypg/internal/db/discovery"
 	"github.com/rebelice/lazypg/internal/db/metadata"
 	"github.com/rebelice/lazypg/internal/db/query"
+	"github.com/rebelice/lazypg/internal/export"
 	"github.com/rebelice/lazypg/internal/favorites"
 	filterBuilder "github.com/rebelice/lazypg/internal/filter"
 	"github.com/rebelice/lazypg/internal/history"
-	"github.com/rebelice/lazypg/internal/jsonb"
 	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/recovery"
 	"github.com/rebelice/lazypg/internal/ui/components"
 	"github.com/rebelice/lazypg/internal/ui/help"
 	"github.com/rebelice/lazypg/internal/ui/theme"
@@ -49,6 +52,7 @@ type pendingPassword struct {
 type App struct {
 	state      models.AppState
 	config     *config.Config
+	configDir  string // Where exports without a location of their own, like the schema, go
 	theme      theme.Theme
 	leftPanel  components.Panel
 	rightPanel components.Panel
@@ -63,11 +67,20 @@ type App struct {
 	isConnecting         bool      // True when connection attempt is in progress
 	connectingStart      time.Time // When connection attempt started
 	connectingConfig     models.ConnectionConfig
+	connectAttempt       int       // Number of the attempt in flight, from 1
+	connectRetryErr      error     // Transient error that caused the current retry
+	connectRetryAt       time.Time // When the next attempt starts; zero unless waiting
+
+	// user@host:port already warned of being a superuser this session
+	superuserWarned map[string]bool
 
 	// Error overlay
 	showError    bool
 	errorOverlay *components.ErrorOverlay
 
+	// Transient notices for non-error events
+	toasts *components.ToastStack
+
 	// Phase 3: Navigation tree
 	treeView *components.TreeView
 
@@ -114,16 +127,138 @@ type App struct {
 	// Connection history
 	connectionHistory *connection_history.Manager
 
+	// The history, favorites and connection history open in the background
+	// at startup, as the keyring can prompt for access or hang. A splash
+	// shows until they are open or startupTimeout passes.
+	startupPending  int
+	startupTimedOut bool
+	startedAt       time.Time
+
 	// Password dialog for missing passwords
 	showPasswordDialog    bool
 	passwordDialog        *components.PasswordDialog
 	pendingConnectionInfo *models.ConnectionHistoryEntry
-	pendingPasswordSave   *pendingPassword // Password to save after successful connection
+	pendingPasswordSave   *pendingPassword         // Password to save after successful connection
+	discoveredAttempt     *models.ConnectionConfig // Connection attempt to a discovered instance
 
 	// Search input
 	showSearch  bool
 	searchInput *components.SearchInput
 
+	// CSV import dialog
+	showCSVImport     bool
+	csvImportDialog   *components.CSVImportDialog
+	csvImportCancel   context.CancelFunc
+	csvImportProgress chan metadata.CopyProgress
+	csvImportDone     chan struct{}
+
+	// Visual query builder
+	showQueryBuilder bool
+	queryBuilder     *components.QueryBuilder
+
+	// LISTEN/NOTIFY
+	showNotifications bool
+	notificationLog   *components.NotificationLog
+	listener          *connection.Listener // Dedicated connection, nil until the first LISTEN
+
+	// Errors shown this session, to re-read one dismissed too fast
+	showErrorLog bool
+	errorLog     *components.ErrorLog
+
+	// Blocking locks monitor
+	showLocks    bool
+	locksMonitor *components.LocksMonitor
+	locksTick    int // Current refresh chain; older chains stop when it changes
+
+	// Server version and session settings panel
+	showServerInfo  bool
+	serverInfoPanel *components.ServerInfoPanel
+
+	// Foreign servers panel
+	showForeignServers bool
+	foreignServers     *components.ForeignServersPanel
+
+	// Safe mode: data-modifying statements wait in pendingTx until the
+	// prompt commits or rolls them back
+	safeMode           bool
+	showSafeModePrompt bool
+	safeModePrompt     *components.SafeModePrompt
+	pendingTx          *query.Transaction
+
+	// Dry run: generated statements, such as row edits, are shown in
+	// dryRunDialog instead of running
+	dryRun       bool
+	showDryRun   bool
+	dryRunDialog *components.DryRunDialog
+
+	// Typed confirmation for truncating a table from the tree
+	showTruncate   bool
+	truncateDialog *components.TruncateDialog
+
+	// Prompt shown when a connection's session is terminated or its
+	// database dropped; lostConnection is its config, to reconnect with
+	showSessionLost   bool
+	sessionLostPrompt *components.SessionLostPrompt
+	lostConnection    models.ConnectionConfig
+
+	// LIMIT/OFFSET editor of the active table tab
+	showRowWindow   bool
+	rowWindowDialog *components.RowWindowDialog
+
+	// Form editing the selected row of a table tab
+	showRowEdit bool
+	rowEditForm *components.RowEditForm
+
+	// Columns whose values are masked in every grid
+	maskRules *components.MaskRules
+
+	// Glyphs for boolean cells in every grid, toggled in place
+	boolGlyphs *components.BoolGlyphs
+
+	// Rules coloring the rows of every grid, edited in place by the dialog
+	rowColors       *components.RowColorRules
+	showRowColors   bool
+	rowColorsDialog *components.RowColorsDialog
+
+	// Crash recovery: the SQL editor is saved every autoSaveInterval, and
+	// content left by a session that didn't exit cleanly is offered back
+	recovery           *recovery.Store
+	autoSaveInterval   time.Duration
+	showRecoveryPrompt bool
+	recoveryPrompt     *components.RecoveryPrompt
+
+	// Right-click menu on tree nodes
+	showContextMenu bool
+	contextMenu     *components.ContextMenu
+
+	// Last key or mouse input, to pause tab auto refresh while in use
+	lastInput time.Time
+
+	// Recently opened tree objects (most recent first)
+	recentObjects *models.RecentObjects
+
+	// Whether pg_catalog and information_schema are shown in the tree
+	showSystemSchemas bool
+
+	// Objects of the connected database, searched by the command palette
+	schemaObjects []metadata.SchemaObject
+
+	// Whether the bottom bar shows the second set of key hints
+	showMoreHints bool
+
+	// Whether table views show the SQL that loaded their rows
+	showGeneratedSQL bool
+
+	// Whether table views color the headers of much truncated columns
+	truncationWarnings bool
+
+	// psql-style variables set with \set in the SQL editor
+	sqlVariables *components.SQLVariables
+
+	// Preview pane docking, shared by every table's pane
+	previewPosition components.PreviewPosition
+	previewSize     int // Percent of the data panel
+
 	// Query execution state
 	executeCancelFn context.CancelFunc
 	executeSpinner  spinner.Model
@@ -148,6 +283,7 @@ type appStyles struct {
 	separatorStyle lipgloss.Style
 	filterStyle    lipgloss.Style
 	vimStyle       lipgloss.Style
+	pathStyle      lipgloss.Style
 	overlayBg      lipgloss.Color
 }
 
@@ -180,12 +316,70 @@ func (a *App) initAppStyles() {
 		vimStyle: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#a6e3a1")). // Green for vim input
 			Bold(true),
+		pathStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#cdd6f4")), // Text for tree path
 		overlayBg: lipgloss.Color("#555555"),
 	}
 }
 
-// New creates a new App instance with config
-func New(cfg *config.Config) *App {
+// formatImportSummary describes the outcome of a favorites or connection import
+func formatImportSummary(kind string, imported, duplicates int, conflicts, invalid []string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Imported %d %s", imported, kind))
+	if duplicates > 0 {
+		b.WriteString(fmt.Sprintf("\nSkipped %d already present", duplicates))
+	}
+	if len(conflicts) > 0 {
+		b.WriteString(fmt.Sprintf("\n\nKept %d existing entries that differ from the import:\n  %s",
+			len(conflicts), strings.Join(conflicts, "\n  ")))
+	}
+	if len(invalid) > 0 {
+		b.WriteString(fmt.Sprintf("\n\nSkipped %d invalid entries:\n  %s",
+			len(invalid), strings.Join(invalid, "\n  ")))
+	}
+	return b.String()
+}
+
+// maxRecentObjects caps the recently opened objects list
+const maxRecentObjects = 10
+
+// Auto refresh waits until there has been no input for autoRefreshIdle, and
+// reloads at most maxAutoRefreshRows of the rows a tab has scrolled through
+const (
+	autoRefreshIdle    = 3 * time.Second
+	maxAutoRefreshRows = 1000
+)
+
+// superuserBadge follows the connection in the top bar while its user is a
+// superuser, whom no permission check stops
+const superuserBadge = "⚡superuser"
+
+// focusKeys move focus straight to a panel. Plain digits are taken: they
+// are text in the SQL editor and counts or structure tabs in the data panel.
+var focusKeys = map[string]models.FocusArea{
+	"alt+1": models.FocusTreeView,
+	"alt+2": models.FocusDataPanel,
+	"alt+3": models.FocusSQLEditor,
+}
+
+// startupTimeout is how long the splash waits for the startup stores before
+// showing the app without them; they are taken up whenever they open.
+// Past keyringHintAfter it says the keyring may be waiting on a prompt.
+const (
+	startupTimeout   = 5 * time.Second
+	keyringHintAfter = 1500 * time.Millisecond
+)
+
+// Below this terminal size the panels can't fit their content (two 20-column
+// panels plus borders, six lines of bars), so a notice is shown instead
+const (
+	minTerminalWidth  = 60
+	minTerminalHeight = 15
+)
+
+// New creates a new App instance with config, keeping history, favorites,
+// connections and saved passwords in configDir (see config.Dir)
+func New(cfg *config.Config, configDir string) *App {
 	state := models.NewAppState()
 
 	// Load theme
@@ -193,7 +387,16 @@ func New(cfg *config.Config) *App {
 	if cfg != nil && cfg.UI.Theme != "" {
 		themeName = cfg.UI.Theme
 	}
-	th := theme.GetTheme(themeName)
+	colorMode := theme.ColorModeAuto
+	if cfg != nil && cfg.UI.ColorMode != "" {
+		colorMode = cfg.UI.ColorMode
+	}
+	profile, forced := theme.Profile(colorMode)
+	if forced {
+		// Hard-coded colors outside the theme follow the override too
+		lipgloss.SetColorProfile(profile)
+	}
+	th := theme.GetTheme(themeName).ForProfile(profile)
 
 	// Apply config to state
 	if cfg != nil && cfg.UI.PanelWidthRatio > 0 && cfg.UI.PanelWidthRatio < 100 {
@@ -210,33 +413,6 @@ func New(cfg *config.Config) *App {
 		registry.Register(cmd)
 	}
 
-	// Initialize history store
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		log.Printf("Warning: Could not get home directory: %v", err)
-		homeDir = "."
-	}
-	configDir := filepath.Join(homeDir, ".config", "lazypg")
-	_ = os.MkdirAll(configDir, 0755)
-
-	historyPath := filepath.Join(configDir, "history.db")
-	historyStore, err := history.NewStore(historyPath)
-	if err != nil {
-		log.Printf("Warning: Could not open history: %v", err)
-	}
-
-	// Initialize favorites manager
-	favoritesManager, err := favorites.NewManager(configDir)
-	if err != nil {
-		log.Printf("Warning: Could not initialize favorites: %v", err)
-	}
-
-	// Initialize connection history manager
-	connectionHistory, err := connection_history.NewManager(configDir)
-	if err != nil {
-		log.Printf("Warning: Could not initialize connection history: %v", err)
-	}
-
 	// Initialize filter builder
 	filterBuilder := components.NewFilterBuilder(th)
 
@@ -246,6 +422,15 @@ func New(cfg *config.Config) *App {
 	// Initialize table view (needed by structure view)
 	tableView := components.NewTableView(th)
 
+	// Row color rules are shared by every grid, so edits show everywhere
+	rowColors := &components.RowColorRules{}
+	tableView.RowColors = rowColors
+	boolGlyphs := components.DefaultBoolGlyphs()
+	if cfg != nil {
+		*boolGlyphs = *components.NewBoolGlyphs(cfg.UI.BoolGlyphs)
+	}
+	tableView.BoolGlyphs = boolGlyphs
+
 	// Initialize structure view with shared table view
 	structureView := components.NewStructureView(th, tableView)
 
@@ -268,12 +453,12 @@ func New(cfg *config.Config) *App {
 		discoverer:        discovery.NewDiscoverer(),
 		connectionDialog:  components.NewConnectionDialog(th),
 		errorOverlay:      components.NewErrorOverlay(th),
+		toasts:            components.NewToastStack(th),
 		treeView:          components.NewTreeView(emptyRoot, th),
 		commandRegistry:   registry,
 		commandPalette:    components.NewCommandPalette(th),
 		sqlEditor:         components.NewSQLEditor(th),
 		resultTabs:        components.NewResultTabs(th),
-		historyStore:      historyStore,
 		tableView:         tableView,
 		showFilterBuilder: false,
 		filterBuilder:     filterBuilder,
@@ -284,13 +469,36 @@ func New(cfg *config.Config) *App {
 		structureView:     structureView,
 		currentTab:        0,
 		showFavorites:     false,
-		favoritesManager:  favoritesManager,
 		favoritesDialog:   favoritesDialog,
-		connectionHistory: connectionHistory,
+		startupPending:    2, // See openStores
+		startedAt:         time.Now(),
 		passwordDialog:    components.NewPasswordDialog(th),
 		showSearch:        false,
 		searchInput:       searchInput,
+		csvImportDialog:   components.NewCSVImportDialog(th),
+		queryBuilder:      components.NewQueryBuilder(th),
+		notificationLog:   components.NewNotificationLog(th),
+		errorLog:          components.NewErrorLog(th),
+		locksMonitor:      components.NewLocksMonitor(th),
+		serverInfoPanel:   components.NewServerInfoPanel(th),
+		foreignServers:    components.NewForeignServersPanel(th),
+		safeModePrompt:    components.NewSafeModePrompt(th),
+		dryRunDialog:      components.NewDryRunDialog(th),
+		truncateDialog:    components.NewTruncateDialog(th),
+		sessionLostPrompt: components.NewSessionLostPrompt(th),
+		rowWindowDialog:   components.NewRowWindowDialog(th),
+		rowEditForm:       components.NewRowEditForm(th),
+		rowColors:         rowColors,
+		boolGlyphs:        boolGlyphs,
+		rowColorsDialog:   components.NewRowColorsDialog(th, rowColors),
+		recovery:          recovery.NewStore(configDir),
+		configDir:         configDir,
+		recoveryPrompt:    components.NewRecoveryPrompt(th),
+		contextMenu:       components.NewContextMenu(th),
+		recentObjects:     models.NewRecentObjects(maxRecentObjects),
 		executeSpinner:    s,
+		previewSize:       components.DefaultPreviewSize,
+		sqlVariables:      components.NewSQLVariables(),
 		leftPanel: components.Panel{
 			Title:   "Explorer",
 			Content: "Databases\n└─ (empty)",
@@ -305,6 +513,43 @@ func New(cfg *config.Config) *App {
 
 	// Share spinner with TreeView
 	app.treeView.Spinner = &app.executeSpinner
+	app.resultTabs.RowColors = rowColors
+	app.resultTabs.BoolGlyphs = boolGlyphs
+
+	if cfg != nil {
+		app.showSystemSchemas = cfg.UI.ShowSystemSchemas
+		app.treeView.ShowCounts = cfg.UI.ShowTreeCounts
+		app.showGeneratedSQL = cfg.UI.ShowGeneratedSQL
+		app.truncationWarnings = cfg.UI.TruncationWarnings
+		app.resultTabs.TitleTemplate = cfg.UI.TabTitleTemplate
+		app.resultTabs.MaxTabs = max(cfg.UI.MaxResultTabs, 0)
+		app.safeMode = cfg.Editor.SafeMode
+		app.dryRun = cfg.Editor.DryRun
+
+		rules, err := components.NewMaskRules(cfg.Data.MaskColumns, cfg.Data.MaskOnCopy)
+		if err != nil {
+			app.ShowError("Column Masking", err.Error()+"\n\nThe other patterns still apply.")
+		}
+		app.maskRules = rules
+		app.tableView.SetMasks(rules)
+		app.resultTabs.Masks = rules
+
+		colors, err := components.NewRowColorRules(cfg.Data.RowColors)
+		if err != nil {
+			app.ShowError("Row Colors", err.Error()+"\n\nThe other rules still apply.")
+		}
+		app.rowColors.Rules = colors.Rules
+
+		app.autoSaveInterval = time.Duration(max(cfg.Editor.AutoSaveSeconds, 0)) * time.Second
+	}
+
+	// Offer back the SQL editor content of a session that didn't exit cleanly
+	if sql, savedAt, err := app.recovery.Load(); err != nil {
+		log.Printf("Warning: Could not read editor recovery file: %v", err)
+	} else if sql != "" {
+		app.recoveryPrompt.Open(sql, savedAt)
+		app.showRecoveryPrompt = true
+	}
 
 	// Set initial panel dimensions and styles
 	app.updatePanelDimensions()
@@ -327,21 +572,135 @@ func New(cfg *config.Config) *App {
 
 // Init implements tea.Model
 func (a *App) Init() tea.Cmd {
-	// Load connection history if available
-	if a.connectionHistory != nil {
-		history := a.connectionHistory.GetRecent(10) // Show up to 10 recent connections
-		a.connectionDialog.SetHistoryEntries(history)
-	}
+	startup := tea.Batch(
+		a.openStores(),
+		a.executeSpinner.Tick,
+		tea.Tick(startupTimeout, func(time.Time) tea.Msg {
+			return messages.StartupTimeoutMsg{}
+		}),
+	)
 
 	// If no active connection, automatically show connection dialog on startup
 	if a.state.ActiveConnection == nil {
 		a.showConnectionDialog = true
 		return tea.Batch(
+			startup,
 			a.triggerDiscovery(),
 			a.connectionDialog.Init(), // Start cursor blinking
+			a.scheduleAutoSave(),
 		)
 	}
-	return a.connectionDialog.Init() // Always init textinput cursors
+	return tea.Batch(
+		startup,
+		a.connectionDialog.Init(), // Always init textinput cursors
+		a.scheduleAutoSave(),
+	)
+}
+
+// openStores opens the query history and favorites, and separately the
+// connection history, whose password store waits on the system keyring.
+// Each arrives as a StoreOpenedMsg; startupPending counts them.
+func (a *App) openStores() tea.Cmd {
+	configDir := a.configDir
+	return tea.Batch(
+		func() tea.Msg {
+			var msg messages.StoreOpenedMsg
+			var err error
+			msg.History, err = history.NewStore(filepath.Join(configDir, "history.db"))
+			if err != nil {
+				log.Printf("Warning: Could not open history: %v", err)
+			}
+			msg.Favorites, err = favorites.NewManager(configDir)
+			if err != nil {
+				log.Printf("Warning: Could not initialize favorites: %v", err)
+			}
+			return msg
+		},
+		func() tea.Msg {
+			var msg messages.StoreOpenedMsg
+			var err error
+			msg.ConnectionHistory, err = connection_history.NewManager(configDir)
+			if err != nil {
+				log.Printf("Warning: Could not initialize connection history: %v", err)
+			}
+			return msg
+		},
+	)
+}
+
+// isStarting reports whether the splash is showing: a startup store is
+// still opening and startupTimeout hasn't passed
+func (a *App) isStarting() bool {
+	return a.startupPending > 0 && !a.startupTimedOut
+}
+
+// storeOpened takes up a store opened in the background at startup. One
+// arriving after the timeout is taken up all the same, with a toast once
+// the saved connections are there.
+func (a *App) storeOpened(msg messages.StoreOpenedMsg) tea.Cmd {
+	a.startupPending--
+	if msg.History != nil {
+		a.historyStore = msg.History
+	}
+	if msg.Favorites != nil {
+		a.favoritesManager = msg.Favorites
+		a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+	}
+	if msg.ConnectionHistory != nil {
+		a.connectionHistory = msg.ConnectionHistory
+		a.refreshConnectionDialogHistory()
+		if a.startupTimedOut {
+			return a.ShowToast("Saved connections loaded")
+		}
+	}
+	return nil
+}
+
+// renderStartup renders the splash shown while the startup stores open.
+// Past keyringHintAfter it points at the keyring, which may be waiting on
+// an access prompt outside the terminal.
+func (a *App) renderStartup() string {
+	titleStyle := lipgloss.NewStyle().Foreground(a.theme.Info).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(a.theme.Foreground)
+	metaStyle := lipgloss.NewStyle().Foreground(a.theme.Metadata)
+
+	lines := []string{
+		titleStyle.Render("lazypg"),
+		"",
+		a.executeSpinner.View() + textStyle.Render(" Loading history and saved connections…"),
+	}
+	if time.Since(a.startedAt) > keyringHintAfter {
+		lines = append(lines, "",
+			metaStyle.Render("Waiting for the system keyring; allow access if it asks."),
+			metaStyle.Render(fmt.Sprintf("Continuing without it after %s.", startupTimeout)))
+	}
+	content := lipgloss.JoinVertical(lipgloss.Center, lines...)
+	return lipgloss.Place(a.state.Width, a.state.Height, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().MaxWidth(a.state.Width).Render(content))
+}
+
+// scheduleAutoSave schedules the next save of the SQL editor for crash
+// recovery, unless auto-save is off
+func (a *App) scheduleAutoSave() tea.Cmd {
+	if a.autoSaveInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(a.autoSaveInterval, func(time.Time) tea.Msg {
+		return messages.AutoSaveTickMsg{}
+	})
+}
+
+// ClearRecovery removes the SQL editor's recovery file. It is called on a
+// clean exit, so only a crash leaves content to restore. A crashed
+// session's file that was never offered back, as when quitting from the
+// startup splash or an error shown over the prompt, is kept for next time.
+func (a *App) ClearRecovery() {
+	if a.showRecoveryPrompt {
+		return
+	}
+	if err := a.recovery.Clear(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 }
 
 // dispatchToDelegate sends a message to all delegates until one handles it.
@@ -364,6 +723,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.MouseMsg:
+		a.lastInput = time.Now()
+		if a.isStarting() {
+			return a, nil
+		}
 		return a.handleMouseEvent(msg)
 
 	case spinner.TickMsg:
@@ -373,7 +736,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.treeView.LoadingNodeID != "" ||
 			a.tableView.IsPaginating ||
 			a.isConnecting ||
-			a.isLoadingObjectDetails
+			a.isLoadingObjectDetails ||
+			a.isStarting()
 
 		// Also check active tab's table view
 		if activeTab := a.resultTabs.GetActiveTab(); activeTab != nil {
@@ -381,6 +745,9 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if tv := sv.GetTableView(); tv != nil && tv.IsLoading {
 					needsSpinner = true
 				}
+				if sv.IsSectionLoading() {
+					needsSpinner = true
+				}
 			}
 		}
 
@@ -391,6 +758,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case components.ToastExpiredMsg:
+		a.toasts.Dismiss(msg.ID)
+		return a, nil
+
+	case messages.StoreOpenedMsg:
+		return a, a.storeOpened(msg)
+
+	case messages.StartupTimeoutMsg:
+		if !a.isStarting() {
+			return a, nil
+		}
+		// Show the app; the stores are taken up whenever they open
+		a.startupTimedOut = true
+		return a, a.ShowToast("Still opening saved connections; they will appear once the system keyring answers")
+
 	case commands.ConnectCommandMsg:
 		// Handle connect command from palette
 		a.showConnectionDialog = true
@@ -445,9 +827,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
-		// Show success notification
-		a.ShowError("Export Complete", fmt.Sprintf("Successfully exported favorites to:\n\n%s\n\nYou can now import this file or share it with others.", path))
-		return a, nil
+		return a, a.ShowToast("Exported favorites to " + path)
 
 	case commands.ExportFavoritesJSONMsg:
 		// Export favorites to JSON
@@ -462,392 +842,1358 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
-		// Show success notification
-		a.ShowError("Export Complete", fmt.Sprintf("Successfully exported favorites to:\n\n%s\n\nYou can now import this file or share it with others.", path))
-		return a, nil
-
-	case components.OpenExternalEditorMsg:
-		// Open external editor
-		return a, a.openExternalEditor(msg.Content)
+		return a, a.ShowToast("Exported favorites to " + path)
 
-	case components.ExternalEditorResultMsg:
-		if msg.Error != nil {
-			a.ShowError("Editor Error", msg.Error.Error())
+	case commands.ImportFavoritesJSONMsg:
+		// Merge favorites from the exported JSON file
+		if a.favoritesManager == nil {
+			a.ShowError("Import Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
 			return a, nil
 		}
-		a.sqlEditor.SetContent(msg.Content)
-		return a, nil
 
-	case components.ExecuteQueryMsg:
-		// Handle query execution from SQL editor
-		if a.state.ActiveConnection == nil {
-			a.ShowError("No Connection", "Please connect to a database first")
+		result, err := a.favoritesManager.ImportFromJSON()
+		if err != nil {
+			a.ShowError("Import Failed", fmt.Sprintf("Failed to import favorites:\n\n%v\n\nPlace an exported favorites.json in the config directory and try again.", err))
 			return a, nil
 		}
 
-		// Create pending tab immediately
-		a.resultTabs.StartPendingQuery(msg.SQL)
-
-		// Immediately switch focus to data panel and collapse editor
-		a.sqlEditor.Collapse()
-		a.state.FocusArea = models.FocusDataPanel
-		a.updatePanelStyles()
+		a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+		a.ShowError("Import Complete", formatImportSummary("favorites", result.Imported, result.Duplicates, result.Conflicts, result.Invalid))
+		return a, nil
 
-		// Create cancellable context for query execution
-		ctx, cancel := context.WithCancel(context.Background())
-		a.executeCancelFn = cancel
+	case commands.ExportConnectionHistoryMsg:
+		// Export connection history (passwords are never included)
+		if a.connectionHistory == nil {
+			a.ShowError("Export Not Available", "Connection history is not initialized.\n\nPlease restart the application.")
+			return a, nil
+		}
 
-		// Execute query asynchronously and start spinner
-		return a, tea.Batch(
-			a.executeSpinner.Tick,
-			func() tea.Msg {
-				conn, err := a.connectionManager.GetActive()
-				if err != nil {
-					return messages.QueryResultMsg{
-						SQL: msg.SQL,
-						Result: models.QueryResult{
-							Error: fmt.Errorf("failed to get connection: %w", err),
-						},
-					}
-				}
+		path, err := a.connectionHistory.Export()
+		if err != nil {
+			a.ShowError("Export Failed", fmt.Sprintf("Failed to export connection history:\n\n%v", err))
+			return a, nil
+		}
 
-				result := query.Execute(ctx, conn.Pool.GetPool(), msg.SQL)
-				return messages.QueryResultMsg{
-					SQL:    msg.SQL,
-					Result: result,
-				}
-			},
-		)
+		return a, a.ShowToast("Exported connection history (without passwords) to " + path)
 
-	case messages.QueryResultMsg:
-		// Clear execution state
-		a.executeCancelFn = nil
+	case commands.SwitchDatabaseCommandMsg:
+		return a, a.loadDatabases()
 
-		// Record query to history
-		if a.historyStore != nil {
-			connName := ""
-			dbName := ""
-			if a.state.ActiveConnection != nil {
-				connName = a.state.ActiveConnection.Config.Name
-				dbName = a.state.ActiveConnection.Config.Database
+	case messages.DatabasesLoadedMsg:
+		if msg.Err != nil {
+			a.ShowError("Switch Database Failed", fmt.Sprintf("Failed to list the databases on the server:\n\n%v", msg.Err))
+			return a, nil
+		}
+		if a.state.ActiveConnection == nil {
+			return a, nil
+		}
+		open := make(map[string]bool)
+		for _, conn := range a.connectionManager.GetAll() {
+			if conn.Pool != nil && conn.Connected && a.onActiveServer(conn.Config) {
+				open[conn.Config.Database] = true
 			}
+		}
+		a.commandPalette.Reset()
+		a.commandPalette.SetDatabases(components.DatabaseCommands(msg.Databases, a.state.ActiveConnection.Config.Database, open))
+		a.commandPalette.SetInput("%")
+		a.showCommandPalette = true
+		return a, nil
 
-			entry := history.HistoryEntry{
-				ConnectionName: connName,
-				DatabaseName:   dbName,
-				Query:          msg.SQL,
-				Duration:       msg.Result.Duration,
-				RowsAffected:   msg.Result.RowsAffected,
-				Success:        msg.Result.Error == nil,
-			}
+	case components.SwitchDatabaseMsg:
+		return a, a.switchDatabase(msg.Database)
 
-			if msg.Result.Error != nil {
-				entry.ErrorMessage = msg.Result.Error.Error()
-			}
+	case commands.RunOnConnectionCommandMsg:
+		sql := strings.TrimSpace(a.sqlEditor.GetCurrentStatement())
+		if sql == "" {
+			a.ShowError("Run Query on Connection", "Write a query in the SQL editor first; the statement under the cursor runs.")
+			return a, nil
+		}
+		activeID := ""
+		if a.state.ActiveConnection != nil {
+			activeID = a.state.ActiveConnection.ID
+		}
+		connections := components.ConnectionCommands(a.connectionManager.GetAll(), activeID, sql)
+		if len(connections) == 0 {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		a.commandPalette.Reset()
+		a.commandPalette.SetConnections(connections)
+		a.commandPalette.SetInput("&")
+		a.showCommandPalette = true
+		return a, nil
 
-			// Record to history (ignore errors to not interrupt user flow)
-			_ = a.historyStore.Add(entry)
+	case commands.ExportSchemaCommandMsg:
+		if a.state.ActiveConnection == nil || a.treeView.Root == nil {
+			a.ShowError("Export Not Available", "Connect to a database and wait for the tree to load first.")
+			return a, nil
 		}
+		return a, a.exportSchema(msg.DOT)
 
-		// Handle query result
-		if msg.Result.Error != nil {
-			// Check if it was cancelled (context cancelled error)
-			if msg.Result.Error.Error() == "context canceled" {
-				// Already handled by CancelPendingQuery, just return
-				return a, nil
-			}
-			// Show error and remove pending tab
-			a.resultTabs.CancelPendingQuery()
-			a.ShowError("Query Error", msg.Result.Error.Error())
+	case messages.SchemaExportedMsg:
+		if msg.Err != nil {
+			a.ShowError("Export Failed", fmt.Sprintf("Failed to export the schema:\n\n%v", msg.Err))
 			return a, nil
 		}
+		return a, a.ShowToast(fmt.Sprintf("Exported %d objects to %s", msg.Objects, msg.Path))
 
-		// Complete the pending query with results
-		a.resultTabs.CompletePendingQuery(msg.SQL, msg.Result)
+	case commands.ImportConnectionHistoryMsg:
+		// Merge connection history from an exported file
+		if a.connectionHistory == nil {
+			a.ShowError("Import Not Available", "Connection history is not initialized.\n\nPlease restart the application.")
+			return a, nil
+		}
+
+		result, err := a.connectionHistory.Import()
+		if err != nil {
+			a.ShowError("Import Failed", fmt.Sprintf("Failed to import connection history:\n\n%v\n\nPlace an exported connection_history_export.yaml in the config directory and try again.", err))
+			return a, nil
+		}
 
+		a.refreshConnectionDialogHistory()
+		a.ShowError("Import Complete", formatImportSummary("connections", result.Imported, result.Duplicates, result.Conflicts, result.Invalid))
 		return a, nil
 
-	case components.ApplyFilterMsg:
-		// Apply the filter and reload table data
-		a.showFilterBuilder = false
-		a.activeFilter = &msg.Filter
+	case commands.RecentObjectsCommandMsg:
+		return a.openRecentObjects()
 
-		// Reload table with filter
-		if a.state.TreeSelected != nil && a.state.TreeSelected.Type == models.TreeNodeTypeTable {
-			return a, a.loadTableDataWithFilter(*a.activeFilter)
-		}
+	case commands.ToggleSystemSchemasCommandMsg:
+		a.toggleSystemSchemas()
 		return a, nil
 
-	case components.CloseFilterBuilderMsg:
-		a.showFilterBuilder = false
+	case commands.ToggleGeneratedSQLCommandMsg:
+		a.showGeneratedSQL = !a.showGeneratedSQL
 		return a, nil
 
-	case components.CloseJSONBViewerMsg:
-		a.showJSONBViewer = false
-		return a, nil
+	case commands.ToggleTruncationWarningsCommandMsg:
+		a.truncationWarnings = !a.truncationWarnings
+		if a.truncationWarnings {
+			return a, a.ShowToast("Truncated column headers highlighted")
+		}
+		return a, a.ShowToast("Truncated column headers not highlighted")
 
-	case components.CloseErrorOverlayMsg:
-		a.showError = false
-		return a, nil
+	case commands.ToggleBoolGlyphsCommandMsg:
+		a.boolGlyphs.Enabled = !a.boolGlyphs.Enabled
+		if a.boolGlyphs.Enabled {
+			return a, a.ShowToast(fmt.Sprintf("Booleans shown as %s/%s", a.boolGlyphs.True, a.boolGlyphs.False))
+		}
+		return a, a.ShowToast("Booleans shown as true/false")
 
-	case components.PasswordSubmitMsg:
-		// User submitted password from password dialog
-		a.showPasswordDialog = false
-		if a.pendingConnectionInfo != nil {
-			// Create connection config with the entered password
-			config := a.pendingConnectionInfo.ToConnectionConfig()
-			config.Password = msg.Password
+	case commands.CycleEditorHeightCommandMsg:
+		return a, a.cycleEditorHeight()
 
-			// Store password info to save after successful connection
-			// This avoids: 1) saving wrong passwords, 2) double keychain prompts
-			a.pendingPasswordSave = &pendingPassword{
-				Host:     config.Host,
-				Port:     config.Port,
-				Database: config.Database,
-				User:     config.User,
-				Password: msg.Password,
-			}
+	case commands.ToggleEditorFullscreenCommandMsg:
+		a.toggleEditorFullscreen()
+		return a, nil
 
-			a.pendingConnectionInfo = nil
-			return a.performConnection(config)
+	case commands.ToggleSafeModeCommandMsg:
+		a.safeMode = !a.safeMode
+		if a.safeMode {
+			return a, a.ShowToast("Safe mode on: changes wait for you to commit")
 		}
-		return a, nil
+		return a, a.ShowToast("Safe mode off")
 
-	case components.PasswordCancelMsg:
-		// User cancelled password dialog
-		a.showPasswordDialog = false
-		a.pendingConnectionInfo = nil
-		// Re-show connection dialog
-		a.showConnectionDialog = true
-		return a, nil
+	case commands.ToggleDryRunCommandMsg:
+		a.dryRun = !a.dryRun
+		if a.dryRun {
+			return a, a.ShowToast("Dry run on: generated statements are shown, not run")
+		}
+		return a, a.ShowToast("Dry run off")
 
-	case components.CloseCommandPaletteMsg:
-		a.showCommandPalette = false
+	case components.CloseDryRunMsg:
+		a.showDryRun = false
 		return a, nil
 
-	case components.ExecuteFavoriteMsg:
-		// Execute favorite query
-		if a.state.ActiveConnection == nil {
-			a.ShowError("No Database Connection", "Please connect to a database before executing queries.\n\nPress 'c' to open the connection dialog.")
-			return a, nil
-		}
+	case commands.BookmarkObjectCommandMsg:
+		return a, a.bookmarkTreeNode()
 
-		// Record usage
-		if a.favoritesManager != nil {
-			if err := a.favoritesManager.RecordUsage(msg.Favorite.ID); err != nil {
-				// Log error but don't block execution
-				log.Printf("Warning: Failed to record favorite usage: %v", err)
-			}
-		}
+	case commands.SaveTableViewCommandMsg:
+		return a, a.saveTableView()
 
-		// Execute query asynchronously
-		a.showFavorites = false
-		return a, func() tea.Msg {
-			conn, err := a.connectionManager.GetActive()
-			if err != nil {
-				return messages.QueryResultMsg{
-					SQL: msg.Favorite.Query,
-					Result: models.QueryResult{
-						Error: fmt.Errorf("connection error: %w", err),
-					},
-				}
-			}
+	case commands.ClearFilterCommandMsg:
+		return a, a.clearFilter()
+
+	case commands.AutoRefreshCommandMsg:
+		return a, a.cycleAutoRefresh()
+
+	case commands.RowWindowCommandMsg:
+		return a, a.openRowWindow()
+
+	case commands.RowColorsCommandMsg:
+		a.rowColorsDialog.Open()
+		a.showRowColors = true
+		return a, a.rowColorsDialog.Init()
+
+	case components.CloseRowColorsMsg:
+		a.showRowColors = false
+		return a, nil
 
-			result := query.Execute(context.Background(), conn.Pool.GetPool(), msg.Favorite.Query)
-			return messages.QueryResultMsg{
-				SQL:    msg.Favorite.Query,
-				Result: result,
+	case commands.EditRowCommandMsg:
+		return a, a.openRowEdit()
+
+	case messages.RowEditLoadedMsg:
+		if msg.Err != nil {
+			if a.HandleSessionLoss(msg.Err, "") {
+				return a, nil
 			}
+			a.ShowError("Cannot Edit Row", fmt.Sprintf("Failed to load the columns of %s.%s:\n\n%v", msg.Schema, msg.Table, msg.Err))
+			return a, nil
 		}
+		if err := a.rowEditForm.Open(msg.Schema, msg.Table, msg.ObjectID, msg.Columns, msg.GridColumns, msg.Row, msg.Masked); err != nil {
+			a.ShowError("Cannot Edit Row", err.Error())
+			return a, nil
+		}
+		a.showRowEdit = true
+		return a, nil
 
-	case components.CloseFavoritesDialogMsg:
-		a.showFavorites = false
+	case components.CloseRowEditMsg:
+		a.showRowEdit = false
 		return a, nil
 
-	case components.SearchInputMsg:
-		// Handle search request from search input
-		a.showSearch = false
-		if msg.Query == "" {
+	case components.RowEditSaveMsg:
+		if a.dryRun {
+			// The form stays open behind the statement
+			a.openDryRun(fmt.Sprintf("Would update 1 row of %s", msg.ObjectID), msg.SQL)
 			return a, nil
 		}
+		return a, a.updateRow(msg)
 
-		// Get the active table view (Result Tabs or main TableView)
-		activeTable := a.getActiveTableView()
+	case components.TruncateConfirmMsg:
+		a.showTruncate = false
+		if a.dryRun {
+			a.openDryRun(fmt.Sprintf("Would truncate %s.%s", msg.Schema, msg.Table), msg.SQL)
+			return a, nil
+		}
+		return a, a.truncateTable(msg)
 
-		if msg.Mode == "local" {
-			// Local search - search only loaded data
-			if activeTable != nil {
-				activeTable.SearchLocal(msg.Query)
-			}
-		} else {
-			// For Result Tabs, always use local search (data is already loaded)
-			if a.resultTabs.HasTabs() {
-				if activeTable != nil {
-					activeTable.SearchLocal(msg.Query)
-				}
-				return a, nil
-			}
+	case components.CloseTruncateMsg:
+		a.showTruncate = false
+		return a, nil
 
-			// Table search - query the database (only for table browser)
-			if a.state.ActiveConnection == nil {
-				a.ShowError("No Connection", "Please connect to a database first")
+	case messages.TableTruncatedMsg:
+		if msg.Err != nil {
+			if a.HandleSessionLoss(msg.Err, "") {
 				return a, nil
 			}
+			a.ShowError("Truncate Failed", metadata.DescribeTruncateError(msg.Err))
+			return a, nil
+		}
+		return a, tea.Batch(a.ShowToast("Truncated "+msg.ObjectID), a.refreshTruncatedTabs(msg))
 
-			if a.currentTable == "" {
-				a.ShowError("No Table", "Please select a table first")
+	case messages.RowUpdatedMsg:
+		if msg.Err != nil {
+			// Keep the form open with the edits, to fix and save again
+			if a.HandleSessionLoss(msg.Err, "") {
+				a.showRowEdit = false
 				return a, nil
 			}
-
-			// Execute table search
-			return a, a.searchTable(msg.Query)
+			a.rowEditForm.Err = msg.Err.Error()
+			return a, nil
 		}
-		return a, nil
+		a.showRowEdit = false
+		if msg.Transaction != nil {
+			a.OpenSafeModePrompt(msg.SQL, 1, msg.Transaction)
+			return a, nil
+		}
+		toast := a.ShowToast("Updated 1 row")
+		if tab := a.resultTabs.GetTabByObjectID(msg.ObjectID); tab != nil && tab.Structure != nil {
+			return a, tea.Batch(toast, a.refreshTab(tab))
+		}
+		return a, toast
 
-	case components.CloseSearchMsg:
-		a.showSearch = false
-		a.searchInput.Reset()
+	case components.RowWindowCancelMsg:
+		a.showRowWindow = false
 		return a, nil
 
-	case SearchTableResultMsg:
-		if msg.Err != nil {
-			a.ShowError("Search Error", msg.Err.Error())
-			return a, nil
-		}
+	case components.RowWindowSubmitMsg:
+		a.showRowWindow = false
+		return a, a.applyRowWindow(msg)
 
-		if msg.Data == nil || len(msg.Data.Rows) == 0 {
-			a.ShowError("No Results", fmt.Sprintf("No matches found for '%s'", msg.Query))
+	case messages.TabRefreshTickMsg:
+		tab := a.resultTabs.GetTabByID(msg.TabID)
+		if tab == nil || tab.RefreshSeq != msg.Seq || tab.RefreshInterval == 0 {
+			// Closed, or auto refresh was turned off or restarted
 			return a, nil
 		}
+		next := a.scheduleTabRefresh(tab)
+		tableView := tab.Structure.GetTableView()
+		if tab.Refreshing || tableView.IsLoading || tableView.IsPaginating || a.autoRefreshPaused() {
+			return a, next
+		}
+		return a, tea.Batch(a.refreshTab(tab), next)
 
-		// Replace table data with search results
-		a.tableView.SetData(msg.Data.Columns, msg.Data.Rows, int(msg.Data.TotalRows))
+	case messages.TableViewCheckedMsg:
+		return a, a.openCheckedTableView(msg)
 
-		// Build matches from all cells that contain the query
-		queryLower := strings.ToLower(msg.Query)
-		var matches []components.MatchPos
-		for rowIdx, row := range msg.Data.Rows {
-			for colIdx, cell := range row {
-				if strings.Contains(strings.ToLower(cell), queryLower) {
-					matches = append(matches, components.MatchPos{Row: rowIdx, Col: colIdx})
-				}
-			}
+	case commands.SessionVariablesCommandMsg:
+		a.ShowError("Session Variables", a.sqlVariables.Describe())
+		return a, nil
+
+	case commands.CopyConnectionURLCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		config := a.state.ActiveConnection.Config
+		if err := clipboard.WriteAll(config.URL(msg.IncludePassword)); err != nil {
+			a.ShowError("Copy Failed", fmt.Sprintf("Failed to copy to clipboard:\n\n%v", err))
+			return a, nil
+		}
+		if msg.IncludePassword {
+			return a, a.ShowToast("Copied connection URL (with password) to clipboard")
 		}
+		return a, a.ShowToast("Copied connection URL to clipboard")
 
-		a.tableView.SetSearchResults(msg.Query, matches)
-		return a, nil
+	case commands.ColumnSizesCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		schema, table := a.getActiveSchemaTable()
+		if schema == "" || table == "" {
+			a.ShowError("No Table", "Please select a table to measure first")
+			return a, nil
+		}
+		return a, a.columnSizes(schema, table, msg.FullScan)
 
-	case components.AddFavoriteMsg:
-		if a.favoritesManager != nil {
-			conn := ""
-			if a.state.ActiveConnection != nil {
-				conn = a.state.ActiveConnection.Config.Name
-			}
-			db := a.state.CurrentDatabase
-			_, err := a.favoritesManager.Add(msg.Name, msg.Description, msg.Query, conn, db, msg.Tags)
-			if err != nil {
-				a.ShowError("Cannot Add Favorite", fmt.Sprintf("Failed to add favorite:\n\n%v\n\nPlease check your input and try again.", err))
+	case commands.CopyPlanCommandMsg:
+		planJSON, planText, ok := a.activePlan()
+		if !ok {
+			a.ShowError("Copy Plan", "Run EXPLAIN on a query to open its plan in a tab first.")
+			return a, nil
+		}
+		content, what := planJSON, "plan as JSON"
+		if msg.AsText {
+			content, what = planText, "plan as text"
+		}
+		if content == "" {
+			if msg.AsText {
+				a.ShowError("Copy Plan", "The plan's JSON could not be rendered as text. Copy it as JSON instead.")
 			} else {
-				// Refresh the dialog
-				a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+				a.ShowError("Copy Plan", "This plan was explained as text. Run EXPLAIN (FORMAT JSON) for a JSON plan.")
 			}
-		} else {
-			a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+			return a, nil
 		}
-		return a, nil
+		if err := clipboard.WriteAll(content); err != nil {
+			a.ShowError("Copy Failed", fmt.Sprintf("Failed to copy to clipboard:\n\n%v", err))
+			return a, nil
+		}
+		return a, a.ShowToast("Copied " + what + " to clipboard")
 
-	case components.EditFavoriteMsg:
-		if a.favoritesManager != nil {
-			err := a.favoritesManager.Update(msg.FavoriteID, msg.Name, msg.Description, msg.Query, msg.Tags)
-			if err != nil {
-				a.ShowError("Cannot Update Favorite", fmt.Sprintf("Failed to update favorite:\n\n%v\n\nPlease check your input and try again.", err))
-			} else {
-				// Refresh the dialog
-				a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
-			}
-		} else {
-			a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+	case commands.SavePlanCommandMsg:
+		planJSON, planText, ok := a.activePlan()
+		if !ok {
+			a.ShowError("Save Plan", "Run EXPLAIN on a query to open its plan in a tab first.")
+			return a, nil
 		}
-		return a, nil
+		paths, err := a.savePlan(planJSON, planText)
+		if err != nil {
+			a.ShowError("Save Failed", fmt.Sprintf("Failed to save the plan:\n\n%v", err))
+			return a, nil
+		}
+		return a, a.ShowToast("Saved plan to " + strings.Join(paths, " and "))
 
-	case components.DeleteFavoriteMsg:
-		if a.favoritesManager != nil {
-			err := a.favoritesManager.Delete(msg.FavoriteID)
-			if err != nil {
-				a.ShowError("Cannot Delete Favorite", fmt.Sprintf("Failed to delete favorite:\n\n%v\n\nThe favorite may have already been deleted.", err))
-			} else {
-				// Refresh the dialog
-				a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
-				// Adjust selection if needed
-				if a.favoritesDialog != nil {
-					favorites := a.favoritesManager.GetAll()
-					if len(favorites) > 0 {
-						// Keep selection valid
-						a.favoritesDialog.SetFavorites(favorites)
-					}
-				}
-			}
-		} else {
-			a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+	case commands.CopyRowsTSVCommandMsg:
+		table := a.getActiveTableView()
+		if table == nil || len(table.Columns) == 0 {
+			a.ShowError("Copy Rows", "Open a table or query result to copy its rows")
+			return a, nil
 		}
-		return a, nil
+		return a, a.copyRowsTSV(table)
 
-	case messages.ErrorMsg:
-		// Handle error messages
-		a.ShowError(msg.Title, msg.Message)
-		return a, nil
+	case commands.CopyRowsSQLCommandMsg:
+		return a, a.copyRowsSQL(msg.Insert)
 
-	case tea.KeyMsg:
-		// Handle error overlay dismissal first if visible
-		if a.showError {
-			key := msg.String()
-			if key == "esc" || key == "enter" {
-				a.DismissError()
+	case messages.RowsSQLMsg:
+		if msg.Err != nil {
+			if a.HandleSessionLoss(msg.Err, "") {
 				return a, nil
 			}
-			// Allow quit keys to pass through even when error is showing
-			if key == "q" || key == "ctrl+c" {
-				return a, tea.Quit
-			}
-			// Consume all other keys when error is showing
+			a.ShowError("Cannot Copy Rows", msg.Err.Error())
+			return a, nil
+		}
+		if err := clipboard.WriteAll(msg.SQL); err != nil {
+			a.ShowError("Copy Failed", fmt.Sprintf("Could not copy to the clipboard:\n\n%v", err))
 			return a, nil
 		}
+		as := "WHERE condition"
+		if msg.Insert {
+			as = "INSERT"
+		}
+		return a, a.ShowToast(fmt.Sprintf("Copied %d row(s) as %s", msg.Rows, as))
 
-		// Handle connection dialog if visible
-		if a.showConnectionDialog {
-			return a.handleConnectionDialog(msg)
+	case commands.CompareResultsCommandMsg:
+		current := a.resultTabs.GetActiveTab()
+		if current == nil || current.Type != components.TabTypeQueryResult || current.TableView == nil || current.IsPending {
+			a.ShowError("Compare Results", "Open the query result to compare first")
+			return a, nil
+		}
+		baseline := a.resultTabs.ComparisonBaseline()
+		if baseline == nil {
+			a.ShowError("Compare Results", "There is no other query result to compare with.\n\nRun the query again, or open the result to compare with in a split (|).")
+			return a, nil
+		}
+		_, col := current.TableView.GetSelectedCell()
+		if col < 0 || col >= len(current.TableView.Columns) {
+			a.ShowError("Compare Results", "Select the column that identifies rows first")
+			return a, nil
 		}
+		// Compare the rows loaded so far, which may be more than the first page
+		key := current.TableView.Columns[col]
+		cmp, err := components.CompareResults(
+			models.QueryResult{Columns: current.TableView.Columns, Rows: current.TableView.LoadedRows()},
+			models.QueryResult{Columns: baseline.TableView.Columns, Rows: baseline.TableView.LoadedRows()},
+			key)
+		if err != nil {
+			a.ShowError("Compare Results", fmt.Sprintf("Can't match rows of %q and %q:\n\n%v", current.Title, baseline.Title, err))
+			return a, nil
+		}
+		a.resultTabs.AddComparison(components.ComparisonTitle(current, baseline), cmp)
+		a.state.FocusArea = models.FocusDataPanel
+		a.updatePanelStyles()
+		return a, a.ShowToast(fmt.Sprintf("Compared by %s: %s", key, cmp.Summary()))
 
-		// Handle password dialog if visible
-		if a.showPasswordDialog {
-			var cmd tea.Cmd
-			a.passwordDialog, cmd = a.passwordDialog.Update(msg)
-			return a, cmd
+	case commands.ImportCSVCommandMsg:
+		// Import a CSV file into the active table
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		schema, table := a.getActiveSchemaTable()
+		if schema == "" || table == "" {
+			a.ShowError("No Table", "Please select a table to import into first")
+			return a, nil
 		}
+		return a, a.loadCSVImportColumns(schema, table)
 
-		// Handle command palette if visible
-		if a.showCommandPalette {
-			return a.handleCommandPalette(msg)
+	case messages.CSVImportColumnsLoadedMsg:
+		if msg.Err != nil {
+			if a.HandleSessionLoss(msg.Err, "") {
+				return a, nil
+			}
+			a.ShowError("Import Error", fmt.Sprintf("Failed to load columns for %s.%s:\n\n%v", msg.Schema, msg.Table, msg.Err))
+			return a, nil
 		}
+		a.csvImportDialog.Open(msg.Schema, msg.Table, msg.Columns)
+		a.showCSVImport = true
+		return a, nil
 
-		// Handle filter builder input
-		if a.showFilterBuilder {
-			return a.handleFilterBuilder(msg)
+	case components.CSVFileSelectedMsg:
+		path := msg.Path
+		return a, func() tea.Msg {
+			records, err := metadata.ReadCSVFile(path)
+			return messages.CSVFileReadMsg{Path: path, Records: records, Err: err}
 		}
 
-		// Handle JSONB viewer input
-		if a.showJSONBViewer {
-			return a.handleJSONBViewer(msg)
+	case messages.CSVFileReadMsg:
+		if !a.showCSVImport {
+			// The dialog was closed while the file was read
+			return a, nil
+		}
+		if msg.Err != nil {
+			a.csvImportDialog.SetStatus(msg.Err.Error(), true)
+			return a, nil
 		}
+		tableColumns := a.csvImportDialog.Columns()
+		hasHeader := metadata.DetectCSVHeader(msg.Records, tableColumns)
+		mapping := metadata.MapCSVColumns(msg.Records[0], tableColumns, hasHeader)
+		a.csvImportDialog.SetRecords(msg.Records, hasHeader, mapping)
+		return a, nil
 
-		// Handle favorites dialog if visible
-		if a.showFavorites {
-			return a.handleFavoritesDialog(msg)
+	case components.CSVImportMsg:
+		// A dry run imports in a transaction that is always rolled back
+		msg.DryRun = msg.DryRun || a.dryRun
+		a.csvImportDialog.SetRunning()
+		ctx, cancel := context.WithCancel(context.Background())
+		a.csvImportCancel = cancel
+		a.csvImportProgress = make(chan metadata.CopyProgress, 1)
+		a.csvImportDone = make(chan struct{})
+		return a, tea.Batch(a.importCSV(ctx, msg), a.waitForCSVImportProgress())
+
+	case messages.CSVImportProgressMsg:
+		a.csvImportDialog.SetProgress(msg.Rows, msg.Bytes)
+		return a, a.waitForCSVImportProgress()
+
+	case components.CancelCSVImportMsg:
+		if a.csvImportCancel != nil {
+			a.csvImportCancel()
 		}
+		return a, nil
 
-		// Handle search input if visible
-		if a.showSearch {
-			return a.handleSearchInput(msg)
+	case messages.CSVImportResultMsg:
+		if a.csvImportCancel != nil {
+			a.csvImportCancel()
+			a.csvImportCancel = nil
+		}
+		if msg.Cancelled {
+			a.csvImportDialog.SetStatus("Import cancelled. The transaction was rolled back, so nothing was imported.", true)
+			return a, nil
+		}
+		if msg.Err != nil {
+			a.csvImportDialog.SetStatus(msg.Err.Error(), true)
+			return a, nil
+		}
+		if msg.DryRun {
+			a.csvImportDialog.SetStatus(fmt.Sprintf("✓ Dry run OK: %d rows would be imported (rolled back)", msg.RowsCopied), false)
+			return a, nil
+		}
+		a.showCSVImport = false
+		toast := a.ShowToast(fmt.Sprintf("Imported %d rows into %s.%s", msg.RowsCopied, msg.Schema, msg.Table))
+		// Reload the table so the new rows are visible
+		if tab := a.resultTabs.GetTabByObjectID(msg.Schema + "." + msg.Table); tab != nil && tab.Structure != nil {
+			return a, tea.Batch(toast, a.loadTableDataForTab(msg.Schema, msg.Table, tab.ObjectID))
+		}
+		return a, nil
+
+	case components.CloseCSVImportDialogMsg:
+		a.showCSVImport = false
+		return a, nil
+
+	case commands.QueryBuilderCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		var tables []string
+		for _, cmd := range a.getTableCommands() {
+			tables = append(tables, cmd.Label)
+		}
+		schema, table := a.getActiveSchemaTable()
+		defaultLimit := 0
+		if a.config != nil {
+			defaultLimit = a.config.General.DefaultLimit
+		}
+		a.queryBuilder.Open(tables, schema, table, defaultLimit)
+		a.showQueryBuilder = true
+		return a, nil
+
+	case components.QueryBuilderTableSelectedMsg:
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			a.ShowError("No Connection", err.Error())
+			return a, nil
+		}
+		columns, err := metadata.GetTableColumns(context.Background(), conn.Pool, msg.Schema, msg.Table)
+		if err != nil {
+			a.ShowError("Query Builder", fmt.Sprintf("Failed to load columns for %s.%s:\n\n%v", msg.Schema, msg.Table, err))
+			return a, nil
+		}
+		infos := make([]models.ColumnInfo, len(columns))
+		for i, col := range columns {
+			infos[i] = models.ColumnInfo{Name: col.Name, DataType: col.DataType}
+		}
+		a.queryBuilder.SetColumns(msg.Schema, msg.Table, infos)
+		return a, nil
+
+	case components.QueryBuilderRunMsg:
+		a.showQueryBuilder = false
+		if msg.Edit {
+			// Hand the generated SQL to the editor for tweaking before running
+			a.sqlEditor.SetContent(msg.SQL)
+			a.sqlEditor.Expand()
+			a.state.FocusArea = models.FocusSQLEditor
+			a.updatePanelStyles()
+			return a, nil
+		}
+		sql := msg.SQL
+		return a, func() tea.Msg {
+			return components.ExecuteQueryMsg{SQL: sql}
+		}
+
+	case components.CloseQueryBuilderMsg:
+		a.showQueryBuilder = false
+		return a, nil
+
+	case commands.NotificationsCommandMsg:
+		a.notificationLog.Open("")
+		a.showNotifications = true
+		return a, nil
+
+	case commands.ListenCommandMsg, commands.NotifyCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		prompt := "listen"
+		if _, ok := msg.(commands.NotifyCommandMsg); ok {
+			prompt = "notify-channel"
+		}
+		a.notificationLog.Open(prompt)
+		a.showNotifications = true
+		return a, nil
+
+	case components.ListenChannelMsg:
+		return a, a.listenChannel(msg.Channel, false)
+
+	case components.UnlistenChannelMsg:
+		return a, a.listenChannel(msg.Channel, true)
+
+	case components.SendNotifyMsg:
+		return a, a.sendNotify(msg.Channel, msg.Payload)
+
+	case messages.ListenResultMsg:
+		return a, a.handleListenResult(msg)
+
+	case messages.NotificationReceivedMsg:
+		if msg.Listener != a.listener {
+			return a, nil
+		}
+		n := msg.Notification
+		a.notificationLog.AddNotification(n.Channel, n.Payload, n.PID, n.ReceivedAt, a.showNotifications)
+		return a, waitForNotification(msg.Listener)
+
+	case messages.ListenerClosedMsg:
+		if msg.Listener != a.listener {
+			return a, nil
+		}
+		a.listener = nil
+		a.notificationLog.SetChannels(nil)
+		if msg.Err != nil {
+			a.notificationLog.AddStatus(fmt.Sprintf("Listener connection lost: %v", msg.Err), true)
+		}
+		return a, nil
+
+	case messages.NotifySentMsg:
+		if msg.Err != nil {
+			a.notificationLog.AddStatus(fmt.Sprintf("NOTIFY %s failed: %v", msg.Channel, msg.Err), true)
+		} else {
+			a.notificationLog.AddStatus("Sent NOTIFY on "+msg.Channel, false)
+		}
+		return a, nil
+
+	case components.CloseNotificationLogMsg:
+		a.showNotifications = false
+		return a, nil
+
+	case commands.ErrorLogCommandMsg:
+		a.errorLog.Open()
+		a.showErrorLog = true
+		return a, nil
+
+	case components.CloseErrorLogMsg:
+		a.showErrorLog = false
+		return a, nil
+
+	case commands.PsqlCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		cmd, err := a.openPsql(a.state.ActiveConnection.Config)
+		if err != nil {
+			a.ShowError("Cannot Open psql", err.Error())
+			return a, nil
+		}
+		return a, cmd
+
+	case messages.PsqlExitedMsg:
+		if msg.Err != nil {
+			a.ShowError("psql Exited With an Error", msg.Err.Error())
+		}
+		return a, nil
+
+	case commands.BlockingLocksCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		a.locksMonitor.Open()
+		a.showLocks = true
+		return a, a.refreshBlockingLocks()
+
+	case components.RefreshLocksMsg:
+		return a, a.refreshBlockingLocks()
+
+	case messages.BlockingLocksLoadedMsg:
+		if !a.showLocks || msg.Tick != a.locksTick {
+			return a, nil
+		}
+		a.locksMonitor.SetLocks(msg.Locks, msg.Err, time.Now())
+		tick := msg.Tick
+		return a, tea.Tick(components.LocksRefreshInterval, func(time.Time) tea.Msg {
+			return messages.LocksTickMsg{Tick: tick}
+		})
+
+	case messages.LocksTickMsg:
+		if !a.showLocks || msg.Tick != a.locksTick {
+			return a, nil
+		}
+		return a, a.loadBlockingLocks(msg.Tick)
+
+	case components.SignalBackendMsg:
+		if a.dryRun {
+			action := fmt.Sprintf("Would cancel the query of PID %d", msg.PID)
+			if msg.Terminate {
+				action = fmt.Sprintf("Would terminate PID %d", msg.PID)
+			}
+			a.openDryRun(action, query.InlineParams(metadata.SignalBackendSQL(msg.Terminate), msg.PID))
+			return a, nil
+		}
+		return a, a.signalBackend(msg.PID, msg.Terminate)
+
+	case messages.BackendSignaledMsg:
+		verb := "Cancelled the query of"
+		if msg.Terminate {
+			verb = "Terminated"
+		}
+		switch {
+		case msg.Err != nil:
+			a.locksMonitor.SetStatus(fmt.Sprintf("Could not signal PID %d: %v", msg.PID, msg.Err), true)
+		case !msg.OK:
+			a.locksMonitor.SetStatus(fmt.Sprintf("PID %d is no longer running", msg.PID), true)
+		default:
+			a.locksMonitor.SetStatus(fmt.Sprintf("%s PID %d", verb, msg.PID), false)
+		}
+		if !a.showLocks {
+			return a, nil
+		}
+		return a, a.refreshBlockingLocks()
+
+	case components.CloseLocksMonitorMsg:
+		a.showLocks = false
+		return a, nil
+
+	case commands.ServerInfoCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		// Queried on its own, so it works while the tree is still loading
+		config := a.state.ActiveConnection.Config
+		a.serverInfoPanel.Open(fmt.Sprintf("%s:%d", config.Host, config.Port))
+		a.showServerInfo = true
+		return a, a.loadServerInfo()
+
+	case messages.ServerInfoLoadedMsg:
+		a.serverInfoPanel.SetInfo(msg.Info, msg.Err)
+		return a, nil
+
+	case commands.ForeignServersCommandMsg:
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		a.foreignServers.Open()
+		a.showForeignServers = true
+		return a, a.loadForeignServers()
+
+	case messages.ForeignServersLoadedMsg:
+		a.foreignServers.SetServers(msg.Servers, msg.Err)
+		return a, nil
+
+	case components.CloseForeignServersMsg:
+		a.showForeignServers = false
+		return a, nil
+
+	case messages.CellConditionMsg:
+		if msg.Err != nil {
+			a.ShowError("Filter Error", fmt.Sprintf("Failed to read column types:\n\n%v", msg.Err))
+			return a, nil
+		}
+		sql := filterBuilder.ConditionSQL(msg.Condition)
+		if !msg.Apply {
+			if err := clipboard.WriteAll(sql); err != nil {
+				a.ShowError("Copy Failed", err.Error())
+				return a, nil
+			}
+			if msg.Masked {
+				// The toast would show the value the grid hides
+				return a, a.ShowToast("Copied the condition on " + msg.Condition.Column)
+			}
+			return a, a.ShowToast("Copied: " + sql)
+		}
+		tab := a.resultTabs.GetActiveTab()
+		if tab == nil || tab.ObjectID != msg.ObjectID {
+			// Switched tabs while the column types loaded
+			return a, nil
+		}
+		parts := strings.SplitN(msg.ObjectID, ".", 2)
+		filter := filterBuilder.WithCondition(a.activeFilter, msg.Condition, parts[0], parts[1])
+		a.activeFilter = &filter
+		return a, tea.Batch(a.loadTableDataWithFilter(filter), a.ShowToast("Filter: "+sql))
+
+	case components.CloseServerInfoMsg:
+		a.showServerInfo = false
+		return a, nil
+
+	case components.SessionLostDecisionMsg:
+		a.showSessionLost = false
+		if !msg.Reconnect {
+			return a, nil
+		}
+		config := a.lostConnection
+		if a.sessionLostPrompt.Loss == connection.SessionDatabaseGone {
+			config.Database = "postgres"
+			config.Name = ""
+		}
+		return a, func() tea.Msg {
+			return messages.ConnectionStartMsg{Config: config}
+		}
+
+	case components.SafeModeDecisionMsg:
+		a.showSafeModePrompt = false
+		return a, a.endPendingTx(msg.Commit)
+
+	case messages.AutoSaveTickMsg:
+		// Keep the previous session's copy until the user has decided
+		if !a.showRecoveryPrompt {
+			if err := a.recovery.Save(a.sqlEditor.GetContent()); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+		return a, a.scheduleAutoSave()
+
+	case components.RecoveryDecisionMsg:
+		a.showRecoveryPrompt = false
+		if !msg.Restore {
+			a.ClearRecovery()
+			return a, nil
+		}
+		a.sqlEditor.SetContent(a.recoveryPrompt.SQL)
+		a.sqlEditor.Expand()
+		return a, a.ShowToast("Restored the SQL editor")
+
+	case messages.SafeModeFinishedMsg:
+		if msg.Err != nil {
+			title := "Rollback Failed"
+			if msg.Commit {
+				title = "Commit Failed"
+			}
+			a.ShowError(title, msg.Err.Error())
+			return a, nil
+		}
+		if msg.Commit {
+			return a, a.ShowToast(fmt.Sprintf("Committed: %s", a.safeModePrompt.Summary()))
+		}
+		return a, a.ShowToast("Rolled back: nothing was changed")
+
+	case components.CloseContextMenuMsg:
+		a.showContextMenu = false
+		return a, nil
+
+	case components.ContextMenuSelectedMsg:
+		a.showContextMenu = false
+		return a, a.runContextMenuAction(msg.Action, msg.Node)
+
+	case messages.ObjectDDLLoadedMsg:
+		if msg.Details.Err != nil {
+			a.ShowError("Copy Failed", fmt.Sprintf("Failed to load the %s definition:\n\n%v", msg.Details.ObjectType, msg.Details.Err))
+			return a, nil
+		}
+		if err := clipboard.WriteAll(msg.Details.Content); err != nil {
+			a.ShowError("Copy Failed", fmt.Sprintf("Failed to copy to clipboard:\n\n%v", err))
+			return a, nil
+		}
+		return a, a.ShowToast(fmt.Sprintf("Copied DDL of %s", msg.Details.Name))
+
+	case components.OpenExternalEditorMsg:
+		// Open external editor
+		return a, a.openExternalEditor(msg.Content)
+
+	case components.LoadHistoryQueryMsg:
+		a.sqlEditor.SetContent(msg.SQL)
+		a.sqlEditor.Expand()
+		a.state.FocusArea = models.FocusSQLEditor
+		a.updatePanelStyles()
+		return a, nil
+
+	case components.ExternalEditorResultMsg:
+		if msg.Error != nil {
+			// Leave the SQL editor content untouched on failure
+			a.ShowError("Editor Error", msg.Error.Error())
+			return a, nil
+		}
+		a.sqlEditor.SetContent(msg.Content)
+		a.sqlEditor.Expand()
+		a.state.FocusArea = models.FocusSQLEditor
+		a.updatePanelStyles()
+		return a, nil
+
+	case components.GoToDefinitionMsg:
+		if a.state.ActiveConnection == nil || a.treeView.Root == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+		if msg.Schema != "" {
+			return a.openDefinition(msg.Schema, msg.Name, nil)
+		}
+		return a, a.loadSearchPath(msg.Name)
+
+	case messages.SearchPathLoadedMsg:
+		if msg.Err != nil {
+			a.ShowError("Go to Definition", fmt.Sprintf("Failed to read search_path:\n\n%v", msg.Err))
+			return a, nil
+		}
+		return a.openDefinition("", msg.Name, msg.SearchPath)
+
+	case components.ExecuteQueryMsg:
+		// Handle query execution from SQL editor
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return a, nil
+		}
+
+		// Create pending tab immediately
+		pendingID := a.resultTabs.StartPendingQuery(msg.SQL)
+
+		// Immediately switch focus to data panel and collapse editor
+		a.sqlEditor.Collapse()
+		a.state.FocusArea = models.FocusDataPanel
+		a.updatePanelStyles()
+
+		// Create cancellable context for query execution
+		ctx, cancel := context.WithCancel(context.Background())
+		a.executeCancelFn = cancel
+
+		// Execute query asynchronously and start spinner
+		return a, tea.Batch(
+			a.executeSpinner.Tick,
+			func() tea.Msg {
+				conn, err := a.connectionManager.GetActive()
+				if err != nil {
+					return messages.QueryResultMsg{
+						SQL: msg.SQL,
+						Result: models.QueryResult{
+							Error: fmt.Errorf("failed to get connection: %w", err),
+						},
+						PendingID: pendingID,
+					}
+				}
+
+				result := query.Execute(ctx, conn.Pool.GetPool(), msg.SQL)
+				return messages.QueryResultMsg{
+					SQL:       msg.SQL,
+					Result:    result,
+					PendingID: pendingID,
+				}
+			},
+		)
+
+	case messages.QueryResultMsg:
+		// Clear execution state
+		a.executeCancelFn = nil
+
+		// Handle query result
+		if msg.Result.Error != nil {
+			// Check if it was cancelled (context cancelled error)
+			if msg.Result.Error.Error() == "context canceled" {
+				// Already handled by CancelPendingQuery, just return
+				return a, nil
+			}
+			// Show error and remove pending tab
+			a.resultTabs.CancelPendingQuery()
+			a.ShowError("Query Error", msg.Result.Error.Error())
+			return a, nil
+		}
+
+		// Complete the pending query with results
+		a.resultTabs.CompletePendingQuery(msg.SQL, msg.Result)
+
+		return a, nil
+
+	case components.ApplyFilterMsg:
+		// Apply the filter and reload table data
+		a.showFilterBuilder = false
+		a.activeFilter = &msg.Filter
+
+		// Reload table with filter
+		if a.state.TreeSelected != nil && a.state.TreeSelected.Type == models.TreeNodeTypeTable {
+			return a, a.loadTableDataWithFilter(*a.activeFilter)
+		}
+		return a, nil
+
+	case components.CloseFilterBuilderMsg:
+		a.showFilterBuilder = false
+		return a, nil
+
+	case components.CloseJSONBViewerMsg:
+		a.showJSONBViewer = false
+		return a, nil
+
+	case components.CloseErrorOverlayMsg:
+		a.showError = false
+		return a, nil
+
+	case components.PasswordSubmitMsg:
+		// User submitted password from password dialog
+		a.showPasswordDialog = false
+		if a.pendingConnectionInfo != nil {
+			// Create connection config with the entered password
+			config := a.pendingConnectionInfo.ToConnectionConfig()
+			config.Password = msg.Password
+
+			// Store password info to save after successful connection
+			// This avoids: 1) saving wrong passwords, 2) double keychain prompts
+			a.pendingPasswordSave = &pendingPassword{
+				Host:     config.Host,
+				Port:     config.Port,
+				Database: config.Database,
+				User:     config.User,
+				Password: msg.Password,
+			}
+
+			a.pendingConnectionInfo = nil
+			return a.performConnection(config)
+		}
+		return a, nil
+
+	case components.PasswordCancelMsg:
+		// User cancelled password dialog
+		a.showPasswordDialog = false
+		a.pendingConnectionInfo = nil
+		// Re-show connection dialog
+		a.showConnectionDialog = true
+		return a, nil
+
+	case components.CloseCommandPaletteMsg:
+		a.showCommandPalette = false
+		return a, nil
+
+	case components.ExecuteFavoriteMsg:
+		if msg.Favorite.IsBookmark() {
+			return a.openBookmark(msg.Favorite)
+		}
+		if msg.Favorite.IsView() {
+			return a, a.openTableView(msg.Favorite)
+		}
+
+		// Execute favorite query
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Database Connection", "Please connect to a database before executing queries.\n\nPress 'c' to open the connection dialog.")
+			return a, nil
+		}
+
+		// Record usage
+		if a.favoritesManager != nil {
+			if err := a.favoritesManager.RecordUsage(msg.Favorite.ID); err != nil {
+				// Log error but don't block execution
+				log.Printf("Warning: Failed to record favorite usage: %v", err)
+			}
+		}
+
+		// Run it like an editor query, so it gets a pending tab and the
+		// one-query-at-a-time guard
+		a.showFavorites = false
+		sql := msg.Favorite.Query
+		return a, func() tea.Msg {
+			return components.ExecuteQueryMsg{SQL: sql}
+		}
+
+	case components.CloseFavoritesDialogMsg:
+		a.showFavorites = false
+		return a, nil
+
+	case components.SearchInputMsg:
+		// Handle search request from search input
+		a.showSearch = false
+		if msg.Query == "" {
+			return a, nil
+		}
+
+		// Get the active table view (Result Tabs or main TableView)
+		activeTable := a.getActiveTableView()
+
+		if msg.Mode == "local" {
+			// Local search - search only loaded data
+			if activeTable != nil {
+				activeTable.SearchLocal(msg.Query)
+			}
+		} else {
+			// For Result Tabs, always use local search (data is already loaded)
+			if a.resultTabs.HasTabs() {
+				if activeTable != nil {
+					activeTable.SearchLocal(msg.Query)
+				}
+				return a, nil
+			}
+
+			// Table search - query the database (only for table browser)
+			if a.state.ActiveConnection == nil {
+				a.ShowError("No Connection", "Please connect to a database first")
+				return a, nil
+			}
+
+			if a.currentTable == "" {
+				a.ShowError("No Table", "Please select a table first")
+				return a, nil
+			}
+
+			// Execute table search
+			return a, a.searchTable(msg.Query)
+		}
+		return a, nil
+
+	case components.CloseSearchMsg:
+		a.showSearch = false
+		a.searchInput.Reset()
+		return a, nil
+
+	case SearchTableResultMsg:
+		if msg.Err != nil {
+			a.ShowError("Search Error", msg.Err.Error())
+			return a, nil
+		}
+
+		if msg.Data == nil || len(msg.Data.Rows) == 0 {
+			a.ShowError("No Results", fmt.Sprintf("No matches found for '%s'", msg.Query))
+			return a, nil
+		}
+
+		// Replace table data with search results
+		a.tableView.SetData(msg.Data.Columns, msg.Data.Rows, int(msg.Data.TotalRows))
+		a.tableView.SetColumnKinds(msg.Data.ColumnKinds)
+		a.tableView.GeneratedSQL = msg.Data.SQL
+
+		// Build matches from all cells that contain the query
+		queryLower := strings.ToLower(msg.Query)
+		var matches []components.MatchPos
+		for rowIdx, row := range msg.Data.Rows {
+			for colIdx, cell := range row {
+				if strings.Contains(strings.ToLower(cell), queryLower) {
+					matches = append(matches, components.MatchPos{Row: rowIdx, Col: colIdx})
+				}
+			}
+		}
+
+		a.tableView.SetSearchResults(msg.Query, matches)
+		return a, nil
+
+	case components.AddFavoriteMsg:
+		if a.favoritesManager != nil {
+			conn := ""
+			if a.state.ActiveConnection != nil {
+				conn = a.state.ActiveConnection.Config.Name
+			}
+			db := a.state.CurrentDatabase
+			_, err := a.favoritesManager.Add(msg.Name, msg.Description, msg.Query, conn, db, msg.Tags)
+			if err != nil {
+				a.ShowError("Cannot Add Favorite", fmt.Sprintf("Failed to add favorite:\n\n%v\n\nPlease check your input and try again.", err))
+			} else {
+				// Refresh the dialog
+				a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+				return a, a.ShowToast("Favorite saved")
+			}
+		} else {
+			a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+		}
+		return a, nil
+
+	case components.EditFavoriteMsg:
+		if a.favoritesManager != nil {
+			err := a.favoritesManager.Update(msg.FavoriteID, msg.Name, msg.Description, msg.Query, msg.Tags)
+			if err != nil {
+				a.ShowError("Cannot Update Favorite", fmt.Sprintf("Failed to update favorite:\n\n%v\n\nPlease check your input and try again.", err))
+			} else {
+				// Refresh the dialog
+				a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+			}
+		} else {
+			a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+		}
+		return a, nil
+
+	case components.DeleteFavoriteMsg:
+		if a.favoritesManager != nil {
+			err := a.favoritesManager.Delete(msg.FavoriteID)
+			if err != nil {
+				a.ShowError("Cannot Delete Favorite", fmt.Sprintf("Failed to delete favorite:\n\n%v\n\nThe favorite may have already been deleted.", err))
+			} else {
+				// Refresh the dialog
+				a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+				// Adjust selection if needed
+				if a.favoritesDialog != nil {
+					favorites := a.favoritesManager.GetAll()
+					if len(favorites) > 0 {
+						// Keep selection valid
+						a.favoritesDialog.SetFavorites(favorites)
+					}
+				}
+			}
+		} else {
+			a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+		}
+		return a, nil
+
+	case messages.ErrorMsg:
+		// Handle error messages
+		a.ShowError(msg.Title, msg.Message)
+		return a, nil
+
+	case tea.KeyMsg:
+		a.lastInput = time.Now()
+
+		// Nothing can be used under the startup splash, but it can be quit
+		if a.isStarting() {
+			if msg.String() == "ctrl+c" {
+				return a, tea.Quit
+			}
+			return a, nil
+		}
+
+		// Handle error overlay dismissal first if visible
+		if a.showError {
+			key := msg.String()
+			if key == "esc" || key == "enter" {
+				a.DismissError()
+				return a, nil
+			}
+			// Allow quit keys to pass through even when error is showing
+			if key == "q" || key == "ctrl+c" {
+				return a, tea.Quit
+			}
+			// Consume all other keys when error is showing
+			return a, nil
+		}
+
+		// The startup recovery prompt waits for an answer before anything
+		// else, the connection dialog included
+		if a.showRecoveryPrompt {
+			var cmd tea.Cmd
+			a.recoveryPrompt, cmd = a.recoveryPrompt.Update(msg)
+			return a, cmd
+		}
+
+		// Handle connection dialog if visible
+		if a.showConnectionDialog {
+			return a.handleConnectionDialog(msg)
+		}
+
+		// Handle password dialog if visible
+		if a.showPasswordDialog {
+			var cmd tea.Cmd
+			a.passwordDialog, cmd = a.passwordDialog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle command palette if visible
+		if a.showCommandPalette {
+			return a.handleCommandPalette(msg)
+		}
+
+		// Handle filter builder input
+		if a.showFilterBuilder {
+			return a.handleFilterBuilder(msg)
+		}
+
+		// Handle JSONB viewer input
+		if a.showJSONBViewer {
+			return a.handleJSONBViewer(msg)
+		}
+
+		// Handle favorites dialog if visible
+		if a.showFavorites {
+			return a.handleFavoritesDialog(msg)
+		}
+
+		// Handle search input if visible
+		if a.showSearch {
+			return a.handleSearchInput(msg)
+		}
+
+		// Handle CSV import dialog if visible
+		if a.showCSVImport {
+			var cmd tea.Cmd
+			a.csvImportDialog, cmd = a.csvImportDialog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle query builder if visible
+		if a.showQueryBuilder {
+			var cmd tea.Cmd
+			a.queryBuilder, cmd = a.queryBuilder.Update(msg)
+			return a, cmd
+		}
+
+		// Handle notification log if visible
+		if a.showNotifications {
+			var cmd tea.Cmd
+			a.notificationLog, cmd = a.notificationLog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle error log if visible
+		if a.showErrorLog {
+			var cmd tea.Cmd
+			a.errorLog, cmd = a.errorLog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle dry run dialog if visible; it shows over the dialog that
+		// generated the statement
+		if a.showDryRun {
+			var cmd tea.Cmd
+			a.dryRunDialog, cmd = a.dryRunDialog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle truncate dialog if visible
+		if a.showTruncate {
+			var cmd tea.Cmd
+			a.truncateDialog, cmd = a.truncateDialog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle locks monitor if visible
+		if a.showLocks {
+			var cmd tea.Cmd
+			a.locksMonitor, cmd = a.locksMonitor.Update(msg)
+			return a, cmd
+		}
+
+		// Handle session lost prompt if visible; it waits for an answer
+		if a.showSessionLost {
+			var cmd tea.Cmd
+			a.sessionLostPrompt, cmd = a.sessionLostPrompt.Update(msg)
+			return a, cmd
+		}
+
+		// Handle safe mode prompt if visible; it waits for an answer
+		if a.showSafeModePrompt {
+			var cmd tea.Cmd
+			a.safeModePrompt, cmd = a.safeModePrompt.Update(msg)
+			return a, cmd
+		}
+
+		// Handle row color rule editor if visible
+		if a.showRowColors {
+			var cmd tea.Cmd
+			a.rowColorsDialog, cmd = a.rowColorsDialog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle row edit form if visible
+		if a.showRowEdit {
+			var cmd tea.Cmd
+			a.rowEditForm, cmd = a.rowEditForm.Update(msg)
+			return a, cmd
+		}
+
+		// Handle LIMIT/OFFSET editor if visible
+		if a.showRowWindow {
+			var cmd tea.Cmd
+			a.rowWindowDialog, cmd = a.rowWindowDialog.Update(msg)
+			return a, cmd
+		}
+
+		// Handle server info panel if visible
+		if a.showServerInfo {
+			var cmd tea.Cmd
+			a.serverInfoPanel, cmd = a.serverInfoPanel.Update(msg)
+			return a, cmd
+		}
+
+		// Handle foreign servers panel if visible
+		if a.showForeignServers {
+			var cmd tea.Cmd
+			a.foreignServers, cmd = a.foreignServers.Update(msg)
+			return a, cmd
+		}
+
+		// Handle tree context menu if open
+		if a.showContextMenu {
+			var cmd tea.Cmd
+			a.contextMenu, cmd = a.contextMenu.Update(msg)
+			return a, cmd
 		}
 
 		// Handle TreeView search mode - route keys to TreeView
@@ -868,6 +2214,25 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Handle the data grid's quick filter - route keys to the table while
+		// it is typed, and Esc to clear it once applied
+		if a.state.FocusArea == models.FocusDataPanel && a.state.ViewMode == models.NormalMode {
+			if activeTable := a.getActiveTableView(); activeTable != nil {
+				if activeTable.IsQuickFilterTyping() || (activeTable.IsQuickFilterActive() && msg.String() == "esc") {
+					activeTable.UpdateQuickFilter(msg)
+					return a, nil
+				}
+			}
+		}
+
+		// Jump to a panel; checked before the editors so it also works
+		// while one of them has focus
+		if area, ok := focusKeys[msg.String()]; ok && a.state.ViewMode == models.NormalMode {
+			a.state.FocusArea = area
+			a.updatePanelStyles()
+			return a, nil
+		}
+
 		// Handle code editor input if visible and DataPanel is focused
 		if a.state.FocusArea == models.FocusDataPanel {
 			// Keys that the code editor handles in read-only mode
@@ -899,10 +2264,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Swap the bottom-bar hint sets; checked first so it also works
+		// while the SQL editor has focus
+		if msg.String() == components.HintToggleKey {
+			a.showMoreHints = !a.showMoreHints
+			return a, nil
+		}
+
 		// If SQL editor is focused, handle input
 		if a.isSQLEditorFocused() {
-			// Handle escape to unfocus
+			// Handle escape to unfocus; in fullscreen it only leaves fullscreen
 			if msg.String() == "esc" {
+				if a.sqlEditor.IsFullscreen() {
+					a.sqlEditor.ExitFullscreen()
+					return a, nil
+				}
 				if a.sqlEditor.IsExpanded() {
 					a.sqlEditor.Collapse()
 				}
@@ -923,6 +2299,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return a, nil
 			}
 
+			// Layout keys, before the rest is routed to the editor
+			switch msg.String() {
+			case "f2":
+				return a, a.cycleEditorHeight()
+			case "f11":
+				a.toggleEditorFullscreen()
+				return a, nil
+			case "ctrl+shift+up":
+				a.sqlEditor.IncreaseHeight()
+				return a, nil
+			case "ctrl+shift+down":
+				a.sqlEditor.DecreaseHeight()
+				return a, nil
+			}
+
 			// Tab is handled in the unified Tab case below for focus cycling
 			if msg.String() == "tab" || msg.String() == "shift+tab" || msg.String() == "backtab" {
 				// Let Tab fall through to the switch case for focus cycling
@@ -948,6 +2339,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return a, nil
 
+		// F2 cycles the editor height presets, F11 edits fullscreen
+		case "f2":
+			return a, a.cycleEditorHeight()
+		case "f11":
+			a.toggleEditorFullscreen()
+			return a, nil
+
 		// Ctrl+Shift+Up to increase editor height preset
 		case "ctrl+shift+up":
 			if a.isSQLEditorFocused() && a.sqlEditor.IsExpanded() {
@@ -967,6 +2365,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Previous result tab (when not in SQL editor)
 			if a.resultTabs.HasTabs() && !a.isSQLEditorFocused() {
 				a.resultTabs.PrevTab()
+				a.syncActiveFilter()
 				// Sync SQL editor content with the active tab's SQL
 				if sql := a.resultTabs.GetActiveSQL(); sql != "" {
 					a.sqlEditor.SetContent(sql)
@@ -984,6 +2383,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Next result tab (when not in SQL editor)
 			if a.resultTabs.HasTabs() && !a.isSQLEditorFocused() {
 				a.resultTabs.NextTab()
+				a.syncActiveFilter()
 				// Sync SQL editor content with the active tab's SQL
 				if sql := a.resultTabs.GetActiveSQL(); sql != "" {
 					a.sqlEditor.SetContent(sql)
@@ -997,6 +2397,27 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return a, nil
 
+		// Split view: | shows two tabs at once, \ moves focus between them
+		case "|":
+			if a.resultTabs.HasTabs() && !a.isSQLEditorFocused() {
+				if a.resultTabs.ToggleSplit() {
+					return a, a.ShowToast("Split view: \\ switches panes, | closes")
+				}
+				if a.resultTabs.TabCount() < 2 {
+					return a, a.ShowToast("Open another tab to split the view")
+				}
+				return a, nil
+			}
+
+		case "\\":
+			if !a.isSQLEditorFocused() && a.resultTabs.SwitchSplitFocus() {
+				a.syncActiveFilter()
+				if sql := a.resultTabs.GetActiveSQL(); sql != "" {
+					a.sqlEditor.SetContent(sql)
+				}
+				return a, nil
+			}
+
 		case "1", "2", "3", "4":
 			// Switch structure view sub-tabs when active tab is TableData
 			if !a.isSQLEditorFocused() {
@@ -1021,6 +2442,20 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case "{", "}":
+			// Cycle structure sub-tabs when active tab is TableData
+			if !a.isSQLEditorFocused() {
+				activeTab := a.resultTabs.GetActiveTab()
+				if activeTab != nil && activeTab.Type == components.TabTypeTableData && activeTab.Structure != nil {
+					if msg.String() == "}" {
+						activeTab.Structure.NextTab()
+					} else {
+						activeTab.Structure.PrevTab()
+					}
+					return a, a.triggerStructureMetadataLoad(activeTab.Structure, activeTab.ObjectID)
+				}
+			}
+
 		case "ctrl+p":
 			// Open SQL editor (expand if collapsed)
 			if !a.sqlEditor.IsExpanded() {
@@ -1035,8 +2470,15 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.commandPalette.SetCommands(a.getBuiltinCommands())
 			a.commandPalette.SetTables(a.getTableCommands())
 			a.commandPalette.SetHistory(a.getHistoryCommands())
+			a.commandPalette.SetRecent(a.getRecentCommands())
 			a.showCommandPalette = true
 			return a, nil
+		case "ctrl+g":
+			// Open command palette on recently opened objects
+			return a.openRecentObjects()
+		case "ctrl+o":
+			// Pick another database on the same server
+			return a, a.loadDatabases()
 		case "ctrl+b":
 			// Open favorites dialog
 			if a.favoritesManager != nil {
@@ -1059,13 +2501,22 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.state.ViewMode = models.HelpMode
 			}
 		case "esc":
-			// Cancel executing query first
-			if a.resultTabs.HasPendingQuery() && a.executeCancelFn != nil {
-				a.executeCancelFn()
-				a.executeCancelFn = nil
+			// Cancel executing query first. The pending tab is cleared even
+			// without a cancel func, so a lost result can't block new queries.
+			if a.resultTabs.HasPendingQuery() {
+				if a.executeCancelFn != nil {
+					a.executeCancelFn()
+					a.executeCancelFn = nil
+				}
 				a.resultTabs.CancelPendingQuery()
 				return a, nil
 			}
+			// Close a preview opened by a click
+			if a.state.FocusArea == models.FocusDataPanel && a.state.ViewMode == models.NormalMode {
+				if activeTable := a.getActiveTableView(); activeTable != nil && activeTable.PreviewOnClick && activeTable.HidePreviewPane() {
+					return a, nil
+				}
+			}
 			// Exit help mode
 			if a.state.ViewMode == models.HelpMode {
 				a.state.ViewMode = models.NormalMode
@@ -1132,6 +2583,19 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return a, nil
 		case "ctrl+r":
+			// On a query result tab, run its SQL again in a fresh tab
+			if a.state.FocusArea == models.FocusDataPanel {
+				if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeQueryResult {
+					return a, a.rerunQueryTab(tab)
+				}
+			}
+			// On a table tab, reload its rows in place, highlighting changes
+			if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeTableData && tab.Structure != nil {
+				if tab.Refreshing {
+					return a, nil
+				}
+				return a, a.refreshTab(tab)
+			}
 			// Refresh current table data (preserve sort and filter)
 			if a.currentTable != "" {
 				parts := strings.Split(a.currentTable, ".")
@@ -1152,22 +2616,33 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return a, nil
+		case "#":
+			// Count the rows matching the active filter without loading
+			// them; without a filter the key falls through
+			if a.state.FocusArea == models.FocusDataPanel && a.activeFilter != nil {
+				if schema, table, ok := a.filterTable(); ok {
+					return a, a.countWithFilter(schema, table, *a.activeFilter)
+				}
+			}
+		case components.QueryNextPageKey:
+			// Load the next page of a query result that filled its LIMIT
+			if a.state.FocusArea == models.FocusDataPanel {
+				if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeQueryResult && tab.TableView != nil && tab.TableView.MoreRows {
+					return a, a.loadNextQueryPage(tab)
+				}
+			}
+		case components.QueryMessagesToggleKey:
+			// Show or hide the notices and statement results of a query
+			if a.state.FocusArea == models.FocusDataPanel {
+				if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeQueryResult && components.HasQueryMessages(tab.Result) {
+					tab.ShowMessages = !tab.ShowMessages
+					return a, nil
+				}
+			}
 		case "ctrl+x":
 			// Clear filter and reload
-			if a.activeFilter != nil && a.state.TreeSelected != nil {
-				a.activeFilter = nil
-				schemaNode := a.state.TreeSelected.Parent
-				if schemaNode != nil {
-					return a, a.loadTableData(messages.LoadTableDataMsg{
-						Schema:     schemaNode.Label,
-						Table:      a.state.TreeSelected.Label,
-						Limit:      100,
-						Offset:     0,
-						SortColumn: a.tableView.GetSortColumn(),
-						SortDir:    a.tableView.GetSortDirection(),
-						NullsFirst: a.tableView.GetNullsFirst(),
-					})
-				}
+			if a.activeFilter != nil {
+				return a, a.clearFilter()
 			}
 			return a, nil
 		case "y":
@@ -1214,6 +2689,15 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		default:
 			// Handle tree navigation when TreeView is focused
 			if a.state.FocusArea == models.FocusTreeView && a.state.ViewMode == models.NormalMode {
+				switch msg.String() {
+				case ".":
+					a.toggleSystemSchemas()
+					return a, nil
+				case "b":
+					return a, a.bookmarkTreeNode()
+				case "T":
+					return a, a.openTruncate(a.treeView.GetCurrentNode())
+				}
 				var cmd tea.Cmd
 				a.treeView, cmd = a.treeView.Update(msg)
 				return a, cmd
@@ -1224,7 +2708,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Get the active table view (Result Tabs, Structure View, or main TableView)
 				activeTable := a.getActiveTableView()
 
-				// Handle preview pane scrolling (when visible)
+				// Handle preview pane scrolling and docking (when visible)
 				if activeTable != nil && activeTable.PreviewPane != nil && activeTable.PreviewPane.Visible {
 					switch msg.String() {
 					case "K":
@@ -1233,6 +2717,23 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					case "J":
 						activeTable.PreviewPane.ScrollDown()
 						return a, nil
+					case "+", "=":
+						activeTable.PreviewPane.Size = a.previewSize
+						activeTable.PreviewPane.Grow()
+						a.previewSize = activeTable.PreviewPane.Size
+						return a, nil
+					case "-":
+						activeTable.PreviewPane.Size = a.previewSize
+						activeTable.PreviewPane.Shrink()
+						a.previewSize = activeTable.PreviewPane.Size
+						return a, nil
+					case "P":
+						if a.previewPosition == components.PreviewBottom {
+							a.previewPosition = components.PreviewRight
+						} else {
+							a.previewPosition = components.PreviewBottom
+						}
+						return a, nil
 					}
 				}
 
@@ -1244,14 +2745,47 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return a, nil
 				}
 
+				// D = copy the selected constraint/index DDL, E = open it in a tab
+				if msg.String() == "D" || msg.String() == "E" {
+					if cmd, ok := a.structureDDL(msg.String() == "E"); ok {
+						return a, cmd
+					}
+				}
+
+				// E elsewhere on a view's tab opens the view's definition
+				if msg.String() == "E" {
+					if cmd, ok := a.viewDefinition(); ok {
+						return a, cmd
+					}
+				}
+
+				// Follow a column filled from a sequence to the sequence
+				if msg.String() == components.ColumnSequenceKey {
+					if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Structure != nil {
+						if schema, name := tab.Structure.SelectedColumnSequence(); name != "" {
+							return a.openSequence(schema, name)
+						}
+					}
+				}
+
+				// Switch the Columns sub-tab between logical and storage order
+				if msg.String() == components.ColumnOrderKey {
+					if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Structure != nil && tab.Structure.ActiveTab() == components.StructureTabColumns {
+						if tab.Structure.ToggleColumnOrder() {
+							return a, a.ShowToast("Columns in storage order (attnum)")
+						}
+						return a, a.ShowToast("Columns in logical order")
+					}
+				}
+
 				// Handle yank: y = copy current cell, Y = copy preview pane content
 				if msg.String() == "y" {
 					if activeTable != nil {
 						row, col := activeTable.GetSelectedCell()
 						if row >= 0 && col >= 0 && row < len(activeTable.Rows) && col < len(activeTable.Rows[row]) {
-							cellContent := activeTable.Rows[row][col]
+							cellContent := activeTable.CopyValue(row, col)
 							if err := clipboard.WriteAll(cellContent); err == nil {
-								log.Println("Copied cell content to clipboard")
+								return a, a.ShowToast("Copied cell to clipboard")
 							}
 						}
 					}
@@ -1260,7 +2794,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if msg.String() == "Y" {
 					if activeTable != nil && activeTable.PreviewPane != nil && activeTable.PreviewPane.Visible {
 						if err := activeTable.PreviewPane.CopyContent(); err == nil {
-							log.Println("Copied preview content to clipboard")
+							return a, a.ShowToast("Copied preview to clipboard")
 						}
 					}
 					return a, nil
@@ -1295,12 +2829,63 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return a, nil
 				}
 
-				// Toggle relative line numbers
-				if msg.String() == "ctrl+n" {
+				// Show or hide the row numbers, or number rows relative
+				// to the selected one
+				switch msg.String() {
+				case components.RowNumbersKey:
+					activeTable.ToggleLineNumbers()
+					return a, nil
+				case components.RelativeNumbersKey:
 					activeTable.ToggleRelativeNumbers()
 					return a, nil
 				}
 
+				// Widen, narrow or reset column widths
+				switch msg.String() {
+				case "ctrl+right":
+					activeTable.AdjustColumnWidth(components.ColumnWidthStep)
+					return a, nil
+				case "ctrl+left":
+					activeTable.AdjustColumnWidth(-components.ColumnWidthStep)
+					return a, nil
+				case "W":
+					if !activeTable.ResetColumnWidths() {
+						return a, a.ShowToast("No column widths to reset")
+					}
+					return a, nil
+				case components.CellFilterKey, components.CopyCellWhereKey:
+					return a, a.cellCondition(activeTable, msg.String() == components.CellFilterKey)
+				case components.RevealCellKey:
+					if !activeTable.ToggleReveal() {
+						return a, a.ShowToast("Column is not masked")
+					}
+					return a, nil
+				case components.RowWindowKey:
+					return a, a.openRowWindow()
+				case components.EditRowKey:
+					return a, a.openRowEdit()
+				case components.CopyRowsTSVKey:
+					return a, a.copyRowsTSV(activeTable)
+				case components.SelectRowKey:
+					activeTable.ToggleRowSelection()
+					return a, nil
+				case components.SelectAllLoadedKey:
+					activeTable.SelectAllLoaded()
+					toast := fmt.Sprintf("Selected %d loaded rows", activeTable.SelectionCount())
+					if len(activeTable.Rows) < activeTable.TotalRows {
+						toast += fmt.Sprintf(" of %d", activeTable.TotalRows)
+					}
+					return a, a.ShowToast(toast)
+				case components.InvertSelectionKey:
+					activeTable.InvertSelection()
+					return a, nil
+				case components.ClearSelectionKey:
+					if activeTable.ClearSelection() == 0 {
+						return a, a.ShowToast("No rows selected")
+					}
+					return a, nil
+				}
+
 				// Handle Vim motion (number prefixes, g, G, etc.)
 				// This must come before individual key handling
 				if activeTable.HandleVimMotion(msg.String()) {
@@ -1421,21 +3006,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return a, nil
 				case "v":
 					// Open JSONB viewer if cell contains JSONB
-					selectedRow, selectedCol := activeTable.GetSelectedCell()
-					if selectedRow >= 0 && selectedCol >= 0 && selectedRow < len(activeTable.Rows) && selectedCol < len(activeTable.Columns) {
-						cellValue := activeTable.Rows[selectedRow][selectedCol]
-						if jsonb.IsJSONB(cellValue) {
-							// Set viewer dimensions based on terminal size (with max width limit)
-							viewerWidth := a.state.Width * 2 / 3
-							if viewerWidth > 100 {
-								viewerWidth = 100
-							}
-							a.jsonbViewer.Width = viewerWidth
-							a.jsonbViewer.Height = a.state.Height * 3 / 4
-							if err := a.jsonbViewer.SetValue(cellValue); err == nil {
-								a.showJSONBViewer = true
-							}
-						}
+					if value, ok := activeTable.SelectedJSON(); ok {
+						a.openJSONBViewer(value)
 					}
 					return a, nil
 				case "/":
@@ -1456,9 +3028,18 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						activeTable.PrevMatch()
 					}
 					return a, nil
-				case "enter", " ":
-					// Consume enter/space in table view (no action needed for now)
-					// This prevents the key from propagating to tree view
+				case "enter":
+					// Enter drills into a JSON cell; otherwise it is consumed so
+					// it doesn't reach the tree view
+					if value, ok := activeTable.SelectedJSON(); ok {
+						a.openJSONBViewer(value)
+					}
+					return a, nil
+				}
+
+				// Printable keys without an action above start the quick
+				// filter, like typing into a spreadsheet
+				if activeTable.StartQuickFilter(msg) {
 					return a, nil
 				}
 			}
@@ -1508,6 +3089,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				ConnectedAt: conn.ConnectedAt,
 				LastPing:    conn.LastPing,
 				Error:       conn.Error,
+				Superuser:   msg.Superuser,
 			}
 		}
 
@@ -1521,8 +3103,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					log.Printf("Warning: Failed to save password: %v", result.PasswordSaveError)
 				}
 				// Reload history in dialog
-				history := a.connectionHistory.GetRecent(10)
-				a.connectionDialog.SetHistoryEntries(history)
+				a.refreshConnectionDialogHistory()
 			}
 		}
 
@@ -1603,7 +3184,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		switch msg.Node.Type {
-		case models.TreeNodeTypeTable, models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView:
+		case models.TreeNodeTypeTable, models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView, models.TreeNodeTypeForeignTable:
 			// Get schema name by traversing up the tree
 			var schemaName string
 			current := msg.Node.Parent
@@ -1630,6 +3211,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for i, tab := range a.resultTabs.GetAllTabs() {
 				if tab.ObjectID == objectID && tab.Type == components.TabTypeTableData {
 					a.resultTabs.SetActiveTab(i)
+					a.syncActiveFilter()
 					existingFound = true
 					a.state.FocusArea = models.FocusDataPanel
 					a.updatePanelStyles()
@@ -1641,6 +3223,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Create new StructureView for this table
 				tableView := components.NewTableView(a.theme)
 				tableView.Spinner = &a.executeSpinner
+				tableView.SetMasks(a.maskRules)
+				tableView.RowColors = a.rowColors
+				tableView.BoolGlyphs = a.boolGlyphs
+				tableView.ForeignServer = components.ForeignServerOf(msg.Node)
 				structureView := components.NewStructureView(a.theme, tableView)
 
 				// Set loading state
@@ -1648,7 +3234,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				tableView.LoadingStart = time.Now()
 
 				// Add as a new tab
-				a.resultTabs.AddTableData(objectID, msg.Node.Label, structureView)
+				title := a.resultTabs.ObjectTitle(components.TabTitleFields{
+					Schema:   schemaName,
+					Name:     msg.Node.Label,
+					Type:     string(msg.Node.Type),
+					Database: a.state.CurrentDatabase,
+				}, msg.Node.Label)
+				a.resultTabs.AddTableData(objectID, title, structureView)
 
 				// Switch focus immediately to show loading state
 				a.state.FocusArea = models.FocusDataPanel
@@ -1762,7 +3354,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		codeEditor.ObjectName = msg.ObjectName
 
 		// Add as a new tab
-		a.resultTabs.AddCodeEditor(msg.ObjectID, msg.Title, codeEditor)
+		a.resultTabs.AddCodeEditor(msg.ObjectID, a.codeTabTitle(msg), codeEditor)
 		a.state.FocusArea = models.FocusDataPanel
 		a.updatePanelStyles()
 		return a, nil
@@ -1772,6 +3364,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		activeTab := a.resultTabs.GetActiveTab()
 		if activeTab != nil && activeTab.Type == components.TabTypeCodeEditor {
 			a.resultTabs.CloseActiveTab()
+			a.syncActiveFilter()
 		}
 		// Legacy: also clear the global code editor state
 		a.showCodeEditor = false
@@ -1807,6 +3400,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case messages.FilterCountLoadedMsg:
+		if msg.Err != nil {
+			if a.HandleSessionLoss(msg.Err, "") {
+				return a, nil
+			}
+			a.ShowError("Count Error", fmt.Sprintf("Failed to count filtered rows:\n\n%v", msg.Err))
+			return a, nil
+		}
+		noun := "rows match"
+		if msg.Count == 1 {
+			noun = "row matches"
+		}
+		return a, a.ShowToast(fmt.Sprintf("%s %s the filter on %s.%s",
+			components.FormatCount(msg.Count), noun, msg.Schema, msg.Table))
+
 	case messages.TableDataLoadedMsg:
 		if msg.Err != nil {
 			a.ShowError("Database Error", fmt.Sprintf("Failed to load table data:\n\n%v", msg.Err))
@@ -1825,14 +3433,17 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if isInitialLoad {
 			// Initial load - replace all data
 			a.tableView.SetData(msg.Columns, msg.Rows, msg.TotalRows)
+			a.tableView.SetColumnKinds(msg.ColumnKinds)
+			a.tableView.GeneratedSQL = msg.SQL
 			a.tableView.SelectedRow = 0
 			a.tableView.TopRow = 0
 			a.state.FocusArea = models.FocusDataPanel
 			a.updatePanelStyles()
 		} else {
 			// Append paginated data (same table, loading more rows)
-			a.tableView.Rows = append(a.tableView.Rows, msg.Rows...)
+			a.tableView.AppendRows(msg.Rows)
 			a.tableView.TotalRows = msg.TotalRows
+			a.tableView.GeneratedSQL = msg.SQL
 		}
 		a.tableView.IsPaginating = false
 		return a, nil
@@ -1858,6 +3469,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if tab.Structure != nil {
 					// Set table data in the structure view
 					tab.Structure.GetTableView().SetData(msg.Columns, msg.Rows, msg.TotalRows)
+					tab.Structure.GetTableView().SetColumnKinds(msg.ColumnKinds)
+					tab.Structure.GetTableView().GeneratedSQL = msg.SQL
 					// Also load structure metadata (columns, constraints, indexes)
 					conn, err := a.connectionManager.GetActive()
 					if err == nil && conn != nil && conn.Pool != nil {
@@ -1890,6 +3503,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.searchInput, cmd = a.searchInput.Update(msg)
 			return a, cmd
 		}
+		if a.showCSVImport {
+			a.csvImportDialog, cmd = a.csvImportDialog.Update(msg)
+			return a, cmd
+		}
 	}
 	return a, nil
 }
@@ -1922,13 +3539,22 @@ func (a *App) checkLazyLoad() tea.Cmd {
 	if activeTable == nil {
 		return nil
 	}
+	if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Refreshing {
+		// The refresh replaces the rows a page would be appended to
+		return nil
+	}
+	if activeTable.IsQuickFilterActive() {
+		// Scrolling the rows the quick filter shows doesn't load more
+		return nil
+	}
 
 	var cmds []tea.Cmd
 
-	// Check if we need to load more data (lazy loading)
+	// Check if we need to load more data (lazy loading); a window the user
+	// set is all that is loaded
 	if activeTable.SelectedRow >= len(activeTable.Rows)-10 &&
 		len(activeTable.Rows) < activeTable.TotalRows &&
-		!activeTable.IsPaginating {
+		!activeTable.IsPaginating && activeTable.Window == nil {
 
 		schema, table := a.getActiveSchemaTable()
 		if schema != "" && table != "" {
@@ -1938,6 +3564,10 @@ func (a *App) checkLazyLoad() tea.Cmd {
 			if a.resultTabs.HasTabs() {
 				// For tab-based views, use prefetch path (PrefetchCompleteMsg
 				// appends to the active table view without routing issues)
+				var filter *models.Filter
+				if tab := a.resultTabs.GetActiveTab(); tab != nil {
+					filter = tab.Filter
+				}
 				cmds = append(cmds, func() tea.Msg {
 					return messages.PrefetchDataMsg{
 						Schema:     schema,
@@ -1947,6 +3577,7 @@ func (a *App) checkLazyLoad() tea.Cmd {
 						SortColumn: activeTable.GetSortColumn(),
 						SortDir:    activeTable.GetSortDirection(),
 						NullsFirst: activeTable.GetNullsFirst(),
+						Filter:     filter,
 					}
 				})
 			} else {
@@ -1978,7 +3609,7 @@ func (a *App) checkLazyLoad() tea.Cmd {
 }
 
 // PrefetchData prefetches table data in background
-func (a *App) PrefetchData(schema, table string, offset, limit int, sortCol, sortDir string, nullsFirst bool) tea.Cmd {
+func (a *App) PrefetchData(schema, table string, offset, limit int, sortCol, sortDir string, nullsFirst bool, filter *models.Filter) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 
@@ -1996,7 +3627,16 @@ func (a *App) PrefetchData(schema, table string, offset, limit int, sortCol, sor
 			}
 		}
 
-		data, err := metadata.QueryTableData(ctx, conn.Pool, schema, table, offset, limit, sort)
+		var where string
+		var args []interface{}
+		if filter != nil {
+			where, args, err = filterBuilder.NewBuilder().BuildWhere(*filter)
+			if err != nil {
+				return messages.PrefetchCompleteMsg{Err: err}
+			}
+		}
+
+		data, err := metadata.QueryFilteredTableData(ctx, conn.Pool, schema, table, where, args, offset, limit, sort)
 		if err != nil {
 			return messages.PrefetchCompleteMsg{Err: err}
 		}
@@ -2004,6 +3644,7 @@ func (a *App) PrefetchData(schema, table string, offset, limit int, sortCol, sor
 		return messages.PrefetchCompleteMsg{
 			Rows:   data.Rows,
 			Offset: offset,
+			SQL:    data.SQL,
 		}
 	}
 }
@@ -2061,6 +3702,16 @@ func (a *App) getActiveTableView() *components.TableView {
 
 // View implements tea.Model
 func (a *App) View() string {
+	// A size of 0 means no WindowSizeMsg has arrived yet
+	if a.state.Width > 0 && a.state.Height > 0 &&
+		(a.state.Width < minTerminalWidth || a.state.Height < minTerminalHeight) {
+		return a.renderTooSmall()
+	}
+
+	if a.isStarting() {
+		return a.renderStartup()
+	}
+
 	// If error overlay is showing, render it centered on top of everything
 	if a.showError {
 		return lipgloss.Place(
@@ -2070,6 +3721,16 @@ func (a *App) View() string {
 		)
 	}
 
+	// The recovery prompt is shown at startup, before the connection dialog
+	if a.showRecoveryPrompt {
+		a.recoveryPrompt.Width = min(70, a.state.Width-4)
+		return lipgloss.Place(
+			a.state.Width, a.state.Height,
+			lipgloss.Center, lipgloss.Center,
+			a.recoveryPrompt.View(),
+		)
+	}
+
 	// If connection dialog is showing, render it with zone.Scan for mouse support
 	if a.showConnectionDialog {
 		return zone.Scan(a.renderConnectionDialog())
@@ -2089,6 +3750,22 @@ func (a *App) View() string {
 	return zone.Scan(a.renderNormalView())
 }
 
+// renderTooSmall renders the notice shown while the terminal is smaller than
+// the minimum size. The normal view comes back on the next resize that fits.
+func (a *App) renderTooSmall() string {
+	titleStyle := lipgloss.NewStyle().Foreground(a.theme.Warning).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(a.theme.Foreground)
+	metaStyle := lipgloss.NewStyle().Foreground(a.theme.Metadata)
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render("Terminal too small"),
+		textStyle.Render(fmt.Sprintf("need at least %dx%d", minTerminalWidth, minTerminalHeight)),
+		metaStyle.Render(fmt.Sprintf("current %dx%d", a.state.Width, a.state.Height)),
+	)
+	return lipgloss.Place(a.state.Width, a.state.Height, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().MaxWidth(a.state.Width).Render(content))
+}
+
 // renderNormalView renders the normal application view
 func (a *App) renderNormalView() string {
 	// Use cached styles for performance
@@ -2106,11 +3783,23 @@ func (a *App) renderNormalView() string {
 			conn.Config.Database)
 
 		connStatus = "  " + styles.connGreen.Render("") + " " + styles.connText.Render(connStr)
+		if conn.Superuser {
+			connStatus += " " + lipgloss.NewStyle().Foreground(a.theme.Warning).Render(superuserBadge)
+		}
 	} else {
 		connStatus = "  " + styles.connGray.Render("") + " " + styles.connGray.Render("Not connected")
 	}
 
 	topBarLeft := styles.appName.Render("  LazyPG ") + connStatus
+	if a.dryRun {
+		// Dry run changes what edits do, so it stays in plain sight
+		topBarLeft += "  " + lipgloss.NewStyle().
+			Foreground(a.theme.Background).
+			Background(a.theme.Warning).
+			Bold(true).
+			Padding(0, 1).
+			Render(components.DryRunBadge)
+	}
 	topBarRight := styles.topBarHelp.Render("? ") + styles.topBarHelpText.Render("help")
 	topBarContent := a.formatStatusBar(topBarLeft, topBarRight)
 
@@ -2127,6 +3816,7 @@ func (a *App) renderNormalView() string {
 		Render(topBarContent)
 
 	// Context-sensitive bottom bar with cached styles
+	// Indicators shown after the key hints
 	var bottomBarLeft string
 	// Focus area label style
 	focusLabelStyle := lipgloss.NewStyle().
@@ -2135,41 +3825,16 @@ func (a *App) renderNormalView() string {
 		Padding(0, 1).
 		Bold(true)
 
+	var focusLabel string
+	hintContext := components.HintContextData
 	if a.isSQLEditorFocused() {
-		// SQL editor mode
-		focusLabel := focusLabelStyle.Render("SQL")
-		bottomBarLeft = focusLabel + styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("Ctrl+S") + styles.dimStyle.Render(" execute") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("Ctrl+O") + styles.dimStyle.Render(" editor") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("Esc") + styles.dimStyle.Render(" close")
+		focusLabel = focusLabelStyle.Render("SQL")
+		hintContext = components.HintContextEditor
 	} else if a.state.FocusArea == models.FocusTreeView {
-		// Tree navigation keys with icons
-		focusLabel := focusLabelStyle.Render("Tree")
-		bottomBarLeft = focusLabel + styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("↑↓") + styles.dimStyle.Render(" navigate") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("→←") + styles.dimStyle.Render(" expand") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("Enter") + styles.dimStyle.Render(" select") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("/") + styles.dimStyle.Render(" search")
+		focusLabel = focusLabelStyle.Render("Tree")
+		hintContext = components.HintContextTree
 	} else {
-		// Data panel - include SQL editor shortcut
-		focusLabel := focusLabelStyle.Render("Data")
-		bottomBarLeft = focusLabel + styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("↑↓") + styles.dimStyle.Render(" navigate") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("Ctrl+D/U") + styles.dimStyle.Render(" page") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("Ctrl+E") + styles.dimStyle.Render(" sql") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("p") + styles.dimStyle.Render(" preview") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("*") + styles.dimStyle.Render(" pin") +
-			styles.separatorStyle.Render(" │ ") +
-			styles.keyStyle.Render("'") + styles.dimStyle.Render(" goto pin")
+		focusLabel = focusLabelStyle.Render("Data")
 	}
 
 	// Add filter indicator if active
@@ -2181,14 +3846,24 @@ func (a *App) renderNormalView() string {
 		}
 		filterIndicator := styles.separatorStyle.Render(" │ ") +
 			styles.filterStyle.Render("") + styles.dimStyle.Render(fmt.Sprintf(" %d filter%s", filterCount, filterSuffix))
-		bottomBarLeft = bottomBarLeft + filterIndicator
+		bottomBarLeft += filterIndicator
+	}
+
+	// Add LISTEN indicator while the listener is running
+	if a.listener != nil {
+		listenText := fmt.Sprintf(" %d ch", len(a.notificationLog.Channels()))
+		if unread := a.notificationLog.Unread(); unread > 0 {
+			listenText += fmt.Sprintf(", %d new", unread)
+		}
+		bottomBarLeft += styles.separatorStyle.Render(" │ ") +
+			styles.filterStyle.Render("LISTEN") + styles.dimStyle.Render(listenText)
 	}
 
 	// Add Vim motion status if pending
 	if a.state.FocusArea == models.FocusDataPanel && a.currentTab == 0 {
 		vimStatus := a.tableView.GetVimMotionStatus()
 		if vimStatus != "" {
-			bottomBarLeft = bottomBarLeft + styles.separatorStyle.Render(" │ ") + styles.vimStyle.Render(vimStatus)
+			bottomBarLeft += styles.separatorStyle.Render(" │ ") + styles.vimStyle.Render(vimStatus)
 		}
 	}
 
@@ -2209,7 +3884,23 @@ func (a *App) renderNormalView() string {
 		styles.separatorStyle.Render(" │ ") +
 		styles.keyStyle.Render("q") + styles.dimStyle.Render(" quit")
 
-	bottomBarContent := a.formatStatusBar(bottomBarLeft, bottomBarRight)
+	hints := components.StatusHints(hintContext, a.showMoreHints)
+
+	// Show the path of the node under the tree cursor while the tree is focused
+	if a.state.FocusArea == models.FocusTreeView {
+		if node := a.treeView.GetCurrentNode(); node != nil {
+			sep := styles.separatorStyle.Render(" │ ")
+			// Space left after the full hints, both sides, borders/padding (4)
+			// and one separator, so the path never pushes hints out
+			fullHints := components.FitKeyHints(hints, a.state.Width, styles.keyStyle, styles.dimStyle, sep)
+			avail := a.state.Width - 4 - lipgloss.Width(focusLabel) - lipgloss.Width(fullHints) - lipgloss.Width(bottomBarLeft) - lipgloss.Width(bottomBarRight) - lipgloss.Width(sep) - 1
+			if path := formatTreePath(node.GetPath(), avail); path != "" {
+				bottomBarLeft += sep + styles.pathStyle.Render(path)
+			}
+		}
+	}
+
+	bottomBarContent := a.formatBottomBar(focusLabel, hints, bottomBarLeft, bottomBarRight)
 
 	// Create modern bottom bar
 	// Width must account for border: subtract border width (2) to avoid overflow
@@ -2243,12 +3934,15 @@ func (a *App) renderNormalView() string {
 
 	a.rightPanel.Content = a.renderRightPanel(rightContentWidth, rightContentHeight)
 
-	// Panels side by side
+	// Panels side by side, or the SQL editor alone in fullscreen
 	panels := lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		a.leftPanel.View(),
 		a.rightPanel.View(),
 	)
+	if a.sqlEditor.IsFullscreen() && a.isSQLEditorFocused() {
+		panels = a.renderFullscreenEditor()
+	}
 
 	// Combine all
 	mainView := lipgloss.JoinVertical(
@@ -2331,6 +4025,222 @@ func (a *App) renderNormalView() string {
 		)
 	}
 
+	// Render CSV import dialog if visible
+	if a.showCSVImport {
+		a.csvImportDialog.Width = 90
+		if a.csvImportDialog.Width > a.state.Width-4 {
+			a.csvImportDialog.Width = a.state.Width - 4
+		}
+		a.csvImportDialog.Height = a.state.Height - 4
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.csvImportDialog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render query builder if visible
+	if a.showQueryBuilder {
+		a.queryBuilder.Width = 90
+		if a.queryBuilder.Width > a.state.Width-4 {
+			a.queryBuilder.Width = a.state.Width - 4
+		}
+		a.queryBuilder.Height = a.state.Height - 4
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.queryBuilder.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render notification log if visible
+	if a.showNotifications {
+		a.notificationLog.Width = 100
+		if a.notificationLog.Width > a.state.Width-4 {
+			a.notificationLog.Width = a.state.Width - 4
+		}
+		a.notificationLog.Height = a.state.Height - 4
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.notificationLog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render error log if visible
+	if a.showErrorLog {
+		a.errorLog.Width = min(100, a.state.Width-4)
+		a.errorLog.Height = a.state.Height - 4
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.errorLog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render locks monitor if visible
+	if a.showLocks {
+		a.locksMonitor.Width = 110
+		if a.locksMonitor.Width > a.state.Width-4 {
+			a.locksMonitor.Width = a.state.Width - 4
+		}
+		a.locksMonitor.Height = a.state.Height - 4
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.locksMonitor.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render server info panel if visible
+	if a.showServerInfo {
+		a.serverInfoPanel.Width = min(70, a.state.Width-4)
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.serverInfoPanel.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render foreign servers panel if visible
+	if a.showForeignServers {
+		a.foreignServers.Width = min(90, a.state.Width-4)
+		a.foreignServers.Height = a.state.Height - 4
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.foreignServers.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render row color rule editor if visible
+	if a.showRowColors {
+		a.rowColorsDialog.Width = min(70, a.state.Width-4)
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.rowColorsDialog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render row edit form if visible
+	if a.showRowEdit {
+		a.rowEditForm.Width = min(90, a.state.Width-4)
+		a.rowEditForm.Height = a.state.Height - 4
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.rowEditForm.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render LIMIT/OFFSET editor if visible
+	if a.showRowWindow {
+		a.rowWindowDialog.Width = min(50, a.state.Width-4)
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.rowWindowDialog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render safe mode prompt if visible
+	if a.showSafeModePrompt {
+		a.safeModePrompt.Width = min(70, a.state.Width-4)
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.safeModePrompt.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render session lost prompt if visible
+	if a.showSessionLost {
+		a.sessionLostPrompt.Width = min(70, a.state.Width-4)
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.sessionLostPrompt.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render truncate dialog if visible
+	if a.showTruncate {
+		a.truncateDialog.Width = min(64, a.state.Width-4)
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.truncateDialog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
+	// Render dry run dialog if visible
+	if a.showDryRun {
+		a.dryRunDialog.Width = min(80, a.state.Width-4)
+		a.dryRunDialog.Height = a.state.Height
+		mainView = lipgloss.Place(
+			a.state.Width,
+			a.state.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.dryRunDialog.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#555555")),
+		)
+	}
+
 	// Render command palette if visible (as overlay on top of mainView)
 	if a.showCommandPalette {
 		a.commandPalette.Width = 80
@@ -2351,6 +4261,16 @@ func (a *App) renderNormalView() string {
 		mainView = a.overlaySearchInput(mainView)
 	}
 
+	// Render the tree context menu where it was opened
+	if a.showContextMenu {
+		mainView = a.overlayContextMenu(mainView)
+	}
+
+	// Toasts sit on top of everything but never take focus
+	if a.toasts.Len() > 0 {
+		mainView = a.overlayToasts(mainView)
+	}
+
 	return mainView
 }
 
@@ -2396,6 +4316,40 @@ func (a *App) renderRightPanel(width, height int) string {
 	return lipgloss.JoinVertical(lipgloss.Left, dataPanel, sqlEditorView)
 }
 
+// renderFullscreenEditor renders the SQL editor over the whole content
+// area, in a panel as wide as both panels together
+func (a *App) renderFullscreenEditor() string {
+	panel := a.rightPanel
+	panel.Width = a.leftPanel.Width + a.rightPanel.Width + 2 // The left panel's borders
+	a.sqlEditor.Width = panel.Width - 2                     // Horizontal padding inside the panel
+	a.sqlEditor.Height = max(panel.Height-2, 5)
+	panel.Content = a.sqlEditor.View()
+	return panel.View()
+}
+
+// cycleEditorHeight steps the SQL editor to its next height preset,
+// expanding it so the change shows
+func (a *App) cycleEditorHeight() tea.Cmd {
+	a.sqlEditor.ExitFullscreen()
+	a.sqlEditor.CycleHeight()
+	if !a.sqlEditor.IsExpanded() {
+		a.sqlEditor.Expand()
+		a.state.FocusArea = models.FocusSQLEditor
+		a.updatePanelStyles()
+	}
+	return a.ShowToast("SQL editor height: " + a.sqlEditor.HeightPresetLabel())
+}
+
+// toggleEditorFullscreen enters fullscreen editing, focusing the editor,
+// or leaves it
+func (a *App) toggleEditorFullscreen() {
+	a.sqlEditor.ToggleFullscreen()
+	if a.sqlEditor.IsFullscreen() {
+		a.state.FocusArea = models.FocusSQLEditor
+		a.updatePanelStyles()
+	}
+}
+
 // renderDataPanel renders the data panel (table view or structure view)
 func (a *App) renderDataPanel(width, height int) string {
 	// Show loading spinner when loading object details (function, sequence, etc.)
@@ -2413,143 +4367,19 @@ func (a *App) renderDataPanel(width, height int) string {
 		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content)
 	}
 
-	// If we have result tabs, show the active tab's content
-	if a.resultTabs.HasTabs() {
-		activeTab := a.resultTabs.GetActiveTab()
-		if activeTab != nil {
-			// If active tab is pending, show spinner
-			if activeTab.IsPending {
-				elapsed := a.resultTabs.GetPendingElapsed()
-				elapsedStr := fmt.Sprintf("%.1fs", elapsed.Seconds())
-
-				spinnerView := a.executeSpinner.View()
-				statusText := lipgloss.NewStyle().
-					Foreground(a.theme.Foreground).
-					Render(fmt.Sprintf("Executing query... (%s)", elapsedStr))
-
-				cancelHint := lipgloss.NewStyle().
-					Foreground(a.theme.Border).
-					Render("Press Esc to cancel")
-
-				content := lipgloss.JoinVertical(lipgloss.Center,
-					"",
-					spinnerView+" "+statusText,
-					"",
-					cancelHint,
-				)
-
-				return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content)
-			}
-
-			// If active tab was cancelled, show cancelled message
-			if activeTab.IsCancelled {
-				cancelledText := lipgloss.NewStyle().
-					Foreground(a.theme.Warning).
-					Bold(true).
-					Render("Query Cancelled")
-
-				hintText := lipgloss.NewStyle().
-					Foreground(a.theme.Border).
-					Render("Press Ctrl+E to edit and re-execute")
-
-				content := lipgloss.JoinVertical(lipgloss.Center,
-					"",
-					cancelledText,
-					"",
-					hintText,
-				)
-
-				return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content)
-			}
-
-			// Render based on tab type
-			switch activeTab.Type {
-			case components.TabTypeQueryResult:
-				// Show query result table view
-				activeTable := a.resultTabs.GetActiveTableView()
-				if activeTable != nil {
-					activeTable.Width = width
-					activeTable.Height = height - 1
-					// Add empty line placeholder to align with TableData mode
-					return "\n" + activeTable.View()
-				}
-
-			case components.TabTypeTableData:
-				// Show table data with structure view
-				if activeTab.Structure != nil {
-					// Calculate preview pane height (only if visible)
-					structureView := activeTab.Structure
-					activeTable := structureView.GetActiveTableView()
-					previewHeight := 0
-					if activeTable != nil && activeTable.PreviewPane != nil && activeTable.PreviewPane.Visible {
-						maxPreviewHeight := height / 3
-						if maxPreviewHeight < 5 {
-							maxPreviewHeight = 5
-						}
-						activeTable.SetPreviewPaneDimensions(width, maxPreviewHeight)
-						previewHeight = activeTable.GetPreviewPaneHeight()
-					}
-
-					// Calculate main content height
-					mainHeight := height - previewHeight
-					if mainHeight < 5 {
-						mainHeight = 5
-					}
-
-					// Update structure view dimensions
-					structureView.Width = width
-					structureView.Height = mainHeight
-
-					// Render main content
-					mainContent := structureView.View()
-
-					// If preview pane is visible, append it
-					if activeTable != nil && previewHeight > 0 {
-						previewContent := activeTable.PreviewPane.View()
-						return lipgloss.JoinVertical(lipgloss.Left, mainContent, previewContent)
-					}
-
-					return mainContent
-				}
-
-			case components.TabTypeCodeEditor:
-				// Show code editor
-				if activeTab.CodeEditor != nil {
-					activeTab.CodeEditor.Width = width
-					activeTab.CodeEditor.Height = height - 1
-					// Add empty line placeholder to align with TableData mode
-					return "\n" + activeTab.CodeEditor.View()
-				}
-			}
+	// If we have result tabs, show the active tab's content, or two tabs
+	// in split view
+	if a.resultTabs.IsSplit() {
+		return a.renderSplitView(width, height)
+	}
+	if tab := a.resultTabs.GetActiveTab(); tab != nil {
+		if content, ok := a.renderTab(tab, width, height); ok {
+			return content
 		}
 	}
 
 	// Legacy: If table is selected in tree (without tabs), show structure view
 	if a.currentTable != "" {
-		// Calculate preview pane height (only if visible)
-		// Get active table view for preview pane handling
-		activeTable := a.structureView.GetActiveTableView()
-		previewHeight := 0
-		if activeTable != nil && activeTable.PreviewPane != nil && activeTable.PreviewPane.Visible {
-			// Set preview pane dimensions (max 1/3 of available height)
-			maxPreviewHeight := height / 3
-			if maxPreviewHeight < 5 {
-				maxPreviewHeight = 5
-			}
-			activeTable.SetPreviewPaneDimensions(width, maxPreviewHeight)
-			previewHeight = activeTable.GetPreviewPaneHeight()
-		}
-
-		// Calculate main content height (subtract preview pane height)
-		mainHeight := height - previewHeight
-		if mainHeight < 5 {
-			mainHeight = 5
-		}
-
-		// Update structure view dimensions
-		a.structureView.Width = width
-		a.structureView.Height = mainHeight
-
 		// Load table structure if needed (when table changes)
 		conn, err := a.connectionManager.GetActive()
 		if err == nil && conn != nil && conn.Pool != nil {
@@ -2566,33 +4396,195 @@ func (a *App) renderDataPanel(width, height int) string {
 			}
 		}
 
-		// Render main content
-		mainContent := a.structureView.View()
+		return a.renderWithPreview(a.structureView.GetActiveTableView(), width, height, func(w, h int) string {
+			a.structureView.Width = w
+			a.structureView.Height = h
+			return a.structureView.View()
+		})
+	}
+
+	// Legacy: If we have code editor to display (function source, sequence info, etc.)
+	if a.showCodeEditor && a.codeEditor != nil {
+		a.codeEditor.Width = width
+		a.codeEditor.Height = height
+		return a.codeEditor.View()
+	}
+
+	// No data - show placeholder
+	placeholderStyle := lipgloss.NewStyle().
+		Foreground(a.theme.Comment).
+		Width(width).
+		Height(height).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return placeholderStyle.Render("No data to display\n\nPress Ctrl+E to open SQL editor")
+}
+
+// renderTab renders the content of a result tab. It reports false if the
+// tab has nothing to show yet.
+func (a *App) renderTab(tab *components.ResultTab, width, height int) (string, bool) {
+	// If the tab is pending, show spinner
+	if tab.IsPending {
+		elapsed := a.resultTabs.GetPendingElapsed()
+		elapsedStr := fmt.Sprintf("%.1fs", elapsed.Seconds())
+
+		spinnerView := a.executeSpinner.View()
+		statusText := lipgloss.NewStyle().
+			Foreground(a.theme.Foreground).
+			Render(fmt.Sprintf("Executing query... (%s)", elapsedStr))
+
+		cancelHint := lipgloss.NewStyle().
+			Foreground(a.theme.Border).
+			Render("Press Esc to cancel")
+
+		content := lipgloss.JoinVertical(lipgloss.Center,
+			"",
+			spinnerView+" "+statusText,
+			"",
+			cancelHint,
+		)
+
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content), true
+	}
+
+	// If the tab was cancelled, show cancelled message
+	if tab.IsCancelled {
+		cancelledText := lipgloss.NewStyle().
+			Foreground(a.theme.Warning).
+			Bold(true).
+			Render("Query Cancelled")
+
+		hintText := lipgloss.NewStyle().
+			Foreground(a.theme.Border).
+			Render("Press Ctrl+E to edit and re-execute")
+
+		content := lipgloss.JoinVertical(lipgloss.Center,
+			"",
+			cancelledText,
+			"",
+			hintText,
+		)
+
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content), true
+	}
+
+	// Render based on tab type
+	switch tab.Type {
+	case components.TabTypeQueryResult:
+		// Show query result table view
+		if table := tab.TableView; table != nil {
+			// The messages line takes the place of the empty line
+			// that aligns with TableData mode
+			header := ""
+			if components.HasQueryMessages(tab.Result) {
+				header = components.RenderQueryMessages(tab.Result, tab.ShowMessages, width, height/2, a.theme)
+			}
+			if components.IsCommandResult(tab.Result) {
+				// Nothing for a grid to show; say it worked instead
+				return header + "\n" + components.RenderCommandResult(tab.Result, width, height-1-lipgloss.Height(header), a.theme), true
+			}
+			content := a.renderWithPreview(table, width, height-lipgloss.Height(header), func(w, h int) string {
+				table.Width = w
+				table.Height = h
+				return table.View()
+			})
+			return header + "\n" + content, true
+		}
+
+	case components.TabTypeTableData:
+		// Show table data with structure view
+		if tab.Structure != nil {
+			structureView := tab.Structure
+			return a.renderWithPreview(structureView.GetActiveTableView(), width, height, func(w, h int) string {
+				structureView.Width = w
+				structureView.Height = h
+				return structureView.View()
+			}), true
+		}
 
-		// If preview pane is visible, append it
-		if activeTable != nil && previewHeight > 0 {
-			previewContent := activeTable.PreviewPane.View()
-			return lipgloss.JoinVertical(lipgloss.Left, mainContent, previewContent)
+	case components.TabTypeCodeEditor:
+		// Show code editor
+		if tab.CodeEditor != nil {
+			tab.CodeEditor.Width = width
+			tab.CodeEditor.Height = height - 1
+			// Add empty line placeholder to align with TableData mode
+			return "\n" + tab.CodeEditor.View(), true
 		}
+	}
 
-		return mainContent
+	return "", false
+}
+
+// renderSplitView shows the active tab and the tab of the other pane side
+// by side, each with a title line; the focused pane's title is highlighted.
+// A data panel too narrow for two useful grids stacks them instead.
+func (a *App) renderSplitView(width, height int) string {
+	first, second, firstFocused := a.resultTabs.SplitPanes()
+
+	if width >= 2*components.MinSplitPaneWidth+1 {
+		firstWidth := (width - 1) / 2
+		secondWidth := width - 1 - firstWidth
+		divider := lipgloss.NewStyle().
+			Foreground(a.theme.Border).
+			Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+		return lipgloss.JoinHorizontal(lipgloss.Top,
+			a.renderSplitPane(first, firstFocused, firstWidth, height),
+			divider,
+			a.renderSplitPane(second, !firstFocused, secondWidth, height),
+		)
 	}
 
-	// Legacy: If we have code editor to display (function source, sequence info, etc.)
-	if a.showCodeEditor && a.codeEditor != nil {
-		a.codeEditor.Width = width
-		a.codeEditor.Height = height
-		return a.codeEditor.View()
+	firstHeight := height / 2
+	return lipgloss.JoinVertical(lipgloss.Left,
+		a.renderSplitPane(first, firstFocused, width, firstHeight),
+		a.renderSplitPane(second, !firstFocused, width, height-firstHeight),
+	)
+}
+
+// renderSplitPane renders one pane of the split view. Keys and clicks go
+// to the focused pane, so the other pane's click zones are dropped to keep
+// them from shadowing the focused pane's cells.
+func (a *App) renderSplitPane(tab *components.ResultTab, focused bool, width, height int) string {
+	title := a.resultTabs.RenderPaneTitle(tab, focused, width)
+	bodyHeight := max(height-1, 1)
+	content, _ := a.renderTab(tab, width, bodyHeight)
+	if !focused {
+		content = components.StripZones(content)
+	}
+	body := lipgloss.NewStyle().
+		Width(width).MaxWidth(width).
+		Height(bodyHeight).MaxHeight(bodyHeight).
+		Render(content)
+	return title + "\n" + body
+}
+
+// renderWithPreview renders the data panel content with the active table's
+// preview pane docked at the bottom or right. render draws the main content
+// in the space the pane leaves free.
+func (a *App) renderWithPreview(activeTable *components.TableView, width, height int, render func(w, h int) string) string {
+	if activeTable != nil {
+		activeTable.ShowGeneratedSQL = a.showGeneratedSQL
+		activeTable.TruncationWarnings = a.truncationWarnings
+	}
+	if activeTable == nil || activeTable.PreviewPane == nil || !activeTable.PreviewPane.Visible {
+		return render(width, height)
 	}
 
-	// No data - show placeholder
-	placeholderStyle := lipgloss.NewStyle().
-		Foreground(a.theme.Comment).
-		Width(width).
-		Height(height).
-		Align(lipgloss.Center, lipgloss.Center)
+	pane := activeTable.PreviewPane
+	if pane.Position != a.previewPosition {
+		pane.ToggleDock()
+	}
+	pane.Size = a.previewSize
+	mainWidth, mainHeight := pane.Layout(width, height)
 
-	return placeholderStyle.Render("No data to display\n\nPress Ctrl+E to open SQL editor")
+	mainContent := render(mainWidth, mainHeight)
+	if pane.Height() == 0 {
+		return mainContent
+	}
+	if pane.Position == components.PreviewRight {
+		return lipgloss.JoinHorizontal(lipgloss.Top, mainContent, pane.View())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, mainContent, pane.View())
 }
 
 // updatePanelDimensions calculates panel sizes based on window size
@@ -2634,6 +4626,11 @@ func (a *App) updatePanelDimensions() {
 
 // updatePanelStyles updates panel styling based on focus with Catppuccin colors
 func (a *App) updatePanelStyles() {
+	// Fullscreen editing ends once the editor loses focus
+	if a.state.FocusArea != models.FocusSQLEditor {
+		a.sqlEditor.ExitFullscreen()
+	}
+
 	// Update legacy FocusedPanel for compatibility
 	if a.state.FocusArea == models.FocusTreeView {
 		a.state.FocusedPanel = models.LeftPanel
@@ -2844,6 +4841,11 @@ func (a *App) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		return a, nil
 	}
 
+	if a.showContextMenu {
+		// Clicking outside the menu closes it
+		return a, a.contextMenu.HandleMouse(msg)
+	}
+
 	// Handle structure view tabs (for result tabs or legacy structure view)
 	if activeTab := a.resultTabs.GetActiveTab(); activeTab != nil && activeTab.Type == components.TabTypeTableData && activeTab.Structure != nil {
 		handled, tabIndex := activeTab.Structure.HandleMouseClick(msg)
@@ -2972,10 +4974,11 @@ func (a *App) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		}
 
 		// Check result tabs first
-		for i := 0; i < components.MaxResultTabs; i++ {
+		for i := 0; i < a.resultTabs.TabCount(); i++ {
 			zoneID := fmt.Sprintf("%s%d", components.ZoneResultTabPrefix, i)
 			if zone.Get(zoneID).InBounds(msg) {
 				a.resultTabs.SetActiveTab(i)
+				a.syncActiveFilter()
 				// Sync SQL editor content with new active tab
 				if activeSQL := a.resultTabs.GetActiveSQL(); activeSQL != "" {
 					a.sqlEditor.SetContent(activeSQL)
@@ -2984,6 +4987,17 @@ func (a *App) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// The title of the split view pane without focus focuses it
+		if zone.Get(components.ZoneSplitPane).InBounds(msg) && a.resultTabs.SwitchSplitFocus() {
+			a.state.FocusArea = models.FocusDataPanel
+			a.updatePanelStyles()
+			a.syncActiveFilter()
+			if activeSQL := a.resultTabs.GetActiveSQL(); activeSQL != "" {
+				a.sqlEditor.SetContent(activeSQL)
+			}
+			return a, nil
+		}
+
 		// Check tree view rows
 		for i := 0; i < 100; i++ {
 			zoneID := fmt.Sprintf("%s%d", components.ZoneTreeRowPrefix, i)
@@ -3011,19 +5025,9 @@ func (a *App) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 						if activeTable.SelectedRow == actualRow && activeTable.SelectedCol == actualCol {
 							// Double-click behavior: open JSONB viewer or preview pane
 							if actualRow >= 0 && actualRow < len(activeTable.Rows) && actualCol >= 0 && actualCol < len(activeTable.Columns) {
-								cellValue := activeTable.Rows[actualRow][actualCol]
 								// Check if it's JSON/JSONB data
-								if jsonb.IsJSONB(cellValue) {
-									// Set viewer dimensions based on terminal size (with max width limit)
-									viewerWidth := a.state.Width * 2 / 3
-									if viewerWidth > 100 {
-										viewerWidth = 100
-									}
-									a.jsonbViewer.Width = viewerWidth
-									a.jsonbViewer.Height = a.state.Height * 3 / 4
-									if err := a.jsonbViewer.SetValue(cellValue); err == nil {
-										a.showJSONBViewer = true
-									}
+								if value, ok := activeTable.SelectedJSON(); ok {
+									a.openJSONBViewer(value)
 								} else {
 									// For non-JSONB, toggle preview pane
 									activeTable.TogglePreviewPane()
@@ -3033,6 +5037,9 @@ func (a *App) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 							// First click: just select the cell
 							activeTable.SetSelectedRow(actualRow)
 							activeTable.SelectedCol = actualCol
+							if activeTable.PreviewOnClick && actualRow < len(activeTable.Rows) {
+								activeTable.ShowPreviewPane()
+							}
 						}
 					}
 					return a, nil
@@ -3066,6 +5073,25 @@ func (a *App) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		}
 
 		return a, nil
+
+	case tea.MouseButtonRight:
+		if msg.Action != tea.MouseActionPress {
+			return a, nil
+		}
+
+		// Open the context menu of the tree node under the cursor
+		for i := 0; i < 100; i++ {
+			zoneID := fmt.Sprintf("%s%d", components.ZoneTreeRowPrefix, i)
+			if zone.Get(zoneID).InBounds(msg) {
+				a.state.FocusArea = models.FocusTreeView
+				a.updatePanelStyles()
+				if node := a.treeView.NodeAtRow(i); node != nil {
+					a.showContextMenu = a.contextMenu.Open(node, msg.X, msg.Y)
+				}
+				return a, nil
+			}
+		}
+		return a, nil
 	}
 
 	return a, nil
@@ -3160,17 +5186,107 @@ func (a *App) connectToHistoryEntry(entry models.ConnectionHistoryEntry) (tea.Mo
 
 // connectToDiscoveredInstance connects using a discovered instance
 func (a *App) connectToDiscoveredInstance(instance models.DiscoveredInstance) (tea.Model, tea.Cmd) {
-	// Create connection config from discovered instance
-	config := models.ConnectionConfig{
+	config := a.discoveredInstanceConfig(instance)
+
+	// Remember the attempt so a failure can prompt for what is missing
+	a.discoveredAttempt = &config
+	return a.performConnection(config)
+}
+
+// discoveredInstanceConfig builds the connection config for a discovered instance.
+// A configured per-host override wins, then the database and user last used
+// successfully on that host, then the configured discovery defaults.
+func (a *App) discoveredInstanceConfig(instance models.DiscoveredInstance) models.ConnectionConfig {
+	defaults := config.GetDefaults().Connection.Discovery
+	if a.config != nil {
+		defaults = a.config.Connection.Discovery
+	}
+
+	cfg := models.ConnectionConfig{
 		Host:     instance.Host,
 		Port:     instance.Port,
-		Database: "postgres",        // Default database
-		User:     os.Getenv("USER"), // Current user
-		Password: "",                // No password for now
-		SSLMode:  "prefer",
+		Database: defaults.Database,
+		User:     defaults.User,
+		SSLMode:  defaults.SSLMode,
+	}
+	if cfg.Database == "" {
+		cfg.Database = "postgres"
+	}
+	if cfg.User == "" {
+		cfg.User = os.Getenv("USER")
+	}
+	if cfg.SSLMode == "" {
+		cfg.SSLMode = "prefer"
 	}
 
-	return a.performConnection(config)
+	for _, override := range defaults.Hosts {
+		if override.Host != instance.Host || (override.Port != 0 && override.Port != instance.Port) {
+			continue
+		}
+		if override.Database != "" {
+			cfg.Database = override.Database
+		}
+		if override.User != "" {
+			cfg.User = override.User
+		}
+		if override.SSLMode != "" {
+			cfg.SSLMode = override.SSLMode
+		}
+		return cfg
+	}
+
+	if a.connectionHistory != nil {
+		for _, entry := range a.connectionHistory.GetRecent(0) {
+			if entry.Host != instance.Host || entry.Port != instance.Port {
+				continue
+			}
+			// A missing password is fine here: trust auth needs none, and
+			// a password error falls back to the password dialog
+			remembered := a.connectionHistory.GetConnectionConfigWithPassword(&entry).Config
+			if remembered.SSLMode == "" {
+				remembered.SSLMode = cfg.SSLMode
+			}
+			return remembered
+		}
+	}
+
+	return cfg
+}
+
+// recoverDiscoveredConnection handles a failed connection to a discovered
+// instance by prompting for a password, or for a database and user when the
+// defaults do not exist. Returns false if the failure was not recoverable.
+func (a *App) recoverDiscoveredConnection(cfg models.ConnectionConfig, err error) (bool, tea.Cmd) {
+	attempt := a.discoveredAttempt
+	a.discoveredAttempt = nil
+	if attempt == nil || attempt.Host != cfg.Host || attempt.Port != cfg.Port {
+		return false, nil
+	}
+
+	switch {
+	case connection.IsPasswordError(err) && cfg.Password == "":
+		a.pendingConnectionInfo = &models.ConnectionHistoryEntry{
+			Host:     cfg.Host,
+			Port:     cfg.Port,
+			Database: cfg.Database,
+			User:     cfg.User,
+			SSLMode:  cfg.SSLMode,
+		}
+		a.passwordDialog.SetConnectionInfo(cfg.Host, cfg.Port, cfg.Database, cfg.User)
+		a.showPasswordDialog = true
+		a.showConnectionDialog = false
+		return true, a.passwordDialog.Init()
+
+	case connection.IsCredentialError(err):
+		a.connectionDialog.PrefillManual(cfg)
+		a.showConnectionDialog = true
+		a.ShowError("Connection Failed", fmt.Sprintf(
+			"Could not connect to %s:%d as %q (database %q)\n\nError: %v\n\nEnter a database and user for this instance.",
+			cfg.Host, cfg.Port, cfg.User, cfg.Database, err))
+		return true, nil
+	}
+
+	return false, nil
 }
 
 // performConnection starts an async connection attempt
@@ -3182,17 +5298,300 @@ func (a *App) performConnection(config models.ConnectionConfig) (tea.Model, tea.
 
 // connectAsync performs the actual connection in a goroutine
 func (a *App) connectAsync(config models.ConnectionConfig) tea.Cmd {
+	a.connectAttempt++
+	a.connectRetryAt = time.Time{}
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		connID, err := a.connectionManager.Connect(ctx, config)
-		return messages.ConnectionResultMsg{
+		connID, err := a.connectionManager.Connect(ctx, a.withOnConnectSQL(a.withApplicationName(config)))
+		result := messages.ConnectionResultMsg{
 			Config: config,
 			ConnID: connID,
 			Err:    err,
 		}
+		if err == nil {
+			if conn, activeErr := a.connectionManager.GetActive(); activeErr == nil {
+				result.OnConnectErr = conn.Pool.OnConnectError()
+				result.Superuser = checkSuperuser(conn.Pool)
+			}
+		}
+		return result
+	}
+}
+
+// checkSuperuser reports whether pool's user is a superuser. It is only a
+// warning, so a check that fails or times out counts as not a superuser.
+func checkSuperuser(pool *connection.Pool) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	superuser, err := metadata.IsSuperuser(ctx, pool)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return superuser
+}
+
+// maxConnectAttempts returns how many times a connection is tried when it
+// fails with a transient error
+func (a *App) maxConnectAttempts() int {
+	if a.config == nil || a.config.Connection.ConnectAttempts < 1 {
+		return 1
+	}
+	return a.config.Connection.ConnectAttempts
+}
+
+// retryConnection schedules another attempt after a failed one, backing off
+// exponentially. It returns nil when the error is not transient or the
+// attempts are used up. Cancelling (Esc) ends the connecting state, which
+// makes the pending ConnectionRetryMsg a no-op.
+func (a *App) retryConnection(config models.ConnectionConfig, err error) tea.Cmd {
+	if !connection.IsRetryable(err) || a.connectAttempt >= a.maxConnectAttempts() {
+		return nil
+	}
+	delay := connection.RetryDelay(a.connectAttempt)
+	a.connectRetryErr = err
+	a.connectRetryAt = time.Now().Add(delay)
+	start := a.connectingStart
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return messages.ConnectionRetryMsg{Config: config, Start: start}
+	})
+}
+
+// listenChannel runs LISTEN (or UNLISTEN) on the dedicated listener
+// connection, opening it on the first LISTEN
+func (a *App) listenChannel(channel string, unlisten bool) tea.Cmd {
+	if a.state.ActiveConnection == nil {
+		a.notificationLog.AddStatus("Not connected", true)
+		return nil
+	}
+	listener := a.listener
+	if listener == nil && unlisten {
+		a.notificationLog.AddStatus("Not listening on any channel", true)
+		return nil
+	}
+	config := a.withApplicationName(a.state.ActiveConnection.Config)
+	connID := a.state.ActiveConnection.ID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var started *connection.Listener
+		if listener == nil {
+			l, err := connection.NewListener(ctx, config)
+			if err != nil {
+				return messages.ListenResultMsg{ConnID: connID, Channel: channel, Err: err}
+			}
+			listener, started = l, l
+		}
+
+		var err error
+		if unlisten {
+			err = listener.Unlisten(ctx, channel)
+		} else {
+			err = listener.Listen(ctx, channel)
+		}
+		return messages.ListenResultMsg{
+			Listener: started,
+			ConnID:   connID,
+			Channel:  channel,
+			Unlisten: unlisten,
+			Err:      err,
+		}
+	}
+}
+
+// handleListenResult records the outcome of LISTEN/UNLISTEN and starts
+// receiving notifications from a newly opened listener
+func (a *App) handleListenResult(msg messages.ListenResultMsg) tea.Cmd {
+	var cmd tea.Cmd
+	if msg.Listener != nil {
+		// The connection changed, or another LISTEN opened a listener first
+		if a.listener != nil || a.state.ActiveConnection == nil || a.state.ActiveConnection.ID != msg.ConnID {
+			go msg.Listener.Close()
+			if a.listener != nil && msg.Err == nil {
+				return a.listenChannel(msg.Channel, false)
+			}
+			return nil
+		}
+		a.listener = msg.Listener
+		cmd = waitForNotification(msg.Listener)
 	}
+
+	verb := "LISTEN"
+	if msg.Unlisten {
+		verb = "UNLISTEN"
+	}
+	if msg.Err != nil {
+		a.notificationLog.AddStatus(fmt.Sprintf("%s %s failed: %v", verb, msg.Channel, msg.Err), true)
+	} else {
+		a.notificationLog.AddStatus(fmt.Sprintf("%s %s", verb, msg.Channel), false)
+	}
+
+	if a.listener != nil {
+		a.notificationLog.SetChannels(a.listener.Channels())
+	}
+	return cmd
+}
+
+// waitForNotification returns a command that delivers the listener's next
+// notification, or reports that it has stopped
+func waitForNotification(listener *connection.Listener) tea.Cmd {
+	return func() tea.Msg {
+		n, ok := <-listener.Notifications()
+		if !ok {
+			return messages.ListenerClosedMsg{Listener: listener, Err: listener.Err()}
+		}
+		return messages.NotificationReceivedMsg{Listener: listener, Notification: n}
+	}
+}
+
+// sendNotify sends a notification on the active connection's query pool
+func (a *App) sendNotify(channel, payload string) tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.NotifySentMsg{Channel: channel, Err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err = conn.Pool.Execute(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+		return messages.NotifySentMsg{Channel: channel, Err: err}
+	}
+}
+
+// refreshBlockingLocks starts a new locks monitor refresh chain, which
+// stops the previous one so manual refreshes do not stack timers
+func (a *App) refreshBlockingLocks() tea.Cmd {
+	a.locksTick++
+	return a.loadBlockingLocks(a.locksTick)
+}
+
+// loadBlockingLocks loads the blocker/blocked pairs for refresh chain tick
+func (a *App) loadBlockingLocks(tick int) tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.BlockingLocksLoadedMsg{Tick: tick, Err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		locks, err := metadata.GetBlockingLocks(ctx, conn.Pool)
+		return messages.BlockingLocksLoadedMsg{Tick: tick, Locks: locks, Err: err}
+	}
+}
+
+// loadServerInfo loads the server info of the active connection
+func (a *App) loadServerInfo() tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.ServerInfoLoadedMsg{Err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		info, err := metadata.GetServerInfo(ctx, conn.Pool)
+		return messages.ServerInfoLoadedMsg{Info: info, Err: err}
+	}
+}
+
+// loadForeignServers loads the foreign servers of the active database
+func (a *App) loadForeignServers() tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.ForeignServersLoadedMsg{Err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		servers, err := metadata.ListForeignServers(ctx, conn.Pool)
+		return messages.ForeignServersLoadedMsg{Servers: servers, Err: err}
+	}
+}
+
+// endPendingTx commits or rolls back the statement safe mode is holding.
+// Either way the transaction's connection goes back to the pool.
+func (a *App) endPendingTx(commit bool) tea.Cmd {
+	tx := a.pendingTx
+	a.pendingTx = nil
+	if tx == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		var err error
+		if commit {
+			err = tx.Commit(ctx)
+		} else {
+			err = tx.Rollback(ctx)
+		}
+		return messages.SafeModeFinishedMsg{Commit: commit, Err: err}
+	}
+}
+
+// openDryRun shows sql, which dry run kept from doing action
+func (a *App) openDryRun(action, sql string) {
+	a.dryRunDialog.Open(action, sql)
+	a.showDryRun = true
+}
+
+// signalBackend cancels the query of, or terminates, a backend on the
+// active connection
+func (a *App) signalBackend(pid int, terminate bool) tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.BackendSignaledMsg{PID: pid, Terminate: terminate, Err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		ok, err := metadata.SignalBackend(ctx, conn.Pool, pid, terminate)
+		return messages.BackendSignaledMsg{PID: pid, Terminate: terminate, OK: ok, Err: err}
+	}
+}
+
+// closeListener unlistens and closes the listener connection in the background
+func (a *App) closeListener(reason string) {
+	if a.listener == nil {
+		return
+	}
+	listener := a.listener
+	a.listener = nil
+	go listener.Close()
+	a.notificationLog.SetChannels(nil)
+	a.notificationLog.AddStatus("Stopped listening ("+reason+")", false)
+}
+
+// Close releases background resources once the program exits
+func (a *App) Close() {
+	if a.listener != nil {
+		a.listener.Close()
+		a.listener = nil
+	}
+}
+
+// withApplicationName sets the configured application_name on a connection config
+func (a *App) withApplicationName(config models.ConnectionConfig) models.ConnectionConfig {
+	if a.config == nil || config.ApplicationName != "" {
+		return config
+	}
+	name := a.config.Connection.ApplicationName
+	if name != "" && a.config.Connection.IncludeConnectionName && config.Name != "" {
+		name = fmt.Sprintf("%s (%s)", name, config.Name)
+	}
+	config.ApplicationName = name
+	return config
+}
+
+// withOnConnectSQL sets the configured on-connect SQL on a connection config
+func (a *App) withOnConnectSQL(config models.ConnectionConfig) models.ConnectionConfig {
+	if a.config == nil || config.OnConnectSQL != "" {
+		return config
+	}
+	config.OnConnectSQL = a.config.Connection.OnConnectSQL
+	return config
 }
 
 // handleTabClick handles clicking on result tabs
@@ -3213,13 +5612,12 @@ func (a *App) formatStatusBar(left, right string) string {
 	leftLen := lipgloss.Width(left)
 	rightLen := lipgloss.Width(right)
 
-	// If content is too wide, truncate
+	// If content is too wide, drop the right side, then cut the left
 	if leftLen+rightLen > availableWidth {
-		if availableWidth > rightLen {
-			// Simple truncation - in a real app you'd want smarter truncation
-			return left + right
+		if leftLen <= availableWidth {
+			return left
 		}
-		return left
+		return ansi.Truncate(left, availableWidth, "…")
 	}
 
 	// Calculate spacing between left and right content
@@ -3231,6 +5629,46 @@ func (a *App) formatStatusBar(left, right string) string {
 	return left + lipgloss.NewStyle().Width(spacing).Render("") + right
 }
 
+// formatBottomBar lays out the bottom bar: the focus label, as many whole
+// key hints as fit next to the indicators and the right side, then the
+// indicators. Hints are dropped before anything else is cut.
+func (a *App) formatBottomBar(label string, hints []components.KeyHint, indicators, right string) string {
+	styles := a.cachedStyles
+	// Borders/padding (4) and at least one cell between the sides
+	avail := a.state.Width - 4 - lipgloss.Width(label) - lipgloss.Width(indicators) - lipgloss.Width(right) - 1
+	if avail < 0 {
+		// Not even the right side fits; keep the hints over it
+		avail = a.state.Width - 4 - lipgloss.Width(label) - lipgloss.Width(indicators)
+	}
+	fitted := components.FitKeyHints(hints, avail, styles.keyStyle, styles.dimStyle, styles.separatorStyle.Render(" │ "))
+	return a.formatStatusBar(label+fitted+indicators, right)
+}
+
+// formatTreePath joins tree path segments for the status bar, dropping leading
+// segments (replaced by "…") until the path fits in maxWidth
+func formatTreePath(segments []string, maxWidth int) string {
+	const sep = " › "
+	if len(segments) == 0 || maxWidth < 4 {
+		return ""
+	}
+	path := strings.Join(segments, sep)
+	for i := 1; lipgloss.Width(path) > maxWidth && i < len(segments); i++ {
+		path = "…" + sep + strings.Join(segments[i:], sep)
+	}
+	if lipgloss.Width(path) > maxWidth {
+		// Even the last segment alone is too wide
+		path = ansi.Truncate(segments[len(segments)-1], maxWidth, "…")
+	}
+	return path
+}
+
+// refreshConnectionDialogHistory lists the connection history in the
+// dialog in the saved order: up to 10 connections plus pinned ones
+func (a *App) refreshConnectionDialogHistory() {
+	a.connectionDialog.MostUsed = a.connectionHistory.Order() == connection_history.OrderMostUsed
+	a.connectionDialog.SetHistoryEntries(a.connectionHistory.GetOrderedWithPinned(10))
+}
+
 // handleConnectionDialog handles key events when connection dialog is visible
 func (a *App) handleConnectionDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle search mode
@@ -3304,10 +5742,70 @@ func (a *App) handleConnectionDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.connectionDialog.ToggleMode()
 			return a, nil
 		}
-		// In manual mode, pass 'm' to textinput
-		var cmd tea.Cmd
-		a.connectionDialog, cmd = a.connectionDialog.Update(msg)
-		return a, cmd
+		// In manual mode, pass 'm' to textinput
+		var cmd tea.Cmd
+		a.connectionDialog, cmd = a.connectionDialog.Update(msg)
+		return a, cmd
+
+	case "p":
+		if a.connectionDialog.ManualMode {
+			var cmd tea.Cmd
+			a.connectionDialog, cmd = a.connectionDialog.Update(msg)
+			return a, cmd
+		}
+		// Pin or unpin the selected history entry
+		entry := a.connectionDialog.GetSelectedHistory()
+		if entry == nil || a.connectionHistory == nil {
+			return a, nil
+		}
+		id := entry.ID
+		if _, err := a.connectionHistory.TogglePin(id); err != nil {
+			a.ShowError("Pin Failed", fmt.Sprintf("Failed to save connection history:\n\n%v", err))
+			return a, nil
+		}
+		a.refreshConnectionDialogHistory()
+		a.connectionDialog.SelectHistoryEntry(id)
+		return a, nil
+
+	case "o":
+		if a.connectionDialog.ManualMode {
+			var cmd tea.Cmd
+			a.connectionDialog, cmd = a.connectionDialog.Update(msg)
+			return a, cmd
+		}
+		if a.connectionHistory == nil {
+			return a, nil
+		}
+		// Switch between most recent and most used first, keeping the
+		// selection on its entry
+		order := connection_history.OrderMostUsed
+		if a.connectionHistory.Order() == connection_history.OrderMostUsed {
+			order = connection_history.OrderRecent
+		}
+		var selectedID string
+		if entry := a.connectionDialog.GetSelectedHistory(); entry != nil {
+			selectedID = entry.ID
+		}
+		err := a.connectionHistory.SetOrder(order)
+		a.refreshConnectionDialogHistory()
+		a.connectionDialog.SelectHistoryEntry(selectedID)
+		if err != nil {
+			a.ShowError("Save Failed", fmt.Sprintf("The order applies, but could not be saved for next time:\n\n%v", err))
+		}
+		return a, nil
+
+	case "e":
+		if a.connectionDialog.ManualMode {
+			var cmd tea.Cmd
+			a.connectionDialog, cmd = a.connectionDialog.Update(msg)
+			return a, cmd
+		}
+		// Open the selected discovered instance in the manual form, to
+		// pick the database and user before connecting
+		if instance := a.connectionDialog.GetSelectedInstance(); instance != nil {
+			a.connectionDialog.PrefillFromDiscovered(*instance)
+		}
+		return a, nil
 
 	case "ctrl+d":
 		// Use Ctrl+D to switch back to discovery mode to avoid conflict with typing 'd'
@@ -3362,131 +5860,776 @@ func (a *App) handleConnectionDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					return a, nil
 				}
 
-				// Create connection config from discovered instance
-				config = models.ConnectionConfig{
-					Host:     instance.Host,
-					Port:     instance.Port,
-					Database: "postgres",
-					User:     os.Getenv("USER"),
-					Password: "",
-					SSLMode:  "prefer",
-				}
+				// Create connection config from discovered instance
+				config = models.ConnectionConfig{
+					Host:     instance.Host,
+					Port:     instance.Port,
+					Database: "postgres",
+					User:     os.Getenv("USER"),
+					Password: "",
+					SSLMode:  "prefer",
+				}
+			}
+
+			return a.performConnection(config)
+		}
+
+	default:
+		// In manual mode, delegate to textinput for cursor and text handling
+		if a.connectionDialog.ManualMode {
+			var cmd tea.Cmd
+			a.connectionDialog, cmd = a.connectionDialog.Update(msg)
+			return a, cmd
+		}
+		return a, nil
+	}
+}
+
+// getBuiltinCommands returns built-in commands with icons
+func (a *App) getBuiltinCommands() []models.Command {
+	cmds := a.commandRegistry.GetAll()
+	// Ensure commands have icons
+	for i := range cmds {
+		if cmds[i].Icon == "" {
+			cmds[i].Icon = "▸"
+		}
+	}
+	return cmds
+}
+
+// getTableCommands returns the tables, views, functions and other objects
+// of the connected database as command palette entries
+func (a *App) getTableCommands() []models.Command {
+	if a.state.ActiveConnection == nil {
+		return nil
+	}
+	return components.SchemaObjectCommands(a.schemaObjects, a.state.ActiveConnection.Config.Database, a.showSystemSchemas)
+}
+
+// toggleSystemSchemas shows or hides system schemas by re-filtering the
+// loaded tree, without reloading it
+func (a *App) toggleSystemSchemas() {
+	a.showSystemSchemas = !a.showSystemSchemas
+	a.treeView.SetSystemSchemasVisible(a.showSystemSchemas)
+}
+
+// codeTabTitle returns the tab title for loaded object details
+func (a *App) codeTabTitle(msg messages.ObjectDetailsLoadedMsg) string {
+	return a.resultTabs.ObjectTitle(components.TabTitleFields{
+		Schema:   msg.Schema,
+		Name:     msg.Name,
+		Type:     msg.ObjectType,
+		Database: a.state.CurrentDatabase,
+	}, msg.Title)
+}
+
+// copyRowsTSV copies the selected rows of table, else its pinned rows, in
+// grid order, or else all its loaded rows to the clipboard as TSV with a
+// header. Values are copied in full, not as the grid cuts them; NULLs
+// become empty cells, which spreadsheets treat as blank.
+func (a *App) copyRowsTSV(table *components.TableView) tea.Cmd {
+	var rows []int
+	what := "loaded rows"
+	if table.SelectionCount() > 0 {
+		rows = table.SelectionRows()
+		what = "selected rows"
+	} else if len(table.PinnedRows) > 0 {
+		rows = append([]int(nil), table.PinnedRows...)
+		sort.Ints(rows)
+		what = "pinned rows"
+	}
+	values := table.CopyRowValues(rows)
+	if len(values) == 0 {
+		return a.ShowToast("No rows to copy")
+	}
+
+	if err := clipboard.WriteAll(export.TSV(table.Columns, values, "")); err != nil {
+		a.ShowError("Copy Failed", fmt.Sprintf("Could not copy to the clipboard:\n\n%v", err))
+		return nil
+	}
+	toast := fmt.Sprintf("Copied %d %s as TSV", len(values), what)
+	if rows == nil && len(table.Rows) < table.TotalRows {
+		toast += fmt.Sprintf(" (%d of %d; scroll to load more)", len(table.Rows), table.TotalRows)
+	}
+	return a.ShowToast(toast)
+}
+
+// copyRowsSQL copies the selected rows of the active table tab, else its
+// pinned rows, else the current row, as a condition finding them by their
+// primary key or as an INSERT. The table's columns are loaded first for
+// the key and the column types.
+func (a *App) copyRowsSQL(insert bool) tea.Cmd {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.Type != components.TabTypeTableData || tab.Structure == nil {
+		a.ShowError("No Table", "Open a table tab to copy its rows as SQL")
+		return nil
+	}
+	parts := strings.SplitN(tab.ObjectID, ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	table := tab.Structure.GetTableView()
+	var rows []int
+	switch {
+	case table.SelectionCount() > 0:
+		rows = table.SelectionRows()
+	case len(table.PinnedRows) > 0:
+		rows = append([]int(nil), table.PinnedRows...)
+		sort.Ints(rows)
+	case table.SelectedRow >= 0 && table.SelectedRow < len(table.Rows):
+		rows = []int{table.SelectedLoadedRow()}
+	default:
+		return a.ShowToast("No rows to copy")
+	}
+	values := table.CopyRowValues(rows)
+	gridColumns := slices.Clone(table.Columns)
+	schema, name := parts[0], parts[1]
+
+	return func() tea.Msg {
+		msg := messages.RowsSQLMsg{Insert: insert, Rows: len(values)}
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		columns, err := metadata.GetColumnDetails(ctx, conn.Pool, schema, name)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		msg.SQL, msg.Err = rowsSQL(schema, name, columns, gridColumns, values, insert)
+		return msg
+	}
+}
+
+// rowsSQL writes rows of the grid as a condition on the table's primary key,
+// in key order, or as an INSERT of the columns that can be set. Without a
+// primary key rows can't be told apart, so there is no condition, and the
+// INSERT has no ON CONFLICT. Masked values can't be written.
+func rowsSQL(schema, table string, columns []models.ColumnDetail, gridColumns []string, rows [][]string, insert bool) (string, error) {
+	index := make(map[string]int, len(gridColumns))
+	for i, name := range gridColumns {
+		index[name] = i
+	}
+	primaryKey := models.PrimaryKey(columns)
+	key := make([]string, len(primaryKey))
+	for i, col := range primaryKey {
+		key[i] = col.Name
+	}
+
+	var written []models.ColumnDetail
+	if insert {
+		for _, col := range columns {
+			if _, ok := index[col.Name]; ok && col.Identity != "a" && !col.IsGenerated {
+				written = append(written, col)
+			}
+		}
+	} else {
+		if len(primaryKey) == 0 {
+			return "", fmt.Errorf("%s.%s has no primary key, so its rows can't be told apart", schema, table)
+		}
+		for _, col := range primaryKey {
+			if _, ok := index[col.Name]; !ok {
+				return "", fmt.Errorf("primary key column %s is not in the grid", col.Name)
+			}
+		}
+		written = primaryKey
+	}
+
+	values := make([][]filterBuilder.ColumnValue, len(rows))
+	for i, row := range rows {
+		values[i] = make([]filterBuilder.ColumnValue, len(written))
+		for j, col := range written {
+			value := row[index[col.Name]]
+			if value == components.MaskedValue {
+				return "", fmt.Errorf("column %s is masked", col.Name)
+			}
+			values[i][j] = filterBuilder.ColumnValue{
+				Column: col.Name,
+				Value:  value,
+				Type:   col.DataType,
+				Null:   value == filterBuilder.NullCellValue,
+			}
+		}
+	}
+
+	if insert {
+		return filterBuilder.InsertRowsSQL(schema, table, values, key)
+	}
+	return filterBuilder.RowsConditionSQL(values)
+}
+
+// cellCondition builds the column = value condition for the selected cell
+// of table and either applies it to the active table tab's filter or copies
+// it. On a table tab the column's type is looked up first so numbers and
+// booleans are copied unquoted; query results copy a quoted literal.
+// A masked cell can't be filtered on, which would show its value, and is
+// only copied when the mask rules let its value be copied.
+func (a *App) cellCondition(table *components.TableView, apply bool) tea.Cmd {
+	row, col := table.GetSelectedCell()
+	if row < 0 || row >= len(table.Rows) || col < 0 || col >= len(table.Columns) || col >= len(table.Rows[row]) {
+		return nil
+	}
+	column, value := table.Columns[col], table.Rows[row][col]
+	masked := table.IsCellMasked(row, col)
+	if masked && (apply || table.CopyValue(row, col) == components.MaskedValue) {
+		return a.ShowToast("The cell is masked; press " + components.RevealCellKey + " to reveal it first")
+	}
+
+	var schema, name, objectID string
+	if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeTableData && tab.Structure != nil && table == tab.Structure.GetTableView() {
+		if parts := strings.SplitN(tab.ObjectID, ".", 2); len(parts) == 2 {
+			objectID, schema, name = tab.ObjectID, parts[0], parts[1]
+		}
+	}
+	if objectID == "" {
+		if apply {
+			return a.ShowToast("Only table tabs can be filtered; " + components.CopyCellWhereKey + " copies the condition")
+		}
+		return func() tea.Msg {
+			return messages.CellConditionMsg{Condition: filterBuilder.CellCondition(column, value, ""), Masked: masked}
+		}
+	}
+
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.CellConditionMsg{Err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		columns, err := metadata.GetTableColumns(ctx, conn.Pool, schema, name)
+		if err != nil {
+			return messages.CellConditionMsg{Err: err}
+		}
+		dataType := ""
+		for _, c := range columns {
+			if c.Name == column {
+				dataType = c.DataType
+				break
+			}
+		}
+		return messages.CellConditionMsg{
+			ObjectID:  objectID,
+			Condition: filterBuilder.CellCondition(column, value, dataType),
+			Apply:     apply,
+			Masked:    masked,
+		}
+	}
+}
+
+// structureDDL copies the DDL of the constraint or index selected in the
+// active table tab, or opens it in a code tab. ok is false when no
+// constraint or index is selected, so the key falls through.
+func (a *App) structureDDL(open bool) (cmd tea.Cmd, ok bool) {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.Type != components.TabTypeTableData || tab.Structure == nil {
+		return nil, false
+	}
+	parts := strings.SplitN(tab.ObjectID, ".", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+	schema, table := parts[0], parts[1]
+
+	ddl, name := tab.Structure.CurrentDDL(schema, table)
+	if ddl == "" {
+		return nil, false
+	}
+
+	if open {
+		objectType := "constraint"
+		if tab.Structure.ActiveTab() == components.StructureTabIndexes {
+			objectType = "index"
+		}
+		a.CreateCodeEditorTab(messages.ObjectDetailsLoadedMsg{
+			ObjectType: objectType,
+			ObjectID:   fmt.Sprintf("ddl:%s.%s.%s", schema, table, name),
+			Schema:     schema,
+			Name:       name,
+			Title:      fmt.Sprintf("%s.%s (on %s)", schema, name, table),
+			Content:    ddl,
+		})
+		return nil, true
+	}
+
+	if err := clipboard.WriteAll(ddl); err != nil {
+		a.ShowError("Copy Failed", fmt.Sprintf("Failed to copy to clipboard:\n\n%v", err))
+		return nil, true
+	}
+	return a.ShowToast(fmt.Sprintf("Copied DDL of %s", name)), true
+}
+
+// rerunQueryTab runs a query result tab's SQL again in a new pending tab,
+// leaving the old result untouched
+func (a *App) rerunQueryTab(tab *components.ResultTab) tea.Cmd {
+	if a.state.ActiveConnection == nil && tab.ConnectionID == "" {
+		a.ShowError("No Connection", "Please connect to a database first")
+		return nil
+	}
+	if a.resultTabs.HasPendingQuery() {
+		return a.ShowToast("A query is already running (Esc to cancel)")
+	}
+
+	// The tab's SQL already has variables expanded and any LIMIT applied
+	sql, limit := tab.SQL, tab.AppliedLimit
+	a.resultTabs.StartPendingQuery(sql)
+	a.resultTabs.SetPendingLimit(limit)
+	if tab.ConnectionID != "" {
+		a.resultTabs.SetPendingConnection(tab.ConnectionID)
+	}
+
+	return tea.Batch(
+		a.executeSpinner.Tick,
+		a.ExecuteQuery(sql, tab.ConnectionID),
+	)
+}
+
+// loadNextQueryPage runs a query result tab's SQL for the rows after the ones
+// loaded, appending them when they arrive. The query runs unchanged apart
+// from its LIMIT and OFFSET, so its ORDER BY keeps the pages in order.
+func (a *App) loadNextQueryPage(tab *components.ResultTab) tea.Cmd {
+	tableView := tab.TableView
+	if tableView.IsPaginating {
+		return nil
+	}
+	if a.state.ActiveConnection == nil && tab.ConnectionID == "" {
+		a.ShowError("No Connection", "Please connect to a database first")
+		return nil
+	}
+
+	offset := len(tableView.LoadedRows())
+	sql, ok := components.QuickQueryPageSQL(tab.SQL, tab.AppliedLimit, offset)
+	if !ok {
+		return nil
+	}
+	tableView.IsPaginating = true
+
+	var toast tea.Cmd
+	if !components.HasTopLevelOrderBy(sql) {
+		toast = a.ShowToast("No ORDER BY: pages may repeat or skip rows")
+	}
+
+	tabID, connectionID := tab.ID, tab.ConnectionID
+	load := func() tea.Msg {
+		conn, err := a.queryConnection(connectionID)
+		if err != nil {
+			return messages.QueryPageLoadedMsg{TabID: tabID, Offset: offset, Result: models.QueryResult{Error: err}}
+		}
+		result := query.Execute(context.Background(), conn.Pool.GetPool(), sql)
+		return messages.QueryPageLoadedMsg{TabID: tabID, Offset: offset, Result: result}
+	}
+	return tea.Batch(toast, a.executeSpinner.Tick, load)
+}
+
+// bookmarkTreeNode adds the object under the tree cursor to favorites
+func (a *App) bookmarkTreeNode() tea.Cmd {
+	if a.favoritesManager == nil {
+		a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+		return nil
+	}
+
+	node := a.treeView.GetCurrentNode()
+	if node == nil || !models.IsReopenableObject(node.Type) {
+		a.ShowError("Cannot Bookmark", "Move the tree cursor to a table, view, function or other object to bookmark it.\n\nIndexes, triggers and columns can't be bookmarked.")
+		return nil
+	}
+
+	object := models.FavoriteObject{
+		Schema: a.getSchemaFromNode(node),
+		Type:   node.Type,
+		Name:   node.Label,
+	}
+	conn := ""
+	if a.state.ActiveConnection != nil {
+		conn = a.state.ActiveConnection.Config.Name
+	}
+	if _, err := a.favoritesManager.AddBookmark(object.QualifiedName(), "", object, conn, a.state.CurrentDatabase, nil); err != nil {
+		a.ShowError("Cannot Bookmark", fmt.Sprintf("Failed to bookmark %s:\n\n%v", object.QualifiedName(), err))
+		return nil
+	}
+
+	a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+	return a.ShowToast(fmt.Sprintf("Bookmarked %s", object.QualifiedName()))
+}
+
+// openTruncate opens the truncate dialog for the table node
+func (a *App) openTruncate(node *models.TreeNode) tea.Cmd {
+	if node == nil || node.Type != models.TreeNodeTypeTable {
+		a.ShowError("Cannot Truncate", "Move the tree cursor to a table to truncate it.")
+		return nil
+	}
+	schema := models.GetSchemaFromNode(node)
+	if schema == "" {
+		return nil
+	}
+	if a.state.ActiveConnection == nil {
+		a.ShowError("No Connection", "Please connect to a database first")
+		return nil
+	}
+	a.truncateDialog.Open(schema, node.Label)
+	a.showTruncate = true
+	return a.truncateDialog.Init()
+}
+
+// openBookmark navigates the tree to a bookmarked object and opens it
+func (a *App) openBookmark(fav models.Favorite) (tea.Model, tea.Cmd) {
+	if a.state.ActiveConnection == nil || a.treeView.Root == nil {
+		a.ShowError("No Database Connection", "Please connect to a database before opening bookmarks.\n\nPress 'c' to open the connection dialog.")
+		return a, nil
+	}
+
+	object := *fav.Object
+	if fav.Database != "" && fav.Database != a.state.CurrentDatabase {
+		a.ShowError("Bookmark Not Found", fmt.Sprintf("%s is in database %q, but you are connected to %q.\n\nConnect to %q to open it.",
+			object.QualifiedName(), fav.Database, a.state.CurrentDatabase, fav.Database))
+		return a, nil
+	}
+
+	node := a.treeView.Root.FindObject(object.Schema, object.Type, object.Name)
+	if node == nil {
+		hint := "It may have been dropped or renamed."
+		if models.IsSystemSchema(object.Schema) && !a.showSystemSchemas {
+			hint = "System schemas are hidden. Press '.' in the tree to show them."
+		}
+		a.ShowError("Bookmark Not Found", fmt.Sprintf("%s was not found in the current database.\n\n%s", object.QualifiedName(), hint))
+		return a, nil
+	}
+
+	if a.favoritesManager != nil {
+		if err := a.favoritesManager.RecordUsage(fav.ID); err != nil {
+			log.Printf("Warning: Failed to record favorite usage: %v", err)
+		}
+	}
+
+	a.showFavorites = false
+	a.treeView.ExpandAndNavigateToNode(node.ID)
+	return a, func() tea.Msg {
+		return components.TreeNodeSelectedMsg{Node: node}
+	}
+}
+
+// currentTableView captures the open table grid: its table, filter and sort.
+// ok is false when no table is open.
+func (a *App) currentTableView() (view models.FavoriteView, ok bool) {
+	var tableView *components.TableView
+	var filter *models.Filter
+
+	if a.resultTabs.HasTabs() {
+		tab := a.resultTabs.GetActiveTab()
+		if tab == nil || tab.Type != components.TabTypeTableData || tab.Structure == nil {
+			return view, false
+		}
+		parts := strings.SplitN(tab.ObjectID, ".", 2)
+		if len(parts) != 2 {
+			return view, false
+		}
+		view.Schema, view.Table = parts[0], parts[1]
+		tableView = tab.Structure.GetTableView()
+		filter = tab.Filter
+	} else {
+		parts := strings.SplitN(a.currentTable, ".", 2)
+		if len(parts) != 2 {
+			return view, false
+		}
+		view.Schema, view.Table = parts[0], parts[1]
+		tableView = a.tableView
+		if f := a.activeFilter; f != nil && f.Schema == view.Schema && f.TableName == view.Table {
+			filter = f
+		}
+	}
+
+	if filter.ConditionCount() > 0 {
+		saved := *filter
+		view.Filter = &saved
+	}
+	if tableView != nil && tableView.GetSortColumn() != "" {
+		view.SortColumn = tableView.GetSortColumn()
+		view.SortDir = tableView.GetSortDirection()
+		view.NullsFirst = tableView.GetNullsFirst()
+	}
+	return view, true
+}
+
+// saveTableView saves the open table grid, with its filter and sort, as a
+// favorite that reopens it the same way
+func (a *App) saveTableView() tea.Cmd {
+	if a.favoritesManager == nil {
+		a.ShowError("Favorites Not Available", "Favorites manager is not initialized.\n\nPlease restart the application.")
+		return nil
+	}
+
+	view, ok := a.currentTableView()
+	if !ok {
+		a.ShowError("Cannot Save View", "Open a table to save it, with its filter and sort, as a view.")
+		return nil
+	}
+
+	conn := ""
+	if a.state.ActiveConnection != nil {
+		conn = a.state.ActiveConnection.Config.Name
+	}
+	name := a.favoritesManager.UniqueName(view.Describe())
+	if _, err := a.favoritesManager.AddView(name, "", view, conn, a.state.CurrentDatabase, nil); err != nil {
+		a.ShowError("Cannot Save View", fmt.Sprintf("Failed to save %s:\n\n%v", view.QualifiedName(), err))
+		return nil
+	}
+
+	a.favoritesDialog.SetFavorites(a.favoritesManager.GetAll())
+	return a.ShowToast(fmt.Sprintf("Saved view %q to favorites", name))
+}
+
+// openTableView checks that a saved view's table still exists before
+// opening it; openCheckedTableView does the rest
+func (a *App) openTableView(fav models.Favorite) tea.Cmd {
+	if a.state.ActiveConnection == nil {
+		a.ShowError("No Database Connection", "Please connect to a database before opening saved views.\n\nPress 'c' to open the connection dialog.")
+		return nil
+	}
+
+	view := *fav.View
+	if fav.Database != "" && fav.Database != a.state.CurrentDatabase {
+		a.ShowError("Saved View Not Found", fmt.Sprintf("%s is in database %q, but you are connected to %q.\n\nConnect to %q to open it.",
+			view.QualifiedName(), fav.Database, a.state.CurrentDatabase, fav.Database))
+		return nil
+	}
+
+	a.showFavorites = false
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.TableViewCheckedMsg{Favorite: fav, Err: err}
+		}
+		exists, err := metadata.TableExists(context.Background(), conn.Pool, view.Schema, view.Table)
+		return messages.TableViewCheckedMsg{Favorite: fav, Exists: exists, Err: err}
+	}
+}
+
+// openCheckedTableView opens a saved view in its table's tab, loading the
+// rows with the saved filter and sort
+func (a *App) openCheckedTableView(msg messages.TableViewCheckedMsg) tea.Cmd {
+	fav := msg.Favorite
+	view := *fav.View
+	if msg.Err != nil {
+		a.ShowError("Saved View Error", fmt.Sprintf("Failed to open %s:\n\n%v", view.QualifiedName(), msg.Err))
+		return nil
+	}
+	if !msg.Exists {
+		a.ShowError("Saved View Not Found", fmt.Sprintf("%s no longer exists, so the view %q can't be opened.\n\nIt may have been dropped or renamed. Delete the view from favorites (Ctrl+B, then d) if it is no longer needed.",
+			view.QualifiedName(), fav.Name))
+		return nil
+	}
+
+	if a.favoritesManager != nil {
+		if err := a.favoritesManager.RecordUsage(fav.ID); err != nil {
+			log.Printf("Warning: Failed to record favorite usage: %v", err)
+		}
+	}
+
+	objectID := view.QualifiedName()
+	a.addTableDataTab(objectID, fav.Name, view.Schema, view.Table)
+	return tea.Batch(a.loadTableViewForTab(view, objectID, 100), a.executeSpinner.Tick)
+}
+
+// loadTableViewForTab loads the first limit rows of a table data tab with a
+// saved view's filter and sort, or with just a sort when the view has no
+// filter. The filter is bound as parameters, as in the filtered data load.
+func (a *App) loadTableViewForTab(view models.FavoriteView, objectID string, limit int) tea.Cmd {
+	return a.loadTableRowsForTab(view, objectID, 0, limit)
+}
+
+// loadRowWindowForTab loads the rows of window into a table data tab with
+// the view's filter and sort
+func (a *App) loadRowWindowForTab(view models.FavoriteView, objectID string, window *models.RowWindow) tea.Cmd {
+	load := a.loadTableRowsForTab(view, objectID, window.Offset, window.Limit)
+	return func() tea.Msg {
+		msg := load().(messages.TabTableDataLoadedMsg)
+		msg.Window = window
+		return msg
+	}
+}
+
+// loadTableRowsForTab loads limit rows of a table data tab from offset,
+// with the view's filter and sort
+func (a *App) loadTableRowsForTab(view models.FavoriteView, objectID string, offset, limit int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.TabTableDataLoadedMsg{ObjectID: objectID, Err: fmt.Errorf("no active connection: %w", err)}
+		}
+
+		var where string
+		var args []interface{}
+		if view.Filter != nil {
+			where, args, err = filterBuilder.NewBuilder().BuildWhere(*view.Filter)
+			if err != nil {
+				return messages.TabTableDataLoadedMsg{ObjectID: objectID, Err: fmt.Errorf("invalid saved filter: %w", err)}
 			}
+		}
 
-			return a.performConnection(config)
+		var sort *metadata.SortOptions
+		if view.SortColumn != "" {
+			sort = &metadata.SortOptions{
+				Column:     view.SortColumn,
+				Direction:  view.SortDir,
+				NullsFirst: view.NullsFirst,
+			}
 		}
 
-	default:
-		// In manual mode, delegate to textinput for cursor and text handling
-		if a.connectionDialog.ManualMode {
-			var cmd tea.Cmd
-			a.connectionDialog, cmd = a.connectionDialog.Update(msg)
-			return a, cmd
+		data, err := metadata.QueryFilteredTableData(ctx, conn.Pool, view.Schema, view.Table, where, args, offset, limit, sort)
+		if err != nil {
+			// e.g. a filtered or sorted column was dropped
+			return messages.TabTableDataLoadedMsg{ObjectID: objectID, Err: fmt.Errorf("could not load %s with the view's filter and sort: %w", view.QualifiedName(), err)}
+		}
+
+		return messages.TabTableDataLoadedMsg{
+			ObjectID:    objectID,
+			Schema:      view.Schema,
+			Table:       view.Table,
+			Columns:     data.Columns,
+			ColumnKinds: data.ColumnKinds,
+			Rows:        data.Rows,
+			TotalRows:   int(data.TotalRows),
+			SQL:         data.SQL,
+			Filter:      view.Filter,
+			SortColumn:  view.SortColumn,
+			SortDir:     view.SortDir,
+			NullsFirst:  view.NullsFirst,
+
+			UnfilteredRows: int(data.UnfilteredRows),
+		}
+	}
+}
+
+// loadSearchPath loads the session's search_path to resolve an unqualified name
+func (a *App) loadSearchPath(name string) tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.SearchPathLoadedMsg{Name: name, Err: err}
+		}
+		searchPath, err := metadata.GetSearchPath(context.Background(), conn.Pool)
+		return messages.SearchPathLoadedMsg{Name: name, SearchPath: searchPath, Err: err}
+	}
+}
+
+// openDefinition resolves a name from the SQL editor against the tree and
+// opens the object, as if it had been selected there
+func (a *App) openDefinition(schema, name string, searchPath []string) (tea.Model, tea.Cmd) {
+	node := a.treeView.Root.ResolveObject(schema, name, searchPath)
+	if node == nil {
+		qualified := name
+		if schema != "" {
+			qualified = schema + "." + name
+		}
+		var hint string
+		switch {
+		case schema != "" && models.IsSystemSchema(schema) && !a.showSystemSchemas:
+			hint = "System schemas are hidden. Press '.' in the tree to show them."
+		case schema == "":
+			hint = fmt.Sprintf("Searched search_path: %s", strings.Join(searchPath, ", "))
+		default:
+			hint = "It may not exist, or the tree may need reloading."
 		}
+		a.ShowError("Object Not Found", fmt.Sprintf("%s was not found in the current database.\n\n%s", qualified, hint))
 		return a, nil
 	}
+
+	a.treeView.ExpandAndNavigateToNode(node.ID)
+	return a, func() tea.Msg {
+		return components.TreeNodeSelectedMsg{Node: node}
+	}
 }
 
-// getBuiltinCommands returns built-in commands with icons
-func (a *App) getBuiltinCommands() []models.Command {
-	cmds := a.commandRegistry.GetAll()
-	// Ensure commands have icons
-	for i := range cmds {
-		if cmds[i].Icon == "" {
-			cmds[i].Icon = "▸"
+// openSequence selects a sequence in the tree and opens it, e.g. the one a
+// serial or identity column takes its values from
+func (a *App) openSequence(schema, name string) (tea.Model, tea.Cmd) {
+	if a.treeView.Root == nil {
+		return a, nil
+	}
+	node := a.treeView.Root.FindObject(schema, models.TreeNodeTypeSequence, name)
+	if node == nil {
+		hint := "It may not exist, or the tree may need reloading."
+		if models.IsSystemSchema(schema) && !a.showSystemSchemas {
+			hint = "System schemas are hidden. Press '.' in the tree to show them."
 		}
+		a.ShowError("Sequence Not Found", fmt.Sprintf("%s.%s was not found in the current database.\n\n%s", schema, name, hint))
+		return a, nil
+	}
+
+	a.treeView.ExpandAndNavigateToNode(node.ID)
+	return a, func() tea.Msg {
+		return components.TreeNodeSelectedMsg{Node: node}
 	}
-	return cmds
 }
 
-// getTableCommands returns tables and views as commands
-func (a *App) getTableCommands() []models.Command {
+// getRecentCommands returns recently opened tree objects as commands
+func (a *App) getRecentCommands() []models.Command {
 	var cmds []models.Command
-
 	if a.treeView.Root == nil {
 		return cmds
 	}
 
-	// Traverse tree to find all tables and views
-	var traverse func(node *models.TreeNode)
-	traverse = func(node *models.TreeNode) {
+	for _, id := range a.recentObjects.IDs() {
+		node := a.treeView.Root.FindByID(id)
 		if node == nil {
-			return
+			continue
 		}
-
-		if node.Type == models.TreeNodeTypeTable || node.Type == models.TreeNodeTypeView {
-			// Get schema name from parent chain
-			var schemaName string
-			parent := node.Parent
-			for parent != nil {
-				if parent.Type == models.TreeNodeTypeSchema {
-					schemaName = strings.Split(parent.Label, " ")[0]
-					break
-				}
-				parent = parent.Parent
-			}
-			if schemaName != "" {
-				icon := "▦"
-				prefix := "table:"
-				if node.Type == models.TreeNodeTypeView {
-					icon = "◎"
-					prefix = "view:"
-				}
-				cmds = append(cmds, models.Command{
-					ID:          fmt.Sprintf("%s%s.%s", prefix, schemaName, node.Label),
-					Label:       fmt.Sprintf("%s.%s", schemaName, node.Label),
-					Description: "",
-					Icon:        icon,
-					Tags:        []string{schemaName, node.Label},
-				})
-			}
+		label := node.Label
+		if schema := a.getSchemaFromNode(node); schema != "" {
+			label = schema + "." + node.Label
 		}
-
-		for _, child := range node.Children {
-			traverse(child)
+		icon := "•"
+		switch node.Type {
+		case models.TreeNodeTypeTable:
+			icon = "▦"
+		case models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView:
+			icon = "◎"
+		case models.TreeNodeTypeForeignTable:
+			icon = components.ForeignTableIcon
+		case models.TreeNodeTypeFunction, models.TreeNodeTypeProcedure, models.TreeNodeTypeTriggerFunction:
+			icon = "ƒ"
 		}
+		cmds = append(cmds, models.Command{
+			ID:          "recent:" + id,
+			Type:        models.CommandTypeObject,
+			Label:       label,
+			Description: strings.ReplaceAll(string(node.Type), "_", " "),
+			Icon:        icon,
+			Tags:        []string{node.Label, "recent"},
+		})
 	}
-
-	traverse(a.treeView.Root)
 	return cmds
 }
 
-// getHistoryCommands returns query history as commands
-func (a *App) getHistoryCommands() []models.Command {
-	var cmds []models.Command
+// openRecentObjects opens the command palette in recent objects mode
+func (a *App) openRecentObjects() (tea.Model, tea.Cmd) {
+	a.commandPalette.Reset()
+	a.commandPalette.SetCommands(a.getBuiltinCommands())
+	a.commandPalette.SetTables(a.getTableCommands())
+	a.commandPalette.SetHistory(a.getHistoryCommands())
+	a.commandPalette.SetRecent(a.getRecentCommands())
+	a.commandPalette.SetInput("~")
+	a.showCommandPalette = true
+	return a, nil
+}
 
+// getHistoryCommands returns query history as commands, one per distinct
+// query
+func (a *App) getHistoryCommands() []models.Command {
 	if a.historyStore == nil {
-		return cmds
+		return nil
 	}
 
-	entries, err := a.historyStore.GetRecent(20)
+	stats, err := a.historyStore.RecentStats(components.PaletteHistoryLimit)
 	if err != nil {
-		return cmds
-	}
-
-	for _, entry := range entries {
-		// Truncate long queries for display
-		displayQuery := entry.Query
-		if len(displayQuery) > 60 {
-			displayQuery = displayQuery[:57] + "..."
-		}
-
-		cmds = append(cmds, models.Command{
-			ID:          fmt.Sprintf("history:%d", entry.ID),
-			Type:        models.CommandTypeHistory,
-			Label:       displayQuery,
-			Description: fmt.Sprintf("From %s • %s", entry.DatabaseName, entry.ExecutedAt.Format("Jan 2 15:04")),
-			Icon:        "📜",
-			Tags:        []string{"history", entry.DatabaseName},
-			Action: func(sql string) tea.Cmd {
-				return func() tea.Msg {
-					return components.ExecuteQueryMsg{SQL: sql}
-				}
-			}(entry.Query),
-		})
+		return nil
 	}
-
-	return cmds
+	return components.HistoryCommands(stats)
 }
 
 // handleCommandPalette handles key events when command palette is visible
@@ -3510,36 +6653,25 @@ func (a *App) handleCommandPalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
-		// Handle table/view selection (ID starts with "table:" or "view:")
-		if strings.HasPrefix(selected.ID, "table:") || strings.HasPrefix(selected.ID, "view:") {
-			// Parse schema.table from ID (format: "table:schema.name" or "view:schema.name")
-			var prefix string
-			if strings.HasPrefix(selected.ID, "table:") {
-				prefix = "table:"
-			} else {
-				prefix = "view:"
+		// Handle object selection: open it as if picked in the tree
+		var nodeID string
+		switch {
+		case strings.HasPrefix(selected.ID, "recent:"):
+			nodeID = strings.TrimPrefix(selected.ID, "recent:")
+		case strings.HasPrefix(selected.ID, components.PaletteObjectPrefix):
+			nodeID = strings.TrimPrefix(selected.ID, components.PaletteObjectPrefix)
+		}
+		if nodeID != "" {
+			if a.treeView.Root == nil {
+				return a, nil
 			}
-			parts := strings.SplitN(strings.TrimPrefix(selected.ID, prefix), ".", 2)
-			if len(parts) == 2 {
-				schema := parts[0]
-				table := parts[1]
-				a.currentTable = schema + "." + table
-
-				// Sync tree view position - find the node and expand ancestors
-				if a.state.ActiveConnection != nil {
-					dbName := a.state.ActiveConnection.Config.Database
-					nodeID := fmt.Sprintf("%s%s.%s.%s", prefix, dbName, schema, table)
-					a.treeView.ExpandAndNavigateToNode(nodeID)
-				}
-
-				return a, func() tea.Msg {
-					return messages.LoadTableDataMsg{
-						Schema: schema,
-						Table:  table,
-						Offset: 0,
-						Limit:  100,
-					}
-				}
+			node := a.treeView.Root.FindByID(nodeID)
+			if node == nil {
+				return a, nil
+			}
+			a.treeView.ExpandAndNavigateToNode(nodeID)
+			return a, func() tea.Msg {
+				return components.TreeNodeSelectedMsg{Node: node}
 			}
 		}
 
@@ -3559,6 +6691,22 @@ func (a *App) handleFilterBuilder(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// openJSONBViewer shows a cell's JSON value in the JSONB viewer, or an
+// error if it doesn't parse. The grid keeps focus for when the viewer closes.
+func (a *App) openJSONBViewer(value string) {
+	// Set viewer dimensions based on terminal size (with max width limit)
+	viewerWidth := a.state.Width * 2 / 3
+	if viewerWidth > 100 {
+		viewerWidth = 100
+	}
+	a.jsonbViewer.SetMaxSize(viewerWidth, a.state.Height*3/4)
+	if err := a.jsonbViewer.SetValue(value); err != nil {
+		a.ShowError("Invalid JSON", fmt.Sprintf("The selected cell can't be opened in the JSON viewer:\n\n%v", err))
+		return
+	}
+	a.showJSONBViewer = true
+}
+
 // handleJSONBViewer handles key events when JSONB viewer is visible
 func (a *App) handleJSONBViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -3661,7 +6809,17 @@ func (a *App) renderConnectingOverlay() string {
 	)
 
 	// Build each line separately
-	line1 := a.executeSpinner.View() + " " + loadingStyle.Render("Connecting...") + " " + elapsedStyle.Render(elapsedStr)
+	status := "Connecting..."
+	if a.connectRetryErr != nil {
+		// Retrying after a transient failure: show the attempt in flight, or
+		// the one waiting out its backoff, out of the configured attempts
+		attempt := a.connectAttempt
+		if !a.connectRetryAt.IsZero() {
+			attempt++
+		}
+		status = fmt.Sprintf("Connecting... retry %d/%d", attempt, a.maxConnectAttempts())
+	}
+	line1 := a.executeSpinner.View() + " " + loadingStyle.Render(status) + " " + elapsedStyle.Render(elapsedStr)
 	line2 := hostStyle.Render(hostInfo)
 	line3 := hintStyle.Render("Press Esc to cancel")
 
@@ -3675,14 +6833,19 @@ func (a *App) renderConnectingOverlay() string {
 	// Create content with proper centering
 	contentStyle := lipgloss.NewStyle().Width(dialogWidth - 6).Align(lipgloss.Center)
 
-	content := lipgloss.JoinVertical(lipgloss.Center,
+	lines := []string{
 		"",
 		contentStyle.Render(line1),
 		"",
 		contentStyle.Render(line2),
-		"",
-		contentStyle.Render(line3),
-	)
+	}
+	if a.connectRetryErr != nil {
+		// Why the previous attempt failed, on one line
+		reason := "Last attempt: " + strings.Join(strings.Fields(a.connectRetryErr.Error()), " ")
+		lines = append(lines, "", contentStyle.Render(elapsedStyle.Render(ansi.Truncate(reason, dialogWidth-6, "…"))))
+	}
+	lines = append(lines, "", contentStyle.Render(line3))
+	content := lipgloss.JoinVertical(lipgloss.Center, lines...)
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -3781,8 +6944,11 @@ func (a *App) loadTree() tea.Msg {
 	}
 	type schemaData struct {
 		tables           []string
+		columnCounts     map[string]int // Relation name -> column count
 		views            []string
 		matViews         []string
+		foreignTables    []string
+		foreignServers   map[string]string // Foreign table name -> its server
 		sequences        []string
 		functions        []funcInfo
 		procedures       []funcInfo
@@ -3797,16 +6963,23 @@ func (a *App) loadTree() tea.Msg {
 	for _, obj := range schemaObjects {
 		sd, ok := schemaMap[obj.SchemaName]
 		if !ok {
-			sd = &schemaData{}
+			sd = &schemaData{columnCounts: make(map[string]int), foreignServers: make(map[string]string)}
 			schemaMap[obj.SchemaName] = sd
 		}
 		switch obj.ObjectType {
+		case "table", "view", "matview", "foreign_table":
+			sd.columnCounts[obj.ObjectName] = obj.Columns
+		}
+		switch obj.ObjectType {
 		case "table":
 			sd.tables = append(sd.tables, obj.ObjectName)
 		case "view":
 			sd.views = append(sd.views, obj.ObjectName)
 		case "matview":
 			sd.matViews = append(sd.matViews, obj.ObjectName)
+		case "foreign_table":
+			sd.foreignTables = append(sd.foreignTables, obj.ObjectName)
+			sd.foreignServers[obj.ObjectName] = obj.Arguments
 		case "sequence":
 			sd.sequences = append(sd.sequences, obj.ObjectName)
 		case "function":
@@ -3865,6 +7038,7 @@ func (a *App) loadTree() tea.Msg {
 			schemaName,
 		)
 		schemaNode.Selectable = true
+		schemaNode.Metadata = map[string]interface{}{"table_count": len(sd.tables)}
 
 		// Tables group with actual table nodes
 		if len(sd.tables) > 0 {
@@ -3881,6 +7055,7 @@ func (a *App) loadTree() tea.Msg {
 					tableName,
 				)
 				tableNode.Selectable = true
+				tableNode.Metadata = map[string]interface{}{"column_count": sd.columnCounts[tableName]}
 				tableNode.Loaded = false // Columns/indexes still lazy load
 				tablesGroup.AddChild(tableNode)
 			}
@@ -3903,6 +7078,7 @@ func (a *App) loadTree() tea.Msg {
 					viewName,
 				)
 				viewNode.Selectable = true
+				viewNode.Metadata = map[string]interface{}{"column_count": sd.columnCounts[viewName]}
 				viewNode.Loaded = true // Views don't have children
 				viewsGroup.AddChild(viewNode)
 			}
@@ -3925,6 +7101,7 @@ func (a *App) loadTree() tea.Msg {
 					matViewName,
 				)
 				matViewNode.Selectable = true
+				matViewNode.Metadata = map[string]interface{}{"column_count": sd.columnCounts[matViewName]}
 				matViewNode.Loaded = true // MatViews don't have children
 				matViewsGroup.AddChild(matViewNode)
 			}
@@ -3932,6 +7109,32 @@ func (a *App) loadTree() tea.Msg {
 			schemaNode.AddChild(matViewsGroup)
 		}
 
+		// Foreign Tables group; each node records its server
+		if len(sd.foreignTables) > 0 {
+			foreignGroup := models.NewTreeNode(
+				fmt.Sprintf("foreigntables:%s.%s", currentDB, schemaName),
+				models.TreeNodeTypeForeignTableGroup,
+				fmt.Sprintf("Foreign Tables (%d)", len(sd.foreignTables)),
+			)
+			foreignGroup.Selectable = false
+			for _, tableName := range sd.foreignTables {
+				foreignNode := models.NewTreeNode(
+					fmt.Sprintf("foreigntable:%s.%s.%s", currentDB, schemaName, tableName),
+					models.TreeNodeTypeForeignTable,
+					tableName,
+				)
+				foreignNode.Selectable = true
+				foreignNode.Metadata = map[string]interface{}{
+					"column_count":   sd.columnCounts[tableName],
+					"foreign_server": sd.foreignServers[tableName],
+				}
+				foreignNode.Loaded = true // Foreign tables have no indexes or triggers to list
+				foreignGroup.AddChild(foreignNode)
+			}
+			foreignGroup.Loaded = true
+			schemaNode.AddChild(foreignGroup)
+		}
+
 		// Functions group with actual function nodes
 		if len(sd.functions) > 0 {
 			funcsGroup := models.NewTreeNode(
@@ -4061,7 +7264,7 @@ func (a *App) loadTree() tea.Msg {
 					enumTypeName,
 				)
 				enumTypeNode.Selectable = true
-				enumTypeNode.Loaded = true // Enum types don't have children
+				// Values are loaded as children when the node is expanded
 				enumTypesGroup.AddChild(enumTypeNode)
 			}
 			enumTypesGroup.Loaded = true
@@ -4116,7 +7319,9 @@ func (a *App) loadTree() tea.Msg {
 		dbNode.AddChild(schemaNode)
 	}
 
-	return messages.TreeLoadedMsg{Root: root}
+	root.SetSystemSchemasVisible(a.showSystemSchemas)
+
+	return messages.TreeLoadedMsg{Root: root, AllObjects: schemaObjects}
 }
 
 // loadNodeChildren loads children for table nodes (indexes and triggers).
@@ -4189,6 +7394,24 @@ func (a *App) loadNodeChildren(nodeID string) tea.Cmd {
 				triggerGroup.Loaded = true
 				children = append(children, triggerGroup)
 			}
+
+		case models.TreeNodeTypeEnumType:
+			// Show the enum's values, in sort order, for reference only
+			schema := a.getSchemaFromNode(node)
+			labels, err := metadata.ListEnumValues(ctx, conn.Pool, schema, node.Label)
+			if err != nil {
+				return messages.NodeChildrenLoadedMsg{NodeID: nodeID, Err: err}
+			}
+			for _, label := range labels {
+				valueNode := models.NewTreeNode(
+					fmt.Sprintf("enumvalue:%s.%s.%s.%s", currentDB, schema, node.Label, label),
+					models.TreeNodeTypeEnumValue,
+					label,
+				)
+				valueNode.Selectable = false
+				valueNode.Loaded = true
+				children = append(children, valueNode)
+			}
 		}
 
 		return messages.NodeChildrenLoadedMsg{NodeID: nodeID, Children: children}
@@ -4233,10 +7456,12 @@ func (a *App) loadTableData(msg messages.LoadTableDataMsg) tea.Cmd {
 		}
 
 		return messages.TableDataLoadedMsg{
-			Columns:   data.Columns,
-			Rows:      data.Rows,
-			TotalRows: int(data.TotalRows),
-			Offset:    msg.Offset,
+			Columns:     data.Columns,
+			ColumnKinds: data.ColumnKinds,
+			Rows:        data.Rows,
+			TotalRows:   int(data.TotalRows),
+			Offset:      msg.Offset,
+			SQL:         data.SQL,
 		}
 	}
 }
@@ -4260,80 +7485,426 @@ func (a *App) loadTableDataForTab(schema, table, objectID string) tea.Cmd {
 			ObjectID:  objectID,
 			Schema:    schema,
 			Table:     table,
-			Columns:   data.Columns,
-			Rows:      data.Rows,
-			TotalRows: int(data.TotalRows),
+			Columns:     data.Columns,
+			ColumnKinds: data.ColumnKinds,
+			Rows:        data.Rows,
+			TotalRows:   int(data.TotalRows),
+			SQL:         data.SQL,
 		}
 	}
 }
 
-// loadStructureMetadata loads columns, constraints, and indexes for a table asynchronously
-func (a *App) loadStructureMetadata(schema, table, objectID string) tea.Cmd {
+// loadStructureMetadata loads the metadata for one structure sub-tab
+// (columns, constraints or indexes) asynchronously
+func (a *App) loadStructureMetadata(schema, table, objectID string, section int) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
+		msg := messages.StructureMetadataLoadedMsg{ObjectID: objectID, Section: section}
 
 		conn, err := a.connectionManager.GetActive()
 		if err != nil {
-			return messages.StructureMetadataLoadedMsg{ObjectID: objectID, Err: fmt.Errorf("no active connection: %w", err)}
+			msg.Err = fmt.Errorf("no active connection: %w", err)
+			return msg
 		}
 
-		columns, err := metadata.GetColumnDetails(ctx, conn.Pool, schema, table)
+		switch section {
+		case components.StructureTabColumns:
+			msg.Columns, msg.Err = metadata.GetColumnDetails(ctx, conn.Pool, schema, table)
+		case components.StructureTabConstraints:
+			msg.Constraints, msg.Err = metadata.GetConstraints(ctx, conn.Pool, schema, table)
+		case components.StructureTabIndexes:
+			msg.Indexes, msg.Err = metadata.GetIndexes(ctx, conn.Pool, schema, table)
+		}
+		return msg
+	}
+}
+
+// triggerStructureMetadataLoad loads the structure view's active sub-tab if
+// it hasn't been loaded yet. Returns a command if loading was triggered,
+// nil otherwise.
+func (a *App) triggerStructureMetadataLoad(structure *components.StructureView, objectID string) tea.Cmd {
+	if structure == nil {
+		return nil
+	}
+	section := structure.ActiveTab()
+	if !structure.NeedsSection(section) {
+		return nil
+	}
+
+	parts := strings.Split(objectID, ".")
+	if len(parts) != 2 {
+		return nil
+	}
+
+	structure.SetSectionLoading(section)
+	return tea.Batch(a.executeSpinner.Tick, a.loadStructureMetadata(parts[0], parts[1], objectID, section))
+}
+
+// cycleAutoRefresh steps the active table tab's auto refresh interval
+// through the presets and then off
+func (a *App) cycleAutoRefresh() tea.Cmd {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.Type != components.TabTypeTableData || tab.Structure == nil {
+		a.ShowError("No Table", "Open a table tab to auto-refresh it")
+		return nil
+	}
+
+	tab.RefreshInterval = components.NextRefreshInterval(tab.RefreshInterval)
+	tab.RefreshSeq++ // Stops the previous interval's ticks
+	if tab.RefreshInterval == 0 {
+		return a.ShowToast("Auto refresh off for " + tab.Title)
+	}
+	return tea.Batch(
+		a.ShowToast(fmt.Sprintf("Refreshing %s every %s", tab.Title, components.FormatRefreshInterval(tab.RefreshInterval))),
+		a.scheduleTabRefresh(tab),
+	)
+}
+
+// openRowWindow opens the LIMIT/OFFSET editor for the active table tab
+func (a *App) openRowWindow() tea.Cmd {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.Type != components.TabTypeTableData || tab.Structure == nil {
+		a.ShowError("No Table", "Open a table tab to set its LIMIT and OFFSET")
+		return nil
+	}
+	tableView := tab.Structure.GetTableView()
+	a.rowWindowDialog.Open(tableView.Window, tableView.TotalRows)
+	a.showRowWindow = true
+	return a.rowWindowDialog.Init()
+}
+
+// openRowEdit loads the columns of the active table tab's table, to open
+// its selected row in the row edit form
+func (a *App) openRowEdit() tea.Cmd {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.Type != components.TabTypeTableData || tab.Structure == nil {
+		a.ShowError("No Table", "Open a table tab to edit one of its rows")
+		return nil
+	}
+	parts := strings.SplitN(tab.ObjectID, ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	tableView := tab.Structure.GetTableView()
+	row := tableView.SelectedRow
+	if row < 0 || row >= len(tableView.Rows) {
+		return a.ShowToast("No row selected")
+	}
+	msg := messages.RowEditLoadedMsg{
+		ObjectID:    tab.ObjectID,
+		Schema:      parts[0],
+		Table:       parts[1],
+		GridColumns: slices.Clone(tableView.Columns),
+		Row:         slices.Clone(tableView.Rows[row]),
+		Masked:      make([]bool, len(tableView.Columns)),
+	}
+	for col := range msg.Masked {
+		msg.Masked[col] = tableView.IsCellMasked(row, col)
+	}
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
 		if err != nil {
-			return messages.StructureMetadataLoadedMsg{ObjectID: objectID, Err: err}
+			msg.Err = err
+			return msg
 		}
+		msg.Columns, msg.Err = metadata.GetColumnDetails(context.Background(), conn.Pool, msg.Schema, msg.Table)
+		return msg
+	}
+}
 
-		constraints, err := metadata.GetConstraints(ctx, conn.Pool, schema, table)
+// updateRow runs the row edit form's UPDATE in a transaction, committing
+// it only if it changed exactly the one row; a row edited or deleted since
+// it was loaded matches none. In safe mode the transaction is left open
+// for the safe mode prompt.
+func (a *App) updateRow(msg components.RowEditSaveMsg) tea.Cmd {
+	safeMode := a.safeMode
+	return func() tea.Msg {
+		updated := messages.RowUpdatedMsg{ObjectID: msg.ObjectID, SQL: msg.SQL}
+		conn, err := a.connectionManager.GetActive()
 		if err != nil {
-			return messages.StructureMetadataLoadedMsg{ObjectID: objectID, Err: err}
+			updated.Err = err
+			return updated
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		result, tx := query.ExecuteInTransaction(ctx, conn.Pool.GetPool(), msg.SQL)
+		if result.Error != nil {
+			updated.Err = result.Error
+			return updated
 		}
+		if result.RowsAffected != 1 {
+			_ = tx.Rollback(ctx)
+			updated.Err = fmt.Errorf("the UPDATE matched %d rows, so it was rolled back; the row may have changed since it was loaded (press r to reload)", result.RowsAffected)
+			return updated
+		}
+		if safeMode {
+			updated.Transaction = tx
+			return updated
+		}
+		updated.Err = tx.Commit(ctx)
+		return updated
+	}
+}
 
-		indexes, err := metadata.GetIndexes(ctx, conn.Pool, schema, table)
+// truncateTable runs the TRUNCATE confirmed in the truncate dialog. The
+// typed name is its confirmation, so safe mode doesn't hold it open too.
+func (a *App) truncateTable(msg components.TruncateConfirmMsg) tea.Cmd {
+	return func() tea.Msg {
+		truncated := messages.TableTruncatedMsg{
+			ObjectID: msg.Schema + "." + msg.Table,
+			Cascade:  msg.Cascade,
+		}
+		conn, err := a.connectionManager.GetActive()
 		if err != nil {
-			return messages.StructureMetadataLoadedMsg{ObjectID: objectID, Err: err}
+			truncated.Err = err
+			return truncated
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, truncated.Err = conn.Pool.Execute(ctx, msg.SQL)
+		return truncated
+	}
+}
 
-		return messages.StructureMetadataLoadedMsg{
-			ObjectID:    objectID,
-			Columns:     columns,
-			Constraints: constraints,
-			Indexes:     indexes,
+// refreshTruncatedTabs reloads the open table tab of a truncated table, or
+// with CASCADE every open table tab, since the tables it reached are only
+// known to the server
+func (a *App) refreshTruncatedTabs(msg messages.TableTruncatedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, tab := range a.resultTabs.GetAllTabs() {
+		if tab.Type != components.TabTypeTableData || tab.Structure == nil {
+			continue
+		}
+		if msg.Cascade || tab.ObjectID == msg.ObjectID {
+			cmds = append(cmds, a.refreshTab(tab))
 		}
 	}
+	return tea.Batch(cmds...)
 }
 
-// triggerStructureMetadataLoad checks if the active tab needs metadata and triggers loading.
-// Returns a command if loading was triggered, nil otherwise.
-func (a *App) triggerStructureMetadataLoad(structure *components.StructureView, objectID string) tea.Cmd {
-	if structure == nil || !structure.NeedsMetadata() {
+// applyRowWindow reloads the active table tab with the window from the
+// LIMIT/OFFSET editor, keeping its filter and sort. A nil window reloads the
+// first page, which loads more as the grid is scrolled.
+func (a *App) applyRowWindow(msg components.RowWindowSubmitMsg) tea.Cmd {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.Type != components.TabTypeTableData || tab.Structure == nil {
+		return nil
+	}
+	parts := strings.SplitN(tab.ObjectID, ".", 2)
+	if len(parts) != 2 {
 		return nil
 	}
+	tableView := tab.Structure.GetTableView()
+	view := models.FavoriteView{
+		Schema:     parts[0],
+		Table:      parts[1],
+		Filter:     tab.Filter,
+		SortColumn: tableView.GetSortColumn(),
+		SortDir:    tableView.GetSortDirection(),
+		NullsFirst: tableView.GetNullsFirst(),
+	}
 
-	parts := strings.Split(objectID, ".")
+	tableView.IsLoading = true
+	tableView.LoadingStart = time.Now()
+	if msg.Window == nil {
+		return tea.Batch(a.loadTableViewForTab(view, tab.ObjectID, 100), a.executeSpinner.Tick)
+	}
+	cmds := []tea.Cmd{a.loadRowWindowForTab(view, tab.ObjectID, msg.Window), a.executeSpinner.Tick}
+	if msg.Clamped {
+		cmds = append(cmds, a.ShowToast(fmt.Sprintf("OFFSET is past the last row; loading from %d", msg.Window.Offset)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// scheduleTabRefresh schedules the next auto refresh tick of a tab
+func (a *App) scheduleTabRefresh(tab *components.ResultTab) tea.Cmd {
+	id, seq := tab.ID, tab.RefreshSeq
+	return tea.Tick(tab.RefreshInterval, func(time.Time) tea.Msg {
+		return messages.TabRefreshTickMsg{TabID: id, Seq: seq}
+	})
+}
+
+// autoRefreshPaused reports whether the user is busy, so auto refresh should
+// not move rows under them: text is being edited, or there was input within
+// autoRefreshIdle
+func (a *App) autoRefreshPaused() bool {
+	return a.isEditingText() || time.Since(a.lastInput) < autoRefreshIdle
+}
+
+// refreshTab loads a table tab again with its filter and sort, as many rows
+// as it shows now up to maxAutoRefreshRows, to update it in place
+func (a *App) refreshTab(tab *components.ResultTab) tea.Cmd {
+	parts := strings.SplitN(tab.ObjectID, ".", 2)
 	if len(parts) != 2 {
 		return nil
 	}
+	tableView := tab.Structure.GetTableView()
+	view := models.FavoriteView{
+		Schema:     parts[0],
+		Table:      parts[1],
+		Filter:     tab.Filter,
+		SortColumn: tableView.GetSortColumn(),
+		SortDir:    tableView.GetSortDirection(),
+		NullsFirst: tableView.GetNullsFirst(),
+	}
+	limit := min(max(len(tableView.LoadedRows()), 100), maxAutoRefreshRows)
+
+	tab.Refreshing = true
+	load := a.loadTableViewForTab(view, tab.ObjectID, limit)
+	if tableView.Window != nil {
+		load = a.loadRowWindowForTab(view, tab.ObjectID, tableView.Window)
+	}
+	primaryKey := tab.PrimaryKey
+	return func() tea.Msg {
+		msg := load().(messages.TabTableDataLoadedMsg)
+		msg.Refresh = true
+		if primaryKey == nil {
+			// Without a key changed rows can't be told from moved ones, so
+			// a failed lookup just means no highlight
+			if conn, err := a.connectionManager.GetActive(); err == nil {
+				primaryKey, _ = metadata.GetPrimaryKeyColumns(context.Background(), conn.Pool, view.Schema, view.Table)
+			}
+		}
+		msg.PrimaryKey = primaryKey
+		return msg
+	}
+}
+
+// clearFilter drops the active filter and reloads the table unfiltered from
+// the first row, keeping its sort
+func (a *App) clearFilter() tea.Cmd {
+	if a.activeFilter == nil {
+		return a.ShowToast("No filter to clear")
+	}
+
+	if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeTableData && tab.Structure != nil {
+		parts := strings.SplitN(tab.ObjectID, ".", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		a.SetActiveFilter(nil)
+		tableView := tab.Structure.GetTableView()
+		view := models.FavoriteView{
+			Schema:     parts[0],
+			Table:      parts[1],
+			SortColumn: tableView.GetSortColumn(),
+			SortDir:    tableView.GetSortDirection(),
+			NullsFirst: tableView.GetNullsFirst(),
+		}
+		tableView.IsLoading = true
+		tableView.LoadingStart = time.Now()
+		return tea.Batch(a.loadTableViewForTab(view, tab.ObjectID, 100), a.executeSpinner.Tick)
+	}
+
+	node := a.state.TreeSelected
+	if node == nil || node.Parent == nil {
+		return nil
+	}
+	a.SetActiveFilter(nil)
+	return a.loadTableData(messages.LoadTableDataMsg{
+		Schema:     node.Parent.Label,
+		Table:      node.Label,
+		Limit:      100,
+		Offset:     0,
+		SortColumn: a.tableView.GetSortColumn(),
+		SortDir:    a.tableView.GetSortDirection(),
+		NullsFirst: a.tableView.GetNullsFirst(),
+	})
+}
+
+// syncActiveFilter makes the active filter the one the active tab was loaded
+// with, so the filter indicator and the filter keys follow tab switches.
+// Without tabs the active filter is left alone.
+func (a *App) syncActiveFilter() {
+	if !a.resultTabs.HasTabs() {
+		return
+	}
+	a.activeFilter = nil
+	if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeTableData {
+		a.activeFilter = tab.Filter
+	}
+}
+
+// loadCSVImportColumns loads the columns of schema.table a CSV import can
+// fill, to open the import dialog
+func (a *App) loadCSVImportColumns(schema, table string) tea.Cmd {
+	return func() tea.Msg {
+		loaded := messages.CSVImportColumnsLoadedMsg{Schema: schema, Table: table}
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			loaded.Err = err
+			return loaded
+		}
+		columns, err := metadata.GetTableColumns(context.Background(), conn.Pool, schema, table)
+		if err != nil {
+			loaded.Err = err
+			return loaded
+		}
+		// COPY can't write generated columns, Postgres computes them
+		for _, col := range columns {
+			if !col.Generated {
+				loaded.Columns = append(loaded.Columns, col.Name)
+			}
+		}
+		return loaded
+	}
+}
 
-	structure.SetMetadataLoading()
-	return a.loadStructureMetadata(parts[0], parts[1], objectID)
+// columnSizes loads the columns of schema.table and runs the query
+// reporting their sizes, over a sample of rows unless fullScan is set
+func (a *App) columnSizes(schema, table string, fullScan bool) tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.ErrorMsg{Title: "No Connection", Message: err.Error()}
+		}
+		columns, err := metadata.GetTableColumns(context.Background(), conn.Pool, schema, table)
+		if err != nil {
+			return messages.ErrorMsg{Title: "Column Sizes", Message: fmt.Sprintf("Failed to load columns for %s.%s:\n\n%v", schema, table, err)}
+		}
+		if len(columns) == 0 {
+			return messages.ErrorMsg{Title: "Column Sizes", Message: fmt.Sprintf("%s.%s has no columns", schema, table)}
+		}
+		names := make([]string, len(columns))
+		for i, col := range columns {
+			names[i] = col.Name
+		}
+		sampleRows := metadata.ColumnSizeSampleRows
+		if fullScan {
+			sampleRows = 0
+		}
+		return components.ExecuteQueryMsg{SQL: metadata.ColumnSizesSQL(schema, table, names, sampleRows)}
+	}
 }
 
 // loadTableDataWithFilter loads table data with an applied filter
 func (a *App) loadTableDataWithFilter(filter models.Filter) tea.Cmd {
+	// With tabs open the rows and the filter go to the active table tab,
+	// which keeps them across tab switches
+	var objectID, schema, table string
+	if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeTableData {
+		if parts := strings.SplitN(tab.ObjectID, ".", 2); len(parts) == 2 {
+			objectID, schema, table = tab.ObjectID, parts[0], parts[1]
+		}
+	}
+
 	return func() tea.Msg {
 		conn, err := a.connectionManager.GetActive()
 		if err != nil {
 			return messages.ErrorMsg{Title: "Connection Error", Message: err.Error()}
 		}
 
-		node := a.state.TreeSelected
-		if node == nil || node.Type != models.TreeNodeTypeTable {
-			return messages.ErrorMsg{Title: "Error", Message: "No table selected"}
-		}
+		if objectID == "" {
+			node := a.state.TreeSelected
+			if node == nil || node.Type != models.TreeNodeTypeTable {
+				return messages.ErrorMsg{Title: "Error", Message: "No table selected"}
+			}
 
-		// Get schema from parent node
-		schemaNode := node.Parent
-		if schemaNode == nil {
-			return messages.ErrorMsg{Title: "Error", Message: "Cannot determine schema"}
+			// Get schema from parent node
+			if node.Parent == nil {
+				return messages.ErrorMsg{Title: "Error", Message: "Cannot determine schema"}
+			}
+			schema, table = node.Parent.Label, node.Label
 		}
 
 		// Build filtered query
@@ -4346,8 +7917,8 @@ func (a *App) loadTableDataWithFilter(filter models.Filter) tea.Cmd {
 		// Construct query
 		query := fmt.Sprintf(
 			`SELECT * FROM "%s"."%s" %s LIMIT 100`,
-			schemaNode.Label,
-			node.Label,
+			schema,
+			table,
 			whereClause,
 		)
 
@@ -4357,32 +7928,120 @@ func (a *App) loadTableDataWithFilter(filter models.Filter) tea.Cmd {
 			return messages.ErrorMsg{Title: "Query Error", Message: err.Error()}
 		}
 
+		// Count the matching and all rows, so pages past the first load and
+		// the status line can tell how much the filter narrowed
+		var matching, unfiltered int
+		if objectID != "" {
+			matching, unfiltered = a.countFilteredRows(conn.Pool, schema, table, filter)
+		}
+
 		// Convert to string rows for display
 		var rows [][]string
 		for _, row := range result.Rows {
 			var strRow []string
-			for _, col := range result.Columns {
-				val := row[col]
-				if val == nil {
-					strRow = append(strRow, "NULL")
-				} else {
-					strRow = append(strRow, fmt.Sprintf("%v", val))
-				}
+			for i, col := range result.Columns {
+				strRow = append(strRow, connection.FormatValue(row[col], result.ColumnOIDs[i]))
 			}
 			rows = append(rows, strRow)
 		}
 
+		if objectID != "" {
+			return messages.TabTableDataLoadedMsg{
+				ObjectID:    objectID,
+				Schema:      schema,
+				Table:       table,
+				Columns:     result.Columns,
+				ColumnKinds: conn.Pool.ColumnKinds(context.Background(), result.ColumnOIDs),
+				Rows:        rows,
+				TotalRows:   max(matching, len(rows)),
+				SQL:         query,
+				Filter:      &filter,
+
+				UnfilteredRows: unfiltered,
+			}
+		}
+
 		return messages.TableDataLoadedMsg{
-			Columns:   result.Columns,
-			Rows:      rows,
-			TotalRows: len(rows),
-			Offset:    0,
+			Columns:     result.Columns,
+			ColumnKinds: conn.Pool.ColumnKinds(context.Background(), result.ColumnOIDs),
+			Rows:        rows,
+			TotalRows:   len(rows),
+			Offset:      0,
+			SQL:         query,
+		}
+	}
+}
+
+// countFilteredRows counts the rows of schema.table that match filter and
+// all of them. Both are 0 if either count fails.
+func (a *App) countFilteredRows(pool *connection.Pool, schema, table string, filter models.Filter) (matching, unfiltered int) {
+	builder := filterBuilder.NewBuilder()
+	count := func(f models.Filter) (int, bool) {
+		query, args, err := builder.BuildCount(schema, table, f)
+		if err != nil {
+			return 0, false
+		}
+		row, err := pool.QueryRow(context.Background(), query, args...)
+		if err != nil {
+			return 0, false
+		}
+		n, ok := row["count"].(int64)
+		return int(n), ok
+	}
+
+	matching, ok := count(filter)
+	if !ok {
+		return 0, 0
+	}
+	unfiltered, ok = count(models.Filter{})
+	if !ok {
+		return 0, 0
+	}
+	return matching, unfiltered
+}
+
+// filterTable returns the table the active filter applies to: the active
+// table tab's, or else the table selected in the tree
+func (a *App) filterTable() (schema, table string, ok bool) {
+	if tab := a.resultTabs.GetActiveTab(); tab != nil && tab.Type == components.TabTypeTableData {
+		if parts := strings.SplitN(tab.ObjectID, ".", 2); len(parts) == 2 {
+			return parts[0], parts[1], true
+		}
+	}
+	node := a.state.TreeSelected
+	if node == nil || node.Type != models.TreeNodeTypeTable {
+		return "", "", false
+	}
+	schema = models.GetSchemaFromNode(node)
+	return schema, node.Label, schema != ""
+}
+
+// countWithFilter counts the rows of schema.table that match a filter,
+// without loading them
+func (a *App) countWithFilter(schema, table string, filter models.Filter) tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.ErrorMsg{Title: "Connection Error", Message: err.Error()}
+		}
+
+		query, args, err := filterBuilder.NewBuilder().BuildCount(schema, table, filter)
+		if err != nil {
+			return messages.ErrorMsg{Title: "Filter Error", Message: err.Error()}
+		}
+
+		row, err := conn.Pool.QueryRow(context.Background(), query, args...)
+		if err != nil {
+			return messages.FilterCountLoadedMsg{Schema: schema, Table: table, Err: err}
 		}
+		count, _ := row["count"].(int64)
+		return messages.FilterCountLoadedMsg{Schema: schema, Table: table, Count: count}
 	}
 }
 
 // ShowError displays an error overlay with the given title and message
 func (a *App) ShowError(title, message string) {
+	a.errorLog.Add(title, message, time.Now())
 	a.errorOverlay.SetError(title, message)
 	a.showError = true
 }
@@ -4392,6 +8051,42 @@ func (a *App) DismissError() {
 	a.showError = false
 }
 
+// ShowToast shows a transient notice and returns the command that hides it
+func (a *App) ShowToast(message string) tea.Cmd {
+	return a.toasts.Push(message)
+}
+
+// overlayToasts renders the toast stack in the bottom-right corner of
+// background, just above the bottom bar
+func (a *App) overlayToasts(background string) string {
+	toastView := a.toasts.View(a.state.Width - 4)
+	if toastView == "" {
+		return background
+	}
+	toastLines := strings.Split(toastView, "\n")
+	bgLines := strings.Split(background, "\n")
+
+	// Bottom bar is 3 lines tall (border + content)
+	startY := len(bgLines) - 3 - len(toastLines)
+	if startY < 0 {
+		startY = 0
+	}
+
+	for i, line := range toastLines {
+		y := startY + i
+		if y >= len(bgLines) {
+			break
+		}
+		startX := a.state.Width - 2 - lipgloss.Width(line)
+		if startX < 0 {
+			startX = 0
+		}
+		bgLines[y] = a.overlayLine(bgLines[y], line, startX)
+	}
+
+	return strings.Join(bgLines, "\n")
+}
+
 // overlayCommandPalette renders the command palette as an overlay on top of background
 func (a *App) overlayCommandPalette(background string) string {
 	paletteView := a.commandPalette.View()
@@ -4470,6 +8165,108 @@ func (a *App) overlaySearchInput(background string) string {
 	return strings.Join(result, "\n")
 }
 
+// overlayContextMenu renders the context menu over background at the
+// position it was opened at, kept on screen
+func (a *App) overlayContextMenu(background string) string {
+	menuLines := strings.Split(a.contextMenu.View(), "\n")
+	bgLines := strings.Split(background, "\n")
+	startX, startY := a.contextMenu.Position(a.state.Width, len(bgLines))
+
+	for i, line := range menuLines {
+		y := startY + i
+		if y >= len(bgLines) {
+			break
+		}
+		bgLines[y] = a.overlayLine(bgLines[y], line, startX)
+	}
+
+	return strings.Join(bgLines, "\n")
+}
+
+// runContextMenuAction carries out a tree context menu action on node
+func (a *App) runContextMenuAction(action components.ContextMenuAction, node *models.TreeNode) tea.Cmd {
+	if node == nil {
+		return nil
+	}
+	switch action {
+	case components.ContextMenuOpen:
+		return func() tea.Msg { return components.TreeNodeSelectedMsg{Node: node} }
+
+	case components.ContextMenuCopyName:
+		name := components.ContextMenuNodeName(node)
+		if err := clipboard.WriteAll(name); err != nil {
+			a.ShowError("Copy Failed", fmt.Sprintf("Failed to copy to clipboard:\n\n%v", err))
+			return nil
+		}
+		return a.ShowToast(fmt.Sprintf("Copied %s", name))
+
+	case components.ContextMenuCopyDDL:
+		load := a.LoadObjectDetails(node)
+		if load == nil {
+			return nil
+		}
+		// Load the definition as if opening it, but copy it instead
+		return func() tea.Msg {
+			details, _ := load().(messages.ObjectDetailsLoadedMsg)
+			return messages.ObjectDDLLoadedMsg{Details: details}
+		}
+
+	case components.ContextMenuOpenDefinition:
+		load := a.LoadObjectDetails(node)
+		if load == nil {
+			return nil
+		}
+		a.isLoadingObjectDetails = true
+		return tea.Batch(load, a.executeSpinner.Tick)
+
+	case components.ContextMenuRefreshChildren:
+		// Drop the loaded children so they are not added twice
+		node.Children = nil
+		node.Loaded = false
+		return func() tea.Msg { return messages.LoadNodeChildrenMsg{NodeID: node.ID} }
+
+	case components.ContextMenuReloadTree:
+		return func() tea.Msg { return messages.LoadTreeMsg{} }
+
+	case components.ContextMenuTruncate:
+		return a.openTruncate(node)
+
+	case components.ContextMenuViewStats:
+		schema := models.GetSchemaFromNode(node)
+		if schema == "" {
+			return nil
+		}
+		if a.state.ActiveConnection == nil {
+			a.ShowError("No Connection", "Please connect to a database first")
+			return nil
+		}
+		if a.resultTabs.HasPendingQuery() {
+			return a.ShowToast("A query is already running (Esc to cancel)")
+		}
+		sql := tableStatsSQL(schema, node.Label)
+		a.resultTabs.StartPendingQuery(sql)
+		return tea.Batch(
+			a.executeSpinner.Tick,
+			a.ExecuteQuery(sql, ""),
+		)
+	}
+	return nil
+}
+
+// tableStatsSQL returns the query View stats runs: activity counters,
+// vacuum and analyze times and size of a table or materialized view
+func tableStatsSQL(schema, table string) string {
+	literal := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+	return fmt.Sprintf(`SELECT schemaname, relname,
+       n_live_tup, n_dead_tup,
+       seq_scan, idx_scan,
+       n_tup_ins, n_tup_upd, n_tup_del, n_tup_hot_upd,
+       last_vacuum, last_autovacuum, last_analyze, last_autoanalyze,
+       pg_size_pretty(pg_total_relation_size(relid)) AS total_size
+FROM pg_catalog.pg_stat_all_tables
+WHERE schemaname = %s AND relname = %s`, literal(schema), literal(table))
+}
+
 // overlayLine overlays foreground onto background at given x position
 // Handles ANSI escape sequences correctly
 func (a *App) overlayLine(background, foreground string, startX int) string {
@@ -4506,6 +8303,74 @@ type SearchTableResultMsg struct {
 	Err     error
 }
 
+// importCSV streams CSV records into a table via COPY FROM STDIN,
+// reporting progress on a.csvImportProgress until it finishes
+func (a *App) importCSV(ctx context.Context, msg components.CSVImportMsg) tea.Cmd {
+	progress, done := a.csvImportProgress, a.csvImportDone
+	return func() tea.Msg {
+		defer close(done)
+
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.CSVImportResultMsg{Schema: msg.Schema, Table: msg.Table, DryRun: msg.DryRun, Err: fmt.Errorf("no active connection: %w", err)}
+		}
+
+		result, err := metadata.CopyCSVToTable(ctx, conn.Pool, msg.Records, metadata.CSVImportOptions{
+			Schema:    msg.Schema,
+			Table:     msg.Table,
+			Columns:   msg.Columns,
+			Mapping:   msg.Mapping,
+			HasHeader: msg.HasHeader,
+			DryRun:    msg.DryRun,
+			Progress: func(p metadata.CopyProgress) {
+				// Keep only the latest update so the copy never waits on the UI
+				select {
+				case progress <- p:
+				default:
+					select {
+					case <-progress:
+					default:
+					}
+					select {
+					case progress <- p:
+					default:
+					}
+				}
+			},
+		})
+		if ctx.Err() != nil {
+			return messages.CSVImportResultMsg{Schema: msg.Schema, Table: msg.Table, DryRun: msg.DryRun, Cancelled: true}
+		}
+		if err != nil {
+			return messages.CSVImportResultMsg{Schema: msg.Schema, Table: msg.Table, DryRun: msg.DryRun, Err: err}
+		}
+
+		return messages.CSVImportResultMsg{
+			Schema:     msg.Schema,
+			Table:      msg.Table,
+			RowsCopied: result.RowsCopied,
+			DryRun:     result.DryRun,
+		}
+	}
+}
+
+// waitForCSVImportProgress waits for the next progress update from a running
+// CSV import. It returns nil once the import has finished.
+func (a *App) waitForCSVImportProgress() tea.Cmd {
+	progress, done := a.csvImportProgress, a.csvImportDone
+	if progress == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		select {
+		case p := <-progress:
+			return messages.CSVImportProgressMsg{Rows: p.Rows, Bytes: p.Bytes}
+		case <-done:
+			return nil
+		}
+	}
+}
+
 // searchTable executes a table-wide search
 func (a *App) searchTable(query string) tea.Cmd {
 	return func() tea.Msg {
@@ -4540,43 +8405,152 @@ func (a *App) searchTable(query string) tea.Cmd {
 
 // openExternalEditor opens the content in an external editor
 func (a *App) openExternalEditor(content string) tea.Cmd {
-	return func() tea.Msg {
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vim"
+	editorArgs, err := resolveExternalEditor()
+	if err != nil {
+		return func() tea.Msg {
+			return components.ExternalEditorResultMsg{Error: err}
 		}
+	}
 
-		// Create temp file
-		tmpFile, err := os.CreateTemp("", "lazypg-*.sql")
-		if err != nil {
+	// Create temp file
+	tmpFile, err := os.CreateTemp("", "lazypg-*.sql")
+	if err != nil {
+		return func() tea.Msg {
 			return components.ExternalEditorResultMsg{Error: err}
 		}
-		defer func() { _ = os.Remove(tmpFile.Name()) }()
+	}
+	tmpPath := tmpFile.Name()
 
-		// Write content
-		if _, err := tmpFile.WriteString(content); err != nil {
+	// Write content
+	if _, err := tmpFile.WriteString(content); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return func() tea.Msg {
 			return components.ExternalEditorResultMsg{Error: err}
 		}
-		_ = tmpFile.Close()
+	}
+	_ = tmpFile.Close()
 
-		// Open editor
-		cmd := exec.Command(editor, tmpFile.Name())
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	// Suspend the TUI (leaving the alt-screen) while the editor runs
+	cmd := exec.Command(editorArgs[0], append(editorArgs[1:], tmpPath)...)
+	return tea.ExecProcess(cmd, func(runErr error) tea.Msg {
+		result, readErr := os.ReadFile(tmpPath)
 
-		if err := cmd.Run(); err != nil {
-			return components.ExternalEditorResultMsg{Error: err}
+		if runErr != nil {
+			// Keep the temp file if it holds edits so they are not lost
+			if readErr == nil && string(result) != content {
+				return components.ExternalEditorResultMsg{
+					Error: fmt.Errorf("editor exited with error: %w\n\nYour edits were kept in %s", runErr, tmpPath),
+				}
+			}
+			_ = os.Remove(tmpPath)
+			return components.ExternalEditorResultMsg{Error: fmt.Errorf("editor exited with error: %w", runErr)}
+		}
+
+		_ = os.Remove(tmpPath)
+		if readErr != nil {
+			return components.ExternalEditorResultMsg{Error: readErr}
 		}
+		return components.ExternalEditorResultMsg{Content: strings.TrimRight(string(result), "\n")}
+	})
+}
+
+// openPsql suspends the TUI, leaving the alt-screen, and runs psql on the
+// connection until it exits. The password goes in a passfile readable only
+// by the user, removed when psql exits, rather than on the command line or
+// in the environment where other processes could see it.
+func (a *App) openPsql(config models.ConnectionConfig) (tea.Cmd, error) {
+	path, err := exec.LookPath("psql")
+	if err != nil {
+		return nil, fmt.Errorf("psql was not found on your PATH\n\nInstall the PostgreSQL client tools (e.g. postgresql-client) to use it from lazypg")
+	}
+
+	// psql reports itself as application_name
+	password := config.Password
+	config.Password = ""
+	config.ApplicationName = ""
+	cmd := exec.Command(path, config.URL(false))
 
-		// Read result
-		result, err := os.ReadFile(tmpFile.Name())
+	// Keep the end of psql's errors to show once the TUI is back, since
+	// the screen is redrawn over them
+	stderr := &tailBuffer{max: 2048}
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+
+	passfile := ""
+	if password != "" {
+		f, err := os.CreateTemp("", "lazypg-pgpass-*")
 		if err != nil {
-			return components.ExternalEditorResultMsg{Error: err}
+			return nil, fmt.Errorf("could not create a passfile for psql: %w", err)
+		}
+		passfile = f.Name()
+		// CreateTemp makes the file 0600, which libpq requires
+		_, err = f.WriteString(models.ConnectionConfig{Password: password}.PassfileEntry())
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(passfile)
+			return nil, fmt.Errorf("could not write a passfile for psql: %w", err)
+		}
+
+		// PGPASSWORD would win over the passfile
+		var env []string
+		for _, kv := range os.Environ() {
+			if !strings.HasPrefix(kv, "PGPASSWORD=") && !strings.HasPrefix(kv, "PGPASSFILE=") {
+				env = append(env, kv)
+			}
+		}
+		cmd.Env = append(env, "PGPASSFILE="+passfile)
+	}
+
+	return tea.ExecProcess(cmd, func(runErr error) tea.Msg {
+		if passfile != "" {
+			_ = os.Remove(passfile)
+		}
+		if runErr != nil {
+			if out := strings.TrimSpace(stderr.String()); out != "" {
+				runErr = fmt.Errorf("%w\n\n%s", runErr, out)
+			}
 		}
+		return messages.PsqlExitedMsg{Err: runErr}
+	}), nil
+}
 
-		return components.ExternalEditorResultMsg{Content: string(result)}
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	buf []byte
+	max int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return string(t.buf)
+}
+
+// resolveExternalEditor returns the editor command from $VISUAL or $EDITOR,
+// falling back to common editors found on PATH
+func resolveExternalEditor() ([]string, error) {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if args := strings.Fields(os.Getenv(env)); len(args) > 0 {
+			if _, err := exec.LookPath(args[0]); err != nil {
+				return nil, fmt.Errorf("$%s is set to %q, but it was not found: %w", env, args[0], err)
+			}
+			return args, nil
+		}
+	}
+	for _, name := range []string{"vim", "vi", "nano"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return []string{name}, nil
+		}
 	}
+	return nil, fmt.Errorf("no editor found\n\nSet $EDITOR to your preferred editor (e.g. export EDITOR=vim)")
 }
 
 // getSchemaFromNode traverses up the tree to find the schema name
@@ -4641,6 +8615,8 @@ func (a *App) loadFunctionSource(node *models.TreeNode) tea.Cmd {
 			ObjectType: "function",
 			ObjectName: fmt.Sprintf("%s.%s(%s)", schema, source.Name, source.Arguments),
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       fmt.Sprintf("%s(%s)", source.Name, source.Arguments),
 			Title:      title,
 			Content:    content,
 		}
@@ -4674,12 +8650,68 @@ func (a *App) loadTriggerFunctionSource(node *models.TreeNode) tea.Cmd {
 			ObjectType: "trigger_function",
 			ObjectName: fmt.Sprintf("%s.%s", schema, source.Name),
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       source.Name,
 			Title:      title,
 			Content:    content,
 		}
 	}
 }
 
+// loadViewDefinition loads the query behind a view or materialized view as
+// its CREATE statement
+func (a *App) loadViewDefinition(schema, name string) tea.Cmd {
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.ObjectDetailsLoadedMsg{ObjectType: "view", Err: err}
+		}
+		if schema == "" {
+			return messages.ObjectDetailsLoadedMsg{ObjectType: "view", Err: fmt.Errorf("could not determine schema")}
+		}
+
+		ctx := context.Background()
+		view, err := metadata.GetViewDefinition(ctx, conn.Pool, schema, name)
+		if err != nil {
+			return messages.ObjectDetailsLoadedMsg{ObjectType: "view", Err: err}
+		}
+
+		objectType := "view"
+		if view.Materialized {
+			objectType = "materialized_view"
+		}
+		return messages.ObjectDetailsLoadedMsg{
+			ObjectType: objectType,
+			ObjectName: fmt.Sprintf("%s.%s", schema, view.Name),
+			ObjectID:   fmt.Sprintf("viewdef:%s.%s", schema, view.Name),
+			Schema:     schema,
+			Name:       view.Name,
+			Title:      fmt.Sprintf("%s.%s", schema, view.Name),
+			Content:    metadata.ViewDDL(*view),
+		}
+	}
+}
+
+// viewDefinition opens the definition of the view shown in the active
+// table tab. ok is false when the active tab doesn't show a view or
+// materialized view, so the key falls through.
+func (a *App) viewDefinition() (cmd tea.Cmd, ok bool) {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.Type != components.TabTypeTableData || a.treeView.Root == nil {
+		return nil, false
+	}
+	parts := strings.SplitN(tab.ObjectID, ".", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+	if a.treeView.Root.FindObject(parts[0], models.TreeNodeTypeView, parts[1]) == nil &&
+		a.treeView.Root.FindObject(parts[0], models.TreeNodeTypeMaterializedView, parts[1]) == nil {
+		return nil, false
+	}
+	a.isLoadingObjectDetails = true
+	return tea.Batch(a.loadViewDefinition(parts[0], parts[1]), a.executeSpinner.Tick), true
+}
+
 // loadSequenceDetails loads sequence properties
 func (a *App) loadSequenceDetails(node *models.TreeNode) tea.Cmd {
 	return func() tea.Msg {
@@ -4722,6 +8754,8 @@ func (a *App) loadSequenceDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "sequence",
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       details.Name,
 			Title:      fmt.Sprintf("%s.%s", schema, details.Name),
 			Content:    b.String(),
 		}
@@ -4765,6 +8799,8 @@ func (a *App) loadIndexDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "index",
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       node.Label,
 			Title:      fmt.Sprintf("%s.%s (on %s)", schema, node.Label, table),
 			Content:    content,
 		}
@@ -4808,6 +8844,8 @@ func (a *App) loadTriggerDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "trigger",
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       node.Label,
 			Title:      fmt.Sprintf("%s.%s (on %s)", schema, node.Label, table),
 			Content:    content,
 		}
@@ -4850,6 +8888,7 @@ func (a *App) loadExtensionDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "extension",
 			ObjectID:   objectID,
+			Name:       details.Name,
 			Title:      details.Name,
 			Content:    b.String(),
 		}
@@ -4891,6 +8930,8 @@ func (a *App) loadCompositeTypeDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "type",
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       details.Name,
 			Title:      fmt.Sprintf("%s.%s (composite)", schema, details.Name),
 			Content:    b.String(),
 		}
@@ -4945,6 +8986,8 @@ func (a *App) loadEnumTypeDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "type",
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       enumType.Name,
 			Title:      fmt.Sprintf("%s.%s (enum)", schema, enumType.Name),
 			Content:    b.String(),
 		}
@@ -4988,6 +9031,8 @@ func (a *App) loadDomainTypeDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "type",
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       details.Name,
 			Title:      fmt.Sprintf("%s.%s (domain)", schema, details.Name),
 			Content:    b.String(),
 		}
@@ -5034,6 +9079,8 @@ func (a *App) loadRangeTypeDetails(node *models.TreeNode) tea.Cmd {
 		return messages.ObjectDetailsLoadedMsg{
 			ObjectType: "type",
 			ObjectID:   objectID,
+			Schema:     schema,
+			Name:       rangeType.Name,
 			Title:      fmt.Sprintf("%s.%s (range)", schema, rangeType.Name),
 			Content:    content,
 		}
@@ -5062,3 +9109,205 @@ func (a *App) saveObjectDefinition(msg components.SaveObjectMsg) tea.Cmd {
 		return components.ObjectSavedMsg{Success: true}
 	}
 }
+
+// exportSchema writes the tables, views, materialized views and foreign
+// tables loaded in the tree to the config directory, with their columns and foreign keys
+// fetched in one pass. Schemas whose objects haven't been loaded are left
+// out rather than fetched, so the export matches what the tree shows.
+func (a *App) exportSchema(dot bool) tea.Cmd {
+	conn, err := a.connectionManager.GetActive()
+	if err != nil {
+		return func() tea.Msg { return messages.SchemaExportedMsg{Err: err} }
+	}
+	database := conn.Config.Database
+	dbNode := a.treeView.Root.FindByID(fmt.Sprintf("db:%s", database))
+	if dbNode == nil {
+		return func() tea.Msg {
+			return messages.SchemaExportedMsg{Err: fmt.Errorf("database %s is not loaded in the tree", database)}
+		}
+	}
+
+	kinds := map[models.TreeNodeType]string{
+		models.TreeNodeTypeTable:            "table",
+		models.TreeNodeTypeView:             "view",
+		models.TreeNodeTypeMaterializedView: "materialized view",
+		models.TreeNodeTypeForeignTable:     "foreign table",
+	}
+	schemaExport := export.SchemaExport{Database: database}
+	var schemas []string
+	for _, schemaNode := range dbNode.Children {
+		if schemaNode.Type != models.TreeNodeTypeSchema || !schemaNode.Loaded {
+			continue
+		}
+		schemas = append(schemas, schemaNode.Label)
+		for _, group := range schemaNode.Children {
+			for _, node := range group.Children {
+				if kind, ok := kinds[node.Type]; ok {
+					schemaExport.Objects = append(schemaExport.Objects, export.SchemaObject{
+						Schema: schemaNode.Label,
+						Name:   node.Label,
+						Kind:   kind,
+					})
+				}
+			}
+		}
+	}
+
+	name, content := "schema.txt", export.SchemaExport.Outline
+	if dot {
+		name, content = "schema.dot", export.SchemaExport.DOT
+	}
+	path := filepath.Join(a.configDir, name)
+
+	return func() tea.Msg {
+		if len(schemaExport.Objects) == 0 {
+			return messages.SchemaExportedMsg{Err: fmt.Errorf("no tables or views are loaded in the tree")}
+		}
+		ctx := context.Background()
+		columns, err := metadata.ListSchemaColumns(ctx, conn.Pool, schemas)
+		if err != nil {
+			return messages.SchemaExportedMsg{Err: err}
+		}
+		for i, obj := range schemaExport.Objects {
+			schemaExport.Objects[i].Columns = columns[obj.Schema+"."+obj.Name]
+		}
+		schemaExport.ForeignKeys, err = metadata.ListForeignKeys(ctx, conn.Pool, schemas)
+		if err != nil {
+			return messages.SchemaExportedMsg{Err: err}
+		}
+
+		if err := os.WriteFile(path, []byte(content(schemaExport)), 0o644); err != nil {
+			return messages.SchemaExportedMsg{Err: err}
+		}
+		return messages.SchemaExportedMsg{Path: path, Objects: len(schemaExport.Objects)}
+	}
+}
+
+// activePlan returns the EXPLAIN plan in the active tab: the server's JSON
+// as it was returned, empty for a text plan, and the plan as text. For a
+// JSON plan the text is rendered from it in the style of FORMAT TEXT, and
+// is empty if that fails. ok is false if the active tab isn't a plan.
+func (a *App) activePlan() (planJSON, planText string, ok bool) {
+	tab := a.resultTabs.GetActiveTab()
+	if tab == nil || tab.CodeEditor == nil {
+		return "", "", false
+	}
+	switch tab.CodeEditor.ObjectType {
+	case "query_plan":
+		return "", components.PlanText(tab.Result), true
+	case "query_plan_json":
+		planJSON = components.PlanText(tab.Result)
+		planText, err := export.PlanTextFromJSON(planJSON)
+		if err != nil {
+			log.Printf("Warning: failed to render plan as text: %v", err)
+		}
+		return planJSON, planText, true
+	default:
+		return "", "", false
+	}
+}
+
+// savePlan writes a plan to the config directory, as plan.json when the
+// server's JSON is available and always as plan.txt, and returns the paths
+// written
+func (a *App) savePlan(planJSON, planText string) ([]string, error) {
+	var paths []string
+	files := []struct{ name, content string }{{"plan.json", planJSON}, {"plan.txt", planText}}
+	for _, file := range files {
+		if file.content == "" {
+			continue
+		}
+		path := filepath.Join(a.configDir, file.name)
+		if err := os.WriteFile(path, []byte(file.content+"\n"), 0o644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// loadDatabases lists the databases on the active connection's server, to
+// pick one to switch to
+func (a *App) loadDatabases() tea.Cmd {
+	if a.state.ActiveConnection == nil {
+		a.ShowError("Not Connected", "Connect to a server first to switch between its databases.")
+		return nil
+	}
+	return func() tea.Msg {
+		conn, err := a.connectionManager.GetActive()
+		if err != nil {
+			return messages.DatabasesLoadedMsg{Err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		databases, err := metadata.ListDatabases(ctx, conn.Pool)
+		return messages.DatabasesLoadedMsg{Databases: databases, Err: err}
+	}
+}
+
+// onActiveServer reports whether config connects to the active
+// connection's server as the same user
+func (a *App) onActiveServer(config models.ConnectionConfig) bool {
+	active := a.state.ActiveConnection.Config
+	return config.Host == active.Host && config.Port == active.Port && config.User == active.User
+}
+
+// switchDatabase makes database on the active server the active
+// connection. The previous connection stays open, so switching back to a
+// database whose pool is still open is instant. Otherwise a new pool is
+// opened with the same credentials: the password in use, or failing that
+// the one saved for the target database.
+func (a *App) switchDatabase(database string) tea.Cmd {
+	if a.state.ActiveConnection == nil {
+		return nil
+	}
+	current := a.state.ActiveConnection.Config
+	if database == current.Database {
+		return a.ShowToast("Already connected to " + database)
+	}
+
+	for _, conn := range a.connectionManager.GetAll() {
+		if conn.Pool == nil || !conn.Connected || conn.Config.Database != database || !a.onActiveServer(conn.Config) {
+			continue
+		}
+		if err := a.connectionManager.SetActive(conn.ID); err != nil {
+			break
+		}
+		// Notifications belong to the previous connection
+		a.CloseListener()
+		a.SetActiveConnection(&models.Connection{
+			ID:          conn.ID,
+			Config:      conn.Config,
+			Connected:   conn.Connected,
+			ConnectedAt: conn.ConnectedAt,
+			LastPing:    conn.LastPing,
+			// Roles are cluster-wide, and this is the same user on the same server
+			Superuser: a.state.ActiveConnection.Superuser,
+		})
+		return tea.Batch(
+			a.ShowToast("Switched to "+database),
+			func() tea.Msg { return messages.LoadTreeMsg{} },
+		)
+	}
+
+	config := current
+	config.Database = database
+	// A connection's name is its ID in the connection manager; clearing it
+	// keeps the previous database's pool open alongside the new one
+	config.Name = ""
+	if config.Password == "" && a.connectionHistory != nil {
+		saved := a.connectionHistory.GetConnectionConfigWithPassword(&models.ConnectionHistoryEntry{
+			Host:     config.Host,
+			Port:     config.Port,
+			Database: database,
+			User:     config.User,
+			SSLMode:  config.SSLMode,
+		})
+		if !saved.PasswordMissing {
+			config.Password = saved.Config.Password
+		}
+	}
+	return func() tea.Msg {
+		return messages.ConnectionStartMsg{Config: config}
+	}
+}
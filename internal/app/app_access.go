@@ -10,7 +10,9 @@ import (
 	"github.com/rebelice/lazypg/internal/app/delegates"
 	"github.com/rebelice/lazypg/internal/app/messages"
 	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/db/metadata"
 	"github.com/rebelice/lazypg/internal/db/query"
+	"github.com/rebelice/lazypg/internal/history"
 	"github.com/rebelice/lazypg/internal/models"
 	"github.com/rebelice/lazypg/internal/ui/components"
 )
@@ -27,6 +29,26 @@ func (a *App) GetState() *models.AppState {
 	return &a.state
 }
 
+// RecordRecentObject adds a selected tree node to the recent objects list
+func (a *App) RecordRecentObject(node *models.TreeNode) {
+	if node != nil {
+		a.recentObjects.Add(node.ID)
+	}
+}
+
+// PruneRecentObjects drops recent objects that are no longer in the tree
+func (a *App) PruneRecentObjects() {
+	root := a.treeView.Root
+	a.recentObjects.Prune(func(id string) bool {
+		return root != nil && root.FindByID(id) != nil
+	})
+}
+
+// SetSchemaObjects stores the objects the command palette searches
+func (a *App) SetSchemaObjects(objects []metadata.SchemaObject) {
+	a.schemaObjects = objects
+}
+
 // SetFocusArea updates the current focus area
 func (a *App) SetFocusArea(area models.FocusArea) {
 	a.state.FocusArea = area
@@ -85,9 +107,15 @@ func (a *App) IsConnecting() bool {
 	return a.isConnecting
 }
 
-// SetConnecting updates the connecting state
+// SetConnecting updates the connecting state. Starting to connect resets
+// the retry count.
 func (a *App) SetConnecting(v bool) {
 	a.isConnecting = v
+	if v {
+		a.connectAttempt = 0
+		a.connectRetryErr = nil
+		a.connectRetryAt = time.Time{}
+	}
 }
 
 // GetConnectingConfig returns the config being connected to
@@ -105,6 +133,11 @@ func (a *App) SetConnectingStart(t time.Time) {
 	a.connectingStart = t
 }
 
+// GetConnectingStart returns when the connection attempt started
+func (a *App) GetConnectingStart() time.Time {
+	return a.connectingStart
+}
+
 // SetShowConnectionDialog shows/hides the connection dialog
 func (a *App) SetShowConnectionDialog(show bool) {
 	a.showConnectionDialog = show
@@ -127,14 +160,86 @@ func (a *App) SetPendingConnectionInfo(entry *models.ConnectionHistoryEntry) {
 
 // ConnectAsync initiates an async connection
 func (a *App) ConnectAsync(config models.ConnectionConfig) tea.Cmd {
-	return func() tea.Msg {
-		connID, err := a.connectionManager.Connect(context.Background(), config)
-		return messages.ConnectionResultMsg{
-			Config: config,
-			ConnID: connID,
-			Err:    err,
-		}
+	return a.connectAsync(config)
+}
+
+// RetryConnection schedules another attempt after a transient failure
+func (a *App) RetryConnection(config models.ConnectionConfig, err error) tea.Cmd {
+	return a.retryConnection(config, err)
+}
+
+// RecoverConnectionFailure prompts for missing credentials after a failed
+// connection to a discovered instance
+func (a *App) RecoverConnectionFailure(config models.ConnectionConfig, err error) (bool, tea.Cmd) {
+	return a.recoverDiscoveredConnection(config, err)
+}
+
+// CloseListener stops LISTEN/NOTIFY on the previous connection
+func (a *App) CloseListener() {
+	a.closeListener("disconnected")
+}
+
+// WarnSuperuser warns that config connected as a superuser, once per user
+// and server this session
+func (a *App) WarnSuperuser(config models.ConnectionConfig) tea.Cmd {
+	if a.config != nil && !a.config.Connection.WarnSuperuser {
+		return nil
+	}
+	key := fmt.Sprintf("%s@%s:%d", config.User, config.Host, config.Port)
+	if a.superuserWarned[key] {
+		return nil
 	}
+	if a.superuserWarned == nil {
+		a.superuserWarned = make(map[string]bool)
+	}
+	a.superuserWarned[key] = true
+	return a.ShowToast(fmt.Sprintf("Connected as superuser %s: no permission checks stop mistakes", config.User))
+}
+
+// HandleSessionLoss checks whether err means a connection's session was
+// terminated or its database dropped (connectionID empty for the active
+// connection). If so the connection is dropped, the tree and tabs loaded
+// from it are closed, and the user is offered to reconnect.
+func (a *App) HandleSessionLoss(err error, connectionID string) bool {
+	loss := connection.ClassifySessionLoss(err)
+	if loss == connection.SessionAlive {
+		return false
+	}
+	// Requests in flight on the lost session fail one after another
+	if a.showSessionLost {
+		return true
+	}
+
+	id := connectionID
+	if id == "" && a.state.ActiveConnection != nil {
+		id = a.state.ActiveConnection.ID
+	}
+	conn, getErr := a.connectionManager.Get(id)
+	if getErr != nil {
+		// Already dropped, or nothing to drop
+		return false
+	}
+	a.lostConnection = conn.Config
+	_ = a.connectionManager.Disconnect(id)
+
+	wasActive := a.state.ActiveConnection != nil && a.state.ActiveConnection.ID == id
+	if wasActive {
+		a.closeListener("session lost")
+		a.state.ActiveConnection = nil
+		a.state.TreeSelected = nil
+		a.treeView.Root = nil
+		a.treeView.IsLoading = false
+		a.activeFilter = nil
+		a.resultTabs.CloseObjectTabs()
+	}
+	// Query tabs run on the connection from the command palette can't be
+	// rerun without it
+	a.resultTabs.CloseConnectionTabs(id)
+
+	a.sessionLostPrompt.Open(id, conn.Config.Database, loss, err, wasActive)
+	a.errorLog.Add(a.sessionLostPrompt.Title(), err.Error(), time.Now())
+	a.showSessionLost = true
+	return true
 }
 
 // TriggerDiscovery starts instance discovery
@@ -165,8 +270,7 @@ func (a *App) AddToConnectionHistory(config models.ConnectionConfig) {
 				log.Printf("Warning: Failed to save password: %v", result.PasswordSaveError)
 			}
 			// Reload history in dialog
-			history := a.connectionHistory.GetRecent(10)
-			a.connectionDialog.SetHistoryEntries(history)
+			a.refreshConnectionDialogHistory()
 		}
 	}
 }
@@ -236,6 +340,8 @@ func (a *App) LoadObjectDetails(node *models.TreeNode) tea.Cmd {
 		return a.loadDomainTypeDetails(node)
 	case models.TreeNodeTypeRangeType:
 		return a.loadRangeTypeDetails(node)
+	case models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView:
+		return a.loadViewDefinition(a.getSchemaFromNode(node), node.Label)
 	default:
 		return nil
 	}
@@ -251,35 +357,100 @@ func (a *App) SetActiveFilter(filter *models.Filter) {
 	a.activeFilter = filter
 }
 
+// SyncActiveFilter makes the active filter follow the active tab
+func (a *App) SyncActiveFilter() {
+	a.syncActiveFilter()
+}
+
 // =============================================================================
 // QueryAccess implementation
 // =============================================================================
 
-// ExecuteQuery executes a SQL query asynchronously
-func (a *App) ExecuteQuery(sql string) tea.Cmd {
+// ExecuteQuery executes a SQL query asynchronously on the connection with
+// connectionID, or on the active connection if it is empty. The result is
+// tagged with the pending tab it was started in.
+func (a *App) ExecuteQuery(sql, connectionID string) tea.Cmd {
 	// Create cancellable context for query execution
+	pendingID := a.resultTabs.PendingID()
 	ctx, cancel := context.WithCancel(context.Background())
 	a.executeCancelFn = cancel
 
 	return func() tea.Msg {
-		conn, err := a.connectionManager.GetActive()
+		conn, err := a.queryConnection(connectionID)
 		if err != nil {
 			return messages.QueryResultMsg{
 				SQL: sql,
 				Result: models.QueryResult{
 					Error: fmt.Errorf("failed to get connection: %w", err),
 				},
+				ConnectionID: connectionID,
+				PendingID:    pendingID,
 			}
 		}
 
 		result := query.Execute(ctx, conn.Pool.GetPool(), sql)
 		return messages.QueryResultMsg{
-			SQL:    sql,
-			Result: result,
+			SQL:          sql,
+			Result:       result,
+			ConnectionID: connectionID,
+			PendingID:    pendingID,
 		}
 	}
 }
 
+// queryConnection returns the connection with id, or the active connection
+// if id is empty. Running a query on another connection never makes it the
+// active one.
+func (a *App) queryConnection(id string) (*connection.Connection, error) {
+	if id == "" {
+		return a.connectionManager.GetActive()
+	}
+	return a.connectionManager.Get(id)
+}
+
+// SafeMode reports whether data-modifying statements wait to be committed
+func (a *App) SafeMode() bool {
+	return a.safeMode
+}
+
+// ExecuteQueryInTransaction runs a statement in a transaction that is left
+// open for the safe mode prompt
+func (a *App) ExecuteQueryInTransaction(sql, connectionID string) tea.Cmd {
+	pendingID := a.resultTabs.PendingID()
+	ctx, cancel := context.WithCancel(context.Background())
+	a.executeCancelFn = cancel
+
+	return func() tea.Msg {
+		conn, err := a.queryConnection(connectionID)
+		if err != nil {
+			return messages.QueryResultMsg{
+				SQL: sql,
+				Result: models.QueryResult{
+					Error: fmt.Errorf("failed to get connection: %w", err),
+				},
+				ConnectionID: connectionID,
+				PendingID:    pendingID,
+			}
+		}
+
+		result, tx := query.ExecuteInTransaction(ctx, conn.Pool.GetPool(), sql)
+		return messages.QueryResultMsg{
+			SQL:          sql,
+			Result:       result,
+			Transaction:  tx,
+			ConnectionID: connectionID,
+			PendingID:    pendingID,
+		}
+	}
+}
+
+// OpenSafeModePrompt asks whether to commit a statement left in tx
+func (a *App) OpenSafeModePrompt(sql string, rowsAffected int64, tx *query.Transaction) {
+	a.pendingTx = tx
+	a.safeModePrompt.Open(sql, rowsAffected)
+	a.showSafeModePrompt = true
+}
+
 // SaveObjectDefinition saves an object definition
 func (a *App) SaveObjectDefinition(msg components.SaveObjectMsg) tea.Cmd {
 	return a.saveObjectDefinition(msg)
@@ -290,11 +461,64 @@ func (a *App) StartPendingQuery(sql string) {
 	a.resultTabs.StartPendingQuery(sql)
 }
 
+// QuickQueryLimit returns the LIMIT appended to bare SELECTs run from the SQL editor
+func (a *App) QuickQueryLimit() int {
+	if a.config == nil {
+		return 0
+	}
+	return a.config.Editor.QuickQueryLimit
+}
+
+// SQLVariables returns the session's \set variables
+func (a *App) SQLVariables() *components.SQLVariables {
+	return a.sqlVariables
+}
+
+// RecordQueryHistory saves an executed query to the query history. Errors
+// are ignored so a history problem never interrupts the user.
+func (a *App) RecordQueryHistory(sql string, result models.QueryResult, connectionID string) {
+	if a.historyStore == nil {
+		return
+	}
+
+	entry := history.HistoryEntry{
+		Query:        sql,
+		Duration:     result.Duration,
+		RowsAffected: result.RowsAffected,
+		Success:      result.Error == nil,
+	}
+	if connectionID != "" {
+		if conn, err := a.connectionManager.Get(connectionID); err == nil {
+			entry.ConnectionName = conn.Config.Name
+			entry.DatabaseName = conn.Config.Database
+		}
+	} else if conn := a.state.ActiveConnection; conn != nil {
+		entry.ConnectionName = conn.Config.Name
+		entry.DatabaseName = conn.Config.Database
+	}
+	if result.Error != nil {
+		entry.ErrorMessage = result.Error.Error()
+	}
+	_ = a.historyStore.Add(entry)
+}
+
 // CompletePendingQuery completes a pending query with results
 func (a *App) CompletePendingQuery(sql string, result models.QueryResult) {
 	a.resultTabs.CompletePendingQuery(sql, result)
 }
 
+// CompletePendingPlan completes a pending EXPLAIN with its text or JSON plan
+func (a *App) CompletePendingPlan(sql string, result models.QueryResult) {
+	objectType := "query_plan"
+	if components.IsJSONExplain(sql, result) {
+		objectType = "query_plan_json"
+	}
+	codeEditor := components.NewCodeEditor(a.theme)
+	codeEditor.SetContent(components.PlanText(result), objectType, "Query Plan")
+	codeEditor.ViewOnly = true
+	a.resultTabs.CompletePendingPlan(sql, result, codeEditor)
+}
+
 // CancelPendingQuery cancels and removes a pending query
 func (a *App) CancelPendingQuery() {
 	a.resultTabs.CancelPendingQuery()
@@ -382,9 +606,43 @@ func (a *App) IsLoadingObjectDetails() bool {
 
 // CreateTableDataTab creates a new table data tab and returns the cmd to load data
 func (a *App) CreateTableDataTab(objectID, label, schema, table string) tea.Cmd {
+	a.addTableDataTab(objectID, label, schema, table)
+
+	// Load table data asynchronously and start spinner tick
+	return tea.Batch(
+		a.loadTableDataForTab(schema, table, objectID),
+		a.executeSpinner.Tick,
+	)
+}
+
+// addTableDataTab adds a table data tab in its loading state, or activates
+// the table's existing tab and marks it loading, and focuses the data panel
+func (a *App) addTableDataTab(objectID, label, schema, table string) {
+	if tab := a.resultTabs.GetTabByObjectID(objectID); tab != nil && tab.Type == components.TabTypeTableData && tab.Structure != nil {
+		a.resultTabs.AddTableData(objectID, tab.Title, tab.Structure) // Activates it
+		tableView := tab.Structure.GetTableView()
+		tableView.IsLoading = true
+		tableView.LoadingStart = time.Now()
+		a.state.FocusArea = models.FocusDataPanel
+		a.updatePanelStyles()
+		return
+	}
+
 	// Create new StructureView for this table
 	tableView := components.NewTableView(a.theme)
 	tableView.Spinner = &a.executeSpinner
+	tableView.SetMasks(a.maskRules)
+	tableView.RowColors = a.rowColors
+	tableView.BoolGlyphs = a.boolGlyphs
+	// The tree selection describes the table only if it is the same one; a
+	// table of the same name in another schema may be selected
+	node := a.state.TreeSelected
+	if node != nil && (node.Label != table || models.GetSchemaFromNode(node) != schema) {
+		node = nil
+	}
+	if node != nil {
+		tableView.ForeignServer = components.ForeignServerOf(node)
+	}
 	structureView := components.NewStructureView(a.theme, tableView)
 
 	// Set loading state
@@ -392,27 +650,31 @@ func (a *App) CreateTableDataTab(objectID, label, schema, table string) tea.Cmd
 	tableView.LoadingStart = time.Now()
 
 	// Add as a new tab
-	a.resultTabs.AddTableData(objectID, label, structureView)
+	tableType := string(models.TreeNodeTypeTable)
+	if node != nil {
+		tableType = string(node.Type)
+	}
+	title := a.resultTabs.ObjectTitle(components.TabTitleFields{
+		Schema:   schema,
+		Name:     table,
+		Type:     tableType,
+		Database: a.state.CurrentDatabase,
+	}, label)
+	a.resultTabs.AddTableData(objectID, title, structureView)
 
 	// Switch focus immediately to show loading state
 	a.state.FocusArea = models.FocusDataPanel
 	a.updatePanelStyles()
-
-	// Load table data asynchronously and start spinner tick
-	return tea.Batch(
-		a.loadTableDataForTab(schema, table, objectID),
-		a.executeSpinner.Tick,
-	)
 }
 
 // CreateCodeEditorTab creates a new code editor tab for object details
-func (a *App) CreateCodeEditorTab(objectID, title, content, objectType, objectName string) {
+func (a *App) CreateCodeEditorTab(msg messages.ObjectDetailsLoadedMsg) {
 	codeEditor := components.NewCodeEditor(a.theme)
-	codeEditor.SetContent(content, objectType, title)
-	codeEditor.ObjectName = objectName
+	codeEditor.SetContent(msg.Content, msg.ObjectType, msg.Title)
+	codeEditor.ObjectName = msg.ObjectName
 
 	// Add as a new tab
-	a.resultTabs.AddCodeEditor(objectID, title, codeEditor)
+	a.resultTabs.AddCodeEditor(msg.ObjectID, a.codeTabTitle(msg), codeEditor)
 }
 
 // GetSpinnerTickCmd returns a command to tick the spinner
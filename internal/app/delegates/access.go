@@ -4,7 +4,10 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/app/messages"
 	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/db/query"
 	"github.com/rebelice/lazypg/internal/models"
 	"github.com/rebelice/lazypg/internal/ui/components"
 )
@@ -45,6 +48,15 @@ type StateAccess interface {
 
 	// SetActiveConnection updates the active connection
 	SetActiveConnection(conn *models.Connection)
+
+	// RecordRecentObject adds a selected tree node to the recent objects list
+	RecordRecentObject(node *models.TreeNode)
+
+	// PruneRecentObjects drops recent objects that are no longer in the tree
+	PruneRecentObjects()
+
+	// SetSchemaObjects stores the objects the command palette searches
+	SetSchemaObjects(objects []metadata.SchemaObject)
 }
 
 // ComponentAccess provides access to UI components
@@ -85,6 +97,9 @@ type ConnectionAccess interface {
 	// SetConnectingStart sets when connection attempt started
 	SetConnectingStart(t time.Time)
 
+	// GetConnectingStart returns when the connection attempt started
+	GetConnectingStart() time.Time
+
 	// ShowConnectionDialog shows/hides the connection dialog
 	SetShowConnectionDialog(show bool)
 
@@ -100,6 +115,14 @@ type ConnectionAccess interface {
 	// ConnectAsync initiates an async connection
 	ConnectAsync(config models.ConnectionConfig) tea.Cmd
 
+	// RetryConnection schedules another attempt after a transient failure,
+	// returning nil if the error is permanent or the attempts are used up
+	RetryConnection(config models.ConnectionConfig, err error) tea.Cmd
+
+	// RecoverConnectionFailure prompts for missing credentials after a failed
+	// connection, returning false if the failure is not recoverable
+	RecoverConnectionFailure(config models.ConnectionConfig, err error) (bool, tea.Cmd)
+
 	// TriggerDiscovery starts instance discovery
 	TriggerDiscovery() tea.Cmd
 
@@ -111,6 +134,20 @@ type ConnectionAccess interface {
 
 	// ClearPendingPasswordSave clears the pending password save
 	ClearPendingPasswordSave()
+
+	// CloseListener stops LISTEN/NOTIFY on the previous connection
+	CloseListener()
+
+	// WarnSuperuser warns that config connected as a superuser, the first
+	// time its user does on its server this session. It returns nil
+	// afterwards, or when connection.warn_superuser is off.
+	WarnSuperuser(config models.ConnectionConfig) tea.Cmd
+
+	// HandleSessionLoss checks whether err means a connection's session was
+	// terminated or its database dropped. If so it drops the connection,
+	// closes the tree and tabs loaded from it and offers to reconnect,
+	// returning true; otherwise the error is left to the caller.
+	HandleSessionLoss(err error, connectionID string) bool
 }
 
 // DataAccess provides data loading operations
@@ -139,14 +176,19 @@ type DataAccess interface {
 	// SetActiveFilter sets the active filter
 	SetActiveFilter(filter *models.Filter)
 
+	// SyncActiveFilter makes the active filter the one the active tab was
+	// loaded with, after switching or closing tabs
+	SyncActiveFilter()
+
 	// PrefetchData prefetches table data in background
-	PrefetchData(schema, table string, offset, limit int, sortCol, sortDir string, nullsFirst bool) tea.Cmd
+	PrefetchData(schema, table string, offset, limit int, sortCol, sortDir string, nullsFirst bool, filter *models.Filter) tea.Cmd
 }
 
 // QueryAccess provides query execution operations
 type QueryAccess interface {
-	// ExecuteQuery executes a SQL query asynchronously
-	ExecuteQuery(sql string) tea.Cmd
+	// ExecuteQuery executes a SQL query asynchronously on the connection
+	// with connectionID, or on the active connection if it is empty
+	ExecuteQuery(sql, connectionID string) tea.Cmd
 
 	// SaveObjectDefinition saves an object definition (function, view, etc.)
 	SaveObjectDefinition(msg components.SaveObjectMsg) tea.Cmd
@@ -157,6 +199,12 @@ type QueryAccess interface {
 	// CompletePendingQuery completes a pending query with results
 	CompletePendingQuery(sql string, result models.QueryResult)
 
+	// RecordQueryHistory saves an executed query to the query history
+	RecordQueryHistory(sql string, result models.QueryResult, connectionID string)
+
+	// CompletePendingPlan completes a pending EXPLAIN with its text or JSON plan
+	CompletePendingPlan(sql string, result models.QueryResult)
+
 	// CancelPendingQuery cancels and removes a pending query
 	CancelPendingQuery()
 
@@ -165,6 +213,22 @@ type QueryAccess interface {
 
 	// GetExecuteCancelFn returns the cancel function for query execution
 	GetExecuteCancelFn() func()
+
+	// QuickQueryLimit returns the LIMIT appended to bare SELECTs run from the SQL editor
+	QuickQueryLimit() int
+
+	// SQLVariables returns the session's \set variables
+	SQLVariables() *components.SQLVariables
+
+	// SafeMode reports whether data-modifying statements wait to be committed
+	SafeMode() bool
+
+	// ExecuteQueryInTransaction runs a statement in a transaction that is
+	// left open for the safe mode prompt
+	ExecuteQueryInTransaction(sql, connectionID string) tea.Cmd
+
+	// OpenSafeModePrompt asks whether to commit a statement left in tx
+	OpenSafeModePrompt(sql string, rowsAffected int64, tx *query.Transaction)
 }
 
 // UIAccess provides UI-related operations
@@ -172,6 +236,9 @@ type UIAccess interface {
 	// ShowError displays an error overlay
 	ShowError(title, message string)
 
+	// ShowToast shows a transient notice and returns the command that hides it
+	ShowToast(message string) tea.Cmd
+
 	// UpdatePanelStyles refreshes panel styling based on focus
 	UpdatePanelStyles()
 
@@ -209,7 +276,7 @@ type UIAccess interface {
 	CreateTableDataTab(objectID, label, schema, table string) tea.Cmd
 
 	// CreateCodeEditorTab creates a new code editor tab for object details
-	CreateCodeEditorTab(objectID, title, content, objectType, objectName string)
+	CreateCodeEditorTab(msg messages.ObjectDetailsLoadedMsg)
 
 	// GetSpinnerTickCmd returns a command to tick the spinner
 	GetSpinnerTickCmd() tea.Cmd
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/rebelice/lazypg/internal/app/messages"
+	"github.com/rebelice/lazypg/internal/db/connection"
 	"github.com/rebelice/lazypg/internal/models"
 	"github.com/rebelice/lazypg/internal/ui/components"
 )
@@ -44,6 +45,14 @@ func (d *ConnectionDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd)
 	case messages.ConnectionResultMsg:
 		return d.handleConnectionResult(msg, app)
 
+	case messages.ConnectionRetryMsg:
+		// Dropped if the user cancelled, or started another connection, while
+		// waiting out the backoff
+		if !app.IsConnecting() || !msg.Start.Equal(app.GetConnectingStart()) {
+			return true, nil
+		}
+		return true, app.ConnectAsync(msg.Config)
+
 	case components.PasswordSubmitMsg:
 		return d.handlePasswordSubmit(msg, app)
 
@@ -66,13 +75,23 @@ func (d *ConnectionDelegate) handleConnectionResult(msg messages.ConnectionResul
 		return true, nil
 	}
 
+	// Transient failures are tried again, staying in the connecting state
+	if msg.Err != nil {
+		if cmd := app.RetryConnection(msg.Config, msg.Err); cmd != nil {
+			return true, cmd
+		}
+	}
+
 	app.SetConnecting(false)
 
 	if msg.Err != nil {
 		// Connection failed - clear pending password (don't save wrong password)
 		app.ClearPendingPasswordSave()
-		app.ShowError("Connection Failed", fmt.Sprintf("Could not connect to %s:%d\n\nError: %v",
-			msg.Config.Host, msg.Config.Port, msg.Err))
+		if handled, cmd := app.RecoverConnectionFailure(msg.Config, msg.Err); handled {
+			return true, cmd
+		}
+		failure := connection.ClassifyConnectError(msg.Err, msg.Config)
+		app.ShowError(failure.Title(), failure.Describe(msg.Config.Host, msg.Config.Port, msg.Err))
 		return true, nil
 	}
 
@@ -81,6 +100,9 @@ func (d *ConnectionDelegate) handleConnectionResult(msg messages.ConnectionResul
 		log.Printf("Warning: Failed to save password: %v", err)
 	}
 
+	// Notifications belong to the previous connection
+	app.CloseListener()
+
 	// Update active connection in state
 	connMgr := app.GetConnectionManager()
 	if connMgr != nil {
@@ -93,6 +115,7 @@ func (d *ConnectionDelegate) handleConnectionResult(msg messages.ConnectionResul
 				ConnectedAt: conn.ConnectedAt,
 				LastPing:    conn.LastPing,
 				Error:       conn.Error,
+				Superuser:   msg.Superuser,
 			})
 		}
 	}
@@ -103,9 +126,20 @@ func (d *ConnectionDelegate) handleConnectionResult(msg messages.ConnectionResul
 	// Hide connection dialog and trigger tree loading
 	app.SetShowConnectionDialog(false)
 
-	return true, func() tea.Msg {
+	if msg.OnConnectErr != nil {
+		// The connection works, just without the session setup
+		app.ShowError("Startup SQL Failed", fmt.Sprintf(
+			"Connected to %s:%d, but connection.on_connect_sql failed:\n\n%v\n\nThe connection stays open without the settings it makes.",
+			msg.Config.Host, msg.Config.Port, msg.OnConnectErr))
+	}
+
+	loadTree := func() tea.Msg {
 		return messages.LoadTreeMsg{}
 	}
+	if msg.Superuser {
+		return true, tea.Batch(loadTree, app.WarnSuperuser(msg.Config))
+	}
+	return true, loadTree
 }
 
 // handlePasswordSubmit processes password submission from dialog.
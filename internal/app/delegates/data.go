@@ -3,6 +3,8 @@ package delegates
 import (
 	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/rebelice/lazypg/internal/app/messages"
@@ -37,13 +39,19 @@ func (d *DataDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd) {
 		return d.handleTabTableDataLoaded(msg, app)
 
 	case messages.PrefetchDataMsg:
-		return true, app.PrefetchData(msg.Schema, msg.Table, msg.Offset, msg.Limit, msg.SortColumn, msg.SortDir, msg.NullsFirst)
+		return true, app.PrefetchData(msg.Schema, msg.Table, msg.Offset, msg.Limit, msg.SortColumn, msg.SortDir, msg.NullsFirst, msg.Filter)
 
 	case messages.PrefetchCompleteMsg:
 		return d.handlePrefetchComplete(msg, app)
 
 	case messages.StructureMetadataLoadedMsg:
 		return d.handleStructureMetadataLoaded(msg, app)
+
+	case messages.ClearChangeHighlightMsg:
+		if tab := app.GetResultTabs().GetTabByObjectID(msg.ObjectID); tab != nil && tab.Structure != nil {
+			tab.Structure.GetTableView().ClearChangeHighlight(msg.Seq)
+		}
+		return true, nil
 	}
 
 	return false, nil
@@ -52,6 +60,9 @@ func (d *DataDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd) {
 // handleTableDataLoaded handles table data loading completion.
 func (d *DataDelegate) handleTableDataLoaded(msg messages.TableDataLoadedMsg, app AppAccess) (bool, tea.Cmd) {
 	if msg.Err != nil {
+		if app.HandleSessionLoss(msg.Err, "") {
+			return true, nil
+		}
 		app.ShowError("Database Error", fmt.Sprintf("Failed to load table data:\n\n%v", msg.Err))
 		return true, nil
 	}
@@ -70,14 +81,17 @@ func (d *DataDelegate) handleTableDataLoaded(msg messages.TableDataLoadedMsg, ap
 	if isInitialLoad {
 		// Initial load - replace all data
 		tableView.SetData(msg.Columns, msg.Rows, msg.TotalRows)
+		tableView.SetColumnKinds(msg.ColumnKinds)
+		tableView.GeneratedSQL = msg.SQL
 		tableView.SelectedRow = 0
 		tableView.TopRow = 0
 		app.SetFocusArea(models.FocusDataPanel)
 		app.UpdatePanelStyles()
 	} else {
 		// Append paginated data (same table, loading more rows)
-		tableView.Rows = append(tableView.Rows, msg.Rows...)
+		tableView.AppendRows(msg.Rows)
 		tableView.TotalRows = msg.TotalRows
+		tableView.GeneratedSQL = msg.SQL
 	}
 	tableView.IsPaginating = false
 	return true, nil
@@ -88,31 +102,88 @@ func (d *DataDelegate) handleTabTableDataLoaded(msg messages.TabTableDataLoadedM
 	resultTabs := app.GetResultTabs()
 
 	// Clear loading state for the tab's table view
-	if tab := resultTabs.GetTabByObjectID(msg.ObjectID); tab != nil {
+	tab := resultTabs.GetTabByObjectID(msg.ObjectID)
+	if tab != nil {
 		if sv := tab.Structure; sv != nil {
 			if tv := sv.GetTableView(); tv != nil {
 				tv.IsLoading = false
 			}
 		}
+		if msg.Refresh {
+			tab.Refreshing = false
+		}
 	}
 
 	if msg.Err != nil {
+		if app.HandleSessionLoss(msg.Err, "") {
+			return true, nil
+		}
+		if msg.Refresh && tab != nil {
+			// Stop rather than report the same failure on every tick
+			tab.RefreshInterval = 0
+			tab.RefreshSeq++
+			app.ShowError("Auto Refresh Stopped", fmt.Sprintf("Failed to refresh %s:\n\n%v", tab.Title, msg.Err))
+			return true, nil
+		}
 		app.ShowError("Database Error", fmt.Sprintf("Failed to load table data:\n\n%v", msg.Err))
 		return true, nil
 	}
 
 	// Find the tab with this objectID and update its data
+	var highlight tea.Cmd
 	for _, tab := range resultTabs.GetAllTabs() {
 		if tab.ObjectID == msg.ObjectID && tab.Type == components.TabTypeTableData {
 			if tab.Structure != nil {
 				// Set table data in the structure view
-				tab.Structure.GetTableView().SetData(msg.Columns, msg.Rows, msg.TotalRows)
+				tableView := tab.Structure.GetTableView()
+				if tab.Filter != msg.Filter || tableView.Window != msg.Window {
+					// A different filter or window is a different result
+					// set, so start at the top; a plain reload keeps the
+					// position
+					tableView.SelectedRow = 0
+					tableView.TopRow = 0
+				}
+				var changed map[int]bool
+				if msg.Refresh && msg.PrimaryKey != nil {
+					tab.PrimaryKey = msg.PrimaryKey
+				}
+				if msg.Refresh && slices.Equal(tableView.Columns, msg.Columns) {
+					// Compare with every loaded row, the ones the quick
+					// filter hides included
+					changed = components.ChangedRows(msg.Columns, tab.PrimaryKey, tableView.LoadedRows(), msg.Rows)
+					tableView.RefreshData(msg.Columns, msg.Rows, msg.TotalRows, tab.PrimaryKey)
+				} else {
+					tableView.SetData(msg.Columns, msg.Rows, msg.TotalRows)
+				}
+				if msg.UnfilteredRows != 0 || tab.Filter != msg.Filter {
+					// Loads past the first page don't count the whole
+					// table again; keep the count of the filter's first
+					// page
+					tableView.UnfilteredRows = msg.UnfilteredRows
+				}
+				tableView.Window = msg.Window
+				if len(changed) > 0 {
+					seq := tableView.HighlightChanges(changed)
+					objectID := msg.ObjectID
+					highlight = tea.Tick(components.ChangeHighlightDuration, func(time.Time) tea.Msg {
+						return messages.ClearChangeHighlightMsg{ObjectID: objectID, Seq: seq}
+					})
+				}
+				tableView.SetColumnKinds(msg.ColumnKinds)
+				tableView.SetSortByName(msg.SortColumn, msg.SortDir, msg.NullsFirst)
+				tableView.GeneratedSQL = msg.SQL
+				tab.Filter = msg.Filter
 				// Note: Structure metadata (columns, constraints, indexes) is loaded
 				// lazily when user switches to those tabs to avoid blocking the UI
 			}
 			break
 		}
 	}
+	app.SyncActiveFilter()
+	if msg.Refresh {
+		// Updated in place; leave focus where the user is
+		return true, highlight
+	}
 	app.SetFocusArea(models.FocusDataPanel)
 	app.UpdatePanelStyles()
 	return true, nil
@@ -129,10 +200,18 @@ func (d *DataDelegate) handleStructureMetadataLoaded(msg messages.StructureMetad
 
 	if msg.Err != nil {
 		log.Printf("Warning: failed to load structure metadata for %s: %v", msg.ObjectID, msg.Err)
+		tab.Structure.SetSectionError(msg.Section, msg.Err)
 		return true, nil
 	}
 
-	tab.Structure.SetMetadata(msg.Columns, msg.Constraints, msg.Indexes)
+	switch msg.Section {
+	case components.StructureTabColumns:
+		tab.Structure.SetColumns(msg.Columns)
+	case components.StructureTabConstraints:
+		tab.Structure.SetConstraints(msg.Constraints)
+	case components.StructureTabIndexes:
+		tab.Structure.SetIndexes(msg.Indexes)
+	}
 	return true, nil
 }
 
@@ -153,7 +232,8 @@ func (d *DataDelegate) handlePrefetchComplete(msg messages.PrefetchCompleteMsg,
 	}
 
 	// Append prefetched rows
-	tableView.Rows = append(tableView.Rows, msg.Rows...)
+	tableView.AppendRows(msg.Rows)
+	tableView.GeneratedSQL = msg.SQL
 
 	return true, nil
 }
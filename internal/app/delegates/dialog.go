@@ -34,7 +34,10 @@ func (d *DialogDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd) {
 		return true, nil
 
 	case components.CloseJSONBViewerMsg:
+		// The viewer is opened from a grid cell, so go back to the grid
 		app.SetShowJSONBViewer(false)
+		app.SetFocusArea(models.FocusDataPanel)
+		app.UpdatePanelStyles()
 		return true, nil
 
 	case components.CloseErrorOverlayMsg:
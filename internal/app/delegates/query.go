@@ -1,7 +1,9 @@
 package delegates
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/rebelice/lazypg/internal/app/messages"
@@ -32,6 +34,9 @@ func (d *QueryDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd) {
 	case messages.QueryResultMsg:
 		return d.handleQueryResult(msg, app)
 
+	case messages.QueryPageLoadedMsg:
+		return d.handleQueryPageLoaded(msg, app)
+
 	case components.SaveObjectMsg:
 		return d.handleSaveObject(msg, app)
 
@@ -44,28 +49,80 @@ func (d *QueryDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd) {
 
 // handleExecuteQuery handles query execution from SQL editor.
 func (d *QueryDelegate) handleExecuteQuery(msg components.ExecuteQueryMsg, app AppAccess) (bool, tea.Cmd) {
-	if app.GetState().ActiveConnection == nil {
+	if app.GetState().ActiveConnection == nil && msg.ConnectionID == "" {
 		app.ShowError("No Connection", "Please connect to a database first")
 		return true, nil
 	}
 
+	// One query at a time: the pending tab clears when its result arrives or
+	// it is cancelled, and Esc always clears it, so this can't stick
+	if app.GetResultTabs().HasPendingQuery() {
+		return true, app.ShowToast("A query is already running (Esc to cancel)")
+	}
+
+	// Run leading \set / \unset lines, then expand :variables
+	vars := app.SQLVariables()
+	sql, listed, err := vars.RunMetaCommands(msg.SQL)
+	if err != nil {
+		app.ShowError("Variable Error", err.Error())
+		return true, nil
+	}
+	if strings.TrimSpace(sql) == "" {
+		if listed {
+			app.ShowError("Session Variables", vars.Describe())
+		}
+		return true, nil
+	}
+	sql = vars.Substitute(sql)
+
+	// Guard exploratory queries from the SQL editor with a default LIMIT
+	limit := 0
+	if msg.QuickQuery {
+		sql, limit = components.ApplyQuickQueryLimit(sql, app.QuickQueryLimit())
+		if sql == "" {
+			return true, nil
+		}
+	}
+
 	// Create pending tab immediately
-	app.StartPendingQuery(msg.SQL)
+	app.StartPendingQuery(sql)
+	app.GetResultTabs().SetPendingLimit(limit)
+	if msg.ConnectionID != "" {
+		app.GetResultTabs().SetPendingConnection(msg.ConnectionID)
+	}
 
 	// Immediately switch focus to data panel and collapse editor
 	app.GetSQLEditor().Collapse()
 	app.SetFocusArea(models.FocusDataPanel)
 	app.UpdatePanelStyles()
 
+	execute := app.ExecuteQuery
+	if app.SafeMode() && components.IsDataModifying(sql) {
+		execute = app.ExecuteQueryInTransaction
+	}
+
 	// Execute query asynchronously and start spinner
 	return true, tea.Batch(
 		app.GetSpinnerTickCmd(),
-		app.ExecuteQuery(msg.SQL),
+		execute(sql, msg.ConnectionID),
 	)
 }
 
 // handleQueryResult handles query execution result.
 func (d *QueryDelegate) handleQueryResult(msg messages.QueryResultMsg, app AppAccess) (bool, tea.Cmd) {
+	// Drop late results from cancelled queries so they can't complete (or
+	// clear the cancel func of) a newer query
+	if !app.GetResultTabs().IsPendingQuery(msg.PendingID) {
+		if tx := msg.Transaction; tx != nil {
+			// Cancelled just as it finished; nobody asked for its changes
+			return true, func() tea.Msg {
+				_ = tx.Rollback(context.Background())
+				return nil
+			}
+		}
+		return true, nil
+	}
+
 	// Clear execution cancel function
 	app.SetExecuteCancelFn(nil)
 
@@ -76,15 +133,66 @@ func (d *QueryDelegate) handleQueryResult(msg messages.QueryResultMsg, app AppAc
 			// Already handled by CancelPendingQuery, just return
 			return true, nil
 		}
+		app.RecordQueryHistory(msg.SQL, msg.Result, msg.ConnectionID)
 		// Show error and remove pending tab
 		app.CancelPendingQuery()
-		app.ShowError("Query Error", msg.Result.Error.Error())
+		if app.HandleSessionLoss(msg.Result.Error, msg.ConnectionID) {
+			return true, nil
+		}
+		errText := msg.Result.Error.Error()
+		if components.HasQueryMessages(msg.Result) {
+			// Notices and completed statements before the failure often
+			// explain it
+			errText += "\n\nMessages before the error:\n" + strings.Join(components.QueryMessageLines(msg.Result), "\n")
+		}
+		app.ShowError("Query Error", errText)
+		return true, nil
+	}
+
+	app.RecordQueryHistory(msg.SQL, msg.Result, msg.ConnectionID)
+
+	// Text EXPLAIN output is one column of indented plan lines, and JSON
+	// output one cell; show them verbatim rather than as a grid
+	if components.IsTextExplain(msg.SQL, msg.Result) || components.IsJSONExplain(msg.SQL, msg.Result) {
+		app.CompletePendingPlan(msg.SQL, msg.Result)
 		return true, nil
 	}
 
 	// Complete the pending query with results
 	app.CompletePendingQuery(msg.SQL, msg.Result)
 
+	if msg.Transaction != nil {
+		app.OpenSafeModePrompt(msg.SQL, msg.Result.RowsAffected, msg.Transaction)
+	}
+
+	return true, nil
+}
+
+// handleQueryPageLoaded appends the next page of a query result tab.
+func (d *QueryDelegate) handleQueryPageLoaded(msg messages.QueryPageLoadedMsg, app AppAccess) (bool, tea.Cmd) {
+	tab := app.GetResultTabs().GetTabByID(msg.TabID)
+	if tab == nil || tab.TableView == nil {
+		// The tab was closed while the page loaded
+		return true, nil
+	}
+	tableView := tab.TableView
+	tableView.IsPaginating = false
+
+	if msg.Result.Error != nil {
+		if app.HandleSessionLoss(msg.Result.Error, tab.ConnectionID) {
+			return true, nil
+		}
+		app.ShowError("Could Not Load More Rows", msg.Result.Error.Error())
+		return true, nil
+	}
+	if msg.Offset != len(tableView.LoadedRows()) {
+		return true, nil
+	}
+
+	tableView.AppendRows(msg.Result.Rows)
+	tableView.TotalRows = len(tableView.LoadedRows())
+	tableView.MoreRows = len(msg.Result.Rows) >= tab.AppliedLimit
+	tab.Result.Rows = tableView.LoadedRows()
 	return true, nil
 }
 
@@ -40,11 +40,16 @@ func (d *TreeDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd) {
 		treeView.IsLoading = false
 		treeView.LoadingNodeID = ""
 		if msg.Err != nil {
+			if app.HandleSessionLoss(msg.Err, "") {
+				return true, nil
+			}
 			app.ShowError("Database Error", fmt.Sprintf("Failed to load database structure:\n\n%v", msg.Err))
 			return true, nil
 		}
 		// Update tree view with loaded data
 		treeView.Root = msg.Root
+		app.PruneRecentObjects()
+		app.SetSchemaObjects(msg.AllObjects)
 
 		// Auto-expand: Root -> Database -> only "public" schema (skip extensions)
 		if msg.Root != nil {
@@ -70,6 +75,9 @@ func (d *TreeDelegate) Update(msg tea.Msg, app AppAccess) (bool, tea.Cmd) {
 		treeView := app.GetTreeView()
 		treeView.LoadingNodeID = ""
 		if msg.Err != nil {
+			if app.HandleSessionLoss(msg.Err, "") {
+				return true, nil
+			}
 			app.ShowError("Load Error", fmt.Sprintf("Failed to load children:\n\n%v", msg.Err))
 			return true, nil
 		}
@@ -114,8 +122,13 @@ func (d *TreeDelegate) handleNodeSelected(msg components.TreeNodeSelectedMsg, ap
 		return true, nil
 	}
 
+	// Remember objects for quick reopening
+	if models.IsReopenableObject(msg.Node.Type) {
+		app.RecordRecentObject(msg.Node)
+	}
+
 	switch msg.Node.Type {
-	case models.TreeNodeTypeTable, models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView:
+	case models.TreeNodeTypeTable, models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView, models.TreeNodeTypeForeignTable:
 		return d.handleTableNodeSelected(msg.Node, app)
 
 	case models.TreeNodeTypeFunction, models.TreeNodeTypeProcedure:
@@ -153,7 +166,8 @@ func (d *TreeDelegate) handleNodeSelected(msg components.TreeNodeSelectedMsg, ap
 	}
 }
 
-// handleTableNodeSelected handles table/view/materialized view node selection.
+// handleTableNodeSelected handles table/view/materialized view/foreign table
+// node selection.
 func (d *TreeDelegate) handleTableNodeSelected(node *models.TreeNode, app AppAccess) (bool, tea.Cmd) {
 	// Get schema name by traversing up the tree
 	schemaName := d.findSchemaName(node)
@@ -172,6 +186,7 @@ func (d *TreeDelegate) handleTableNodeSelected(node *models.TreeNode, app AppAcc
 	for i, tab := range resultTabs.GetAllTabs() {
 		if tab.ObjectID == objectID && tab.Type == components.TabTypeTableData {
 			resultTabs.SetActiveTab(i)
+			app.SyncActiveFilter()
 			app.SetFocusArea(models.FocusDataPanel)
 			app.UpdatePanelStyles()
 			return true, nil
@@ -266,6 +281,9 @@ func (d *TreeDelegate) handleRangeTypeNodeSelected(node *models.TreeNode, app Ap
 func (d *TreeDelegate) handleObjectDetailsLoaded(msg messages.ObjectDetailsLoadedMsg, app AppAccess) (bool, tea.Cmd) {
 	app.SetLoadingObjectDetails(false) // Clear loading state
 	if msg.Err != nil {
+		if app.HandleSessionLoss(msg.Err, "") {
+			return true, nil
+		}
 		app.ShowError("Error", fmt.Sprintf("Failed to load %s details:\n\n%v", msg.ObjectType, msg.Err))
 		return true, nil
 	}
@@ -282,7 +300,7 @@ func (d *TreeDelegate) handleObjectDetailsLoaded(msg messages.ObjectDetailsLoade
 	}
 
 	// Create code editor tab
-	app.CreateCodeEditorTab(msg.ObjectID, msg.Title, msg.Content, msg.ObjectType, msg.ObjectName)
+	app.CreateCodeEditorTab(msg)
 	app.SetFocusArea(models.FocusDataPanel)
 	app.UpdatePanelStyles()
 	return true, nil
@@ -295,6 +313,7 @@ func (d *TreeDelegate) handleCodeEditorClose(app AppAccess) (bool, tea.Cmd) {
 	activeTab := resultTabs.GetActiveTab()
 	if activeTab != nil && activeTab.Type == components.TabTypeCodeEditor {
 		resultTabs.CloseActiveTab()
+		app.SyncActiveFilter()
 	}
 
 	// Legacy: also clear the global code editor state
@@ -4,7 +4,14 @@
 package messages
 
 import (
+	"time"
+
+	"github.com/rebelice/lazypg/internal/connection_history"
+	"github.com/rebelice/lazypg/internal/db/connection"
 	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/db/query"
+	"github.com/rebelice/lazypg/internal/favorites"
+	"github.com/rebelice/lazypg/internal/history"
 	"github.com/rebelice/lazypg/internal/models"
 )
 
@@ -13,6 +20,18 @@ type DiscoveryCompleteMsg struct {
 	Instances []models.DiscoveredInstance
 }
 
+// StoreOpenedMsg carries a store opened in the background at startup. Each
+// sets only the fields it opened, which stay nil if opening failed.
+type StoreOpenedMsg struct {
+	History           *history.Store
+	Favorites         *favorites.Manager
+	ConnectionHistory *connection_history.Manager
+}
+
+// StartupTimeoutMsg is sent when the startup stores are slow to open, to
+// show the app without waiting for them any longer
+type StartupTimeoutMsg struct{}
+
 // ErrorMsg is sent when an error occurs
 type ErrorMsg struct {
 	Title   string
@@ -29,6 +48,20 @@ type ConnectionResultMsg struct {
 	Config models.ConnectionConfig
 	ConnID string
 	Err    error
+
+	// OnConnectErr is set when connected but the on-connect SQL failed
+	OnConnectErr error
+
+	// Superuser is set when the connection's user is a superuser. A failed
+	// check leaves it false rather than failing the connection.
+	Superuser bool
+}
+
+// ConnectionRetryMsg is sent when the backoff after a transient connection
+// failure is over. Start identifies the connection attempt it belongs to.
+type ConnectionRetryMsg struct {
+	Config models.ConnectionConfig
+	Start  time.Time
 }
 
 // LoadTreeMsg requests loading the navigation tree
@@ -66,11 +99,22 @@ type LoadTableDataMsg struct {
 
 // TableDataLoadedMsg is sent when table data is loaded
 type TableDataLoadedMsg struct {
-	Columns   []string
-	Rows      [][]string
-	TotalRows int
-	Offset    int // Offset used in the query (0 for initial load)
-	Err       error
+	Columns     []string
+	ColumnKinds []models.ColumnKind
+	Rows        [][]string
+	TotalRows   int
+	Offset      int    // Offset used in the query (0 for initial load)
+	SQL         string // The SELECT that loaded the rows
+	Err         error
+}
+
+// FilterCountLoadedMsg is sent when the rows matching the active filter
+// have been counted
+type FilterCountLoadedMsg struct {
+	Schema string
+	Table  string
+	Count  int64
+	Err    error
 }
 
 // PrefetchDataMsg requests prefetching data in background
@@ -82,12 +126,14 @@ type PrefetchDataMsg struct {
 	SortColumn string
 	SortDir    string
 	NullsFirst bool
+	Filter     *models.Filter // Filter of the table data tab, if any
 }
 
 // PrefetchCompleteMsg is sent when prefetch completes
 type PrefetchCompleteMsg struct {
 	Rows   [][]string
 	Offset int
+	SQL    string // The SELECT that loaded the rows
 	Err    error
 }
 
@@ -95,6 +141,36 @@ type PrefetchCompleteMsg struct {
 type QueryResultMsg struct {
 	SQL    string
 	Result models.QueryResult
+
+	// ConnectionID is the connection the query ran on, empty for the
+	// active one
+	ConnectionID string
+
+	// PendingID is the ID of the pending tab the query was started in; it
+	// tells this execution's result apart from one of a cancelled run of
+	// the same SQL
+	PendingID int
+
+	// Transaction is set when safe mode ran the query and left its
+	// transaction open; it must be committed or rolled back
+	Transaction *query.Transaction
+}
+
+// AutoSaveTickMsg triggers saving the SQL editor content for crash recovery
+type AutoSaveTickMsg struct{}
+
+// SafeModeFinishedMsg is sent when a statement held by safe mode has been
+// committed or rolled back
+type SafeModeFinishedMsg struct {
+	Commit bool
+	Err    error
+}
+
+// QueryPageLoadedMsg is sent when the next page of a query result tab loads
+type QueryPageLoadedMsg struct {
+	TabID  int
+	Offset int
+	Result models.QueryResult
 }
 
 // ObjectDetailsLoadedMsg is sent when object details are loaded
@@ -102,6 +178,8 @@ type ObjectDetailsLoadedMsg struct {
 	ObjectType string // "function", "sequence", "extension", "type", "index", "trigger"
 	ObjectName string // "schema.name" for save operations
 	ObjectID   string // Unique ID for tab deduplication (e.g., "schema.function_name")
+	Schema     string // Empty for objects outside a schema (extensions)
+	Name       string // Unqualified name, for tab title templates
 	Title      string
 	Content    string // Formatted content to display
 	Err        error
@@ -109,18 +187,48 @@ type ObjectDetailsLoadedMsg struct {
 
 // TabTableDataLoadedMsg is sent when table data for a tab is loaded
 type TabTableDataLoadedMsg struct {
-	ObjectID  string // schema.table identifier
-	Schema    string
-	Table     string
-	Columns   []string
-	Rows      [][]string
-	TotalRows int
-	Err       error
+	ObjectID    string // schema.table identifier
+	Schema      string
+	Table       string
+	Columns     []string
+	ColumnKinds []models.ColumnKind
+	Rows        [][]string
+	TotalRows   int
+	SQL         string // The SELECT that loaded the rows
+	Err         error
+
+	// Filter and sort the rows were loaded with, e.g. from a saved view.
+	// UnfilteredRows counts the table's rows without Filter.
+	Filter         *models.Filter
+	UnfilteredRows int
+	SortColumn     string
+	SortDir        string
+	NullsFirst     bool
+
+	// Refresh is set for a refresh, which updates the tab in place without
+	// moving focus. PrimaryKey holds the table's key columns on a refresh,
+	// empty if it has none.
+	Refresh    bool
+	PrimaryKey []string
+
+	// Window is the LIMIT and OFFSET the rows were loaded with, nil when
+	// they are the first page of a table that loads more as it is scrolled
+	Window *models.RowWindow
+}
+
+// TableViewCheckedMsg is sent when a saved table view's table has been
+// looked up before opening it
+type TableViewCheckedMsg struct {
+	Favorite models.Favorite
+	Exists   bool
+	Err      error
 }
 
-// StructureMetadataLoadedMsg is sent when table structure metadata is loaded
+// StructureMetadataLoadedMsg is sent when one structure sub-tab's metadata
+// is loaded. Only the field for Section is set.
 type StructureMetadataLoadedMsg struct {
 	ObjectID    string // schema.table identifier for routing to correct tab
+	Section     int    // components.StructureTabColumns, ...Constraints or ...Indexes
 	Columns     []models.ColumnDetail
 	Constraints []models.Constraint
 	Indexes     []models.IndexInfo
@@ -151,3 +259,198 @@ type TableSearchData struct {
 	Rows      [][]string
 	TotalRows int
 }
+
+// CSVImportColumnsLoadedMsg carries the columns a CSV import into
+// Schema.Table can fill, loaded to open the import dialog
+type CSVImportColumnsLoadedMsg struct {
+	Schema  string
+	Table   string
+	Columns []string
+	Err     error
+}
+
+// CSVFileReadMsg carries the records of the CSV file picked in the import
+// dialog
+type CSVFileReadMsg struct {
+	Path    string
+	Records [][]string
+	Err     error
+}
+
+// CSVImportResultMsg is sent when a CSV import (or dry run) completes
+type CSVImportResultMsg struct {
+	Schema     string
+	Table      string
+	RowsCopied int64
+	DryRun     bool
+	Cancelled  bool
+	Err        error
+}
+
+// CSVImportProgressMsg reports how far a running CSV import has got
+type CSVImportProgressMsg struct {
+	Rows  int64
+	Bytes int64
+}
+
+// ListenResultMsg is sent when a LISTEN or UNLISTEN completes. Listener is
+// set when the dedicated listener connection was opened for this request.
+type ListenResultMsg struct {
+	Listener *connection.Listener
+	ConnID   string
+	Channel  string
+	Unlisten bool
+	Err      error
+}
+
+// NotificationReceivedMsg is sent for each notification from the listener
+type NotificationReceivedMsg struct {
+	Listener     *connection.Listener
+	Notification connection.Notification
+}
+
+// ListenerClosedMsg is sent when the listener connection stops
+type ListenerClosedMsg struct {
+	Listener *connection.Listener
+	Err      error
+}
+
+// NotifySentMsg is sent when a NOTIFY completes
+type NotifySentMsg struct {
+	Channel string
+	Err     error
+}
+
+// SearchPathLoadedMsg carries the session's search_path, loaded to resolve
+// an unqualified name for go to definition
+type SearchPathLoadedMsg struct {
+	Name       string
+	SearchPath []string
+	Err        error
+}
+
+// BlockingLocksLoadedMsg carries the blocker/blocked pairs for the locks
+// monitor. Tick is the refresh chain that loaded them.
+type BlockingLocksLoadedMsg struct {
+	Tick  int
+	Locks []models.BlockingLock
+	Err   error
+}
+
+// CellConditionMsg carries the condition matching the selected cell once
+// its column's type is known. Apply filters the table tab ObjectID by it;
+// otherwise it is copied as SQL. Masked is set for a masked cell, whose
+// value mustn't be shown.
+type CellConditionMsg struct {
+	ObjectID  string
+	Condition models.FilterCondition
+	Apply     bool
+	Masked    bool
+	Err       error
+}
+
+// ServerInfoLoadedMsg carries the server info of the active connection
+type ServerInfoLoadedMsg struct {
+	Info models.ServerInfo
+	Err  error
+}
+
+// TableTruncatedMsg is sent when a TRUNCATE from the tree completes
+type TableTruncatedMsg struct {
+	ObjectID string // schema.table
+	Cascade  bool
+	Err      error
+}
+
+// ForeignServersLoadedMsg carries the foreign servers of the active database
+type ForeignServersLoadedMsg struct {
+	Servers []metadata.ForeignServer
+	Err     error
+}
+
+// LocksTickMsg triggers the next locks monitor refresh of chain Tick
+type LocksTickMsg struct {
+	Tick int
+}
+
+// BackendSignaledMsg is sent when cancelling or terminating a backend completes.
+// OK is false when the backend was already gone.
+type BackendSignaledMsg struct {
+	PID       int
+	Terminate bool
+	OK        bool
+	Err       error
+}
+
+// TabRefreshTickMsg triggers the next auto refresh of tab TabID, if Seq is
+// still its current tick chain
+type TabRefreshTickMsg struct {
+	TabID int
+	Seq   int
+}
+
+// ClearChangeHighlightMsg ends the highlight of the rows a refresh of the
+// table tab ObjectID changed, if Seq is still its current highlight
+type ClearChangeHighlightMsg struct {
+	ObjectID string
+	Seq      int
+}
+
+// PsqlExitedMsg is sent when the psql subshell exits and lazypg resumes
+type PsqlExitedMsg struct {
+	Err error
+}
+
+// ObjectDDLLoadedMsg carries an object's definition loaded to be copied to
+// the clipboard rather than opened in a tab
+type ObjectDDLLoadedMsg struct {
+	Details ObjectDetailsLoadedMsg
+}
+
+// DatabasesLoadedMsg carries the databases on the active connection's
+// server, to pick one to switch to
+type DatabasesLoadedMsg struct {
+	Databases []metadata.Database
+	Err       error
+}
+
+// SchemaExportedMsg is sent when the schema has been written to Path
+type SchemaExportedMsg struct {
+	Path    string
+	Objects int
+	Err     error
+}
+
+// RowEditLoadedMsg carries the columns of a table tab's table, loaded to
+// open a row of it in the row edit form. GridColumns and Row are the grid's
+// header and the row as they were when the form was asked for, and Masked
+// marks the row's cells the grid masks.
+type RowEditLoadedMsg struct {
+	ObjectID    string
+	Schema      string
+	Table       string
+	Columns     []models.ColumnDetail
+	GridColumns []string
+	Row         []string
+	Masked      []bool
+	Err         error
+}
+
+// RowsSQLMsg carries rows of a table tab written as a condition on their
+// primary key or as an INSERT, once the table's columns are loaded, for
+// copying. Rows is how many rows it covers.
+type RowsSQLMsg struct {
+	SQL    string
+	Insert bool
+	Rows   int
+	Err    error
+}
+
+// RowUpdatedMsg is sent when the row edit form's UPDATE has run. In safe
+// mode Transaction holds it uncommitted for the safe mode prompt.
+type RowUpdatedMsg struct {
+	ObjectID    string
+	SQL         string
+	Transaction *query.Transaction
+	Err         error
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/app/messages"
+	"github.com/rebelice/lazypg/internal/connection_history"
+	"github.com/rebelice/lazypg/internal/favorites"
+)
+
+// newStartingApp returns an App as New leaves it, with both startup stores
+// still opening
+func newStartingApp(t *testing.T) *App {
+	t.Helper()
+	a := New(nil, t.TempDir())
+	if !a.isStarting() {
+		t.Fatal("a new App should be starting")
+	}
+	return a
+}
+
+func TestStartup_StoresOpened(t *testing.T) {
+	a := newStartingApp(t)
+	fav, err := favorites.NewManager(a.configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Update(messages.StoreOpenedMsg{Favorites: fav})
+	if !a.isStarting() || a.startupPending != 1 {
+		t.Errorf("pending %d after the first store, want 1 and still starting", a.startupPending)
+	}
+	if a.favoritesManager != fav {
+		t.Error("the favorites should be taken up")
+	}
+
+	_, cmd := a.Update(messages.StoreOpenedMsg{ConnectionHistory: &connection_history.Manager{}})
+	if a.isStarting() || a.startupPending != 0 {
+		t.Errorf("pending %d after both stores, want the splash gone", a.startupPending)
+	}
+	if a.connectionHistory == nil {
+		t.Error("the connection history should be taken up")
+	}
+	if cmd != nil {
+		t.Error("stores opened in time should not toast")
+	}
+
+	// The timeout after both stores opened does nothing
+	if _, cmd := a.Update(messages.StartupTimeoutMsg{}); cmd != nil || a.startupTimedOut {
+		t.Error("a timeout after startup should be ignored")
+	}
+}
+
+func TestStartup_TimeoutAndLateStore(t *testing.T) {
+	a := newStartingApp(t)
+	a.Update(messages.StoreOpenedMsg{})
+
+	_, cmd := a.Update(messages.StartupTimeoutMsg{})
+	if !a.startupTimedOut || a.isStarting() {
+		t.Fatal("the timeout should end the splash with a store still opening")
+	}
+	if cmd == nil {
+		t.Error("the timeout should say the saved connections are still opening")
+	}
+
+	// The keyring answers late: the saved connections still arrive
+	_, cmd = a.Update(messages.StoreOpenedMsg{ConnectionHistory: &connection_history.Manager{}})
+	if a.connectionHistory == nil {
+		t.Error("a store opened after the timeout should be taken up")
+	}
+	if cmd == nil {
+		t.Error("late saved connections should be announced")
+	}
+	if a.isStarting() {
+		t.Error("a late store should not bring the splash back")
+	}
+}
+
+func TestStartup_KeysUnderSplash(t *testing.T) {
+	a := newStartingApp(t)
+	a.showConnectionDialog = false
+
+	// Keys are swallowed, so nothing opens under the splash
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlP}); cmd != nil || a.showCommandPalette {
+		t.Error("keys under the splash should be ignored")
+	}
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		t.Error("q under the splash should not quit")
+	}
+	if _, cmd := a.Update(tea.MouseMsg{}); cmd != nil {
+		t.Error("mouse events under the splash should be ignored")
+	}
+
+	// Ctrl+C still quits
+	_, cmd := a.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("ctrl+c should quit from the splash")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("ctrl+c should quit from the splash")
+	}
+}
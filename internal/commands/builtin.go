@@ -16,6 +16,77 @@ type FavoritesCommandMsg struct{}
 type SettingsCommandMsg struct{}
 type ExportFavoritesCSVMsg struct{}
 type ExportFavoritesJSONMsg struct{}
+type ImportFavoritesJSONMsg struct{}
+type ExportConnectionHistoryMsg struct{}
+type ImportConnectionHistoryMsg struct{}
+type ImportCSVCommandMsg struct{}
+type RecentObjectsCommandMsg struct{}
+type QueryBuilderCommandMsg struct{}
+type NotificationsCommandMsg struct{}
+type ErrorLogCommandMsg struct{}
+type ListenCommandMsg struct{}
+type NotifyCommandMsg struct{}
+type ToggleSystemSchemasCommandMsg struct{}
+type ToggleGeneratedSQLCommandMsg struct{}
+type BookmarkObjectCommandMsg struct{}
+type SaveTableViewCommandMsg struct{}
+type ClearFilterCommandMsg struct{}
+type AutoRefreshCommandMsg struct{}
+type SessionVariablesCommandMsg struct{}
+type BlockingLocksCommandMsg struct{}
+type PsqlCommandMsg struct{}
+type ServerInfoCommandMsg struct{}
+type ForeignServersCommandMsg struct{}
+type ToggleSafeModeCommandMsg struct{}
+type ToggleDryRunCommandMsg struct{}
+type SwitchDatabaseCommandMsg struct{}
+type RunOnConnectionCommandMsg struct{}
+type RowWindowCommandMsg struct{}
+type EditRowCommandMsg struct{}
+type RowColorsCommandMsg struct{}
+type CopyRowsTSVCommandMsg struct{}
+type ToggleBoolGlyphsCommandMsg struct{}
+type ToggleTruncationWarningsCommandMsg struct{}
+type CycleEditorHeightCommandMsg struct{}
+type ToggleEditorFullscreenCommandMsg struct{}
+
+// CopyConnectionURLCommandMsg copies the active connection as a postgres://
+// URL, with the password masked unless IncludePassword is set
+type CopyConnectionURLCommandMsg struct {
+	IncludePassword bool
+}
+
+// ColumnSizesCommandMsg reports per-column stored sizes for the active table,
+// from a sample of rows unless FullScan is set
+type ColumnSizesCommandMsg struct {
+	FullScan bool
+}
+
+// CompareResultsCommandMsg compares the active result tab with another,
+// keyed by the selected column
+type CompareResultsCommandMsg struct{}
+
+// ExportSchemaCommandMsg writes the schema loaded in the tree to a file, as
+// a Graphviz DOT graph if DOT is set, otherwise as a text outline
+type ExportSchemaCommandMsg struct {
+	DOT bool
+}
+
+// CopyRowsSQLCommandMsg copies the selected, pinned or current rows of the
+// active table tab as a condition on their primary key, or as an INSERT if
+// Insert is set
+type CopyRowsSQLCommandMsg struct {
+	Insert bool
+}
+
+// CopyPlanCommandMsg copies the plan in the active tab, as the server's JSON
+// unless AsText is set
+type CopyPlanCommandMsg struct {
+	AsText bool
+}
+
+// SavePlanCommandMsg writes the plan in the active tab to files to share
+type SavePlanCommandMsg struct{}
 
 // GetBuiltinCommands returns the list of built-in commands
 func GetBuiltinCommands() []models.Command {
@@ -42,6 +113,17 @@ func GetBuiltinCommands() []models.Command {
 				return DisconnectCommandMsg{}
 			},
 		},
+		{
+			ID:          "switch-database",
+			Type:        models.CommandTypeAction,
+			Label:       "Switch Database",
+			Description: "Connect to another database on the same server",
+			Icon:        "🗄",
+			Tags:        []string{"database", "switch", "connect", "use"},
+			Action: func() tea.Msg {
+				return SwitchDatabaseCommandMsg{}
+			},
+		},
 		{
 			ID:          "refresh",
 			Type:        models.CommandTypeAction,
@@ -75,6 +157,17 @@ func GetBuiltinCommands() []models.Command {
 				return QueryEditorCommandMsg{}
 			},
 		},
+		{
+			ID:          "run-on-connection",
+			Type:        models.CommandTypeAction,
+			Label:       "Run Query on Connection",
+			Description: "Run the editor's query on another open connection without switching to it",
+			Icon:        "🔌",
+			Tags:        []string{"query", "execute", "connection", "target", "prod", "staging"},
+			Action: func() tea.Msg {
+				return RunOnConnectionCommandMsg{}
+			},
+		},
 		{
 			ID:          "history",
 			Type:        models.CommandTypeAction,
@@ -142,5 +235,478 @@ func GetBuiltinCommands() []models.Command {
 				return ExportFavoritesJSONMsg{}
 			},
 		},
+		{
+			ID:          "export-schema-outline",
+			Type:        models.CommandTypeAction,
+			Label:       "Export Schema as Text Outline",
+			Description: "Write the schemas, tables and columns in the tree to a text file",
+			Icon:        "🌳",
+			Tags:        []string{"export", "schema", "tree", "outline", "text", "documentation"},
+			Action: func() tea.Msg {
+				return ExportSchemaCommandMsg{}
+			},
+		},
+		{
+			ID:          "export-schema-dot",
+			Type:        models.CommandTypeAction,
+			Label:       "Export Schema as DOT Graph",
+			Description: "Write the tables in the tree and their foreign keys as a Graphviz graph",
+			Icon:        "🕸️",
+			Tags:        []string{"export", "schema", "graphviz", "dot", "erd", "foreign", "documentation"},
+			Action: func() tea.Msg {
+				return ExportSchemaCommandMsg{DOT: true}
+			},
+		},
+		{
+			ID:          "import-favorites-json",
+			Type:        models.CommandTypeAction,
+			Label:       "Import Favorites from JSON",
+			Description: "Merge favorites from favorites.json in the config directory",
+			Icon:        "📥",
+			Tags:        []string{"import", "favorites", "json", "backup"},
+			Action: func() tea.Msg {
+				return ImportFavoritesJSONMsg{}
+			},
+		},
+		{
+			ID:          "export-connection-history",
+			Type:        models.CommandTypeAction,
+			Label:       "Export Connection History",
+			Description: "Export saved connections (without passwords)",
+			Icon:        "📤",
+			Tags:        []string{"export", "connection", "history", "backup"},
+			Action: func() tea.Msg {
+				return ExportConnectionHistoryMsg{}
+			},
+		},
+		{
+			ID:          "import-connection-history",
+			Type:        models.CommandTypeAction,
+			Label:       "Import Connection History",
+			Description: "Merge saved connections from an exported file",
+			Icon:        "📥",
+			Tags:        []string{"import", "connection", "history", "backup"},
+			Action: func() tea.Msg {
+				return ImportConnectionHistoryMsg{}
+			},
+		},
+		{
+			ID:          "import-csv",
+			Type:        models.CommandTypeAction,
+			Label:       "Import CSV into Table",
+			Description: "Load a CSV file into the current table",
+			Icon:        "📥",
+			Tags:        []string{"import", "csv", "copy", "table"},
+			Action: func() tea.Msg {
+				return ImportCSVCommandMsg{}
+			},
+		},
+		{
+			ID:          "recent-objects",
+			Type:        models.CommandTypeAction,
+			Label:       "Recent Objects",
+			Description: "Jump to a recently opened table, view, or function",
+			Icon:        "🕘",
+			Tags:        []string{"recent", "jump", "mru", "table"},
+			Action: func() tea.Msg {
+				return RecentObjectsCommandMsg{}
+			},
+		},
+		{
+			ID:          "query-builder",
+			Type:        models.CommandTypeAction,
+			Label:       "Query Builder",
+			Description: "Build a SELECT by picking columns, filters, and sort order",
+			Icon:        "🧱",
+			Tags:        []string{"query", "builder", "select", "visual", "sql"},
+			Action: func() tea.Msg {
+				return QueryBuilderCommandMsg{}
+			},
+		},
+		{
+			ID:          "notifications",
+			Type:        models.CommandTypeAction,
+			Label:       "Notifications",
+			Description: "Show notifications received from LISTEN channels",
+			Icon:        "🔔",
+			Tags:        []string{"listen", "notify", "notifications", "pg_notify", "channel"},
+			Action: func() tea.Msg {
+				return NotificationsCommandMsg{}
+			},
+		},
+		{
+			ID:          "error-log",
+			Type:        models.CommandTypeAction,
+			Label:       "Recent Errors",
+			Description: "Re-read the errors shown this session",
+			Icon:        "⚠",
+			Tags:        []string{"errors", "log", "history", "failed", "dismissed"},
+			Action: func() tea.Msg {
+				return ErrorLogCommandMsg{}
+			},
+		},
+		{
+			ID:          "listen",
+			Type:        models.CommandTypeAction,
+			Label:       "Listen on Channel",
+			Description: "LISTEN on a channel and stream its notifications",
+			Icon:        "📡",
+			Tags:        []string{"listen", "notify", "channel", "pg_notify"},
+			Action: func() tea.Msg {
+				return ListenCommandMsg{}
+			},
+		},
+		{
+			ID:          "notify",
+			Type:        models.CommandTypeAction,
+			Label:       "Send NOTIFY",
+			Description: "Send a notification to a channel",
+			Icon:        "📣",
+			Tags:        []string{"notify", "pg_notify", "channel", "send"},
+			Action: func() tea.Msg {
+				return NotifyCommandMsg{}
+			},
+		},
+		{
+			ID:          "blocking-locks",
+			Type:        models.CommandTypeAction,
+			Label:       "Blocking Locks",
+			Description: "Show which sessions block which, and cancel or terminate the blocker",
+			Icon:        "🔒",
+			Tags:        []string{"locks", "blocking", "pg_locks", "activity", "cancel", "terminate", "kill"},
+			Action: func() tea.Msg {
+				return BlockingLocksCommandMsg{}
+			},
+		},
+		{
+			ID:          "server-info",
+			Type:        models.CommandTypeAction,
+			Label:       "Server Info",
+			Description: "Show the server version and the session's user, database, encoding and time zone",
+			Icon:        "ℹ",
+			Tags:        []string{"server", "version", "info", "encoding", "timezone", "user", "database"},
+			Action: func() tea.Msg {
+				return ServerInfoCommandMsg{}
+			},
+		},
+		{
+			ID:          "foreign-servers",
+			Type:        models.CommandTypeAction,
+			Label:       "Foreign Servers",
+			Description: "List the foreign servers, their options and user mappings, with secrets masked",
+			Icon:        "⇄",
+			Tags:        []string{"foreign", "server", "fdw", "postgres_fdw", "remote", "user mapping"},
+			Action: func() tea.Msg {
+				return ForeignServersCommandMsg{}
+			},
+		},
+		{
+			ID:          "psql",
+			Type:        models.CommandTypeAction,
+			Label:       "Open psql",
+			Description: "Suspend lazypg and run psql on the active connection",
+			Icon:        "💻",
+			Tags:        []string{"psql", "shell", "terminal", "console", "cli"},
+			Action: func() tea.Msg {
+				return PsqlCommandMsg{}
+			},
+		},
+		{
+			ID:          "toggle-system-schemas",
+			Type:        models.CommandTypeAction,
+			Label:       "Toggle System Schemas",
+			Description: "Show or hide pg_catalog and information_schema in the tree",
+			Icon:        "⚙️",
+			Tags:        []string{"schema", "system", "pg_catalog", "information_schema", "tree", "hide"},
+			Action: func() tea.Msg {
+				return ToggleSystemSchemasCommandMsg{}
+			},
+		},
+		{
+			ID:          "toggle-generated-sql",
+			Type:        models.CommandTypeAction,
+			Label:       "Toggle Generated SQL",
+			Description: "Show or hide the SQL lazypg ran to load table data",
+			Icon:        "🔍",
+			Tags:        []string{"sql", "generated", "debug", "query", "filter", "sort", "search"},
+			Action: func() tea.Msg {
+				return ToggleGeneratedSQLCommandMsg{}
+			},
+		},
+		{
+			ID:          "toggle-truncation-warnings",
+			Type:        models.CommandTypeAction,
+			Label:       "Toggle Truncation Warnings",
+			Description: "Highlight the headers of columns shown at less than half their values' width",
+			Icon:        "↔",
+			Tags:        []string{"column", "width", "truncated", "header", "warning", "display"},
+			Action: func() tea.Msg {
+				return ToggleTruncationWarningsCommandMsg{}
+			},
+		},
+		{
+			ID:          "toggle-bool-glyphs",
+			Type:        models.CommandTypeAction,
+			Label:       "Toggle Boolean Checkmarks",
+			Description: "Show boolean values as ✓/✗ or as true/false",
+			Icon:        "✓",
+			Tags:        []string{"boolean", "bool", "checkmark", "glyph", "true", "false", "display"},
+			Action: func() tea.Msg {
+				return ToggleBoolGlyphsCommandMsg{}
+			},
+		},
+		{
+			ID:          "cycle-editor-height",
+			Type:        models.CommandTypeAction,
+			Label:       "Cycle SQL Editor Height",
+			Description: "Step the SQL editor through its small, medium and large heights (F2)",
+			Icon:        "↕",
+			Tags:        []string{"editor", "sql", "height", "resize", "size", "layout"},
+			Action: func() tea.Msg {
+				return CycleEditorHeightCommandMsg{}
+			},
+		},
+		{
+			ID:          "toggle-editor-fullscreen",
+			Type:        models.CommandTypeAction,
+			Label:       "Toggle Fullscreen SQL Editor",
+			Description: "Edit SQL over the whole content area, then restore the layout (F11)",
+			Icon:        "⛶",
+			Tags:        []string{"editor", "sql", "fullscreen", "maximize", "zoom", "layout"},
+			Action: func() tea.Msg {
+				return ToggleEditorFullscreenCommandMsg{}
+			},
+		},
+		{
+			ID:          "toggle-safe-mode",
+			Type:        models.CommandTypeAction,
+			Label:       "Toggle Safe Mode",
+			Description: "Run INSERT, UPDATE and DELETE in a transaction and ask before committing",
+			Icon:        "🛡️",
+			Tags:        []string{"safe", "transaction", "commit", "rollback", "dml", "update", "delete"},
+			Action: func() tea.Msg {
+				return ToggleSafeModeCommandMsg{}
+			},
+		},
+		{
+			ID:          "toggle-dry-run",
+			Type:        models.CommandTypeAction,
+			Label:       "Toggle Dry Run",
+			Description: "Show the statements row edits and other generated changes would run, instead of running them",
+			Icon:        "🧪",
+			Tags:        []string{"dry", "run", "preview", "safe", "generated", "sql", "update"},
+			Action: func() tea.Msg {
+				return ToggleDryRunCommandMsg{}
+			},
+		},
+		{
+			ID:          "bookmark-object",
+			Type:        models.CommandTypeAction,
+			Label:       "Bookmark Object",
+			Description: "Add the object under the tree cursor to favorites",
+			Icon:        "🔖",
+			Tags:        []string{"bookmark", "favorites", "table", "view", "function"},
+			Action: func() tea.Msg {
+				return BookmarkObjectCommandMsg{}
+			},
+		},
+		{
+			ID:          "save-table-view",
+			Type:        models.CommandTypeAction,
+			Label:       "Save Table View",
+			Description: "Save the open table with its filter and sort to favorites",
+			Icon:        "▦",
+			Tags:        []string{"view", "favorites", "filter", "sort", "table", "save"},
+			Action: func() tea.Msg {
+				return SaveTableViewCommandMsg{}
+			},
+		},
+		{
+			ID:          "clear-filter",
+			Type:        models.CommandTypeAction,
+			Label:       "Clear Filter",
+			Description: "Remove the filter from the open table and reload all rows",
+			Icon:        "🧹",
+			Tags:        []string{"filter", "clear", "reset", "where", "reload"},
+			Action: func() tea.Msg {
+				return ClearFilterCommandMsg{}
+			},
+		},
+		{
+			ID:          "auto-refresh",
+			Type:        models.CommandTypeAction,
+			Label:       "Auto Refresh Tab",
+			Description: "Reload the open table every 2s, 5s, 10s, 30s or 1m; run again to change or stop",
+			Icon:        "↻",
+			Tags:        []string{"refresh", "reload", "auto", "interval", "dashboard", "watch"},
+			Action: func() tea.Msg {
+				return AutoRefreshCommandMsg{}
+			},
+		},
+		{
+			ID:          "row-window",
+			Type:        models.CommandTypeAction,
+			Label:       "Set LIMIT/OFFSET",
+			Description: "Load an explicit window of the open table's rows, e.g. from the middle",
+			Icon:        "⇕",
+			Tags:        []string{"limit", "offset", "page", "window", "rows", "sample"},
+			Action: func() tea.Msg {
+				return RowWindowCommandMsg{}
+			},
+		},
+		{
+			ID:          "edit-row",
+			Type:        models.CommandTypeAction,
+			Label:       "Edit Row",
+			Description: "Edit the selected row of the table tab in a form and save it as one UPDATE (e)",
+			Icon:        "✎",
+			Tags:        []string{"edit", "row", "update", "form", "modify", "record"},
+			Action: func() tea.Msg {
+				return EditRowCommandMsg{}
+			},
+		},
+		{
+			ID:          "row-colors",
+			Type:        models.CommandTypeAction,
+			Label:       "Row Color Rules",
+			Description: "Color rows matching a rule, e.g. status = 'error' -> red",
+			Icon:        "🎨",
+			Tags:        []string{"color", "highlight", "rule", "rows", "monitor", "status"},
+			Action: func() tea.Msg {
+				return RowColorsCommandMsg{}
+			},
+		},
+		{
+			ID:          "session-variables",
+			Type:        models.CommandTypeAction,
+			Label:       "Session Variables",
+			Description: "List variables defined with \\set",
+			Icon:        "💲",
+			Tags:        []string{"variables", "set", "psql", "substitution"},
+			Action: func() tea.Msg {
+				return SessionVariablesCommandMsg{}
+			},
+		},
+		{
+			ID:          "copy-connection-url",
+			Type:        models.CommandTypeAction,
+			Label:       "Copy Connection URL",
+			Description: "Copy the active connection as a postgres:// URL with the password masked",
+			Icon:        "🔗",
+			Tags:        []string{"connection", "url", "dsn", "copy", "clipboard", "psql"},
+			Action: func() tea.Msg {
+				return CopyConnectionURLCommandMsg{}
+			},
+		},
+		{
+			ID:          "copy-connection-url-password",
+			Type:        models.CommandTypeAction,
+			Label:       "Copy Connection URL (with Password)",
+			Description: "Copy the active connection as a postgres:// URL including the password",
+			Icon:        "🔑",
+			Tags:        []string{"connection", "url", "dsn", "copy", "clipboard", "password"},
+			Action: func() tea.Msg {
+				return CopyConnectionURLCommandMsg{IncludePassword: true}
+			},
+		},
+		{
+			ID:          "column-sizes",
+			Type:        models.CommandTypeAction,
+			Label:       "Column Sizes (Sampled)",
+			Description: "Average and max stored size per column of the active table, from a sample of rows",
+			Icon:        "📏",
+			Tags:        []string{"size", "toast", "bloat", "bytes", "storage", "column"},
+			Action: func() tea.Msg {
+				return ColumnSizesCommandMsg{}
+			},
+		},
+		{
+			ID:          "column-sizes-full",
+			Type:        models.CommandTypeAction,
+			Label:       "Column Sizes (Full Scan)",
+			Description: "Exact stored size per column of the active table; reads every row",
+			Icon:        "📐",
+			Tags:        []string{"size", "toast", "bloat", "bytes", "storage", "column", "exact"},
+			Action: func() tea.Msg {
+				return ColumnSizesCommandMsg{FullScan: true}
+			},
+		},
+		{
+			ID:          "compare-results",
+			Type:        models.CommandTypeAction,
+			Label:       "Compare With Other Result Tab",
+			Description: "Show per-cell differences from the split or previous result, matching rows by the selected column",
+			Icon:        "Δ",
+			Tags:        []string{"compare", "diff", "delta", "result", "tab"},
+			Action: func() tea.Msg {
+				return CompareResultsCommandMsg{}
+			},
+		},
+		{
+			ID:          "copy-rows-tsv",
+			Type:        models.CommandTypeAction,
+			Label:       "Copy Rows as TSV",
+			Description: "Copy the selected or pinned rows, or all loaded rows, with a header for pasting into a spreadsheet",
+			Icon:        "📋",
+			Tags:        []string{"copy", "tsv", "spreadsheet", "excel", "sheets", "clipboard", "export"},
+			Action: func() tea.Msg {
+				return CopyRowsTSVCommandMsg{}
+			},
+		},
+		{
+			ID:          "copy-rows-where",
+			Type:        models.CommandTypeAction,
+			Label:       "Copy Rows as WHERE",
+			Description: "Copy a condition matching the selected or pinned rows, or the current row, by every column of the primary key",
+			Icon:        "📋",
+			Tags:        []string{"copy", "where", "condition", "primary", "key", "sql", "clipboard"},
+			Action: func() tea.Msg {
+				return CopyRowsSQLCommandMsg{}
+			},
+		},
+		{
+			ID:          "copy-rows-insert",
+			Type:        models.CommandTypeAction,
+			Label:       "Copy Rows as INSERT",
+			Description: "Copy the selected or pinned rows, or the current row, as an INSERT skipping rows whose primary key is taken",
+			Icon:        "📋",
+			Tags:        []string{"copy", "insert", "sql", "seed", "fixture", "clipboard", "export"},
+			Action: func() tea.Msg {
+				return CopyRowsSQLCommandMsg{Insert: true}
+			},
+		},
+		{
+			ID:          "copy-plan-json",
+			Type:        models.CommandTypeAction,
+			Label:       "Copy Plan as JSON",
+			Description: "Copy the EXPLAIN (FORMAT JSON) plan in the active tab as the server returned it",
+			Icon:        "📋",
+			Tags:        []string{"copy", "explain", "plan", "json", "share", "clipboard", "export"},
+			Action: func() tea.Msg {
+				return CopyPlanCommandMsg{}
+			},
+		},
+		{
+			ID:          "copy-plan-text",
+			Type:        models.CommandTypeAction,
+			Label:       "Copy Plan as Text",
+			Description: "Copy the EXPLAIN plan in the active tab as indented text",
+			Icon:        "📋",
+			Tags:        []string{"copy", "explain", "plan", "text", "share", "clipboard", "export"},
+			Action: func() tea.Msg {
+				return CopyPlanCommandMsg{AsText: true}
+			},
+		},
+		{
+			ID:          "save-plan",
+			Type:        models.CommandTypeAction,
+			Label:       "Save Plan to Files",
+			Description: "Write the EXPLAIN plan in the active tab to plan.json and plan.txt",
+			Icon:        "💾",
+			Tags:        []string{"save", "explain", "plan", "json", "text", "share", "export"},
+			Action: func() tea.Msg {
+				return SavePlanCommandMsg{}
+			},
+		},
 	}
 }
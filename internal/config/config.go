@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -16,6 +17,7 @@ type Config struct {
 	Data        DataConfig        `mapstructure:"data"`
 	History     HistoryConfig     `mapstructure:"history"`
 	Performance PerformanceConfig `mapstructure:"performance"`
+	Connection  ConnectionConfig  `mapstructure:"connection"`
 }
 
 type GeneralConfig struct {
@@ -26,17 +28,33 @@ type GeneralConfig struct {
 
 type UIConfig struct {
 	Theme             string `mapstructure:"theme"`
+	ColorMode         string `mapstructure:"color_mode"` // auto, truecolor, 256 or 16
 	MouseEnabled      bool   `mapstructure:"mouse_enabled"`
 	PanelWidthRatio   int    `mapstructure:"panel_width_ratio"`
 	ShowBreadcrumbs   bool   `mapstructure:"show_breadcrumbs"`
 	CommandPaletteKey string `mapstructure:"command_palette_key"`
+	ShowSystemSchemas bool   `mapstructure:"show_system_schemas"`
+	ShowTreeCounts    bool   `mapstructure:"show_tree_counts"`   // Table/column counts on collapsed tree nodes
+	TabTitleTemplate  string `mapstructure:"tab_title_template"` // e.g. "{schema}.{name}"; empty for built-in titles
+	ShowGeneratedSQL  bool   `mapstructure:"show_generated_sql"` // Show the SQL behind table loads, sorts, filters and searches
+	MaxResultTabs     int    `mapstructure:"max_result_tabs"`    // Tabs kept open before the oldest closes; 0 for no limit
+	// TruncationWarnings colors the headers of columns shown at less than
+	// half the width of their values
+	TruncationWarnings bool `mapstructure:"truncation_warnings"`
+	// BoolGlyphs are drawn for true and false in boolean columns; empty
+	// shows the words
+	BoolGlyphs []string `mapstructure:"bool_glyphs"`
 }
 
 type EditorConfig struct {
-	TabSize      int  `mapstructure:"tab_size"`
-	UseSpaces    bool `mapstructure:"use_spaces"`
-	AutoComplete bool `mapstructure:"auto_complete"`
-	FormatOnSave bool `mapstructure:"format_on_save"`
+	TabSize         int  `mapstructure:"tab_size"`
+	UseSpaces       bool `mapstructure:"use_spaces"`
+	AutoComplete    bool `mapstructure:"auto_complete"`
+	FormatOnSave    bool `mapstructure:"format_on_save"`
+	QuickQueryLimit int  `mapstructure:"quick_query_limit"` // LIMIT for bare SELECTs from the SQL editor (0 disables)
+	SafeMode        bool `mapstructure:"safe_mode"`         // Run INSERT/UPDATE/DELETE in a transaction and ask before committing
+	DryRun          bool `mapstructure:"dry_run"`           // Show generated statements, e.g. row edits, instead of running them
+	AutoSaveSeconds int  `mapstructure:"auto_save_seconds"` // How often the editor is saved for crash recovery (0 disables)
 }
 
 type DataConfig struct {
@@ -47,6 +65,12 @@ type DataConfig struct {
 	PrefetchThreshold    int  `mapstructure:"prefetch_threshold"`
 	PrefetchSize         int  `mapstructure:"prefetch_size"`
 	MaxPinnedRows        int  `mapstructure:"max_pinned_rows"`
+	// MaskColumns hides values of columns whose names contain one of these
+	// strings; a /pattern/ is a regular expression. Matching ignores case.
+	MaskColumns []string `mapstructure:"mask_columns"`
+	MaskOnCopy  bool     `mapstructure:"mask_on_copy"`
+	// RowColors color the rows matching a rule like "status = 'error' -> red"
+	RowColors []string `mapstructure:"row_colors"`
 }
 
 type HistoryConfig struct {
@@ -62,6 +86,32 @@ type PerformanceConfig struct {
 	MetadataCacheTTL   int `mapstructure:"metadata_cache_ttl"`
 }
 
+type ConnectionConfig struct {
+	ApplicationName       string          `mapstructure:"application_name"`
+	IncludeConnectionName bool            `mapstructure:"include_connection_name"`
+	ConnectAttempts       int             `mapstructure:"connect_attempts"` // Tries per connection on transient errors; 1 disables retrying
+	OnConnectSQL          string          `mapstructure:"on_connect_sql"`   // Runs on every new pooled connection, e.g. SET timezone
+	WarnSuperuser         bool            `mapstructure:"warn_superuser"`   // Warn the first time a superuser connects
+	Discovery             DiscoveryConfig `mapstructure:"discovery"`
+}
+
+// DiscoveryConfig holds the defaults used when connecting to a discovered instance
+type DiscoveryConfig struct {
+	Database string                  `mapstructure:"database"`
+	User     string                  `mapstructure:"user"` // Empty means the current OS user
+	SSLMode  string                  `mapstructure:"sslmode"`
+	Hosts    []DiscoveryHostOverride `mapstructure:"hosts"`
+}
+
+// DiscoveryHostOverride overrides the discovery defaults for one host and port
+type DiscoveryHostOverride struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Database string `mapstructure:"database"`
+	User     string `mapstructure:"user"`
+	SSLMode  string `mapstructure:"sslmode"`
+}
+
 // GetDefaults returns a Config with all default values
 func GetDefaults() *Config {
 	return &Config{
@@ -72,16 +122,27 @@ func GetDefaults() *Config {
 		},
 		UI: UIConfig{
 			Theme:             "default",
+			ColorMode:         "auto",
 			MouseEnabled:      true,
 			PanelWidthRatio:   25,
 			ShowBreadcrumbs:   true,
 			CommandPaletteKey: "ctrl+k",
+			ShowSystemSchemas: false,
+			ShowTreeCounts:    false,
+			TabTitleTemplate:  "",
+			ShowGeneratedSQL:  false,
+			BoolGlyphs:        []string{"✓", "✗"},
+			MaxResultTabs:     10,
 		},
 		Editor: EditorConfig{
-			TabSize:      2,
-			UseSpaces:    true,
-			AutoComplete: true,
-			FormatOnSave: false,
+			TabSize:         2,
+			UseSpaces:       true,
+			AutoComplete:    true,
+			FormatOnSave:    false,
+			QuickQueryLimit: 100,
+			SafeMode:        false,
+			DryRun:          false,
+			AutoSaveSeconds: 30,
 		},
 		Data: DataConfig{
 			VirtualScrollBuffer:  100,
@@ -91,6 +152,9 @@ func GetDefaults() *Config {
 			PrefetchThreshold:    50,
 			PrefetchSize:         100,
 			MaxPinnedRows:        5,
+			MaskColumns:          []string{},
+			MaskOnCopy:           false,
+			RowColors:            []string{},
 		},
 		History: HistoryConfig{
 			Enabled:           true,
@@ -103,40 +167,115 @@ func GetDefaults() *Config {
 			QueryTimeout:       30000,
 			MetadataCacheTTL:   300,
 		},
+		Connection: ConnectionConfig{
+			ApplicationName:       "lazypg",
+			IncludeConnectionName: false,
+			ConnectAttempts:       3,
+			OnConnectSQL:          "",
+			WarnSuperuser:         true,
+			Discovery: DiscoveryConfig{
+				Database: "postgres",
+				User:     "",
+				SSLMode:  "prefer",
+			},
+		},
 	}
 }
 
-// Load loads configuration from files
-func Load() (*Config, error) {
+// DirEnv is the environment variable that moves the config directory
+const DirEnv = "LAZYPG_CONFIG_DIR"
+
+// Override returns the config directory asked for with the --config-dir
+// flag (flagValue) or else DirEnv, or "" to use the default
+func Override(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return os.Getenv(DirEnv)
+}
+
+// Dir returns the directory lazypg keeps history, favorites, connections
+// and saved passwords in, creating it if missing. A non-empty override (see
+// Override) replaces the default ~/.config/lazypg; a leading ~ in it is
+// expanded.
+func Dir(override string) (string, error) {
+	dir := override
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		dir = filepath.Join(homeDir, ".config", "lazypg")
+	} else {
+		dir = expandHome(dir)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return dir, fmt.Errorf("failed to create config directory %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
+
+// Load loads configuration from files. With an override directory (see
+// Override), config.yaml is only read from there.
+func Load(override string) (*Config, error) {
 	v := viper.New()
 
 	// Set config name and type
 	v.SetConfigName("config")
 	v.SetConfigType("yaml")
 
-	// Add config paths in priority order
-	// 1. User config directory
-	if configDir, err := os.UserConfigDir(); err == nil {
-		v.AddConfigPath(filepath.Join(configDir, "lazypg"))
-	}
+	if override != "" {
+		v.AddConfigPath(expandHome(override))
+	} else {
+		// Add config paths in priority order
+		// 1. User config directory
+		if configDir, err := os.UserConfigDir(); err == nil {
+			v.AddConfigPath(filepath.Join(configDir, "lazypg"))
+		}
 
-	// 2. Current directory
-	v.AddConfigPath(".")
+		// 2. Current directory
+		v.AddConfigPath(".")
 
-	// 3. Default config directory
-	v.AddConfigPath("./config")
+		// 3. Default config directory
+		v.AddConfigPath("./config")
+	}
 
 	// Set defaults from default.yaml
 	v.SetDefault("general.auto_connect_last", false)
 	v.SetDefault("general.confirm_destructive_ops", true)
 	v.SetDefault("general.default_limit", 100)
 	v.SetDefault("ui.theme", "default")
+	v.SetDefault("ui.color_mode", "auto")
 	v.SetDefault("ui.mouse_enabled", true)
 	v.SetDefault("ui.panel_width_ratio", 25)
 	v.SetDefault("ui.show_breadcrumbs", true)
 	v.SetDefault("ui.command_palette_key", "ctrl+k")
+	v.SetDefault("ui.show_system_schemas", false)
+	v.SetDefault("ui.show_tree_counts", false)
+	v.SetDefault("ui.tab_title_template", "")
+	v.SetDefault("ui.show_generated_sql", false)
+	v.SetDefault("ui.bool_glyphs", []string{"✓", "✗"})
+	v.SetDefault("ui.max_result_tabs", 10)
 	v.SetDefault("editor.tab_size", 2)
 	v.SetDefault("editor.use_spaces", true)
+	v.SetDefault("editor.quick_query_limit", 100)
+	v.SetDefault("editor.safe_mode", false)
+	v.SetDefault("editor.auto_save_seconds", 30)
 	v.SetDefault("editor.auto_complete", true)
 	v.SetDefault("editor.format_on_save", false)
 	v.SetDefault("data.virtual_scroll_buffer", 100)
@@ -146,6 +285,9 @@ func Load() (*Config, error) {
 	v.SetDefault("data.prefetch_threshold", 50)
 	v.SetDefault("data.prefetch_size", 100)
 	v.SetDefault("data.max_pinned_rows", 5)
+	v.SetDefault("data.mask_columns", []string{})
+	v.SetDefault("data.mask_on_copy", false)
+	v.SetDefault("data.row_colors", []string{})
 	v.SetDefault("history.enabled", true)
 	v.SetDefault("history.max_entries", 1000)
 	v.SetDefault("history.persist", true)
@@ -153,6 +295,14 @@ func Load() (*Config, error) {
 	v.SetDefault("performance.connection_pool_size", 10)
 	v.SetDefault("performance.query_timeout", 30000)
 	v.SetDefault("performance.metadata_cache_ttl", 300)
+	v.SetDefault("connection.application_name", "lazypg")
+	v.SetDefault("connection.include_connection_name", false)
+	v.SetDefault("connection.connect_attempts", 3)
+	v.SetDefault("connection.on_connect_sql", "")
+	v.SetDefault("connection.warn_superuser", true)
+	v.SetDefault("connection.discovery.database", "postgres")
+	v.SetDefault("connection.discovery.user", "")
+	v.SetDefault("connection.discovery.sslmode", "prefer")
 
 	// Read config (it's okay if file doesn't exist, we have defaults)
 	if err := v.ReadInConfig(); err != nil {
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverride(t *testing.T) {
+	t.Setenv(DirEnv, "/from/env")
+	if got := Override("/from/flag"); got != "/from/flag" {
+		t.Errorf("Override() with a flag = %q, want the flag", got)
+	}
+	if got := Override(""); got != "/from/env" {
+		t.Errorf("Override() without a flag = %q, want $%s", got, DirEnv)
+	}
+}
+
+func TestDirCreatesOverride(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "lazypg")
+	got, err := Dir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != dir {
+		t.Errorf("Dir() = %q, want %q", got, dir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("Dir() did not create %s: %v", dir, err)
+	}
+}
+
+func TestLoadFromOverride(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "ui:\n  theme: nord\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.UI.Theme != "nord" {
+		t.Errorf("theme = %q, want the one from the override's config.yaml", cfg.UI.Theme)
+	}
+	// Settings the file leaves out keep their defaults
+	if cfg.General.DefaultLimit != 100 {
+		t.Errorf("default_limit = %d, want the default", cfg.General.DefaultLimit)
+	}
+}
@@ -27,6 +27,7 @@ type Manager struct {
 	configDir     string
 	history       []models.ConnectionHistoryEntry
 	passwordStore *PasswordStore
+	order         Order // How the connection dialog lists the history
 }
 
 // NewManager creates a new connection history manager
@@ -57,6 +58,7 @@ func NewManager(configDir string) (*Manager, error) {
 			return nil, fmt.Errorf("failed to load connection history: %w", err)
 		}
 	}
+	m.loadPreferences()
 
 	return m, nil
 }
@@ -187,13 +189,35 @@ func (m *Manager) GetRecent(limit int) []models.ConnectionHistoryEntry {
 	return sorted
 }
 
-// GetMostUsed returns the most frequently used connections
+// GetRecentWithPinned returns the most recently used connections plus every
+// pinned one, however long ago it was used
+func (m *Manager) GetRecentWithPinned(limit int) []models.ConnectionHistoryEntry {
+	return withPinned(m.GetRecent(0), limit)
+}
+
+// TogglePin pins or unpins a connection by ID and returns whether it is
+// now pinned
+func (m *Manager) TogglePin(id string) (bool, error) {
+	for i := range m.history {
+		if m.history[i].ID == id {
+			m.history[i].Pinned = !m.history[i].Pinned
+			return m.history[i].Pinned, m.Save()
+		}
+	}
+	return false, fmt.Errorf("connection history entry with ID '%s' not found", id)
+}
+
+// GetMostUsed returns the most frequently used connections, the most
+// recently used first among equally used ones
 func (m *Manager) GetMostUsed(limit int) []models.ConnectionHistoryEntry {
 	sorted := make([]models.ConnectionHistoryEntry, len(m.history))
 	copy(sorted, m.history)
 
 	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].UsageCount > sorted[j].UsageCount
+		if sorted[i].UsageCount != sorted[j].UsageCount {
+			return sorted[i].UsageCount > sorted[j].UsageCount
+		}
+		return sorted[i].LastUsed.After(sorted[j].LastUsed)
 	})
 
 	if limit > 0 && limit < len(sorted) {
@@ -265,3 +289,118 @@ func (m *Manager) SavePassword(host string, port int, database, user, password s
 	}
 	return m.passwordStore.Save(host, port, database, user, password)
 }
+
+// ImportResult summarizes a connection history import
+type ImportResult struct {
+	Imported   int
+	Duplicates int      // Same host, port, database, and user already present
+	Conflicts  []string // Existing entries with a different name or SSL mode (kept as-is)
+	Invalid    []string // Entries skipped because they failed validation
+}
+
+// Export writes connection history to a YAML file. Passwords live in the
+// keyring and are never part of history entries, so none are exported.
+func (m *Manager) Export(customPath ...string) (string, error) {
+	if len(m.history) == 0 {
+		return "", fmt.Errorf("no connection history to export")
+	}
+
+	path := filepath.Join(m.configDir, "connection_history_export.yaml")
+	if len(customPath) > 0 && customPath[0] != "" {
+		path = customPath[0]
+	}
+
+	data, err := yaml.Marshal(m.history)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal connection history: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write export file: %w", err)
+	}
+
+	return path, nil
+}
+
+// Import merges connection history from a file produced by Export.
+// Existing entries are never overwritten: matches are counted as duplicates,
+// or reported as conflicts when their name or SSL mode differ.
+func (m *Manager) Import(customPath ...string) (*ImportResult, error) {
+	path := filepath.Join(m.configDir, "connection_history_export.yaml")
+	if len(customPath) > 0 && customPath[0] != "" {
+		path = customPath[0]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	var incoming []models.ConnectionHistoryEntry
+	if err := yaml.Unmarshal(data, &incoming); err != nil {
+		return nil, fmt.Errorf("failed to parse import file: %w", err)
+	}
+
+	result := &ImportResult{}
+	for i, entry := range incoming {
+		if err := validateEntry(entry); err != nil {
+			result.Invalid = append(result.Invalid, fmt.Sprintf("entry %d: %v", i+1, err))
+			continue
+		}
+
+		if existing := m.findEntry(entry); existing != nil {
+			if existing.Name != entry.Name || existing.SSLMode != entry.SSLMode {
+				result.Conflicts = append(result.Conflicts, existing.Name)
+			} else {
+				result.Duplicates++
+			}
+			continue
+		}
+
+		// Always assign a fresh ID so imported entries cannot collide
+		entry.ID = uuid.New().String()
+		if entry.Name == "" {
+			entry.Name = fmt.Sprintf("%s@%s:%d/%s", entry.User, entry.Host, entry.Port, entry.Database)
+		}
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = time.Now()
+		}
+
+		m.history = append(m.history, entry)
+		result.Imported++
+	}
+
+	if result.Imported > 0 {
+		if err := m.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// findEntry returns the history entry for the same host, port, database, and user
+func (m *Manager) findEntry(entry models.ConnectionHistoryEntry) *models.ConnectionHistoryEntry {
+	for i := range m.history {
+		h := &m.history[i]
+		if h.Host == entry.Host && h.Port == entry.Port && h.Database == entry.Database && h.User == entry.User {
+			return h
+		}
+	}
+	return nil
+}
+
+// validateEntry checks that an imported entry has usable connection details
+func validateEntry(entry models.ConnectionHistoryEntry) error {
+	switch {
+	case entry.Host == "":
+		return fmt.Errorf("host is required")
+	case entry.Port < 1 || entry.Port > 65535:
+		return fmt.Errorf("invalid port %d", entry.Port)
+	case entry.Database == "":
+		return fmt.Errorf("database is required")
+	case entry.User == "":
+		return fmt.Errorf("user is required")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package connection_history
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/rebelice/lazypg/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Order is how the connection dialog lists the history
+type Order string
+
+const (
+	OrderRecent   Order = "recent"    // Most recently used first
+	OrderMostUsed Order = "most_used" // Most connected to first
+)
+
+// preferences are the connection dialog settings kept next to the history
+type preferences struct {
+	Order Order `yaml:"order"`
+}
+
+// preferencesPath returns where the dialog preferences are kept
+func (m *Manager) preferencesPath() string {
+	return filepath.Join(m.configDir, "connection_history_prefs.yaml")
+}
+
+// loadPreferences reads the dialog preferences. A missing or unreadable
+// file leaves the defaults, since losing a sort order is harmless.
+func (m *Manager) loadPreferences() {
+	m.order = OrderRecent
+	data, err := os.ReadFile(m.preferencesPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to read connection history preferences: %v", err)
+		}
+		return
+	}
+	var prefs preferences
+	if err := yaml.Unmarshal(data, &prefs); err != nil {
+		log.Printf("Warning: Failed to parse connection history preferences: %v", err)
+		return
+	}
+	if prefs.Order == OrderMostUsed {
+		m.order = OrderMostUsed
+	}
+}
+
+// Order returns how the connection dialog lists the history
+func (m *Manager) Order() Order {
+	if m.order == "" {
+		return OrderRecent
+	}
+	return m.order
+}
+
+// SetOrder changes how the connection dialog lists the history and saves
+// it for the next session
+func (m *Manager) SetOrder(order Order) error {
+	m.order = order
+	data, err := yaml.Marshal(preferences{Order: order})
+	if err != nil {
+		return fmt.Errorf("failed to marshal connection history preferences: %w", err)
+	}
+	if err := os.MkdirAll(m.configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(m.preferencesPath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write connection history preferences: %w", err)
+	}
+	return nil
+}
+
+// GetOrderedWithPinned returns the first limit connections in the chosen
+// order plus every pinned one, however far down it is
+func (m *Manager) GetOrderedWithPinned(limit int) []models.ConnectionHistoryEntry {
+	if m.Order() == OrderMostUsed {
+		return withPinned(m.GetMostUsed(0), limit)
+	}
+	return m.GetRecentWithPinned(limit)
+}
+
+// withPinned keeps the first limit entries and every pinned one
+func withPinned(sorted []models.ConnectionHistoryEntry, limit int) []models.ConnectionHistoryEntry {
+	if limit <= 0 || limit >= len(sorted) {
+		return sorted
+	}
+
+	var entries []models.ConnectionHistoryEntry
+	for i, entry := range sorted {
+		if i < limit || entry.Pinned {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
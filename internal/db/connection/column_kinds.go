@@ -0,0 +1,60 @@
+package connection
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// firstNormalObjectID is the first OID handed out to user-created objects;
+// every built-in type sits below it
+const firstNormalObjectID = 16384
+
+// ResolveColumnKinds classifies result columns by type OID. Enum types are
+// user-defined, so their OIDs are looked up in pg_type; the lookup is skipped
+// when every column has a built-in type. A failed lookup leaves those
+// columns plain rather than failing the query.
+func ResolveColumnKinds(ctx context.Context, pool *pgxpool.Pool, oids []uint32) []models.ColumnKind {
+	kinds := make([]models.ColumnKind, len(oids))
+	var userOIDs []uint32
+	for i, oid := range oids {
+		kinds[i] = models.ColumnKindForOID(oid)
+		if oid >= firstNormalObjectID {
+			userOIDs = append(userOIDs, oid)
+		}
+	}
+	if len(userOIDs) == 0 || pool == nil {
+		return kinds
+	}
+
+	rows, err := pool.Query(ctx, `SELECT oid FROM pg_type WHERE typtype = 'e' AND oid = ANY($1)`, userOIDs)
+	if err != nil {
+		return kinds
+	}
+	defer rows.Close()
+
+	enums := make(map[uint32]bool)
+	for rows.Next() {
+		var oid uint32
+		if err := rows.Scan(&oid); err != nil {
+			return kinds
+		}
+		enums[oid] = true
+	}
+	if rows.Err() != nil {
+		return kinds
+	}
+
+	for i, oid := range oids {
+		if enums[oid] {
+			kinds[i] = models.ColumnKindEnum
+		}
+	}
+	return kinds
+}
+
+// ColumnKinds classifies result columns by type OID (see ResolveColumnKinds)
+func (p *Pool) ColumnKinds(ctx context.Context, oids []uint32) []models.ColumnKind {
+	return ResolveColumnKinds(ctx, p.pool, oids)
+}
@@ -0,0 +1,359 @@
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// SQLSTATE codes for connection failures that the user can fix by
+// supplying different credentials
+const (
+	sqlStateInvalidPassword      = "28P01" // invalid_password
+	sqlStateInvalidAuthorization = "28000" // invalid_authorization_specification
+	sqlStateInvalidCatalogName   = "3D000" // invalid_catalog_name
+)
+
+// SQLSTATE codes for connection failures that may succeed if tried again
+const (
+	sqlStateCannotConnectNow   = "57P03" // cannot_connect_now, e.g. the server is starting up
+	sqlStateTooManyConnections = "53300" // too_many_connections
+	sqlStateConnectionClass    = "08"    // connection_exception and its subclasses
+)
+
+// SQLSTATE codes for a session the server ended
+const (
+	sqlStateAdminShutdown      = "57P01" // admin_shutdown: pg_terminate_backend or a server shutdown
+	sqlStateCrashShutdown      = "57P02" // crash_shutdown
+	sqlStateDatabaseDropped    = "57P04" // database_dropped
+	sqlStateIdleSessionTimeout = "57P05" // idle_session_timeout
+)
+
+// Backoff between connection attempts: doubling from retryBaseDelay, capped
+// at retryMaxDelay
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 8 * time.Second
+)
+
+// IsPasswordError reports whether a connection failed because the server
+// requires a password that was missing or wrong
+func IsPasswordError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == sqlStateInvalidPassword
+	}
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "password")
+}
+
+// IsCredentialError reports whether a connection failed because the
+// database or role does not exist (or the role may not connect)
+func IsCredentialError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == sqlStateInvalidCatalogName ||
+			pgErr.Code == sqlStateInvalidAuthorization && !requiresEncryption(pgErr)
+	}
+	return false
+}
+
+// requiresEncryption reports whether the server rejected a connection
+// because pg_hba.conf only allows this host and user over SSL. Servers
+// before 14 say "SSL off", later ones "no encryption".
+func requiresEncryption(pgErr *pgconn.PgError) bool {
+	return pgErr.Code == sqlStateInvalidAuthorization &&
+		(strings.Contains(pgErr.Message, "no encryption") || strings.Contains(pgErr.Message, "SSL off"))
+}
+
+// IsRetryable reports whether a connection attempt failed for a reason that
+// may go away on its own: the server refusing or dropping the connection,
+// a timeout, or the server starting up or being out of slots. Errors the
+// user has to fix, like a wrong password, an unknown database or host, or a
+// TLS mismatch, are not retryable, and neither is a cancelled attempt.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == sqlStateCannotConnectNow ||
+			pgErr.Code == sqlStateTooManyConnections ||
+			strings.HasPrefix(pgErr.Code, sqlStateConnectionClass)
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		// An unknown host stays unknown; a failing resolver may recover
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ETIMEDOUT) ||
+		errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.ENETUNREACH) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// RetryDelay returns how long to wait before the next connection attempt,
+// after failed attempts so far (1 or more)
+func RetryDelay(failed int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < failed && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// FailureKind is the stage a connection attempt failed at
+type FailureKind int
+
+const (
+	FailureOther    FailureKind = iota
+	FailureNetwork              // Host not found, unreachable, refused or timed out
+	FailureTLS                  // SSL negotiation or certificate verification
+	FailureAuth                 // Password or role rejected
+	FailureDatabase             // The database does not exist
+)
+
+// String names the kind for the error overlay's title
+func (k FailureKind) String() string {
+	switch k {
+	case FailureNetwork:
+		return "network"
+	case FailureTLS:
+		return "SSL"
+	case FailureAuth:
+		return "authentication"
+	case FailureDatabase:
+		return "database"
+	default:
+		return "connection"
+	}
+}
+
+// ConnectFailure explains a failed connection attempt: what went wrong and
+// what to try. Summary is empty when the error wasn't recognized.
+type ConnectFailure struct {
+	Kind    FailureKind
+	Summary string
+	Hint    string
+}
+
+// Title is the error overlay title for the failure
+func (f ConnectFailure) Title() string {
+	if f.Kind == FailureOther {
+		return "Connection Failed"
+	}
+	return fmt.Sprintf("Connection Failed (%s)", f.Kind)
+}
+
+// Describe explains the failure of connecting to host:port with err,
+// keeping the server's own message at the end
+func (f ConnectFailure) Describe(host string, port int, err error) string {
+	if f.Summary == "" {
+		return fmt.Sprintf("Could not connect to %s:%d\n\nError: %v", host, port, err)
+	}
+	msg := fmt.Sprintf("Could not connect to %s:%d\n\n%s", host, port, f.Summary)
+	if f.Hint != "" {
+		msg += "\n\n→ " + f.Hint
+	}
+	return msg + fmt.Sprintf("\n\nError: %v", err)
+}
+
+// ClassifyConnectError works out why connecting with config failed, from
+// the pgconn, TLS and network errors wrapped in err
+func ClassifyConnectError(err error, config models.ConnectionConfig) ConnectFailure {
+	sslMode := config.SSLMode
+	if sslMode == "" {
+		sslMode = "prefer"
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == sqlStateInvalidPassword:
+			return ConnectFailure{FailureAuth,
+				fmt.Sprintf("Password authentication failed for user %q.", config.User),
+				"Check the password and user name."}
+		case requiresEncryption(pgErr):
+			return ConnectFailure{FailureTLS,
+				"The server requires SSL for this host and user.",
+				"Server requires SSL — try sslmode=require."}
+		case pgErr.Code == sqlStateInvalidAuthorization && strings.Contains(pgErr.Message, "pg_hba.conf"):
+			return ConnectFailure{FailureAuth,
+				fmt.Sprintf("The server's pg_hba.conf doesn't let user %q connect to %q from this host.", config.User, config.Database),
+				"Check the user and database, or ask the administrator to allow this host."}
+		case pgErr.Code == sqlStateInvalidAuthorization:
+			return ConnectFailure{FailureAuth,
+				fmt.Sprintf("The server rejected user %q.", config.User),
+				"Check that the role exists and has the LOGIN attribute."}
+		case pgErr.Code == sqlStateInvalidCatalogName:
+			return ConnectFailure{FailureDatabase,
+				fmt.Sprintf("Database %q does not exist.", config.Database),
+				"Check the name, or connect to the postgres database to see which databases exist."}
+		case pgErr.Code == sqlStateTooManyConnections:
+			return ConnectFailure{FailureOther,
+				"The server has no free connection slots.",
+				"Close idle sessions or try again later."}
+		case pgErr.Code == sqlStateCannotConnectNow:
+			return ConnectFailure{FailureOther,
+				"The server is starting up or shutting down.",
+				"Try again in a moment."}
+		}
+		return ConnectFailure{}
+	}
+
+	if strings.Contains(err.Error(), "server refused TLS connection") {
+		return ConnectFailure{FailureTLS,
+			fmt.Sprintf("The server doesn't support SSL, which sslmode=%s requires.", sslMode),
+			"Try sslmode=prefer, or enable ssl on the server."}
+	}
+	var hostErr x509.HostnameError
+	if errors.As(err, &hostErr) {
+		return ConnectFailure{FailureTLS,
+			fmt.Sprintf("The server's certificate isn't valid for host %q.", config.Host),
+			"Connect using the host name on the certificate, or use sslmode=verify-ca or require."}
+	}
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &authorityErr) || errors.As(err, &invalidErr) || errors.As(err, &verifyErr) {
+		return ConnectFailure{FailureTLS,
+			"The server's certificate couldn't be verified.",
+			fmt.Sprintf("sslmode=%s checks the certificate; sslmode=require encrypts without checking it.", sslMode)}
+	}
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &recordErr) || strings.Contains(err.Error(), "tls error") {
+		return ConnectFailure{FailureTLS,
+			"SSL negotiation with the server failed.",
+			"Check the port is PostgreSQL's, or try another sslmode."}
+	}
+
+	if strings.Contains(err.Error(), "failed SASL auth") || IsPasswordError(err) {
+		return ConnectFailure{FailureAuth,
+			fmt.Sprintf("Password authentication failed for user %q.", config.User),
+			"Check the password and user name."}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return ConnectFailure{FailureNetwork,
+				fmt.Sprintf("Host %q could not be found.", config.Host),
+				"Check the host name."}
+		}
+		return ConnectFailure{FailureNetwork,
+			fmt.Sprintf("Looking up host %q failed.", config.Host),
+			"Check your network connection and DNS."}
+	}
+
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ConnectFailure{FailureNetwork,
+			fmt.Sprintf("Nothing is accepting connections at %s:%d.", config.Host, config.Port),
+			"Check that the server is running and listening on that port."}
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return ConnectFailure{FailureNetwork,
+			fmt.Sprintf("Host %q is unreachable.", config.Host),
+			"Check your network connection or VPN."}
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, syscall.ETIMEDOUT):
+		return ConnectFailure{FailureNetwork,
+			"The server didn't answer in time.",
+			"Check the host and port, and any firewall in between."}
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		hint := "Check the port is PostgreSQL's."
+		if sslMode == "disable" || sslMode == "allow" {
+			hint = "If the server only accepts SSL, try sslmode=require."
+		}
+		return ConnectFailure{FailureNetwork, "The server closed the connection during startup.", hint}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ConnectFailure{FailureNetwork,
+			"The server didn't answer in time.",
+			"Check the host and port, and any firewall in between."}
+	}
+	return ConnectFailure{}
+}
+
+// SessionLoss is how an open connection stopped working
+type SessionLoss int
+
+const (
+	SessionAlive        SessionLoss = iota
+	SessionTerminated               // The backend was terminated or the connection dropped
+	SessionDatabaseGone             // The database was dropped or no longer exists
+)
+
+// ClassifySessionLoss tells whether a query failed because its session is
+// gone rather than because of the query itself: the backend was terminated
+// (pg_terminate_backend, a server shutdown or crash, idle_session_timeout),
+// the connection closed under it, or its database was dropped. A query
+// naming a missing database (DROP DATABASE nosuch) also fails with
+// invalid_catalog_name, so that code only counts when the pool failed to
+// connect to the database again.
+func ClassifySessionLoss(err error) SessionLoss {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return SessionAlive
+	}
+
+	var connectErr *pgconn.ConnectError
+	reconnecting := errors.As(err, &connectErr)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == sqlStateDatabaseDropped,
+			pgErr.Code == sqlStateInvalidCatalogName && reconnecting:
+			return SessionDatabaseGone
+		case pgErr.Code == sqlStateAdminShutdown, pgErr.Code == sqlStateCrashShutdown,
+			pgErr.Code == sqlStateIdleSessionTimeout, strings.HasPrefix(pgErr.Code, sqlStateConnectionClass):
+			return SessionTerminated
+		}
+		return SessionAlive
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, net.ErrClosed) {
+		return SessionTerminated
+	}
+
+	// pgx reports some of these only as text, e.g. after the error was
+	// wrapped without %w
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "(SQLSTATE "+sqlStateDatabaseDropped+")"),
+		strings.Contains(msg, "(SQLSTATE "+sqlStateInvalidCatalogName+")") && strings.Contains(msg, "failed to connect to"):
+		return SessionDatabaseGone
+	case strings.Contains(msg, "(SQLSTATE "+sqlStateAdminShutdown+")"),
+		strings.Contains(msg, "(SQLSTATE "+sqlStateCrashShutdown+")"),
+		strings.Contains(msg, "(SQLSTATE "+sqlStateIdleSessionTimeout+")"),
+		strings.Contains(msg, "conn closed"),
+		strings.Contains(msg, "unexpected EOF"):
+		return SessionTerminated
+	}
+	return SessionAlive
+}
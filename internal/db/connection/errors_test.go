@@ -0,0 +1,135 @@
+package connection
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+func TestIsRetryable(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"connection refused", fmt.Errorf("failed to ping database: %w", refused), true},
+		{"timeout", fmt.Errorf("failed to ping database: %w", context.DeadlineExceeded), true},
+		{"server starting up", &pgconn.PgError{Code: "57P03"}, true},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"temporary DNS failure", &net.DNSError{Err: "server misbehaving", Name: "db", IsTemporary: true}, true},
+		{"cancelled", fmt.Errorf("failed to ping database: %w", context.Canceled), false},
+		{"wrong password", &pgconn.PgError{Code: "28P01"}, false},
+		{"unknown role", &pgconn.PgError{Code: "28000"}, false},
+		{"unknown database", fmt.Errorf("failed to ping database: %w", &pgconn.PgError{Code: "3D000"}), false},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "nope", IsNotFound: true}, false},
+		{"bad config", fmt.Errorf("failed to parse connection config: invalid port"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second}
+	for i, w := range want {
+		if got := RetryDelay(i + 1); got != w {
+			t.Errorf("RetryDelay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestClassifyConnectError(t *testing.T) {
+	config := models.ConnectionConfig{Host: "db.example.com", Port: 5432, User: "app", Database: "shop", SSLMode: "require"}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantKind FailureKind
+		wantHint string // Substring of the hint
+	}{
+		{"wrong password", &pgconn.PgError{Code: "28P01", Message: `password authentication failed for user "app"`}, FailureAuth, "password"},
+		{"hostssl only", &pgconn.PgError{Code: "28000", Message: `no pg_hba.conf entry for host "10.0.0.5", user "app", database "shop", no encryption`}, FailureTLS, "sslmode=require"},
+		{"hostssl only, old server", &pgconn.PgError{Code: "28000", Message: `no pg_hba.conf entry for host "10.0.0.5", user "app", database "shop", SSL off`}, FailureTLS, "sslmode=require"},
+		{"no hba entry", &pgconn.PgError{Code: "28000", Message: `no pg_hba.conf entry for host "10.0.0.5", user "app", database "shop", SSL encryption`}, FailureAuth, "allow this host"},
+		{"unknown role", &pgconn.PgError{Code: "28000", Message: `role "app" does not exist`}, FailureAuth, "LOGIN"},
+		{"unknown database", fmt.Errorf("failed to connect: %w", &pgconn.PgError{Code: "3D000"}), FailureDatabase, "postgres database"},
+		{"server without SSL", fmt.Errorf("failed to connect: %w", errors.New("server refused TLS connection")), FailureTLS, "sslmode=prefer"},
+		{"untrusted certificate", fmt.Errorf("tls error: %w", x509.UnknownAuthorityError{}), FailureTLS, "sslmode=require"},
+		{"wrong certificate host", fmt.Errorf("tls error: %w", x509.HostnameError{Host: "db.example.com"}), FailureTLS, "host name"},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "db.example.com", IsNotFound: true}, FailureNetwork, "host name"},
+		{"refused", fmt.Errorf("failed to connect: %w", refused), FailureNetwork, "running"},
+		{"timeout", fmt.Errorf("failed to connect: %w", context.DeadlineExceeded), FailureNetwork, "firewall"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyConnectError(tt.err, config)
+			if got.Kind != tt.wantKind || got.Summary == "" || !strings.Contains(got.Hint, tt.wantHint) {
+				t.Errorf("ClassifyConnectError() = %+v, want kind %v with a hint about %q", got, tt.wantKind, tt.wantHint)
+			}
+		})
+	}
+
+	// A connection that merely needs SSL isn't a wrong database or user
+	if IsCredentialError(&pgconn.PgError{Code: "28000", Message: "no pg_hba.conf entry for host, no encryption"}) {
+		t.Error("IsCredentialError() = true for a server requiring SSL")
+	}
+
+	unknown := ClassifyConnectError(errors.New("something odd"), config)
+	if unknown.Summary != "" || unknown.Title() != "Connection Failed" {
+		t.Errorf("ClassifyConnectError() of an unknown error = %+v", unknown)
+	}
+	if msg := unknown.Describe("db", 5432, errors.New("something odd")); !strings.Contains(msg, "something odd") {
+		t.Errorf("Describe() = %q, want the original error", msg)
+	}
+}
+
+func TestClassifySessionLoss(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want SessionLoss
+	}{
+		{"nil", nil, SessionAlive},
+		{"query error", &pgconn.PgError{Code: "42P01"}, SessionAlive},
+		{"cancelled", fmt.Errorf("statement 1: %w", context.Canceled), SessionAlive},
+		{"terminated backend", fmt.Errorf("statement 1: %w", &pgconn.PgError{Code: "57P01"}), SessionTerminated},
+		{"idle session timeout", &pgconn.PgError{Code: "57P05"}, SessionTerminated},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, SessionTerminated},
+		{"closed under the query", fmt.Errorf("failed to receive message: %w", io.ErrUnexpectedEOF), SessionTerminated},
+		{"reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, SessionTerminated},
+		{"drop database nosuch", fmt.Errorf("statement 1: %w", &pgconn.PgError{Code: "3D000", Message: `database "nosuch" does not exist`}), SessionAlive},
+		{"drop database nosuch text", errors.New(`ERROR: database "nosuch" does not exist (SQLSTATE 3D000)`), SessionAlive},
+		{"database dropped", &pgconn.PgError{Code: "57P04"}, SessionDatabaseGone},
+		{"pool reconnects to a dropped database", errors.New("failed to connect to `user=app database=shop`: FATAL: database \"shop\" does not exist (SQLSTATE 3D000)"), SessionDatabaseGone},
+		{"conn closed text", errors.New("failed to load table data: conn closed"), SessionTerminated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifySessionLoss(tt.err); got != tt.want {
+				t.Errorf("ClassifySessionLoss(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// Notification is a payload received from NOTIFY / pg_notify
+type Notification struct {
+	Channel    string
+	Payload    string
+	PID        uint32
+	ReceivedAt time.Time
+}
+
+// listenRequest asks the listener loop to run LISTEN or UNLISTEN
+type listenRequest struct {
+	sql    string
+	result chan error
+}
+
+// Listener holds a dedicated connection for LISTEN/NOTIFY, separate from the
+// query pool so that waiting for notifications never blocks query execution
+// and long queries never delay notifications.
+type Listener struct {
+	conn          *pgx.Conn
+	notifications chan Notification
+	requests      chan listenRequest
+	cancel        context.CancelFunc
+	done          chan struct{}
+
+	mu         sync.Mutex
+	channels   map[string]bool
+	cancelWait context.CancelFunc
+	err        error
+}
+
+// NewListener opens a dedicated connection and starts waiting for notifications
+func NewListener(ctx context.Context, config models.ConnectionConfig) (*Listener, error) {
+	connConfig, err := pgx.ParseConfig(buildConnectionString(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection config: %w", err)
+	}
+	if config.ApplicationName != "" {
+		connConfig.RuntimeParams["application_name"] = config.ApplicationName
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, connConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open listener connection: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	l := &Listener{
+		conn:          conn,
+		notifications: make(chan Notification, 64),
+		requests:      make(chan listenRequest, 8),
+		cancel:        cancel,
+		done:          make(chan struct{}),
+		channels:      make(map[string]bool),
+	}
+	go l.run(runCtx)
+	return l, nil
+}
+
+// Notifications returns the channel notifications are delivered on. It is
+// closed when the listener stops.
+func (l *Listener) Notifications() <-chan Notification {
+	return l.notifications
+}
+
+// Err returns the error that stopped the listener, if any
+func (l *Listener) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Channels returns the channels currently listened on, sorted by name
+func (l *Listener) Channels() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	channels := make([]string, 0, len(l.channels))
+	for ch := range l.channels {
+		channels = append(channels, ch)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Listen subscribes to a channel
+func (l *Listener) Listen(ctx context.Context, channel string) error {
+	if err := l.do(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.channels[channel] = true
+	l.mu.Unlock()
+	return nil
+}
+
+// Unlisten unsubscribes from a channel
+func (l *Listener) Unlisten(ctx context.Context, channel string) error {
+	if err := l.do(ctx, "UNLISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	delete(l.channels, channel)
+	l.mu.Unlock()
+	return nil
+}
+
+// Close unlistens from all channels and closes the dedicated connection
+func (l *Listener) Close() {
+	select {
+	case <-l.done:
+		return
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = l.do(ctx, "UNLISTEN *")
+
+	l.cancel()
+	<-l.done
+}
+
+// do hands a statement to the listener loop and waits for its result.
+// The loop owns the connection, so the current wait is interrupted first.
+func (l *Listener) do(ctx context.Context, sql string) error {
+	req := listenRequest{sql: sql, result: make(chan error, 1)}
+	select {
+	case l.requests <- req:
+	case <-l.done:
+		return fmt.Errorf("listener is closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	if l.cancelWait != nil {
+		l.cancelWait()
+	}
+	l.mu.Unlock()
+
+	select {
+	case err := <-req.result:
+		return err
+	case <-l.done:
+		return fmt.Errorf("listener is closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run waits for notifications and services LISTEN/UNLISTEN requests until
+// the listener is closed or the connection fails
+func (l *Listener) run(ctx context.Context) {
+	defer close(l.done)
+	defer close(l.notifications)
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = l.conn.Close(closeCtx)
+	}()
+
+	for {
+		// Service pending requests before waiting again
+		select {
+		case req := <-l.requests:
+			_, err := l.conn.Exec(ctx, req.sql)
+			req.result <- err
+			continue
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		waitCtx, cancelWait := context.WithCancel(ctx)
+		l.mu.Lock()
+		l.cancelWait = cancelWait
+		// A request queued before cancelWait was set would otherwise wait
+		// for the next notification
+		if len(l.requests) > 0 {
+			cancelWait()
+		}
+		l.mu.Unlock()
+
+		n, err := l.conn.WaitForNotification(waitCtx)
+		cancelWait()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if waitCtx.Err() != nil {
+				// Interrupted to run a request
+				continue
+			}
+			l.mu.Lock()
+			l.err = err
+			l.mu.Unlock()
+			return
+		}
+
+		select {
+		case l.notifications <- Notification{
+			Channel:    n.Channel,
+			Payload:    n.Payload,
+			PID:        n.PID,
+			ReceivedAt: time.Now(),
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -115,6 +115,23 @@ func (m *Manager) GetActive() (*Connection, error) {
 	return conn, nil
 }
 
+// Get returns the connection with id, whether or not it is the active one.
+// It leaves the active connection unchanged.
+func (m *Manager) Get(id string) (*Connection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	conn, ok := m.connections[id]
+	if !ok {
+		return nil, fmt.Errorf("connection %s not found", id)
+	}
+	if conn.Pool == nil || !conn.Connected {
+		return nil, fmt.Errorf("connection %s is not connected", id)
+	}
+
+	return conn, nil
+}
+
 // SetActive sets the active connection
 func (m *Manager) SetActive(id string) error {
 	m.mu.Lock()
@@ -0,0 +1,67 @@
+package connection
+
+import (
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// noticeCollectors maps a connection to the collector of the query it is
+// running. Notices on connections without one, e.g. from metadata queries,
+// are dropped.
+var noticeCollectors sync.Map // *pgconn.PgConn -> *NoticeCollector
+
+// NoticeCollector gathers the notices one query raises, tagged with the
+// statement that raised them
+type NoticeCollector struct {
+	mu        sync.Mutex
+	statement int
+	notices   []models.QueryNotice
+}
+
+// CollectNotices routes the notices conn receives to a new collector until
+// stop is called. The caller must hold conn for the whole query so no other
+// query's notices are mixed in.
+func CollectNotices(conn *pgconn.PgConn) (collector *NoticeCollector, stop func()) {
+	collector = &NoticeCollector{}
+	noticeCollectors.Store(conn, collector)
+	return collector, func() { noticeCollectors.Delete(conn) }
+}
+
+// SetStatement sets the statement that notices from now on are tagged with
+func (c *NoticeCollector) SetStatement(statement int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statement = statement
+}
+
+// Notices returns the notices collected so far
+func (c *NoticeCollector) Notices() []models.QueryNotice {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]models.QueryNotice(nil), c.notices...)
+}
+
+func (c *NoticeCollector) add(n *pgconn.Notice) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	severity := n.SeverityUnlocalized
+	if severity == "" {
+		severity = n.Severity
+	}
+	c.notices = append(c.notices, models.QueryNotice{
+		Statement: c.statement,
+		Severity:  severity,
+		Message:   n.Message,
+		Detail:    n.Detail,
+		Hint:      n.Hint,
+	})
+}
+
+// handleNotice is the pool's notice handler
+func handleNotice(conn *pgconn.PgConn, n *pgconn.Notice) {
+	if c, ok := noticeCollectors.Load(conn); ok {
+		c.(*NoticeCollector).add(n)
+	}
+}
@@ -0,0 +1,30 @@
+package connection
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestCollectNotices(t *testing.T) {
+	conn, other := &pgconn.PgConn{}, &pgconn.PgConn{}
+	collector, stop := CollectNotices(conn)
+
+	handleNotice(conn, &pgconn.Notice{SeverityUnlocalized: "NOTICE", Message: "first"})
+	collector.SetStatement(2)
+	handleNotice(conn, &pgconn.Notice{Severity: "WARNUNG", SeverityUnlocalized: "WARNING", Message: "second", Hint: "check it"})
+	handleNotice(other, &pgconn.Notice{Message: "another query's"})
+	stop()
+	handleNotice(conn, &pgconn.Notice{Message: "after the query"})
+
+	got := collector.Notices()
+	if len(got) != 2 {
+		t.Fatalf("collected %d notices, want 2: %+v", len(got), got)
+	}
+	if got[0].Statement != 0 || got[0].Message != "first" {
+		t.Errorf("first notice = %+v", got[0])
+	}
+	if got[1].Statement != 2 || got[1].Severity != "WARNING" || got[1].Hint != "check it" {
+		t.Errorf("second notice = %+v, want statement 2 with the unlocalized severity", got[1])
+	}
+}
@@ -3,8 +3,11 @@ package connection
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rebelice/lazypg/internal/models"
 )
@@ -13,10 +16,44 @@ import (
 type Pool struct {
 	pool   *pgxpool.Pool
 	config models.ConnectionConfig
+
+	onConnect *onConnectState
+}
+
+// onConnectState records the last failure of the on-connect SQL
+type onConnectState struct {
+	mu  sync.Mutex
+	err error
+}
+
+// run executes sql on a new connection. A failure is recorded rather than
+// returned, since returning it would make the pool drop the connection.
+func (s *onConnectState) run(ctx context.Context, conn *pgx.Conn, sql string) error {
+	// Without arguments this uses the simple protocol, so several
+	// statements can be given at once
+	_, err := conn.Exec(ctx, sql)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.err = err
+	}
+	return nil
 }
 
 // NewPool creates a new connection pool
 func NewPool(ctx context.Context, config models.ConnectionConfig) (*Pool, error) {
+	pool, err := newPool(ctx, config)
+	if err != nil && config.ApplicationName != "" && isApplicationNameRejected(err) {
+		// Some servers and poolers reject application_name as a startup
+		// parameter; retry without it rather than failing the connection
+		config.ApplicationName = ""
+		pool, err = newPool(ctx, config)
+	}
+	return pool, err
+}
+
+// newPool creates and pings a connection pool for the given config
+func newPool(ctx context.Context, config models.ConnectionConfig) (*Pool, error) {
 	connString := buildConnectionString(config)
 
 	poolConfig, err := pgxpool.ParseConfig(connString)
@@ -24,6 +61,20 @@ func NewPool(ctx context.Context, config models.ConnectionConfig) (*Pool, error)
 		return nil, fmt.Errorf("failed to parse connection config: %w", err)
 	}
 
+	if config.ApplicationName != "" {
+		poolConfig.ConnConfig.RuntimeParams["application_name"] = config.ApplicationName
+	}
+
+	// Route RAISE NOTICE and warnings to the query that raised them
+	poolConfig.ConnConfig.OnNotice = handleNotice
+
+	onConnect := &onConnectState{}
+	if sql := strings.TrimSpace(config.OnConnectSQL); sql != "" {
+		poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+			return onConnect.run(ctx, conn, sql)
+		}
+	}
+
 	// Configure pool settings
 	poolConfig.MaxConns = 5
 	poolConfig.MinConns = 1
@@ -43,11 +94,23 @@ func NewPool(ctx context.Context, config models.ConnectionConfig) (*Pool, error)
 	}
 
 	return &Pool{
-		pool:   pool,
-		config: config,
+		pool:      pool,
+		config:    config,
+		onConnect: onConnect,
 	}, nil
 }
 
+// OnConnectError returns the last error from running the on-connect SQL on
+// a new pooled connection, or nil if it has always succeeded
+func (p *Pool) OnConnectError() error {
+	if p.onConnect == nil {
+		return nil
+	}
+	p.onConnect.mu.Lock()
+	defer p.onConnect.mu.Unlock()
+	return p.onConnect.err
+}
+
 // Close closes the connection pool
 func (p *Pool) Close() {
 	if p.pool != nil {
@@ -67,8 +130,9 @@ func (p *Pool) GetPool() *pgxpool.Pool {
 
 // QueryResult represents a query result with columns and rows
 type QueryResult struct {
-	Columns []string
-	Rows    []map[string]interface{}
+	Columns    []string
+	ColumnOIDs []uint32 // Type OID of each column
+	Rows       []map[string]interface{}
 }
 
 // Query executes a query
@@ -108,10 +172,12 @@ func (p *Pool) QueryWithColumns(ctx context.Context, sql string, args ...interfa
 
 	fieldDescriptions := rows.FieldDescriptions()
 
-	// Extract column names in order
+	// Extract column names and types in order
 	columns := make([]string, len(fieldDescriptions))
+	oids := make([]uint32, len(fieldDescriptions))
 	for i, fd := range fieldDescriptions {
 		columns[i] = string(fd.Name)
+		oids[i] = fd.DataTypeOID
 	}
 
 	var results []map[string]interface{}
@@ -129,8 +195,9 @@ func (p *Pool) QueryWithColumns(ctx context.Context, sql string, args ...interfa
 	}
 
 	return &QueryResult{
-		Columns: columns,
-		Rows:    results,
+		Columns:    columns,
+		ColumnOIDs: oids,
+		Rows:       results,
 	}, rows.Err()
 }
 
@@ -155,6 +222,14 @@ func (p *Pool) Execute(ctx context.Context, sql string, args ...interface{}) (in
 	return result.RowsAffected(), nil
 }
 
+// isApplicationNameRejected reports whether a connection error was caused by
+// the server refusing the application_name startup parameter
+func isApplicationNameRejected(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "application_name") &&
+		(strings.Contains(msg, "unsupported") || strings.Contains(msg, "unrecognized"))
+}
+
 // buildConnectionString creates a PostgreSQL connection string
 func buildConnectionString(config models.ConnectionConfig) string {
 	sslMode := config.SSLMode
@@ -0,0 +1,93 @@
+package connection
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// moneyOID is the built-in money type, which pgx leaves undecoded
+const moneyOID = 790
+
+// FormatValue converts a value read from a column of type oid to the text
+// shown in the grid and copied to the clipboard. Values come out in a form
+// Postgres accepts back as input of the same type, and NULL as "NULL".
+func FormatValue(val interface{}, oid uint32) string {
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case map[string]interface{}, []interface{}:
+		// JSON and JSONB
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(jsonBytes)
+	case [16]byte:
+		// UUID from PostgreSQL
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[:4], v[4:6], v[6:8], v[8:10], v[10:])
+	case []byte:
+		// Might be raw JSON bytes
+		return string(v)
+	case pgtype.Interval:
+		if !v.Valid {
+			return "NULL"
+		}
+		return FormatInterval(v)
+	case string:
+		// money arrives as the server's text, already formatted for its
+		// lc_monetary, which is also what it accepts back
+		if oid == moneyOID {
+			return strings.TrimSpace(v)
+		}
+		return v
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// FormatInterval formats an interval as Postgres does with the default
+// IntervalStyle, e.g. "1 year 2 mons", "3 days 04:05:06.5" or
+// "-1 days +02:00:00"
+func FormatInterval(iv pgtype.Interval) string {
+	var parts []string
+	negative := false // A field before the time was negative
+	field := func(n int64, unit string) {
+		if n == 0 {
+			return
+		}
+		if n != 1 {
+			unit += "s"
+		}
+		parts = append(parts, strconv.FormatInt(n, 10)+" "+unit)
+		negative = negative || n < 0
+	}
+	field(int64(iv.Months/12), "year")
+	field(int64(iv.Months%12), "mon")
+	field(int64(iv.Days), "day")
+
+	if iv.Microseconds == 0 && len(parts) > 0 {
+		return strings.Join(parts, " ")
+	}
+
+	us := iv.Microseconds
+	sign := ""
+	switch {
+	case us < 0:
+		sign, us = "-", -us
+	case negative:
+		// Marks the time as positive after a negative field
+		sign = "+"
+	}
+	hours := us / 3_600_000_000
+	minutes := us / 60_000_000 % 60
+	seconds := us / 1_000_000 % 60
+	t := fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minutes, seconds)
+	if frac := us % 1_000_000; frac != 0 {
+		t += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+	}
+	return strings.Join(append(parts, t), " ")
+}
@@ -0,0 +1,49 @@
+package connection
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestFormatInterval(t *testing.T) {
+	tests := []struct {
+		iv   pgtype.Interval
+		want string
+	}{
+		{pgtype.Interval{Valid: true}, "00:00:00"},
+		{pgtype.Interval{Days: 1, Microseconds: 9_000_000_000, Valid: true}, "1 day 02:30:00"},
+		{pgtype.Interval{Months: 14, Valid: true}, "1 year 2 mons"},
+		{pgtype.Interval{Months: 12, Days: 3, Valid: true}, "1 year 3 days"},
+		{pgtype.Interval{Microseconds: 1_500_000, Valid: true}, "00:00:01.5"},
+		{pgtype.Interval{Microseconds: -90_000_000, Valid: true}, "-00:01:30"},
+		{pgtype.Interval{Days: -1, Microseconds: 7_200_000_000, Valid: true}, "-1 days +02:00:00"},
+		{pgtype.Interval{Microseconds: 100 * 3_600_000_000, Valid: true}, "100:00:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatInterval(tt.iv); got != tt.want {
+			t.Errorf("FormatInterval(%+v) = %q, want %q", tt.iv, got, tt.want)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+		oid  uint32
+		want string
+	}{
+		{"null", nil, 0, "NULL"},
+		{"null interval", pgtype.Interval{}, pgtype.IntervalOID, "NULL"},
+		{"interval", pgtype.Interval{Days: 2, Valid: true}, pgtype.IntervalOID, "2 days"},
+		{"money", "$1,234.50", moneyOID, "$1,234.50"},
+		{"jsonb", map[string]interface{}{"a": 1.0}, pgtype.JSONBOID, `{"a":1}`},
+		{"int", int64(42), pgtype.Int8OID, "42"},
+	}
+	for _, tt := range tests {
+		if got := FormatValue(tt.val, tt.oid); got != tt.want {
+			t.Errorf("%s: FormatValue() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package metadata
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/rebelice/lazypg/internal/db/connection"
 	"github.com/rebelice/lazypg/internal/models"
@@ -17,7 +18,8 @@ func GetColumnDetails(ctx context.Context, pool *connection.Pool, schema, table
 				bool_or(con.contype = 'p') AS is_pk,
 				bool_or(con.contype = 'f') AS is_fk,
 				bool_or(con.contype = 'u') AS is_unique,
-				bool_or(con.contype = 'c') AS has_check
+				bool_or(con.contype = 'c') AS has_check,
+				min(array_position(con.conkey, a.attnum)) FILTER (WHERE con.contype = 'p') AS pk_position
 			FROM pg_catalog.pg_attribute a
 			LEFT JOIN pg_catalog.pg_constraint con ON con.conrelid = a.attrelid
 				AND a.attnum = ANY(con.conkey)
@@ -37,18 +39,38 @@ func GetColumnDetails(ctx context.Context, pool *connection.Pool, schema, table
 				ELSE c.data_type
 			END AS formatted_type,
 			c.is_nullable = 'YES' AS is_nullable,
-			COALESCE(c.column_default, '-') AS default_value,
+			COALESCE(c.column_default, c.generation_expression, '-') AS default_value,
 			COALESCE(cc.is_pk, false) AS is_primary_key,
 			COALESCE(cc.is_fk, false) AS is_foreign_key,
 			COALESCE(cc.is_unique, false) AS is_unique,
 			COALESCE(cc.has_check, false) AS has_check,
-			COALESCE(d.description, '-') AS comment
+			COALESCE(cc.pk_position, 0) AS pk_position,
+			COALESCE(d.description, '-') AS comment,
+			COALESCE(a.attidentity::text, '') AS identity,
+			COALESCE(a.attgenerated::text, '') AS generated,
+			a.attnum,
+			a.attlen,
+			a.attalign::text AS attalign,
+			COALESCE(owned.schema_name, '') AS owned_sequence_schema,
+			COALESCE(owned.sequence_name, '') AS owned_sequence
 		FROM information_schema.columns c
 		LEFT JOIN column_constraints cc ON cc.column_name = c.column_name
 		LEFT JOIN pg_catalog.pg_attribute a ON a.attname = c.column_name
 			AND a.attrelid = ($1 || '.' || $2)::regclass
 		LEFT JOIN pg_catalog.pg_description d ON d.objoid = a.attrelid
 			AND d.objsubid = a.attnum
+		LEFT JOIN LATERAL (
+			-- The sequence a serial (auto) or identity (internal) column owns
+			SELECT sn.nspname AS schema_name, s.relname AS sequence_name
+			FROM pg_catalog.pg_depend dep
+			JOIN pg_catalog.pg_class s ON s.oid = dep.objid AND s.relkind = 'S'
+			JOIN pg_catalog.pg_namespace sn ON sn.oid = s.relnamespace
+			WHERE dep.classid = 'pg_catalog.pg_class'::regclass
+				AND dep.refobjid = a.attrelid
+				AND dep.refobjsubid = a.attnum
+				AND dep.deptype IN ('a', 'i')
+			LIMIT 1
+		) owned ON true
 		WHERE c.table_schema = $1 AND c.table_name = $2
 		ORDER BY c.ordinal_position
 	`
@@ -70,6 +92,19 @@ func GetColumnDetails(ctx context.Context, pool *connection.Pool, schema, table
 			IsUnique:      toBool(row["is_unique"]),
 			HasCheck:      toBool(row["has_check"]),
 			Comment:       toString(row["comment"]),
+			Identity:      toString(row["identity"]),
+			IsGenerated:   toString(row["generated"]) == "s",
+			Position:      len(columns) + 1,
+			AttNum:        int(toInt64(row["attnum"])),
+			StorageLength: int(toInt64(row["attlen"])),
+			StorageAlign:  toString(row["attalign"]),
+			KeyPosition:   int(toInt64(row["pk_position"])),
+		}
+		col.SequenceSchema, col.Sequence = columnSequence(schema, col.DefaultValue,
+			toString(row["owned_sequence_schema"]), toString(row["owned_sequence"]))
+		if col.Identity != "" {
+			// Identity columns have no column_default to show
+			col.DefaultValue = identityDefault(col.Identity)
 		}
 		columns = append(columns, col)
 	}
@@ -77,6 +112,107 @@ func GetColumnDetails(ctx context.Context, pool *connection.Pool, schema, table
 	return columns, nil
 }
 
+// identityDefault describes how an identity column gets its values
+func identityDefault(identity string) string {
+	if identity == "a" {
+		return "GENERATED ALWAYS AS IDENTITY"
+	}
+	return "GENERATED BY DEFAULT AS IDENTITY"
+}
+
+// columnSequence returns the sequence a column takes its values from: the
+// one nextval() reads in its default, or else the one it owns, as identity
+// columns have no default. The default only names the schema when the
+// sequence isn't on the search_path; an unqualified name is taken to be the
+// owned sequence when the names match, or else in the table's schema.
+func columnSequence(tableSchema, defaultValue, ownedSchema, owned string) (schema, name string) {
+	schema, name, ok := ParseNextvalSequence(defaultValue)
+	if !ok {
+		return ownedSchema, owned
+	}
+	if schema == "" {
+		schema = tableSchema
+		if name == owned {
+			schema = ownedSchema
+		}
+	}
+	return schema, name
+}
+
+// ParseNextvalSequence extracts the sequence from a column default such as
+// nextval('orders_id_seq'::regclass) or nextval('"Sales"."Order Ids"'). The
+// schema is empty when the default doesn't name one.
+func ParseNextvalSequence(defaultValue string) (schema, name string, ok bool) {
+	start := strings.Index(strings.ToLower(defaultValue), "nextval('")
+	if start < 0 {
+		return "", "", false
+	}
+
+	// The string literal, with '' for a quote
+	var literal strings.Builder
+	rest := defaultValue[start+len("nextval('"):]
+	closed := false
+	for i := 0; i < len(rest); i++ {
+		if rest[i] == '\'' {
+			if i+1 < len(rest) && rest[i+1] == '\'' {
+				literal.WriteByte('\'')
+				i++
+				continue
+			}
+			closed = true
+			break
+		}
+		literal.WriteByte(rest[i])
+	}
+	if !closed {
+		return "", "", false
+	}
+
+	parts := splitQualifiedName(literal.String())
+	switch len(parts) {
+	case 1:
+		return "", parts[0], parts[0] != ""
+	case 2:
+		return parts[0], parts[1], parts[0] != "" && parts[1] != ""
+	}
+	return "", "", false
+}
+
+// splitQualifiedName splits a possibly schema-qualified name at dots outside
+// double quotes. Quoted parts keep their case, with "" for a quote; unquoted
+// parts are folded to lower case, as Postgres does.
+func splitQualifiedName(s string) []string {
+	var parts []string
+	var part strings.Builder
+	quoted, inQuotes := false, false
+	flush := func() {
+		p := part.String()
+		if !quoted {
+			p = strings.ToLower(strings.TrimSpace(p))
+		}
+		parts = append(parts, p)
+		part.Reset()
+		quoted = false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' && inQuotes && i+1 < len(s) && s[i+1] == '"':
+			part.WriteByte('"')
+			i++
+		case c == '"':
+			inQuotes = !inQuotes
+			quoted = true
+		case c == '.' && !inQuotes:
+			flush()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	flush()
+	return parts
+}
+
 func toBool(v interface{}) bool {
 	if v == nil {
 		return false
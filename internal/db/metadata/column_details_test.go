@@ -0,0 +1,49 @@
+package metadata
+
+import "testing"
+
+func TestParseNextvalSequence(t *testing.T) {
+	tests := []struct {
+		def          string
+		schema, name string
+		ok           bool
+	}{
+		{"nextval('orders_id_seq'::regclass)", "", "orders_id_seq", true},
+		{"nextval('sales.orders_id_seq'::regclass)", "sales", "orders_id_seq", true},
+		{`nextval('"Sales"."Order Ids"'::regclass)`, "Sales", "Order Ids", true},
+		{`nextval('"it''s"."a.b"')`, "it's", "a.b", true},
+		{`nextval('"say ""hi"""'::regclass)`, "", `say "hi"`, true},
+		{"NEXTVAL('Orders_Seq')", "", "orders_seq", true},
+		{"(nextval('ids'::regclass) * 10)", "", "ids", true},
+		{"now()", "", "", false},
+		{"-", "", "", false},
+		{"nextval('unterminated", "", "", false},
+		{"nextval('a.b.c'::regclass)", "", "", false},
+	}
+	for _, tt := range tests {
+		schema, name, ok := ParseNextvalSequence(tt.def)
+		if schema != tt.schema || name != tt.name || ok != tt.ok {
+			t.Errorf("ParseNextvalSequence(%q) = %q, %q, %v, want %q, %q, %v", tt.def, schema, name, ok, tt.schema, tt.name, tt.ok)
+		}
+	}
+}
+
+func TestColumnSequence(t *testing.T) {
+	tests := []struct {
+		name                 string
+		def, ownedS, owned   string
+		wantSchema, wantName string
+	}{
+		{"serial owned in another schema", "nextval('ids'::regclass)", "app", "ids", "app", "ids"},
+		{"unqualified shared sequence", "nextval('ids'::regclass)", "", "", "public", "ids"},
+		{"qualified default", "nextval('other.ids'::regclass)", "", "", "other", "ids"},
+		{"identity column", "-", "public", "orders_id_seq", "public", "orders_id_seq"},
+		{"no sequence", "now()", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		schema, name := columnSequence("public", tt.def, tt.ownedS, tt.owned)
+		if schema != tt.wantSchema || name != tt.wantName {
+			t.Errorf("%s: columnSequence() = %q, %q, want %q, %q", tt.name, schema, name, tt.wantSchema, tt.wantName)
+		}
+	}
+}
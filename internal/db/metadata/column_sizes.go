@@ -0,0 +1,94 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ColumnSizeSampleRows is how many rows ColumnSizesSQL reads when sampling
+const ColumnSizeSampleRows = 1000
+
+// toastCandidateBytes is roughly where PostgreSQL starts compressing or
+// moving values out of line (TOAST_TUPLE_THRESHOLD is about 2kB)
+const toastCandidateBytes = 2000
+
+// ColumnSizesSQL builds a query reporting the stored size of each column of
+// a table, to find the columns behind TOAST bloat. With sampleRows > 0 only
+// that many rows are read; with 0 the whole table is scanned. Every figure
+// is labeled with where it came from: "sampled" or "exact" for figures
+// measured by the query, "pg_stats estimate" for ANALYZE statistics.
+func ColumnSizesSQL(schema, table string, columns []string, sampleRows int) string {
+	qualified := pgx.Identifier{schema, table}.Sanitize()
+
+	source, label, title := qualified, "exact", "full scan"
+	if sampleRows > 0 {
+		source = fmt.Sprintf("(SELECT * FROM %s LIMIT %d)", qualified, sampleRows)
+		label, title = "sampled", fmt.Sprintf("sample of %d rows", sampleRows)
+	}
+
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = fmt.Sprintf("(%s, pg_column_size(t.%s))", quoteLiteral(col), pgx.Identifier{col}.Sanitize())
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "-- %s.%s column sizes (%s)\n", commentText(schema), commentText(table), title)
+	fmt.Fprintf(&sb, `WITH sizes AS (
+	SELECT v.col,
+		count(v.size) AS non_null,
+		avg(v.size) AS avg_size,
+		max(v.size) AS max_size,
+		count(*) FILTER (WHERE v.size > %[1]d) AS large
+	FROM %[2]s AS t,
+		LATERAL (VALUES
+			%[3]s
+		) AS v(col, size)
+	GROUP BY v.col
+)
+SELECT a.attname AS "column",
+	format_type(a.atttypid, a.atttypmod) AS "type",
+	CASE a.attstorage
+		WHEN 'p' THEN 'plain'
+		WHEN 'e' THEN 'external'
+		WHEN 'm' THEN 'main'
+		WHEN 'x' THEN 'extended'
+	END AS "storage",
+	st.avg_width AS "avg bytes (pg_stats estimate)",
+	round(s.avg_size) AS "avg bytes (%[4]s)",
+	s.max_size AS "max bytes (%[4]s)",
+	s.non_null AS "non-null values (%[4]s)",
+	CASE WHEN s.non_null > 0 THEN round(100.0 * s.large / s.non_null, 1) END AS "over 2kB %% (%[4]s)"
+FROM pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_catalog.pg_stats st ON st.schemaname = n.nspname
+	AND st.tablename = c.relname
+	AND st.attname = a.attname
+	AND NOT st.inherited
+LEFT JOIN sizes s ON s.col = a.attname
+WHERE a.attrelid = %[5]s::regclass
+	AND a.attnum > 0
+	AND NOT a.attisdropped
+ORDER BY s.avg_size DESC NULLS LAST, a.attnum`,
+		toastCandidateBytes, source, strings.Join(values, ",\n\t\t\t"), label, quoteLiteral(qualified))
+	return sb.String()
+}
+
+// commentText makes a name safe to write in a -- comment, where a line
+// break would end the comment and leave the rest of the name as SQL
+func commentText(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, name)
+}
+
+// quoteLiteral quotes a value as a SQL string literal
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,41 @@
+package metadata
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnSizesSQL(t *testing.T) {
+	sampled := ColumnSizesSQL("public", "Order's", []string{"id", `big "doc"`}, 500)
+	for _, want := range []string{
+		"-- public.Order's column sizes (sample of 500 rows)",
+		`FROM (SELECT * FROM "public"."Order's" LIMIT 500) AS t`,
+		`('id', pg_column_size(t."id"))`,
+		`('big "doc"', pg_column_size(t."big ""doc"""))`,
+		`"avg bytes (sampled)"`,
+		`"over 2kB % (sampled)"`,
+		`"avg bytes (pg_stats estimate)"`,
+		`WHERE a.attrelid = '"public"."Order''s"'::regclass`,
+		"AND NOT st.inherited",
+	} {
+		if !strings.Contains(sampled, want) {
+			t.Errorf("sampled SQL is missing %q:\n%s", want, sampled)
+		}
+	}
+
+	exact := ColumnSizesSQL("public", "orders", []string{"id"}, 0)
+	if strings.Contains(exact, "LIMIT") || strings.Contains(exact, "sampled") {
+		t.Errorf("full scan SQL should not sample:\n%s", exact)
+	}
+	for _, want := range []string{`FROM "public"."orders" AS t`, `"max bytes (exact)"`, "(full scan)"} {
+		if !strings.Contains(exact, want) {
+			t.Errorf("full scan SQL is missing %q:\n%s", want, exact)
+		}
+	}
+
+	// A line break in a name must not end the leading comment
+	injected := ColumnSizesSQL("public", "t\nDROP TABLE users; --", []string{"id"}, 0)
+	if first, _, _ := strings.Cut(injected, "\n"); first != "-- public.t DROP TABLE users; -- column sizes (full scan)" {
+		t.Errorf("comment line = %q", first)
+	}
+}
@@ -15,7 +15,8 @@ func GetTableColumns(ctx context.Context, pool *connection.Pool, schema, table s
 			column_name,
 			data_type,
 			udt_name,
-			CASE WHEN data_type = 'ARRAY' THEN true ELSE false END as is_array
+			CASE WHEN data_type = 'ARRAY' THEN true ELSE false END as is_array,
+			is_generated = 'ALWAYS' as is_generated
 		FROM information_schema.columns
 		WHERE table_schema = $1 AND table_name = $2
 		ORDER BY ordinal_position
@@ -40,8 +41,59 @@ func GetTableColumns(ctx context.Context, pool *connection.Pool, schema, table s
 		// Check if it's JSONB
 		col.IsJsonb = udtName == "jsonb"
 
+		if isGenerated, ok := row["is_generated"].(bool); ok {
+			col.Generated = isGenerated
+		}
+
 		columns = append(columns, col)
 	}
 
 	return columns, nil
 }
+
+// ListSchemaColumns returns the columns of every table, view and
+// materialized view in schemas, keyed by "schema.name"
+func ListSchemaColumns(ctx context.Context, pool *connection.Pool, schemas []string) (map[string][]models.ColumnInfo, error) {
+	query := `
+		SELECT
+			ns.nspname AS schema_name,
+			cl.relname AS relation_name,
+			att.attname AS column_name,
+			format_type(att.atttypid, att.atttypmod) AS data_type,
+			NOT att.attnotnull AS nullable,
+			EXISTS (
+				SELECT 1 FROM pg_catalog.pg_constraint con
+				WHERE con.conrelid = cl.oid AND con.contype = 'p'
+					AND att.attnum = ANY(con.conkey)
+			) AS is_pk
+		FROM pg_catalog.pg_attribute att
+		JOIN pg_catalog.pg_class cl ON att.attrelid = cl.oid
+		JOIN pg_catalog.pg_namespace ns ON cl.relnamespace = ns.oid
+		WHERE ns.nspname = ANY($1)
+			AND cl.relkind IN ('r', 'p', 'v', 'm', 'f')
+			AND att.attnum > 0 AND NOT att.attisdropped
+		ORDER BY ns.nspname, cl.relname, att.attnum
+	`
+
+	rows, err := pool.Query(ctx, query, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+
+	columns := make(map[string][]models.ColumnInfo)
+	for _, row := range rows {
+		key := toString(row["schema_name"]) + "." + toString(row["relation_name"])
+		col := models.ColumnInfo{
+			Name:     toString(row["column_name"]),
+			DataType: toString(row["data_type"]),
+		}
+		if nullable, ok := row["nullable"].(bool); ok {
+			col.Nullable = nullable
+		}
+		if isPK, ok := row["is_pk"].(bool); ok {
+			col.PrimaryKey = isPK
+		}
+		columns[key] = append(columns[key], col)
+	}
+	return columns, nil
+}
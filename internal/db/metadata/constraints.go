@@ -78,6 +78,33 @@ func GetConstraints(ctx context.Context, pool *connection.Pool, schema, table st
 	return constraints, nil
 }
 
+// GetPrimaryKeyColumns returns the primary key columns of a table in key
+// order, or an empty slice if it has none
+func GetPrimaryKeyColumns(ctx context.Context, pool *connection.Pool, schema, table string) ([]string, error) {
+	query := `
+		SELECT att.attname AS column_name
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class cl ON con.conrelid = cl.oid
+		JOIN pg_catalog.pg_namespace ns ON cl.relnamespace = ns.oid
+		CROSS JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS u(attnum, attposition)
+		JOIN pg_catalog.pg_attribute att ON att.attrelid = con.conrelid
+			AND att.attnum = u.attnum
+		WHERE ns.nspname = $1 AND cl.relname = $2 AND con.contype = 'p'
+		ORDER BY u.attposition
+	`
+
+	rows, err := pool.Query(ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get primary key: %w", err)
+	}
+
+	columns := []string{}
+	for _, row := range rows {
+		columns = append(columns, toString(row["column_name"]))
+	}
+	return columns, nil
+}
+
 // FormatConstraintType returns a short type label
 func FormatConstraintType(conType string) string {
 	switch conType {
@@ -93,3 +120,56 @@ func FormatConstraintType(conType string) string {
 		return strings.ToUpper(conType)
 	}
 }
+
+// ListForeignKeys returns the foreign keys of all tables in schemas,
+// ordered by table and constraint name
+func ListForeignKeys(ctx context.Context, pool *connection.Pool, schemas []string) ([]models.ForeignKey, error) {
+	query := `
+		SELECT
+			con.conname AS constraint_name,
+			ns.nspname AS schema_name,
+			cl.relname AS table_name,
+			ARRAY(
+				SELECT att.attname::text
+				FROM unnest(con.conkey) WITH ORDINALITY AS u(attnum, attposition)
+				JOIN pg_catalog.pg_attribute att ON att.attrelid = con.conrelid
+					AND att.attnum = u.attnum
+				ORDER BY u.attposition
+			) AS columns,
+			nf.nspname AS ref_schema,
+			clf.relname AS ref_table,
+			ARRAY(
+				SELECT att.attname::text
+				FROM unnest(con.confkey) WITH ORDINALITY AS u(attnum, attposition)
+				JOIN pg_catalog.pg_attribute att ON att.attrelid = con.confrelid
+					AND att.attnum = u.attnum
+				ORDER BY u.attposition
+			) AS ref_columns
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class cl ON con.conrelid = cl.oid
+		JOIN pg_catalog.pg_namespace ns ON cl.relnamespace = ns.oid
+		JOIN pg_catalog.pg_class clf ON con.confrelid = clf.oid
+		JOIN pg_catalog.pg_namespace nf ON clf.relnamespace = nf.oid
+		WHERE con.contype = 'f' AND ns.nspname = ANY($1)
+		ORDER BY ns.nspname, cl.relname, con.conname
+	`
+
+	rows, err := pool.Query(ctx, query, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+
+	keys := make([]models.ForeignKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, models.ForeignKey{
+			Name:       toString(row["constraint_name"]),
+			Schema:     toString(row["schema_name"]),
+			Table:      toString(row["table_name"]),
+			Columns:    toStringSlice(row["columns"]),
+			RefSchema:  toString(row["ref_schema"]),
+			RefTable:   toString(row["ref_table"]),
+			RefColumns: toStringSlice(row["ref_columns"]),
+		})
+	}
+	return keys, nil
+}
@@ -0,0 +1,254 @@
+package metadata
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rebelice/lazypg/internal/db/connection"
+)
+
+// copyLineRe extracts the line number from a COPY error context
+// (e.g. `COPY users, line 3, column age: "abc"`)
+var copyLineRe = regexp.MustCompile(`line (\d+)`)
+
+// copyProgressInterval is how often a running COPY reports progress
+const copyProgressInterval = 100 * time.Millisecond
+
+// CopyProgress reports how much of a COPY has been streamed so far
+type CopyProgress struct {
+	Rows  int64
+	Bytes int64
+}
+
+// CSVImportOptions describes how CSV records map onto a target table
+type CSVImportOptions struct {
+	Schema    string
+	Table     string
+	Columns   []string // Target table columns, in insert order
+	Mapping   []int    // CSV column index for each target column (-1 = skip)
+	HasHeader bool     // First record is a header row and is not imported
+	DryRun    bool     // Roll back after copying so nothing is committed
+
+	// Progress, if set, is called periodically from the streaming goroutine
+	// and once more when all rows have been sent
+	Progress func(CopyProgress)
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// CSVImportResult is the outcome of a CSV import
+type CSVImportResult struct {
+	RowsCopied int64
+	DryRun     bool
+}
+
+// ReadCSVFile reads all records from a CSV file
+func ReadCSVFile(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Allow ragged rows, mapping handles missing fields
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV file: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	return records, nil
+}
+
+// DetectCSVHeader reports whether the first record looks like a header row.
+// A row is treated as a header if most of its fields match table column names,
+// or if it is entirely non-numeric while the next row has numeric fields.
+func DetectCSVHeader(records [][]string, tableColumns []string) bool {
+	if len(records) == 0 || len(records[0]) == 0 {
+		return false
+	}
+	first := records[0]
+
+	known := make(map[string]bool, len(tableColumns))
+	for _, col := range tableColumns {
+		known[strings.ToLower(col)] = true
+	}
+
+	matches := 0
+	for _, field := range first {
+		if known[strings.ToLower(strings.TrimSpace(field))] {
+			matches++
+		}
+	}
+	if matches*2 >= len(first) && matches > 0 {
+		return true
+	}
+
+	if len(records) < 2 {
+		return false
+	}
+	second := records[1]
+	for i, field := range first {
+		if isNumeric(field) || strings.TrimSpace(field) == "" {
+			return false
+		}
+		if i < len(second) && isNumeric(second[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// MapCSVColumns builds a default mapping from target columns to CSV columns.
+// With a header, columns are matched by name (case-insensitive); otherwise
+// they are matched by position.
+func MapCSVColumns(header []string, tableColumns []string, hasHeader bool) []int {
+	mapping := make([]int, len(tableColumns))
+	for i, col := range tableColumns {
+		mapping[i] = -1
+		if hasHeader {
+			for j, name := range header {
+				if strings.EqualFold(strings.TrimSpace(name), col) {
+					mapping[i] = j
+					break
+				}
+			}
+		} else if i < len(header) {
+			mapping[i] = i
+		}
+	}
+	return mapping
+}
+
+// CopyCSVToTable streams CSV records into a table using COPY FROM STDIN.
+// The copy runs in a transaction; dry runs are rolled back after the server
+// has validated every row, so type errors are still reported.
+func CopyCSVToTable(ctx context.Context, pool *connection.Pool, records [][]string, opts CSVImportOptions) (*CSVImportResult, error) {
+	var columns []string
+	var sources []int
+	for i, col := range opts.Columns {
+		if i < len(opts.Mapping) && opts.Mapping[i] >= 0 {
+			columns = append(columns, pgx.Identifier{col}.Sanitize())
+			sources = append(sources, opts.Mapping[i])
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns mapped for import")
+	}
+
+	data := records
+	lineOffset := 0
+	if opts.HasHeader && len(data) > 0 {
+		data = data[1:]
+		lineOffset = 1
+	}
+
+	conn, err := pool.GetPool().Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Re-encode mapped records as CSV and stream them to the server
+	pr, pw := io.Pipe()
+	go func() {
+		counter := &countingWriter{w: pw}
+		writer := csv.NewWriter(counter)
+		row := make([]string, len(sources))
+		lastReport := time.Now()
+		for n, record := range data {
+			for i, src := range sources {
+				row[i] = ""
+				if src < len(record) {
+					row[i] = record[src]
+				}
+			}
+			if err := writer.Write(row); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			if opts.Progress != nil && time.Since(lastReport) >= copyProgressInterval {
+				// Flush so the byte count matches the rows reported
+				writer.Flush()
+				opts.Progress(CopyProgress{Rows: int64(n + 1), Bytes: counter.n})
+				lastReport = time.Now()
+			}
+		}
+		writer.Flush()
+		if opts.Progress != nil && writer.Error() == nil {
+			opts.Progress(CopyProgress{Rows: int64(len(data)), Bytes: counter.n})
+		}
+		_ = pw.CloseWithError(writer.Error())
+	}()
+
+	sql := fmt.Sprintf("COPY %s (%s) FROM STDIN WITH (FORMAT csv)",
+		pgx.Identifier{opts.Schema, opts.Table}.Sanitize(),
+		strings.Join(columns, ", "))
+
+	tag, err := tx.Conn().PgConn().CopyFrom(ctx, pr, sql)
+	if err != nil {
+		_ = pr.Close()
+		return nil, formatCopyError(err, lineOffset)
+	}
+
+	if !opts.DryRun {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit import: %w", err)
+		}
+	}
+
+	return &CSVImportResult{
+		RowsCopied: tag.RowsAffected(),
+		DryRun:     opts.DryRun,
+	}, nil
+}
+
+// formatCopyError rewrites a COPY error to point at the offending CSV line
+func formatCopyError(err error, lineOffset int) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	if m := copyLineRe.FindStringSubmatch(pgErr.Where); m != nil {
+		if line, convErr := strconv.Atoi(m[1]); convErr == nil {
+			return fmt.Errorf("CSV line %d: %s\n\n%s", line+lineOffset, pgErr.Message, pgErr.Where)
+		}
+	}
+	return fmt.Errorf("import failed: %s", pgErr.Message)
+}
+
+// isNumeric reports whether a CSV field parses as a number
+func isNumeric(s string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return err == nil
+}
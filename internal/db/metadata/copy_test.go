@@ -0,0 +1,64 @@
+package metadata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectCSVHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		records [][]string
+		columns []string
+		want    bool
+	}{
+		{
+			name:    "header matches table columns",
+			records: [][]string{{"id", "Name"}, {"1", "alice"}},
+			columns: []string{"id", "name"},
+			want:    true,
+		},
+		{
+			name:    "text header over numeric data",
+			records: [][]string{{"user id", "label"}, {"1", "alice"}},
+			columns: []string{"id", "name"},
+			want:    true,
+		},
+		{
+			name:    "data only",
+			records: [][]string{{"1", "alice"}, {"2", "bob"}},
+			columns: []string{"id", "name"},
+			want:    false,
+		},
+		{
+			name:    "empty",
+			records: nil,
+			columns: []string{"id"},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectCSVHeader(tt.records, tt.columns); got != tt.want {
+				t.Errorf("DetectCSVHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapCSVColumns(t *testing.T) {
+	columns := []string{"id", "name", "email"}
+
+	got := MapCSVColumns([]string{"email", "ID"}, columns, true)
+	want := []int{1, -1, 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapCSVColumns(header) = %v, want %v", got, want)
+	}
+
+	got = MapCSVColumns([]string{"1", "alice"}, columns, false)
+	want = []int{0, 1, -1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapCSVColumns(positional) = %v, want %v", got, want)
+	}
+}
@@ -2,18 +2,24 @@ package metadata
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/models"
 )
 
 // TableData represents paginated table data
 type TableData struct {
-	Columns   []string
-	Rows      [][]string
-	TotalRows int64
+	Columns     []string
+	ColumnKinds []models.ColumnKind // Parallel to Columns, for type-aware rendering
+	Rows        [][]string
+	TotalRows   int64
+	SQL         string // The SELECT that produced Rows, $n placeholders left in
+
+	// UnfilteredRows counts the table's rows without the WHERE clause; it is
+	// only set for the first page (offset 0) of a filtered query
+	UnfilteredRows int64
 }
 
 // SortOptions holds sorting configuration
@@ -25,20 +31,28 @@ type SortOptions struct {
 
 // QueryTableData fetches paginated table data with optional sorting
 func QueryTableData(ctx context.Context, pool *connection.Pool, schema, table string, offset, limit int, sort *SortOptions) (*TableData, error) {
-	// Get exact row count - uses Index-Only Scan for tables with PK/index
-	countQuery := fmt.Sprintf("SELECT COUNT(*) as count FROM %s.%s", schema, table)
-	countRow, err := pool.QueryRow(ctx, countQuery)
-	if err != nil {
-		countRow = map[string]interface{}{"count": int64(0)}
-	}
+	return QueryFilteredTableData(ctx, pool, schema, table, "", nil, offset, limit, sort)
+}
 
-	totalRows := int64(0)
-	if count, ok := countRow["count"].(int64); ok {
-		totalRows = count
+// QueryFilteredTableData fetches paginated table data restricted to the rows
+// matching where, a "WHERE ..." clause whose $n placeholders are bound to
+// args (as built by filter.Builder). TotalRows counts the matching rows and,
+// on the first page only, UnfilteredRows all of them; later pages (prefetch,
+// row windows) reuse the first page's count.
+func QueryFilteredTableData(ctx context.Context, pool *connection.Pool, schema, table, where string, args []interface{}, offset, limit int, sort *SortOptions) (*TableData, error) {
+	from := fmt.Sprintf("%s.%s", schema, table)
+	var unfilteredRows int64
+	if where != "" {
+		if offset == 0 {
+			unfilteredRows = countRows(ctx, pool, from, nil)
+		}
+		from += " " + where
 	}
 
+	totalRows := countRows(ctx, pool, from, args)
+
 	// Build query with optional ORDER BY
-	query := fmt.Sprintf("SELECT * FROM %s.%s", schema, table)
+	query := fmt.Sprintf("SELECT * FROM %s", from)
 
 	if sort != nil && sort.Column != "" {
 		nullsClause := "NULLS LAST"
@@ -50,16 +64,20 @@ func QueryTableData(ctx context.Context, pool *connection.Pool, schema, table st
 
 	query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
 
-	result, err := pool.QueryWithColumns(ctx, query)
+	result, err := pool.QueryWithColumns(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query table data: %w", err)
 	}
 
 	if len(result.Rows) == 0 {
 		return &TableData{
-			Columns:   result.Columns,
-			Rows:      [][]string{},
-			TotalRows: totalRows,
+			Columns:     result.Columns,
+			ColumnKinds: pool.ColumnKinds(ctx, result.ColumnOIDs),
+			Rows:        [][]string{},
+			TotalRows:   totalRows,
+			SQL:         query,
+
+			UnfilteredRows: unfilteredRows,
 		}, nil
 	}
 
@@ -70,43 +88,32 @@ func QueryTableData(ctx context.Context, pool *connection.Pool, schema, table st
 	for i, row := range result.Rows {
 		rowData := make([]string, len(columns))
 		for j, col := range columns {
-			val := row[col]
-			if val == nil {
-				rowData[j] = "NULL"
-			} else {
-				rowData[j] = convertValueToString(val)
-			}
+			rowData[j] = connection.FormatValue(row[col], result.ColumnOIDs[j])
 		}
 		data[i] = rowData
 	}
 
 	return &TableData{
-		Columns:   columns,
-		Rows:      data,
-		TotalRows: totalRows,
+		Columns:     columns,
+		ColumnKinds: pool.ColumnKinds(ctx, result.ColumnOIDs),
+		Rows:        data,
+		TotalRows:   totalRows,
+		SQL:         query,
+
+		UnfilteredRows: unfilteredRows,
 	}, nil
 }
 
-// convertValueToString converts a database value to string, handling JSONB properly
-func convertValueToString(val interface{}) string {
-	// Check if it's a map or slice (JSONB types)
-	switch v := val.(type) {
-	case map[string]interface{}, []interface{}:
-		// Convert to JSON string
-		jsonBytes, err := json.Marshal(v)
-		if err != nil {
-			return fmt.Sprintf("%v", val)
-		}
-		return string(jsonBytes)
-	case [16]byte:
-		// UUID from PostgreSQL
-		return fmt.Sprintf("%x-%x-%x-%x-%x", v[:4], v[4:6], v[6:8], v[8:10], v[10:])
-	case []byte:
-		// Might be raw JSON bytes
-		return string(v)
-	default:
-		return fmt.Sprintf("%v", val)
+// countRows returns the exact number of rows of from, a table optionally
+// followed by a WHERE clause, or 0 if they can't be counted. Uses an
+// Index-Only Scan for tables with a PK or index.
+func countRows(ctx context.Context, pool *connection.Pool, from string, args []interface{}) int64 {
+	countRow, err := pool.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) as count FROM %s", from), args...)
+	if err != nil {
+		return 0
 	}
+	count, _ := countRow["count"].(int64)
+	return count
 }
 
 // SearchTableData searches entire table using ILIKE on all columns
@@ -143,6 +150,7 @@ func SearchTableData(ctx context.Context, pool *connection.Pool, schema, table s
 			Columns:   result.Columns,
 			Rows:      [][]string{},
 			TotalRows: 0,
+			SQL:       query,
 		}, nil
 	}
 
@@ -153,19 +161,16 @@ func SearchTableData(ctx context.Context, pool *connection.Pool, schema, table s
 	for i, row := range result.Rows {
 		rowData := make([]string, len(cols))
 		for j, col := range cols {
-			val := row[col]
-			if val == nil {
-				rowData[j] = "NULL"
-			} else {
-				rowData[j] = convertValueToString(val)
-			}
+			rowData[j] = connection.FormatValue(row[col], result.ColumnOIDs[j])
 		}
 		data[i] = rowData
 	}
 
 	return &TableData{
-		Columns:   cols,
-		Rows:      data,
-		TotalRows: int64(len(data)),
+		Columns:     cols,
+		ColumnKinds: pool.ColumnKinds(ctx, result.ColumnOIDs),
+		Rows:        data,
+		TotalRows:   int64(len(data)),
+		SQL:         query,
 	}, nil
 }
@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// ConstraintDDL builds the ALTER TABLE statement that recreates a
+// constraint on schema.table. The body is the pg_get_constraintdef text, so
+// CHECK expressions, FOREIGN KEY actions and EXCLUDE clauses are kept as-is.
+func ConstraintDDL(schema, table string, con models.Constraint) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{con.Name}.Sanitize(),
+		strings.TrimSpace(con.Definition))
+}
+
+// IndexDDL returns the CREATE INDEX statement for an index. It is the
+// pg_get_indexdef text, which already schema-qualifies the table and keeps
+// expression columns and the WHERE clause of a partial index. An index that
+// backs a primary key is recreated by its constraint, so that is noted.
+func IndexDDL(idx models.IndexInfo) string {
+	ddl := strings.TrimSuffix(strings.TrimSpace(idx.Definition), ";") + ";"
+	if idx.IsPrimary {
+		ddl = "-- Backs the primary key; recreate it with the constraint's ALTER TABLE instead\n" + ddl
+	}
+	return ddl
+}
+
+// ViewDDL builds the statement that recreates a view from its definition.
+// Views get CREATE OR REPLACE so the text can be edited and run as-is;
+// materialized views can't be replaced, which is noted above the CREATE.
+func ViewDDL(view ViewDefinition) string {
+	var b strings.Builder
+	if view.Owner != "" {
+		fmt.Fprintf(&b, "-- Owner: %s\n", view.Owner)
+	}
+	name := pgx.Identifier{view.Schema, view.Name}.Sanitize()
+	if view.Materialized {
+		b.WriteString("-- Materialized views can't be replaced; drop and recreate to change the query\n")
+		fmt.Fprintf(&b, "CREATE MATERIALIZED VIEW %s AS\n", name)
+	} else {
+		fmt.Fprintf(&b, "CREATE OR REPLACE VIEW %s AS\n", name)
+	}
+	b.WriteString(strings.TrimSuffix(strings.TrimSpace(view.Query), ";") + ";")
+	return b.String()
+}
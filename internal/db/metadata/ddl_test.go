@@ -0,0 +1,83 @@
+package metadata
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+func TestConstraintDDL(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		table  string
+		con    models.Constraint
+		want   string
+	}{
+		{
+			name:   "foreign key",
+			schema: "public",
+			table:  "orders",
+			con:    models.Constraint{Name: "orders_user_id_fkey", Definition: "FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE"},
+			want:   `ALTER TABLE "public"."orders" ADD CONSTRAINT "orders_user_id_fkey" FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;`,
+		},
+		{
+			name:   "quoted names",
+			schema: "Sales",
+			table:  `my "orders"`,
+			con:    models.Constraint{Name: "Positive Total", Definition: "CHECK ((total > (0)::numeric))"},
+			want:   `ALTER TABLE "Sales"."my ""orders""" ADD CONSTRAINT "Positive Total" CHECK ((total > (0)::numeric));`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConstraintDDL(tt.schema, tt.table, tt.con); got != tt.want {
+				t.Errorf("ConstraintDDL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexDDL(t *testing.T) {
+	partial := models.IndexInfo{
+		Name:       "orders_open_idx",
+		Definition: "CREATE INDEX orders_open_idx ON public.orders USING btree (lower(email)) WHERE (status = 'open'::text)",
+		IsPartial:  true,
+	}
+	want := "CREATE INDEX orders_open_idx ON public.orders USING btree (lower(email)) WHERE (status = 'open'::text);"
+	if got := IndexDDL(partial); got != want {
+		t.Errorf("IndexDDL() = %q, want %q", got, want)
+	}
+
+	primary := models.IndexInfo{
+		Name:       "orders_pkey",
+		Definition: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)",
+		IsPrimary:  true,
+	}
+	got := IndexDDL(primary)
+	if !strings.HasPrefix(got, "-- ") || !strings.HasSuffix(got, "USING btree (id);") {
+		t.Errorf("IndexDDL() for a primary key index = %q", got)
+	}
+}
+
+func TestViewDDL(t *testing.T) {
+	// pg_get_viewdef pretty-prints with a leading space and a trailing semicolon
+	view := ViewDefinition{
+		Schema: "public",
+		Name:   "open_orders",
+		Query:  " SELECT id,\n    total\n   FROM orders\n  WHERE status = 'open'::text;",
+		Owner:  "app",
+	}
+	want := "-- Owner: app\nCREATE OR REPLACE VIEW \"public\".\"open_orders\" AS\nSELECT id,\n    total\n   FROM orders\n  WHERE status = 'open'::text;"
+	if got := ViewDDL(view); got != want {
+		t.Errorf("ViewDDL() = %q, want %q", got, want)
+	}
+
+	mat := ViewDefinition{Schema: "Sales", Name: "daily totals", Materialized: true, Query: " SELECT 1"}
+	got := ViewDDL(mat)
+	if !strings.HasPrefix(got, "-- ") || !strings.Contains(got, "\nCREATE MATERIALIZED VIEW \"Sales\".\"daily totals\" AS\nSELECT 1;") {
+		t.Errorf("ViewDDL() for a materialized view = %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rebelice/lazypg/internal/db/connection"
+)
+
+// ForeignServer is a server foreign tables read from, with its user
+// mappings
+type ForeignServer struct {
+	Name     string
+	Wrapper  string // Foreign data wrapper, e.g. postgres_fdw
+	Owner    string
+	Options  []string // As key=value, e.g. host=db.internal
+	Tables   int      // Foreign tables on the server
+	Mappings []UserMapping
+}
+
+// UserMapping maps a local user to the credentials used on a foreign server
+type UserMapping struct {
+	User    string   // Local user, or "public" for everyone without their own
+	Options []string // Empty unless the current user may see them
+}
+
+// ListForeignServers returns the foreign servers of the database with their
+// user mappings. Mapping options come from pg_user_mappings, which hides
+// them from users who neither own the server nor are mapped by them.
+func ListForeignServers(ctx context.Context, pool *connection.Pool) ([]ForeignServer, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT s.srvname AS name,
+		       w.fdwname AS wrapper,
+		       pg_catalog.pg_get_userbyid(s.srvowner) AS owner,
+		       s.srvoptions AS options,
+		       (SELECT count(*) FROM pg_catalog.pg_foreign_table ft WHERE ft.ftserver = s.oid) AS table_count
+		FROM pg_catalog.pg_foreign_server s
+		JOIN pg_catalog.pg_foreign_data_wrapper w ON w.oid = s.srvfdw
+		ORDER BY s.srvname
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign servers: %w", err)
+	}
+
+	servers := make([]ForeignServer, 0, len(rows))
+	index := make(map[string]int, len(rows))
+	for _, row := range rows {
+		index[toString(row["name"])] = len(servers)
+		servers = append(servers, ForeignServer{
+			Name:    toString(row["name"]),
+			Wrapper: toString(row["wrapper"]),
+			Owner:   toString(row["owner"]),
+			Options: toStringSlice(row["options"]),
+			Tables:  int(toInt64(row["table_count"])),
+		})
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT srvname AS server, usename AS user_name, umoptions AS options
+		FROM pg_catalog.pg_user_mappings
+		ORDER BY srvname, usename
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user mappings: %w", err)
+	}
+	for _, row := range rows {
+		if i, ok := index[toString(row["server"])]; ok {
+			servers[i].Mappings = append(servers[i].Mappings, UserMapping{
+				User:    toString(row["user_name"]),
+				Options: toStringSlice(row["options"]),
+			})
+		}
+	}
+	return servers, nil
+}
+
+// secretOptions are the options whose values are never shown
+var secretOptions = []string{"password", "passfile", "sslkey", "sslpassword"}
+
+// MaskOptions returns key=value options with the values of secrets such as
+// password hidden
+func MaskOptions(options []string) []string {
+	masked := make([]string, len(options))
+	for i, opt := range options {
+		key, _, ok := strings.Cut(opt, "=")
+		masked[i] = opt
+		for _, secret := range secretOptions {
+			if ok && strings.EqualFold(key, secret) {
+				masked[i] = key + "=••••"
+				break
+			}
+		}
+	}
+	return masked
+}
@@ -0,0 +1,14 @@
+package metadata
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMaskOptions(t *testing.T) {
+	got := MaskOptions([]string{"user=reader", "password=s3cret=x", "Password=abc", "host=db.internal", "sslkey"})
+	want := []string{"user=reader", "password=••••", "Password=••••", "host=db.internal", "sslkey"}
+	if !slices.Equal(got, want) {
+		t.Errorf("MaskOptions() = %v, want %v", got, want)
+	}
+}
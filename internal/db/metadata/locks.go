@@ -0,0 +1,100 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// GetBlockingLocks lists every backend that is waiting on a lock together
+// with each backend blocking it, from pg_blocking_pids. A row-level wait is
+// on the other transaction's ID, so the relation then comes from the tuple
+// lock the waiter holds. Longest waits come first.
+func GetBlockingLocks(ctx context.Context, pool *connection.Pool) ([]models.BlockingLock, error) {
+	query := `
+		SELECT
+			blocked.pid AS blocked_pid,
+			COALESCE(blocked.usename, '') AS blocked_user,
+			COALESCE(blocked.query, '') AS blocked_query,
+			COALESCE(EXTRACT(EPOCH FROM now() - blocked.query_start), 0)::float8 AS waiting_seconds,
+			blocker.pid AS blocking_pid,
+			COALESCE(blocker.usename, '') AS blocking_user,
+			COALESCE(blocker.query, '') AS blocking_query,
+			COALESCE(blocker.state, '') AS blocking_state,
+			COALESCE(wait.relation, tup.relation, '') AS relation,
+			COALESCE(wait.locktype, '') AS lock_type,
+			COALESCE(wait.mode, '') AS lock_mode
+		FROM pg_catalog.pg_stat_activity blocked
+		CROSS JOIN LATERAL unnest(pg_catalog.pg_blocking_pids(blocked.pid)) AS b(pid)
+		JOIN pg_catalog.pg_stat_activity blocker ON blocker.pid = b.pid
+		LEFT JOIN LATERAL (
+			SELECT l.relation::regclass::text AS relation, l.locktype, l.mode
+			FROM pg_catalog.pg_locks l
+			WHERE l.pid = blocked.pid AND NOT l.granted
+			LIMIT 1
+		) wait ON true
+		LEFT JOIN LATERAL (
+			SELECT l.relation::regclass::text AS relation
+			FROM pg_catalog.pg_locks l
+			WHERE l.pid = blocked.pid AND l.locktype = 'tuple'
+			LIMIT 1
+		) tup ON true
+		ORDER BY waiting_seconds DESC, blocked.pid, blocker.pid
+	`
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocking locks: %w", err)
+	}
+
+	locks := make([]models.BlockingLock, 0, len(rows))
+	for _, row := range rows {
+		seconds, _ := row["waiting_seconds"].(float64)
+		locks = append(locks, models.BlockingLock{
+			BlockedPID:    int(toInt64(row["blocked_pid"])),
+			BlockedUser:   toString(row["blocked_user"]),
+			BlockedQuery:  toString(row["blocked_query"]),
+			Waiting:       time.Duration(seconds * float64(time.Second)),
+			BlockingPID:   int(toInt64(row["blocking_pid"])),
+			BlockingUser:  toString(row["blocking_user"]),
+			BlockingQuery: toString(row["blocking_query"]),
+			BlockingState: toString(row["blocking_state"]),
+			Relation:      toString(row["relation"]),
+			LockType:      toString(row["lock_type"]),
+			LockMode:      toString(row["lock_mode"]),
+		})
+	}
+	return locks, nil
+}
+
+// SignalBackend cancels the current query of a backend, or with terminate
+// ends its session. It reports false when the server could not signal it,
+// e.g. because the backend already exited.
+func SignalBackend(ctx context.Context, pool *connection.Pool, pid int, terminate bool) (bool, error) {
+	fn, sql := signalBackendSQL(terminate)
+	row, err := pool.QueryRow(ctx, sql, pid)
+	if err != nil {
+		return false, fmt.Errorf("%s(%d) failed: %w", fn, pid, err)
+	}
+	ok, _ := row["ok"].(bool)
+	return ok, nil
+}
+
+// SignalBackendSQL returns the statement SignalBackend runs, with its
+// parameter, for showing it
+func SignalBackendSQL(terminate bool) string {
+	_, sql := signalBackendSQL(terminate)
+	return sql
+}
+
+// signalBackendSQL returns the signal function and the query calling it
+func signalBackendSQL(terminate bool) (string, string) {
+	fn := "pg_cancel_backend"
+	if terminate {
+		fn = "pg_terminate_backend"
+	}
+	return fn, fmt.Sprintf("SELECT pg_catalog.%s($1) AS ok", fn)
+}
@@ -113,9 +113,10 @@ type SchemaObjectCounts struct {
 // SchemaObject represents a single database object for search indexing
 type SchemaObject struct {
 	SchemaName string
-	ObjectType string // "table", "view", "matview", "function", "procedure", "trigger_function", "sequence", "composite_type", "enum_type", "domain_type", "range_type"
+	ObjectType string // "table", "view", "matview", "foreign_table", "function", "procedure", "trigger_function", "sequence", "composite_type", "enum_type", "domain_type", "range_type"
 	ObjectName string
-	Arguments  string // Function/procedure arguments (empty for non-function types)
+	Arguments  string // Function/procedure arguments, or a foreign table's server (empty for other types)
+	Columns    int    // Column count for tables, views, materialized views and foreign tables (from pg_attribute)
 }
 
 // TotalObjects returns the total count of all objects in the schema
@@ -409,6 +410,31 @@ func ListEnumTypes(ctx context.Context, pool *connection.Pool, schema string) ([
 	return types, nil
 }
 
+// ListEnumValues returns the labels of an enum type in their sort order
+func ListEnumValues(ctx context.Context, pool *connection.Pool, schema, name string) ([]string, error) {
+	query := `
+		SELECT e.enumlabel
+		FROM pg_enum e
+		JOIN pg_type t ON t.oid = e.enumtypid
+		JOIN pg_namespace n ON t.typnamespace = n.oid
+		WHERE n.nspname = $1
+		  AND t.typname = $2
+		ORDER BY e.enumsortorder;
+	`
+
+	rows, err := pool.Query(ctx, query, schema, name)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, toString(row["enumlabel"]))
+	}
+
+	return labels, nil
+}
+
 // ListDomainTypes returns all domain types in a schema
 func ListDomainTypes(ctx context.Context, pool *connection.Pool, schema string) ([]DomainType, error) {
 	query := `
@@ -523,6 +549,15 @@ type DomainTypeDetails struct {
 	Constraints []string
 }
 
+// ViewDefinition is the source of a view or materialized view
+type ViewDefinition struct {
+	Schema       string
+	Name         string
+	Materialized bool
+	Query        string // pg_get_viewdef text, pretty-printed
+	Owner        string
+}
+
 // GetFunctionSource returns the source code of a function, procedure, or trigger function
 func GetFunctionSource(ctx context.Context, pool *connection.Pool, schema, name, args string) (*FunctionSource, error) {
 	query := `
@@ -773,6 +808,43 @@ func GetDomainTypeDetails(ctx context.Context, pool *connection.Pool, schema, na
 	return details, nil
 }
 
+// GetViewDefinition returns the query behind a view or materialized view
+func GetViewDefinition(ctx context.Context, pool *connection.Pool, schema, name string) (*ViewDefinition, error) {
+	query := `
+		SELECT
+			n.nspname,
+			c.relname,
+			c.relkind::text as relkind,
+			pg_get_viewdef(c.oid, true) as definition,
+			pg_get_userbyid(c.relowner) as owner
+		FROM pg_class c
+		JOIN pg_namespace n ON c.relnamespace = n.oid
+		WHERE n.nspname = $1 AND c.relname = $2;
+	`
+
+	rows, err := pool.Query(ctx, query, schema, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("view %s.%s not found", schema, name)
+	}
+
+	row := rows[0]
+	kind := toString(row["relkind"])
+	if kind != "v" && kind != "m" {
+		return nil, fmt.Errorf("%s.%s is not a view", schema, name)
+	}
+	return &ViewDefinition{
+		Schema:       toString(row["nspname"]),
+		Name:         toString(row["relname"]),
+		Materialized: kind == "m",
+		Query:        toString(row["definition"]),
+		Owner:        toString(row["owner"]),
+	}, nil
+}
+
 // GetSchemaObjectCounts returns object counts for all schemas in one query
 func GetSchemaObjectCounts(ctx context.Context, pool *connection.Pool) ([]SchemaObjectCounts, error) {
 	query := `
@@ -860,119 +932,125 @@ func GetSchemaObjectCounts(ctx context.Context, pool *connection.Pool) ([]Schema
 	return counts, nil
 }
 
-// GetAllSchemaObjects returns all object names grouped by schema and type
+// GetAllSchemaObjects returns all object names grouped by schema and type.
+// System schemas (pg_catalog, information_schema) are included so the tree
+// can show or hide them without reloading; TOAST and temp schemas are not.
 func GetAllSchemaObjects(ctx context.Context, pool *connection.Pool) ([]SchemaObject, error) {
 	query := `
 		-- Tables
-		SELECT n.nspname AS schema_name, 'table' AS object_type, c.relname AS object_name, '' AS arguments
+		SELECT n.nspname AS schema_name, 'table' AS object_type, c.relname AS object_name, '' AS arguments,
+		       (SELECT count(*) FROM pg_attribute a WHERE a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped) AS column_count
 		FROM pg_class c
 		JOIN pg_namespace n ON c.relnamespace = n.oid
 		WHERE c.relkind = 'r'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Views
-		SELECT n.nspname, 'view', c.relname, ''
+		SELECT n.nspname, 'view', c.relname, '',
+		       (SELECT count(*) FROM pg_attribute a WHERE a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped)
 		FROM pg_class c
 		JOIN pg_namespace n ON c.relnamespace = n.oid
 		WHERE c.relkind = 'v'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Materialized Views
-		SELECT n.nspname, 'matview', c.relname, ''
+		SELECT n.nspname, 'matview', c.relname, '',
+		       (SELECT count(*) FROM pg_attribute a WHERE a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped)
 		FROM pg_class c
 		JOIN pg_namespace n ON c.relnamespace = n.oid
 		WHERE c.relkind = 'm'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
+
+		UNION ALL
+
+		-- Foreign Tables, with the server their rows come from
+		SELECT n.nspname, 'foreign_table', c.relname, s.srvname,
+		       (SELECT count(*) FROM pg_attribute a WHERE a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped)
+		FROM pg_class c
+		JOIN pg_namespace n ON c.relnamespace = n.oid
+		JOIN pg_foreign_table ft ON ft.ftrelid = c.oid
+		JOIN pg_foreign_server s ON s.oid = ft.ftserver
+		WHERE c.relkind = 'f'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Sequences
-		SELECT n.nspname, 'sequence', c.relname, ''
+		SELECT n.nspname, 'sequence', c.relname, '', 0
 		FROM pg_class c
 		JOIN pg_namespace n ON c.relnamespace = n.oid
 		WHERE c.relkind = 'S'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Functions (excluding trigger functions)
-		SELECT n.nspname, 'function', p.proname, pg_get_function_identity_arguments(p.oid)
+		SELECT n.nspname, 'function', p.proname, pg_get_function_identity_arguments(p.oid), 0
 		FROM pg_proc p
 		JOIN pg_namespace n ON p.pronamespace = n.oid
 		WHERE p.prokind = 'f'
 		  AND p.prorettype != 'trigger'::regtype
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Procedures
-		SELECT n.nspname, 'procedure', p.proname, pg_get_function_identity_arguments(p.oid)
+		SELECT n.nspname, 'procedure', p.proname, pg_get_function_identity_arguments(p.oid), 0
 		FROM pg_proc p
 		JOIN pg_namespace n ON p.pronamespace = n.oid
 		WHERE p.prokind = 'p'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Trigger Functions (no arguments needed, they always have no params)
-		SELECT n.nspname, 'trigger_function', p.proname, ''
+		SELECT n.nspname, 'trigger_function', p.proname, '', 0
 		FROM pg_proc p
 		JOIN pg_namespace n ON p.pronamespace = n.oid
 		WHERE p.prorettype = 'trigger'::regtype
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Composite Types
-		SELECT n.nspname, 'composite_type', t.typname, ''
+		SELECT n.nspname, 'composite_type', t.typname, '', 0
 		FROM pg_type t
 		JOIN pg_namespace n ON t.typnamespace = n.oid
 		LEFT JOIN pg_class c ON t.typrelid = c.oid
 		WHERE t.typtype = 'c'
 		  AND (c.relkind IS NULL OR c.relkind = 'c')
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Enum Types
-		SELECT n.nspname, 'enum_type', t.typname, ''
+		SELECT n.nspname, 'enum_type', t.typname, '', 0
 		FROM pg_type t
 		JOIN pg_namespace n ON t.typnamespace = n.oid
 		WHERE t.typtype = 'e'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Domain Types
-		SELECT n.nspname, 'domain_type', t.typname, ''
+		SELECT n.nspname, 'domain_type', t.typname, '', 0
 		FROM pg_type t
 		JOIN pg_namespace n ON t.typnamespace = n.oid
 		WHERE t.typtype = 'd'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		UNION ALL
 
 		-- Range Types
-		SELECT n.nspname, 'range_type', t.typname, ''
+		SELECT n.nspname, 'range_type', t.typname, '', 0
 		FROM pg_type t
 		JOIN pg_namespace n ON t.typnamespace = n.oid
 		WHERE t.typtype = 'r'
-		  AND n.nspname NOT LIKE 'pg_%'
-		  AND n.nspname != 'information_schema'
+		  AND (n.nspname NOT LIKE 'pg\_%' OR n.nspname = 'pg_catalog')
 
 		ORDER BY schema_name, object_type, object_name;
 	`
@@ -989,6 +1067,7 @@ func GetAllSchemaObjects(ctx context.Context, pool *connection.Pool) ([]SchemaOb
 			ObjectType: toString(row["object_type"]),
 			ObjectName: toString(row["object_name"]),
 			Arguments:  toString(row["arguments"]),
+			Columns:    int(toInt64(row["column_count"])),
 		})
 	}
 
@@ -1005,6 +1084,8 @@ func toInt64(v interface{}) int64 {
 		return val
 	case int32:
 		return int64(val)
+	case int16:
+		return int64(val)
 	case int:
 		return int64(val)
 	case float64:
@@ -0,0 +1,62 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// IsSuperuser reports whether the session's current user is a superuser.
+// It checks current_user rather than is_superuser, so a SET ROLE in the
+// startup SQL counts.
+func IsSuperuser(ctx context.Context, pool *connection.Pool) (bool, error) {
+	row, err := pool.QueryRow(ctx, `
+		SELECT r.rolsuper
+		FROM pg_catalog.pg_roles r
+		WHERE r.rolname = current_user
+	`)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for superuser: %w", err)
+	}
+	return toBool(row["rolsuper"]), nil
+}
+
+// GetServerInfo returns the server's version and the session's user,
+// database, encodings and time zone
+func GetServerInfo(ctx context.Context, pool *connection.Pool) (models.ServerInfo, error) {
+	query := `
+		SELECT
+			pg_catalog.version() AS version,
+			pg_catalog.current_setting('server_version') AS server_version,
+			current_user::text AS user_name,
+			session_user::text AS session_user_name,
+			pg_catalog.current_database()::text AS database,
+			pg_catalog.current_setting('server_encoding') AS server_encoding,
+			pg_catalog.current_setting('client_encoding') AS client_encoding,
+			pg_catalog.current_setting('TimeZone') AS timezone,
+			pg_catalog.pg_postmaster_start_time() AS started_at,
+			pg_catalog.pg_is_in_recovery() AS in_recovery
+	`
+
+	row, err := pool.QueryRow(ctx, query)
+	if err != nil {
+		return models.ServerInfo{}, fmt.Errorf("failed to get server info: %w", err)
+	}
+
+	started, _ := row["started_at"].(time.Time)
+	return models.ServerInfo{
+		Version:        toString(row["version"]),
+		ServerVersion:  toString(row["server_version"]),
+		User:           toString(row["user_name"]),
+		SessionUser:    toString(row["session_user_name"]),
+		Database:       toString(row["database"]),
+		ServerEncoding: toString(row["server_encoding"]),
+		ClientEncoding: toString(row["client_encoding"]),
+		TimeZone:       toString(row["timezone"]),
+		StartedAt:      started,
+		InRecovery:     toBool(row["in_recovery"]),
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rebelice/lazypg/internal/db/connection"
 )
 
@@ -67,6 +68,22 @@ func ListSchemas(ctx context.Context, pool *connection.Pool) ([]Schema, error) {
 	return schemas, nil
 }
 
+// GetSearchPath returns the schemas an unqualified name is looked up in, in
+// order. Unlike SHOW search_path this resolves "$user", drops schemas that
+// don't exist and includes the implicitly searched pg_catalog.
+func GetSearchPath(ctx context.Context, pool *connection.Pool) ([]string, error) {
+	rows, err := pool.Query(ctx, "SELECT unnest(current_schemas(true))::text AS name")
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make([]string, 0, len(rows))
+	for _, row := range rows {
+		schemas = append(schemas, toString(row["name"]))
+	}
+	return schemas, nil
+}
+
 // ListTables returns all tables in a schema
 func ListTables(ctx context.Context, pool *connection.Pool, schema string) ([]Table, error) {
 	query := `
@@ -144,3 +161,14 @@ func GetTableRowCount(ctx context.Context, pool *connection.Pool, schema, table
 
 	return estimate, nil
 }
+
+// TableExists reports whether schema.table exists (as a table, view or any
+// other relation the current user can see)
+func TableExists(ctx context.Context, pool *connection.Pool, schema, table string) (bool, error) {
+	row, err := pool.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL AS exists", pgx.Identifier{schema, table}.Sanitize())
+	if err != nil {
+		return false, err
+	}
+	exists, _ := row["exists"].(bool)
+	return exists, nil
+}
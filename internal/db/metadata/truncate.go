@@ -0,0 +1,46 @@
+package metadata
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// sqlStateFeatureNotSupported is what TRUNCATE fails with when other tables
+// reference the table by foreign key
+const sqlStateFeatureNotSupported = "0A000"
+
+// TruncateSQL returns the TRUNCATE statement for schema.table.
+// restartIdentity resets the sequences its identity and serial columns
+// own; cascade also truncates the tables referencing it by foreign key.
+func TruncateSQL(schema, table string, cascade, restartIdentity bool) string {
+	sql := "TRUNCATE TABLE " + pgx.Identifier{schema, table}.Sanitize()
+	if restartIdentity {
+		sql += " RESTART IDENTITY"
+	}
+	if cascade {
+		sql += " CASCADE"
+	}
+	return sql
+}
+
+// DescribeTruncateError explains why a TRUNCATE failed. When foreign keys
+// stopped it, the server's detail names the referencing table and the
+// explanation points at CASCADE.
+func DescribeTruncateError(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != sqlStateFeatureNotSupported ||
+		!strings.Contains(pgErr.Message, "foreign key") {
+		return err.Error()
+	}
+
+	lines := []string{pgErr.Message}
+	if pgErr.Detail != "" {
+		lines = append(lines, "", pgErr.Detail)
+	}
+	lines = append(lines, "",
+		"Truncate again with CASCADE to also empty the referencing tables, or empty them first.")
+	return strings.Join(lines, "\n")
+}
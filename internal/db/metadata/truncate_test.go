@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTruncateSQL(t *testing.T) {
+	tests := []struct {
+		cascade, restart bool
+		want             string
+	}{
+		{false, false, `TRUNCATE TABLE "public"."orders"`},
+		{true, false, `TRUNCATE TABLE "public"."orders" CASCADE`},
+		{false, true, `TRUNCATE TABLE "public"."orders" RESTART IDENTITY`},
+		{true, true, `TRUNCATE TABLE "public"."orders" RESTART IDENTITY CASCADE`},
+	}
+	for _, tt := range tests {
+		if got := TruncateSQL("public", "orders", tt.cascade, tt.restart); got != tt.want {
+			t.Errorf("TruncateSQL(cascade=%v, restart=%v) = %q, want %q", tt.cascade, tt.restart, got, tt.want)
+		}
+	}
+	if got := TruncateSQL("My Schema", `we"ird`, false, false); got != `TRUNCATE TABLE "My Schema"."we""ird"` {
+		t.Errorf("TruncateSQL() = %q, want quoted identifiers", got)
+	}
+}
+
+func TestDescribeTruncateError(t *testing.T) {
+	fkErr := fmt.Errorf("query failed: %w", &pgconn.PgError{
+		Code:    "0A000",
+		Message: "cannot truncate a table referenced in a foreign key constraint",
+		Detail:  `Table "order_items" references "orders".`,
+		Hint:    `Truncate table "order_items" at the same time, or use TRUNCATE ... CASCADE.`,
+	})
+	got := DescribeTruncateError(fkErr)
+	for _, want := range []string{"foreign key constraint", `Table "order_items" references "orders".`, "CASCADE"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("DescribeTruncateError() = %q, want %q", got, want)
+		}
+	}
+
+	other := errors.New("permission denied for table orders")
+	if got := DescribeTruncateError(other); got != other.Error() {
+		t.Errorf("DescribeTruncateError() = %q, want the error as is", got)
+	}
+}
@@ -2,90 +2,212 @@ package query
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rebelice/lazypg/internal/db/connection"
 	"github.com/rebelice/lazypg/internal/models"
 )
 
-// Execute executes a SQL query and returns the results
+// sqlStateSyntaxError is syntax_error, which is also raised when a script
+// of several statements is prepared
+const sqlStateSyntaxError = "42601"
+
+// parseMessageRoutine is the server function that refuses to prepare a
+// script of several statements. Unlike the error message, it isn't
+// translated.
+const parseMessageRoutine = "exec_parse_message"
+
+// explainColumn is the one column of EXPLAIN output
+const explainColumn = "QUERY PLAN"
+
+// errTransactionLeftOpen is returned for a query that began a transaction
+// without ending it
+var errTransactionLeftOpen = errors.New("the query left a transaction open (BEGIN without COMMIT or ROLLBACK); it was rolled back")
+
+// Execute executes a SQL query and returns the results. Notices raised while
+// it runs are returned with it. A script of several statements runs as one
+// simple-protocol query, and the result is its last statement that returned
+// rows.
 func Execute(ctx context.Context, pool *pgxpool.Pool, sql string) models.QueryResult {
 	start := time.Now()
 
-	rows, err := pool.Query(ctx, sql)
+	// Hold one connection for the whole query so its notices can be told
+	// apart from other queries'
+	conn, err := pool.Acquire(ctx)
 	if err != nil {
 		return models.QueryResult{
 			Error:    err,
 			Duration: time.Since(start),
 		}
 	}
+	notices, stopNotices := connection.CollectNotices(conn.Conn().PgConn())
+
+	result, oids := execute(ctx, conn.Conn(), sql, notices)
+	if rollbackOpenTransaction(conn.Conn()) && result.Error == nil {
+		result = models.QueryResult{Error: errTransactionLeftOpen, Statements: result.Statements}
+		oids = nil
+	}
+
+	// Resolve kinds after releasing the connection so the enum lookup doesn't
+	// hold a second connection while this one is busy
+	stopNotices()
+	conn.Release()
+
+	result.Notices = notices.Notices()
+	if result.Error == nil {
+		result.ColumnKinds = connection.ResolveColumnKinds(ctx, pool, oids)
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// execute runs sql on conn and returns the result and its column type OIDs
+func execute(ctx context.Context, conn *pgx.Conn, sql string, notices *connection.NoticeCollector) (models.QueryResult, []uint32) {
+	rows, err := conn.Query(ctx, sql)
+	if err != nil {
+		if isMultipleCommandsError(err) {
+			// Nothing ran: the server refused to prepare a script
+			return executeScript(ctx, conn, sql, notices)
+		}
+		return models.QueryResult{Error: err}, nil
+	}
+
+	columns, oids, result, err := readRows(rows)
+	if err != nil {
+		return models.QueryResult{Error: err}, nil
+	}
+	tag := rows.CommandTag()
+	affected := int64(len(result))
+	if len(columns) == 0 {
+		// INSERT, UPDATE and DELETE without RETURNING only report their
+		// count in the command tag
+		affected = tag.RowsAffected()
+	}
+	return models.QueryResult{
+		Columns:      columns,
+		Rows:         result,
+		RowsAffected: affected,
+		CommandTag:   tag.String(),
+	}, oids
+}
+
+// executeScript runs a script of several statements with the simple
+// protocol, reading every statement's result in turn. Notices are tagged
+// with the statement being read when they arrive, which is the one that
+// raised them. As in psql, the statements run in one implicit transaction
+// unless the script has its own BEGIN and COMMIT.
+func executeScript(ctx context.Context, conn *pgx.Conn, sql string, notices *connection.NoticeCollector) (models.QueryResult, []uint32) {
+	var res models.QueryResult
+	var resOIDs []uint32
+
+	mrr := conn.PgConn().Exec(ctx, sql)
+	for statement := 1; ; statement++ {
+		notices.SetStatement(statement)
+		if !mrr.NextResult() {
+			break
+		}
+
+		rows := pgx.RowsFromResultReader(conn.TypeMap(), mrr.ResultReader())
+		columns, oids, result, err := readRows(rows)
+		if err != nil {
+			_ = mrr.Close()
+			return models.QueryResult{Error: statementError(ctx, statement, err), Statements: res.Statements}, nil
+		}
+
+		tag := rows.CommandTag()
+		res.Statements = append(res.Statements, tag.String())
+		if len(columns) > 0 {
+			res.Columns, res.Rows, resOIDs = columns, result, oids
+			res.RowsAffected = int64(len(result))
+			res.CommandTag = tag.String()
+		} else if res.Columns == nil {
+			res.RowsAffected = tag.RowsAffected()
+			res.CommandTag = tag.String()
+		}
+	}
+	if err := mrr.Close(); err != nil {
+		return models.QueryResult{Error: statementError(ctx, len(res.Statements)+1, err), Statements: res.Statements}, nil
+	}
+	return res, resOIDs
+}
+
+// statementError names the statement of a script that failed. A cancelled
+// script returns the context's error as is, as a cancelled query does.
+func statementError(ctx context.Context, statement int, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return fmt.Errorf("statement %d: %w", statement, err)
+}
+
+// readRows drains rows into display strings
+func readRows(rows pgx.Rows) (columns []string, oids []uint32, result [][]string, err error) {
 	defer rows.Close()
 
-	// Get column names
+	// Get column names and types
 	fieldDescs := rows.FieldDescriptions()
-	columns := make([]string, len(fieldDescs))
+	columns = make([]string, len(fieldDescs))
+	oids = make([]uint32, len(fieldDescs))
 	for i, fd := range fieldDescs {
 		columns[i] = string(fd.Name)
+		oids[i] = fd.DataTypeOID
 	}
 
+	// EXPLAIN (FORMAT JSON) keeps the server's text, with its key order and
+	// spacing; any other json is formatted as usual
+	rawJSON := len(columns) == 1 && columns[0] == explainColumn && oids[0] == pgtype.JSONOID
+
 	// Get rows
-	var result [][]string
 	for rows.Next() {
 		values, err := rows.Values()
 		if err != nil {
-			return models.QueryResult{
-				Error:    err,
-				Duration: time.Since(start),
-			}
+			return nil, nil, nil, err
 		}
 
+		raw := rows.RawValues()
 		row := make([]string, len(values))
 		for i, v := range values {
-			if v == nil {
-				row[i] = "NULL"
-			} else {
-				row[i] = convertValueToString(v)
+			if rawJSON && raw[i] != nil {
+				row[i] = string(raw[i])
+				continue
 			}
+			row[i] = connection.FormatValue(v, oids[i])
 		}
 		result = append(result, row)
 	}
 
 	// Check for errors from iteration
+	rows.Close()
 	if err := rows.Err(); err != nil {
-		return models.QueryResult{
-			Error:    err,
-			Duration: time.Since(start),
-		}
+		return nil, nil, nil, err
 	}
+	return columns, oids, result, nil
+}
 
-	return models.QueryResult{
-		Columns:      columns,
-		Rows:         result,
-		RowsAffected: int64(len(result)),
-		Duration:     time.Since(start),
+// rollbackOpenTransaction rolls back a transaction the query began and
+// didn't end, e.g. a script with BEGIN and no COMMIT, or one whose statement
+// failed after BEGIN. Its changes would otherwise be lost silently when the
+// connection goes back to the pool. Reports whether there was one.
+func rollbackOpenTransaction(conn *pgx.Conn) bool {
+	if conn.PgConn().TxStatus() == 'I' {
+		return false
 	}
+	// The query's context may be cancelled already
+	_, _ = conn.Exec(context.Background(), "ROLLBACK")
+	return true
 }
 
-// convertValueToString converts a database value to string, handling JSONB properly
-func convertValueToString(val interface{}) string {
-	// Check if it's a map or slice (JSONB types)
-	switch v := val.(type) {
-	case map[string]interface{}, []interface{}:
-		// Convert to JSON string
-		jsonBytes, err := json.Marshal(v)
-		if err != nil {
-			return fmt.Sprintf("%v", val)
-		}
-		return string(jsonBytes)
-	case [16]byte:
-		// UUID from PostgreSQL
-		return fmt.Sprintf("%x-%x-%x-%x-%x", v[:4], v[4:6], v[6:8], v[8:10], v[10:])
-	case []byte:
-		// Might be raw JSON bytes
-		return string(v)
-	default:
-		return fmt.Sprintf("%v", val)
-	}
+// isMultipleCommandsError reports whether the server refused to prepare sql
+// because it holds several statements. The routine is checked rather than
+// the message, which depends on the server's lc_messages.
+func isMultipleCommandsError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == sqlStateSyntaxError &&
+		pgErr.Routine == parseMessageRoutine
 }
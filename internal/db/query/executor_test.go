@@ -0,0 +1,50 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsMultipleCommandsError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "english",
+			err:  &pgconn.PgError{Code: "42601", Routine: "exec_parse_message", Message: "cannot insert multiple commands into a prepared statement"},
+			want: true,
+		},
+		{
+			name: "translated",
+			err:  &pgconn.PgError{Code: "42601", Routine: "exec_parse_message", Message: "no se pueden insertar múltiples órdenes en una sentencia preparada"},
+			want: true,
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("prepare: %w", &pgconn.PgError{Code: "42601", Routine: "exec_parse_message"}),
+			want: true,
+		},
+		{
+			name: "syntax error",
+			err:  &pgconn.PgError{Code: "42601", Routine: "scanner_yyerror", Message: "syntax error at or near \"SELEC\""},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("connection reset"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isMultipleCommandsError(tt.err); got != tt.want {
+				t.Errorf("isMultipleCommandsError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
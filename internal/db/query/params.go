@@ -0,0 +1,67 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InlineParams returns sql with its $1, $2, ... placeholders replaced by the
+// args as literals, the statement as it would run. It is for display only:
+// statements are always sent with their parameters. Placeholders inside
+// quoted strings and identifiers, and those without an arg, are left as
+// they are.
+func InlineParams(sql string, args ...any) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '$':
+			j := i + 1
+			for j < len(sql) && sql[j] >= '0' && sql[j] <= '9' {
+				j++
+			}
+			if n, err := strconv.Atoi(sql[i+1 : j]); err == nil && n >= 1 && n <= len(args) {
+				b.WriteString(paramLiteral(args[n-1]))
+				i = j - 1
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// paramLiteral writes a parameter value as a SQL literal
+func paramLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	case time.Time:
+		return "'" + v.Format(time.RFC3339Nano) + "'"
+	case fmt.Stringer:
+		return quoteString(v.String())
+	case string:
+		return quoteString(v)
+	}
+	return quoteString(fmt.Sprint(v))
+}
+
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,21 @@
+package query
+
+import "testing"
+
+func TestInlineParams(t *testing.T) {
+	tests := []struct {
+		sql  string
+		args []any
+		want string
+	}{
+		{"SELECT pg_catalog.pg_terminate_backend($1) AS ok", []any{4242}, "SELECT pg_catalog.pg_terminate_backend(4242) AS ok"},
+		{"UPDATE t SET a = $1, b = $2 WHERE id = $10", []any{"it's", nil}, "UPDATE t SET a = 'it''s', b = NULL WHERE id = $10"},
+		{"SELECT '$1', \"$1\", $1", []any{true}, "SELECT '$1', \"$1\", true"},
+		{"SELECT $$", nil, "SELECT $$"},
+	}
+	for _, tt := range tests {
+		if got := InlineParams(tt.sql, tt.args...); got != tt.want {
+			t.Errorf("InlineParams(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
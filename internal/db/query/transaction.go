@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// Transaction is a statement that ran in a transaction still waiting to be
+// committed or rolled back. It holds its connection until one of them is
+// called, so every Transaction must end with exactly one of the two.
+type Transaction struct {
+	conn *pgxpool.Conn
+	tx   pgx.Tx
+}
+
+// ExecuteInTransaction runs sql like Execute, but inside a transaction that
+// is left open so its effect can be checked before it is kept. The returned
+// Transaction is nil if the statement failed, in which case the transaction
+// has already been rolled back and its connection released.
+func ExecuteInTransaction(ctx context.Context, pool *pgxpool.Pool, sql string) (models.QueryResult, *Transaction) {
+	start := time.Now()
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return models.QueryResult{Error: err, Duration: time.Since(start)}, nil
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return models.QueryResult{Error: err, Duration: time.Since(start)}, nil
+	}
+	t := &Transaction{conn: conn, tx: tx}
+
+	notices, stopNotices := connection.CollectNotices(conn.Conn().PgConn())
+	result, oids := execute(ctx, conn.Conn(), sql, notices)
+	stopNotices()
+	result.Notices = notices.Notices()
+
+	if result.Error != nil {
+		// The context may be the cancelled one, so end the transaction
+		// without it
+		_ = t.Rollback(context.Background())
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	result.ColumnKinds = connection.ResolveColumnKinds(ctx, pool, oids)
+	result.Duration = time.Since(start)
+	return result, t
+}
+
+// Commit commits the transaction and releases its connection
+func (t *Transaction) Commit(ctx context.Context) error {
+	defer t.conn.Release()
+	return t.tx.Commit(ctx)
+}
+
+// Rollback rolls the transaction back and releases its connection
+func (t *Transaction) Rollback(ctx context.Context) error {
+	defer t.conn.Release()
+	return t.tx.Rollback(ctx)
+}
@@ -0,0 +1,188 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// planDetails are the node properties written under a node, in order, as
+// FORMAT TEXT writes them
+var planDetails = []string{
+	"Index Cond", "Recheck Cond", "Hash Cond", "Merge Cond", "Join Filter",
+	"Rows Removed by Join Filter", "Filter", "Rows Removed by Filter",
+	"Rows Removed by Index Recheck", "Sort Key", "Group Key", "Heap Fetches",
+	"Workers Planned", "Workers Launched",
+}
+
+// PlanTextFromJSON renders an EXPLAIN (FORMAT JSON) plan as indented text in the
+// style of FORMAT TEXT, for reading or pasting where JSON isn't accepted.
+// It covers the node line, costs, actual times and the common conditions;
+// properties it doesn't know are left out.
+func PlanTextFromJSON(planJSON string) (string, error) {
+	var explained []map[string]any
+	if err := json.Unmarshal([]byte(planJSON), &explained); err != nil {
+		return "", fmt.Errorf("not an EXPLAIN (FORMAT JSON) plan: %w", err)
+	}
+
+	var lines []string
+	for _, statement := range explained {
+		plan, ok := statement["Plan"].(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("not an EXPLAIN (FORMAT JSON) plan: no Plan")
+		}
+		lines = appendPlanNode(lines, plan, 0, true)
+		for _, timing := range []string{"Planning Time", "Execution Time"} {
+			if ms, ok := statement[timing].(float64); ok {
+				lines = append(lines, fmt.Sprintf("%s: %.3f ms", timing, ms))
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// appendPlanNode writes node at indent and its children below it. Child
+// nodes start with "->", and details go under the node's name.
+func appendPlanNode(lines []string, node map[string]any, indent int, root bool) []string {
+	pad := strings.Repeat(" ", indent)
+	inner := indent + 6 // Details and children, under the name after "->  "
+	if root {
+		inner = 2
+	}
+
+	if name, ok := node["Subplan Name"].(string); ok {
+		lines = append(lines, pad+name)
+	}
+	line := planNodeName(node) + "  " + planNodeCosts(node)
+	if root {
+		lines = append(lines, line)
+	} else {
+		lines = append(lines, pad+"->  "+line)
+	}
+
+	detailPad := strings.Repeat(" ", inner)
+	for _, key := range planDetails {
+		if value, ok := node[key]; ok {
+			lines = append(lines, detailPad+key+": "+planValue(value))
+		}
+	}
+	if method, ok := node["Sort Method"].(string); ok {
+		sort := "Sort Method: " + method
+		if space, ok := node["Sort Space Used"].(float64); ok {
+			sort += fmt.Sprintf("  %s: %.0fkB", planValue(node["Sort Space Type"]), space)
+		}
+		lines = append(lines, detailPad+sort)
+	}
+
+	children, _ := node["Plans"].([]any)
+	for _, child := range children {
+		if childNode, ok := child.(map[string]any); ok {
+			lines = appendPlanNode(lines, childNode, inner, false)
+		}
+	}
+	return lines
+}
+
+// planNodeName names a node as FORMAT TEXT does, e.g. "Hash Left Join" or
+// "Index Scan using orders_pkey on orders o"
+func planNodeName(node map[string]any) string {
+	nodeType, _ := node["Node Type"].(string)
+	name := nodeType
+
+	switch nodeType {
+	case "Aggregate":
+		switch node["Strategy"] {
+		case "Hashed":
+			name = "HashAggregate"
+		case "Sorted":
+			name = "GroupAggregate"
+		case "Mixed":
+			name = "MixedAggregate"
+		}
+	case "Hash Join", "Merge Join", "Nested Loop":
+		if join, ok := node["Join Type"].(string); ok && join != "Inner" {
+			if nodeType == "Nested Loop" {
+				name += " " + join + " Join"
+			} else {
+				name = strings.TrimSuffix(nodeType, " Join") + " " + join + " Join"
+			}
+		}
+	}
+	if mode, ok := node["Partial Mode"].(string); ok && mode != "Simple" {
+		name = mode + " " + name
+	}
+	if parallel, _ := node["Parallel Aware"].(bool); parallel {
+		name = "Parallel " + name
+	}
+	if node["Scan Direction"] == "Backward" {
+		name += " Backward"
+	}
+
+	if index, ok := node["Index Name"].(string); ok {
+		if nodeType == "Bitmap Index Scan" {
+			return name + " on " + index
+		}
+		name += " using " + index
+	}
+	target := ""
+	for _, key := range []string{"Relation Name", "CTE Name", "Function Name"} {
+		if s, ok := node[key].(string); ok {
+			target = s
+			break
+		}
+	}
+	alias, _ := node["Alias"].(string)
+	switch {
+	case target != "" && alias != "" && alias != target:
+		name += " on " + target + " " + alias
+	case target != "":
+		name += " on " + target
+	case alias != "":
+		name += " on " + alias
+	}
+	return name
+}
+
+// planNodeCosts returns a node's estimates, with the actual figures when the
+// plan was run with ANALYZE
+func planNodeCosts(node map[string]any) string {
+	costs := fmt.Sprintf("(cost=%.2f..%.2f rows=%.0f width=%.0f)",
+		planNumber(node["Startup Cost"]), planNumber(node["Total Cost"]),
+		planNumber(node["Plan Rows"]), planNumber(node["Plan Width"]))
+	if _, ok := node["Actual Total Time"]; ok {
+		costs += fmt.Sprintf(" (actual time=%.3f..%.3f rows=%.0f loops=%.0f)",
+			planNumber(node["Actual Startup Time"]), planNumber(node["Actual Total Time"]),
+			planNumber(node["Actual Rows"]), planNumber(node["Actual Loops"]))
+	} else if loops, ok := node["Actual Loops"]; ok && planNumber(loops) == 0 {
+		costs += " (never executed)"
+	}
+	return costs
+}
+
+// planNumber reads a number of the plan, 0 if it is missing
+func planNumber(v any) float64 {
+	n, _ := v.(float64)
+	return n
+}
+
+// planValue writes a property's value: lists comma-separated, numbers
+// without a fraction when whole
+func planValue(v any) string {
+	switch v := v.(type) {
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = planValue(item)
+		}
+		return strings.Join(parts, ", ")
+	case float64:
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%g", v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
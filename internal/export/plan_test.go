@@ -0,0 +1,125 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+const analyzedPlan = `[
+  {
+    "Plan": {
+      "Node Type": "Hash Join",
+      "Parallel Aware": false,
+      "Join Type": "Left",
+      "Startup Cost": 1.07,
+      "Total Cost": 2.21,
+      "Plan Rows": 5,
+      "Plan Width": 68,
+      "Actual Startup Time": 0.031,
+      "Actual Total Time": 0.036,
+      "Actual Rows": 5,
+      "Actual Loops": 1,
+      "Hash Cond": "(o.customer_id = c.id)",
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Parent Relationship": "Outer",
+          "Relation Name": "orders",
+          "Alias": "o",
+          "Startup Cost": 0.00,
+          "Total Cost": 1.05,
+          "Plan Rows": 5,
+          "Plan Width": 36,
+          "Actual Startup Time": 0.008,
+          "Actual Total Time": 0.009,
+          "Actual Rows": 5,
+          "Actual Loops": 1,
+          "Filter": "(total > 10)",
+          "Rows Removed by Filter": 2
+        },
+        {
+          "Node Type": "Hash",
+          "Parent Relationship": "Inner",
+          "Startup Cost": 1.03,
+          "Total Cost": 1.03,
+          "Plan Rows": 3,
+          "Plan Width": 36,
+          "Actual Startup Time": 0.012,
+          "Actual Total Time": 0.012,
+          "Actual Rows": 3,
+          "Actual Loops": 1,
+          "Plans": [
+            {
+              "Node Type": "Index Scan",
+              "Parent Relationship": "Outer",
+              "Scan Direction": "Forward",
+              "Index Name": "customers_pkey",
+              "Relation Name": "customers",
+              "Alias": "c",
+              "Startup Cost": 0.15,
+              "Total Cost": 1.03,
+              "Plan Rows": 3,
+              "Plan Width": 36,
+              "Actual Startup Time": 0.004,
+              "Actual Total Time": 0.005,
+              "Actual Rows": 3,
+              "Actual Loops": 1
+            }
+          ]
+        }
+      ]
+    },
+    "Planning Time": 0.152,
+    "Triggers": [],
+    "Execution Time": 0.071
+  }
+]`
+
+func TestPlanText(t *testing.T) {
+	got, err := PlanTextFromJSON(analyzedPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"Hash Left Join  (cost=1.07..2.21 rows=5 width=68) (actual time=0.031..0.036 rows=5 loops=1)",
+		"  Hash Cond: (o.customer_id = c.id)",
+		"  ->  Seq Scan on orders o  (cost=0.00..1.05 rows=5 width=36) (actual time=0.008..0.009 rows=5 loops=1)",
+		"        Filter: (total > 10)",
+		"        Rows Removed by Filter: 2",
+		"  ->  Hash  (cost=1.03..1.03 rows=3 width=36) (actual time=0.012..0.012 rows=3 loops=1)",
+		"        ->  Index Scan using customers_pkey on customers c  (cost=0.15..1.03 rows=3 width=36) (actual time=0.004..0.005 rows=3 loops=1)",
+		"Planning Time: 0.152 ms",
+		"Execution Time: 0.071 ms",
+	}, "\n")
+	if got != want {
+		t.Errorf("PlanTextFromJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPlanTextNodeNames(t *testing.T) {
+	tests := []struct {
+		plan string
+		want string
+	}{
+		{`{"Node Type": "Aggregate", "Strategy": "Hashed", "Partial Mode": "Partial"}`, "Partial HashAggregate"},
+		{`{"Node Type": "Aggregate", "Strategy": "Plain", "Partial Mode": "Simple"}`, "Aggregate"},
+		{`{"Node Type": "Seq Scan", "Parallel Aware": true, "Relation Name": "t", "Alias": "t"}`, "Parallel Seq Scan on t"},
+		{`{"Node Type": "Nested Loop", "Join Type": "Anti"}`, "Nested Loop Anti Join"},
+		{`{"Node Type": "Bitmap Index Scan", "Index Name": "t_idx"}`, "Bitmap Index Scan on t_idx"},
+		{`{"Node Type": "Index Only Scan", "Scan Direction": "Backward", "Index Name": "t_pkey", "Relation Name": "t", "Alias": "t"}`, "Index Only Scan Backward using t_pkey on t"},
+		{`{"Node Type": "CTE Scan", "CTE Name": "recent", "Alias": "r"}`, "CTE Scan on recent r"},
+	}
+	for _, tt := range tests {
+		got, err := PlanTextFromJSON(`[{"Plan": ` + tt.plan + `}]`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name, _, _ := strings.Cut(got, "  ("); name != tt.want {
+			t.Errorf("node %s named %q, want %q", tt.plan, name, tt.want)
+		}
+	}
+
+	if _, err := PlanTextFromJSON(`Seq Scan on t`); err == nil {
+		t.Error("PlanTextFromJSON() of a text plan succeeded")
+	}
+}
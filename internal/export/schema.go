@@ -0,0 +1,169 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// SchemaObject is a table or view in a schema export
+type SchemaObject struct {
+	Schema  string
+	Name    string
+	Kind    string // "table", "view", "materialized view" or "foreign table"
+	Columns []models.ColumnInfo
+}
+
+// SchemaExport is the part of a database's schema loaded in the tree,
+// written out for documentation
+type SchemaExport struct {
+	Database    string
+	Objects     []SchemaObject // Grouped by schema, in tree order
+	ForeignKeys []models.ForeignKey
+}
+
+// schemaGroup is the objects of one schema, in order
+type schemaGroup struct {
+	name    string
+	objects []SchemaObject
+}
+
+// groups splits the objects by schema, keeping the order schemas first
+// appear in
+func (s SchemaExport) groups() []schemaGroup {
+	var groups []schemaGroup
+	index := make(map[string]int)
+	for _, obj := range s.Objects {
+		i, ok := index[obj.Schema]
+		if !ok {
+			i = len(groups)
+			index[obj.Schema] = i
+			groups = append(groups, schemaGroup{name: obj.Schema})
+		}
+		groups[i].objects = append(groups[i].objects, obj)
+	}
+	return groups
+}
+
+// foreignKeysFrom returns the foreign keys of table schema.name
+func (s SchemaExport) foreignKeysFrom(schema, name string) []models.ForeignKey {
+	var keys []models.ForeignKey
+	for _, fk := range s.ForeignKeys {
+		if fk.Schema == schema && fk.Table == name {
+			keys = append(keys, fk)
+		}
+	}
+	return keys
+}
+
+// Outline returns the schema as an indented outline: database, schemas,
+// objects, then each object's columns and foreign keys
+func (s SchemaExport) Outline() string {
+	var b strings.Builder
+	b.WriteString(s.Database + "\n")
+	for _, group := range s.groups() {
+		fmt.Fprintf(&b, "  %s\n", group.name)
+		for _, obj := range group.objects {
+			fmt.Fprintf(&b, "    %s (%s)\n", obj.Name, obj.Kind)
+			for _, col := range obj.Columns {
+				fmt.Fprintf(&b, "      %s\n", columnSummary(col))
+			}
+			for _, fk := range s.foreignKeysFrom(obj.Schema, obj.Name) {
+				fmt.Fprintf(&b, "      FK %s (%s) → %s.%s (%s)\n",
+					fk.Name, strings.Join(fk.Columns, ", "),
+					fk.RefSchema, fk.RefTable, strings.Join(fk.RefColumns, ", "))
+			}
+		}
+	}
+	return b.String()
+}
+
+// DOT returns the schema as a Graphviz digraph: one box per object, listing
+// its columns, clustered by schema, with an edge per foreign key. Keys to
+// objects outside the export are left out.
+func (s SchemaExport) DOT() string {
+	exported := make(map[string]bool, len(s.Objects))
+	for _, obj := range s.Objects {
+		exported[dotNodeID(obj.Schema, obj.Name)] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(s.Database))
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	for i, group := range s.groups() {
+		fmt.Fprintf(&b, "\n\tsubgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", dotQuote(group.name))
+		for _, obj := range group.objects {
+			// \l ends a left-aligned line of the label
+			label := dotEscape(obj.Name)
+			if obj.Kind != "table" {
+				label += dotEscape(" (" + obj.Kind + ")")
+			}
+			label += `\l`
+			if len(obj.Columns) > 0 {
+				label += `\l`
+			}
+			for _, col := range obj.Columns {
+				label += dotEscape(columnSummary(col)) + `\l`
+			}
+			fmt.Fprintf(&b, "\t\t%s [label=\"%s\"];\n", dotNodeID(obj.Schema, obj.Name), label)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	edges := false
+	for _, fk := range s.ForeignKeys {
+		from := dotNodeID(fk.Schema, fk.Table)
+		to := dotNodeID(fk.RefSchema, fk.RefTable)
+		if !exported[from] || !exported[to] {
+			continue
+		}
+		if !edges {
+			b.WriteString("\n")
+			edges = true
+		}
+		pairs := make([]string, len(fk.Columns))
+		for i, col := range fk.Columns {
+			ref := ""
+			if i < len(fk.RefColumns) {
+				ref = fk.RefColumns[i]
+			}
+			pairs[i] = col + " → " + ref
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s, tooltip=%s];\n",
+			from, to, dotQuote(strings.Join(pairs, ", ")), dotQuote(fk.Name))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// columnSummary describes a column on one line, e.g. "id integer PK"
+func columnSummary(col models.ColumnInfo) string {
+	s := col.Name + " " + col.DataType
+	if col.PrimaryKey {
+		s += " PK"
+	} else if !col.Nullable {
+		s += " NOT NULL"
+	}
+	return s
+}
+
+// dotNodeID identifies an object in the graph by its quoted SQL name, which
+// can't collide the way "schema.name" could when names contain dots
+func dotNodeID(schema, name string) string {
+	return dotQuote(pgx.Identifier{schema, name}.Sanitize())
+}
+
+// dotQuote returns s as a double-quoted DOT ID
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+// dotEscape escapes s for use inside a double-quoted DOT string. Backslashes
+// are doubled so names can't form label escapes like \l.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "").Replace(s)
+}
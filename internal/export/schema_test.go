@@ -0,0 +1,78 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+func testSchemaExport() SchemaExport {
+	return SchemaExport{
+		Database: "shop",
+		Objects: []SchemaObject{
+			{Schema: "public", Name: "orgs", Kind: "table", Columns: []models.ColumnInfo{
+				{Name: "id", DataType: "integer", PrimaryKey: true},
+			}},
+			{Schema: "public", Name: "users", Kind: "table", Columns: []models.ColumnInfo{
+				{Name: "id", DataType: "integer", PrimaryKey: true},
+				{Name: "org_id", DataType: "integer", Nullable: true},
+				{Name: "email", DataType: "text"},
+			}},
+			{Schema: "public", Name: "active_users", Kind: "view"},
+			{Schema: `we"ird`, Name: `a\b`, Kind: "table"},
+		},
+		ForeignKeys: []models.ForeignKey{
+			{Name: "users_org_id_fkey", Schema: "public", Table: "users", Columns: []string{"org_id"},
+				RefSchema: "public", RefTable: "orgs", RefColumns: []string{"id"}},
+			{Name: "users_plan_fkey", Schema: "public", Table: "users", Columns: []string{"plan"},
+				RefSchema: "billing", RefTable: "plans", RefColumns: []string{"name"}},
+		},
+	}
+}
+
+func TestSchemaOutline(t *testing.T) {
+	want := `shop
+  public
+    orgs (table)
+      id integer PK
+    users (table)
+      id integer PK
+      org_id integer
+      email text NOT NULL
+      FK users_org_id_fkey (org_id) → public.orgs (id)
+      FK users_plan_fkey (plan) → billing.plans (name)
+    active_users (view)
+  we"ird
+    a\b (table)
+`
+	if got := testSchemaExport().Outline(); got != want {
+		t.Errorf("Outline() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSchemaDOT(t *testing.T) {
+	got := testSchemaExport().DOT()
+
+	for _, want := range []string{
+		`digraph "shop" {`,
+		`label="public";`,
+		`"\"public\".\"users\"" [label="users\l\lid integer PK\lorg_id integer\lemail text NOT NULL\l"];`,
+		`"\"public\".\"active_users\"" [label="active_users (view)\l"];`,
+		`label="we\"ird";`,
+		`"\"we\"\"ird\".\"a\\b\"" [label="a\\b\l"];`,
+		`"\"public\".\"users\"" -> "\"public\".\"orgs\"" [label="org_id → id", tooltip="users_org_id_fkey"];`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("DOT() is missing %s\n%s", want, got)
+		}
+	}
+
+	// The key to billing.plans points outside the export
+	if strings.Contains(got, "plans") {
+		t.Errorf("DOT() has an edge to an object not exported:\n%s", got)
+	}
+	if strings.Count(got, "{") != strings.Count(got, "}") {
+		t.Errorf("DOT() has unbalanced braces:\n%s", got)
+	}
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"encoding/csv"
+	"strings"
+)
+
+// NullValue is how query results hold SQL NULL
+const NullValue = "NULL"
+
+// TSV returns a header line and rows as tab-separated values, the format
+// spreadsheets paste into separate cells. A value holding a tab, newline
+// or double quote is quoted, with its quotes doubled, the way spreadsheets
+// quote such values when copying, so a multi-line value stays one cell.
+// NULLs are written as nullAs; rows shorter than the header get empty
+// cells.
+func TSV(columns []string, rows [][]string, nullAs string) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Comma = '\t'
+
+	// Writing to a strings.Builder can't fail
+	_ = w.Write(columns)
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = row[i]
+			}
+			if record[i] == NullValue {
+				record[i] = nullAs
+			}
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package export
+
+import "testing"
+
+func TestTSV(t *testing.T) {
+	columns := []string{"id", "note", "deleted_at"}
+	rows := [][]string{
+		{"1", "plain", "NULL"},
+		{"2", "line one\nline two", "2024-01-01"},
+		{"3", "a\tb", "NULL"},
+		{"4", `say "hi"`, ""},
+		{"5"}, // Short row
+	}
+
+	want := "id\tnote\tdeleted_at\n" +
+		"1\tplain\t\n" +
+		"2\t\"line one\nline two\"\t2024-01-01\n" +
+		"3\t\"a\tb\"\t\n" +
+		"4\t\"say \"\"hi\"\"\"\t\n" +
+		"5\t\t\n"
+	if got := TSV(columns, rows, ""); got != want {
+		t.Errorf("TSV() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := TSV(columns[:1], [][]string{{"NULL"}}, `\N`); got != "id\n\\N\n" {
+		t.Errorf("TSV() with nullAs = %q, want NULL written as \\N", got)
+	}
+}
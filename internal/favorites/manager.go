@@ -1,9 +1,11 @@
 package favorites
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -116,6 +118,103 @@ func (m *Manager) Add(name, description, query, connection, database string, tag
 	return &favorite, nil
 }
 
+// AddBookmark adds a favorite that opens a database object instead of running a query
+func (m *Manager) AddBookmark(name, description string, object models.FavoriteObject, connection, database string, tags []string) (*models.Favorite, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return nil, fmt.Errorf("favorite name cannot be empty")
+	}
+	if object.Name == "" || object.Type == "" {
+		return nil, fmt.Errorf("bookmarked object must have a type and name")
+	}
+
+	for _, fav := range m.favorites {
+		if strings.EqualFold(fav.Name, name) {
+			return nil, fmt.Errorf("a favorite with the name '%s' already exists (names are case-insensitive)", name)
+		}
+		if fav.Object != nil && *fav.Object == object && fav.Connection == connection && fav.Database == database {
+			return nil, fmt.Errorf("%s is already bookmarked as '%s'", object.QualifiedName(), fav.Name)
+		}
+	}
+
+	favorite := models.Favorite{
+		ID:          uuid.New().String(),
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Object:      &object,
+		Tags:        tags,
+		Connection:  connection,
+		Database:    database,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	m.favorites = append(m.favorites, favorite)
+
+	if err := m.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save favorite: %w", err)
+	}
+
+	return &favorite, nil
+}
+
+// AddView adds a favorite that reopens a table with a filter and sort applied
+func (m *Manager) AddView(name, description string, view models.FavoriteView, connection, database string, tags []string) (*models.Favorite, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return nil, fmt.Errorf("favorite name cannot be empty")
+	}
+	if view.Schema == "" || view.Table == "" {
+		return nil, fmt.Errorf("saved view must have a schema and table")
+	}
+
+	for _, fav := range m.favorites {
+		if strings.EqualFold(fav.Name, name) {
+			return nil, fmt.Errorf("a favorite with the name '%s' already exists (names are case-insensitive)", name)
+		}
+	}
+
+	favorite := models.Favorite{
+		ID:          uuid.New().String(),
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		View:        &view,
+		Tags:        tags,
+		Connection:  connection,
+		Database:    database,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	m.favorites = append(m.favorites, favorite)
+
+	if err := m.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save favorite: %w", err)
+	}
+
+	return &favorite, nil
+}
+
+// UniqueName returns name, or name with a " (2)", " (3)"... suffix if a
+// favorite already uses it
+func (m *Manager) UniqueName(name string) string {
+	taken := func(candidate string) bool {
+		for _, fav := range m.favorites {
+			if strings.EqualFold(fav.Name, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+	candidate := name
+	for i := 2; taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s (%d)", name, i)
+	}
+	return candidate
+}
+
 // Update updates an existing favorite
 func (m *Manager) Update(id string, name, description, query string, tags []string) error {
 	// Validate inputs
@@ -125,15 +224,16 @@ func (m *Manager) Update(id string, name, description, query string, tags []stri
 	if name == "" {
 		return fmt.Errorf("favorite name cannot be empty")
 	}
-	if query == "" {
-		return fmt.Errorf("favorite query cannot be empty")
-	}
 
 	// Check for duplicate names (case-insensitive, excluding the current favorite)
 	for _, fav := range m.favorites {
 		if fav.ID != id && strings.EqualFold(fav.Name, name) {
 			return fmt.Errorf("a favorite with the name '%s' already exists (names are case-insensitive)", name)
 		}
+		// Bookmarks and views have no query to validate
+		if fav.ID == id && query == "" && fav.IsQuery() {
+			return fmt.Errorf("favorite query cannot be empty")
+		}
 	}
 
 	for i, fav := range m.favorites {
@@ -301,3 +401,107 @@ func (m *Manager) ExportToJSON(customPath ...string) (string, error) {
 
 	return path, nil
 }
+
+// ImportResult summarizes a favorites import
+type ImportResult struct {
+	Imported   int
+	Duplicates int      // Already present with the same query
+	Conflicts  []string // Names that clash with a different existing favorite
+	Invalid    []string // Entries skipped because they failed validation
+}
+
+// ImportFromJSON merges favorites from a JSON file produced by ExportToJSON.
+// Entries are matched by ID and then by name; existing favorites are never
+// overwritten, so clashes are skipped and reported as conflicts.
+func (m *Manager) ImportFromJSON(customPath ...string) (*ImportResult, error) {
+	path := filepath.Join(filepath.Dir(m.path), "favorites.json")
+	if len(customPath) > 0 && customPath[0] != "" {
+		path = customPath[0]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read favorites file: %w", err)
+	}
+
+	var incoming []models.Favorite
+	if err := json.Unmarshal(data, &incoming); err != nil {
+		return nil, fmt.Errorf("failed to parse favorites JSON: %w", err)
+	}
+
+	result := &ImportResult{}
+	for i, fav := range incoming {
+		fav.Name = strings.TrimSpace(fav.Name)
+		fav.Query = strings.TrimSpace(fav.Query)
+		if fav.Object != nil && (fav.Object.Name == "" || fav.Object.Type == "") {
+			result.Invalid = append(result.Invalid, fmt.Sprintf("entry %d: bookmarked object needs a type and name", i+1))
+			continue
+		}
+		if fav.View != nil && (fav.View.Schema == "" || fav.View.Table == "") {
+			result.Invalid = append(result.Invalid, fmt.Sprintf("entry %d: saved view needs a schema and table", i+1))
+			continue
+		}
+		if fav.Name == "" || (fav.Query == "" && fav.IsQuery()) {
+			result.Invalid = append(result.Invalid, fmt.Sprintf("entry %d: name and query are required", i+1))
+			continue
+		}
+
+		if existing := m.findImportMatch(fav); existing != nil {
+			if sameTarget(*existing, fav) {
+				result.Duplicates++
+			} else {
+				result.Conflicts = append(result.Conflicts, fav.Name)
+			}
+			continue
+		}
+
+		if fav.ID == "" {
+			fav.ID = uuid.New().String()
+		}
+		now := time.Now()
+		if fav.CreatedAt.IsZero() {
+			fav.CreatedAt = now
+		}
+		if fav.UpdatedAt.IsZero() {
+			fav.UpdatedAt = now
+		}
+
+		m.favorites = append(m.favorites, fav)
+		result.Imported++
+	}
+
+	if result.Imported > 0 {
+		if err := m.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save imported favorites: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// findImportMatch returns the existing favorite with the same ID or name
+func (m *Manager) findImportMatch(fav models.Favorite) *models.Favorite {
+	for i := range m.favorites {
+		if fav.ID != "" && m.favorites[i].ID == fav.ID {
+			return &m.favorites[i]
+		}
+	}
+	for i := range m.favorites {
+		if strings.EqualFold(m.favorites[i].Name, fav.Name) {
+			return &m.favorites[i]
+		}
+	}
+	return nil
+}
+
+// sameTarget reports whether two favorites run the same query, open the
+// same object or show the same table view
+func sameTarget(a, b models.Favorite) bool {
+	if a.IsBookmark() || b.IsBookmark() {
+		return a.IsBookmark() && b.IsBookmark() && *a.Object == *b.Object
+	}
+	if a.IsView() || b.IsView() {
+		return a.IsView() && b.IsView() && reflect.DeepEqual(*a.View, *b.View)
+	}
+	return strings.TrimSpace(a.Query) == strings.TrimSpace(b.Query)
+}
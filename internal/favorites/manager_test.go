@@ -0,0 +1,201 @@
+package favorites
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+func TestImportFromJSON_MergesWithoutOverwriting(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if _, err := m.Add("active users", "", "SELECT * FROM users WHERE active", "", "", nil); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := m.Add("orders", "", "SELECT * FROM orders", "", "", nil); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	importJSON := `[
+		{"Name": "Active Users", "Query": "SELECT * FROM users WHERE active"},
+		{"Name": "orders", "Query": "SELECT id FROM orders"},
+		{"Name": "invoices", "Query": "SELECT * FROM invoices", "Tags": ["billing"]},
+		{"Name": "", "Query": "SELECT 1"}
+	]`
+	path := filepath.Join(dir, "import.json")
+	if err := os.WriteFile(path, []byte(importJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := m.ImportFromJSON(path)
+	if err != nil {
+		t.Fatalf("ImportFromJSON: %v", err)
+	}
+
+	if result.Imported != 1 {
+		t.Errorf("Imported = %d, want 1", result.Imported)
+	}
+	if result.Duplicates != 1 {
+		t.Errorf("Duplicates = %d, want 1", result.Duplicates)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "orders" {
+		t.Errorf("Conflicts = %v, want [orders]", result.Conflicts)
+	}
+	if len(result.Invalid) != 1 {
+		t.Errorf("Invalid = %v, want 1 entry", result.Invalid)
+	}
+
+	// The conflicting favorite keeps its original query
+	for _, fav := range m.GetAll() {
+		if fav.Name == "orders" && fav.Query != "SELECT * FROM orders" {
+			t.Errorf("existing favorite was overwritten: %q", fav.Query)
+		}
+		if fav.Name == "invoices" && fav.ID == "" {
+			t.Error("imported favorite should be assigned an ID")
+		}
+	}
+	if len(m.GetAll()) != 3 {
+		t.Errorf("expected 3 favorites, got %d", len(m.GetAll()))
+	}
+}
+
+func TestImportFromJSON_RoundTrip(t *testing.T) {
+	src, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.Add("count", "row count", "SELECT count(*) FROM t", "", "", []string{"stats"}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := src.ExportToJSON()
+	if err != nil {
+		t.Fatalf("ExportToJSON: %v", err)
+	}
+
+	dst, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := dst.ImportFromJSON(path)
+	if err != nil {
+		t.Fatalf("ImportFromJSON: %v", err)
+	}
+	if result.Imported != 1 {
+		t.Fatalf("Imported = %d, want 1", result.Imported)
+	}
+	got := dst.GetAll()[0]
+	want := src.GetAll()[0]
+	if got.ID != want.ID || got.Query != want.Query || got.Description != want.Description {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+func TestAddBookmark(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := models.FavoriteObject{Schema: "sales", Type: models.TreeNodeTypeTable, Name: "orders"}
+	fav, err := m.AddBookmark("orders", "", obj, "prod", "shop", nil)
+	if err != nil {
+		t.Fatalf("AddBookmark: %v", err)
+	}
+	if _, err := m.AddBookmark("orders again", "", obj, "prod", "shop", nil); err == nil {
+		t.Error("expected an error when bookmarking the same object twice")
+	}
+
+	// Bookmarks have no query, so editing one must not require it
+	if err := m.Update(fav.ID, "sales orders", "", "", []string{"sales"}); err != nil {
+		t.Errorf("Update bookmark: %v", err)
+	}
+
+	reloaded, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.GetAll()
+	if len(got) != 1 || !got[0].IsBookmark() || *got[0].Object != obj || got[0].Name != "sales orders" {
+		t.Fatalf("bookmark did not survive reload: %+v", got)
+	}
+
+	// Bookmarks survive an export/import round trip
+	path, err := reloaded.ExportToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := dst.ImportFromJSON(path)
+	if err != nil {
+		t.Fatalf("ImportFromJSON: %v", err)
+	}
+	if result.Imported != 1 || len(result.Invalid) != 0 {
+		t.Fatalf("Imported = %d, Invalid = %v", result.Imported, result.Invalid)
+	}
+	if imported := dst.GetAll()[0]; !imported.IsBookmark() || *imported.Object != obj {
+		t.Errorf("imported bookmark mismatch: %+v", imported)
+	}
+}
+
+func TestAddView(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	view := models.FavoriteView{
+		Schema: "sales",
+		Table:  "orders",
+		Filter: &models.Filter{
+			Schema:    "sales",
+			TableName: "orders",
+			RootGroup: models.FilterGroup{
+				Logic: "AND",
+				Conditions: []models.FilterCondition{
+					{Column: "status", Operator: models.OpEqual, Value: "open", Type: "text"},
+					{Column: "shipped_at", Operator: models.OpIsNull},
+				},
+			},
+		},
+		SortColumn: "created_at",
+		SortDir:    "DESC",
+		NullsFirst: true,
+	}
+	fav, err := m.AddView("open orders", "", view, "prod", "shop", nil)
+	if err != nil {
+		t.Fatalf("AddView: %v", err)
+	}
+	if _, err := m.AddView("no table", "", models.FavoriteView{Schema: "sales"}, "", "", nil); err == nil {
+		t.Error("expected an error for a view without a table")
+	}
+
+	// Views have no query, so editing one must not require it
+	if err := m.Update(fav.ID, "open sales orders", "", "", nil); err != nil {
+		t.Errorf("Update view: %v", err)
+	}
+
+	// The filter model survives the YAML round trip
+	reloaded, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.GetAll()
+	if len(got) != 1 || !got[0].IsView() || got[0].IsQuery() {
+		t.Fatalf("view did not survive reload: %+v", got)
+	}
+	if !reflect.DeepEqual(*got[0].View, view) {
+		t.Errorf("reloaded view = %+v, want %+v", *got[0].View, view)
+	}
+	if want := "sales.orders, 2 filters, sorted by created_at DESC"; got[0].View.Describe() != want {
+		t.Errorf("Describe() = %q, want %q", got[0].View.Describe(), want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rebelice/lazypg/internal/models"
 )
 
@@ -29,6 +30,22 @@ func (b *Builder) BuildWhere(filter models.Filter) (string, []interface{}, error
 	return "WHERE " + clause, args, nil
 }
 
+// BuildCount generates a COUNT(*) query over the rows of schema.table that
+// match a filter. The WHERE clause and its arguments come from BuildWhere, so
+// the count uses the same parameters as the filtered data load.
+func (b *Builder) BuildCount(schema, table string, filter models.Filter) (string, []interface{}, error) {
+	whereClause, args, err := b.BuildWhere(filter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := "SELECT count(*) FROM " + pgx.Identifier{schema, table}.Sanitize()
+	if whereClause != "" {
+		query += " " + whereClause
+	}
+	return query, args, nil
+}
+
 // buildGroup recursively builds a filter group
 func (b *Builder) buildGroup(group models.FilterGroup, paramIndex int) (string, []interface{}, error) {
 	var clauses []string
@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+func TestBuildCount(t *testing.T) {
+	b := NewBuilder()
+
+	filter := models.Filter{
+		RootGroup: models.FilterGroup{
+			Logic: "AND",
+			Conditions: []models.FilterCondition{
+				{Column: "status", Operator: models.OpEqual, Value: "open"},
+				{Column: "total", Operator: models.OpGreaterThan, Value: 100},
+			},
+		},
+	}
+
+	query, args, err := b.BuildCount("public", "orders", filter)
+	if err != nil {
+		t.Fatalf("BuildCount() error = %v", err)
+	}
+	want := `SELECT count(*) FROM "public"."orders" WHERE "status" = $1 AND "total" > $2`
+	if query != want {
+		t.Errorf("BuildCount() query = %q, want %q", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"open", 100}) {
+		t.Errorf("BuildCount() args = %v", args)
+	}
+
+	// The count must use exactly the data load's WHERE clause and arguments
+	where, whereArgs, _ := b.BuildWhere(filter)
+	if query != `SELECT count(*) FROM "public"."orders" `+where || !reflect.DeepEqual(args, whereArgs) {
+		t.Errorf("BuildCount() differs from BuildWhere(): %q vs %q", query, where)
+	}
+
+	query, args, err = b.BuildCount("public", "orders", models.Filter{})
+	if err != nil || query != `SELECT count(*) FROM "public"."orders"` || len(args) != 0 {
+		t.Errorf("BuildCount() without conditions = %q, %v, %v", query, args, err)
+	}
+}
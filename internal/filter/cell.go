@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// NullCellValue is how the data grid shows a NULL
+const NullCellValue = "NULL"
+
+// decimalPattern matches a finite number Postgres reads as a numeric
+// constant. NaN and Infinity are words it would take for column names.
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// CellCondition returns the condition matching a grid cell: column = value,
+// or column IS NULL for a NULL cell. dataType is the column's PostgreSQL
+// type, or "" if unknown.
+func CellCondition(column, value, dataType string) models.FilterCondition {
+	if value == NullCellValue {
+		return models.FilterCondition{Column: column, Operator: models.OpIsNull, Type: dataType}
+	}
+	return models.FilterCondition{Column: column, Operator: models.OpEqual, Value: value, Type: dataType}
+}
+
+// ConditionSQL renders an equality or IS NULL condition as SQL with its
+// value inlined, for copying. Numbers and booleans of a column known to be
+// of that type are left bare; everything else is a quoted literal, which
+// Postgres reads as the column's type.
+func ConditionSQL(cond models.FilterCondition) string {
+	column := pgx.Identifier{cond.Column}.Sanitize()
+	switch cond.Operator {
+	case models.OpIsNull, models.OpIsNotNull:
+		return column + " " + string(cond.Operator)
+	}
+	return column + " " + string(cond.Operator) + " " + literal(cond.Value, cond.Type)
+}
+
+// literal renders value as a SQL literal for a column of dataType
+func literal(value interface{}, dataType string) string {
+	s, _ := value.(string)
+	switch {
+	case isNumericType(dataType):
+		if decimalPattern.MatchString(s) {
+			return s
+		}
+	case isBooleanType(dataType):
+		if s == "true" || s == "false" {
+			return s
+		}
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// isNumericType reports whether values of dataType are plain numbers
+func isNumericType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "smallint", "integer", "bigint", "int2", "int4", "int8",
+		"numeric", "decimal", "real", "double precision", "float4", "float8":
+		return true
+	}
+	return false
+}
+
+// isBooleanType reports whether dataType is boolean
+func isBooleanType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "boolean", "bool":
+		return true
+	}
+	return false
+}
+
+// WithCondition returns filter narrowed by cond. The conditions of an AND
+// filter are kept and cond is added; any other filter becomes a group ANDed
+// with cond. A condition on the same column with the same operator is
+// replaced rather than added, so refining by another value of a column
+// doesn't leave an impossible filter.
+func WithCondition(filter *models.Filter, cond models.FilterCondition, schema, table string) models.Filter {
+	result := models.Filter{
+		Schema:    schema,
+		TableName: table,
+		RootGroup: models.FilterGroup{Logic: "AND"},
+	}
+	if filter == nil {
+		result.RootGroup.Conditions = []models.FilterCondition{cond}
+		return result
+	}
+
+	root := filter.RootGroup
+	if root.Logic != "" && root.Logic != "AND" {
+		result.RootGroup.Conditions = []models.FilterCondition{cond}
+		result.RootGroup.Groups = []models.FilterGroup{root}
+		return result
+	}
+
+	result.RootGroup.Groups = root.Groups
+	replaced := false
+	for _, c := range root.Conditions {
+		if c.Column == cond.Column && sameKind(c.Operator, cond.Operator) {
+			if !replaced {
+				result.RootGroup.Conditions = append(result.RootGroup.Conditions, cond)
+				replaced = true
+			}
+			continue
+		}
+		result.RootGroup.Conditions = append(result.RootGroup.Conditions, c)
+	}
+	if !replaced {
+		result.RootGroup.Conditions = append(result.RootGroup.Conditions, cond)
+	}
+	return result
+}
+
+// sameKind reports whether two operators both pin a column to one value:
+// = and IS NULL
+func sameKind(a, b models.FilterOperator) bool {
+	pins := func(op models.FilterOperator) bool { return op == models.OpEqual || op == models.OpIsNull }
+	return pins(a) && pins(b)
+}
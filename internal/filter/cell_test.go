@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+func TestConditionSQL(t *testing.T) {
+	tests := []struct {
+		column, value, dataType string
+		want                    string
+	}{
+		{"id", "42", "integer", `"id" = 42`},
+		{"price", "9.99", "numeric", `"price" = 9.99`},
+		{"ratio", "-1.5e-3", "double precision", `"ratio" = -1.5e-3`},
+		{"ratio", "NaN", "double precision", `"ratio" = 'NaN'`},
+		{"ratio", "Infinity", "real", `"ratio" = 'Infinity'`},
+		{"ratio", "-Infinity", "float8", `"ratio" = '-Infinity'`},
+		{"price", "NaN", "numeric", `"price" = 'NaN'`},
+		{"active", "true", "boolean", `"active" = true`},
+		{"name", "O'Brien", "text", `"name" = 'O''Brien'`},
+		{"zip", "00123", "character varying", `"zip" = '00123'`},
+		{"created_at", "2024-05-01 12:00:00", "timestamp without time zone", `"created_at" = '2024-05-01 12:00:00'`},
+		{"id", "42", "", `"id" = '42'`}, // Unknown type: quoted, which still compares as the column's type
+		{"deleted_at", "NULL", "timestamp with time zone", `"deleted_at" IS NULL`},
+		{`odd"name`, "x", "text", `"odd""name" = 'x'`},
+	}
+	for _, tt := range tests {
+		cond := CellCondition(tt.column, tt.value, tt.dataType)
+		if got := ConditionSQL(cond); got != tt.want {
+			t.Errorf("ConditionSQL(%s = %q as %s) = %s, want %s", tt.column, tt.value, tt.dataType, got, tt.want)
+		}
+	}
+}
+
+func TestWithCondition(t *testing.T) {
+	status := models.FilterCondition{Column: "status", Operator: models.OpEqual, Value: "open"}
+	total := models.FilterCondition{Column: "total", Operator: models.OpGreaterThan, Value: 100}
+
+	// No filter yet
+	got := WithCondition(nil, status, "public", "orders")
+	if got.TableName != "orders" || !reflect.DeepEqual(got.RootGroup.Conditions, []models.FilterCondition{status}) {
+		t.Errorf("WithCondition(nil) = %+v", got)
+	}
+
+	// An AND filter keeps its conditions
+	and := &models.Filter{RootGroup: models.FilterGroup{Logic: "AND", Conditions: []models.FilterCondition{total}}}
+	got = WithCondition(and, status, "public", "orders")
+	if want := []models.FilterCondition{total, status}; !reflect.DeepEqual(got.RootGroup.Conditions, want) {
+		t.Errorf("AND filter conditions = %+v, want %+v", got.RootGroup.Conditions, want)
+	}
+
+	// Another value of the same column replaces the first
+	closed := CellCondition("status", "closed", "text")
+	got = WithCondition(&got, closed, "public", "orders")
+	if want := []models.FilterCondition{total, closed}; !reflect.DeepEqual(got.RootGroup.Conditions, want) {
+		t.Errorf("refined conditions = %+v, want %+v", got.RootGroup.Conditions, want)
+	}
+
+	// An OR filter is kept whole as a group
+	or := &models.Filter{RootGroup: models.FilterGroup{Logic: "OR", Conditions: []models.FilterCondition{total, status}}}
+	got = WithCondition(or, closed, "public", "orders")
+	where, _, err := NewBuilder().BuildWhere(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `WHERE "status" = $1 AND ("total" > $2 OR "status" = $3)`; where != want {
+		t.Errorf("OR filter WHERE = %s, want %s", where, want)
+	}
+}
@@ -0,0 +1,137 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// BuildSelect generates a complete SELECT statement. Unlike BuildWhere, values
+// are inlined as quoted literals so the SQL can be edited and run as-is.
+func (b *Builder) BuildSelect(q models.SelectQuery) (string, error) {
+	if q.Table == "" {
+		return "", fmt.Errorf("table name is required")
+	}
+	if err := b.validateGroup(q.Where); err != nil {
+		return "", err
+	}
+
+	columns := "*"
+	if len(q.Columns) > 0 {
+		quoted := make([]string, len(q.Columns))
+		for i, col := range q.Columns {
+			quoted[i] = QuoteIdentifier(col)
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+
+	table := QuoteIdentifier(q.Table)
+	if q.Schema != "" {
+		table = pgx.Identifier{q.Schema, q.Table}.Sanitize()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("SELECT %s\nFROM %s", columns, table))
+
+	where, err := b.buildLiteralGroup(q.Where)
+	if err != nil {
+		return "", err
+	}
+	if where != "" {
+		sb.WriteString("\nWHERE " + where)
+	}
+
+	if len(q.OrderBy) > 0 {
+		terms := make([]string, len(q.OrderBy))
+		for i, ob := range q.OrderBy {
+			terms[i] = QuoteIdentifier(ob.Column)
+			if ob.Desc {
+				terms[i] += " DESC"
+			}
+		}
+		sb.WriteString("\nORDER BY " + strings.Join(terms, ", "))
+	}
+
+	if q.Limit > 0 {
+		sb.WriteString(fmt.Sprintf("\nLIMIT %d", q.Limit))
+	}
+
+	return sb.String(), nil
+}
+
+// QuoteIdentifier quotes a column or table name, escaping embedded quotes
+func QuoteIdentifier(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+// QuoteLiteral quotes a value as a SQL string literal
+func QuoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// buildLiteralGroup builds a filter group with inlined literal values
+func (b *Builder) buildLiteralGroup(group models.FilterGroup) (string, error) {
+	var clauses []string
+
+	for _, cond := range group.Conditions {
+		clause, err := b.buildLiteralCondition(cond)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, clause)
+	}
+
+	for _, subGroup := range group.Groups {
+		clause, err := b.buildLiteralGroup(subGroup)
+		if err != nil {
+			return "", err
+		}
+		if clause != "" {
+			clauses = append(clauses, "("+clause+")")
+		}
+	}
+
+	logic := group.Logic
+	if logic == "" {
+		logic = "AND"
+	}
+
+	return strings.Join(clauses, " "+logic+" "), nil
+}
+
+// buildLiteralCondition builds a single condition with an inlined literal value
+func (b *Builder) buildLiteralCondition(cond models.FilterCondition) (string, error) {
+	column := QuoteIdentifier(cond.Column)
+	value := QuoteLiteral(fmt.Sprint(cond.Value))
+
+	switch cond.Operator {
+	case models.OpIsNull:
+		return fmt.Sprintf("%s IS NULL", column), nil
+	case models.OpIsNotNull:
+		return fmt.Sprintf("%s IS NOT NULL", column), nil
+	case models.OpEqual, models.OpNotEqual, models.OpGreaterThan, models.OpGreaterOrEqual,
+		models.OpLessThan, models.OpLessOrEqual, models.OpLike, models.OpILike,
+		models.OpHasKey, models.OpArrayOverlap:
+		return fmt.Sprintf("%s %s %s", column, cond.Operator, value), nil
+	case models.OpIn, models.OpNotIn:
+		var items []string
+		for _, item := range strings.Split(fmt.Sprint(cond.Value), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, QuoteLiteral(item))
+			}
+		}
+		if len(items) == 0 {
+			return "", fmt.Errorf("%s requires at least one value", cond.Operator)
+		}
+		return fmt.Sprintf("%s %s (%s)", column, cond.Operator, strings.Join(items, ", ")), nil
+	case models.OpContains, models.OpContainedBy:
+		if strings.Contains(strings.ToLower(cond.Type), "jsonb") {
+			return fmt.Sprintf("%s %s %s::jsonb", column, cond.Operator, value), nil
+		}
+		return fmt.Sprintf("%s %s %s", column, cond.Operator, value), nil
+	default:
+		return "", fmt.Errorf("unsupported operator: %s", cond.Operator)
+	}
+}
@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+func TestBuildSelect(t *testing.T) {
+	b := NewBuilder()
+
+	tests := []struct {
+		name  string
+		query models.SelectQuery
+		want  string
+	}{
+		{
+			name:  "all columns",
+			query: models.SelectQuery{Schema: "public", Table: "users"},
+			want:  `SELECT *` + "\n" + `FROM "public"."users"`,
+		},
+		{
+			name: "columns, where, order and limit",
+			query: models.SelectQuery{
+				Schema:  "public",
+				Table:   "users",
+				Columns: []string{"id", "Email"},
+				Where: models.FilterGroup{
+					Logic: "AND",
+					Conditions: []models.FilterCondition{
+						{Column: "name", Operator: models.OpILike, Value: "o'brien%"},
+						{Column: "deleted_at", Operator: models.OpIsNull},
+					},
+				},
+				OrderBy: []models.OrderByColumn{{Column: "created_at", Desc: true}, {Column: "id"}},
+				Limit:   50,
+			},
+			want: `SELECT "id", "Email"` + "\n" +
+				`FROM "public"."users"` + "\n" +
+				`WHERE "name" ILIKE 'o''brien%' AND "deleted_at" IS NULL` + "\n" +
+				`ORDER BY "created_at" DESC, "id"` + "\n" +
+				`LIMIT 50`,
+		},
+		{
+			name: "quoted identifiers and IN list",
+			query: models.SelectQuery{
+				Schema: "my schema",
+				Table:  `odd"table`,
+				Where: models.FilterGroup{Conditions: []models.FilterCondition{
+					{Column: "status", Operator: models.OpIn, Value: "new, open"},
+				}},
+			},
+			want: `SELECT *` + "\n" +
+				`FROM "my schema"."odd""table"` + "\n" +
+				`WHERE "status" IN ('new', 'open')`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.BuildSelect(tt.query)
+			if err != nil {
+				t.Fatalf("BuildSelect: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildSelect() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSelect_RequiresTable(t *testing.T) {
+	if _, err := NewBuilder().BuildSelect(models.SelectQuery{}); err == nil {
+		t.Error("expected error for missing table")
+	}
+}
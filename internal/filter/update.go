@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ColumnValue is a column's value in a row: Value as the grid shows it,
+// unless Null is set. Type is the column's PostgreSQL type, or "" if
+// unknown.
+type ColumnValue struct {
+	Column string
+	Value  string
+	Type   string
+	Null   bool
+}
+
+// UpdateRowSQL generates an UPDATE of the one row whose key columns hold
+// key, setting the columns in set. Values are inlined like ConditionSQL's,
+// so the statement can be shown and run as-is.
+func UpdateRowSQL(schema, table string, set, key []ColumnValue) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("%s.%s has no primary key, so the row can't be identified", schema, table)
+	}
+	if len(set) == 0 {
+		return "", fmt.Errorf("no columns to update")
+	}
+
+	assignments := make([]string, len(set))
+	for i, v := range set {
+		value := "NULL"
+		if !v.Null {
+			value = literal(v.Value, BaseType(v.Type))
+		}
+		assignments[i] = QuoteIdentifier(v.Column) + " = " + value
+	}
+
+	where, err := KeyConditionSQL(key)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("UPDATE %s\nSET %s\nWHERE %s",
+		pgx.Identifier{schema, table}.Sanitize(),
+		strings.Join(assignments, ", "),
+		where), nil
+}
+
+// KeyConditionSQL returns the condition finding the one row whose key
+// columns, in key order, hold key. A NULL value can't identify a row: it
+// equals nothing, and a unique key allows any number of rows with NULLs.
+func KeyConditionSQL(key []ColumnValue) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("no key columns, so the row can't be identified")
+	}
+	conditions := make([]string, len(key))
+	for i, v := range key {
+		if v.Null {
+			return "", fmt.Errorf("key column %s is NULL, so the row can't be identified", v.Column)
+		}
+		conditions[i] = ConditionSQL(CellCondition(v.Column, v.Value, BaseType(v.Type)))
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// RowsConditionSQL returns the condition finding the rows with the given
+// keys: the one key's condition, or each key's in parentheses ORed
+func RowsConditionSQL(keys [][]ColumnValue) (string, error) {
+	if len(keys) == 0 {
+		return "", fmt.Errorf("no rows")
+	}
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		cond, err := KeyConditionSQL(key)
+		if err != nil {
+			return "", err
+		}
+		if len(keys) > 1 && len(key) > 1 {
+			cond = "(" + cond + ")"
+		}
+		conditions[i] = cond
+	}
+	return strings.Join(conditions, "\n   OR "), nil
+}
+
+// InsertRowsSQL generates an INSERT of rows, which all hold the same
+// columns in the same order. Given the table's key columns, rows whose key
+// is already taken are skipped with ON CONFLICT, so the statement can be
+// run again or against a copy holding some of the rows.
+func InsertRowsSQL(schema, table string, rows [][]ColumnValue, key []string) (string, error) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return "", fmt.Errorf("no rows to insert")
+	}
+
+	columns := make([]string, len(rows[0]))
+	for i, v := range rows[0] {
+		columns[i] = QuoteIdentifier(v.Column)
+	}
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		literals := make([]string, len(row))
+		for j, v := range row {
+			literals[j] = "NULL"
+			if !v.Null {
+				literals[j] = literal(v.Value, BaseType(v.Type))
+			}
+		}
+		values[i] = "  (" + strings.Join(literals, ", ") + ")"
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s)\nVALUES\n%s",
+		pgx.Identifier{schema, table}.Sanitize(),
+		strings.Join(columns, ", "),
+		strings.Join(values, ",\n"))
+	if len(key) > 0 {
+		quoted := make([]string, len(key))
+		for i, column := range key {
+			quoted[i] = QuoteIdentifier(column)
+		}
+		sql += "\nON CONFLICT (" + strings.Join(quoted, ", ") + ") DO NOTHING"
+	}
+	return sql, nil
+}
+
+// BaseType returns dataType without its modifiers, lowercased: "numeric"
+// for "numeric(10,2)"
+func BaseType(dataType string) string {
+	if i := strings.IndexByte(dataType, '('); i >= 0 {
+		dataType = dataType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(dataType))
+}
@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateRowSQL(t *testing.T) {
+	got, err := UpdateRowSQL("public", "users",
+		[]ColumnValue{
+			{Column: "name", Value: "O'Brien", Type: "character varying(100)"},
+			{Column: "age", Value: "42", Type: "integer(32,0)"},
+			{Column: "deleted_at", Type: "timestamp with time zone", Null: true},
+		},
+		[]ColumnValue{
+			{Column: "tenant", Value: "acme", Type: "text"},
+			{Column: "id", Value: "7", Type: "bigint(64,0)"},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `UPDATE "public"."users"
+SET "name" = 'O''Brien', "age" = 42, "deleted_at" = NULL
+WHERE "tenant" = 'acme' AND "id" = 7`
+	if got != want {
+		t.Errorf("UpdateRowSQL() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Bare NaN or inf would be read as a column name
+	got, err = UpdateRowSQL("public", "readings",
+		[]ColumnValue{
+			{Column: "f", Value: "inf", Type: "real"},
+			{Column: "n", Value: "NaN", Type: "numeric(10,2)"},
+			{Column: "e", Value: "1e3", Type: "double precision"},
+		},
+		[]ColumnValue{{Column: "id", Value: "1", Type: "integer(32,0)"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SET "f" = 'inf', "n" = 'NaN', "e" = 1e3`; !strings.Contains(got, want) {
+		t.Errorf("UpdateRowSQL() of non-finite values =\n%s\nwant %s", got, want)
+	}
+
+	tests := []struct {
+		name     string
+		set, key []ColumnValue
+		err      string
+	}{
+		{"no key", []ColumnValue{{Column: "a", Value: "1"}}, nil, "no primary key"},
+		{"nothing to set", nil, []ColumnValue{{Column: "id", Value: "1"}}, "no columns"},
+		{"null key", []ColumnValue{{Column: "a", Value: "1"}}, []ColumnValue{{Column: "id", Null: true}}, "is NULL"},
+	}
+	for _, tt := range tests {
+		if _, err := UpdateRowSQL("public", "users", tt.set, tt.key); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestRowsConditionSQL(t *testing.T) {
+	key := func(tenant, id string) []ColumnValue {
+		return []ColumnValue{{Column: "tenant", Value: tenant, Type: "text"}, {Column: "id", Value: id, Type: "bigint(64,0)"}}
+	}
+
+	got, err := RowsConditionSQL([][]ColumnValue{key("acme", "7")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"tenant" = 'acme' AND "id" = 7`; got != want {
+		t.Errorf("one row: got %s, want %s", got, want)
+	}
+
+	got, err = RowsConditionSQL([][]ColumnValue{key("acme", "7"), key("globex", "8")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(\"tenant\" = 'acme' AND \"id\" = 7)\n   OR (\"tenant\" = 'globex' AND \"id\" = 8)"; got != want {
+		t.Errorf("two rows: got\n%s\nwant\n%s", got, want)
+	}
+
+	// A float key holding NaN or Infinity is quoted, not read as a column
+	floatKey := func(v string) []ColumnValue {
+		return []ColumnValue{{Column: "reading", Value: v, Type: "double precision"}}
+	}
+	got, err = RowsConditionSQL([][]ColumnValue{floatKey("NaN"), floatKey("-Infinity")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\"reading\" = 'NaN'\n   OR \"reading\" = '-Infinity'"; got != want {
+		t.Errorf("non-finite keys: got\n%s\nwant\n%s", got, want)
+	}
+	if got, _ := KeyConditionSQL(floatKey("Infinity")); got != `"reading" = 'Infinity'` {
+		t.Errorf("KeyConditionSQL(Infinity) = %s", got)
+	}
+
+	// A NULL in a nullable unique key matches no row, and several rows may
+	// hold it, so it can't stand in for a key
+	nullKey := []ColumnValue{{Column: "email", Null: true}}
+	if _, err := RowsConditionSQL([][]ColumnValue{key("acme", "7"), nullKey}); err == nil || !strings.Contains(err.Error(), "is NULL") {
+		t.Errorf("NULL key value: error = %v, want one naming the NULL", err)
+	}
+}
+
+func TestInsertRowsSQL(t *testing.T) {
+	rows := [][]ColumnValue{
+		{{Column: "tenant", Value: "acme", Type: "text"}, {Column: "id", Value: "7", Type: "bigint(64,0)"}, {Column: "note", Value: "it's", Type: "text"}},
+		{{Column: "tenant", Value: "acme", Type: "text"}, {Column: "id", Value: "8", Type: "bigint(64,0)"}, {Column: "note", Null: true, Type: "text"}},
+	}
+	got, err := InsertRowsSQL("public", "orders", rows, []string{"tenant", "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `INSERT INTO "public"."orders" ("tenant", "id", "note")
+VALUES
+  ('acme', 7, 'it''s'),
+  ('acme', 8, NULL)
+ON CONFLICT ("tenant", "id") DO NOTHING`
+	if got != want {
+		t.Errorf("InsertRowsSQL() =\n%s\nwant\n%s", got, want)
+	}
+
+	floats := [][]ColumnValue{
+		{{Column: "id", Value: "1", Type: "integer(32,0)"}, {Column: "reading", Value: "NaN", Type: "real"}},
+		{{Column: "id", Value: "2", Type: "integer(32,0)"}, {Column: "reading", Value: "Infinity", Type: "real"}},
+	}
+	got, err = InsertRowsSQL("public", "readings", floats, []string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "  (1, 'NaN'),\n  (2, 'Infinity')\n"; !strings.Contains(got, want) {
+		t.Errorf("InsertRowsSQL() of non-finite values =\n%s\nwant rows\n%s", got, want)
+	}
+
+	got, err = InsertRowsSQL("public", "log", rows[:1], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "ON CONFLICT") {
+		t.Errorf("a table without a key got ON CONFLICT:\n%s", got)
+	}
+	if _, err := InsertRowsSQL("public", "log", nil, nil); err == nil {
+		t.Error("InsertRowsSQL() of no rows succeeded")
+	}
+}
+
+func TestBaseType(t *testing.T) {
+	for dataType, want := range map[string]string{
+		"numeric(10,2)":               "numeric",
+		"integer(32,0)":               "integer",
+		"character varying(255)":      "character varying",
+		"timestamp without time zone": "timestamp without time zone",
+		"BOOLEAN":                     "boolean",
+	} {
+		if got := BaseType(dataType); got != want {
+			t.Errorf("BaseType(%q) = %q, want %q", dataType, got, want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package history
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxStatsDurations caps the run durations kept for a query's sparkline
+const maxStatsDurations = 12
+
+// QueryStats sums up every run of one query
+type QueryStats struct {
+	Latest   HistoryEntry // The most recent run; its text is the one shown
+	Runs     int
+	Failures int
+
+	// Durations of the successful runs; zero when none succeeded
+	MinDuration time.Duration
+	AvgDuration time.Duration
+	MaxDuration time.Duration
+	// Durations holds the last successful runs' durations, oldest first
+	Durations []time.Duration
+
+	LastError   string // Error of the most recent failed run
+	LastErrorAt time.Time
+}
+
+// NormalizeQuery returns the form of a query that runs are grouped by:
+// its words separated by single spaces, so reindenting or rewrapping a
+// query doesn't count it as another one. Whitespace inside string
+// literals, quoted identifiers and dollar-quoted strings is kept, as it
+// changes what the query does.
+func NormalizeQuery(sql string) string {
+	var b strings.Builder
+	space := false // Whitespace since the last word
+	for i := 0; i < len(sql); {
+		r, size := utf8.DecodeRuneInString(sql[i:])
+		if unicode.IsSpace(r) {
+			space = true
+			i += size
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		end := i + size
+		if quoted := quotedEnd(sql, i); quoted > i {
+			end = quoted
+		}
+		b.WriteString(sql[i:end])
+		i = end
+	}
+	return b.String()
+}
+
+// quotedEnd returns the end of the string literal, quoted identifier or
+// dollar-quoted string starting at i, or i if none starts there. One left
+// open runs to the end of sql.
+func quotedEnd(sql string, i int) int {
+	switch sql[i] {
+	case '\'', '"':
+		quote := sql[i]
+		for j := i + 1; j < len(sql); j++ {
+			if sql[j] != quote {
+				continue
+			}
+			// Doubled quotes are escapes
+			if j+1 < len(sql) && sql[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+		return len(sql)
+	case '$':
+		// $$...$$ or $tag$...$tag$
+		tagEnd := strings.IndexByte(sql[i+1:], '$')
+		if tagEnd < 0 || !isDollarTag(sql[i+1:i+1+tagEnd]) {
+			return i
+		}
+		tag := sql[i : i+2+tagEnd]
+		if j := strings.Index(sql[i+len(tag):], tag); j >= 0 {
+			return i + len(tag) + j + len(tag)
+		}
+		return len(sql)
+	}
+	return i
+}
+
+// isDollarTag reports whether tag can name a dollar quote: empty, or an
+// identifier that doesn't start with a digit (so $1 stays a parameter)
+func isDollarTag(tag string) bool {
+	for i, r := range tag {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Aggregate groups history entries, newest first, by their normalized
+// query. The groups are ordered by their most recent run.
+func Aggregate(entries []HistoryEntry) []QueryStats {
+	var stats []QueryStats
+	index := make(map[string]int)
+	var totals []time.Duration
+	for _, e := range entries {
+		key := NormalizeQuery(e.Query)
+		i, ok := index[key]
+		if !ok {
+			i = len(stats)
+			index[key] = i
+			stats = append(stats, QueryStats{Latest: e})
+			totals = append(totals, 0)
+		}
+		s := &stats[i]
+		s.Runs++
+		if !e.Success {
+			s.Failures++
+			if s.LastError == "" {
+				s.LastError = e.ErrorMessage
+				s.LastErrorAt = e.ExecutedAt
+			}
+			continue
+		}
+		if len(s.Durations) == 0 || e.Duration < s.MinDuration {
+			s.MinDuration = e.Duration
+		}
+		if e.Duration > s.MaxDuration {
+			s.MaxDuration = e.Duration
+		}
+		totals[i] += e.Duration
+		// Entries come newest first; keep the newest runs
+		s.Durations = append(s.Durations, e.Duration)
+	}
+
+	for i := range stats {
+		s := &stats[i]
+		if n := len(s.Durations); n > 0 {
+			s.AvgDuration = totals[i] / time.Duration(n)
+		}
+		if len(s.Durations) > maxStatsDurations {
+			s.Durations = s.Durations[:maxStatsDurations]
+		}
+		// Oldest first, for reading a sparkline left to right
+		for l, r := 0, len(s.Durations)-1; l < r; l, r = l+1, r-1 {
+			s.Durations[l], s.Durations[r] = s.Durations[r], s.Durations[l]
+		}
+	}
+	return stats
+}
+
+// RecentStats returns the stats of the queries among the last limit runs,
+// the most recently run first
+func (s *Store) RecentStats(limit int) ([]QueryStats, error) {
+	entries, err := s.GetRecent(limit)
+	if err != nil {
+		return nil, err
+	}
+	return Aggregate(entries), nil
+}
@@ -0,0 +1,67 @@
+package history
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		sql, want string
+	}{
+		{"  SELECT *\n\tFROM  users\n", "SELECT * FROM users"},
+		// Quoted text keeps its whitespace
+		{"SELECT  'a  b'\nFROM t", "SELECT 'a  b' FROM t"},
+		{"SELECT 'it''s  here',  \"my  col\" FROM t", "SELECT 'it''s  here', \"my  col\" FROM t"},
+		{"SELECT $$a\n  b$$,\n $fn$ x  $fn$", "SELECT $$a\n  b$$, $fn$ x  $fn$"},
+		// $1 is a parameter, not a dollar quote
+		{"SELECT  $1,  $2", "SELECT $1, $2"},
+		{"SELECT 'open  quote", "SELECT 'open  quote"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeQuery(tt.sql); got != tt.want {
+			t.Errorf("NormalizeQuery(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+
+	if NormalizeQuery("SELECT 'a b'") == NormalizeQuery("SELECT 'a  b'") {
+		t.Error("queries differing inside a string literal should not be grouped")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	at := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	ms := time.Millisecond
+	// Newest first, as GetRecent returns them
+	entries := []HistoryEntry{
+		{ID: 5, Query: "SELECT *\nFROM users", Duration: 30 * ms, Success: true, ExecutedAt: at.Add(5 * time.Minute)},
+		{ID: 4, Query: "DELETE FROM carts", Success: false, ErrorMessage: "permission denied", ExecutedAt: at.Add(4 * time.Minute)},
+		{ID: 3, Query: "SELECT * FROM users", Success: false, ErrorMessage: "timeout", ExecutedAt: at.Add(3 * time.Minute)},
+		{ID: 2, Query: "SELECT *  FROM users", Duration: 10 * ms, Success: true, ExecutedAt: at.Add(2 * time.Minute)},
+		{ID: 1, Query: "SELECT * FROM users;", Duration: 50 * ms, Success: true, ExecutedAt: at.Add(time.Minute)},
+	}
+
+	stats := Aggregate(entries)
+	if len(stats) != 3 {
+		t.Fatalf("Aggregate() returned %d groups, want 3 (a trailing semicolon is a different query)", len(stats))
+	}
+
+	users := stats[0]
+	if users.Latest.ID != 5 || users.Runs != 3 || users.Failures != 1 {
+		t.Errorf("users stats = latest %d, %d runs, %d failures, want 5, 3, 1", users.Latest.ID, users.Runs, users.Failures)
+	}
+	if users.MinDuration != 10*ms || users.AvgDuration != 20*ms || users.MaxDuration != 30*ms {
+		t.Errorf("users durations = %v/%v/%v, want 10ms/20ms/30ms", users.MinDuration, users.AvgDuration, users.MaxDuration)
+	}
+	if !slices.Equal(users.Durations, []time.Duration{10 * ms, 30 * ms}) {
+		t.Errorf("users Durations = %v, want the successful runs oldest first", users.Durations)
+	}
+	if users.LastError != "timeout" || !users.LastErrorAt.Equal(at.Add(3*time.Minute)) {
+		t.Errorf("users last error = %q at %v, want timeout", users.LastError, users.LastErrorAt)
+	}
+
+	if carts := stats[1]; carts.Runs != 1 || carts.MaxDuration != 0 || carts.Durations != nil {
+		t.Errorf("carts stats = %+v, want one failed run without durations", carts)
+	}
+}
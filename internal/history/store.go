@@ -98,7 +98,7 @@ func (s *Store) GetRecent(limit int) ([]HistoryEntry, error) {
 		}
 
 		e.Duration = time.Duration(durationMs) * time.Millisecond
-		e.ExecutedAt, _ = time.Parse("2006-01-02 15:04:05", executedAt)
+		e.ExecutedAt = parseExecutedAt(executedAt)
 
 		entries = append(entries, e)
 	}
@@ -142,7 +142,7 @@ func (s *Store) Search(query string, limit int) ([]HistoryEntry, error) {
 		}
 
 		e.Duration = time.Duration(durationMs) * time.Millisecond
-		e.ExecutedAt, _ = time.Parse("2006-01-02 15:04:05", executedAt)
+		e.ExecutedAt = parseExecutedAt(executedAt)
 
 		entries = append(entries, e)
 	}
@@ -150,6 +150,18 @@ func (s *Store) Search(query string, limit int) ([]HistoryEntry, error) {
 	return entries, nil
 }
 
+// parseExecutedAt reads an executed_at value. SQLite stores
+// CURRENT_TIMESTAMP as UTC text, which the driver hands back as RFC 3339
+// for a TIMESTAMP column.
+func parseExecutedAt(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // Close closes the database connection
 func (s *Store) Close() error {
 	if s.db != nil {
@@ -0,0 +1,49 @@
+package models
+
+// ColumnKind classifies a result column by its PostgreSQL type so the data
+// grid can render values of that type specially
+type ColumnKind int
+
+const (
+	ColumnKindPlain       ColumnKind = iota // No special rendering
+	ColumnKindUUID                          // uuid
+	ColumnKindTimestamp                     // timestamp without time zone
+	ColumnKindTimestampTZ                   // timestamp with time zone
+	ColumnKindEnum                          // Any enum type
+	ColumnKindJSON                          // json or jsonb
+	ColumnKindBool                          // boolean
+)
+
+// Built-in type OIDs (see pg_type.dat); these are fixed across servers
+const (
+	boolOID        = 16
+	uuidOID        = 2950
+	timestampOID   = 1114
+	timestamptzOID = 1184
+	jsonOID        = 114
+	jsonbOID       = 3802
+)
+
+// ColumnKindForOID returns the kind of a built-in type. Enums are
+// user-defined and need a catalog lookup, so they come back as plain.
+func ColumnKindForOID(oid uint32) ColumnKind {
+	switch oid {
+	case boolOID:
+		return ColumnKindBool
+	case uuidOID:
+		return ColumnKindUUID
+	case timestampOID:
+		return ColumnKindTimestamp
+	case timestamptzOID:
+		return ColumnKindTimestampTZ
+	case jsonOID, jsonbOID:
+		return ColumnKindJSON
+	default:
+		return ColumnKindPlain
+	}
+}
+
+// IsTimestamp reports whether the kind is timestamp or timestamptz
+func (k ColumnKind) IsTimestamp() bool {
+	return k == ColumnKindTimestamp || k == ColumnKindTimestampTZ
+}
@@ -20,6 +20,8 @@ type Command struct {
 	Description string
 	Icon        string
 	Tags        []string
-	Score       int // For ranking in search results
+	SearchText  string // Also matched by search but not shown, e.g. the full SQL
+	Detail      string // Shown below the results while selected, e.g. a query's run stats
+	Score       int    // For ranking in search results
 	Action      func() tea.Msg
 }
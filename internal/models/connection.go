@@ -1,9 +1,16 @@
 package models
 
 import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// MaskedPassword stands in for the password in masked connection URLs
+const MaskedPassword = "********"
+
 // ConnectionConfig represents a PostgreSQL connection configuration
 type ConnectionConfig struct {
 	Name     string `yaml:"name"`
@@ -13,6 +20,67 @@ type ConnectionConfig struct {
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
 	SSLMode  string `yaml:"ssl_mode"`
+
+	// ApplicationName is reported to the server as application_name
+	// (visible in pg_stat_activity). Empty means the driver default.
+	ApplicationName string `yaml:"application_name,omitempty"`
+
+	// OnConnectSQL runs on every new connection in the pool, e.g. to SET
+	// timezone or a role. A failure is reported but keeps the connection.
+	OnConnectSQL string `yaml:"on_connect_sql,omitempty"`
+}
+
+// URL returns the config as a postgres:// connection URL. The password is
+// included only when showPassword is set; otherwise it is replaced with
+// MaskedPassword so the URL can be shared without leaking it.
+func (c ConnectionConfig) URL(showPassword bool) string {
+	u := url.URL{Scheme: "postgres", Path: "/" + c.Database}
+	query := url.Values{}
+
+	masked := c.Password != "" && !showPassword
+	if c.User != "" {
+		if c.Password != "" && showPassword {
+			u.User = url.UserPassword(c.User, c.Password)
+		} else {
+			u.User = url.User(c.User)
+		}
+	}
+
+	// Unix socket directories can't go in the authority, so libpq takes
+	// them as a host parameter instead
+	if strings.HasPrefix(c.Host, "/") {
+		query.Set("host", c.Host)
+		if c.Port != 0 {
+			query.Set("port", strconv.Itoa(c.Port))
+		}
+	} else if c.Port != 0 {
+		u.Host = net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	} else {
+		u.Host = c.Host
+	}
+
+	if c.SSLMode != "" {
+		query.Set("sslmode", c.SSLMode)
+	}
+	if c.ApplicationName != "" {
+		query.Set("application_name", c.ApplicationName)
+	}
+	u.RawQuery = query.Encode()
+
+	s := u.String()
+	if masked && c.User != "" {
+		// Spliced in by hand since url.URL would percent-encode the mask
+		s = strings.Replace(s, "@", ":"+MaskedPassword+"@", 1)
+	}
+	return s
+}
+
+// PassfileEntry returns a .pgpass line that gives the config's password to
+// any server, for a passfile made for one connection. Colons and
+// backslashes in the password are escaped as libpq expects.
+func (c ConnectionConfig) PassfileEntry() string {
+	password := strings.NewReplacer(`\`, `\\`, ":", `\:`).Replace(c.Password)
+	return "*:*:*:*:" + password + "\n"
 }
 
 // Connection represents an active database connection
@@ -23,6 +91,7 @@ type Connection struct {
 	ConnectedAt time.Time
 	LastPing    time.Time
 	Error       error
+	Superuser   bool // The user is a superuser; false when the check failed
 }
 
 // ConnectionState represents the current connection state
@@ -88,6 +157,7 @@ type ConnectionHistoryEntry struct {
 	LastUsed    time.Time `yaml:"last_used"`
 	UsageCount  int       `yaml:"usage_count"`
 	CreatedAt   time.Time `yaml:"created_at"`
+	Pinned      bool      `yaml:"pinned,omitempty"` // Listed first in the connection dialog
 }
 
 // ToConnectionConfig converts a history entry to a ConnectionConfig (without password)
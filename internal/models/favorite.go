@@ -1,18 +1,84 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
-// Favorite represents a saved query
+// Favorite represents a saved query, a bookmarked database object when
+// Object is set, or a saved table view when View is set
 type Favorite struct {
-	ID          string    `yaml:"id"`
-	Name        string    `yaml:"name"`
-	Description string    `yaml:"description"`
-	Query       string    `yaml:"query"`
-	Tags        []string  `yaml:"tags"`
-	Connection  string    `yaml:"connection"`  // Connection name
-	Database    string    `yaml:"database"`    // Database name
-	CreatedAt   time.Time `yaml:"created_at"`
-	UpdatedAt   time.Time `yaml:"updated_at"`
-	UsageCount  int       `yaml:"usage_count"`
-	LastUsed    time.Time `yaml:"last_used"`
+	ID          string          `yaml:"id"`
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description"`
+	Query       string          `yaml:"query"`
+	Object      *FavoriteObject `yaml:"object,omitempty"` // Bookmarked object (nil for queries)
+	View        *FavoriteView   `yaml:"view,omitempty"`   // Saved table view (nil for queries)
+	Tags        []string        `yaml:"tags"`
+	Connection  string          `yaml:"connection"` // Connection name
+	Database    string          `yaml:"database"`   // Database name
+	CreatedAt   time.Time       `yaml:"created_at"`
+	UpdatedAt   time.Time       `yaml:"updated_at"`
+	UsageCount  int             `yaml:"usage_count"`
+	LastUsed    time.Time       `yaml:"last_used"`
+}
+
+// FavoriteObject identifies a bookmarked object in the navigation tree
+type FavoriteObject struct {
+	Schema string       `yaml:"schema"` // Empty for objects outside a schema (extensions)
+	Type   TreeNodeType `yaml:"type"`
+	Name   string       `yaml:"name"` // Tree label, e.g. "users" or "add(integer, integer)"
+}
+
+// QualifiedName returns the object name prefixed with its schema
+func (o FavoriteObject) QualifiedName() string {
+	if o.Schema == "" {
+		return o.Name
+	}
+	return o.Schema + "." + o.Name
+}
+
+// FavoriteView is a table grid saved with its filter and sort, so opening it
+// reproduces the same rows in the same order
+type FavoriteView struct {
+	Schema     string  `yaml:"schema"`
+	Table      string  `yaml:"table"`
+	Filter     *Filter `yaml:"filter,omitempty"`
+	SortColumn string  `yaml:"sort_column,omitempty"`
+	SortDir    string  `yaml:"sort_dir,omitempty"` // "ASC" or "DESC"
+	NullsFirst bool    `yaml:"nulls_first,omitempty"`
+}
+
+// QualifiedName returns the table name prefixed with its schema
+func (v FavoriteView) QualifiedName() string {
+	return v.Schema + "." + v.Table
+}
+
+// Describe summarizes the view, e.g. "public.orders, 2 filters, sorted by id DESC"
+func (v FavoriteView) Describe() string {
+	s := v.QualifiedName()
+	if n := v.Filter.ConditionCount(); n == 1 {
+		s += ", 1 filter"
+	} else if n > 1 {
+		s += fmt.Sprintf(", %d filters", n)
+	}
+	if v.SortColumn != "" {
+		s += fmt.Sprintf(", sorted by %s %s", v.SortColumn, v.SortDir)
+	}
+	return s
+}
+
+// IsBookmark reports whether the favorite bookmarks an object rather than a query
+func (f Favorite) IsBookmark() bool {
+	return f.Object != nil
+}
+
+// IsView reports whether the favorite is a saved table view rather than a query
+func (f Favorite) IsView() bool {
+	return f.View != nil
+}
+
+// IsQuery reports whether the favorite runs a saved query
+func (f Favorite) IsQuery() bool {
+	return !f.IsBookmark() && !f.IsView()
 }
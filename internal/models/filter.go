@@ -24,22 +24,39 @@ const (
 
 // FilterCondition represents a single filter condition
 type FilterCondition struct {
-	Column   string
-	Operator FilterOperator
-	Value    interface{}
-	Type     string // PostgreSQL type (text, integer, jsonb, etc.)
+	Column   string         `yaml:"column"`
+	Operator FilterOperator `yaml:"operator"`
+	Value    interface{}    `yaml:"value"`
+	Type     string         `yaml:"type,omitempty"` // PostgreSQL type (text, integer, jsonb, etc.)
 }
 
 // FilterGroup represents a group of conditions with AND/OR logic
 type FilterGroup struct {
-	Conditions []FilterCondition
-	Logic      string // "AND" or "OR"
-	Groups     []FilterGroup
+	Conditions []FilterCondition `yaml:"conditions,omitempty"`
+	Logic      string            `yaml:"logic,omitempty"` // "AND" or "OR"
+	Groups     []FilterGroup     `yaml:"groups,omitempty"`
 }
 
 // Filter represents the complete filter state
 type Filter struct {
-	RootGroup FilterGroup
-	TableName string
-	Schema    string
+	RootGroup FilterGroup `yaml:"root_group"`
+	TableName string      `yaml:"table_name,omitempty"`
+	Schema    string      `yaml:"schema,omitempty"`
+}
+
+// ConditionCount returns the number of conditions in the filter, including
+// nested groups. A nil filter has none.
+func (f *Filter) ConditionCount() int {
+	if f == nil {
+		return 0
+	}
+	return f.RootGroup.conditionCount()
+}
+
+func (g FilterGroup) conditionCount() int {
+	n := len(g.Conditions)
+	for _, sub := range g.Groups {
+		n += sub.conditionCount()
+	}
+	return n
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// BlockingLock is one blocker→blocked pair: a backend waiting for a lock
+// held (or queued ahead of it) by another backend
+type BlockingLock struct {
+	BlockedPID   int
+	BlockedUser  string
+	BlockedQuery string
+	Waiting      time.Duration // Since the blocked query started
+
+	BlockingPID   int
+	BlockingUser  string
+	BlockingQuery string
+	BlockingState string // e.g. "idle in transaction"
+
+	Relation string // Relation the blocked backend waits on, if any
+	LockType string // e.g. "relation", "transactionid", "tuple"
+	LockMode string // Mode the blocked backend asked for
+}
@@ -1,5 +1,7 @@
 package models
 
+import "slices"
+
 // AppState holds the application state
 type AppState struct {
 	Width          int
@@ -71,6 +73,34 @@ type ColumnDetail struct {
 	IsUnique      bool
 	HasCheck      bool
 	Comment       string
+	Identity      string // "a" for GENERATED ALWAYS AS IDENTITY, "d" for BY DEFAULT, "" otherwise
+	IsGenerated   bool   // GENERATED ALWAYS AS (...) STORED
+	Position      int    // 1-based place among the table's columns, as SELECT * lists them
+	AttNum        int    // pg_attribute.attnum: storage order; dropped columns leave gaps
+	StorageLength int    // attlen: bytes per value, -1 for varlena, -2 for cstring
+	StorageAlign  string // attalign: "c", "s", "i" or "d" (1, 2, 4 or 8 bytes)
+	KeyPosition   int    // 1-based place in the primary key, 0 if not part of it
+
+	// The sequence the column takes its values from: the one nextval() reads
+	// in its default, or the one a serial or identity column owns
+	SequenceSchema string
+	Sequence       string
+}
+
+// PrimaryKey returns the primary key columns among columns in key order,
+// which differs from table order when the key was declared in another
+// order, or nil if the table has no primary key
+func PrimaryKey(columns []ColumnDetail) []ColumnDetail {
+	var key []ColumnDetail
+	for _, col := range columns {
+		if col.IsPrimaryKey {
+			key = append(key, col)
+		}
+	}
+	slices.SortStableFunc(key, func(a, b ColumnDetail) int {
+		return a.KeyPosition - b.KeyPosition
+	})
+	return key
 }
 
 // Constraint represents a table constraint
@@ -83,6 +113,18 @@ type Constraint struct {
 	ForeignCols  []string
 }
 
+// ForeignKey is a foreign key from the columns of one table to those of the
+// table it references, in key order
+type ForeignKey struct {
+	Name       string
+	Schema     string
+	Table      string
+	Columns    []string
+	RefSchema  string
+	RefTable   string
+	RefColumns []string
+}
+
 // IndexInfo represents an index
 type IndexInfo struct {
 	Name        string
@@ -95,3 +137,10 @@ type IndexInfo struct {
 	Size        int64
 	Predicate   string // WHERE clause for partial indexes
 }
+
+// RowWindow is an explicit LIMIT and OFFSET a table tab's rows were loaded
+// with, in place of loading more rows as the grid is scrolled
+type RowWindow struct {
+	Offset int
+	Limit  int
+}
@@ -0,0 +1,17 @@
+package models
+
+// SelectQuery describes a simple SELECT built by the visual query builder
+type SelectQuery struct {
+	Schema  string
+	Table   string
+	Columns []string // Empty selects all columns
+	Where   FilterGroup
+	OrderBy []OrderByColumn
+	Limit   int // 0 means no limit
+}
+
+// OrderByColumn is a single ORDER BY term
+type OrderByColumn struct {
+	Column string
+	Desc   bool
+}
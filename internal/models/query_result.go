@@ -5,8 +5,30 @@ import "time"
 // QueryResult represents the result of a SQL query execution
 type QueryResult struct {
 	Columns      []string
+	ColumnKinds  []ColumnKind // Parallel to Columns, for type-aware rendering
 	Rows         [][]string
 	RowsAffected int64
 	Duration     time.Duration
 	Error        error
+
+	// Notices raised while the query ran, e.g. by RAISE NOTICE. They are
+	// informational and never make the query fail.
+	Notices []QueryNotice
+
+	// Statements holds the command tag of each statement when a script of
+	// several statements ran, e.g. "INSERT 0 3". Empty for one statement.
+	Statements []string
+
+	// CommandTag is the command tag of the statement whose result is shown,
+	// e.g. "SET", "DO" or "UPDATE 3"
+	CommandTag string
+}
+
+// QueryNotice is a notice or warning the server sent while a query ran
+type QueryNotice struct {
+	Statement int    // 1-based statement of a script that raised it, 0 for a single statement
+	Severity  string // NOTICE, WARNING, INFO, ...
+	Message   string
+	Detail    string
+	Hint      string
 }
@@ -0,0 +1,49 @@
+package models
+
+// RecentObjects is a most-recently-used list of tree node IDs
+type RecentObjects struct {
+	ids []string
+	max int
+}
+
+// NewRecentObjects creates an MRU list holding at most max entries
+func NewRecentObjects(max int) *RecentObjects {
+	if max < 1 {
+		max = 1
+	}
+	return &RecentObjects{max: max}
+}
+
+// Add moves id to the front of the list, dropping the oldest entry if full
+func (r *RecentObjects) Add(id string) {
+	if id == "" {
+		return
+	}
+	ids := make([]string, 0, len(r.ids)+1)
+	ids = append(ids, id)
+	for _, existing := range r.ids {
+		if existing != id {
+			ids = append(ids, existing)
+		}
+	}
+	if len(ids) > r.max {
+		ids = ids[:r.max]
+	}
+	r.ids = ids
+}
+
+// IDs returns the node IDs, most recent first
+func (r *RecentObjects) IDs() []string {
+	return r.ids
+}
+
+// Prune drops entries for which exists returns false
+func (r *RecentObjects) Prune(exists func(id string) bool) {
+	kept := r.ids[:0]
+	for _, id := range r.ids {
+		if exists(id) {
+			kept = append(kept, id)
+		}
+	}
+	r.ids = kept
+}
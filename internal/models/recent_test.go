@@ -0,0 +1,34 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecentObjects_AddDeduplicatesAndCaps(t *testing.T) {
+	r := NewRecentObjects(3)
+	r.Add("table:db.public.a")
+	r.Add("table:db.public.b")
+	r.Add("table:db.public.a")
+	r.Add("table:db.public.c")
+	r.Add("table:db.public.d")
+
+	want := []string{"table:db.public.d", "table:db.public.c", "table:db.public.a"}
+	if got := r.IDs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+}
+
+func TestRecentObjects_Prune(t *testing.T) {
+	r := NewRecentObjects(5)
+	r.Add("table:db.public.a")
+	r.Add("table:db.public.gone")
+	r.Add("view:db.public.v")
+
+	r.Prune(func(id string) bool { return id != "table:db.public.gone" })
+
+	want := []string{"view:db.public.v", "table:db.public.a"}
+	if got := r.IDs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+}
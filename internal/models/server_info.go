@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ServerInfo describes the server a connection is on and its session
+type ServerInfo struct {
+	Version       string // Full version() string
+	ServerVersion string // e.g. "16.2"
+
+	User        string // current_user, which SET ROLE changes
+	SessionUser string
+	Database    string
+
+	ServerEncoding string
+	ClientEncoding string
+	TimeZone       string
+
+	StartedAt  time.Time // When the server started, zero if unknown
+	InRecovery bool      // A standby replaying WAL
+}
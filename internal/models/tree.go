@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -20,6 +21,7 @@ const (
 
 	// New group types
 	TreeNodeTypeMaterializedViewGroup TreeNodeType = "materialized_view_group"
+	TreeNodeTypeForeignTableGroup     TreeNodeType = "foreign_table_group"
 	TreeNodeTypeFunctionGroup         TreeNodeType = "function_group"
 	TreeNodeTypeProcedureGroup        TreeNodeType = "procedure_group"
 	TreeNodeTypeTriggerFunctionGroup  TreeNodeType = "trigger_function_group"
@@ -37,6 +39,7 @@ const (
 
 	// New leaf node types
 	TreeNodeTypeMaterializedView TreeNodeType = "materialized_view"
+	TreeNodeTypeForeignTable     TreeNodeType = "foreign_table" // Rows come from a foreign server
 	TreeNodeTypeFunction         TreeNodeType = "function"
 	TreeNodeTypeProcedure        TreeNodeType = "procedure"
 	TreeNodeTypeTriggerFunction  TreeNodeType = "trigger_function"
@@ -48,6 +51,9 @@ const (
 	TreeNodeTypeEnumType         TreeNodeType = "enum_type"
 	TreeNodeTypeDomainType       TreeNodeType = "domain_type"
 	TreeNodeTypeRangeType        TreeNodeType = "range_type"
+
+	// Display-only children
+	TreeNodeTypeEnumValue TreeNodeType = "enum_value" // A label of an enum type
 )
 
 // TreeNode represents a node in the navigation tree
@@ -61,6 +67,8 @@ type TreeNode struct {
 	Selectable bool          // Whether node can be selected
 	Metadata   interface{}   // Type-specific metadata (table info, column types, etc.)
 	Loaded     bool          // Whether children have been loaded (for lazy loading)
+
+	hiddenChildren []*TreeNode // Children detached from the tree by a filter
 }
 
 // NewTreeNode creates a new tree node
@@ -96,7 +104,7 @@ func (n *TreeNode) Toggle() {
 		TreeNodeTypeTrigger,
 		TreeNodeTypeExtension,
 		TreeNodeTypeCompositeType,
-		TreeNodeTypeEnumType,
+		TreeNodeTypeEnumValue,
 		TreeNodeTypeDomainType,
 		TreeNodeTypeRangeType:
 		return
@@ -151,6 +159,80 @@ func (n *TreeNode) FindByID(id string) *TreeNode {
 	return nil
 }
 
+// IsReopenableObject reports whether nodes of this type are loaded with the
+// tree and can be found again by schema, type and name. Indexes and triggers
+// are lazy-loaded, so they would not survive a tree refresh.
+func IsReopenableObject(nodeType TreeNodeType) bool {
+	switch nodeType {
+	case TreeNodeTypeTable, TreeNodeTypeView, TreeNodeTypeMaterializedView, TreeNodeTypeForeignTable,
+		TreeNodeTypeFunction, TreeNodeTypeProcedure, TreeNodeTypeTriggerFunction,
+		TreeNodeTypeSequence, TreeNodeTypeExtension, TreeNodeTypeCompositeType,
+		TreeNodeTypeEnumType, TreeNodeTypeDomainType, TreeNodeTypeRangeType:
+		return true
+	}
+	return false
+}
+
+// FindObject finds a node by type and label within a schema (depth-first
+// search). An empty schema matches objects outside any schema.
+func (n *TreeNode) FindObject(schema string, nodeType TreeNodeType, label string) *TreeNode {
+	if n.Type == nodeType && n.Label == label && GetSchemaFromNode(n) == schema {
+		return n
+	}
+
+	for _, child := range n.Children {
+		if found := child.FindObject(schema, nodeType, label); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// ObjectName returns the bare object name of a node: the label without the
+// argument list that function and procedure labels carry
+func (n *TreeNode) ObjectName() string {
+	if n.Type == TreeNodeTypeFunction || n.Type == TreeNodeTypeProcedure {
+		if i := strings.Index(n.Label, "("); i >= 0 {
+			return n.Label[:i]
+		}
+	}
+	return n.Label
+}
+
+// ResolveObject finds the schema object a SQL name refers to. A qualified
+// name is looked up in its own schema; an unqualified one in each
+// searchPath schema in turn, the way PostgreSQL resolves it. Returns nil
+// when no loaded object matches.
+func (n *TreeNode) ResolveObject(schema, name string, searchPath []string) *TreeNode {
+	schemas := searchPath
+	if schema != "" {
+		schemas = []string{schema}
+	}
+
+	// Index schema-level objects by schema, first match wins
+	found := make(map[string]*TreeNode)
+	var walk func(node *TreeNode)
+	walk = func(node *TreeNode) {
+		if IsReopenableObject(node.Type) && node.Type != TreeNodeTypeExtension && node.ObjectName() == name {
+			if s := GetSchemaFromNode(node); s != "" && found[s] == nil {
+				found[s] = node
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	for _, s := range schemas {
+		if node := found[s]; node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
 // GetPath returns the full path from root to this node
 // For example: ["Databases", "postgres", "public", "users"]
 func (n *TreeNode) GetPath() []string {
@@ -238,6 +320,53 @@ func RefreshTreeChildren(node *TreeNode, children []*TreeNode) {
 	node.Loaded = true
 }
 
+// IsSystemSchema reports whether a schema is a PostgreSQL system schema
+func IsSystemSchema(name string) bool {
+	return name == "pg_catalog" || name == "information_schema" ||
+		strings.HasPrefix(name, "pg_toast") || strings.HasPrefix(name, "pg_temp_")
+}
+
+// SetSystemSchemasVisible shows or hides system schema nodes under every
+// database node in the tree. Hidden schemas are detached rather than just
+// skipped when rendering, so tree search and other traversals ignore them too.
+func (n *TreeNode) SetSystemSchemasVisible(visible bool) {
+	if n.Type != TreeNodeTypeDatabase {
+		for _, child := range n.Children {
+			if child.Type == TreeNodeTypeDatabase || child.Type == TreeNodeTypeRoot {
+				child.SetSystemSchemasVisible(visible)
+			}
+		}
+		return
+	}
+
+	if visible {
+		if len(n.hiddenChildren) == 0 {
+			return
+		}
+		n.Children = append(n.Children, n.hiddenChildren...)
+		n.hiddenChildren = nil
+		// Schemas are listed alphabetically after any non-schema groups
+		sort.SliceStable(n.Children, func(i, j int) bool {
+			a, b := n.Children[i], n.Children[j]
+			if (a.Type == TreeNodeTypeSchema) != (b.Type == TreeNodeTypeSchema) {
+				return b.Type == TreeNodeTypeSchema
+			}
+			return a.Type == TreeNodeTypeSchema && a.Label < b.Label
+		})
+		return
+	}
+
+	kept := make([]*TreeNode, 0, len(n.Children))
+	for _, child := range n.Children {
+		if child.Type == TreeNodeTypeSchema && IsSystemSchema(child.Label) {
+			n.hiddenChildren = append(n.hiddenChildren, child)
+		} else {
+			kept = append(kept, child)
+		}
+	}
+	n.Children = kept
+}
+
 // BuildSchemaNodes creates schema nodes for a database
 // This is a helper function for lazy loading schemas when a database is expanded
 func BuildSchemaNodes(dbName string, schemas []string) []*TreeNode {
@@ -283,6 +412,7 @@ type ColumnInfo struct {
 	Default    *string
 	IsArray    bool
 	IsJsonb    bool
+	Generated  bool // GENERATED ALWAYS AS (...) STORED, so it can't be written
 }
 
 // BuildColumnNodes creates column nodes for a table
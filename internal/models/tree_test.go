@@ -90,6 +90,18 @@ func TestToggle(t *testing.T) {
 	if colNode.Expanded {
 		t.Error("Column nodes should never expand")
 	}
+
+	// Enum types expand to load their values; the values are leaves
+	enumNode := NewTreeNode("enumtype:postgres.public.mood", TreeNodeTypeEnumType, "mood")
+	enumNode.Toggle()
+	if !enumNode.Expanded {
+		t.Error("Unloaded enum type should expand to load its values")
+	}
+	valueNode := NewTreeNode("enumvalue:postgres.public.mood.happy", TreeNodeTypeEnumValue, "happy")
+	valueNode.Toggle()
+	if valueNode.Expanded {
+		t.Error("Enum value nodes should never expand")
+	}
 }
 
 func TestFlatten(t *testing.T) {
@@ -148,6 +160,70 @@ func TestFindByID(t *testing.T) {
 	}
 }
 
+func TestFindObject(t *testing.T) {
+	db := NewTreeNode("db:postgres", TreeNodeTypeDatabase, "postgres")
+	for _, schemaName := range []string{"public", "sales"} {
+		schema := NewTreeNode("schema:postgres."+schemaName, TreeNodeTypeSchema, schemaName)
+		group := NewTreeNode("tables:postgres."+schemaName, TreeNodeTypeTableGroup, "Tables (1)")
+		group.AddChild(NewTreeNode("table:postgres."+schemaName+".orders", TreeNodeTypeTable, "orders"))
+		schema.AddChild(group)
+		db.AddChild(schema)
+	}
+	db.AddChild(NewTreeNode("extension:postgres.pgcrypto", TreeNodeTypeExtension, "pgcrypto"))
+
+	node := db.FindObject("sales", TreeNodeTypeTable, "orders")
+	if node == nil || node.ID != "table:postgres.sales.orders" {
+		t.Fatalf("Expected sales.orders, got %v", node)
+	}
+
+	if node := db.FindObject("", TreeNodeTypeExtension, "pgcrypto"); node == nil {
+		t.Error("Should find extension outside any schema")
+	}
+
+	if node := db.FindObject("public", TreeNodeTypeView, "orders"); node != nil {
+		t.Error("Should not match a node of a different type")
+	}
+
+	if node := db.FindObject("archive", TreeNodeTypeTable, "orders"); node != nil {
+		t.Error("Should not match a node in a different schema")
+	}
+}
+
+func TestResolveObject(t *testing.T) {
+	db := NewTreeNode("db:postgres", TreeNodeTypeDatabase, "postgres")
+	for _, schemaName := range []string{"public", "sales"} {
+		schema := NewTreeNode("schema:postgres."+schemaName, TreeNodeTypeSchema, schemaName)
+		group := NewTreeNode("tables:postgres."+schemaName, TreeNodeTypeTableGroup, "Tables (1)")
+		group.AddChild(NewTreeNode("table:postgres."+schemaName+".orders", TreeNodeTypeTable, "orders"))
+		schema.AddChild(group)
+		db.AddChild(schema)
+	}
+	funcs := NewTreeNode("functions:postgres.sales", TreeNodeTypeFunctionGroup, "Functions (1)")
+	funcs.AddChild(NewTreeNode("function:postgres.sales.total", TreeNodeTypeFunction, "total(integer)"))
+	db.Children[1].AddChild(funcs)
+
+	if node := db.ResolveObject("sales", "orders", nil); node == nil || node.ID != "table:postgres.sales.orders" {
+		t.Errorf("Expected sales.orders, got %v", node)
+	}
+
+	// Unqualified names follow the search path order
+	if node := db.ResolveObject("", "orders", []string{"sales", "public"}); node == nil || node.ID != "table:postgres.sales.orders" {
+		t.Errorf("Expected search path to pick sales.orders, got %v", node)
+	}
+	if node := db.ResolveObject("", "orders", []string{"pg_catalog", "public"}); node == nil || node.ID != "table:postgres.public.orders" {
+		t.Errorf("Expected search path to pick public.orders, got %v", node)
+	}
+
+	// Function labels carry arguments, names don't
+	if node := db.ResolveObject("", "total", []string{"public", "sales"}); node == nil || node.ID != "function:postgres.sales.total" {
+		t.Errorf("Expected sales.total function, got %v", node)
+	}
+
+	if node := db.ResolveObject("", "total", []string{"public"}); node != nil {
+		t.Error("Should not find an object outside the search path")
+	}
+}
+
 func TestGetPath(t *testing.T) {
 	// Build a deeper tree
 	root := NewTreeNode("root", TreeNodeTypeRoot, "Databases")
@@ -423,3 +499,48 @@ func TestIsAncestorOf(t *testing.T) {
 		t.Error("Node should not be ancestor of itself")
 	}
 }
+
+func TestSetSystemSchemasVisible(t *testing.T) {
+	root := NewTreeNode("root", TreeNodeTypeRoot, "Databases")
+	db := NewTreeNode("db:postgres", TreeNodeTypeDatabase, "postgres")
+	root.AddChild(db)
+	db.AddChild(NewTreeNode("extensions:postgres", TreeNodeTypeExtensionGroup, "Extensions (1)"))
+	for _, name := range []string{"app", "information_schema", "pg_catalog", "public"} {
+		db.AddChild(NewTreeNode("schema:postgres."+name, TreeNodeTypeSchema, name))
+	}
+
+	labels := func() []string {
+		var out []string
+		for _, child := range db.Children {
+			out = append(out, child.Label)
+		}
+		return out
+	}
+
+	root.SetSystemSchemasVisible(false)
+	got := labels()
+	want := []string{"Extensions (1)", "app", "public"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v when hidden, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v when hidden, got %v", want, got)
+		}
+	}
+	if root.FindByID("schema:postgres.pg_catalog") != nil {
+		t.Error("Hidden schema should not be found in the tree")
+	}
+
+	root.SetSystemSchemasVisible(true)
+	got = labels()
+	want = []string{"Extensions (1)", "app", "information_schema", "pg_catalog", "public"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v when shown, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v when shown, got %v", want, got)
+		}
+	}
+}
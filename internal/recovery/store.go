@@ -0,0 +1,87 @@
+// Package recovery keeps a copy of the SQL editor's content on disk so it
+// can be restored after a crash.
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileName is the recovery file's name in the config directory
+const FileName = "editor_recovery.sql"
+
+// Store saves and restores the editor content
+type Store struct {
+	path  string
+	saved string // Content last written, to skip writes when nothing changed
+}
+
+// NewStore creates a store for the recovery file in configDir
+func NewStore(configDir string) *Store {
+	return &Store{path: filepath.Join(configDir, FileName)}
+}
+
+// Save writes content to the recovery file if it changed since the last
+// save. Blank content removes the file, since there is nothing to recover.
+func (s *Store) Save(content string) error {
+	if content == s.saved {
+		return nil
+	}
+	if strings.TrimSpace(content) == "" {
+		if err := s.Clear(); err != nil {
+			return err
+		}
+		s.saved = content
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	// Write a temporary file and rename it over the old one, so a crash
+	// mid-write can't leave a truncated copy
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write recovery file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write recovery file: %w", err)
+	}
+	s.saved = content
+	return nil
+}
+
+// Load returns the content left by a session that didn't exit cleanly and
+// when it was saved. The content is empty if there is nothing to restore.
+func (s *Store) Load() (string, time.Time, error) {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read recovery file: %w", err)
+	}
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		return "", time.Time{}, nil
+	}
+	return content, info.ModTime(), nil
+}
+
+// Clear removes the recovery file
+func (s *Store) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove recovery file: %w", err)
+	}
+	s.saved = ""
+	return nil
+}
@@ -0,0 +1,60 @@
+package recovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_SaveLoadClear(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir)
+
+	if content, _, err := s.Load(); err != nil || content != "" {
+		t.Fatalf("Load() with no file = %q, %v", content, err)
+	}
+
+	sql := "SELECT *\nFROM orders\nWHERE status = 'open'"
+	if err := s.Save(sql); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// A new session reads what the last one saved
+	content, savedAt, err := NewStore(dir).Load()
+	if err != nil || content != sql {
+		t.Fatalf("Load() = %q, %v, want the saved SQL", content, err)
+	}
+	if savedAt.IsZero() {
+		t.Error("Load() returned no save time")
+	}
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, FileName)); !os.IsNotExist(err) {
+		t.Errorf("recovery file still exists after Clear: %v", err)
+	}
+}
+
+func TestStore_BlankContentIsNotRecovered(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir)
+
+	if err := s.Save("SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	// Clearing the editor removes the copy, so there is nothing to offer
+	if err := s.Save("  \n"); err != nil {
+		t.Fatal(err)
+	}
+	if content, _, err := NewStore(dir).Load(); err != nil || content != "" {
+		t.Errorf("Load() after saving a blank editor = %q, %v, want nothing", content, err)
+	}
+
+	// A blank file left by some other means isn't offered either
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("\n\t"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if content, _, _ := NewStore(dir).Load(); content != "" {
+		t.Errorf("Load() of a blank file = %q, want nothing", content)
+	}
+}
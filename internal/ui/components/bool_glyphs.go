@@ -0,0 +1,44 @@
+package components
+
+// BoolGlyphs draw the values of boolean columns as glyphs, e.g. ✓ and ✗,
+// centered in the cell. Only the drawing changes: copying, searching and
+// the preview pane still use true and false. The same settings are shared
+// by all grids, so toggling them shows everywhere at once.
+type BoolGlyphs struct {
+	Enabled bool
+	True    string
+	False   string
+}
+
+// DefaultBoolGlyphs returns the glyphs used unless configured otherwise
+func DefaultBoolGlyphs() *BoolGlyphs {
+	return &BoolGlyphs{Enabled: true, True: "✓", False: "✗"}
+}
+
+// NewBoolGlyphs returns glyphs from the config: a true and a false glyph,
+// or none to show true and false as text
+func NewBoolGlyphs(glyphs []string) *BoolGlyphs {
+	g := DefaultBoolGlyphs()
+	switch {
+	case len(glyphs) == 0:
+		g.Enabled = false
+	case len(glyphs) >= 2:
+		g.True, g.False = glyphs[0], glyphs[1]
+	}
+	return g
+}
+
+// Glyph returns the glyph drawn for a boolean value. NULL and anything
+// else that isn't a boolean keep their text.
+func (g *BoolGlyphs) Glyph(value string) (string, bool) {
+	if g == nil || !g.Enabled {
+		return "", false
+	}
+	switch value {
+	case "true", "t":
+		return g.True, true
+	case "false", "f":
+		return g.False, true
+	}
+	return "", false
+}
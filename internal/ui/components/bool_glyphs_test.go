@@ -0,0 +1,58 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestNewBoolGlyphs(t *testing.T) {
+	if g := NewBoolGlyphs([]string{"Y", "N"}); !g.Enabled || g.True != "Y" || g.False != "N" {
+		t.Errorf("NewBoolGlyphs([Y N]) = %+v, want Y and N", g)
+	}
+	// Off when configured empty, but toggling on shows the defaults
+	if g := NewBoolGlyphs(nil); g.Enabled || g.True != "✓" || g.False != "✗" {
+		t.Errorf("NewBoolGlyphs(nil) = %+v, want disabled defaults", g)
+	}
+}
+
+func TestTableView_BoolGlyphs(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width, tv.Height = 80, 10
+	tv.BoolGlyphs = DefaultBoolGlyphs()
+	tv.SetData([]string{"id", "active", "note"}, [][]string{
+		{"1", "true", "true"},
+		{"2", "false", "false"},
+		{"3", "NULL", "NULL"},
+	}, 3)
+	tv.SetColumnKinds([]models.ColumnKind{models.ColumnKindPlain, models.ColumnKindBool, models.ColumnKindPlain})
+
+	view := tv.View()
+	if strings.Count(view, "✓") != 1 || strings.Count(view, "✗") != 1 {
+		t.Errorf("view does not show one ✓ and one ✗:\n%s", view)
+	}
+	// Only the boolean column is drawn as glyphs, and NULL stays NULL
+	if strings.Count(view, "true") != 1 || strings.Count(view, "false") != 1 || strings.Count(view, "NULL") != 2 {
+		t.Errorf("view drew a glyph outside the boolean column or for NULL:\n%s", view)
+	}
+
+	// The glyph is centered in the cell
+	lines := strings.Split(view, "\n")
+	for _, line := range lines {
+		if i := strings.Index(line, "✓"); i >= 0 && !strings.Contains(line[:i], "│   ") {
+			t.Errorf("✓ is not centered: %q", line)
+		}
+	}
+
+	// Copying keeps the value
+	if got := tv.CopyValue(0, 1); got != "true" {
+		t.Errorf("CopyValue() = %q, want true", got)
+	}
+
+	tv.BoolGlyphs.Enabled = false
+	if view := tv.View(); strings.Contains(view, "✓") || strings.Count(view, "true") != 2 {
+		t.Errorf("view still draws glyphs when turned off:\n%s", view)
+	}
+}
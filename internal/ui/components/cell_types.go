@@ -0,0 +1,105 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// relativeTimeWidth is the room reserved on timestamp columns for the
+// annotation, e.g. " · 11 months ago"
+const relativeTimeWidth = 16
+
+// Layouts accepted for timestamptz cells. Cells are normally formatted with
+// time.Time.String(); the PostgreSQL text formats cover values that arrive
+// as text.
+var timestampTZLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	time.RFC3339Nano,
+}
+
+// Layouts accepted for timestamp (without time zone) cells. pgx reports
+// these as UTC, so a zone suffix may be present but carries no meaning.
+var timestampLayouts = append([]string{"2006-01-02 15:04:05.999999999"}, timestampTZLayouts...)
+
+// parseTimestampCell parses a timestamp cell. A timestamptz value must carry
+// an offset and is an absolute instant; a timestamp value is wall-clock time
+// and is read in the local zone, whatever suffix it was printed with.
+func parseTimestampCell(value string, withTZ bool) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "NULL" {
+		return time.Time{}, false
+	}
+
+	layouts := timestampLayouts
+	if withTZ {
+		layouts = timestampTZLayouts
+	}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if !withTZ {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// formatRelativeTime describes t relative to now, e.g. "3 days ago" or
+// "in 2 hours"
+func formatRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < 45*time.Second {
+		return "just now"
+	}
+
+	var amount string
+	switch {
+	case d < 45*time.Minute:
+		amount = pluralize(int((d+30*time.Second)/time.Minute), "minute", "minutes")
+	case d < 22*time.Hour:
+		amount = pluralize(int((d+30*time.Minute)/time.Hour), "hour", "hours")
+	case d < 26*24*time.Hour:
+		amount = pluralize(int((d+12*time.Hour)/(24*time.Hour)), "day", "days")
+	case d < 320*24*time.Hour:
+		amount = pluralize(int((d+15*24*time.Hour)/(30*24*time.Hour)), "month", "months")
+	default:
+		amount = pluralize(int((d+182*24*time.Hour)/(365*24*time.Hour)), "year", "years")
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+// relativeTimeAnnotation returns the " · 3 days ago" suffix for a timestamp
+// cell on the selected row, or "" when the cell isn't one or the value would
+// have to be cut short to make room. Only the column type decides: text
+// that merely looks like a date is never annotated.
+func (tv *TableView) relativeTimeAnnotation(kind models.ColumnKind, value, display string, width int, selected bool) string {
+	if !selected || !kind.IsTimestamp() || display != value {
+		return ""
+	}
+	t, ok := parseTimestampCell(value, kind == models.ColumnKindTimestampTZ)
+	if !ok {
+		return ""
+	}
+	annotation := fmt.Sprintf(" · %s", formatRelativeTime(t, time.Now()))
+	if runewidth.StringWidth(value)+runewidth.StringWidth(annotation) > width {
+		return ""
+	}
+	return annotation
+}
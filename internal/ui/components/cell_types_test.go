@@ -0,0 +1,136 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestParseTimestampCell(t *testing.T) {
+	instant := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		withTZ bool
+		want   time.Time
+		ok     bool
+	}{
+		{"timestamptz from pgx", "2024-03-01 14:30:00 +0200 EET", true, instant, true},
+		{"timestamptz text", "2024-03-01 12:30:00+00", true, instant, true},
+		{"timestamptz text with minutes", "2024-03-01 18:00:00+05:30", true, instant, true},
+		{"timestamptz without offset", "2024-03-01 12:30:00", true, time.Time{}, false},
+		{"timestamp is local wall clock", "2024-03-01 12:30:00 +0000 UTC", false,
+			time.Date(2024, 3, 1, 12, 30, 0, 0, time.Local), true},
+		{"timestamp text", "2024-03-01 12:30:00.5", false,
+			time.Date(2024, 3, 1, 12, 30, 0, 500000000, time.Local), true},
+		{"null", "NULL", true, time.Time{}, false},
+		{"infinity", "infinity", false, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTimestampCell(tt.value, tt.withTZ)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{-10 * time.Second, "just now"},
+		{-time.Minute, "1 minute ago"},
+		{-3 * time.Hour, "3 hours ago"},
+		{-3 * 24 * time.Hour, "3 days ago"},
+		{2 * 24 * time.Hour, "in 2 days"},
+		{-90 * 24 * time.Hour, "3 months ago"},
+		{-2 * 365 * 24 * time.Hour, "2 years ago"},
+	}
+	for _, tt := range tests {
+		if got := formatRelativeTime(now.Add(tt.offset), now); got != tt.want {
+			t.Errorf("formatRelativeTime(%v) = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeTimeAnnotationOnlyForTimestampColumns(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.SetData([]string{"created_at", "note"}, [][]string{
+		{"2024-03-01 12:30:00 +0000 UTC", "2024-03-01 12:30:00 +0000 UTC"},
+	}, 1)
+	tv.SetColumnKinds([]models.ColumnKind{models.ColumnKindTimestampTZ, models.ColumnKindPlain})
+
+	value := tv.Rows[0][0]
+	width := tv.ColumnWidths[0]
+	if got := tv.relativeTimeAnnotation(tv.columnKind(0), value, value, width, true); !strings.Contains(got, "ago") {
+		t.Errorf("expected annotation on timestamptz column, got %q", got)
+	}
+	if got := tv.relativeTimeAnnotation(tv.columnKind(0), value, value, width, false); got != "" {
+		t.Errorf("expected no annotation off the selected row, got %q", got)
+	}
+	if got := tv.relativeTimeAnnotation(tv.columnKind(1), value, value, tv.ColumnWidths[1], true); got != "" {
+		t.Errorf("expected no annotation on a text column, got %q", got)
+	}
+}
+
+func TestSetColumnKindsIgnoresMismatch(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.SetData([]string{"a", "b"}, nil, 0)
+	tv.SetColumnKinds([]models.ColumnKind{models.ColumnKindUUID})
+	if tv.ColumnKinds != nil {
+		t.Error("expected mismatched kinds to be ignored")
+	}
+
+	tv.SetColumnKinds([]models.ColumnKind{models.ColumnKindUUID, models.ColumnKindEnum})
+	tv.SetData([]string{"c"}, nil, 0)
+	if tv.ColumnKinds != nil {
+		t.Error("expected SetData to clear stale kinds")
+	}
+}
+
+func TestSelectedJSON(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	long := `{"items": [` + strings.Repeat(`{"sku": "A-1", "qty": 2}, `, 50) + `{"sku": "B-2", "qty": 1}]}`
+	tv.SetData([]string{"doc", "count", "note"}, [][]string{
+		{long, "42", "[draft]"},
+		{"NULL", "7", "plain"},
+	}, 2)
+	tv.SetColumnKinds([]models.ColumnKind{models.ColumnKindPlain, models.ColumnKindJSON, models.ColumnKindPlain})
+
+	// The whole value, not what fits in the cell
+	if got, ok := tv.SelectedJSON(); !ok || got != long {
+		t.Errorf("SelectedJSON() on an object = %.20q, %v, want the untruncated value", got, ok)
+	}
+
+	// A scalar in a jsonb column is JSON too
+	tv.SelectedCol = 1
+	if got, ok := tv.SelectedJSON(); !ok || got != "42" {
+		t.Errorf("SelectedJSON() on a jsonb scalar = %q, %v", got, ok)
+	}
+
+	// Text that only looks like JSON is offered, for the viewer to reject
+	tv.SelectedCol = 2
+	if _, ok := tv.SelectedJSON(); !ok {
+		t.Error("SelectedJSON() on bracketed text = false")
+	}
+
+	tv.SelectedRow, tv.SelectedCol = 1, 0
+	if _, ok := tv.SelectedJSON(); ok {
+		t.Error("SelectedJSON() on NULL = true")
+	}
+	tv.SelectedCol = 2
+	if _, ok := tv.SelectedJSON(); ok {
+		t.Error("SelectedJSON() on plain text = true")
+	}
+}
@@ -55,11 +55,17 @@ type CodeEditor struct {
 	Width         int
 	Height        int
 	ReadOnly      bool   // true = view mode, false = edit mode
+	ViewOnly      bool   // true = edit mode can't be entered (e.g. query plans)
 	Modified      bool   // true if content changed from original
 	Original      string // Original content for comparison
 	Focused       bool   // true if this editor has focus
 	statusMessage string // Temporary status message (e.g., "✓ Copied")
 
+	// Diff view (original vs. edited content)
+	showDiff   bool
+	diffLines  []DiffLine
+	diffScroll int
+
 	// Theme
 	Theme theme.Theme
 
@@ -85,6 +91,11 @@ type codeEditorStyles struct {
 	statusBar      lipgloss.Style
 	cursor         lipgloss.Style
 	emptyLine      lipgloss.Style
+	diffAdded      lipgloss.Style
+	diffRemoved    lipgloss.Style
+	modeDiff       lipgloss.Style
+	planNode       lipgloss.Style
+	planCost       lipgloss.Style
 }
 
 // NewCodeEditor creates a new code editor
@@ -134,6 +145,18 @@ func (ce *CodeEditor) initStyles() {
 			Background(ce.Theme.Cursor),
 		emptyLine: lipgloss.NewStyle().
 			Foreground(ce.Theme.Metadata),
+		diffAdded: lipgloss.NewStyle().
+			Foreground(ce.Theme.Success),
+		diffRemoved: lipgloss.NewStyle().
+			Foreground(ce.Theme.Error),
+		modeDiff: lipgloss.NewStyle().
+			Foreground(ce.Theme.Info).
+			Bold(true),
+		planNode: lipgloss.NewStyle().
+			Foreground(ce.Theme.Info).
+			Bold(true),
+		planCost: lipgloss.NewStyle().
+			Foreground(ce.Theme.Warning),
 	}
 }
 
@@ -171,6 +194,10 @@ func (ce *CodeEditor) SetContent(content, objectType, title string) {
 	switch objectType {
 	case "function", "procedure", "trigger_function":
 		ce.Language = "plpgsql"
+	case "query_plan":
+		ce.Language = "plan"
+	case "query_plan_json":
+		ce.Language = "json"
 	default:
 		ce.Language = "sql"
 	}
@@ -186,8 +213,27 @@ func (ce *CodeEditor) EnterEditMode() {
 	ce.ReadOnly = false
 }
 
+// ShowDiff switches to the diff view between the original and edited content
+func (ce *CodeEditor) ShowDiff() {
+	ce.diffLines = DiffLines(strings.Split(ce.Original, "\n"), ce.lines)
+	ce.diffScroll = 0
+	ce.showDiff = true
+}
+
+// HideDiff returns from the diff view to edit mode
+func (ce *CodeEditor) HideDiff() {
+	ce.showDiff = false
+	ce.diffLines = nil
+}
+
+// IsShowingDiff returns whether the diff view is active
+func (ce *CodeEditor) IsShowingDiff() bool {
+	return ce.showDiff
+}
+
 // ExitEditMode switches to read-only mode, optionally discarding changes
 func (ce *CodeEditor) ExitEditMode(discardChanges bool) {
+	ce.HideDiff()
 	if discardChanges && ce.Modified {
 		// Restore original content
 		ce.SetContent(ce.Original, ce.ObjectType, ce.Title)
@@ -205,6 +251,9 @@ func (ce *CodeEditor) highlightLine(line string) string {
 	if line == "" {
 		return ""
 	}
+	if ce.Language == "plan" {
+		return ce.highlightPlanLine(line)
+	}
 
 	// Get appropriate lexer
 	var lexer chroma.Lexer
@@ -214,6 +263,8 @@ func (ce *CodeEditor) highlightLine(line string) string {
 		if lexer == nil {
 			lexer = lexers.Get("postgresql")
 		}
+	case "json":
+		lexer = lexers.Get("json")
 	default:
 		lexer = lexers.Get("postgresql")
 	}
@@ -246,6 +297,17 @@ func (ce *CodeEditor) highlightLine(line string) string {
 	return result
 }
 
+// highlightPlanLine highlights plan nodes and their cost estimates in an
+// EXPLAIN line, leaving the indentation that shows the plan shape untouched
+func (ce *CodeEditor) highlightPlanLine(line string) string {
+	idx := strings.Index(line, "(cost=")
+	if idx < 0 {
+		// Detail lines (Filter, Sort Key, ...) and summary lines
+		return ce.cachedStyles.content.Render(line)
+	}
+	return ce.cachedStyles.planNode.Render(line[:idx]) + ce.cachedStyles.planCost.Render(line[idx:])
+}
+
 // getLineNumberWidth returns the width needed for line numbers
 func (ce *CodeEditor) getLineNumberWidth() int {
 	maxLine := len(ce.lines)
@@ -362,6 +424,14 @@ func (ce *CodeEditor) View() string {
 	// Build content lines
 	var contentLines []string
 
+	if ce.showDiff {
+		contentLines = ce.renderDiff(contentWidth, contentHeight)
+		allLines := []string{titleBar, titleSeparator}
+		allLines = append(allLines, contentLines...)
+		allLines = append(allLines, ce.renderSeparator(contentWidth), ce.renderStatusBar(contentWidth))
+		return borderStyle.Width(contentWidth).Render(strings.Join(allLines, "\n"))
+	}
+
 	// Ensure cursor is visible (scroll if needed)
 	ce.ensureCursorVisible(contentHeight)
 
@@ -399,6 +469,51 @@ func (ce *CodeEditor) View() string {
 	return borderStyle.Width(contentWidth).Render(content)
 }
 
+// renderDiff renders the visible portion of the diff with +/- markers
+func (ce *CodeEditor) renderDiff(contentWidth, contentHeight int) []string {
+	maxScroll := len(ce.diffLines) - contentHeight
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if ce.diffScroll > maxScroll {
+		ce.diffScroll = maxScroll
+	}
+
+	var lines []string
+	for i := ce.diffScroll; i < len(ce.diffLines) && len(lines) < contentHeight; i++ {
+		dl := ce.diffLines[i]
+		text := dl.Text
+		if runewidth.StringWidth(text) > contentWidth-2 {
+			text = runewidth.Truncate(text, contentWidth-3, "…")
+		}
+		switch dl.Op {
+		case DiffAdded:
+			lines = append(lines, ce.cachedStyles.diffAdded.Render("+ "+text))
+		case DiffRemoved:
+			lines = append(lines, ce.cachedStyles.diffRemoved.Render("- "+text))
+		default:
+			lines = append(lines, ce.cachedStyles.content.Render("  "+text))
+		}
+	}
+	for len(lines) < contentHeight {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// diffStats returns the number of added and removed lines in the diff
+func (ce *CodeEditor) diffStats() (added, removed int) {
+	for _, dl := range ce.diffLines {
+		switch dl.Op {
+		case DiffAdded:
+			added++
+		case DiffRemoved:
+			removed++
+		}
+	}
+	return added, removed
+}
+
 // getObjectIcon returns the icon and color for the object type
 func (ce *CodeEditor) getObjectIcon() (string, lipgloss.Color) {
 	switch ce.ObjectType {
@@ -428,6 +543,8 @@ func (ce *CodeEditor) getObjectIcon() (string, lipgloss.Color) {
 		return "◨", ce.Theme.TypeIcon
 	case "range_type":
 		return "◩", ce.Theme.TypeIcon
+	case "query_plan", "query_plan_json":
+		return "⊞", ce.Theme.Info
 	default:
 		return "□", ce.Theme.Foreground
 	}
@@ -447,7 +564,9 @@ func (ce *CodeEditor) renderTitleBar(width int) string {
 
 	// Mode indicator on right
 	var modeIndicator string
-	if ce.ReadOnly {
+	if ce.showDiff {
+		modeIndicator = ce.cachedStyles.modeDiff.Render("[Diff]")
+	} else if ce.ReadOnly {
 		modeIndicator = ce.cachedStyles.modeReadOnly.Render("[Read Only]")
 	} else if ce.Modified {
 		modeIndicator = ce.cachedStyles.modeModified.Render("[Modified *]")
@@ -484,15 +603,24 @@ func (ce *CodeEditor) renderSeparator(width int) string {
 func (ce *CodeEditor) renderStatusBar(width int) string {
 	var helpParts []string
 
-	if ce.ReadOnly {
+	if ce.showDiff {
+		added, removed := ce.diffStats()
+		helpParts = []string{
+			fmt.Sprintf("+%d -%d", added, removed),
+			"j/k:scroll", "Ctrl+S:save", "Esc:back to edit",
+		}
+	} else if ce.ReadOnly {
 		helpParts = []string{"e:edit", "y:copy", "esc:close"}
+		if ce.ViewOnly {
+			helpParts = helpParts[1:]
+		}
 
 		// Show scroll hint if content is scrollable
 		if len(ce.lines) > ce.Height-5 {
 			helpParts = append([]string{"j/k:scroll"}, helpParts...)
 		}
 	} else {
-		helpParts = []string{"Ctrl+S:save", "Esc:cancel"}
+		helpParts = []string{"Ctrl+S:save", "Ctrl+D:diff", "Esc:cancel"}
 	}
 
 	helpText := strings.Join(helpParts, "  ")
@@ -503,7 +631,9 @@ func (ce *CodeEditor) renderStatusBar(width int) string {
 	colNum := ce.cursorCol + 1
 
 	var posInfo string
-	if ce.ReadOnly {
+	if ce.showDiff {
+		posInfo = fmt.Sprintf("Line %d/%d", ce.diffScroll+1, len(ce.diffLines))
+	} else if ce.ReadOnly {
 		// Show line/total and percentage
 		percent := 100
 		if totalLines > 1 {
@@ -612,7 +742,9 @@ func (ce *CodeEditor) handleReadOnlyKeys(msg tea.KeyMsg) (*CodeEditor, tea.Cmd)
 
 	// Enter edit mode
 	case "e":
-		ce.EnterEditMode()
+		if !ce.ViewOnly {
+			ce.EnterEditMode()
+		}
 
 	// Close (only esc, q is reserved for quitting app)
 	case "esc":
@@ -624,8 +756,37 @@ func (ce *CodeEditor) handleReadOnlyKeys(msg tea.KeyMsg) (*CodeEditor, tea.Cmd)
 	return ce, nil
 }
 
+// handleDiffKeys handles key events while the diff view is shown
+func (ce *CodeEditor) handleDiffKeys(msg tea.KeyMsg) (*CodeEditor, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		if ce.diffScroll < len(ce.diffLines)-1 {
+			ce.diffScroll++
+		}
+	case "k", "up":
+		if ce.diffScroll > 0 {
+			ce.diffScroll--
+		}
+	case "g":
+		ce.diffScroll = 0
+	case "G":
+		ce.diffScroll = len(ce.diffLines) - 1
+	case "ctrl+s":
+		ce.HideDiff()
+		return ce.handleEditKeys(msg)
+	case "esc", "ctrl+d":
+		// Return to edit mode with edits intact
+		ce.HideDiff()
+	}
+	return ce, nil
+}
+
 // handleEditKeys handles key events in edit mode
 func (ce *CodeEditor) handleEditKeys(msg tea.KeyMsg) (*CodeEditor, tea.Cmd) {
+	if ce.showDiff {
+		return ce.handleDiffKeys(msg)
+	}
+
 	switch msg.String() {
 	// Cursor movement
 	case "left":
@@ -669,6 +830,10 @@ func (ce *CodeEditor) handleEditKeys(msg tea.KeyMsg) (*CodeEditor, tea.Cmd) {
 			}
 		}
 
+	// Show diff against the original
+	case "ctrl+d":
+		ce.ShowDiff()
+
 	// Cancel edit
 	case "esc":
 		ce.ExitEditMode(true) // Discard changes
@@ -25,10 +25,13 @@ type CloseCommandPaletteMsg struct{}
 type PaletteMode int
 
 const (
-	PaletteModeDefault  PaletteMode = iota // Commands + Tables/Views
-	PaletteModeCommands                    // Only commands (> prefix)
-	PaletteModeTables                      // Only tables/views (@ prefix)
-	PaletteModeHistory                     // Only history (# prefix)
+	PaletteModeDefault     PaletteMode = iota // Commands + Tables/Views
+	PaletteModeCommands                       // Only commands (> prefix)
+	PaletteModeTables                         // Only database objects (@ prefix)
+	PaletteModeHistory                        // Only history (# prefix)
+	PaletteModeRecent                         // Only recently opened objects (~ prefix)
+	PaletteModeDatabases                      // Only databases on the server (% prefix)
+	PaletteModeConnections                    // Only open connections to run a query on (& prefix)
 )
 
 // CommandPalette provides fuzzy search over commands, tables, and history
@@ -38,9 +41,12 @@ type CommandPalette struct {
 	Mode     PaletteMode
 
 	// Data sources
-	Commands []models.Command // Built-in commands
-	Tables   []models.Command // Tables and views
-	History  []models.Command // Query history
+	Commands    []models.Command // Built-in commands
+	Tables      []models.Command // Tables and views
+	History     []models.Command // Query history
+	Recent      []models.Command // Recently opened tree objects
+	Databases   []models.Command // Databases to switch to
+	Connections []models.Command // Open connections to run a query on
 
 	// Filtered results
 	Filtered     []models.Command
@@ -62,6 +68,7 @@ func NewCommandPalette(th theme.Theme) *CommandPalette {
 		Commands: []models.Command{},
 		Tables:   []models.Command{},
 		History:  []models.Command{},
+		Recent:   []models.Command{},
 		Filtered: []models.Command{},
 		Selected: 0,
 		Width:    80,
@@ -76,7 +83,7 @@ func (cp *CommandPalette) SetCommands(commands []models.Command) {
 	cp.Filter()
 }
 
-// SetTables updates the available tables/views
+// SetTables updates the available database objects
 func (cp *CommandPalette) SetTables(tables []models.Command) {
 	cp.Tables = tables
 	cp.Filter()
@@ -88,6 +95,31 @@ func (cp *CommandPalette) SetHistory(history []models.Command) {
 	cp.Filter()
 }
 
+// SetRecent updates the recently opened objects
+func (cp *CommandPalette) SetRecent(recent []models.Command) {
+	cp.Recent = recent
+	cp.Filter()
+}
+
+// SetConnections updates the open connections to run a query on
+func (cp *CommandPalette) SetConnections(connections []models.Command) {
+	cp.Connections = connections
+	cp.Filter()
+}
+
+// SetDatabases updates the databases to switch to
+func (cp *CommandPalette) SetDatabases(databases []models.Command) {
+	cp.Databases = databases
+	cp.Filter()
+}
+
+// SetInput replaces the input (including any mode prefix) and re-filters
+func (cp *CommandPalette) SetInput(input string) {
+	cp.Input = input
+	cp.parseInput()
+	cp.Filter()
+}
+
 // Reset clears the input and resets the palette state
 func (cp *CommandPalette) Reset() {
 	cp.Input = ""
@@ -116,6 +148,15 @@ func (cp *CommandPalette) parseInput() {
 	case '#':
 		cp.Mode = PaletteModeHistory
 		cp.Query = strings.TrimSpace(cp.Input[1:])
+	case '~':
+		cp.Mode = PaletteModeRecent
+		cp.Query = strings.TrimSpace(cp.Input[1:])
+	case '&':
+		cp.Mode = PaletteModeConnections
+		cp.Query = strings.TrimSpace(cp.Input[1:])
+	case '%':
+		cp.Mode = PaletteModeDatabases
+		cp.Query = strings.TrimSpace(cp.Input[1:])
 	default:
 		cp.Mode = PaletteModeDefault
 		cp.Query = cp.Input
@@ -289,6 +330,12 @@ func (cp *CommandPalette) Filter() {
 		sources = [][]models.Command{cp.Tables}
 	case PaletteModeHistory:
 		sources = [][]models.Command{cp.History}
+	case PaletteModeRecent:
+		sources = [][]models.Command{cp.Recent}
+	case PaletteModeDatabases:
+		sources = [][]models.Command{cp.Databases}
+	case PaletteModeConnections:
+		sources = [][]models.Command{cp.Connections}
 	default: // PaletteModeDefault - Commands + Tables
 		sources = [][]models.Command{cp.Commands, cp.Tables}
 	}
@@ -320,6 +367,11 @@ func (cp *CommandPalette) Filter() {
 					matchTag = tagMatch
 				}
 			}
+			if cmd.SearchText != "" {
+				if textMatch := search.FuzzyMatch(cp.Query, cmd.SearchText); textMatch.Matched && textMatch.Score > matchTag.Score {
+					matchTag = textMatch
+				}
+			}
 
 			// Use best match
 			bestScore := 0
@@ -370,9 +422,15 @@ func (cp *CommandPalette) getPlaceholder() string {
 	case PaletteModeCommands:
 		return "Search commands..."
 	case PaletteModeTables:
-		return "Search tables and views..."
+		return "Search tables, views, functions... (schema.name)"
 	case PaletteModeHistory:
 		return "Search query history..."
+	case PaletteModeRecent:
+		return "Search recent objects..."
+	case PaletteModeDatabases:
+		return "Switch to database..."
+	case PaletteModeConnections:
+		return "Run the query on connection..."
 	default:
 		return "Search commands and tables..."
 	}
@@ -387,6 +445,12 @@ func (cp *CommandPalette) getModePrefix() string {
 		return "@ "
 	case PaletteModeHistory:
 		return "# "
+	case PaletteModeRecent:
+		return "~ "
+	case PaletteModeDatabases:
+		return "% "
+	case PaletteModeConnections:
+		return "& "
 	default:
 		return ""
 	}
@@ -522,7 +586,7 @@ func (cp *CommandPalette) View() string {
 	labelStyle := lipgloss.NewStyle().
 		Foreground(cp.Theme.Comment)
 
-	// Build hint items: [>] Commands  [@] Tables  [#] History
+	// Build hint items: [>] Commands  [@] Tables  [#] History  [~] Recent
 	cmdHint := bracketStyle.Render("[") + keyStyle.Render(">") + bracketStyle.Render("]") +
 		labelStyle.Render(" Commands")
 
@@ -532,13 +596,26 @@ func (cp *CommandPalette) View() string {
 	historyHint := bracketStyle.Render("[") + keyStyle.Render("#") + bracketStyle.Render("]") +
 		labelStyle.Render(" History")
 
-	hints := cmdHint + labelStyle.Render("   ") + tableHint + labelStyle.Render("   ") + historyHint
+	recentHint := bracketStyle.Render("[") + keyStyle.Render("~") + bracketStyle.Render("]") +
+		labelStyle.Render(" Recent")
+
+	hints := cmdHint + labelStyle.Render("   ") + tableHint + labelStyle.Render("   ") + historyHint +
+		labelStyle.Render("   ") + recentHint
 
 	hintLine := lipgloss.NewStyle().
 		Width(cp.Width - 4).
 		Padding(0, 1).
 		Render(hints)
 
+	// Details of the selected entry, such as a past query's run stats
+	if cp.Selected >= 0 && cp.Selected < len(cp.Filtered) && cp.Filtered[cp.Selected].Detail != "" {
+		results = append(results, lipgloss.NewStyle().
+			Foreground(cp.Theme.Metadata).
+			Width(cp.Width-4).
+			Padding(0, 1).
+			Render(truncateToWidth(cp.Filtered[cp.Selected].Detail, cp.Width-6)))
+	}
+
 	// Bottom separator
 	bottomSeparator := lipgloss.NewStyle().
 		Foreground(cp.Theme.Border).
@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/cursor"
@@ -23,6 +24,7 @@ type ConnectionDialog struct {
 	ManualMode          bool
 	SelectedIndex       int
 	InHistorySection    bool // true = selecting in history, false = selecting in discovered
+	MostUsed            bool // List history most used first rather than most recent
 
 	// Search
 	SearchMode  bool // true = user is typing in search box
@@ -32,6 +34,9 @@ type ConnectionDialog struct {
 	inputs      []textinput.Model
 	focusIndex  int
 	cursorMode  cursor.Mode
+
+	// SSL mode of the manual form, chosen from SSLModes
+	sslMode string
 }
 
 const (
@@ -40,8 +45,16 @@ const (
 	databaseField
 	userField
 	passwordField
+	sslModeField // Not a text input; cycled with ←/→
+	manualFieldCount
 )
 
+// SSLModes are the libpq sslmode values the manual form cycles through
+var SSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// defaultSSLMode is libpq's default
+const defaultSSLMode = "prefer"
+
 // Zone IDs for mouse click handling
 const (
 	ZoneHistoryPrefix    = "conn-history-"
@@ -120,6 +133,7 @@ func NewConnectionDialog(th theme.Theme) *ConnectionDialog {
 		Theme:            th,
 		searchInput:      searchInput,
 		InHistorySection: true, // Start in history section
+		sslMode:          defaultSSLMode,
 	}
 }
 
@@ -140,6 +154,17 @@ func (c *ConnectionDialog) Update(msg tea.Msg) (*ConnectionDialog, tea.Cmd) {
 
 	// Handle manual mode
 	if c.ManualMode {
+		if c.focusIndex == sslModeField {
+			if key, ok := msg.(tea.KeyMsg); ok {
+				switch key.String() {
+				case "left", "h":
+					c.CycleSSLMode(-1)
+				case "right", "l", " ":
+					c.CycleSSLMode(1)
+				}
+			}
+			return c, nil
+		}
 		c.inputs[c.focusIndex], cmd = c.inputs[c.focusIndex].Update(msg)
 		return c, cmd
 	}
@@ -214,7 +239,12 @@ func (c *ConnectionDialog) renderDiscoveryMode(contentWidth int) string {
 	historyHeaderStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#a6adc8")).
 		Bold(true)
-	sections = append(sections, historyHeaderStyle.Render("Recent Connections"))
+	orderHintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	if c.MostUsed {
+		sections = append(sections, historyHeaderStyle.Render("Most Used Connections")+orderHintStyle.Render("  o: recent first"))
+	} else {
+		sections = append(sections, historyHeaderStyle.Render("Recent Connections")+orderHintStyle.Render("  o: most used first"))
+	}
 
 	// History entries (filtered by search)
 	filteredHistory := c.GetFilteredHistory()
@@ -252,8 +282,12 @@ func (c *ConnectionDialog) renderDiscoveryMode(contentWidth int) string {
 			// Format: name (local)
 			metaStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#6c7086"))
+			name := entry.Name
+			if entry.Pinned {
+				name = "📌 " + name
+			}
 			line := fmt.Sprintf("%s  %s",
-				entry.Name,
+				name,
 				metaStyle.Render("(local)"),
 			)
 			// Wrap with zone for click detection
@@ -328,7 +362,7 @@ func (c *ConnectionDialog) renderDiscoveryMode(contentWidth int) string {
 	if c.SearchMode {
 		sections = append(sections, helpStyle.Render("Type to search │ Enter: Apply │ Esc: Clear & Exit"))
 	} else {
-		sections = append(sections, helpStyle.Render("↑↓: Navigate │ /: Search │ m: Manual │ Enter: Connect"))
+		sections = append(sections, helpStyle.Render("↑↓: Navigate │ /: Search │ p: Pin │ e: Edit │ m: Manual │ Enter: Connect"))
 	}
 
 	return strings.Join(sections, "\n")
@@ -366,6 +400,7 @@ func (c *ConnectionDialog) renderManualMode(contentWidth int) string {
 		)
 		sections = append(sections, fieldLine)
 	}
+	sections = append(sections, c.renderSSLModeField())
 
 	sections = append(sections, "")
 
@@ -377,21 +412,60 @@ func (c *ConnectionDialog) renderManualMode(contentWidth int) string {
 	return strings.Join(sections, "\n")
 }
 
+// renderSSLModeField renders the SSL mode selector of the manual form
+func (c *ConnectionDialog) renderSSLModeField() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#a6adc8")).
+		Width(10).
+		Align(lipgloss.Right)
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	focusIndicator := "  "
+	value := valueStyle.Render(c.sslMode)
+	if c.focusIndex == sslModeField {
+		focusIndicator = "▸ "
+		value = hintStyle.Render("‹ ") + valueStyle.Bold(true).Render(c.sslMode) + hintStyle.Render(" ›  ←/→ to change")
+	}
+	return fmt.Sprintf("%s%s %s", focusIndicator, labelStyle.Render("SSL Mode:"), value)
+}
+
+// focusField moves the manual form's focus to field
+func (c *ConnectionDialog) focusField(field int) {
+	if c.focusIndex < len(c.inputs) {
+		c.inputs[c.focusIndex].Blur()
+	}
+	c.focusIndex = field
+	if field < len(c.inputs) {
+		c.inputs[field].Focus()
+	}
+}
+
 // NextInput focuses the next input field
 func (c *ConnectionDialog) NextInput() {
-	c.inputs[c.focusIndex].Blur()
-	c.focusIndex = (c.focusIndex + 1) % len(c.inputs)
-	c.inputs[c.focusIndex].Focus()
+	c.focusField((c.focusIndex + 1) % manualFieldCount)
 }
 
 // PrevInput focuses the previous input field
 func (c *ConnectionDialog) PrevInput() {
-	c.inputs[c.focusIndex].Blur()
-	c.focusIndex--
-	if c.focusIndex < 0 {
-		c.focusIndex = len(c.inputs) - 1
+	c.focusField((c.focusIndex + manualFieldCount - 1) % manualFieldCount)
+}
+
+// SSLMode returns the SSL mode chosen in the manual form
+func (c *ConnectionDialog) SSLMode() string {
+	return c.sslMode
+}
+
+// CycleSSLMode picks the SSL mode delta places along SSLModes, wrapping
+func (c *ConnectionDialog) CycleSSLMode(delta int) {
+	i := 0
+	for j, mode := range SSLModes {
+		if mode == c.sslMode {
+			i = j
+			break
+		}
 	}
-	c.inputs[c.focusIndex].Focus()
+	c.sslMode = SSLModes[(i+delta+len(SSLModes))%len(SSLModes)]
 }
 
 // MoveSelection moves the selection up or down in discovery mode
@@ -467,8 +541,7 @@ func (c *ConnectionDialog) ToggleMode() {
 	c.ManualMode = !c.ManualMode
 	if c.ManualMode {
 		// Focus first input when entering manual mode
-		c.focusIndex = 0
-		c.inputs[c.focusIndex].Focus()
+		c.focusField(hostField)
 	} else {
 		// Blur all inputs when leaving manual mode
 		for i := range c.inputs {
@@ -596,10 +669,39 @@ func (c *ConnectionDialog) GetManualConfig() (models.ConnectionConfig, error) {
 		Database: database,
 		User:     user,
 		Password: password,
-		SSLMode:  "prefer",
+		SSLMode:  c.sslMode,
 	}, nil
 }
 
+// PrefillManual switches to manual mode with the fields filled from config,
+// focusing the database field so the user can correct it
+func (c *ConnectionDialog) PrefillManual(config models.ConnectionConfig) {
+	c.inputs[hostField].SetValue(config.Host)
+	c.inputs[portField].SetValue(fmt.Sprintf("%d", config.Port))
+	c.inputs[databaseField].SetValue(config.Database)
+	c.inputs[userField].SetValue(config.User)
+	c.inputs[passwordField].SetValue("")
+	c.sslMode = defaultSSLMode
+	if config.SSLMode != "" {
+		c.sslMode = config.SSLMode
+	}
+
+	c.ManualMode = true
+	c.SearchMode = false
+	for i := range c.inputs {
+		c.inputs[i].Blur()
+	}
+	c.focusIndex = databaseField
+	c.inputs[c.focusIndex].Focus()
+}
+
+// PrefillFromDiscovered switches to manual mode with the host and port of a
+// discovered instance, leaving the database and credentials to be entered
+// (empty fields fall back to their placeholders)
+func (c *ConnectionDialog) PrefillFromDiscovered(instance models.DiscoveredInstance) {
+	c.PrefillManual(models.ConnectionConfig{Host: instance.Host, Port: instance.Port})
+}
+
 // SetDiscoveredInstances updates the list of discovered instances
 func (c *ConnectionDialog) SetDiscoveredInstances(instances []models.DiscoveredInstance) {
 	c.DiscoveredInstances = instances
@@ -608,14 +710,44 @@ func (c *ConnectionDialog) SetDiscoveredInstances(instances []models.DiscoveredI
 	}
 }
 
-// SetHistoryEntries updates the list of connection history entries
+// SetHistoryEntries updates the list of connection history entries, listing
+// pinned entries first and each group most recently used first, or most
+// used first when MostUsed is set
 func (c *ConnectionDialog) SetHistoryEntries(entries []models.ConnectionHistoryEntry) {
-	c.HistoryEntries = entries
+	sorted := make([]models.ConnectionHistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Pinned != sorted[j].Pinned {
+			return sorted[i].Pinned
+		}
+		if c.MostUsed && sorted[i].UsageCount != sorted[j].UsageCount {
+			return sorted[i].UsageCount > sorted[j].UsageCount
+		}
+		return sorted[i].LastUsed.After(sorted[j].LastUsed)
+	})
+	c.HistoryEntries = sorted
 	if c.InHistorySection && c.SelectedIndex >= len(entries) {
 		c.SelectedIndex = 0
 	}
 }
 
+// SelectHistoryEntry selects the history entry with the given ID if it is
+// among the shown entries, so the selection follows it after a re-sort
+func (c *ConnectionDialog) SelectHistoryEntry(id string) {
+	if c.ManualMode || !c.InHistorySection {
+		return
+	}
+	for i, entry := range c.GetFilteredHistory() {
+		if i >= 5 {
+			break // Only 5 history items are shown
+		}
+		if entry.ID == id {
+			c.SelectedIndex = i
+			return
+		}
+	}
+}
+
 func mustParseInt(s string, defaultVal int) int {
 	var result int
 	if _, err := fmt.Sscanf(s, "%d", &result); err != nil {
@@ -0,0 +1,143 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestConnectionDialog_PinnedEntriesFirst(t *testing.T) {
+	now := time.Now()
+	c := NewConnectionDialog(theme.DefaultTheme())
+	c.SetHistoryEntries([]models.ConnectionHistoryEntry{
+		{ID: "recent", Name: "recent", Host: "localhost", LastUsed: now},
+		{ID: "old-pinned", Name: "old-pinned", Host: "prod", LastUsed: now.Add(-48 * time.Hour), Pinned: true},
+		{ID: "older", Name: "older", Host: "localhost", LastUsed: now.Add(-time.Hour)},
+		{ID: "pinned", Name: "pinned", Host: "staging", LastUsed: now.Add(-24 * time.Hour), Pinned: true},
+	})
+
+	var got []string
+	for _, e := range c.HistoryEntries {
+		got = append(got, e.ID)
+	}
+	want := []string{"pinned", "old-pinned", "recent", "older"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("history order = %v, want %v", got, want)
+		}
+	}
+
+	// Search still finds pinned entries
+	c.searchInput.SetValue("prod")
+	filtered := c.GetFilteredHistory()
+	if len(filtered) != 1 || filtered[0].ID != "old-pinned" {
+		t.Errorf("search for prod = %v, want the pinned prod entry", filtered)
+	}
+	c.searchInput.SetValue("")
+
+	c.SelectHistoryEntry("recent")
+	if sel := c.GetSelectedHistory(); sel == nil || sel.ID != "recent" {
+		t.Errorf("SelectHistoryEntry(recent) selected %v", sel)
+	}
+}
+
+func TestConnectionDialog_PrefillFromDiscovered(t *testing.T) {
+	c := NewConnectionDialog(theme.DefaultTheme())
+	c.inputs[databaseField].SetValue("leftover")
+	c.PrefillFromDiscovered(models.DiscoveredInstance{Host: "127.0.0.1", Port: 5433})
+
+	if !c.ManualMode {
+		t.Fatal("dialog did not switch to manual mode")
+	}
+	if c.focusIndex != databaseField {
+		t.Errorf("focus on field %d, want the database field", c.focusIndex)
+	}
+
+	config, err := c.GetManualConfig()
+	if err != nil {
+		t.Fatalf("GetManualConfig() error = %v", err)
+	}
+	if config.Host != "127.0.0.1" || config.Port != 5433 {
+		t.Errorf("host and port = %s:%d, want 127.0.0.1:5433", config.Host, config.Port)
+	}
+	if config.Database != "postgres" {
+		t.Errorf("database = %q, want the placeholder default", config.Database)
+	}
+}
+
+func TestConnectionDialog_SSLMode(t *testing.T) {
+	c := NewConnectionDialog(theme.DefaultTheme())
+	c.ToggleMode()
+
+	// Tab past the password to the SSL mode selector
+	for c.focusIndex != sslModeField {
+		c.NextInput()
+	}
+	c, _ = c.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if got := c.SSLMode(); got != "require" {
+		t.Errorf("SSL mode after → = %q, want require", got)
+	}
+	// Typing doesn't reach a text field while the selector has focus
+	c, _ = c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if c.inputs[passwordField].Value() != "" {
+		t.Error("typing on the SSL mode selector changed the password")
+	}
+
+	// It wraps both ways, and tab wraps back to the host
+	c.CycleSSLMode(-4)
+	if got := c.SSLMode(); got != "verify-full" {
+		t.Errorf("SSL mode = %q, want verify-full after wrapping", got)
+	}
+	c.NextInput()
+	if c.focusIndex != hostField {
+		t.Errorf("focus after the SSL mode = %d, want the host field", c.focusIndex)
+	}
+
+	config, err := c.GetManualConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.SSLMode != "verify-full" {
+		t.Errorf("config SSL mode = %q", config.SSLMode)
+	}
+
+	// Prefilling from a failed connection keeps its mode
+	c.PrefillManual(models.ConnectionConfig{Host: "db", Port: 5432, SSLMode: "disable"})
+	if got := c.SSLMode(); got != "disable" {
+		t.Errorf("SSL mode after prefill = %q, want disable", got)
+	}
+}
+
+func TestConnectionDialog_MostUsedOrder(t *testing.T) {
+	now := time.Now()
+	c := NewConnectionDialog(theme.DefaultTheme())
+	c.MostUsed = true
+	c.SetHistoryEntries([]models.ConnectionHistoryEntry{
+		{ID: "recent", Name: "recent", Host: "localhost", LastUsed: now, UsageCount: 1},
+		{ID: "busy", Name: "busy", Host: "prod", LastUsed: now.Add(-48 * time.Hour), UsageCount: 40},
+		{ID: "pinned", Name: "pinned", Host: "staging", LastUsed: now.Add(-24 * time.Hour), UsageCount: 2, Pinned: true},
+		{ID: "tied", Name: "tied", Host: "prod-replica", LastUsed: now.Add(-time.Hour), UsageCount: 40},
+	})
+
+	var got []string
+	for _, e := range c.HistoryEntries {
+		got = append(got, e.ID)
+	}
+	// Pinned stays on top; equally used entries go most recent first
+	want := []string{"pinned", "tied", "busy", "recent"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("history order = %v, want %v", got, want)
+		}
+	}
+
+	// Search filters within the chosen order
+	c.searchInput.SetValue("prod")
+	filtered := c.GetFilteredHistory()
+	if len(filtered) != 2 || filtered[0].ID != "tied" || filtered[1].ID != "busy" {
+		t.Errorf("search for prod = %v, want tied then busy", filtered)
+	}
+}
@@ -0,0 +1,224 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	zone "github.com/lrstanley/bubblezone"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// ZoneContextMenuItemPrefix is the zone ID prefix of context menu items
+const ZoneContextMenuItemPrefix = "context-menu-item-"
+
+// ContextMenuAction is what a context menu item does to its node
+type ContextMenuAction int
+
+const (
+	ContextMenuOpen ContextMenuAction = iota
+	ContextMenuCopyName
+	ContextMenuCopyDDL
+	ContextMenuRefreshChildren
+	ContextMenuReloadTree
+	ContextMenuViewStats
+	ContextMenuOpenDefinition
+	ContextMenuTruncate
+)
+
+// ContextMenuItem is one entry of a context menu
+type ContextMenuItem struct {
+	Label  string
+	Action ContextMenuAction
+}
+
+// ContextMenuSelectedMsg is sent when a context menu item is chosen
+type ContextMenuSelectedMsg struct {
+	Action ContextMenuAction
+	Node   *models.TreeNode
+}
+
+// CloseContextMenuMsg is sent when the context menu should close without
+// doing anything
+type CloseContextMenuMsg struct{}
+
+// TreeNodeMenuItems returns the context menu items that apply to a tree
+// node. Copy DDL is only offered for objects whose definition lazypg can
+// load, and stats only for relations Postgres keeps table stats for.
+func TreeNodeMenuItems(node *models.TreeNode) []ContextMenuItem {
+	if node == nil {
+		return nil
+	}
+
+	open := ContextMenuItem{"Open", ContextMenuOpen}
+	copyName := ContextMenuItem{"Copy name", ContextMenuCopyName}
+	copyDDL := ContextMenuItem{"Copy DDL", ContextMenuCopyDDL}
+	stats := ContextMenuItem{"View stats", ContextMenuViewStats}
+	definition := ContextMenuItem{"Open definition", ContextMenuOpenDefinition}
+	reload := ContextMenuItem{"Reload tree", ContextMenuReloadTree}
+
+	switch node.Type {
+	case models.TreeNodeTypeTable:
+		return []ContextMenuItem{open, copyName, {"Refresh indexes & triggers", ContextMenuRefreshChildren}, stats,
+			{"Truncate…", ContextMenuTruncate}}
+	case models.TreeNodeTypeMaterializedView:
+		return []ContextMenuItem{open, definition, copyName, copyDDL, stats}
+	case models.TreeNodeTypeView:
+		return []ContextMenuItem{open, definition, copyName, copyDDL}
+	case models.TreeNodeTypeForeignTable:
+		return []ContextMenuItem{open, copyName}
+	case models.TreeNodeTypeFunction, models.TreeNodeTypeProcedure, models.TreeNodeTypeTriggerFunction,
+		models.TreeNodeTypeSequence, models.TreeNodeTypeIndex, models.TreeNodeTypeTrigger,
+		models.TreeNodeTypeExtension, models.TreeNodeTypeCompositeType, models.TreeNodeTypeEnumType,
+		models.TreeNodeTypeDomainType, models.TreeNodeTypeRangeType:
+		return []ContextMenuItem{open, copyName, copyDDL}
+	case models.TreeNodeTypeDatabase, models.TreeNodeTypeSchema:
+		return []ContextMenuItem{copyName, reload}
+	case models.TreeNodeTypeColumn, models.TreeNodeTypeEnumValue:
+		return []ContextMenuItem{copyName}
+	default:
+		// Group folders only have their children
+		return []ContextMenuItem{reload}
+	}
+}
+
+// ContextMenuNodeName is the name Copy name copies: schema-qualified for
+// objects that live in a schema, bare otherwise
+func ContextMenuNodeName(node *models.TreeNode) string {
+	switch node.Type {
+	case models.TreeNodeTypeDatabase, models.TreeNodeTypeSchema:
+		// Labels may carry a count after the name
+		return strings.Split(node.Label, " ")[0]
+	case models.TreeNodeTypeExtension:
+		// Label format: "name vX.Y"
+		if i := strings.Index(node.Label, " v"); i != -1 {
+			return node.Label[:i]
+		}
+		return node.Label
+	case models.TreeNodeTypeTrigger, models.TreeNodeTypeColumn, models.TreeNodeTypeEnumValue:
+		// Not schema-qualified in SQL
+		return node.Label
+	}
+	if schema := models.GetSchemaFromNode(node); schema != "" {
+		return schema + "." + node.Label
+	}
+	return node.Label
+}
+
+// ContextMenu is a small menu of actions on a tree node, drawn over the
+// rest of the UI at the position it was opened at
+type ContextMenu struct {
+	Theme theme.Theme
+
+	Node     *models.TreeNode
+	Items    []ContextMenuItem
+	X, Y     int
+	selected int
+}
+
+// NewContextMenu creates a new context menu
+func NewContextMenu(th theme.Theme) *ContextMenu {
+	return &ContextMenu{Theme: th}
+}
+
+// Open shows the items for node with the menu's top-left corner at x, y.
+// It reports false when nothing applies to the node.
+func (m *ContextMenu) Open(node *models.TreeNode, x, y int) bool {
+	m.Node = node
+	m.Items = TreeNodeMenuItems(node)
+	m.X, m.Y = x, y
+	m.selected = 0
+	return len(m.Items) > 0
+}
+
+// Position returns where to draw the menu so it fits in a screen of the
+// given size, moving it left and up from where it was opened if needed
+func (m *ContextMenu) Position(screenWidth, screenHeight int) (x, y int) {
+	view := m.View()
+	x, y = m.X, m.Y
+	if over := x + lipgloss.Width(view) - screenWidth; over > 0 {
+		x -= over
+	}
+	if over := y + lipgloss.Height(view) - screenHeight; over > 0 {
+		y -= over
+	}
+	return max(x, 0), max(y, 0)
+}
+
+// Update handles keyboard input
+func (m *ContextMenu) Update(msg tea.KeyMsg) (*ContextMenu, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseContextMenuMsg{} }
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "down", "j":
+		if m.selected < len(m.Items)-1 {
+			m.selected++
+		}
+	case "enter":
+		return m, m.choose(m.selected)
+	}
+	return m, nil
+}
+
+// HandleMouse handles mouse input while the menu is open. A click on an
+// item chooses it; a click anywhere else closes the menu.
+func (m *ContextMenu) HandleMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress || tea.MouseEvent(msg).IsWheel() {
+		return nil
+	}
+	if msg.Button == tea.MouseButtonLeft {
+		for i := range m.Items {
+			if zone.Get(fmt.Sprintf("%s%d", ZoneContextMenuItemPrefix, i)).InBounds(msg) {
+				return m.choose(i)
+			}
+		}
+	}
+	return func() tea.Msg { return CloseContextMenuMsg{} }
+}
+
+// choose returns the command for choosing item i
+func (m *ContextMenu) choose(i int) tea.Cmd {
+	if i < 0 || i >= len(m.Items) {
+		return nil
+	}
+	action, node := m.Items[i].Action, m.Node
+	return func() tea.Msg { return ContextMenuSelectedMsg{Action: action, Node: node} }
+}
+
+// View renders the menu
+func (m *ContextMenu) View() string {
+	width := 0
+	for _, item := range m.Items {
+		width = max(width, lipgloss.Width(item.Label))
+	}
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(m.Theme.Foreground).
+		Background(m.Theme.Background).
+		Padding(0, 1).
+		Width(width + 2)
+	selectedStyle := itemStyle.
+		Foreground(m.Theme.Background).
+		Background(m.Theme.BorderFocused).
+		Bold(true)
+
+	lines := make([]string, len(m.Items))
+	for i, item := range m.Items {
+		style := itemStyle
+		if i == m.selected {
+			style = selectedStyle
+		}
+		lines[i] = zone.Mark(fmt.Sprintf("%s%d", ZoneContextMenuItemPrefix, i), style.Render(item.Label))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.Theme.BorderFocused).
+		Render(strings.Join(lines, "\n"))
+}
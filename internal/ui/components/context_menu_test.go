@@ -0,0 +1,124 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func contextMenuTree() (table, function, schema *models.TreeNode) {
+	db := models.NewTreeNode("db:app", models.TreeNodeTypeDatabase, "app")
+	schema = models.NewTreeNode("schema:app.public", models.TreeNodeTypeSchema, "public")
+	db.AddChild(schema)
+	table = models.NewTreeNode("table:app.public.users", models.TreeNodeTypeTable, "users")
+	schema.AddChild(table)
+	function = models.NewTreeNode("function:app.public.add", models.TreeNodeTypeFunction, "add(integer, integer)")
+	schema.AddChild(function)
+	return table, function, schema
+}
+
+func menuActions(items []ContextMenuItem) map[ContextMenuAction]bool {
+	actions := make(map[ContextMenuAction]bool)
+	for _, item := range items {
+		actions[item.Action] = true
+	}
+	return actions
+}
+
+func TestTreeNodeMenuItems(t *testing.T) {
+	table, function, schema := contextMenuTree()
+
+	tableActions := menuActions(TreeNodeMenuItems(table))
+	if !tableActions[ContextMenuViewStats] || !tableActions[ContextMenuRefreshChildren] || !tableActions[ContextMenuTruncate] {
+		t.Errorf("table menu lacks stats, refresh or truncate: %v", TreeNodeMenuItems(table))
+	}
+	if tableActions[ContextMenuCopyDDL] {
+		t.Error("table menu offers Copy DDL, which has no loader for tables")
+	}
+
+	functionActions := menuActions(TreeNodeMenuItems(function))
+	if !functionActions[ContextMenuCopyDDL] || functionActions[ContextMenuViewStats] {
+		t.Errorf("function menu = %v, want Copy DDL and no stats", TreeNodeMenuItems(function))
+	}
+
+	view := models.NewTreeNode("view:app.public.active_users", models.TreeNodeTypeView, "active_users")
+	schema.AddChild(view)
+	viewActions := menuActions(TreeNodeMenuItems(view))
+	if !viewActions[ContextMenuOpen] || !viewActions[ContextMenuOpenDefinition] || !viewActions[ContextMenuCopyDDL] {
+		t.Errorf("view menu = %v, want Open, Open definition and Copy DDL", TreeNodeMenuItems(view))
+	}
+	if viewActions[ContextMenuTruncate] {
+		t.Error("view menu offers Truncate")
+	}
+
+	if menuActions(TreeNodeMenuItems(schema))[ContextMenuOpen] {
+		t.Error("schema menu offers Open")
+	}
+
+	if got := ContextMenuNodeName(table); got != "public.users" {
+		t.Errorf("ContextMenuNodeName(table) = %q, want public.users", got)
+	}
+	if got := ContextMenuNodeName(schema); got != "public" {
+		t.Errorf("ContextMenuNodeName(schema) = %q, want public", got)
+	}
+}
+
+func TestContextMenuKeys(t *testing.T) {
+	table, _, _ := contextMenuTree()
+	m := NewContextMenu(theme.DefaultTheme())
+	if !m.Open(table, 10, 5) {
+		t.Fatal("Open() found no items for a table")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter returned no command")
+	}
+	if got, want := cmd(), (ContextMenuSelectedMsg{Action: ContextMenuCopyName, Node: table}); got != want {
+		t.Errorf("enter sent %#v, want %#v", got, want)
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil || cmd() != (CloseContextMenuMsg{}) {
+		t.Error("esc did not close the menu")
+	}
+}
+
+func TestContextMenuMouse(t *testing.T) {
+	table, _, _ := contextMenuTree()
+	m := NewContextMenu(theme.DefaultTheme())
+	m.Open(table, 10, 5)
+
+	// Releases and wheel events leave the menu open
+	if cmd := m.HandleMouse(tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease}); cmd != nil {
+		t.Error("a button release closed the menu")
+	}
+	if cmd := m.HandleMouse(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress}); cmd != nil {
+		t.Error("a wheel event closed the menu")
+	}
+
+	// A click outside any item closes it
+	cmd := m.HandleMouse(tea.MouseMsg{X: 200, Y: 200, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if cmd == nil || cmd() != (CloseContextMenuMsg{}) {
+		t.Error("an outside click did not close the menu")
+	}
+}
+
+func TestContextMenuPosition(t *testing.T) {
+	table, _, _ := contextMenuTree()
+	m := NewContextMenu(theme.DefaultTheme())
+	m.Open(table, 75, 22)
+
+	x, y := m.Position(80, 24)
+	view := m.View()
+	if x+lipgloss.Width(view) > 80 || y+lipgloss.Height(view) > 24 {
+		t.Errorf("menu at %d,%d does not fit an 80x24 screen", x, y)
+	}
+	if x, y := m.Position(200, 100); x != 75 || y != 22 {
+		t.Errorf("Position() with room = %d,%d, want 75,22", x, y)
+	}
+}
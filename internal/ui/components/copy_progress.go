@@ -0,0 +1,183 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// indeterminateSegment is the width of the sliding block shown when the
+// total is unknown
+const indeterminateSegment = 8
+
+// CopyProgress renders the progress of a streaming COPY: rows, bytes and
+// elapsed time. With a known row total it shows a filled bar; otherwise a
+// block slides across the bar until the copy finishes. The CSV import is
+// the only COPY there is; it feeds the bar from metadata.CopyProgress
+// reports, as any other COPY would.
+type CopyProgress struct {
+	Width int
+	Theme theme.Theme
+
+	bar       progress.Model
+	total     int64 // Expected rows (0 = unknown)
+	rows      int64
+	bytes     int64
+	start     time.Time
+	stopped   time.Time // Set when the copy is cancelled
+	cancelled bool
+}
+
+// NewCopyProgress creates a new COPY progress bar
+func NewCopyProgress(th theme.Theme) *CopyProgress {
+	return &CopyProgress{
+		Width: 60,
+		Theme: th,
+		bar:   progress.New(progress.WithSolidFill(string(th.Info)), progress.WithoutPercentage()),
+	}
+}
+
+// Start resets the bar for a new copy. total is the expected number of
+// rows, or 0 if it is not known.
+func (p *CopyProgress) Start(total int64) {
+	p.total = total
+	p.rows = 0
+	p.bytes = 0
+	p.start = time.Now()
+	p.stopped = time.Time{}
+	p.cancelled = false
+	p.bar.FullColor = string(p.Theme.Info)
+}
+
+// SetProgress records how many rows and bytes have been copied so far
+func (p *CopyProgress) SetProgress(rows, bytes int64) {
+	if p.cancelled {
+		return
+	}
+	p.rows = rows
+	p.bytes = bytes
+}
+
+// Cancel freezes the bar where the copy stopped
+func (p *CopyProgress) Cancel() {
+	if p.cancelled {
+		return
+	}
+	p.cancelled = true
+	p.stopped = time.Now()
+	p.bar.FullColor = string(p.Theme.Warning)
+}
+
+// Cancelled reports whether the copy was cancelled
+func (p *CopyProgress) Cancelled() bool {
+	return p.cancelled
+}
+
+// Percent returns the fraction of rows copied, or -1 if the total is unknown
+func (p *CopyProgress) Percent() float64 {
+	if p.total <= 0 {
+		return -1
+	}
+	pct := float64(p.rows) / float64(p.total)
+	if pct > 1 {
+		pct = 1
+	}
+	return pct
+}
+
+// elapsed returns the time since the copy started, stopping at cancellation
+func (p *CopyProgress) elapsed() time.Duration {
+	end := time.Now()
+	if p.cancelled {
+		end = p.stopped
+	}
+	return end.Sub(p.start)
+}
+
+// View renders the bar and a status line
+func (p *CopyProgress) View() string {
+	barWidth := p.Width
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	p.bar.Width = barWidth
+
+	var bar string
+	if pct := p.Percent(); pct >= 0 {
+		bar = p.bar.ViewAs(pct)
+	} else {
+		bar = p.indeterminateView(barWidth)
+	}
+
+	rows := FormatCount(p.rows)
+	if p.total > 0 {
+		rows = fmt.Sprintf("%s / %s", rows, FormatCount(p.total))
+	}
+	status := fmt.Sprintf("%s rows  •  %s  •  %s", rows, formatBytes(p.bytes), p.elapsed().Round(100*time.Millisecond))
+	if pct := p.Percent(); pct >= 0 {
+		status = fmt.Sprintf("%3.0f%%  ", pct*100) + status
+	}
+
+	statusStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+	if p.cancelled {
+		statusStyle = lipgloss.NewStyle().Foreground(p.Theme.Warning)
+		status = "Cancelled  •  " + status
+	}
+
+	return bar + "\n" + statusStyle.Render(status)
+}
+
+// indeterminateView renders a block sliding back and forth across the bar
+func (p *CopyProgress) indeterminateView(width int) string {
+	segment := indeterminateSegment
+	if segment > width {
+		segment = width
+	}
+	travel := width - segment
+
+	pos := 0
+	if travel > 0 {
+		// Advance one cell every 60ms, bouncing at the ends
+		step := int(p.elapsed() / (60 * time.Millisecond))
+		pos = step % (2 * travel)
+		if pos > travel {
+			pos = 2*travel - pos
+		}
+	}
+
+	fill := lipgloss.NewStyle().Foreground(lipgloss.Color(p.bar.FullColor))
+	empty := lipgloss.NewStyle().Foreground(lipgloss.Color(p.bar.EmptyColor))
+	return empty.Render(strings.Repeat(string(p.bar.Empty), pos)) +
+		fill.Render(strings.Repeat(string(p.bar.Full), segment)) +
+		empty.Render(strings.Repeat(string(p.bar.Empty), travel-pos))
+}
+
+// FormatCount formats a number with thousands separators
+func FormatCount(n int64) string {
+	s := fmt.Sprintf("%d", n)
+	if n < 0 {
+		return s
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatBytes formats a byte count using binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
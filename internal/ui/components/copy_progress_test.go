@@ -0,0 +1,63 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestCopyProgressPercent(t *testing.T) {
+	p := NewCopyProgress(theme.DefaultTheme())
+	p.Start(200)
+	p.SetProgress(50, 1024)
+	if got := p.Percent(); got != 0.25 {
+		t.Errorf("Percent() = %v, want 0.25", got)
+	}
+	if view := p.View(); !strings.Contains(view, "50 / 200 rows") || !strings.Contains(view, "1.0 KiB") {
+		t.Errorf("View() missing totals:\n%s", view)
+	}
+
+	// An unknown total switches to the indeterminate bar
+	p.Start(0)
+	p.SetProgress(5000, 10)
+	if got := p.Percent(); got != -1 {
+		t.Errorf("Percent() with unknown total = %v, want -1", got)
+	}
+	if view := p.View(); !strings.Contains(view, "5,000 rows") || strings.Contains(view, "%") {
+		t.Errorf("indeterminate View() = \n%s", view)
+	}
+}
+
+func TestCopyProgressCancelFreezes(t *testing.T) {
+	p := NewCopyProgress(theme.DefaultTheme())
+	p.Start(100)
+	p.SetProgress(40, 400)
+	p.Cancel()
+	p.SetProgress(90, 900)
+
+	if !p.Cancelled() {
+		t.Fatal("Cancelled() = false after Cancel")
+	}
+	if got := p.Percent(); got != 0.4 {
+		t.Errorf("Percent() after cancel = %v, want 0.4", got)
+	}
+	if view := p.View(); !strings.Contains(view, "Cancelled") {
+		t.Errorf("View() should show the cancellation:\n%s", view)
+	}
+}
+
+func TestFormatCountAndBytes(t *testing.T) {
+	counts := map[int64]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567"}
+	for n, want := range counts {
+		if got := FormatCount(n); got != want {
+			t.Errorf("FormatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+	bytes := map[int64]string{512: "512 B", 1536: "1.5 KiB", 3 * 1024 * 1024: "3.0 MiB"}
+	for n, want := range bytes {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// CSVImportStep represents the current step of the import dialog
+type CSVImportStep int
+
+const (
+	CSVImportStepFile CSVImportStep = iota
+	CSVImportStepMapping
+)
+
+// CSVFileSelectedMsg is sent when the user has entered a CSV file path
+type CSVFileSelectedMsg struct {
+	Path string
+}
+
+// CSVImportMsg is sent when the user confirms (or dry-runs) an import
+type CSVImportMsg struct {
+	Schema    string
+	Table     string
+	Records   [][]string
+	Columns   []string
+	Mapping   []int
+	HasHeader bool
+	DryRun    bool
+}
+
+// CloseCSVImportDialogMsg is sent when the import dialog should close
+type CloseCSVImportDialogMsg struct{}
+
+// CancelCSVImportMsg is sent when the user cancels a running import
+type CancelCSVImportMsg struct{}
+
+// CSVImportDialog lets the user pick a CSV file and map its columns onto a table
+type CSVImportDialog struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	step      CSVImportStep
+	pathInput textinput.Model
+
+	// Target table
+	schema  string
+	table   string
+	columns []string
+
+	// Parsed CSV
+	records   [][]string
+	hasHeader bool
+	mapping   []int // CSV column index per target column (-1 = skip)
+	selected  int
+
+	// Status line (dry-run result or error)
+	status      string
+	statusError bool
+	running     bool
+
+	// Progress of a running import; kept on screen after a cancellation
+	progress     *CopyProgress
+	showProgress bool
+
+	// Cached styles for rendering
+	cachedStyles *csvImportDialogStyles
+}
+
+// csvImportDialogStyles holds pre-computed styles for CSVImportDialog
+// rendering
+type csvImportDialogStyles struct {
+	title        lipgloss.Style
+	instructions lipgloss.Style
+	metadata     lipgloss.Style
+	sample       lipgloss.Style
+	row          lipgloss.Style
+	selectedRow  lipgloss.Style
+	success      lipgloss.Style
+	error        lipgloss.Style
+	container    lipgloss.Style
+}
+
+// NewCSVImportDialog creates a new CSV import dialog
+func NewCSVImportDialog(th theme.Theme) *CSVImportDialog {
+	ti := textinput.New()
+	ti.Placeholder = "/path/to/file.csv"
+	ti.CharLimit = 512
+	ti.Width = 60
+
+	d := &CSVImportDialog{
+		Width:     80,
+		Height:    24,
+		Theme:     th,
+		pathInput: ti,
+		progress:  NewCopyProgress(th),
+	}
+	d.initStyles()
+	return d
+}
+
+// initStyles initializes cached styles for rendering performance
+func (d *CSVImportDialog) initStyles() {
+	status := lipgloss.NewStyle().Padding(1, 1, 0, 1)
+	d.cachedStyles = &csvImportDialogStyles{
+		title: lipgloss.NewStyle().
+			Foreground(d.Theme.Foreground).
+			Background(d.Theme.Info).
+			Padding(0, 1).
+			Bold(true),
+		instructions: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#a6adc8")).
+			Padding(0, 1),
+		metadata:    lipgloss.NewStyle().Foreground(d.Theme.Metadata),
+		sample:      lipgloss.NewStyle().Foreground(d.Theme.Metadata).Italic(true),
+		row:         lipgloss.NewStyle().Padding(0, 1),
+		selectedRow: lipgloss.NewStyle().Padding(0, 1).Background(d.Theme.Selection).Foreground(d.Theme.Foreground),
+		success:     status.Foreground(d.Theme.Success),
+		error:       status.Foreground(d.Theme.Error),
+		container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(d.Theme.Border).
+			Padding(1),
+	}
+}
+
+// Open resets the dialog for importing into the given table
+func (d *CSVImportDialog) Open(schema, table string, columns []string) {
+	d.schema = schema
+	d.table = table
+	d.columns = columns
+	d.step = CSVImportStepFile
+	d.records = nil
+	d.mapping = nil
+	d.selected = 0
+	d.status = ""
+	d.statusError = false
+	d.running = false
+	d.showProgress = false
+	d.pathInput.Focus()
+}
+
+// SetRecords loads parsed CSV records and the initial column mapping
+func (d *CSVImportDialog) SetRecords(records [][]string, hasHeader bool, mapping []int) {
+	d.records = records
+	d.hasHeader = hasHeader
+	d.mapping = mapping
+	d.selected = 0
+	d.step = CSVImportStepMapping
+	d.pathInput.Blur()
+	d.status = ""
+	d.statusError = false
+	d.showProgress = false
+}
+
+// Columns returns the target table columns
+func (d *CSVImportDialog) Columns() []string {
+	return d.columns
+}
+
+// SetStatus shows a status line; isError renders it as an error
+func (d *CSVImportDialog) SetStatus(status string, isError bool) {
+	d.status = status
+	d.statusError = isError
+	d.running = false
+	d.showProgress = d.progress.Cancelled()
+}
+
+// SetRunning marks an import or dry run as in progress
+func (d *CSVImportDialog) SetRunning() {
+	d.running = true
+	d.status = ""
+	d.statusError = false
+	d.progress.Start(int64(d.dataRowCount()))
+	d.showProgress = true
+}
+
+// SetProgress updates the progress of a running import
+func (d *CSVImportDialog) SetProgress(rows, bytes int64) {
+	if d.running {
+		d.progress.SetProgress(rows, bytes)
+	}
+}
+
+// dataRowCount returns the number of records that will be imported
+func (d *CSVImportDialog) dataRowCount() int {
+	if d.hasHeader && len(d.records) > 0 {
+		return len(d.records) - 1
+	}
+	return len(d.records)
+}
+
+// csvColumnCount returns the widest record's column count
+func (d *CSVImportDialog) csvColumnCount() int {
+	n := 0
+	for _, r := range d.records {
+		if len(r) > n {
+			n = len(r)
+		}
+	}
+	return n
+}
+
+// csvColumnLabel returns a display label for a CSV column
+func (d *CSVImportDialog) csvColumnLabel(idx int) string {
+	if idx < 0 {
+		return "(skip)"
+	}
+	if d.hasHeader && len(d.records) > 0 && idx < len(d.records[0]) {
+		return d.records[0][idx]
+	}
+	return fmt.Sprintf("column %d", idx+1)
+}
+
+// sampleValue returns the first data row's value for a CSV column
+func (d *CSVImportDialog) sampleValue(idx int) string {
+	row := 0
+	if d.hasHeader {
+		row = 1
+	}
+	if idx < 0 || row >= len(d.records) || idx >= len(d.records[row]) {
+		return ""
+	}
+	return d.records[row][idx]
+}
+
+// Update handles keyboard input
+func (d *CSVImportDialog) Update(msg tea.Msg) (*CSVImportDialog, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if d.step == CSVImportStepFile {
+		if ok {
+			switch keyMsg.String() {
+			case "esc":
+				return d, func() tea.Msg { return CloseCSVImportDialogMsg{} }
+			case "enter":
+				path := strings.TrimSpace(d.pathInput.Value())
+				if path == "" {
+					return d, nil
+				}
+				return d, func() tea.Msg { return CSVFileSelectedMsg{Path: path} }
+			}
+		}
+		var cmd tea.Cmd
+		d.pathInput, cmd = d.pathInput.Update(msg)
+		return d, cmd
+	}
+
+	if !ok {
+		return d, nil
+	}
+	if d.running {
+		if keyMsg.String() == "esc" && !d.progress.Cancelled() {
+			d.progress.Cancel()
+			return d, func() tea.Msg { return CancelCSVImportMsg{} }
+		}
+		return d, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		d.step = CSVImportStepFile
+		d.pathInput.Focus()
+		d.status = ""
+	case "up", "k":
+		if d.selected > 0 {
+			d.selected--
+		}
+	case "down", "j":
+		if d.selected < len(d.columns)-1 {
+			d.selected++
+		}
+	case "left", "h":
+		d.cycleMapping(-1)
+	case "right", "l":
+		d.cycleMapping(1)
+	case "x":
+		if d.selected < len(d.mapping) {
+			d.mapping[d.selected] = -1
+		}
+	case "H":
+		// Toggling the header changes how columns are matched, so remap
+		d.hasHeader = !d.hasHeader
+		if len(d.records) > 0 {
+			d.mapping = metadata.MapCSVColumns(d.records[0], d.columns, d.hasHeader)
+		}
+		d.status = ""
+	case "d":
+		return d, d.importCmd(true)
+	case "enter":
+		return d, d.importCmd(false)
+	}
+	return d, nil
+}
+
+// cycleMapping moves the selected column's source through the CSV columns
+func (d *CSVImportDialog) cycleMapping(delta int) {
+	if d.selected >= len(d.mapping) {
+		return
+	}
+	n := d.csvColumnCount()
+	// Values range from -1 (skip) to n-1
+	next := d.mapping[d.selected] + delta
+	if next < -1 {
+		next = n - 1
+	} else if next >= n {
+		next = -1
+	}
+	d.mapping[d.selected] = next
+}
+
+// importCmd returns a command that requests the import
+func (d *CSVImportDialog) importCmd(dryRun bool) tea.Cmd {
+	mapping := make([]int, len(d.mapping))
+	copy(mapping, d.mapping)
+	msg := CSVImportMsg{
+		Schema:    d.schema,
+		Table:     d.table,
+		Records:   d.records,
+		Columns:   d.columns,
+		Mapping:   mapping,
+		HasHeader: d.hasHeader,
+		DryRun:    dryRun,
+	}
+	return func() tea.Msg { return msg }
+}
+
+// View renders the dialog
+func (d *CSVImportDialog) View() string {
+	var sections []string
+
+	styles := d.cachedStyles
+	sections = append(sections, styles.title.Render(fmt.Sprintf("Import CSV into %s.%s", d.schema, d.table)))
+
+	instrStyle := styles.instructions
+
+	if d.step == CSVImportStepFile {
+		sections = append(sections, instrStyle.Render("Enter: Load file  Esc: Cancel"))
+		sections = append(sections, "")
+		sections = append(sections, "File: "+d.pathInput.View())
+	} else if d.running {
+		sections = append(sections, instrStyle.Render("Esc: Cancel import"))
+	} else {
+		sections = append(sections, instrStyle.Render("↑↓: Column  ←→: Source  x: Skip  H: Header  d: Dry run  Enter: Import  Esc: Back"))
+		sections = append(sections, "")
+
+		headerState := "no"
+		if d.hasHeader {
+			headerState = "yes"
+		}
+		sections = append(sections, styles.metadata.Render(fmt.Sprintf("%d rows to import  •  header row: %s", d.dataRowCount(), headerState)))
+		sections = append(sections, "")
+
+		nameWidth := 0
+		for _, col := range d.columns {
+			if len(col) > nameWidth {
+				nameWidth = len(col)
+			}
+		}
+
+		visible := d.Height - 12
+		if visible < 3 {
+			visible = 3
+		}
+		start := 0
+		if d.selected >= visible {
+			start = d.selected - visible + 1
+		}
+		end := start + visible
+		if end > len(d.columns) {
+			end = len(d.columns)
+		}
+
+		for i := start; i < end; i++ {
+			src := -1
+			if i < len(d.mapping) {
+				src = d.mapping[i]
+			}
+			// Truncate by display width so multi-byte text isn't split
+			sample := runewidth.Truncate(d.sampleValue(src), 24, "...")
+			line := fmt.Sprintf("%-*s  ←  %s", nameWidth, d.columns[i], d.csvColumnLabel(src))
+			if sample != "" {
+				line += "  " + styles.sample.Render(sample)
+			}
+
+			style := styles.row
+			if i == d.selected {
+				style = styles.selectedRow
+			}
+			sections = append(sections, style.Render(line))
+		}
+	}
+
+	if d.showProgress {
+		d.progress.Width = d.Width - 6
+		sections = append(sections, "", d.progress.View())
+	}
+
+	if d.status != "" {
+		statusStyle := styles.success
+		if d.statusError {
+			statusStyle = styles.error
+		}
+		sections = append(sections, statusStyle.Render(d.status))
+	}
+
+	return styles.container.Width(d.Width).Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,23 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestCSVImportDialog_SampleKeepsRunesWhole(t *testing.T) {
+	d := NewCSVImportDialog(theme.DefaultTheme())
+	d.Open("public", "notes", []string{"body"})
+	d.SetRecords([][]string{{strings.Repeat("日本語", 10)}}, false, []int{0})
+
+	view := d.View()
+	if !utf8.ValidString(view) {
+		t.Fatal("the sample was cut inside a character")
+	}
+	if !strings.Contains(view, "日本語日本語日本語日...") {
+		t.Errorf("the sample should be cut to 24 columns:\n%s", view)
+	}
+}
@@ -0,0 +1,63 @@
+package components
+
+// DiffOp is the kind of change a diff line represents
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffAdded
+	DiffRemoved
+)
+
+// DiffLine is a single line of a unified diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// DiffLines computes a line-by-line unified diff between two texts using
+// the longest common subsequence of lines
+func DiffLines(original, modified []string) []DiffLine {
+	n, m := len(original), len(modified)
+
+	// lcs[i][j] = length of LCS of original[i:] and modified[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if original[i] == modified[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var result []DiffLine
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case original[i] == modified[j]:
+			result = append(result, DiffLine{Op: DiffEqual, Text: original[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, DiffLine{Op: DiffRemoved, Text: original[i]})
+			i++
+		default:
+			result = append(result, DiffLine{Op: DiffAdded, Text: modified[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, DiffLine{Op: DiffRemoved, Text: original[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, DiffLine{Op: DiffAdded, Text: modified[j]})
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	original := []string{"BEGIN", "  RETURN 1;", "END;"}
+	modified := []string{"BEGIN", "  PERFORM log();", "  RETURN 2;", "END;"}
+
+	got := DiffLines(original, modified)
+	want := []DiffLine{
+		{Op: DiffEqual, Text: "BEGIN"},
+		{Op: DiffRemoved, Text: "  RETURN 1;"},
+		{Op: DiffAdded, Text: "  PERFORM log();"},
+		{Op: DiffAdded, Text: "  RETURN 2;"},
+		{Op: DiffEqual, Text: "END;"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffLines() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffLinesIdentical(t *testing.T) {
+	lines := []string{"SELECT 1;"}
+	for _, dl := range DiffLines(lines, lines) {
+		if dl.Op != DiffEqual {
+			t.Errorf("expected only equal lines, got %+v", dl)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// DryRunBadge marks the top bar while dry run is on
+const DryRunBadge = "DRY RUN"
+
+// CloseDryRunMsg is sent when the dry run dialog should close
+type CloseDryRunMsg struct{}
+
+// DryRunDialog shows a generated statement that dry run kept from running,
+// exactly as it would have run, so it can be read and copied
+type DryRunDialog struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	Action string // What the statement would have done
+	SQL    string
+	Status string
+}
+
+// NewDryRunDialog creates a new dry run dialog
+func NewDryRunDialog(th theme.Theme) *DryRunDialog {
+	return &DryRunDialog{
+		Width:  80,
+		Height: 24,
+		Theme:  th,
+	}
+}
+
+// Open shows sql, which would have done action
+func (d *DryRunDialog) Open(action, sql string) {
+	d.Action = action
+	d.SQL = strings.TrimSpace(sql)
+	d.Status = ""
+}
+
+// Update handles keyboard input
+func (d *DryRunDialog) Update(msg tea.KeyMsg) (*DryRunDialog, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "enter":
+		return d, func() tea.Msg { return CloseDryRunMsg{} }
+	case "y":
+		if err := clipboard.WriteAll(d.SQL); err != nil {
+			d.Status = "Copy failed: " + err.Error()
+		} else {
+			d.Status = "Copied the statement"
+		}
+	}
+	return d, nil
+}
+
+// View renders the dialog
+func (d *DryRunDialog) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(d.Theme.Background).
+		Background(d.Theme.Warning).
+		Padding(0, 1).
+		Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(d.Theme.Foreground)
+	sqlStyle := lipgloss.NewStyle().Foreground(d.Theme.Info)
+	keyStyle := lipgloss.NewStyle().Foreground(d.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(d.Theme.Metadata)
+
+	textWidth := d.Width - 4 // Border and padding
+	sqlLines := strings.Split(wrapText(d.SQL, textWidth), "\n")
+	if maxLines := max(d.Height-14, 3); len(sqlLines) > maxLines {
+		sqlLines = append(sqlLines[:maxLines-1], metaStyle.Render("… (y copies it all)"))
+	}
+
+	sections := []string{
+		titleStyle.Render("Dry Run: Not Executed"),
+		"",
+		textStyle.Render(wrapText(d.Action+". Dry run is on, so this statement was not sent:", textWidth)),
+		"",
+		sqlStyle.Render(strings.Join(sqlLines, "\n")),
+	}
+	if d.Status != "" {
+		sections = append(sections, "", lipgloss.NewStyle().Foreground(d.Theme.Success).Render(d.Status))
+	}
+	sections = append(sections, "",
+		keyStyle.Render("y")+metaStyle.Render(": Copy   ")+
+			keyStyle.Render("Esc")+metaStyle.Render(": Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(d.Theme.Warning).
+		Width(d.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
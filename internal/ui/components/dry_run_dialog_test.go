@@ -0,0 +1,32 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestDryRunDialog(t *testing.T) {
+	d := NewDryRunDialog(theme.DefaultTheme())
+	d.Open("Would update 1 row of public.users", "\nUPDATE public.users\nSET name = 'Ann'\nWHERE id = 1;\n")
+
+	if d.SQL != "UPDATE public.users\nSET name = 'Ann'\nWHERE id = 1;" {
+		t.Errorf("SQL = %q, want the statement trimmed", d.SQL)
+	}
+	view := d.View()
+	for _, want := range []string{"Dry Run", "public.users", "SET name = 'Ann'"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() is missing %q:\n%s", want, view)
+		}
+	}
+
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc did not close the dialog")
+	}
+	if _, ok := cmd().(CloseDryRunMsg); !ok {
+		t.Errorf("Esc sent %#v, want CloseDryRunMsg", cmd())
+	}
+}
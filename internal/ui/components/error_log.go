@@ -0,0 +1,287 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// maxErrorLogEntries caps the error log; the oldest errors are dropped first
+const maxErrorLogEntries = 100
+
+// CloseErrorLogMsg is sent when the error log should close
+type CloseErrorLogMsg struct{}
+
+// ErrorKind tells connection errors from query errors in the error log
+type ErrorKind int
+
+const (
+	ErrorKindOther ErrorKind = iota
+	ErrorKindConnection
+	ErrorKindQuery
+)
+
+// Label returns the short name shown next to an error
+func (k ErrorKind) Label() string {
+	switch k {
+	case ErrorKindConnection:
+		return "connection"
+	case ErrorKindQuery:
+		return "query"
+	}
+	return "other"
+}
+
+// sqlState matches the SQLSTATE pgx appends to server errors
+var sqlState = regexp.MustCompile(`\(SQLSTATE ([0-9A-Z]{5})\)`)
+
+// connectionErrorHints are phrases of errors reaching or talking to the server
+var connectionErrorHints = []string{
+	"connection", "connect to", "not connected", "dial tcp", "no such host",
+	"i/o timeout", "broken pipe", "conn closed", "password authentication",
+	"server closed", "switch database",
+}
+
+// ClassifyError tells whether an error came from reaching the server or from
+// a statement it ran. Server errors are told apart by their SQLSTATE:
+// classes 08 (connection exception), 28 (invalid authorization) and 57P
+// (server shutting down) are connection errors, the rest query errors.
+func ClassifyError(title, message string) ErrorKind {
+	if m := sqlState.FindStringSubmatch(message); m != nil {
+		code := m[1]
+		if strings.HasPrefix(code, "08") || strings.HasPrefix(code, "28") || strings.HasPrefix(code, "57P") {
+			return ErrorKindConnection
+		}
+		return ErrorKindQuery
+	}
+
+	text := strings.ToLower(title + "\n" + message)
+	for _, hint := range connectionErrorHints {
+		if strings.Contains(text, hint) {
+			return ErrorKindConnection
+		}
+	}
+	if strings.Contains(strings.ToLower(title), "query") {
+		return ErrorKindQuery
+	}
+	return ErrorKindOther
+}
+
+// ErrorLogEntry is an error as it was shown
+type ErrorLogEntry struct {
+	Time    time.Time
+	Title   string
+	Message string
+	Kind    ErrorKind
+}
+
+// ErrorLog keeps the errors shown in this session, so one dismissed too
+// fast can be read again. It lists the newest first, with the selected
+// error's message in full below the list.
+type ErrorLog struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	entries  []ErrorLogEntry // Oldest first
+	selected int             // Index into the visible list, newest first
+	offset   int             // First visible list line
+	kind     *ErrorKind      // Only show errors of this kind
+	Status   string
+}
+
+// NewErrorLog creates an empty error log
+func NewErrorLog(th theme.Theme) *ErrorLog {
+	return &ErrorLog{
+		Width:  80,
+		Height: 24,
+		Theme:  th,
+	}
+}
+
+// Add records an error, dropping the oldest once the log is full
+func (l *ErrorLog) Add(title, message string, at time.Time) {
+	l.entries = append(l.entries, ErrorLogEntry{
+		Time:    at,
+		Title:   title,
+		Message: message,
+		Kind:    ClassifyError(title, message),
+	})
+	if len(l.entries) > maxErrorLogEntries {
+		l.entries = l.entries[len(l.entries)-maxErrorLogEntries:]
+	}
+}
+
+// Len returns the number of errors in the log
+func (l *ErrorLog) Len() int {
+	return len(l.entries)
+}
+
+// Open selects the newest error
+func (l *ErrorLog) Open() {
+	l.selected = 0
+	l.offset = 0
+	l.Status = ""
+}
+
+// Visible returns the errors passing the kind filter, newest first
+func (l *ErrorLog) Visible() []ErrorLogEntry {
+	var visible []ErrorLogEntry
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if l.kind == nil || l.entries[i].Kind == *l.kind {
+			visible = append(visible, l.entries[i])
+		}
+	}
+	return visible
+}
+
+// cycleKind steps the filter through all, connection, query and other errors
+func (l *ErrorLog) cycleKind() {
+	switch {
+	case l.kind == nil:
+		k := ErrorKindConnection
+		l.kind = &k
+	case *l.kind == ErrorKindConnection:
+		k := ErrorKindQuery
+		l.kind = &k
+	case *l.kind == ErrorKindQuery:
+		k := ErrorKindOther
+		l.kind = &k
+	default:
+		l.kind = nil
+	}
+	l.selected = 0
+	l.offset = 0
+}
+
+// listLines returns how many errors the list shows at once
+func (l *ErrorLog) listLines() int {
+	return max(3, (l.Height-12)/2)
+}
+
+// Update handles keyboard input
+func (l *ErrorLog) Update(msg tea.KeyMsg) (*ErrorLog, tea.Cmd) {
+	visible := l.Visible()
+	l.Status = ""
+	switch msg.String() {
+	case "esc", "q":
+		return l, func() tea.Msg { return CloseErrorLogMsg{} }
+	case "up", "k":
+		if l.selected > 0 {
+			l.selected--
+		}
+	case "down", "j":
+		if l.selected < len(visible)-1 {
+			l.selected++
+		}
+	case "g", "home":
+		l.selected = 0
+	case "G", "end":
+		l.selected = max(len(visible)-1, 0)
+	case "tab":
+		l.cycleKind()
+		return l, nil
+	case "y":
+		if l.selected < len(visible) {
+			e := visible[l.selected]
+			if err := clipboard.WriteAll(e.Title + "\n\n" + e.Message); err != nil {
+				l.Status = "Copy failed: " + err.Error()
+			} else {
+				l.Status = "Copied the error"
+			}
+		}
+	case "c":
+		l.entries = nil
+		l.selected = 0
+	}
+
+	// Keep the selection in view
+	lines := l.listLines()
+	if l.selected < l.offset {
+		l.offset = l.selected
+	} else if l.selected >= l.offset+lines {
+		l.offset = l.selected - lines + 1
+	}
+	return l, nil
+}
+
+// View renders the error log
+func (l *ErrorLog) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(l.Theme.Background).
+		Background(l.Theme.Error).
+		Padding(0, 1).
+		Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(l.Theme.Metadata)
+	selectedStyle := lipgloss.NewStyle().Background(l.Theme.Selection).Bold(true)
+	kindStyles := map[ErrorKind]lipgloss.Style{
+		ErrorKindConnection: lipgloss.NewStyle().Foreground(l.Theme.Warning),
+		ErrorKindQuery:      lipgloss.NewStyle().Foreground(l.Theme.Error),
+		ErrorKindOther:      metaStyle,
+	}
+
+	filter := "all"
+	if l.kind != nil {
+		filter = l.kind.Label()
+	}
+	sections := []string{
+		titleStyle.Render("Recent Errors"),
+		metaStyle.Render(fmt.Sprintf("Showing: %s errors · the last %d are kept", filter, maxErrorLogEntries)),
+		"",
+	}
+
+	visible := l.Visible()
+	if len(visible) == 0 {
+		sections = append(sections, metaStyle.Render("No errors"))
+	} else {
+		end := min(l.offset+l.listLines(), len(visible))
+		for i := l.offset; i < end; i++ {
+			e := visible[i]
+			kind := fmt.Sprintf("%-10s", e.Kind.Label())
+			line := fmt.Sprintf("%s  %s  %s", e.Time.Format("15:04:05"), kind, e.Title)
+			line = truncateToWidth(line, l.Width-4)
+			if i == l.selected {
+				sections = append(sections, selectedStyle.Render(line))
+			} else {
+				sections = append(sections, metaStyle.Render(e.Time.Format("15:04:05"))+"  "+
+					kindStyles[e.Kind].Render(kind)+"  "+truncateToWidth(e.Title, l.Width-28))
+			}
+		}
+		if l.offset > 0 || end < len(visible) {
+			sections = append(sections, metaStyle.Render(fmt.Sprintf("%d-%d of %d", l.offset+1, end, len(visible))))
+		}
+
+		// The selected error in full
+		if l.selected < len(visible) {
+			e := visible[l.selected]
+			message := wrapText(e.Message, l.Width-6)
+			if lines := strings.Split(message, "\n"); len(lines) > l.Height-l.listLines()-14 {
+				keep := max(l.Height-l.listLines()-15, 1)
+				message = strings.Join(lines[:keep], "\n") + "\n" + metaStyle.Render("… (y copies it all)")
+			}
+			sections = append(sections, "",
+				lipgloss.NewStyle().Foreground(l.Theme.Error).Bold(true).Render(e.Title)+
+					metaStyle.Render("  "+e.Time.Format("2006-01-02 15:04:05")),
+				message)
+		}
+	}
+
+	if l.Status != "" {
+		sections = append(sections, "", lipgloss.NewStyle().Foreground(l.Theme.Success).Render(l.Status))
+	}
+	sections = append(sections, "",
+		metaStyle.Render("↑↓/g/G: Select  Tab: Filter kind  y: Copy  c: Clear  Esc: Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(l.Theme.Error).
+		Width(l.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
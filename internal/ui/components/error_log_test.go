@@ -0,0 +1,74 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		title, message string
+		want           ErrorKind
+	}{
+		{"Query Error", `ERROR: relation "connections" does not exist (SQLSTATE 42P01)`, ErrorKindQuery},
+		{"Database Error", "Failed to load table data:\n\nERROR: permission denied for table t (SQLSTATE 42501)", ErrorKindQuery},
+		{"Query Error", "FATAL: terminating connection due to administrator command (SQLSTATE 57P01)", ErrorKindConnection},
+		{"Connection Failed", "failed to connect to `host=db user=app`: dial tcp: lookup db: no such host", ErrorKindConnection},
+		{"Error", `FATAL: password authentication failed for user "app" (SQLSTATE 28P01)`, ErrorKindConnection},
+		{"No Connection", "Please connect to a database first", ErrorKindConnection},
+		{"Query Error", "context deadline exceeded", ErrorKindQuery},
+		{"Export Failed", "open out.csv: permission denied", ErrorKindOther},
+	}
+	for _, tt := range tests {
+		if got := ClassifyError(tt.title, tt.message); got != tt.want {
+			t.Errorf("ClassifyError(%q, %q) = %s, want %s", tt.title, tt.message, got.Label(), tt.want.Label())
+		}
+	}
+}
+
+func TestErrorLog(t *testing.T) {
+	l := NewErrorLog(theme.DefaultTheme())
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < maxErrorLogEntries+5; i++ {
+		l.Add("Export Failed", fmt.Sprintf("error %d", i), start.Add(time.Duration(i)*time.Second))
+	}
+	if l.Len() != maxErrorLogEntries {
+		t.Fatalf("Len() = %d, want the log capped at %d", l.Len(), maxErrorLogEntries)
+	}
+	l.Add("Connection Failed", "dial tcp: connection refused", start.Add(time.Hour))
+	l.Add("Query Error", "syntax error (SQLSTATE 42601)", start.Add(2*time.Hour))
+
+	visible := l.Visible()
+	if visible[0].Title != "Query Error" || visible[len(visible)-1].Message != "error 7" {
+		t.Errorf("Visible() runs from %q to %q, want newest first and the oldest dropped", visible[0].Title, visible[len(visible)-1].Message)
+	}
+
+	l.Open()
+	l.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if view := l.View(); !strings.Contains(view, "dial tcp: connection refused") {
+		t.Errorf("View() does not show the selected error in full:\n%s", view)
+	}
+
+	// Tab filters to connection errors
+	l.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if visible := l.Visible(); len(visible) != 1 || visible[0].Kind != ErrorKindConnection {
+		t.Errorf("Visible() = %v with the connection filter", visible)
+	}
+	l.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if visible := l.Visible(); len(visible) != 1 || visible[0].Kind != ErrorKindQuery {
+		t.Errorf("Visible() = %v with the query filter", visible)
+	}
+
+	_, cmd := l.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc did not close the log")
+	}
+	if _, ok := cmd().(CloseErrorLogMsg); !ok {
+		t.Errorf("Esc sent %#v, want CloseErrorLogMsg", cmd())
+	}
+}
@@ -26,7 +26,8 @@ const (
 	FavoritesModeEdit
 )
 
-// ExecuteFavoriteMsg is sent when a favorite should be executed
+// ExecuteFavoriteMsg is sent when a favorite should be executed, or its
+// object opened if it is a bookmark
 type ExecuteFavoriteMsg struct {
 	Favorite models.Favorite
 }
@@ -74,6 +75,8 @@ type FavoritesDialog struct {
 	queryInput       string
 	tagsInput        string
 	currentField     int // 0=name, 1=description, 2=query, 3=tags
+	editingTarget    string // Set while editing a bookmark or view, whose target is read-only
+	editingView      bool
 
 	// Validation and errors
 	validationError string
@@ -206,6 +209,9 @@ func (fd *FavoritesDialog) HandleMouseClick(msg tea.MouseMsg) (handled bool, cmd
 		for i := 0; i < 4; i++ {
 			zoneID := fmt.Sprintf("%s%d", ZoneFavoriteFieldPrefix, i)
 			if zone.Get(zoneID).InBounds(msg) {
+				if i == 2 && fd.editingTarget != "" {
+					return true, nil
+				}
 				fd.currentField = i
 				fd.validationError = ""
 				return true, nil
@@ -265,6 +271,8 @@ func (fd *FavoritesDialog) handleListMode(msg tea.KeyMsg) (*FavoritesDialog, tea
 		fd.queryInput = ""
 		fd.tagsInput = ""
 		fd.currentField = 0
+		fd.editingTarget = ""
+		fd.editingView = false
 		fd.validationError = ""
 		fd.deleteConfirmMode = false
 	case "e":
@@ -279,6 +287,8 @@ func (fd *FavoritesDialog) handleListMode(msg tea.KeyMsg) (*FavoritesDialog, tea
 			fd.descriptionInput = fav.Description
 			fd.queryInput = fav.Query
 			fd.tagsInput = strings.Join(fav.Tags, ", ")
+			fd.editingTarget = favoriteTarget(fav)
+			fd.editingView = fav.IsView()
 			fd.currentField = 0
 			fd.validationError = ""
 			fd.deleteConfirmMode = false
@@ -312,9 +322,15 @@ func (fd *FavoritesDialog) handleEditMode(msg tea.KeyMsg) (*FavoritesDialog, tea
 		fd.validationError = ""
 	case "tab":
 		fd.currentField = (fd.currentField + 1) % 4
+		if fd.currentField == 2 && fd.editingTarget != "" {
+			fd.currentField = 3 // A bookmark's object can't be edited
+		}
 		fd.validationError = "" // Clear validation error when moving between fields
 	case "shift+tab":
 		fd.currentField = (fd.currentField - 1 + 4) % 4
+		if fd.currentField == 2 && fd.editingTarget != "" {
+			fd.currentField = 1
+		}
 		fd.validationError = "" // Clear validation error when moving between fields
 	case "backspace":
 		fd.deleteChar()
@@ -370,6 +386,9 @@ func (fd *FavoritesDialog) handleEditMode(msg tea.KeyMsg) (*FavoritesDialog, tea
 			}
 		} else {
 			fd.currentField++
+			if fd.currentField == 2 && fd.editingTarget != "" {
+				fd.currentField = 3
+			}
 		}
 	default:
 		if len(msg.String()) == 1 {
@@ -386,7 +405,9 @@ func (fd *FavoritesDialog) addChar(ch string) {
 	case 1:
 		fd.descriptionInput += ch
 	case 2:
-		fd.queryInput += ch
+		if fd.editingTarget == "" {
+			fd.queryInput += ch
+		}
 	case 3:
 		fd.tagsInput += ch
 	}
@@ -419,6 +440,8 @@ func (fd *FavoritesDialog) clearInputs() {
 	fd.queryInput = ""
 	fd.tagsInput = ""
 	fd.currentField = 0
+	fd.editingTarget = ""
+	fd.editingView = false
 	fd.validationError = ""
 }
 
@@ -431,7 +454,7 @@ func (fd *FavoritesDialog) validateInputs() error {
 		return fmt.Errorf("name is required")
 	}
 
-	if query == "" {
+	if query == "" && fd.editingTarget == "" {
 		return fmt.Errorf("query is required")
 	}
 
@@ -462,13 +485,13 @@ func (fd *FavoritesDialog) renderList() string {
 		Background(fd.Theme.Info).
 		Padding(0, 1).
 		Bold(true)
-	sections = append(sections, titleStyle.Render("Favorite Queries"))
+	sections = append(sections, titleStyle.Render("Favorites"))
 
 	// Instructions
 	instrStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#a6adc8")).
 		Padding(0, 1)
-	sections = append(sections, instrStyle.Render("↑↓: Navigate  Enter: Execute  a: Add  e: Edit  d: Delete  Esc: Close"))
+	sections = append(sections, instrStyle.Render("↑↓: Navigate  Enter: Run/Open  a: Add  e: Edit  d: Delete  Esc: Close"))
 
 	// Delete confirmation warning
 	if fd.deleteConfirmMode && len(fd.favorites) > 0 {
@@ -485,7 +508,7 @@ func (fd *FavoritesDialog) renderList() string {
 		emptyStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#a6adc8")).
 			Padding(1, 1)
-		emptyMsg := "No favorites yet.\n\nPress 'a' to add your first favorite query.\n\nFavorites let you save frequently used queries for quick access."
+		emptyMsg := "No favorites yet.\n\nPress 'a' to add your first favorite query.\n\nFavorites let you save frequently used queries for quick access.\nPress 'b' in the tree to bookmark a table, view or function."
 		sections = append(sections, emptyStyle.Render(emptyMsg))
 	} else {
 		sections = append(sections, "")
@@ -509,7 +532,22 @@ func (fd *FavoritesDialog) renderList() string {
 				desc = desc[:47] + "..."
 			}
 
-			line := fmt.Sprintf("%s\n  %s", name, desc)
+			// Bookmarks and views open something rather than run a query,
+			// so mark them and show what they point at
+			var line string
+			if !fav.IsQuery() {
+				target := favoriteTarget(fav)
+				if desc != "" {
+					target += " - " + desc
+				}
+				icon := "🔖"
+				if fav.IsView() {
+					icon = "▦ "
+				}
+				line = fmt.Sprintf("%s %s\n   %s", icon, name, target)
+			} else {
+				line = fmt.Sprintf("▶  %s\n   %s", name, desc)
+			}
 			if len(fav.Tags) > 0 {
 				line += fmt.Sprintf(" [%s]", strings.Join(fav.Tags, ", "))
 			}
@@ -554,6 +592,11 @@ func (fd *FavoritesDialog) renderEdit() string {
 	title := "Add Favorite"
 	if fd.mode == FavoritesModeEdit {
 		title = "Edit Favorite"
+		if fd.editingView {
+			title = "Edit Saved View"
+		} else if fd.editingTarget != "" {
+			title = "Edit Bookmark"
+		}
 	}
 	sections = append(sections, titleStyle.Render(title))
 
@@ -577,7 +620,11 @@ func (fd *FavoritesDialog) renderEdit() string {
 	sections = append(sections, "")
 	sections = append(sections, zone.Mark(ZoneFavoriteFieldPrefix+"0", fd.renderField("Name: (required)", fd.nameInput, fd.currentField == 0)))
 	sections = append(sections, zone.Mark(ZoneFavoriteFieldPrefix+"1", fd.renderField("Description:", fd.descriptionInput, fd.currentField == 1)))
-	sections = append(sections, zone.Mark(ZoneFavoriteFieldPrefix+"2", fd.renderField("Query: (required)", fd.queryInput, fd.currentField == 2)))
+	if fd.editingTarget != "" {
+		sections = append(sections, zone.Mark(ZoneFavoriteFieldPrefix+"2", fd.renderField("Opens: (read-only)", fd.editingTarget, false)))
+	} else {
+		sections = append(sections, zone.Mark(ZoneFavoriteFieldPrefix+"2", fd.renderField("Query: (required)", fd.queryInput, fd.currentField == 2)))
+	}
 	sections = append(sections, zone.Mark(ZoneFavoriteFieldPrefix+"3", fd.renderField("Tags: (comma separated, optional)", fd.tagsInput, fd.currentField == 3)))
 
 	// Help text
@@ -626,3 +673,16 @@ func (fd *FavoritesDialog) GetEditData() (name, description, query string, tags
 
 	return
 }
+
+// favoriteTarget describes what a bookmark or saved view opens, e.g.
+// "table sales.orders" or "view sales.orders, 1 filter". Queries have none.
+func favoriteTarget(fav models.Favorite) string {
+	switch {
+	case fav.IsBookmark():
+		return strings.ReplaceAll(string(fav.Object.Type), "_", " ") + " " + fav.Object.QualifiedName()
+	case fav.IsView():
+		return "view " + fav.View.Describe()
+	default:
+		return ""
+	}
+}
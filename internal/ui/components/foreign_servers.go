@@ -0,0 +1,166 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// ForeignTableIcon marks foreign tables, whose rows live on another server
+const ForeignTableIcon = "⇄"
+
+// ForeignServerOf returns the server a foreign table node reads from, or ""
+// for other nodes
+func ForeignServerOf(node *models.TreeNode) string {
+	if node == nil || node.Type != models.TreeNodeTypeForeignTable {
+		return ""
+	}
+	if meta, ok := node.Metadata.(map[string]interface{}); ok {
+		if server, ok := meta["foreign_server"].(string); ok {
+			return server
+		}
+	}
+	return ""
+}
+
+// CloseForeignServersMsg is sent when the foreign servers panel should close
+type CloseForeignServersMsg struct{}
+
+// ForeignServersPanel lists the foreign servers of the database with their
+// options and user mappings. Secrets in options are masked.
+type ForeignServersPanel struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	servers []metadata.ForeignServer
+	loaded  bool
+	err     error
+	offset  int // First visible line
+}
+
+// NewForeignServersPanel creates a new foreign servers panel
+func NewForeignServersPanel(th theme.Theme) *ForeignServersPanel {
+	return &ForeignServersPanel{
+		Width:  80,
+		Height: 24,
+		Theme:  th,
+	}
+}
+
+// Open shows the panel loading the servers
+func (p *ForeignServersPanel) Open() {
+	p.servers = nil
+	p.loaded = false
+	p.err = nil
+	p.offset = 0
+}
+
+// SetServers shows the loaded servers, or why they could not be loaded
+func (p *ForeignServersPanel) SetServers(servers []metadata.ForeignServer, err error) {
+	p.servers = servers
+	p.err = err
+	p.loaded = true
+}
+
+// bodyLines returns how many lines of servers are shown at once
+func (p *ForeignServersPanel) bodyLines() int {
+	return max(p.Height-10, 3)
+}
+
+// Update handles keyboard input
+func (p *ForeignServersPanel) Update(msg tea.KeyMsg) (*ForeignServersPanel, tea.Cmd) {
+	last := max(len(p.Lines())-p.bodyLines(), 0)
+	switch msg.String() {
+	case "esc", "q", "enter":
+		return p, func() tea.Msg { return CloseForeignServersMsg{} }
+	case "up", "k":
+		p.offset = max(p.offset-1, 0)
+	case "down", "j":
+		p.offset = min(p.offset+1, last)
+	case "g", "home":
+		p.offset = 0
+	case "G", "end":
+		p.offset = last
+	}
+	return p, nil
+}
+
+// Lines returns the servers as display lines, unstyled
+func (p *ForeignServersPanel) Lines() []string {
+	var lines []string
+	for i, s := range p.servers {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		tables := "tables"
+		if s.Tables == 1 {
+			tables = "table"
+		}
+		lines = append(lines, fmt.Sprintf("%s  (%s, owner %s, %d foreign %s)", s.Name, s.Wrapper, s.Owner, s.Tables, tables))
+		if len(s.Options) > 0 {
+			lines = append(lines, "  options: "+strings.Join(metadata.MaskOptions(s.Options), ", "))
+		}
+		if len(s.Mappings) == 0 {
+			lines = append(lines, "  no user mappings")
+		}
+		for _, m := range s.Mappings {
+			line := "  mapping for " + m.User
+			if len(m.Options) > 0 {
+				line += ": " + strings.Join(metadata.MaskOptions(m.Options), ", ")
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// View renders the panel
+func (p *ForeignServersPanel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(p.Theme.Foreground).
+		Background(p.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	nameStyle := lipgloss.NewStyle().Foreground(p.Theme.Foreground).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+
+	textWidth := p.Width - 4 // Border and padding
+	sections := []string{titleStyle.Render("Foreign Servers"), ""}
+	switch {
+	case p.err != nil:
+		errorStyle := lipgloss.NewStyle().Foreground(p.Theme.Error)
+		sections = append(sections, errorStyle.Render(wrapText("Could not load foreign servers: "+p.err.Error(), textWidth)))
+	case !p.loaded:
+		sections = append(sections, metaStyle.Render("Loading..."))
+	case len(p.servers) == 0:
+		sections = append(sections, metaStyle.Render("No foreign servers in this database"))
+	default:
+		lines := p.Lines()
+		end := min(p.offset+p.bodyLines(), len(lines))
+		for _, line := range lines[p.offset:end] {
+			line = truncateToWidth(line, textWidth)
+			if line != "" && !strings.HasPrefix(line, " ") {
+				sections = append(sections, nameStyle.Render(line))
+			} else {
+				sections = append(sections, metaStyle.Render(line))
+			}
+		}
+		if p.offset > 0 || end < len(lines) {
+			sections = append(sections, metaStyle.Render(fmt.Sprintf("lines %d-%d of %d", p.offset+1, end, len(lines))))
+		}
+	}
+	sections = append(sections, "", metaStyle.Render("↑↓: Scroll  Esc: Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Theme.Border).
+		Width(p.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
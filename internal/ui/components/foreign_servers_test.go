@@ -0,0 +1,82 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestForeignServersPanel(t *testing.T) {
+	p := NewForeignServersPanel(theme.DefaultTheme())
+	p.Open()
+	if view := p.View(); !strings.Contains(view, "Loading") {
+		t.Errorf("View() before loading = %q, want Loading", view)
+	}
+
+	p.SetServers([]metadata.ForeignServer{{
+		Name:    "warehouse",
+		Wrapper: "postgres_fdw",
+		Owner:   "admin",
+		Options: []string{"host=db.internal", "dbname=dw"},
+		Tables:  2,
+		Mappings: []metadata.UserMapping{
+			{User: "public"},
+			{User: "alice", Options: []string{"user=reader", "password=hunter2"}},
+		},
+	}}, nil)
+
+	lines := strings.Join(p.Lines(), "\n")
+	for _, want := range []string{
+		"warehouse  (postgres_fdw, owner admin, 2 foreign tables)",
+		"options: host=db.internal, dbname=dw",
+		"mapping for public",
+		"mapping for alice: user=reader, password=••••",
+	} {
+		if !strings.Contains(lines, want) {
+			t.Errorf("Lines() = %q, want %q", lines, want)
+		}
+	}
+	if strings.Contains(p.View(), "hunter2") {
+		t.Error("View() shows a mapping's password")
+	}
+
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc did not close the panel")
+	}
+	if _, ok := cmd().(CloseForeignServersMsg); !ok {
+		t.Errorf("Esc sent %#v, want CloseForeignServersMsg", cmd())
+	}
+
+	p.Open()
+	p.SetServers(nil, errors.New("permission denied"))
+	if view := p.View(); !strings.Contains(view, "permission denied") {
+		t.Errorf("View() = %q, want the error", view)
+	}
+}
+
+func TestTableView_ForeignServer(t *testing.T) {
+	node := models.NewTreeNode("foreigntable:app.public.orders", models.TreeNodeTypeForeignTable, "orders")
+	node.Metadata = map[string]interface{}{"foreign_server": "warehouse"}
+	if got := ForeignServerOf(node); got != "warehouse" {
+		t.Errorf("ForeignServerOf() = %q, want warehouse", got)
+	}
+	if got := ForeignServerOf(models.NewTreeNode("table:app.public.t", models.TreeNodeTypeTable, "t")); got != "" {
+		t.Errorf("ForeignServerOf(table) = %q, want none", got)
+	}
+
+	tv := NewTableView(theme.DefaultTheme())
+	tv.SetData([]string{"id"}, [][]string{{"1"}}, 1)
+	if strings.Contains(tv.renderStatus(), "remote") {
+		t.Error("status of a local table mentions a remote server")
+	}
+	tv.ForeignServer = "warehouse"
+	if status := tv.renderStatus(); !strings.Contains(status, "remote: warehouse") {
+		t.Errorf("renderStatus() = %q, want the remote server", status)
+	}
+}
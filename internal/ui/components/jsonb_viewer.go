@@ -44,12 +44,26 @@ type TreeNode struct {
 // CloseJSONBViewerMsg is sent when viewer should close
 type CloseJSONBViewerMsg struct{}
 
+// Bounds used when sizing the JSONB viewer to its content
+const (
+	jsonbMinWidth  = 50 // Room for the search bar and the status line
+	jsonbMinHeight = 8  // Header and status (5) plus three rows
+	// Border and padding (4), the selection margin (2) and a search
+	// match marker (3) around the widest node line
+	jsonbLineChrome = 9
+)
+
 // JSONBViewer displays JSONB data as an interactive collapsible tree
 type JSONBViewer struct {
 	Width  int
 	Height int
 	Theme  theme.Theme
 
+	// Upper bounds for Width and Height. When set, the viewer is sized to
+	// its visible nodes within them and scrolls beyond.
+	MaxWidth  int
+	MaxHeight int
+
 	// Tree structure
 	root *TreeNode
 
@@ -257,6 +271,43 @@ func (jv *JSONBViewer) rebuildVisibleNodes() {
 	if jv.root != nil {
 		jv.flattenTree(jv.root)
 	}
+	jv.fitToContent()
+}
+
+// SetMaxSize sets the largest size the viewer may take and sizes it to its
+// content within that
+func (jv *JSONBViewer) SetMaxSize(width, height int) {
+	jv.MaxWidth = width
+	jv.MaxHeight = height
+	jv.fitToContent()
+}
+
+// fitToContent sizes the viewer to the visible nodes and their widest line,
+// so a small value gets a small box. The size is capped by MaxWidth and
+// MaxHeight; larger content scrolls. Help always uses the full size.
+func (jv *JSONBViewer) fitToContent() {
+	if jv.MaxWidth <= 0 || jv.MaxHeight <= 0 {
+		return
+	}
+	if jv.helpMode {
+		jv.Width, jv.Height = jv.MaxWidth, jv.MaxHeight
+		return
+	}
+
+	lineWidth := 0
+	for _, node := range jv.visibleNodes {
+		if w := lipgloss.Width(jv.renderNode(node, false, false)); w > lineWidth {
+			lineWidth = w
+		}
+	}
+	jv.Width = min(max(lineWidth+jsonbLineChrome, jsonbMinWidth), jv.MaxWidth)
+	jv.Height = min(max(len(jv.visibleNodes)+5, jsonbMinHeight), jv.MaxHeight)
+
+	// Don't leave rows scrolled off the top when everything fits again
+	contentHeight := max(jv.Height-5, 1)
+	if maxOffset := max(len(jv.visibleNodes)-contentHeight, 0); jv.scrollOffset > maxOffset {
+		jv.scrollOffset = maxOffset
+	}
 }
 
 // flattenTree recursively flattens the tree into visibleNodes
@@ -282,6 +333,7 @@ func (jv *JSONBViewer) Update(msg tea.KeyMsg) (*JSONBViewer, tea.Cmd) {
 	if jv.helpMode {
 		// Any key exits help mode
 		jv.helpMode = false
+		jv.fitToContent()
 		return jv, nil
 	}
 
@@ -527,6 +579,7 @@ func (jv *JSONBViewer) Update(msg tea.KeyMsg) (*JSONBViewer, tea.Cmd) {
 	case "?":
 		// Toggle help mode
 		jv.helpMode = !jv.helpMode
+		jv.fitToContent()
 
 	default:
 		// Handle quick jump mode
@@ -882,12 +935,21 @@ func (jv *JSONBViewer) renderMainPanel(width int) string {
 	title := " JSONB Tree Viewer"
 	sections = append(sections, jv.cachedStyles.title.Render(title))
 
-	// Instructions or search bar (use cached style)
+	// Instructions or search bar (use cached style), kept to one line: the
+	// text area is the width less border, padding and the style's padding
+	lineWidth := width - 6
 	if jv.searchMode {
 		searchBar := fmt.Sprintf("Search: %s_", jv.searchQuery)
 		if len(jv.searchResults) > 0 {
 			searchBar += fmt.Sprintf("  (%d matches)", len(jv.searchResults))
 		}
+		// Cut the start of a long query so the cursor stays in view
+		if runes := []rune(searchBar); lipgloss.Width(searchBar) > lineWidth && lineWidth > 1 {
+			for lipgloss.Width(string(runes))+1 > lineWidth {
+				runes = runes[1:]
+			}
+			searchBar = "…" + string(runes)
+		}
 		sections = append(sections, jv.cachedStyles.instructions.Render(searchBar))
 	} else if jv.quickJumpMode {
 		// Show mark/jump mode
@@ -898,16 +960,16 @@ func (jv *JSONBViewer) renderMainPanel(width int) string {
 		case "'":
 			modeInfo = "Jump mode: Press a-z to jump to mark"
 		}
-		sections = append(sections, jv.cachedStyles.instructions.Render(modeInfo))
+		sections = append(sections, jv.cachedStyles.instructions.Render(truncateToWidth(modeInfo, lineWidth)))
 	} else if len(jv.searchResults) > 0 {
 		// Show search results navigation info
 		searchInfo := fmt.Sprintf("Search: \"%s\" (%d/%d)  n: Next  N: Prev  Esc: Clear",
 			jv.searchQuery, jv.currentMatchIndex+1, len(jv.searchResults))
-		sections = append(sections, jv.cachedStyles.instructions.Render(searchInfo))
+		sections = append(sections, jv.cachedStyles.instructions.Render(truncateToWidth(searchInfo, lineWidth)))
 	} else {
 		// Show help text
 		instr := "↑↓/jk: Move  g/G: Top/Bottom  Ctrl-f/b: Page  JK: Sibling  p: Parent  ]/[: Jump Type  y: Copy Path  m/': Mark  /: Search  ?: Help"
-		sections = append(sections, jv.cachedStyles.instructions.Render(instr))
+		sections = append(sections, jv.cachedStyles.instructions.Render(truncateToWidth(instr, lineWidth)))
 	}
 
 	// Content (tree view or help)
@@ -0,0 +1,81 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestJSONBViewer_FitsSmallValues(t *testing.T) {
+	jv := NewJSONBViewer(theme.DefaultTheme())
+	jv.SetMaxSize(100, 40)
+	if err := jv.SetValue(`{"a": 1, "b": true}`); err != nil {
+		t.Fatal(err)
+	}
+
+	// root, a, b
+	if jv.Height != jsonbMinHeight {
+		t.Errorf("Height = %d, want the minimum %d for three nodes", jv.Height, jsonbMinHeight)
+	}
+	if jv.Width != jsonbMinWidth {
+		t.Errorf("Width = %d, want the minimum %d for short lines", jv.Width, jsonbMinWidth)
+	}
+	for _, line := range strings.Split(jv.View(), "\n") {
+		if w := lipgloss.Width(line); w > jv.Width {
+			t.Fatalf("line is %d wide, wider than the viewer (%d): %q", w, jv.Width, line)
+		}
+	}
+}
+
+func TestJSONBViewer_CapsAndScrollsLargeValues(t *testing.T) {
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = fmt.Sprintf("%q: %d", strings.Repeat("k", 70)+fmt.Sprint(i), i)
+	}
+
+	jv := NewJSONBViewer(theme.DefaultTheme())
+	jv.SetMaxSize(70, 20)
+	if err := jv.SetValue("{" + strings.Join(items, ",") + "}"); err != nil {
+		t.Fatal(err)
+	}
+	if jv.Width != 70 || jv.Height != 20 {
+		t.Fatalf("size = %dx%d, want the 70x20 cap", jv.Width, jv.Height)
+	}
+
+	// Still scrollable past the cap
+	jv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if jv.scrollOffset == 0 {
+		t.Error("scrollOffset = 0 after jumping to the bottom, want the view scrolled")
+	}
+
+	// Collapsing the root fits the box to one node and resets the scroll
+	jv.root.IsExpanded = false
+	jv.rebuildVisibleNodes()
+	if jv.Height != jsonbMinHeight || jv.scrollOffset != 0 {
+		t.Errorf("after collapsing: Height = %d, scrollOffset = %d", jv.Height, jv.scrollOffset)
+	}
+}
+
+func TestJSONBViewer_SearchBarStaysVisible(t *testing.T) {
+	jv := NewJSONBViewer(theme.DefaultTheme())
+	jv.SetMaxSize(100, 40)
+	if err := jv.SetValue(`{"a": 1}`); err != nil {
+		t.Fatal(err)
+	}
+	jv.searchMode = true
+	jv.searchQuery = strings.Repeat("q", 80) + "end"
+
+	view := jv.View()
+	if !strings.Contains(view, "end_") {
+		t.Errorf("search bar lost the end of the query:\n%s", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > jv.Width {
+			t.Fatalf("line is %d wide, wider than the viewer (%d)", w, jv.Width)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// KeyHint is one key and what it does, as shown in the status bar
+type KeyHint struct {
+	Key  string
+	Desc string
+}
+
+// HintContext is the focus area the status bar hints are for
+type HintContext int
+
+const (
+	HintContextTree HintContext = iota
+	HintContextData
+	HintContextEditor
+)
+
+// HintToggleKey switches the status bar between the two hint sets
+const HintToggleKey = "ctrl+t"
+
+// statusHints holds the two hint sets of each focus area. Keep them in step
+// with the key handling in the app and the help screen. The last hint of
+// each set is the toggle, which FitKeyHints always keeps.
+var statusHints = map[HintContext][2][]KeyHint{
+	HintContextTree: {
+		{
+			{"↑↓", "navigate"},
+			{"→←", "expand"},
+			{"Enter", "select"},
+			{"/", "search"},
+			{"Ctrl+T", "more"},
+		},
+		{
+			{".", "system schemas"},
+			{"b", "bookmark"},
+			{"T", "truncate"},
+			{"Ctrl+G", "recent"},
+			{"Ctrl+K", "commands"},
+			{"?", "help"},
+			{"Ctrl+T", "back"},
+		},
+	},
+	HintContextData: {
+		{
+			{"↑↓", "navigate"},
+			{"Ctrl+D/U", "page"},
+			{"Ctrl+E", "sql"},
+			{"p", "preview"},
+			{"*", "pin"},
+			{"'", "goto pin"},
+			{"Ctrl+T", "more"},
+		},
+		{
+			{"f", "filter"},
+			{"s", "sort"},
+			{"/", "search"},
+			{"`", "quick filter"},
+			{"y", "copy"},
+			{"J", "jsonb"},
+			{"#", "count"},
+			{"?", "help"},
+			{"Ctrl+T", "back"},
+		},
+	},
+	HintContextEditor: {
+		{
+			{"Ctrl+S", "execute"},
+			{"Ctrl+O", "editor"},
+			{"F11", "fullscreen"},
+			{"Esc", "close"},
+			{"Ctrl+T", "more"},
+		},
+		{
+			{"Ctrl+↑↓", "history"},
+			{"F12", "definition"},
+			{"Ctrl+U", "clear"},
+			{"F2", "height"},
+			{"Ctrl+T", "back"},
+		},
+	},
+}
+
+// StatusHints returns the status bar hints for a focus area; more selects
+// the second set
+func StatusHints(ctx HintContext, more bool) []KeyHint {
+	sets := statusHints[ctx]
+	if more {
+		return sets[1]
+	}
+	return sets[0]
+}
+
+// FitKeyHints renders hints as "key desc" items, each preceded by sep, and
+// drops whole items from the end until the result fits in width cells. The
+// last hint (the set toggle) is kept as long as anything fits, so the other
+// set stays reachable on narrow terminals.
+func FitKeyHints(hints []KeyHint, width int, keyStyle, descStyle lipgloss.Style, sep string) string {
+	items := make([]string, len(hints))
+	for i, h := range hints {
+		items[i] = sep + keyStyle.Render(h.Key) + descStyle.Render(" "+h.Desc)
+	}
+
+	total := 0
+	for _, item := range items {
+		total += lipgloss.Width(item)
+	}
+	for len(items) > 1 && total > width {
+		// Drop the item just before the toggle
+		drop := len(items) - 2
+		total -= lipgloss.Width(items[drop])
+		items = append(items[:drop], items[drop+1:]...)
+	}
+	if total > width {
+		return ""
+	}
+
+	return strings.Join(items, "")
+}
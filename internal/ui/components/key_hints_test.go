@@ -0,0 +1,53 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFitKeyHints(t *testing.T) {
+	hints := []KeyHint{
+		{"↑↓", "navigate"},
+		{"Enter", "select"},
+		{"/", "search"},
+		{"Ctrl+T", "more"},
+	}
+	plain := lipgloss.NewStyle()
+	full := FitKeyHints(hints, 100, plain, plain, " │ ")
+	if want := " │ ↑↓ navigate │ Enter select │ / search │ Ctrl+T more"; full != want {
+		t.Fatalf("FitKeyHints() = %q, want %q", full, want)
+	}
+
+	// Narrower: whole items are dropped from the end, keeping the toggle
+	got := FitKeyHints(hints, lipgloss.Width(full)-1, plain, plain, " │ ")
+	if want := " │ ↑↓ navigate │ Enter select │ Ctrl+T more"; got != want {
+		t.Errorf("FitKeyHints() = %q, want %q", got, want)
+	}
+	if strings.Contains(got, "sear") {
+		t.Errorf("FitKeyHints() cut a hint in half: %q", got)
+	}
+
+	got = FitKeyHints(hints, 16, plain, plain, " │ ")
+	if want := " │ Ctrl+T more"; got != want {
+		t.Errorf("FitKeyHints() = %q, want %q", got, want)
+	}
+
+	if got := FitKeyHints(hints, 5, plain, plain, " │ "); got != "" {
+		t.Errorf("FitKeyHints() with no room = %q, want empty", got)
+	}
+}
+
+func TestStatusHints(t *testing.T) {
+	for _, ctx := range []HintContext{HintContextTree, HintContextData, HintContextEditor} {
+		primary, more := StatusHints(ctx, false), StatusHints(ctx, true)
+		if len(primary) == 0 || len(more) == 0 {
+			t.Fatalf("context %d has an empty hint set", ctx)
+		}
+		// Both sets end in the toggle so the other set stays reachable
+		if primary[len(primary)-1].Key != "Ctrl+T" || more[len(more)-1].Key != "Ctrl+T" {
+			t.Errorf("context %d hint sets do not end in the toggle", ctx)
+		}
+	}
+}
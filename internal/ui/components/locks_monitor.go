@@ -0,0 +1,320 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// LocksRefreshInterval is how often the open locks monitor reloads
+const LocksRefreshInterval = 2 * time.Second
+
+// CloseLocksMonitorMsg is sent when the locks monitor should close
+type CloseLocksMonitorMsg struct{}
+
+// RefreshLocksMsg asks for the blocking locks to be reloaded right away
+type RefreshLocksMsg struct{}
+
+// SignalBackendMsg asks to cancel a backend's query, or with Terminate to
+// end its session
+type SignalBackendMsg struct {
+	PID       int
+	Terminate bool
+}
+
+// LockBlocker is a backend holding up others, with the backends waiting on it
+type LockBlocker struct {
+	PID     int
+	User    string
+	State   string
+	Query   string
+	Blocked []models.BlockingLock
+}
+
+// GroupByBlocker groups blocker→blocked pairs by blocker. Blockers keep the
+// order they first appear in, so with pairs sorted by wait the blocker of
+// the longest wait comes first.
+func GroupByBlocker(locks []models.BlockingLock) []LockBlocker {
+	var blockers []LockBlocker
+	index := make(map[int]int)
+	for _, l := range locks {
+		i, ok := index[l.BlockingPID]
+		if !ok {
+			i = len(blockers)
+			index[l.BlockingPID] = i
+			blockers = append(blockers, LockBlocker{
+				PID:   l.BlockingPID,
+				User:  l.BlockingUser,
+				State: l.BlockingState,
+				Query: l.BlockingQuery,
+			})
+		}
+		blockers[i].Blocked = append(blockers[i].Blocked, l)
+	}
+	return blockers
+}
+
+// LocksMonitor shows which backends block which, refreshed on a timer, and
+// cancels or terminates the selected blocker
+type LocksMonitor struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	blockers []LockBlocker
+	selected int // Index in blockers
+	loaded   bool
+	err      error
+	updated  time.Time
+
+	status    string
+	statusErr bool
+
+	// PID waiting for terminate confirmation, 0 when none
+	confirmPID int
+}
+
+// NewLocksMonitor creates a new locks monitor
+func NewLocksMonitor(th theme.Theme) *LocksMonitor {
+	return &LocksMonitor{
+		Width:  100,
+		Height: 30,
+		Theme:  th,
+	}
+}
+
+// Open clears the previous session's state
+func (m *LocksMonitor) Open() {
+	m.blockers = nil
+	m.selected = 0
+	m.loaded = false
+	m.err = nil
+	m.status = ""
+	m.confirmPID = 0
+}
+
+// SetLocks replaces the shown locks, keeping the selected blocker selected
+// if it is still blocking
+func (m *LocksMonitor) SetLocks(locks []models.BlockingLock, err error, at time.Time) {
+	m.loaded = true
+	m.updated = at
+	m.err = err
+	if err != nil {
+		return
+	}
+
+	selectedPID := m.SelectedPID()
+	m.blockers = GroupByBlocker(locks)
+	m.selected = 0
+	for i, b := range m.blockers {
+		if b.PID == selectedPID {
+			m.selected = i
+			break
+		}
+	}
+	if m.confirmPID != 0 && m.SelectedPID() != m.confirmPID {
+		// The blocker went away while asking
+		m.confirmPID = 0
+	}
+}
+
+// SetStatus shows the outcome of an action
+func (m *LocksMonitor) SetStatus(status string, isErr bool) {
+	m.status = status
+	m.statusErr = isErr
+}
+
+// SelectedPID returns the PID of the selected blocker, or 0
+func (m *LocksMonitor) SelectedPID() int {
+	if m.selected < 0 || m.selected >= len(m.blockers) {
+		return 0
+	}
+	return m.blockers[m.selected].PID
+}
+
+// Update handles keyboard input
+func (m *LocksMonitor) Update(msg tea.KeyMsg) (*LocksMonitor, tea.Cmd) {
+	if m.confirmPID != 0 {
+		pid := m.confirmPID
+		m.confirmPID = 0
+		if msg.String() == "y" || msg.String() == "Y" {
+			return m, func() tea.Msg { return SignalBackendMsg{PID: pid, Terminate: true} }
+		}
+		m.SetStatus("Terminate cancelled", false)
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseLocksMonitorMsg{} }
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "down", "j":
+		if m.selected < len(m.blockers)-1 {
+			m.selected++
+		}
+	case "r":
+		return m, func() tea.Msg { return RefreshLocksMsg{} }
+	case "c":
+		if m.SelectedPID() == 0 {
+			return m, nil
+		}
+		b := m.blockers[m.selected]
+		if strings.HasPrefix(b.State, "idle in transaction") {
+			// There is no running query to cancel; its locks stay until
+			// the transaction ends
+			m.SetStatus(fmt.Sprintf("PID %d is idle in transaction, so cancelling does nothing. Press t to terminate it.", b.PID), true)
+			return m, nil
+		}
+		return m, func() tea.Msg { return SignalBackendMsg{PID: b.PID} }
+	case "t":
+		if pid := m.SelectedPID(); pid != 0 {
+			m.confirmPID = pid
+		}
+	}
+	return m, nil
+}
+
+// View renders the locks monitor
+func (m *LocksMonitor) View() string {
+	var sections []string
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.Theme.Foreground).
+		Background(m.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	sections = append(sections, titleStyle.Render("Blocking Locks"))
+
+	instrStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#a6adc8")).
+		Padding(0, 1)
+	sections = append(sections, instrStyle.Render("↑↓: Select blocker  c: Cancel query  t: Terminate  r: Refresh  Esc: Close"))
+
+	metaStyle := lipgloss.NewStyle().Foreground(m.Theme.Metadata)
+	errorStyle := lipgloss.NewStyle().Foreground(m.Theme.Error)
+	updated := "Loading..."
+	if !m.updated.IsZero() {
+		updated = fmt.Sprintf("Updated %s, refreshes every %s", m.updated.Format("15:04:05"), LocksRefreshInterval)
+	}
+	sections = append(sections, "", metaStyle.Render(updated), "")
+
+	textWidth := m.Width - 4 // Border and padding
+	switch {
+	case m.err != nil:
+		sections = append(sections, errorStyle.Render(truncateToWidth("Could not load locks: "+m.err.Error(), textWidth)))
+	case m.loaded && len(m.blockers) == 0:
+		sections = append(sections, metaStyle.Render("No backend is waiting on a lock"))
+	default:
+		sections = append(sections, m.renderBlockers(textWidth)...)
+	}
+
+	if m.confirmPID != 0 {
+		warnStyle := lipgloss.NewStyle().Foreground(m.Theme.Warning).Bold(true)
+		sections = append(sections, "", warnStyle.Render(truncateToWidth(fmt.Sprintf(
+			"Terminate PID %d? Its session ends and its transaction rolls back. y/n", m.confirmPID), textWidth)))
+	} else if m.status != "" {
+		style := lipgloss.NewStyle().Foreground(m.Theme.Success)
+		if m.statusErr {
+			style = errorStyle
+		}
+		sections = append(sections, "", style.Render(truncateToWidth(m.status, textWidth)))
+	}
+
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.Theme.Border).
+		Width(m.Width).
+		Padding(1)
+
+	return containerStyle.Render(strings.Join(sections, "\n"))
+}
+
+// renderBlockers renders each blocker with the backends waiting on it
+// beneath, scrolled so the selected blocker is in view
+func (m *LocksMonitor) renderBlockers(width int) []string {
+	pidStyle := lipgloss.NewStyle().Foreground(m.Theme.Warning).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(m.Theme.Background).Background(m.Theme.BorderFocused).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(m.Theme.Metadata)
+	queryStyle := lipgloss.NewStyle().Foreground(m.Theme.Foreground)
+
+	var lines []string
+	selectedLine := 0
+	for i, b := range m.blockers {
+		header := fmt.Sprintf("PID %d  %s · %s · blocks %d", b.PID, b.User, displayState(b.State), len(b.Blocked))
+		if i == m.selected {
+			selectedLine = len(lines)
+			lines = append(lines, selectedStyle.Render(truncateToWidth("▶ "+header, width)))
+		} else {
+			lines = append(lines, pidStyle.Render(truncateToWidth("  "+header, width)))
+		}
+		lines = append(lines, queryStyle.Render(truncateToWidth("    "+oneLine(b.Query), width)))
+
+		for _, l := range b.Blocked {
+			waitOn := l.LockMode
+			if l.Relation != "" {
+				waitOn += " on " + l.Relation
+			}
+			if l.LockType != "" && l.LockType != "relation" {
+				waitOn += " (" + l.LockType + ")"
+			}
+			blocked := fmt.Sprintf("    └─▶ PID %d  %s · waiting %s · %s", l.BlockedPID, l.BlockedUser, FormatWait(l.Waiting), waitOn)
+			lines = append(lines, metaStyle.Render(truncateToWidth(blocked, width)))
+			lines = append(lines, queryStyle.Render(truncateToWidth("          "+oneLine(l.BlockedQuery), width)))
+		}
+	}
+
+	// Window the lines around the selected blocker
+	visible := m.Height - 14
+	if visible < 4 {
+		visible = 4
+	}
+	if len(lines) <= visible {
+		return lines
+	}
+	start := 0
+	if selectedLine+visible > len(lines) {
+		start = len(lines) - visible
+	} else if selectedLine > 0 {
+		start = selectedLine
+	}
+	window := lines[start : start+visible]
+	return append(window, metaStyle.Render(fmt.Sprintf("Blocker %d of %d", m.selected+1, len(m.blockers))))
+}
+
+// FormatWait formats a lock wait compactly: 12s, 3m05s, 1h02m
+func FormatWait(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// displayState names a backend state, including the empty state of
+// backends that report none
+func displayState(state string) string {
+	if state == "" {
+		return "unknown state"
+	}
+	return state
+}
+
+// oneLine collapses a query's whitespace so it fits on one line
+func oneLine(query string) string {
+	if query == "" {
+		return "(no query)"
+	}
+	return strings.Join(strings.Fields(query), " ")
+}
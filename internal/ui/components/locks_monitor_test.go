@@ -0,0 +1,90 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func testLocks() []models.BlockingLock {
+	return []models.BlockingLock{
+		{BlockedPID: 20, BlockedUser: "bob", BlockedQuery: "UPDATE orders SET total = 0", Waiting: 90 * time.Second,
+			BlockingPID: 10, BlockingUser: "alice", BlockingState: "idle in transaction", BlockingQuery: "UPDATE orders\n  SET total = 1",
+			Relation: "public.orders", LockType: "transactionid", LockMode: "ShareLock"},
+		{BlockedPID: 40, BlockedUser: "dave", BlockedQuery: "ALTER TABLE users ADD c int", Waiting: 30 * time.Second,
+			BlockingPID: 30, BlockingUser: "carol", BlockingState: "active", BlockingQuery: "SELECT * FROM users",
+			Relation: "public.users", LockType: "relation", LockMode: "AccessExclusiveLock"},
+		{BlockedPID: 21, BlockedUser: "bob", BlockedQuery: "DELETE FROM orders", Waiting: 5 * time.Second,
+			BlockingPID: 10, BlockingUser: "alice", BlockingState: "idle in transaction",
+			Relation: "public.orders", LockType: "relation", LockMode: "RowExclusiveLock"},
+	}
+}
+
+func TestGroupByBlocker(t *testing.T) {
+	blockers := GroupByBlocker(testLocks())
+	if len(blockers) != 2 {
+		t.Fatalf("GroupByBlocker() returned %d blockers, want 2", len(blockers))
+	}
+	if blockers[0].PID != 10 || len(blockers[0].Blocked) != 2 {
+		t.Errorf("first blocker = PID %d blocking %d, want PID 10 blocking 2", blockers[0].PID, len(blockers[0].Blocked))
+	}
+	if blockers[1].PID != 30 || blockers[1].Blocked[0].BlockedPID != 40 {
+		t.Errorf("second blocker = %+v, want PID 30 blocking 40", blockers[1])
+	}
+}
+
+func TestLocksMonitorKeys(t *testing.T) {
+	m := NewLocksMonitor(theme.DefaultTheme())
+	m.Open()
+	m.SetLocks(testLocks(), nil, time.Now())
+
+	// An idle-in-transaction blocker has no query to cancel
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")}); cmd != nil {
+		t.Error("cancelling an idle in transaction blocker should not signal it")
+	}
+
+	// Terminate asks first and only signals on y
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if !strings.Contains(m.View(), "Terminate PID 10?") {
+		t.Error("terminate did not ask for confirmation")
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("confirming terminate returned no command")
+	}
+	if got, want := cmd(), (SignalBackendMsg{PID: 10, Terminate: true}); got != want {
+		t.Errorf("confirming terminate sent %#v, want %#v", got, want)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if cmd == nil {
+		t.Fatal("cancelling an active blocker returned no command")
+	}
+	if got, want := cmd(), (SignalBackendMsg{PID: 30}); got != want {
+		t.Errorf("cancel sent %#v, want %#v", got, want)
+	}
+
+	// The selection follows the blocker across refreshes
+	m.SetLocks(testLocks()[1:], nil, time.Now())
+	if m.SelectedPID() != 30 {
+		t.Errorf("SelectedPID() after refresh = %d, want 30", m.SelectedPID())
+	}
+}
+
+func TestFormatWait(t *testing.T) {
+	tests := map[time.Duration]string{
+		12 * time.Second:               "12s",
+		3*time.Minute + 5*time.Second:  "3m05s",
+		62*time.Minute + 9*time.Second: "1h02m",
+	}
+	for d, want := range tests {
+		if got := FormatWait(d); got != want {
+			t.Errorf("FormatWait(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaskedValue is shown in place of the value of a masked cell
+const MaskedValue = "••••"
+
+// RevealCellKey shows or hides the real value of the selected masked cell
+const RevealCellKey = "u"
+
+// MaskRules decide which columns have their values masked, e.g. while
+// sharing the screen. A pattern matches a column whose name contains it,
+// ignoring case; a pattern written /like this/ is a regular expression,
+// also matched ignoring case.
+type MaskRules struct {
+	substrings []string
+	patterns   []*regexp.Regexp
+
+	// CopyMask copies the mask instead of the real value of a masked cell
+	// that hasn't been revealed
+	CopyMask bool
+}
+
+// NewMaskRules compiles masking patterns. Invalid regular expressions are
+// left out and reported in the error; the rest still apply.
+func NewMaskRules(patterns []string, copyMask bool) (*MaskRules, error) {
+	rules := &MaskRules{CopyMask: copyMask}
+	var invalid []string
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if len(p) > 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+			re, err := regexp.Compile("(?i)" + p[1:len(p)-1])
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s: %v", p, err))
+				continue
+			}
+			rules.patterns = append(rules.patterns, re)
+			continue
+		}
+		rules.substrings = append(rules.substrings, strings.ToLower(p))
+	}
+	if len(invalid) > 0 {
+		return rules, fmt.Errorf("invalid mask patterns:\n%s", strings.Join(invalid, "\n"))
+	}
+	return rules, nil
+}
+
+// Matches reports whether values of column are masked
+func (m *MaskRules) Matches(column string) bool {
+	if m == nil {
+		return false
+	}
+	lower := strings.ToLower(column)
+	for _, s := range m.substrings {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty reports whether no column can match
+func (m *MaskRules) Empty() bool {
+	return m == nil || (len(m.substrings) == 0 && len(m.patterns) == 0)
+}
@@ -0,0 +1,91 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestMaskRules_Matches(t *testing.T) {
+	rules, err := NewMaskRules([]string{"password", "Token", "/^ssn$/", ""}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		column string
+		want   bool
+	}{
+		{"password_hash", true},
+		{"API_TOKEN", true},
+		{"ssn", true},
+		{"SSN", true},
+		{"ssn_last4", false}, // The regex is anchored
+		{"email", false},
+	}
+	for _, tt := range tests {
+		if got := rules.Matches(tt.column); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.column, got, tt.want)
+		}
+	}
+
+	var none *MaskRules
+	if none.Matches("password") || !none.Empty() {
+		t.Error("nil rules mask columns")
+	}
+}
+
+func TestMaskRules_InvalidPattern(t *testing.T) {
+	rules, err := NewMaskRules([]string{"/[/", "secret"}, false)
+	if err == nil || !strings.Contains(err.Error(), "/[/") {
+		t.Errorf("error = %v, want one naming the invalid pattern", err)
+	}
+	if !rules.Matches("client_secret") {
+		t.Error("valid patterns were dropped along with the invalid one")
+	}
+}
+
+func TestTableView_Masking(t *testing.T) {
+	rules, _ := NewMaskRules([]string{"password"}, false)
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width, tv.Height = 80, 10
+	tv.SetMasks(rules)
+	tv.SetData([]string{"name", "password"}, [][]string{{"alice", "hunter2"}, {"bob", "NULL"}}, 2)
+
+	if view := tv.View(); strings.Contains(view, "hunter2") || !strings.Contains(view, MaskedValue) {
+		t.Errorf("masked value shown:\n%s", view)
+	}
+	if tv.IsCellMasked(1, 1) {
+		t.Error("NULL cell is masked")
+	}
+	if tv.SearchLocal("hunter"); len(tv.Matches) != 0 {
+		t.Errorf("search matched a masked cell: %v", tv.Matches)
+	}
+	if got := tv.CopyValue(0, 1); got != "hunter2" {
+		t.Errorf("CopyValue() = %q, want the real value", got)
+	}
+
+	tv.SelectedRow, tv.SelectedCol = 0, 1
+	if !tv.ToggleReveal() {
+		t.Fatal("ToggleReveal() on a masked column reported no mask")
+	}
+	if view := tv.View(); !strings.Contains(view, "hunter2") {
+		t.Errorf("revealed value not shown:\n%s", view)
+	}
+
+	// Reloading hides the cell again
+	tv.SetData(tv.Columns, tv.Rows, 2)
+	if !tv.IsCellMasked(0, 1) {
+		t.Error("cell still revealed after a reload")
+	}
+
+	tv.SelectedCol = 0
+	if tv.ToggleReveal() {
+		t.Error("ToggleReveal() on an unmasked column reported a mask")
+	}
+
+	rules.CopyMask = true
+	if got := tv.CopyValue(0, 1); got != MaskedValue {
+		t.Errorf("CopyValue() with mask_on_copy = %q, want the mask", got)
+	}
+}
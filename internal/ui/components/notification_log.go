@@ -0,0 +1,346 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// maxNotificationEntries caps the log so a chatty channel can't grow it forever
+const maxNotificationEntries = 1000
+
+// ListenChannelMsg is sent when the user wants to LISTEN on a channel
+type ListenChannelMsg struct {
+	Channel string
+}
+
+// UnlistenChannelMsg is sent when the user wants to UNLISTEN a channel
+type UnlistenChannelMsg struct {
+	Channel string
+}
+
+// SendNotifyMsg is sent when the user wants to send a NOTIFY
+type SendNotifyMsg struct {
+	Channel string
+	Payload string
+}
+
+// CloseNotificationLogMsg is sent when the notification log should close
+type CloseNotificationLogMsg struct{}
+
+// NotificationEntry is a line in the notification log: either a received
+// notification or a status message
+type NotificationEntry struct {
+	Time    time.Time
+	Channel string
+	Payload string
+	PID     uint32
+	Status  string // Set for status lines instead of notifications
+	IsError bool
+}
+
+// NotificationLog is a scrollable log of LISTEN/NOTIFY traffic
+type NotificationLog struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	entries  []NotificationEntry
+	channels []string
+	offset   int  // First visible entry
+	follow   bool // Stick to the newest entry
+	unread   int
+
+	// Prompt state: "", "listen", "unlisten", "notify-channel", "notify-payload"
+	prompt        string
+	input         textinput.Model
+	notifyChannel string
+}
+
+// NewNotificationLog creates a new notification log
+func NewNotificationLog(th theme.Theme) *NotificationLog {
+	ti := textinput.New()
+	ti.CharLimit = 8000 // NOTIFY payloads are limited to 8000 bytes
+	ti.Width = 50
+
+	return &NotificationLog{
+		Width:  80,
+		Height: 24,
+		Theme:  th,
+		follow: true,
+		input:  ti,
+	}
+}
+
+// Open marks all notifications as read. prompt optionally starts a prompt
+// ("listen" or "notify-channel").
+func (n *NotificationLog) Open(prompt string) {
+	n.unread = 0
+	n.follow = true
+	n.startPrompt(prompt)
+}
+
+// Unread returns the number of notifications received while closed
+func (n *NotificationLog) Unread() int {
+	return n.unread
+}
+
+// SetChannels updates the list of channels being listened on
+func (n *NotificationLog) SetChannels(channels []string) {
+	n.channels = channels
+}
+
+// Channels returns the channels being listened on
+func (n *NotificationLog) Channels() []string {
+	return n.channels
+}
+
+// AddNotification appends a received notification. visible reports whether
+// the log is on screen, so unseen notifications can be counted.
+func (n *NotificationLog) AddNotification(channel, payload string, pid uint32, at time.Time, visible bool) {
+	n.append(NotificationEntry{Time: at, Channel: channel, Payload: payload, PID: pid})
+	if !visible {
+		n.unread++
+	}
+}
+
+// AddStatus appends a status line
+func (n *NotificationLog) AddStatus(status string, isError bool) {
+	n.append(NotificationEntry{Time: time.Now(), Status: status, IsError: isError})
+}
+
+// IsPrompting returns true while a text prompt has focus
+func (n *NotificationLog) IsPrompting() bool {
+	return n.prompt != ""
+}
+
+func (n *NotificationLog) append(entry NotificationEntry) {
+	n.entries = append(n.entries, entry)
+	if len(n.entries) > maxNotificationEntries {
+		drop := len(n.entries) - maxNotificationEntries
+		n.entries = n.entries[drop:]
+		n.offset -= drop
+		if n.offset < 0 {
+			n.offset = 0
+		}
+	}
+	if n.follow {
+		n.scrollToEnd()
+	}
+}
+
+// visibleLines returns how many log entries fit in the view
+func (n *NotificationLog) visibleLines() int {
+	lines := n.Height - 12
+	if lines < 3 {
+		lines = 3
+	}
+	return lines
+}
+
+func (n *NotificationLog) maxOffset() int {
+	limit := len(n.entries) - n.visibleLines()
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+func (n *NotificationLog) scrollToEnd() {
+	n.offset = n.maxOffset()
+}
+
+func (n *NotificationLog) startPrompt(prompt string) {
+	n.prompt = prompt
+	if prompt == "" {
+		n.input.Blur()
+		return
+	}
+	n.input.SetValue("")
+	switch prompt {
+	case "listen", "notify-channel":
+		n.input.Placeholder = "channel"
+	case "unlisten":
+		n.input.Placeholder = "channel"
+		if len(n.channels) == 1 {
+			n.input.SetValue(n.channels[0])
+		}
+	case "notify-payload":
+		n.input.Placeholder = "payload (optional)"
+	}
+	n.input.CursorEnd()
+	n.input.Focus()
+}
+
+// Update handles keyboard input
+func (n *NotificationLog) Update(msg tea.KeyMsg) (*NotificationLog, tea.Cmd) {
+	if n.prompt != "" {
+		return n.handlePromptKeys(msg)
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return n, func() tea.Msg { return CloseNotificationLogMsg{} }
+	case "up", "k":
+		if n.offset > 0 {
+			n.offset--
+		}
+		n.follow = false
+	case "down", "j":
+		if n.offset < n.maxOffset() {
+			n.offset++
+		}
+		n.follow = n.offset == n.maxOffset()
+	case "g", "home":
+		n.offset = 0
+		n.follow = false
+	case "G", "end":
+		n.scrollToEnd()
+		n.follow = true
+	case "l":
+		n.startPrompt("listen")
+	case "u":
+		n.startPrompt("unlisten")
+	case "n":
+		n.startPrompt("notify-channel")
+	case "c":
+		n.entries = nil
+		n.offset = 0
+		n.follow = true
+	}
+	return n, nil
+}
+
+// handlePromptKeys handles input while a prompt is open
+func (n *NotificationLog) handlePromptKeys(msg tea.KeyMsg) (*NotificationLog, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		n.startPrompt("")
+		return n, nil
+	case "enter":
+		value := n.input.Value()
+		channel := strings.TrimSpace(value)
+		switch n.prompt {
+		case "listen":
+			if channel == "" {
+				return n, nil
+			}
+			n.startPrompt("")
+			return n, func() tea.Msg { return ListenChannelMsg{Channel: channel} }
+		case "unlisten":
+			if channel == "" {
+				return n, nil
+			}
+			n.startPrompt("")
+			return n, func() tea.Msg { return UnlistenChannelMsg{Channel: channel} }
+		case "notify-channel":
+			if channel == "" {
+				return n, nil
+			}
+			n.notifyChannel = channel
+			n.startPrompt("notify-payload")
+			return n, nil
+		case "notify-payload":
+			target := n.notifyChannel
+			n.startPrompt("")
+			return n, func() tea.Msg { return SendNotifyMsg{Channel: target, Payload: value} }
+		}
+		return n, nil
+	}
+
+	var cmd tea.Cmd
+	n.input, cmd = n.input.Update(msg)
+	return n, cmd
+}
+
+// View renders the notification log
+func (n *NotificationLog) View() string {
+	var sections []string
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(n.Theme.Foreground).
+		Background(n.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	sections = append(sections, titleStyle.Render("Notifications (LISTEN/NOTIFY)"))
+
+	instrStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#a6adc8")).
+		Padding(0, 1)
+	if n.prompt != "" {
+		sections = append(sections, instrStyle.Render("Enter: Confirm  Esc: Cancel"))
+	} else {
+		sections = append(sections, instrStyle.Render("l: Listen  u: Unlisten  n: Notify  c: Clear  ↑↓/g/G: Scroll  Esc: Close"))
+	}
+
+	metaStyle := lipgloss.NewStyle().Foreground(n.Theme.Metadata)
+	channels := "none"
+	if len(n.channels) > 0 {
+		channels = strings.Join(n.channels, ", ")
+	}
+	sections = append(sections, "", metaStyle.Render("Listening on: "+channels), "")
+
+	// Log entries
+	if len(n.entries) == 0 {
+		sections = append(sections, metaStyle.Render("No notifications yet"))
+	} else {
+		channelStyle := lipgloss.NewStyle().Foreground(n.Theme.Info).Bold(true)
+		statusStyle := lipgloss.NewStyle().Foreground(n.Theme.Success)
+		errorStyle := lipgloss.NewStyle().Foreground(n.Theme.Error)
+
+		end := n.offset + n.visibleLines()
+		if end > len(n.entries) {
+			end = len(n.entries)
+		}
+		maxPayload := n.Width - 30
+		if maxPayload < 10 {
+			maxPayload = 10
+		}
+		for _, e := range n.entries[n.offset:end] {
+			ts := metaStyle.Render(e.Time.Format("15:04:05"))
+			if e.Status != "" {
+				style := statusStyle
+				if e.IsError {
+					style = errorStyle
+				}
+				sections = append(sections, ts+"  "+style.Render(e.Status))
+				continue
+			}
+			// Keep each notification on one line
+			payload := strings.ReplaceAll(e.Payload, "\n", "↵")
+			if len([]rune(payload)) > maxPayload {
+				payload = string([]rune(payload)[:maxPayload-3]) + "..."
+			}
+			sections = append(sections, fmt.Sprintf("%s  %s %s %s", ts, channelStyle.Render(e.Channel),
+				metaStyle.Render(fmt.Sprintf("[pid %d]", e.PID)), payload))
+		}
+		if n.offset > 0 || end < len(n.entries) {
+			sections = append(sections, metaStyle.Render(fmt.Sprintf("%d-%d of %d", n.offset+1, end, len(n.entries))))
+		}
+	}
+
+	// Prompt
+	switch n.prompt {
+	case "listen":
+		sections = append(sections, "", "LISTEN "+n.input.View())
+	case "unlisten":
+		sections = append(sections, "", "UNLISTEN "+n.input.View())
+	case "notify-channel":
+		sections = append(sections, "", "NOTIFY "+n.input.View())
+	case "notify-payload":
+		sections = append(sections, "", fmt.Sprintf("NOTIFY %s, %s", n.notifyChannel, n.input.View()))
+	}
+
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(n.Theme.Border).
+		Width(n.Width).
+		Padding(1)
+
+	return containerStyle.Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,52 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// ConnectionCommands turns the open connections into palette entries that
+// run sql on them without making them the active connection. The active
+// connection is listed last, since the point is usually to run the query
+// somewhere else; picking it runs the query as usual.
+func ConnectionCommands(conns []*connection.Connection, activeID, sql string) []models.Command {
+	sorted := make([]*connection.Connection, 0, len(conns))
+	for _, conn := range conns {
+		if conn.Pool != nil && conn.Connected {
+			sorted = append(sorted, conn)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if (sorted[i].ID == activeID) != (sorted[j].ID == activeID) {
+			return sorted[j].ID == activeID
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	cmds := make([]models.Command, 0, len(sorted))
+	for _, conn := range sorted {
+		cfg := conn.Config
+		description := fmt.Sprintf("%s@%s:%d/%s", cfg.User, cfg.Host, cfg.Port, cfg.Database)
+		target := conn.ID
+		if conn.ID == activeID {
+			description = "active · " + description
+			target = ""
+		}
+		cmds = append(cmds, models.Command{
+			ID:          "connection:" + conn.ID,
+			Type:        models.CommandTypeAction,
+			Label:       conn.ID,
+			Description: description,
+			Icon:        "🔌",
+			Tags:        []string{cfg.Host, cfg.Database, cfg.User},
+			Action: func() tea.Msg {
+				return ExecuteQueryMsg{SQL: sql, QuickQuery: true, ConnectionID: target}
+			},
+		})
+	}
+	return cmds
+}
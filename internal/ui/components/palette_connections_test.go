@@ -0,0 +1,59 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestConnectionCommands(t *testing.T) {
+	pool := &connection.Pool{}
+	conns := []*connection.Connection{
+		{ID: "staging", Config: models.ConnectionConfig{User: "app", Host: "staging.db", Port: 5432, Database: "shop"}, Pool: pool, Connected: true},
+		{ID: "prod", Config: models.ConnectionConfig{User: "app", Host: "prod.db", Port: 5432, Database: "shop"}, Pool: pool, Connected: true},
+		{ID: "broken", Pool: nil},
+		{ID: "analytics", Pool: pool, Connected: true},
+	}
+
+	cmds := ConnectionCommands(conns, "prod", "SELECT count(*) FROM orders")
+	if len(cmds) != 3 {
+		t.Fatalf("ConnectionCommands() returned %d entries, want the 3 connected ones", len(cmds))
+	}
+	// The active connection comes last
+	want := []string{"analytics", "staging", "prod"}
+	for i, label := range want {
+		if cmds[i].Label != label {
+			t.Errorf("entry %d = %q, want %q", i, cmds[i].Label, label)
+		}
+	}
+	if cmds[2].Description != "active · app@prod.db:5432/shop" {
+		t.Errorf("active entry description = %q", cmds[2].Description)
+	}
+
+	msg, ok := cmds[1].Action().(ExecuteQueryMsg)
+	if !ok || msg.ConnectionID != "staging" || msg.SQL != "SELECT count(*) FROM orders" || !msg.QuickQuery {
+		t.Errorf("Action() = %#v, want the query run on staging", cmds[1].Action())
+	}
+	// Picking the active connection runs the query as usual
+	if msg, _ := cmds[2].Action().(ExecuteQueryMsg); msg.ConnectionID != "" {
+		t.Errorf("active entry runs on %q, want the active connection", msg.ConnectionID)
+	}
+}
+
+func TestResultTabs_PendingConnection(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+	rt.StartPendingQuery("SELECT 1")
+	rt.SetPendingConnection("staging")
+	if tab := rt.GetActiveTab(); tab.Title != "Executing on staging..." {
+		t.Errorf("pending title = %q", tab.Title)
+	}
+
+	rt.CompletePendingQuery("SELECT 1", models.QueryResult{Columns: []string{"?column?"}, Rows: [][]string{{"1"}}})
+	tab := rt.GetActiveTab()
+	if tab.ConnectionID != "staging" || !strings.HasSuffix(tab.Title, " @ staging") {
+		t.Errorf("finished tab = %q on %q, want the title to name staging", tab.Title, tab.ConnectionID)
+	}
+}
@@ -0,0 +1,52 @@
+package components
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// SwitchDatabaseMsg asks to make Database on the active server the active
+// connection
+type SwitchDatabaseMsg struct {
+	Database string
+}
+
+// DatabaseCommands turns the databases of a server into palette entries
+// that switch to them. The current database is listed last, so Enter on the
+// first entry always switches somewhere; databases with a connection still
+// open are marked, since switching back to them is instant.
+func DatabaseCommands(databases []metadata.Database, current string, open map[string]bool) []models.Command {
+	cmds := make([]models.Command, 0, len(databases))
+	var currentCmd *models.Command
+	for _, db := range databases {
+		name := db.Name
+		description := db.Size + " · owner " + db.Owner
+		switch {
+		case name == current:
+			description = "current · " + description
+		case open[name]:
+			description = "open · " + description
+		}
+		cmd := models.Command{
+			ID:          "database:" + name,
+			Type:        models.CommandTypeAction,
+			Label:       name,
+			Description: description,
+			Icon:        "🗄",
+			Tags:        []string{db.Owner},
+			Action: func() tea.Msg {
+				return SwitchDatabaseMsg{Database: name}
+			},
+		}
+		if name == current {
+			currentCmd = &cmd
+			continue
+		}
+		cmds = append(cmds, cmd)
+	}
+	if currentCmd != nil {
+		cmds = append(cmds, *currentCmd)
+	}
+	return cmds
+}
@@ -0,0 +1,53 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestDatabaseCommands(t *testing.T) {
+	databases := []metadata.Database{
+		{Name: "analytics", Owner: "bob", Size: "12 MB"},
+		{Name: "app", Owner: "alice", Size: "1 GB"},
+		{Name: "postgres", Owner: "postgres", Size: "8 MB"},
+	}
+
+	cmds := DatabaseCommands(databases, "app", map[string]bool{"postgres": true})
+	if len(cmds) != 3 {
+		t.Fatalf("DatabaseCommands() returned %d entries, want 3", len(cmds))
+	}
+
+	want := []struct{ label, description string }{
+		{"analytics", "12 MB · owner bob"},
+		{"postgres", "open · 8 MB · owner postgres"},
+		{"app", "current · 1 GB · owner alice"},
+	}
+	for i, w := range want {
+		if cmds[i].Label != w.label || cmds[i].Description != w.description {
+			t.Errorf("entry %d = %q %q, want %q %q", i, cmds[i].Label, cmds[i].Description, w.label, w.description)
+		}
+	}
+
+	msg, ok := cmds[1].Action().(SwitchDatabaseMsg)
+	if !ok || msg.Database != "postgres" {
+		t.Errorf("Action() = %#v, want SwitchDatabaseMsg for postgres", cmds[1].Action())
+	}
+}
+
+func TestCommandPaletteDatabasesMode(t *testing.T) {
+	cp := NewCommandPalette(theme.DefaultTheme())
+	cp.SetCommands([]models.Command{{ID: "help", Label: "Help"}})
+	cp.SetDatabases(DatabaseCommands([]metadata.Database{{Name: "analytics"}, {Name: "app"}}, "app", nil))
+	cp.SetInput("%ana")
+
+	if cp.Mode != PaletteModeDatabases {
+		t.Fatalf("Mode = %v, want PaletteModeDatabases", cp.Mode)
+	}
+	if len(cp.Filtered) != 1 || !strings.HasPrefix(cp.Filtered[0].ID, "database:analytics") {
+		t.Errorf("Filtered = %v, want only the analytics database", cp.Filtered)
+	}
+}
@@ -0,0 +1,133 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/history"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// PaletteHistoryLimit is how many past queries the palette's history mode
+// searches
+const PaletteHistoryLimit = 500
+
+// paletteHistoryLabelLen is the most cells of a query shown in its palette
+// entry
+const paletteHistoryLabelLen = 60
+
+// LoadHistoryQueryMsg asks to put a query from history into the SQL editor
+type LoadHistoryQueryMsg struct {
+	SQL string
+}
+
+// HistoryCommands turns the stats of past queries, most recently run
+// first, into palette entries. Each is labelled with the start of its query
+// on one line and described by where and when it last ran; search matches
+// the whole query. The selected entry's run stats are shown below the list.
+// Choosing one loads its latest text into the SQL editor rather than
+// running it.
+func HistoryCommands(stats []history.QueryStats) []models.Command {
+	cmds := make([]models.Command, 0, len(stats))
+	for _, s := range stats {
+		entry := s.Latest
+		sql := entry.Query
+		oneLine := history.NormalizeQuery(sql)
+		cmds = append(cmds, models.Command{
+			ID:          fmt.Sprintf("history:%d", entry.ID),
+			Type:        models.CommandTypeHistory,
+			Label:       truncateToWidth(oneLine, paletteHistoryLabelLen),
+			Description: historyDescription(s),
+			Icon:        "📜",
+			Tags:        []string{entry.ConnectionName, entry.DatabaseName},
+			SearchText:  oneLine,
+			Detail:      historyDetail(s),
+			Action: func() tea.Msg {
+				return LoadHistoryQueryMsg{SQL: sql}
+			},
+		})
+	}
+	return cmds
+}
+
+// historyDescription names the connection, database and time of a query's
+// last run, whether it failed, and how often the query ran
+func historyDescription(s history.QueryStats) string {
+	entry := s.Latest
+	var parts []string
+	if !entry.Success {
+		parts = append(parts, "✗ failed")
+	}
+	if s.Runs > 1 {
+		parts = append(parts, fmt.Sprintf("×%d", s.Runs))
+	}
+	if entry.ConnectionName != "" && entry.ConnectionName != entry.DatabaseName {
+		parts = append(parts, entry.ConnectionName)
+	}
+	if entry.DatabaseName != "" {
+		parts = append(parts, entry.DatabaseName)
+	}
+	if !entry.ExecutedAt.IsZero() {
+		parts = append(parts, entry.ExecutedAt.Local().Format("Jan 2 15:04"))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// historyDetail sums up a query's runs: how many there were, the spread of
+// their durations with a sparkline of the latest, and the last error
+func historyDetail(s history.QueryStats) string {
+	runs := fmt.Sprintf("%d runs", s.Runs)
+	if s.Runs == 1 {
+		runs = "1 run"
+	}
+	if s.Failures > 0 {
+		runs += fmt.Sprintf(", %d failed", s.Failures)
+	}
+	parts := []string{runs}
+	if len(s.Durations) > 0 {
+		parts = append(parts, fmt.Sprintf("min %s  avg %s  max %s  %s",
+			statDuration(s.MinDuration), statDuration(s.AvgDuration), statDuration(s.MaxDuration),
+			Sparkline(s.Durations)))
+	}
+	if s.LastError != "" {
+		when := ""
+		if !s.LastErrorAt.IsZero() {
+			when = " " + s.LastErrorAt.Local().Format("Jan 2 15:04")
+		}
+		parts = append(parts, fmt.Sprintf("last error%s: %s", when, strings.Join(strings.Fields(s.LastError), " ")))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// statDuration formats a run duration, which history keeps to the
+// millisecond
+func statDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// sparkBars are the bars of a sparkline, lowest first
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws durations as bars scaled from the shortest to the
+// longest; equal durations draw as low bars
+func Sparkline(durations []time.Duration) string {
+	if len(durations) == 0 {
+		return ""
+	}
+	lo, hi := slices.Min(durations), slices.Max(durations)
+	bars := make([]rune, len(durations))
+	for i, d := range durations {
+		level := 0
+		if hi > lo {
+			level = int(int64(d-lo) * int64(len(sparkBars)-1) / int64(hi-lo))
+		}
+		bars[i] = sparkBars[level]
+	}
+	return string(bars)
+}
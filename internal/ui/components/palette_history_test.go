@@ -0,0 +1,82 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rebelice/lazypg/internal/history"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestHistoryCommands(t *testing.T) {
+	ran := time.Date(2025, 3, 4, 15, 30, 0, 0, time.Local)
+	entries := []history.HistoryEntry{
+		{
+			ID:             2,
+			ConnectionName: "prod",
+			DatabaseName:   "shop",
+			Query:          "SELECT o.id, o.total\nFROM orders o\nJOIN customers c ON c.id = o.customer_id\nWHERE c.email = 'alice@example.com'",
+			ExecutedAt:     ran,
+			Success:        true,
+		},
+		{ID: 1, DatabaseName: "shop", Query: "DELETE FROM carts", Success: false},
+	}
+
+	cmds := HistoryCommands(history.Aggregate(entries))
+	if len(cmds) != 2 {
+		t.Fatalf("HistoryCommands() returned %d entries, want 2", len(cmds))
+	}
+	if got := cmds[0].Label; strings.Contains(got, "\n") || !strings.HasPrefix(got, "SELECT o.id, o.total FROM orders o") || !strings.HasSuffix(got, "…") {
+		t.Errorf("label = %q, want the query on one line, cut short", got)
+	}
+	if got, want := cmds[0].Description, "prod • shop • Mar 4 15:30"; got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+	if got, want := cmds[1].Description, "✗ failed • shop"; got != want {
+		t.Errorf("description of a failed query = %q, want %q", got, want)
+	}
+
+	if msg, ok := cmds[0].Action().(LoadHistoryQueryMsg); !ok || msg.SQL != entries[0].Query {
+		t.Errorf("Action() = %#v, want the full query loaded into the editor", msg)
+	}
+
+	// Search matches text past the end of the label
+	cp := NewCommandPalette(theme.DefaultTheme())
+	cp.SetHistory(cmds)
+	cp.SetInput("#alice@example")
+	if len(cp.Filtered) != 1 || cp.Filtered[0].ID != "history:2" {
+		t.Errorf("search for text in the WHERE clause found %v", cp.Filtered)
+	}
+}
+
+func TestHistoryCommands_Stats(t *testing.T) {
+	ms := time.Millisecond
+	entries := []history.HistoryEntry{
+		{ID: 3, DatabaseName: "shop", Query: "SELECT count(*)\nFROM orders", Duration: 40 * ms, Success: true},
+		{ID: 2, DatabaseName: "shop", Query: "SELECT count(*) FROM orders", Success: false, ErrorMessage: "canceling statement\ndue to statement timeout"},
+		{ID: 1, DatabaseName: "shop", Query: "SELECT  count(*) FROM orders", Duration: 1200 * ms, Success: true},
+	}
+
+	cmds := HistoryCommands(history.Aggregate(entries))
+	if len(cmds) != 1 {
+		t.Fatalf("HistoryCommands() returned %d entries, want the runs grouped into 1", len(cmds))
+	}
+	if got, want := cmds[0].Description, "×3 • shop"; got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+	want := "3 runs, 1 failed • min 40ms  avg 620ms  max 1.2s  █▁ • last error: canceling statement due to statement timeout"
+	if got := cmds[0].Detail; got != want {
+		t.Errorf("detail = %q, want %q", got, want)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	ms := time.Millisecond
+	if got, want := Sparkline([]time.Duration{10 * ms, 50 * ms, 90 * ms}), "▁▄█"; got != want {
+		t.Errorf("Sparkline() = %q, want %q", got, want)
+	}
+	if got, want := Sparkline([]time.Duration{5 * ms, 5 * ms}), "▁▁"; got != want {
+		t.Errorf("Sparkline() of equal durations = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// PaletteObjectPrefix starts the ID of a palette entry for a database
+// object; the rest is the object's tree node ID
+const PaletteObjectPrefix = "object:"
+
+// paletteObjectKind is how an object type is shown in the palette and
+// found in the tree. Icons match the tree's.
+type paletteObjectKind struct {
+	nodePrefix string // Tree node ID prefix
+	icon       string
+	name       string
+}
+
+var paletteObjectKinds = map[string]paletteObjectKind{
+	"table":            {"table", "▦", "table"},
+	"view":             {"view", "◎", "view"},
+	"matview":          {"matview", "◉", "materialized view"},
+	"foreign_table":    {"foreigntable", ForeignTableIcon, "foreign table"},
+	"function":         {"function", "ƒ", "function"},
+	"procedure":        {"procedure", "⚙", "procedure"},
+	"trigger_function": {"triggerfunction", "⚡", "trigger function"},
+	"sequence":         {"sequence", "#", "sequence"},
+	"composite_type":   {"compositetype", "◫", "composite type"},
+	"enum_type":        {"enumtype", "◧", "enum type"},
+	"domain_type":      {"domaintype", "◨", "domain type"},
+	"range_type":       {"rangetype", "◩", "range type"},
+}
+
+// SchemaObjectCommands turns the objects of a database into palette entries
+// labelled schema.name, so they can be found by qualified name. Objects in
+// system schemas are left out unless showSystem is set, as in the tree.
+func SchemaObjectCommands(objects []metadata.SchemaObject, database string, showSystem bool) []models.Command {
+	var cmds []models.Command
+	for _, obj := range objects {
+		kind, ok := paletteObjectKinds[obj.ObjectType]
+		if !ok || (!showSystem && models.IsSystemSchema(obj.SchemaName)) {
+			continue
+		}
+		label := obj.SchemaName + "." + obj.ObjectName
+		if obj.ObjectType == "function" || obj.ObjectType == "procedure" {
+			// Tell overloads apart
+			label += "(" + obj.Arguments + ")"
+		}
+		cmds = append(cmds, models.Command{
+			ID:          PaletteObjectPrefix + fmt.Sprintf("%s:%s.%s.%s", kind.nodePrefix, database, obj.SchemaName, obj.ObjectName),
+			Type:        models.CommandTypeObject,
+			Label:       label,
+			Description: kind.name,
+			Icon:        kind.icon,
+			Tags:        []string{obj.ObjectName, obj.SchemaName},
+		})
+	}
+	return cmds
+}
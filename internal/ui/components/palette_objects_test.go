@@ -0,0 +1,40 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/search"
+)
+
+func TestSchemaObjectCommands(t *testing.T) {
+	objects := []metadata.SchemaObject{
+		{SchemaName: "sales", ObjectType: "table", ObjectName: "orders"},
+		{SchemaName: "sales", ObjectType: "matview", ObjectName: "daily_totals"},
+		{SchemaName: "public", ObjectType: "function", ObjectName: "add", Arguments: "a integer, b integer"},
+		{SchemaName: "pg_catalog", ObjectType: "view", ObjectName: "pg_stats"},
+	}
+
+	cmds := SchemaObjectCommands(objects, "shop", false)
+	if len(cmds) != 3 {
+		t.Fatalf("SchemaObjectCommands() returned %d entries, want 3 without system schemas", len(cmds))
+	}
+	want := []struct{ id, label, icon string }{
+		{"object:table:shop.sales.orders", "sales.orders", "▦"},
+		{"object:matview:shop.sales.daily_totals", "sales.daily_totals", "◉"},
+		{"object:function:shop.public.add", "public.add(a integer, b integer)", "ƒ"},
+	}
+	for i, w := range want {
+		if cmds[i].ID != w.id || cmds[i].Label != w.label || cmds[i].Icon != w.icon {
+			t.Errorf("entry %d = %q %q %q, want %q %q %q", i, cmds[i].ID, cmds[i].Label, cmds[i].Icon, w.id, w.label, w.icon)
+		}
+	}
+
+	if !search.FuzzyMatch("sal.ord", cmds[0].Label).Matched {
+		t.Error("qualified fuzzy query does not match the label")
+	}
+
+	if got := SchemaObjectCommands(objects, "shop", true); len(got) != 4 {
+		t.Errorf("SchemaObjectCommands() with system schemas returned %d entries, want 4", len(got))
+	}
+}
@@ -11,13 +11,37 @@ import (
 	"github.com/rebelice/lazypg/internal/ui/theme"
 )
 
+// PreviewPosition is where the preview pane is docked in the data panel
+type PreviewPosition int
+
+const (
+	PreviewBottom PreviewPosition = iota // Below the table
+	PreviewRight                         // Beside the table
+)
+
+// DefaultPreviewSize is the initial pane size, in percent of the data panel
+const DefaultPreviewSize = 33
+
+const (
+	previewSizeStep    = 10 // Percent added/removed per grow/shrink
+	previewMinSize     = 15 // Smallest pane size, in percent
+	previewMaxSize     = 80 // Largest pane size, in percent
+	previewMinHeight   = 5  // Smallest usable pane height (borders, header, footer, one line)
+	previewMinWidth    = 24 // Smallest usable pane width when docked right
+	previewMinMainSize = 5  // Rows/columns always left for the table
+)
+
 // PreviewPane displays full content for truncated values
 type PreviewPane struct {
 	Width     int
-	MaxHeight int    // Maximum height (screen 1/3)
+	MaxHeight int    // Rendered height, set by Layout
 	Content   string // Raw content to display
 	Title     string // Title (column name or JSON path)
 
+	// Docking
+	Position PreviewPosition // Bottom or right of the data panel
+	Size     int             // Percent of the panel height (bottom) or width (right)
+
 	// Visibility state
 	Visible       bool // Whether pane should be shown
 	ForceHidden   bool // User manually hid the pane (overrides auto-show)
@@ -37,6 +61,7 @@ func NewPreviewPane(th theme.Theme) *PreviewPane {
 	return &PreviewPane{
 		Width:       80,
 		MaxHeight:   10,
+		Size:        DefaultPreviewSize,
 		Theme:       th,
 		ForceHidden: true, // Default to hidden, user must press 'p' to show
 		style: lipgloss.NewStyle().
@@ -138,6 +163,75 @@ func (p *PreviewPane) Toggle() {
 	}
 }
 
+// Grow makes the pane larger
+func (p *PreviewPane) Grow() {
+	p.setSize(p.Size + previewSizeStep)
+}
+
+// Shrink makes the pane smaller
+func (p *PreviewPane) Shrink() {
+	p.setSize(p.Size - previewSizeStep)
+}
+
+func (p *PreviewPane) setSize(size int) {
+	p.Size = clampInt(size, previewMinSize, previewMaxSize)
+}
+
+// ToggleDock moves the pane between the bottom and the right of the data panel
+func (p *PreviewPane) ToggleDock() {
+	if p.Position == PreviewBottom {
+		p.Position = PreviewRight
+	} else {
+		p.Position = PreviewBottom
+	}
+	p.contentLines = nil // Width changes, so rewrap
+	p.scrollY = 0
+}
+
+// Layout sizes the pane for a data panel of width x height and returns the
+// space left for the table. The pane is sized to zero when it doesn't fit, so
+// it never pushes the table past the panel (and the status line off screen).
+func (p *PreviewPane) Layout(width, height int) (mainWidth, mainHeight int) {
+	mainWidth, mainHeight = width, height
+	if !p.Visible {
+		return mainWidth, mainHeight
+	}
+
+	paneWidth, paneHeight := width, height
+	if p.Position == PreviewRight {
+		paneWidth = clampInt(width*p.Size/100, previewMinWidth, width-previewMinMainSize)
+		if paneWidth < previewMinWidth || paneHeight < previewMinHeight {
+			paneWidth, paneHeight = 0, 0
+		}
+		mainWidth -= paneWidth
+	} else {
+		paneHeight = clampInt(height*p.Size/100, previewMinHeight, height-previewMinMainSize)
+		if paneHeight < previewMinHeight {
+			paneWidth, paneHeight = 0, 0
+		}
+		mainHeight -= paneHeight
+	}
+
+	if paneWidth != p.Width {
+		p.contentLines = nil // Rewrap for the new width
+	}
+	p.Width = paneWidth
+	p.MaxHeight = paneHeight
+	p.clampScroll()
+	return mainWidth, mainHeight
+}
+
+// clampInt limits v to [lo, hi]; hi wins when the range is empty
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		v = lo
+	}
+	if v > hi {
+		v = hi
+	}
+	return v
+}
+
 // Height returns the rendered height including borders
 // Returns 0 if not visible, otherwise returns MaxHeight for consistent layout
 func (p *PreviewPane) Height() int {
@@ -163,6 +257,13 @@ func (p *PreviewPane) ScrollUp() {
 
 // ScrollDown scrolls content down
 func (p *PreviewPane) ScrollDown() {
+	if p.scrollY < p.maxScroll() {
+		p.scrollY++
+	}
+}
+
+// maxScroll returns the largest scroll offset for the current height
+func (p *PreviewPane) maxScroll() int {
 	maxContentHeight := p.MaxHeight - p.style.GetVerticalFrameSize()
 	maxContentLines := maxContentHeight - 2 // -2 for header and footer
 	if maxContentLines < 1 {
@@ -172,8 +273,16 @@ func (p *PreviewPane) ScrollDown() {
 	if maxScroll < 0 {
 		maxScroll = 0
 	}
-	if p.scrollY < maxScroll {
-		p.scrollY++
+	return maxScroll
+}
+
+// clampScroll keeps the scroll offset in range after a resize
+func (p *PreviewPane) clampScroll() {
+	if p.contentLines == nil && p.Content != "" {
+		p.formatContent()
+	}
+	if p.scrollY > p.maxScroll() {
+		p.scrollY = p.maxScroll()
 	}
 }
 
@@ -189,7 +298,7 @@ func (p *PreviewPane) CopyContent() error {
 
 // View renders the preview pane
 func (p *PreviewPane) View() string {
-	if !p.Visible {
+	if !p.Visible || p.MaxHeight < previewMinHeight {
 		return ""
 	}
 
@@ -262,7 +371,7 @@ func (p *PreviewPane) View() string {
 	if p.IsScrollable() {
 		helpParts = append(helpParts, "J/K:scroll")
 	}
-	helpParts = append(helpParts, "y:copy", "p:close")
+	helpParts = append(helpParts, "+/-:size", "P:dock", "y:copy", "p:close")
 
 	// Add JSONB hint if content is JSON
 	if jsonb.IsJSONB(p.Content) {
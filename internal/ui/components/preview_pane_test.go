@@ -0,0 +1,88 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func newVisiblePreviewPane(content string) *PreviewPane {
+	p := NewPreviewPane(theme.DefaultTheme())
+	p.SetContent(content, "col", true)
+	p.Toggle()
+	return p
+}
+
+func TestPreviewPane_LayoutBottom(t *testing.T) {
+	p := newVisiblePreviewPane("hello")
+
+	mainWidth, mainHeight := p.Layout(100, 30)
+	if mainWidth != 100 || mainHeight != 21 {
+		t.Errorf("main = %dx%d, want 100x21", mainWidth, mainHeight)
+	}
+	if p.Width != 100 || p.Height() != 9 {
+		t.Errorf("pane = %dx%d, want 100x9", p.Width, p.Height())
+	}
+	if got := strings.Count(p.View(), "\n") + 1; got != p.Height() {
+		t.Errorf("rendered %d lines, want %d", got, p.Height())
+	}
+}
+
+func TestPreviewPane_LayoutRight(t *testing.T) {
+	p := newVisiblePreviewPane("hello")
+	p.ToggleDock()
+
+	mainWidth, mainHeight := p.Layout(100, 30)
+	if mainWidth != 67 || mainHeight != 30 {
+		t.Errorf("main = %dx%d, want 67x30", mainWidth, mainHeight)
+	}
+	if p.Width != 33 || p.Height() != 30 {
+		t.Errorf("pane = %dx%d, want 33x30", p.Width, p.Height())
+	}
+}
+
+func TestPreviewPane_LayoutNeverOverflows(t *testing.T) {
+	p := newVisiblePreviewPane("hello")
+
+	// Too short for both the table and the pane: the pane gives way
+	mainWidth, mainHeight := p.Layout(100, 8)
+	if mainWidth != 100 || mainHeight != 8 {
+		t.Errorf("main = %dx%d, want 100x8", mainWidth, mainHeight)
+	}
+	if p.Height() != 0 || p.View() != "" {
+		t.Error("expected the pane to be hidden when it doesn't fit")
+	}
+
+	// At the maximum size the table still keeps its minimum
+	for i := 0; i < 10; i++ {
+		p.Grow()
+	}
+	if p.Size != previewMaxSize {
+		t.Errorf("Size = %d, want %d", p.Size, previewMaxSize)
+	}
+	_, mainHeight = p.Layout(100, 12)
+	if mainHeight+p.Height() != 12 || mainHeight < previewMinMainSize {
+		t.Errorf("main %d + pane %d should fill 12 rows", mainHeight, p.Height())
+	}
+}
+
+func TestPreviewPane_ResizeClampsScroll(t *testing.T) {
+	p := newVisiblePreviewPane(strings.Repeat("line\n", 50))
+	p.Layout(80, 40)
+	for i := 0; i < 100; i++ {
+		p.ScrollDown()
+	}
+	before := p.scrollY
+
+	for i := 0; i < 10; i++ {
+		p.Grow()
+	}
+	p.Layout(80, 40)
+	if p.scrollY >= before {
+		t.Errorf("scroll %d should shrink below %d once the pane grows", p.scrollY, before)
+	}
+	if p.scrollY != p.maxScroll() {
+		t.Errorf("scroll %d should stay at the end (%d)", p.scrollY, p.maxScroll())
+	}
+}
@@ -0,0 +1,643 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/filter"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// QueryBuilderSection is a section of the visual query builder
+type QueryBuilderSection int
+
+const (
+	QueryBuilderSectionTable QueryBuilderSection = iota
+	QueryBuilderSectionColumns
+	QueryBuilderSectionWhere
+	QueryBuilderSectionOrderBy
+	QueryBuilderSectionLimit
+)
+
+// QueryBuilderTableSelectedMsg is sent when the user picks a table, so its
+// columns can be loaded
+type QueryBuilderTableSelectedMsg struct {
+	Schema string
+	Table  string
+}
+
+// QueryBuilderRunMsg is sent with the generated SQL. Edit opens it in the
+// SQL editor instead of running it.
+type QueryBuilderRunMsg struct {
+	SQL  string
+	Edit bool
+}
+
+// CloseQueryBuilderMsg is sent when the query builder should close
+type CloseQueryBuilderMsg struct{}
+
+// QueryBuilder guides the user through building a simple SELECT statement
+type QueryBuilder struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	builder *filter.Builder
+	section QueryBuilderSection
+	cursor  int // Cursor within the current section's list
+
+	// Table selection
+	tables []string // "schema.table"
+
+	// Query state
+	query    models.SelectQuery
+	columns  []models.ColumnInfo
+	selected map[string]bool
+
+	// Condition editing: "", "column", "operator", "value"
+	condMode   string
+	condColumn int
+	condOps    []models.FilterOperator
+	condOp     int
+	valueInput textinput.Model
+
+	limitInput textinput.Model
+
+	previewSQL string
+	previewErr string
+}
+
+// NewQueryBuilder creates a new visual query builder
+func NewQueryBuilder(th theme.Theme) *QueryBuilder {
+	vi := textinput.New()
+	vi.Placeholder = "value"
+	vi.CharLimit = 256
+	vi.Width = 40
+
+	li := textinput.New()
+	li.Placeholder = "no limit"
+	li.CharLimit = 9
+	li.Width = 12
+
+	return &QueryBuilder{
+		Width:      80,
+		Height:     30,
+		Theme:      th,
+		builder:    filter.NewBuilder(),
+		selected:   map[string]bool{},
+		valueInput: vi,
+		limitInput: li,
+	}
+}
+
+// Open resets the builder to the table step, with the cursor on
+// schema.table when it is one of tables. A positive defaultLimit fills in
+// the LIMIT.
+func (qb *QueryBuilder) Open(tables []string, schema, table string, defaultLimit int) {
+	qb.tables = tables
+	qb.query = models.SelectQuery{
+		Schema: schema,
+		Table:  table,
+		Where:  models.FilterGroup{Logic: "AND"},
+	}
+	qb.columns = nil
+	qb.selected = map[string]bool{}
+	qb.condMode = ""
+	qb.section = QueryBuilderSectionTable
+	qb.cursor = 0
+	qb.limitInput.Blur()
+	qb.limitInput.SetValue("")
+	if defaultLimit > 0 {
+		qb.limitInput.SetValue(strconv.Itoa(defaultLimit))
+	}
+
+	// Start the table cursor on the requested table
+	for i, t := range tables {
+		if t == schema+"."+table {
+			qb.cursor = i
+			break
+		}
+	}
+	qb.updatePreview()
+}
+
+// SetColumns loads the columns of the chosen table and moves to column selection
+func (qb *QueryBuilder) SetColumns(schema, table string, columns []models.ColumnInfo) {
+	qb.query.Schema = schema
+	qb.query.Table = table
+	qb.query.Columns = nil
+	qb.query.OrderBy = nil
+	qb.query.Where = models.FilterGroup{Logic: "AND"}
+	qb.columns = columns
+	qb.selected = map[string]bool{}
+	qb.setSection(QueryBuilderSectionColumns)
+	qb.updatePreview()
+}
+
+// IsEditingText returns true while a text input has focus
+func (qb *QueryBuilder) IsEditingText() bool {
+	return qb.condMode == "value" || qb.section == QueryBuilderSectionLimit
+}
+
+// setSection switches sections, focusing the limit input when needed
+func (qb *QueryBuilder) setSection(section QueryBuilderSection) {
+	qb.section = section
+	qb.cursor = 0
+	if section == QueryBuilderSectionLimit {
+		qb.limitInput.Focus()
+	} else {
+		qb.limitInput.Blur()
+	}
+}
+
+// Update handles keyboard input
+func (qb *QueryBuilder) Update(msg tea.KeyMsg) (*QueryBuilder, tea.Cmd) {
+	if qb.condMode != "" {
+		return qb.handleConditionKeys(msg)
+	}
+
+	switch msg.String() {
+	case "esc":
+		return qb, func() tea.Msg { return CloseQueryBuilderMsg{} }
+	case "tab":
+		if qb.columns != nil {
+			qb.setSection(QueryBuilderSectionColumns + (qb.section % QueryBuilderSectionLimit))
+		}
+		return qb, nil
+	case "shift+tab":
+		if qb.columns != nil {
+			prev := qb.section - 1
+			if prev < QueryBuilderSectionColumns {
+				prev = QueryBuilderSectionLimit
+			}
+			qb.setSection(prev)
+		}
+		return qb, nil
+	case "ctrl+e":
+		return qb, qb.runCmd(true)
+	}
+
+	switch qb.section {
+	case QueryBuilderSectionTable:
+		return qb.handleTableKeys(msg)
+	case QueryBuilderSectionLimit:
+		if msg.String() == "enter" {
+			return qb, qb.runCmd(false)
+		}
+		var cmd tea.Cmd
+		qb.limitInput, cmd = qb.limitInput.Update(msg)
+		qb.updatePreview()
+		return qb, cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if qb.cursor > 0 {
+			qb.cursor--
+		}
+	case "down", "j":
+		if qb.cursor < qb.sectionLen()-1 {
+			qb.cursor++
+		}
+	case " ":
+		qb.toggleCurrent()
+	case "a", "n":
+		if qb.section == QueryBuilderSectionWhere && len(qb.columns) > 0 {
+			qb.condMode = "column"
+			qb.condColumn = 0
+		}
+	case "d", "x":
+		if qb.section == QueryBuilderSectionWhere && qb.cursor < len(qb.query.Where.Conditions) {
+			conds := qb.query.Where.Conditions
+			qb.query.Where.Conditions = append(conds[:qb.cursor], conds[qb.cursor+1:]...)
+			if qb.cursor > 0 && qb.cursor >= len(qb.query.Where.Conditions) {
+				qb.cursor--
+			}
+			qb.updatePreview()
+		}
+	case "o":
+		// Toggle AND/OR between conditions
+		if qb.section == QueryBuilderSectionWhere {
+			if qb.query.Where.Logic == "OR" {
+				qb.query.Where.Logic = "AND"
+			} else {
+				qb.query.Where.Logic = "OR"
+			}
+			qb.updatePreview()
+		}
+	case "enter":
+		return qb, qb.runCmd(false)
+	}
+	return qb, nil
+}
+
+// handleTableKeys handles the table selection step
+func (qb *QueryBuilder) handleTableKeys(msg tea.KeyMsg) (*QueryBuilder, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if qb.cursor > 0 {
+			qb.cursor--
+		}
+	case "down", "j":
+		if qb.cursor < len(qb.tables)-1 {
+			qb.cursor++
+		}
+	case "enter":
+		if qb.cursor < len(qb.tables) {
+			parts := strings.SplitN(qb.tables[qb.cursor], ".", 2)
+			if len(parts) == 2 {
+				schema, table := parts[0], parts[1]
+				return qb, func() tea.Msg {
+					return QueryBuilderTableSelectedMsg{Schema: schema, Table: table}
+				}
+			}
+		}
+	}
+	return qb, nil
+}
+
+// handleConditionKeys handles adding a WHERE condition
+func (qb *QueryBuilder) handleConditionKeys(msg tea.KeyMsg) (*QueryBuilder, tea.Cmd) {
+	switch qb.condMode {
+	case "column":
+		switch msg.String() {
+		case "esc":
+			qb.condMode = ""
+		case "up", "k":
+			if qb.condColumn > 0 {
+				qb.condColumn--
+			}
+		case "down", "j":
+			if qb.condColumn < len(qb.columns)-1 {
+				qb.condColumn++
+			}
+		case "enter":
+			qb.condOps = queryBuilderOperators(qb.columns[qb.condColumn].DataType)
+			qb.condOp = 0
+			qb.condMode = "operator"
+		}
+	case "operator":
+		switch msg.String() {
+		case "esc":
+			qb.condMode = "column"
+		case "up", "k":
+			if qb.condOp > 0 {
+				qb.condOp--
+			}
+		case "down", "j":
+			if qb.condOp < len(qb.condOps)-1 {
+				qb.condOp++
+			}
+		case "enter":
+			op := qb.condOps[qb.condOp]
+			if op == models.OpIsNull || op == models.OpIsNotNull {
+				qb.addCondition(nil)
+			} else {
+				qb.condMode = "value"
+				qb.valueInput.SetValue("")
+				qb.valueInput.Focus()
+			}
+		}
+	case "value":
+		switch msg.String() {
+		case "esc":
+			qb.valueInput.Blur()
+			qb.condMode = "operator"
+		case "enter":
+			qb.valueInput.Blur()
+			qb.addCondition(qb.valueInput.Value())
+		default:
+			var cmd tea.Cmd
+			qb.valueInput, cmd = qb.valueInput.Update(msg)
+			return qb, cmd
+		}
+	}
+	return qb, nil
+}
+
+// queryBuilderOperators returns the filter operators for a type, plus IN lists
+// for scalar types since the builder inlines values
+func queryBuilderOperators(dataType string) []models.FilterOperator {
+	ops := filter.GetOperatorsForType(dataType)
+	dt := strings.ToLower(dataType)
+	if strings.Contains(dt, "json") || strings.Contains(dt, "array") || strings.Contains(dt, "bool") {
+		return ops
+	}
+	return append(ops, models.OpIn, models.OpNotIn)
+}
+
+// addCondition appends the condition being edited to the WHERE clause
+func (qb *QueryBuilder) addCondition(value interface{}) {
+	col := qb.columns[qb.condColumn]
+	qb.query.Where.Conditions = append(qb.query.Where.Conditions, models.FilterCondition{
+		Column:   col.Name,
+		Operator: qb.condOps[qb.condOp],
+		Value:    value,
+		Type:     col.DataType,
+	})
+	qb.condMode = ""
+	qb.cursor = len(qb.query.Where.Conditions) - 1
+	qb.updatePreview()
+}
+
+// toggleCurrent toggles the column under the cursor (select or sort order)
+func (qb *QueryBuilder) toggleCurrent() {
+	if qb.cursor >= len(qb.columns) {
+		return
+	}
+	name := qb.columns[qb.cursor].Name
+
+	switch qb.section {
+	case QueryBuilderSectionColumns:
+		qb.selected[name] = !qb.selected[name]
+		// Keep selected columns in table order
+		qb.query.Columns = nil
+		for _, col := range qb.columns {
+			if qb.selected[col.Name] {
+				qb.query.Columns = append(qb.query.Columns, col.Name)
+			}
+		}
+	case QueryBuilderSectionOrderBy:
+		// Cycle: none → ASC → DESC → none, keeping the order terms were added
+		for i, ob := range qb.query.OrderBy {
+			if ob.Column == name {
+				if ob.Desc {
+					qb.query.OrderBy = append(qb.query.OrderBy[:i], qb.query.OrderBy[i+1:]...)
+				} else {
+					qb.query.OrderBy[i].Desc = true
+				}
+				qb.updatePreview()
+				return
+			}
+		}
+		qb.query.OrderBy = append(qb.query.OrderBy, models.OrderByColumn{Column: name})
+	}
+	qb.updatePreview()
+}
+
+// sectionLen returns the number of rows in the current section
+func (qb *QueryBuilder) sectionLen() int {
+	if qb.section == QueryBuilderSectionWhere {
+		return len(qb.query.Where.Conditions)
+	}
+	return len(qb.columns)
+}
+
+// runCmd generates the SQL and requests it be run or edited
+func (qb *QueryBuilder) runCmd(edit bool) tea.Cmd {
+	qb.updatePreview()
+	if qb.previewErr != "" || qb.previewSQL == "" {
+		return nil
+	}
+	msg := QueryBuilderRunMsg{SQL: qb.previewSQL, Edit: edit}
+	return func() tea.Msg { return msg }
+}
+
+// updatePreview regenerates the SQL preview
+func (qb *QueryBuilder) updatePreview() {
+	qb.previewErr = ""
+	qb.query.Limit = 0
+	if v := strings.TrimSpace(qb.limitInput.Value()); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			qb.previewSQL = ""
+			qb.previewErr = "LIMIT must be a non-negative number"
+			return
+		}
+		qb.query.Limit = limit
+	}
+
+	if qb.query.Table == "" {
+		qb.previewSQL = ""
+		return
+	}
+
+	sql, err := qb.builder.BuildSelect(qb.query)
+	if err != nil {
+		qb.previewSQL = ""
+		qb.previewErr = err.Error()
+		return
+	}
+	qb.previewSQL = sql
+}
+
+// View renders the query builder
+func (qb *QueryBuilder) View() string {
+	var sections []string
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(qb.Theme.Foreground).
+		Background(qb.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	title := "Query Builder"
+	if qb.query.Table != "" && qb.columns != nil {
+		title += fmt.Sprintf(" — %s.%s", qb.query.Schema, qb.query.Table)
+	}
+	sections = append(sections, titleStyle.Render(title))
+
+	instrStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#a6adc8")).
+		Padding(0, 1)
+	sections = append(sections, instrStyle.Render(qb.instructions()))
+
+	if qb.columns == nil {
+		sections = append(sections, "", qb.renderTables())
+	} else {
+		sections = append(sections, "", qb.renderTabs(), "")
+		switch qb.section {
+		case QueryBuilderSectionColumns, QueryBuilderSectionOrderBy:
+			sections = append(sections, qb.renderColumns())
+		case QueryBuilderSectionWhere:
+			sections = append(sections, qb.renderWhere())
+		case QueryBuilderSectionLimit:
+			sections = append(sections, "LIMIT "+qb.limitInput.View())
+		}
+	}
+
+	// SQL preview
+	sections = append(sections, "", "SQL Preview:")
+	if qb.previewErr != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(qb.Theme.Error).Padding(0, 1).Render(qb.previewErr))
+	} else if qb.previewSQL != "" {
+		previewStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6c7086")).
+			Padding(0, 1).
+			Italic(true)
+		sections = append(sections, previewStyle.Render(qb.previewSQL))
+	}
+
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(qb.Theme.Border).
+		Width(qb.Width).
+		Padding(1)
+
+	return containerStyle.Render(strings.Join(sections, "\n"))
+}
+
+// instructions returns the key hints for the current state
+func (qb *QueryBuilder) instructions() string {
+	switch qb.condMode {
+	case "column":
+		return "↑↓: Column  Enter: Next  Esc: Cancel"
+	case "operator":
+		return "↑↓: Operator  Enter: Next  Esc: Back"
+	case "value":
+		return "Type value (comma-separate IN lists)  Enter: Add  Esc: Back"
+	}
+
+	switch qb.section {
+	case QueryBuilderSectionTable:
+		return "↑↓: Table  Enter: Select  Esc: Close"
+	case QueryBuilderSectionColumns:
+		return "Space: Toggle column  Tab: Next  Enter: Run  Ctrl+E: Edit SQL  Esc: Close"
+	case QueryBuilderSectionWhere:
+		return "a: Add  d: Delete  o: AND/OR  Tab: Next  Enter: Run  Ctrl+E: Edit SQL"
+	case QueryBuilderSectionOrderBy:
+		return "Space: None/ASC/DESC  Tab: Next  Enter: Run  Ctrl+E: Edit SQL"
+	default:
+		return "Type a row limit  Tab: Next  Enter: Run  Ctrl+E: Edit SQL"
+	}
+}
+
+// renderTabs renders the section tabs
+func (qb *QueryBuilder) renderTabs() string {
+	labels := []struct {
+		section QueryBuilderSection
+		label   string
+	}{
+		{QueryBuilderSectionColumns, "Columns"},
+		{QueryBuilderSectionWhere, "Where"},
+		{QueryBuilderSectionOrderBy, "Order By"},
+		{QueryBuilderSectionLimit, "Limit"},
+	}
+
+	var parts []string
+	for _, l := range labels {
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Padding(0, 1)
+		if l.section == qb.section {
+			style = style.Foreground(qb.Theme.Foreground).Background(qb.Theme.Selection).Bold(true)
+		}
+		parts = append(parts, style.Render(l.label))
+	}
+	return strings.Join(parts, " ")
+}
+
+// visibleRange returns the window of rows to render around the cursor
+func (qb *QueryBuilder) visibleRange(cursor, total int) (int, int) {
+	visible := qb.Height - 16
+	if visible < 5 {
+		visible = 5
+	}
+	start := 0
+	if cursor >= visible {
+		start = cursor - visible + 1
+	}
+	end := start + visible
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// renderTables renders the table picker
+func (qb *QueryBuilder) renderTables() string {
+	if len(qb.tables) == 0 {
+		return lipgloss.NewStyle().Foreground(qb.Theme.Metadata).Render("No tables available")
+	}
+	var lines []string
+	start, end := qb.visibleRange(qb.cursor, len(qb.tables))
+	for i := start; i < end; i++ {
+		lines = append(lines, qb.renderRow(qb.tables[i], i == qb.cursor))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderColumns renders the column list for the Columns and Order By sections
+func (qb *QueryBuilder) renderColumns() string {
+	metaStyle := lipgloss.NewStyle().Foreground(qb.Theme.Metadata)
+	var lines []string
+	start, end := qb.visibleRange(qb.cursor, len(qb.columns))
+	for i := start; i < end; i++ {
+		col := qb.columns[i]
+		var marker string
+		if qb.section == QueryBuilderSectionColumns {
+			marker = "[ ]"
+			if qb.selected[col.Name] {
+				marker = "[x]"
+			}
+		} else {
+			marker = "   "
+			for pos, ob := range qb.query.OrderBy {
+				if ob.Column == col.Name {
+					dir := "ASC"
+					if ob.Desc {
+						dir = "DESC"
+					}
+					marker = fmt.Sprintf("%d %s", pos+1, dir)
+				}
+			}
+		}
+		line := fmt.Sprintf("%-6s %s %s", marker, col.Name, metaStyle.Render(col.DataType))
+		lines = append(lines, qb.renderRow(line, i == qb.cursor))
+	}
+	if len(qb.columns) == 0 {
+		lines = append(lines, metaStyle.Render("Table has no columns"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderWhere renders the WHERE conditions and the condition editor
+func (qb *QueryBuilder) renderWhere() string {
+	metaStyle := lipgloss.NewStyle().Foreground(qb.Theme.Metadata)
+	var lines []string
+
+	conds := qb.query.Where.Conditions
+	if len(conds) == 0 && qb.condMode == "" {
+		lines = append(lines, metaStyle.Render("No conditions. Press 'a' to add one."))
+	}
+	for i, cond := range conds {
+		text := fmt.Sprintf("%s %s %v", cond.Column, cond.Operator, cond.Value)
+		if cond.Operator == models.OpIsNull || cond.Operator == models.OpIsNotNull {
+			text = fmt.Sprintf("%s %s", cond.Column, cond.Operator)
+		}
+		if i > 0 {
+			text = qb.query.Where.Logic + " " + text
+		}
+		lines = append(lines, qb.renderRow(text, i == qb.cursor && qb.condMode == ""))
+	}
+
+	switch qb.condMode {
+	case "column":
+		lines = append(lines, "", "Column:")
+		start, end := qb.visibleRange(qb.condColumn, len(qb.columns))
+		for i := start; i < end; i++ {
+			col := qb.columns[i]
+			lines = append(lines, qb.renderRow(col.Name+" "+metaStyle.Render(col.DataType), i == qb.condColumn))
+		}
+	case "operator":
+		lines = append(lines, "", fmt.Sprintf("%s — operator:", qb.columns[qb.condColumn].Name))
+		for i, op := range qb.condOps {
+			lines = append(lines, qb.renderRow(string(op), i == qb.condOp))
+		}
+	case "value":
+		lines = append(lines, "", fmt.Sprintf("%s %s %s",
+			qb.columns[qb.condColumn].Name, qb.condOps[qb.condOp], qb.valueInput.View()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRow renders a list row, highlighted when selected
+func (qb *QueryBuilder) renderRow(text string, selected bool) string {
+	style := lipgloss.NewStyle().Padding(0, 1)
+	if selected {
+		style = style.Background(qb.Theme.Selection).Foreground(qb.Theme.Foreground)
+	}
+	return style.Render(text)
+}
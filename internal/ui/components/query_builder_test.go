@@ -0,0 +1,101 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func queryBuilderKey(qb *QueryBuilder, keys ...string) tea.Cmd {
+	special := map[string]tea.KeyMsg{
+		"up": {Type: tea.KeyUp}, "down": {Type: tea.KeyDown}, "enter": {Type: tea.KeyEnter},
+		"tab": {Type: tea.KeyTab}, "esc": {Type: tea.KeyEsc}, " ": {Type: tea.KeySpace},
+		"backspace": {Type: tea.KeyBackspace},
+	}
+	var cmd tea.Cmd
+	for _, k := range keys {
+		msg, ok := special[k]
+		if !ok {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		_, cmd = qb.Update(msg)
+	}
+	return cmd
+}
+
+func TestQueryBuilder_Open(t *testing.T) {
+	qb := NewQueryBuilder(theme.DefaultTheme())
+	qb.Open([]string{"public.orders", "public.users"}, "public", "users", 100)
+
+	if qb.section != QueryBuilderSectionTable || qb.cursor != 1 {
+		t.Errorf("section %d, cursor %d: want the table step on public.users", qb.section, qb.cursor)
+	}
+	if got := qb.limitInput.Value(); got != "100" {
+		t.Errorf("limit = %q, want the default limit", got)
+	}
+
+	cmd := queryBuilderKey(qb, "enter")
+	if cmd == nil {
+		t.Fatal("enter on a table should ask for its columns")
+	}
+	if msg, ok := cmd().(QueryBuilderTableSelectedMsg); !ok || msg.Schema != "public" || msg.Table != "users" {
+		t.Errorf("enter sent %#v, want public.users selected", cmd())
+	}
+
+	// No default limit leaves the LIMIT empty
+	qb.Open([]string{"public.users"}, "", "", 0)
+	if got := qb.limitInput.Value(); got != "" {
+		t.Errorf("limit = %q, want none", got)
+	}
+}
+
+func TestQueryBuilder_BuildsSelect(t *testing.T) {
+	qb := NewQueryBuilder(theme.DefaultTheme())
+	qb.Open([]string{"public.users"}, "public", "users", 0)
+	qb.SetColumns("public", "users", []models.ColumnInfo{
+		{Name: "id", DataType: "integer"},
+		{Name: "email", DataType: "text"},
+	})
+	if qb.section != QueryBuilderSectionColumns {
+		t.Fatalf("section %d, want columns once they are loaded", qb.section)
+	}
+
+	// Columns: pick email
+	queryBuilderKey(qb, "down", " ")
+	// Where: id = 7
+	queryBuilderKey(qb, "tab", "a", "enter", "enter", "7", "enter")
+	// Order by: id descending
+	queryBuilderKey(qb, "tab", " ", " ")
+
+	want := `SELECT "email"` + "\n" +
+		`FROM "public"."users"` + "\n" +
+		`WHERE "id" = '7'` + "\n" +
+		`ORDER BY "id" DESC`
+	if qb.previewSQL != want {
+		t.Errorf("preview =\n%s\nwant:\n%s", qb.previewSQL, want)
+	}
+
+	cmd := queryBuilderKey(qb, "enter")
+	if cmd == nil {
+		t.Fatal("enter should run the query")
+	}
+	if msg, ok := cmd().(QueryBuilderRunMsg); !ok || msg.SQL != want || msg.Edit {
+		t.Errorf("enter sent %#v, want the query run", cmd())
+	}
+}
+
+func TestQueryBuilder_InvalidLimit(t *testing.T) {
+	qb := NewQueryBuilder(theme.DefaultTheme())
+	qb.Open([]string{"public.users"}, "public", "users", 0)
+	qb.SetColumns("public", "users", []models.ColumnInfo{{Name: "id", DataType: "integer"}})
+
+	queryBuilderKey(qb, "tab", "tab", "tab", "x")
+	if qb.section != QueryBuilderSectionLimit || qb.previewErr == "" {
+		t.Errorf("section %d, error %q: want a LIMIT error", qb.section, qb.previewErr)
+	}
+	if cmd := queryBuilderKey(qb, "enter"); cmd != nil {
+		t.Error("an invalid LIMIT should not run")
+	}
+}
@@ -0,0 +1,141 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// QueryMessagesToggleKey shows or hides a query result's messages
+const QueryMessagesToggleKey = "m"
+
+// HasQueryMessages reports whether a result has notices or per-statement
+// command tags to show
+func HasQueryMessages(result models.QueryResult) bool {
+	return len(result.Notices) > 0 || len(result.Statements) > 0
+}
+
+// QueryMessagesSummary summarizes a result's messages, e.g.
+// "3 statements · 2 notices"
+func QueryMessagesSummary(result models.QueryResult) string {
+	var parts []string
+	if n := len(result.Statements); n > 0 {
+		parts = append(parts, pluralize(n, "statement", "statements"))
+	}
+	if n := len(result.Notices); n > 0 {
+		parts = append(parts, pluralize(n, "notice", "notices"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// QueryMessageLines lists a result's messages. For a script each statement's
+// command tag is listed with the notices it raised beneath it; a statement
+// that failed has no tag but keeps the notices it raised first.
+func QueryMessageLines(result models.QueryResult) []string {
+	var lines []string
+	noticeLines := func(statement int, indent string) {
+		for _, n := range result.Notices {
+			if n.Statement != statement {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s%s: %s", indent, n.Severity, n.Message))
+			if n.Detail != "" {
+				lines = append(lines, fmt.Sprintf("%s  DETAIL: %s", indent, n.Detail))
+			}
+			if n.Hint != "" {
+				lines = append(lines, fmt.Sprintf("%s  HINT: %s", indent, n.Hint))
+			}
+		}
+	}
+
+	if len(result.Statements) == 0 {
+		// A single statement, or a script that failed in its first statement
+		if len(result.Notices) > 0 {
+			noticeLines(result.Notices[0].Statement, "")
+		}
+		return lines
+	}
+
+	last := len(result.Statements)
+	for _, n := range result.Notices {
+		last = max(last, n.Statement)
+	}
+	for i := 1; i <= last; i++ {
+		tag := "(failed)"
+		if i <= len(result.Statements) {
+			tag = result.Statements[i-1]
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s", i, tag))
+		noticeLines(i, "   ")
+	}
+	return lines
+}
+
+// RenderQueryMessages renders a result's messages section: one summary line
+// when collapsed, or the summary and up to maxLines-1 messages when expanded
+func RenderQueryMessages(result models.QueryResult, expanded bool, width, maxLines int, th theme.Theme) string {
+	headerStyle := lipgloss.NewStyle().Foreground(th.Info)
+	summary := QueryMessagesSummary(result)
+
+	if !expanded {
+		return headerStyle.Render(truncateToWidth("▸ Messages: "+summary+" · m to show", width))
+	}
+
+	out := []string{headerStyle.Render(truncateToWidth("▾ Messages: "+summary+" · m to hide", width))}
+
+	lines := QueryMessageLines(result)
+	room := max(maxLines-1, 1)
+	if len(lines) > room {
+		hidden := len(lines) - room + 1
+		lines = append(lines[:room-1:room-1], fmt.Sprintf("… %d more", hidden))
+	}
+
+	warnStyle := lipgloss.NewStyle().Foreground(th.Warning)
+	textStyle := lipgloss.NewStyle().Foreground(th.Foreground)
+	for _, l := range lines {
+		style := textStyle
+		if strings.Contains(l, "WARNING: ") {
+			style = warnStyle
+		}
+		out = append(out, style.Render(truncateToWidth("  "+l, width)))
+	}
+	return strings.Join(out, "\n")
+}
+
+// IsCommandResult reports whether a successful result has no columns, as
+// for SET, DO or an UPDATE without RETURNING. It has nothing to show in a
+// grid, unlike a SELECT that found no rows.
+func IsCommandResult(result models.QueryResult) bool {
+	return result.Error == nil && len(result.Columns) == 0
+}
+
+// RenderCommandResult renders a command result in place of its empty grid:
+// that it succeeded, its command tag and how long it took
+func RenderCommandResult(result models.QueryResult, width, height int, th theme.Theme) string {
+	successStyle := lipgloss.NewStyle().Foreground(th.Success).Bold(true)
+	tagStyle := lipgloss.NewStyle().Foreground(th.Foreground)
+	metaStyle := lipgloss.NewStyle().Foreground(th.Metadata)
+
+	lines := []string{successStyle.Render("✓ Command executed successfully")}
+	if result.CommandTag != "" {
+		lines = append(lines, "", tagStyle.Render(truncateToWidth(result.CommandTag, width)))
+	}
+	took := result.Duration.Round(time.Millisecond)
+	if took == 0 {
+		took = result.Duration.Round(time.Microsecond)
+	}
+	lines = append(lines, "", metaStyle.Render("Took "+took.String()))
+
+	return lipgloss.Place(width, max(height, len(lines)), lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
+
+// showMessagesByDefault expands the messages of a result without rows, where
+// they are all there is to see
+func showMessagesByDefault(result models.QueryResult) bool {
+	return len(result.Columns) == 0 && HasQueryMessages(result)
+}
@@ -0,0 +1,101 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestQueryMessageLines(t *testing.T) {
+	script := models.QueryResult{
+		Statements: []string{"CREATE TABLE", "INSERT 0 3"},
+		Notices: []models.QueryNotice{
+			{Statement: 1, Severity: "NOTICE", Message: "relation \"t\" already exists, skipping"},
+			{Statement: 3, Severity: "WARNING", Message: "low stock", Hint: "reorder soon"},
+		},
+	}
+	got := QueryMessageLines(script)
+	want := []string{
+		"1. CREATE TABLE",
+		"   NOTICE: relation \"t\" already exists, skipping",
+		"2. INSERT 0 3",
+		"3. (failed)",
+		"   WARNING: low stock",
+		"     HINT: reorder soon",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("QueryMessageLines() = %q, want %q", got, want)
+	}
+	if got := QueryMessagesSummary(script); got != "2 statements · 2 notices" {
+		t.Errorf("QueryMessagesSummary() = %q", got)
+	}
+
+	single := models.QueryResult{
+		Columns: []string{"id"},
+		Notices: []models.QueryNotice{{Severity: "NOTICE", Message: "hello", Detail: "from a function"}},
+	}
+	got = QueryMessageLines(single)
+	if want := []string{"NOTICE: hello", "  DETAIL: from a function"}; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("QueryMessageLines() for one statement = %q, want %q", got, want)
+	}
+}
+
+func TestRenderQueryMessages(t *testing.T) {
+	result := models.QueryResult{}
+	for i := 0; i < 10; i++ {
+		result.Notices = append(result.Notices, models.QueryNotice{Severity: "NOTICE", Message: "step"})
+	}
+	th := theme.DefaultTheme()
+
+	collapsed := RenderQueryMessages(result, false, 80, 6, th)
+	if lipgloss.Height(collapsed) != 1 || !strings.Contains(collapsed, "10 notices") {
+		t.Errorf("collapsed messages = %q, want one summary line", collapsed)
+	}
+
+	expanded := RenderQueryMessages(result, true, 80, 6, th)
+	if h := lipgloss.Height(expanded); h != 6 {
+		t.Errorf("expanded messages are %d lines, want 6", h)
+	}
+	if !strings.Contains(expanded, "… 6 more") {
+		t.Errorf("expanded messages do not count the hidden ones: %q", expanded)
+	}
+}
+
+func TestResultTabs_MessagesShownWithoutRows(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+	rt.AddResult("SELECT 1", models.QueryResult{Columns: []string{"?column?"}, Rows: [][]string{{"1"}},
+		Notices: []models.QueryNotice{{Severity: "NOTICE", Message: "hi"}}})
+	if rt.GetActiveTab().ShowMessages {
+		t.Error("messages of a result with rows should start collapsed")
+	}
+	rt.AddResult("DO $$ ... $$", models.QueryResult{Notices: []models.QueryNotice{{Severity: "NOTICE", Message: "hi"}}})
+	if !rt.GetActiveTab().ShowMessages {
+		t.Error("messages of a result without rows should start expanded")
+	}
+}
+
+func TestRenderCommandResult(t *testing.T) {
+	set := models.QueryResult{CommandTag: "SET", Duration: 1500 * time.Microsecond}
+	if !IsCommandResult(set) {
+		t.Fatal("SET is not a command result")
+	}
+	view := RenderCommandResult(set, 60, 10, theme.DefaultTheme())
+	for _, want := range []string{"Command executed successfully", "SET", "Took 2ms"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view lacks %q:\n%s", want, view)
+		}
+	}
+
+	// A SELECT that found nothing still has a grid to show
+	if IsCommandResult(models.QueryResult{Columns: []string{"id"}, CommandTag: "SELECT 0"}) {
+		t.Error("a zero-row SELECT is a command result")
+	}
+	if IsCommandResult(models.QueryResult{Error: errors.New("boom")}) {
+		t.Error("a failed query is a command result")
+	}
+}
@@ -0,0 +1,102 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// RecoveryDecisionMsg is sent when the user chooses whether to restore the
+// SQL editor content saved by a session that didn't exit cleanly
+type RecoveryDecisionMsg struct {
+	Restore bool
+}
+
+// RecoveryPrompt offers to restore SQL editor content found at startup
+type RecoveryPrompt struct {
+	Width int
+	Theme theme.Theme
+
+	SQL     string
+	SavedAt time.Time
+}
+
+// NewRecoveryPrompt creates a new recovery prompt
+func NewRecoveryPrompt(th theme.Theme) *RecoveryPrompt {
+	return &RecoveryPrompt{
+		Width: 70,
+		Theme: th,
+	}
+}
+
+// Open shows the prompt for sql, saved at savedAt
+func (p *RecoveryPrompt) Open(sql string, savedAt time.Time) {
+	p.SQL = sql
+	p.SavedAt = savedAt
+}
+
+// Update handles keyboard input
+func (p *RecoveryPrompt) Update(msg tea.KeyMsg) (*RecoveryPrompt, tea.Cmd) {
+	switch msg.String() {
+	case "r", "y", "enter":
+		return p, func() tea.Msg { return RecoveryDecisionMsg{Restore: true} }
+	case "d", "n", "esc":
+		return p, func() tea.Msg { return RecoveryDecisionMsg{Restore: false} }
+	}
+	return p, nil
+}
+
+// Summary describes the recovered content
+func (p *RecoveryPrompt) Summary() string {
+	lines := strings.Count(strings.TrimRight(p.SQL, "\n"), "\n") + 1
+	summary := "1 line"
+	if lines != 1 {
+		summary = fmt.Sprintf("%d lines", lines)
+	}
+	if !p.SavedAt.IsZero() {
+		summary += " saved " + p.SavedAt.Format("Jan 2 15:04")
+	}
+	return summary
+}
+
+// View renders the prompt
+func (p *RecoveryPrompt) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(p.Theme.Background).
+		Background(p.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(p.Theme.Foreground)
+	sqlStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+	keyStyle := lipgloss.NewStyle().Foreground(p.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+
+	textWidth := p.Width - 4 // Border and padding
+	sqlLines := strings.Split(wrapText(strings.TrimSpace(p.SQL), textWidth), "\n")
+	if len(sqlLines) > 8 {
+		sqlLines = append(sqlLines[:7], "…")
+	}
+
+	sections := []string{
+		titleStyle.Render("Restore Unsaved SQL?"),
+		"",
+		textStyle.Render("lazypg didn't exit cleanly last time. The SQL editor held:"),
+		metaStyle.Render(p.Summary()),
+		"",
+		sqlStyle.Render(strings.Join(sqlLines, "\n")),
+		"",
+		keyStyle.Render("r") + metaStyle.Render("/") + keyStyle.Render("Enter") + metaStyle.Render(": Restore   ") +
+			keyStyle.Render("d") + metaStyle.Render("/") + keyStyle.Render("Esc") + metaStyle.Render(": Discard"),
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Theme.Info).
+		Width(p.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
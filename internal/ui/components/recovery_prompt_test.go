@@ -0,0 +1,44 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestRecoveryPrompt(t *testing.T) {
+	p := NewRecoveryPrompt(theme.DefaultTheme())
+	p.Open("SELECT *\nFROM orders\n", time.Date(2025, 6, 1, 9, 30, 0, 0, time.Local))
+
+	if got, want := p.Summary(), "2 lines saved Jun 1 09:30"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if view := p.View(); !strings.Contains(view, "FROM orders") {
+		t.Errorf("view lacks the recovered SQL:\n%s", view)
+	}
+
+	tests := []struct {
+		key  tea.KeyMsg
+		want RecoveryDecisionMsg
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}, RecoveryDecisionMsg{Restore: true}},
+		{tea.KeyMsg{Type: tea.KeyEnter}, RecoveryDecisionMsg{Restore: true}},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")}, RecoveryDecisionMsg{Restore: false}},
+		{tea.KeyMsg{Type: tea.KeyEsc}, RecoveryDecisionMsg{Restore: false}},
+	}
+	for _, tt := range tests {
+		_, cmd := p.Update(tt.key)
+		if cmd == nil {
+			t.Fatalf("%s returned no command", tt.key)
+		}
+		if got := cmd(); got != tt.want {
+			t.Errorf("%s sent %#v, want %#v", tt.key, got, tt.want)
+		}
+	}
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		t.Error("q made a decision")
+	}
+}
@@ -0,0 +1,268 @@
+package components
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/rebelice/lazypg/internal/models"
+)
+
+// CellTone colors a cell of a comparison to show how it differs from the
+// baseline result
+type CellTone int
+
+const (
+	CellToneNone     CellTone = iota
+	CellToneIncrease          // Number went up, or row only in the current result
+	CellToneDecrease          // Number went down, or row only in the baseline
+	CellToneChanged           // Non-numeric value changed
+)
+
+// Values of a comparison's status column
+const (
+	CompareStatusSame    = "="
+	CompareStatusChanged = "≠"
+	CompareStatusAdded   = "+ added"   // Only in the current result
+	CompareStatusRemoved = "− removed" // Only in the baseline
+)
+
+// CompareStatusColumn is the name of the comparison's first column
+const CompareStatusColumn = "Δ"
+
+// ResultComparison is a result compared cell by cell with a baseline. Rows
+// are aligned by a key column; Tones runs parallel to Rows.
+type ResultComparison struct {
+	Columns []string
+	Rows    [][]string
+	Tones   [][]CellTone
+
+	Changed, Added, Removed int
+}
+
+// CompareResults aligns the rows of current and baseline by the key column
+// and describes each cell of current against its baseline value. Numbers
+// show the difference, e.g. "120 (+20)"; other values show what they were.
+// Rows found in only one result are marked added or removed. Columns of
+// current missing from baseline are shown as-is; columns only in baseline
+// are left out. Rows sharing a key are matched in order.
+func CompareResults(current, baseline models.QueryResult, key string) (ResultComparison, error) {
+	curKey := columnIndex(current.Columns, key)
+	baseKey := columnIndex(baseline.Columns, key)
+	if curKey < 0 || baseKey < 0 {
+		return ResultComparison{}, fmt.Errorf("column %q is not in both results", key)
+	}
+
+	// baseCols[i] is the baseline column matching current column i, or -1
+	baseCols := make([]int, len(current.Columns))
+	for i, name := range current.Columns {
+		baseCols[i] = columnIndex(baseline.Columns, name)
+	}
+
+	// Index baseline rows by key, in order for keys that repeat
+	baseRows := make(map[string][]int)
+	for i, row := range baseline.Rows {
+		k := cellAt(row, baseKey)
+		baseRows[k] = append(baseRows[k], i)
+	}
+	matched := make([]bool, len(baseline.Rows))
+
+	cmp := ResultComparison{Columns: append([]string{CompareStatusColumn}, current.Columns...)}
+	for _, row := range current.Rows {
+		k := cellAt(row, curKey)
+		if len(baseRows[k]) == 0 {
+			cmp.addRow(CompareStatusAdded, CellToneIncrease, row, nil)
+			cmp.Added++
+			continue
+		}
+		baseIdx := baseRows[k][0]
+		baseRows[k] = baseRows[k][1:]
+		matched[baseIdx] = true
+
+		out := []string{CompareStatusSame}
+		tones := []CellTone{CellToneNone}
+		changed := false
+		for i := range current.Columns {
+			value := cellAt(row, i)
+			if baseCols[i] < 0 {
+				out = append(out, value)
+				tones = append(tones, CellToneNone)
+				continue
+			}
+			cell, tone := compareCell(value, cellAt(baseline.Rows[baseIdx], baseCols[i]))
+			if tone != CellToneNone {
+				changed = true
+			}
+			out = append(out, cell)
+			tones = append(tones, tone)
+		}
+		if changed {
+			out[0], tones[0] = CompareStatusChanged, CellToneChanged
+			cmp.Changed++
+		}
+		cmp.Rows = append(cmp.Rows, out)
+		cmp.Tones = append(cmp.Tones, tones)
+	}
+
+	for i, row := range baseline.Rows {
+		if matched[i] {
+			continue
+		}
+		cmp.addRow(CompareStatusRemoved, CellToneDecrease, row, baseCols)
+		cmp.Removed++
+	}
+	return cmp, nil
+}
+
+// addRow adds a row found in only one result, taking the values of
+// current's columns from row through cols (nil when row is from current)
+func (c *ResultComparison) addRow(status string, tone CellTone, row []string, cols []int) {
+	out := []string{status}
+	tones := []CellTone{tone}
+	for i := 1; i < len(c.Columns); i++ {
+		switch {
+		case cols == nil:
+			out = append(out, cellAt(row, i-1))
+		case cols[i-1] >= 0:
+			out = append(out, cellAt(row, cols[i-1]))
+		default:
+			out = append(out, "")
+		}
+		tones = append(tones, tone)
+	}
+	c.Rows = append(c.Rows, out)
+	c.Tones = append(c.Tones, tones)
+}
+
+// Summary counts the rows that differ
+func (c ResultComparison) Summary() string {
+	return fmt.Sprintf("%d changed, %d added, %d removed", c.Changed, c.Added, c.Removed)
+}
+
+// Result returns the comparison as a query result for a result tab
+func (c ResultComparison) Result() models.QueryResult {
+	return models.QueryResult{
+		Columns:      c.Columns,
+		Rows:         c.Rows,
+		RowsAffected: int64(len(c.Rows)),
+	}
+}
+
+// compareCell describes value against its baseline. Numbers are compared
+// exactly, as decimals, and keep the precision of the more precise side.
+func compareCell(value, base string) (string, CellTone) {
+	if value == base {
+		return value, CellToneNone
+	}
+	cur, curOK := parseDecimal(value)
+	old, oldOK := parseDecimal(base)
+	if !curOK || !oldOK {
+		return fmt.Sprintf("%s (was %s)", value, base), CellToneChanged
+	}
+	delta := new(big.Rat).Sub(cur, old)
+	switch delta.Sign() {
+	case 0:
+		// Same number written differently, e.g. 1.0 and 1.00
+		return value, CellToneNone
+	case 1:
+		return fmt.Sprintf("%s (+%s)", value, delta.FloatString(max(decimals(value), decimals(base)))), CellToneIncrease
+	default:
+		return fmt.Sprintf("%s (%s)", value, delta.FloatString(max(decimals(value), decimals(base)))), CellToneDecrease
+	}
+}
+
+// parseDecimal parses a number as Postgres prints it. big.Rat alone would
+// also take fractions like "1/2" and hex, which are text here.
+func parseDecimal(s string) (*big.Rat, bool) {
+	if s == "" || strings.Trim(s, "+-.0123456789eE") != "" {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// decimals counts the digits after a number's decimal point
+func decimals(s string) int {
+	i := strings.IndexByte(s, '.')
+	if i < 0 {
+		return 0
+	}
+	n := 0
+	for _, r := range s[i+1:] {
+		if r < '0' || r > '9' {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// columnIndex returns the index of the column named name, or -1
+func columnIndex(columns []string, name string) int {
+	for i, c := range columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// cellAt returns row's value at col, or "" for a short row
+func cellAt(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+// ComparisonTitle names the tab comparing current with baseline
+func ComparisonTitle(current, baseline *ResultTab) string {
+	return fmt.Sprintf("Δ %s vs %s", current.Title, baseline.Title)
+}
+
+// AddComparison opens cmp in a new result tab titled title
+func (rt *ResultTabs) AddComparison(title string, cmp ResultComparison) {
+	result := cmp.Result()
+	tableView := NewTableView(rt.Theme)
+	tableView.SetMasks(rt.Masks)
+	tableView.RowColors = rt.RowColors
+	tableView.BoolGlyphs = rt.BoolGlyphs
+	tableView.SetData(result.Columns, result.Rows, len(result.Rows))
+	tableView.Tones = cmp.Tones
+
+	rt.tabs = append([]*ResultTab{{
+		ID:        rt.nextID,
+		Title:     title,
+		Result:    result,
+		CreatedAt: time.Now(),
+		TableView: tableView,
+		Type:      TabTypeQueryResult,
+	}}, rt.tabs...)
+	rt.nextID++
+	rt.evictOldest()
+	rt.activeIdx = 0
+}
+
+// ComparisonBaseline returns the result tab the active one is compared
+// with: the other pane of a split view, otherwise the nearest older query
+// result, otherwise the nearest newer one. Nil if there is none.
+func (rt *ResultTabs) ComparisonBaseline() *ResultTab {
+	isResult := func(tab *ResultTab) bool {
+		return tab != nil && tab.Type == TabTypeQueryResult && !tab.IsPending && tab.TableView != nil
+	}
+	if split := rt.SplitTab(); isResult(split) {
+		return split
+	}
+	// Newer tabs are to the left, so look right first
+	for i := rt.activeIdx + 1; i < len(rt.tabs); i++ {
+		if isResult(rt.tabs[i]) {
+			return rt.tabs[i]
+		}
+	}
+	for i := rt.activeIdx - 1; i >= 0; i-- {
+		if isResult(rt.tabs[i]) {
+			return rt.tabs[i]
+		}
+	}
+	return nil
+}
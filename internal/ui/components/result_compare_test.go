@@ -0,0 +1,76 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestCompareResults(t *testing.T) {
+	baseline := models.QueryResult{
+		Columns: []string{"region", "orders", "revenue", "status"},
+		Rows: [][]string{
+			{"north", "10", "100.50", "open"},
+			{"south", "7", "80.00", "open"},
+			{"west", "3", "12.5", "open"},
+		},
+	}
+	current := models.QueryResult{
+		Columns: []string{"region", "orders", "revenue", "status", "note"},
+		Rows: [][]string{
+			{"north", "12", "99.25", "open", "a"},
+			{"south", "7", "80.0", "closed", "b"},
+			{"east", "1", "5", "open", "c"},
+		},
+	}
+
+	cmp, err := CompareResults(current, baseline, "region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{CompareStatusChanged, "north", "12 (+2)", "99.25 (-1.25)", "open", "a"},
+		// 80.0 and 80.00 are the same number
+		{CompareStatusChanged, "south", "7", "80.0", "closed (was open)", "b"},
+		{CompareStatusAdded, "east", "1", "5", "open", "c"},
+		// The note column isn't in the baseline
+		{CompareStatusRemoved, "west", "3", "12.5", "open", ""},
+	}
+	if !reflect.DeepEqual(cmp.Rows, want) {
+		t.Errorf("CompareResults() rows =\n%q\nwant\n%q", cmp.Rows, want)
+	}
+	if cmp.Changed != 2 || cmp.Added != 1 || cmp.Removed != 1 {
+		t.Errorf("Summary() = %s", cmp.Summary())
+	}
+
+	tones := cmp.Tones[0]
+	if tones[2] != CellToneIncrease || tones[3] != CellToneDecrease || tones[4] != CellToneNone {
+		t.Errorf("tones of a changed row = %v", tones)
+	}
+	if cmp.Tones[3][1] != CellToneDecrease || cmp.Tones[2][1] != CellToneIncrease {
+		t.Error("rows in only one result are not colored as removed/added")
+	}
+
+	if _, err := CompareResults(current, baseline, "note"); err == nil {
+		t.Error("CompareResults() by a column missing from the baseline succeeded")
+	}
+}
+
+func TestComparisonBaseline(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+	if rt.ComparisonBaseline() != nil {
+		t.Fatal("ComparisonBaseline() with no tabs is not nil")
+	}
+	result := models.QueryResult{Columns: []string{"n"}, Rows: [][]string{{"1"}}}
+	rt.AddResult("SELECT 1 AS n", result)
+	first := rt.GetActiveTab()
+	rt.AddCodeEditor("fn", "fn", NewCodeEditor(theme.DefaultTheme()))
+	rt.AddResult("SELECT 2 AS n", result)
+
+	// The code tab in between is skipped
+	if got := rt.ComparisonBaseline(); got != first {
+		t.Errorf("ComparisonBaseline() = %v, want the previous result", got)
+	}
+}
@@ -15,9 +15,24 @@ import (
 // Zone ID prefixes for mouse click handling
 const (
 	ZoneResultTabPrefix = "result-tab-"
+	ZoneSplitPane       = "split-pane" // Title of the split view pane without focus
 )
 
-const MaxResultTabs = 10
+// zoneMarker matches the markers zone.Mark wraps around a zone
+var zoneMarker = regexp.MustCompile(`\x1b\[\d+z`)
+
+// StripZones removes the click zones from rendered content, for content
+// shown where it can't be clicked
+func StripZones(s string) string {
+	return zoneMarker.ReplaceAllString(s, "")
+}
+
+// MinSplitPaneWidth is the narrowest pane of a side-by-side split view;
+// narrower data panels stack the two tabs instead
+const MinSplitPaneWidth = 40
+
+// DefaultMaxResultTabs is how many tabs stay open before the oldest closes
+const DefaultMaxResultTabs = 10
 
 // Pre-compiled regex patterns for performance
 var (
@@ -27,6 +42,10 @@ var (
 	updateRe       = regexp.MustCompile(`(?i)\bUPDATE\s+([a-zA-Z_][a-zA-Z0-9_.]*)`)
 	deleteRe       = regexp.MustCompile(`(?i)\bDELETE\s+FROM\s+([a-zA-Z_][a-zA-Z0-9_.]*)`)
 	insertRe       = regexp.MustCompile(`(?i)\bINSERT\s+INTO\s+([a-zA-Z_][a-zA-Z0-9_.]*)`)
+
+	leadingCommentRe = regexp.MustCompile(`(?s)^\s*(?:--[^\n]*(?:\n|$)|/\*.*?\*/)`)
+	explainRe        = regexp.MustCompile(`(?is)^\s*EXPLAIN\b\s*(\([^)]*\))?`)
+	explainFormatRe  = regexp.MustCompile(`(?i)\bFORMAT\s+(JSON|XML|YAML)\b`)
 )
 
 // TabType represents the type of content in a tab
@@ -49,13 +68,67 @@ type ResultTab struct {
 	IsPending   bool // true if query is still executing
 	IsCancelled bool // true if query was cancelled
 
+	// LIMIT appended to the query by the SQL editor (0 if none)
+	AppliedLimit int
+
+	// Connection the query ran on when it wasn't the active one, shown in
+	// the title; reruns and further pages use it too (empty for the active)
+	ConnectionID string
+
 	// Tab type and additional content
 	Type       TabType
 	CodeEditor *CodeEditor    // For code/DDL display tabs
 	Structure  *StructureView // For table data tabs
+	Filter     *models.Filter // Filter the table data tab was loaded with (nil if none)
+
+	// Whether the messages section of a query result is expanded
+	ShowMessages bool
 
 	// Identifier for deduplication (e.g., "schema.table" or "schema.function")
 	ObjectID string
+
+	// Auto refresh of a table data tab: its load runs again every
+	// RefreshInterval (0 is off). RefreshSeq tells the current tick chain
+	// from stale ones, and Refreshing is set while a refresh is in flight.
+	RefreshInterval time.Duration
+	RefreshSeq      int
+	Refreshing      bool
+
+	// Primary key columns of a table data tab, loaded on its first refresh
+	// to find the rows that changed (nil until loaded, empty if none)
+	PrimaryKey []string
+}
+
+// AutoRefreshIntervals are the intervals the auto refresh toggle cycles
+// through, after which it turns off
+var AutoRefreshIntervals = []time.Duration{
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+}
+
+// NextRefreshInterval returns the auto refresh interval after current, or 0
+// (off) after the last one
+func NextRefreshInterval(current time.Duration) time.Duration {
+	if current == 0 {
+		return AutoRefreshIntervals[0]
+	}
+	for i, d := range AutoRefreshIntervals {
+		if d == current && i+1 < len(AutoRefreshIntervals) {
+			return AutoRefreshIntervals[i+1]
+		}
+	}
+	return 0
+}
+
+// FormatRefreshInterval formats an auto refresh interval compactly: 5s, 1m
+func FormatRefreshInterval(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
 }
 
 // ResultTabs manages multiple query result tabs
@@ -65,9 +138,33 @@ type ResultTabs struct {
 	nextID    int
 	Theme     theme.Theme
 
-	// Pending execution state
-	pendingSQL       string
+	// Pending execution state; pendingID is the ID of the pending tab, 0
+	// when nothing is running
+	pendingID        int
 	pendingStartTime time.Time
+
+	// TitleTemplate names table and code tabs (see FormatTabTitle); empty
+	// keeps the built-in titles
+	TitleTemplate string
+
+	// MaxTabs is how many tabs stay open; opening another closes the oldest.
+	// 0 keeps every tab open.
+	MaxTabs int
+
+	// Masks are applied to the grid of each new result
+	Masks *MaskRules
+
+	// RowColors are applied to the grid of each new result
+	RowColors *RowColorRules
+
+	// BoolGlyphs are applied to the grid of each new result
+	BoolGlyphs *BoolGlyphs
+
+	// Split view: the data panel shows the active tab next to the tab with
+	// ID splitID (0 when not split). activeFirst is whether the active tab
+	// is in the first (left or top) pane.
+	splitID     int
+	activeFirst bool
 }
 
 // NewResultTabs creates a new result tabs manager
@@ -77,12 +174,21 @@ func NewResultTabs(th theme.Theme) *ResultTabs {
 		activeIdx: 0,
 		nextID:    1,
 		Theme:     th,
+		MaxTabs:   DefaultMaxResultTabs,
+	}
+}
+
+// evictOldest closes the oldest (rightmost) tabs past MaxTabs
+func (rt *ResultTabs) evictOldest() {
+	if rt.MaxTabs > 0 && len(rt.tabs) > rt.MaxTabs {
+		rt.tabs = rt.tabs[:rt.MaxTabs]
 	}
 }
 
-// StartPendingQuery creates a pending tab for an executing query
-func (rt *ResultTabs) StartPendingQuery(sql string) {
-	rt.pendingSQL = sql
+// StartPendingQuery creates a pending tab for an executing query and returns
+// its ID, which identifies the execution's result
+func (rt *ResultTabs) StartPendingQuery(sql string) int {
+	rt.pendingID = rt.nextID
 	rt.pendingStartTime = time.Now()
 
 	// Create pending tab
@@ -98,28 +204,68 @@ func (rt *ResultTabs) StartPendingQuery(sql string) {
 	// Insert pending tab at the beginning (leftmost position)
 	rt.tabs = append([]*ResultTab{tab}, rt.tabs...)
 
-	// Remove oldest (rightmost) if exceeding max
-	if len(rt.tabs) > MaxResultTabs {
-		rt.tabs = rt.tabs[:MaxResultTabs]
-	}
+	rt.evictOldest()
 
 	// Set pending tab as active
 	rt.activeIdx = 0
+	return tab.ID
+}
+
+// SetPendingLimit records the LIMIT that was appended to the pending query,
+// so the finished tab can show it
+func (rt *ResultTabs) SetPendingLimit(limit int) {
+	for _, tab := range rt.tabs {
+		if tab.IsPending {
+			tab.AppliedLimit = limit
+			return
+		}
+	}
+}
+
+// SetPendingConnection records that the pending query runs on the
+// connection with id rather than the active one
+func (rt *ResultTabs) SetPendingConnection(id string) {
+	for _, tab := range rt.tabs {
+		if tab.IsPending {
+			tab.ConnectionID = id
+			tab.Title = "Executing on " + id + "..."
+			return
+		}
+	}
+}
+
+// connectionSuffix labels the title of a tab run on a connection other
+// than the active one
+func connectionSuffix(tab *ResultTab) string {
+	if tab.ConnectionID == "" {
+		return ""
+	}
+	return " @ " + tab.ConnectionID
 }
 
 // CompletePendingQuery completes the pending query with results
 func (rt *ResultTabs) CompletePendingQuery(sql string, result models.QueryResult) {
 	// Find and update the pending tab
 	for i, tab := range rt.tabs {
-		if tab.IsPending && tab.SQL == sql {
+		if tab.IsPending && tab.ID == rt.pendingID {
 			// Create TableView for results
 			tableView := NewTableView(rt.Theme)
+			tableView.SetMasks(rt.Masks)
+			tableView.RowColors = rt.RowColors
+			tableView.BoolGlyphs = rt.BoolGlyphs
 			tableView.SetData(result.Columns, result.Rows, len(result.Rows))
+			tableView.SetColumnKinds(result.ColumnKinds)
+			tableView.MoreRows = tab.AppliedLimit > 0 && len(result.Rows) >= tab.AppliedLimit
 
 			tab.Title = rt.generateTitle(sql, result)
+			if tab.AppliedLimit > 0 {
+				tab.Title += fmt.Sprintf(" [limit %d]", tab.AppliedLimit)
+			}
+			tab.Title += connectionSuffix(tab)
 			tab.Result = result
 			tab.TableView = tableView
 			tab.IsPending = false
+			tab.ShowMessages = showMessagesByDefault(result)
 
 			// Make sure this tab is active
 			rt.activeIdx = i
@@ -128,7 +274,75 @@ func (rt *ResultTabs) CompletePendingQuery(sql string, result models.QueryResult
 	}
 
 	// Clear pending state
-	rt.pendingSQL = ""
+	rt.pendingID = 0
+}
+
+// CompletePendingPlan completes the pending query by showing its EXPLAIN
+// output in a read-only code editor instead of a grid
+func (rt *ResultTabs) CompletePendingPlan(sql string, result models.QueryResult, codeEditor *CodeEditor) {
+	for i, tab := range rt.tabs {
+		if tab.IsPending && tab.ID == rt.pendingID {
+			tab.Title = "Plan: " + rt.generateTitle(sql, result) + connectionSuffix(tab)
+			tab.Result = result
+			tab.Type = TabTypeCodeEditor
+			tab.CodeEditor = codeEditor
+			tab.IsPending = false
+			rt.activeIdx = i
+			break
+		}
+	}
+
+	rt.pendingID = 0
+}
+
+// IsTextExplain reports whether a query result is text-format EXPLAIN
+// output, which is a single "QUERY PLAN" column better shown verbatim
+func IsTextExplain(sql string, result models.QueryResult) bool {
+	return explainFormat(sql, result) == "TEXT"
+}
+
+// IsJSONExplain reports whether a query result is EXPLAIN (FORMAT JSON)
+// output, whose one cell holds the plan as the server wrote it
+func IsJSONExplain(sql string, result models.QueryResult) bool {
+	return explainFormat(sql, result) == "JSON"
+}
+
+// explainFormat returns the FORMAT of an EXPLAIN result in upper case, or ""
+// if the result isn't EXPLAIN output
+func explainFormat(sql string, result models.QueryResult) string {
+	if len(result.Columns) != 1 || result.Columns[0] != "QUERY PLAN" {
+		return ""
+	}
+
+	// Skip leading comments (e.g. a "-- title" line)
+	for {
+		loc := leadingCommentRe.FindStringIndex(sql)
+		if loc == nil {
+			break
+		}
+		sql = sql[loc[1]:]
+	}
+
+	matches := explainRe.FindStringSubmatch(sql)
+	if matches == nil {
+		return ""
+	}
+	if format := explainFormatRe.FindStringSubmatch(matches[1]); format != nil {
+		return strings.ToUpper(format[1])
+	}
+	return "TEXT"
+}
+
+// PlanText joins the rows of a text EXPLAIN result into one document,
+// preserving the plan's indentation
+func PlanText(result models.QueryResult) string {
+	lines := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			lines = append(lines, row[0])
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 // CancelPendingQuery marks the pending tab as cancelled
@@ -144,17 +358,30 @@ func (rt *ResultTabs) CancelPendingQuery() {
 	}
 
 	// Clear pending state
-	rt.pendingSQL = ""
+	rt.pendingID = 0
 }
 
 // HasPendingQuery returns true if there's a pending query
 func (rt *ResultTabs) HasPendingQuery() bool {
-	return rt.pendingSQL != ""
+	return rt.pendingID != 0
+}
+
+// PendingID returns the ID of the pending query's tab, or 0 if no query is
+// pending
+func (rt *ResultTabs) PendingID() int {
+	return rt.pendingID
+}
+
+// IsPendingQuery reports whether id is the execution currently pending. A
+// result for anything else belongs to a query that was already cancelled,
+// even if it ran the same SQL.
+func (rt *ResultTabs) IsPendingQuery(id int) bool {
+	return rt.pendingID != 0 && rt.pendingID == id
 }
 
 // GetPendingElapsed returns the elapsed time for the pending query
 func (rt *ResultTabs) GetPendingElapsed() time.Duration {
-	if rt.pendingSQL == "" {
+	if rt.pendingID == 0 {
 		return 0
 	}
 	return time.Since(rt.pendingStartTime)
@@ -164,7 +391,11 @@ func (rt *ResultTabs) GetPendingElapsed() time.Duration {
 func (rt *ResultTabs) AddResult(sql string, result models.QueryResult) {
 	// Create TableView for this result
 	tableView := NewTableView(rt.Theme)
+	tableView.SetMasks(rt.Masks)
+	tableView.RowColors = rt.RowColors
+	tableView.BoolGlyphs = rt.BoolGlyphs
 	tableView.SetData(result.Columns, result.Rows, len(result.Rows))
+	tableView.SetColumnKinds(result.ColumnKinds)
 
 	tab := &ResultTab{
 		ID:        rt.nextID,
@@ -174,21 +405,26 @@ func (rt *ResultTabs) AddResult(sql string, result models.QueryResult) {
 		CreatedAt: time.Now(),
 		TableView: tableView,
 		Type:      TabTypeQueryResult,
+
+		ShowMessages: showMessagesByDefault(result),
 	}
 	rt.nextID++
 
 	// Insert new tab at the beginning (leftmost position)
 	rt.tabs = append([]*ResultTab{tab}, rt.tabs...)
 
-	// Remove oldest (rightmost) if exceeding max
-	if len(rt.tabs) > MaxResultTabs {
-		rt.tabs = rt.tabs[:MaxResultTabs]
-	}
+	rt.evictOldest()
 
 	// Set new tab as active (index 0 = leftmost)
 	rt.activeIdx = 0
 }
 
+// ObjectTitle returns the title for a table or code tab, applying
+// TitleTemplate when it can be filled in and fallback otherwise
+func (rt *ResultTabs) ObjectTitle(fields TabTitleFields, fallback string) string {
+	return FormatTabTitle(rt.TitleTemplate, fields, fallback)
+}
+
 // AddTableData adds a table/view data tab (from tree selection)
 // If a tab for the same objectID exists, it becomes active instead of creating a new tab
 func (rt *ResultTabs) AddTableData(objectID, title string, structure *StructureView) {
@@ -214,10 +450,7 @@ func (rt *ResultTabs) AddTableData(objectID, title string, structure *StructureV
 	// Insert new tab at the beginning (leftmost position)
 	rt.tabs = append([]*ResultTab{tab}, rt.tabs...)
 
-	// Remove oldest (rightmost) if exceeding max
-	if len(rt.tabs) > MaxResultTabs {
-		rt.tabs = rt.tabs[:MaxResultTabs]
-	}
+	rt.evictOldest()
 
 	// Set new tab as active (index 0 = leftmost)
 	rt.activeIdx = 0
@@ -248,10 +481,7 @@ func (rt *ResultTabs) AddCodeEditor(objectID, title string, codeEditor *CodeEdit
 	// Insert new tab at the beginning (leftmost position)
 	rt.tabs = append([]*ResultTab{tab}, rt.tabs...)
 
-	// Remove oldest (rightmost) if exceeding max
-	if len(rt.tabs) > MaxResultTabs {
-		rt.tabs = rt.tabs[:MaxResultTabs]
-	}
+	rt.evictOldest()
 
 	// Set new tab as active (index 0 = leftmost)
 	rt.activeIdx = 0
@@ -270,6 +500,63 @@ func (rt *ResultTabs) CloseActiveTab() {
 	if rt.activeIdx >= len(rt.tabs) && len(rt.tabs) > 0 {
 		rt.activeIdx = len(rt.tabs) - 1
 	}
+
+	// The other pane's tab fills the panel if it became active
+	if tab := rt.GetActiveTab(); tab != nil && tab.ID == rt.splitID {
+		rt.splitID = 0
+	}
+}
+
+// CloseObjectTabs closes the table data and code tabs, which were loaded
+// from a connection's objects, keeping query results. It returns how many
+// tabs were closed.
+func (rt *ResultTabs) CloseObjectTabs() int {
+	return rt.closeTabs(func(tab *ResultTab) bool {
+		return tab.Type != TabTypeQueryResult
+	})
+}
+
+// CloseConnectionTabs closes the query tabs run on the connection with id
+// from the command palette, e.g. when its session is lost; there is nothing
+// left to rerun them on. It returns how many tabs were closed.
+func (rt *ResultTabs) CloseConnectionTabs(id string) int {
+	return rt.closeTabs(func(tab *ResultTab) bool {
+		return tab.Type == TabTypeQueryResult && tab.ConnectionID == id
+	})
+}
+
+// closeTabs closes the tabs matching closing, keeping the active tab active
+// if it stays open and ending a split to a closed tab. It returns how many
+// tabs were closed.
+func (rt *ResultTabs) closeTabs(closing func(*ResultTab) bool) int {
+	var active *ResultTab
+	if rt.activeIdx < len(rt.tabs) {
+		active = rt.tabs[rt.activeIdx]
+	}
+
+	kept := rt.tabs[:0]
+	for _, tab := range rt.tabs {
+		switch {
+		case !closing(tab):
+			kept = append(kept, tab)
+		case tab.IsPending && tab.ID == rt.pendingID:
+			// Its result has nowhere to go
+			rt.pendingID = 0
+		}
+	}
+	closed := len(rt.tabs) - len(kept)
+	rt.tabs = kept
+
+	rt.activeIdx = 0
+	for i, tab := range rt.tabs {
+		if tab == active {
+			rt.activeIdx = i
+		}
+	}
+	if rt.GetTabByID(rt.splitID) == nil || rt.GetActiveTab() == rt.GetTabByID(rt.splitID) {
+		rt.splitID = 0
+	}
+	return closed
 }
 
 // GetActiveStructureView returns the StructureView of the active tab (if it's a table data tab)
@@ -394,15 +681,87 @@ func (rt *ResultTabs) GetActiveSQL() string {
 // NextTab switches to the next tab
 func (rt *ResultTabs) NextTab() {
 	if len(rt.tabs) > 0 {
-		rt.activeIdx = (rt.activeIdx + 1) % len(rt.tabs)
+		rt.setActive((rt.activeIdx + 1) % len(rt.tabs))
 	}
 }
 
 // PrevTab switches to the previous tab
 func (rt *ResultTabs) PrevTab() {
 	if len(rt.tabs) > 0 {
-		rt.activeIdx = (rt.activeIdx - 1 + len(rt.tabs)) % len(rt.tabs)
+		rt.setActive((rt.activeIdx - 1 + len(rt.tabs)) % len(rt.tabs))
+	}
+}
+
+// setActive makes the tab at index active. In split view, switching to the
+// tab in the other pane moves focus to that pane, so neither pane's tab
+// changes.
+func (rt *ResultTabs) setActive(index int) {
+	if split := rt.SplitTab(); split != nil && rt.tabs[index] == split {
+		rt.SwitchSplitFocus()
+		return
+	}
+	rt.activeIdx = index
+}
+
+// ToggleSplit splits the data panel to show the tab after the active one
+// next to it, or goes back to showing only the active tab. It reports
+// whether the panel is now split, which needs two tabs.
+func (rt *ResultTabs) ToggleSplit() bool {
+	if rt.IsSplit() {
+		rt.splitID = 0
+		return false
+	}
+	if len(rt.tabs) < 2 {
+		return false
 	}
+	rt.splitID = rt.tabs[(rt.activeIdx+1)%len(rt.tabs)].ID
+	rt.activeFirst = true
+	return true
+}
+
+// IsSplit returns whether the data panel shows two tabs
+func (rt *ResultTabs) IsSplit() bool {
+	return rt.SplitTab() != nil
+}
+
+// SplitTab returns the tab in the pane without focus, or nil when the
+// panel isn't split
+func (rt *ResultTabs) SplitTab() *ResultTab {
+	if rt.splitID == 0 {
+		return nil
+	}
+	tab := rt.GetTabByID(rt.splitID)
+	if tab == nil || tab == rt.GetActiveTab() {
+		return nil
+	}
+	return tab
+}
+
+// SwitchSplitFocus makes the tab in the other pane active. It reports
+// false when the panel isn't split.
+func (rt *ResultTabs) SwitchSplitFocus() bool {
+	split := rt.SplitTab()
+	if split == nil {
+		return false
+	}
+	rt.splitID = rt.tabs[rt.activeIdx].ID
+	for i, tab := range rt.tabs {
+		if tab == split {
+			rt.activeIdx = i
+		}
+	}
+	rt.activeFirst = !rt.activeFirst
+	return true
+}
+
+// SplitPanes returns the tabs of the first (left or top) and second panes
+// of the split view, and whether the first one has focus
+func (rt *ResultTabs) SplitPanes() (first, second *ResultTab, firstFocused bool) {
+	active, split := rt.GetActiveTab(), rt.SplitTab()
+	if rt.activeFirst {
+		return active, split, true
+	}
+	return split, active, false
 }
 
 // TabCount returns the number of tabs
@@ -415,6 +774,16 @@ func (rt *ResultTabs) GetAllTabs() []*ResultTab {
 	return rt.tabs
 }
 
+// GetTabByID returns a tab by its ID, or nil once it has been closed
+func (rt *ResultTabs) GetTabByID(id int) *ResultTab {
+	for _, tab := range rt.tabs {
+		if tab.ID == id {
+			return tab
+		}
+	}
+	return nil
+}
+
 // GetTabByObjectID returns a tab by its object ID
 func (rt *ResultTabs) GetTabByObjectID(objectID string) *ResultTab {
 	for _, tab := range rt.tabs {
@@ -435,7 +804,7 @@ func (rt *ResultTabs) SetActiveTab(index int) {
 	if index < 0 || index >= len(rt.tabs) {
 		return
 	}
-	rt.activeIdx = index
+	rt.setActive(index)
 }
 
 // RenderTabBar renders the tab bar
@@ -445,52 +814,27 @@ func (rt *ResultTabs) RenderTabBar(width int) string {
 	}
 
 	var tabViews []string
+	split := rt.SplitTab()
 
 	for i, tab := range rt.tabs {
-		// Generate label based on tab type
-		var label string
-		switch tab.Type {
-		case TabTypeQueryResult:
-			// Format: [index] title (rows)
-			rowCount := len(tab.Result.Rows)
-			rowStr := fmt.Sprintf("%d rows", rowCount)
-			if rowCount == 1 {
-				rowStr = "1 row"
-			}
-			label = fmt.Sprintf("[%d] %s (%s)", i+1, tab.Title, rowStr)
-		case TabTypeTableData:
-			// Format: [index] ▦ title
-			label = fmt.Sprintf("[%d] ▦ %s", i+1, tab.Title)
-		case TabTypeCodeEditor:
-			// Format: [index] ƒ title
-			label = fmt.Sprintf("[%d] ƒ %s", i+1, tab.Title)
-		default:
-			label = fmt.Sprintf("[%d] %s", i+1, tab.Title)
-		}
-
-		// Truncate if too long
-		maxLabelLen := width / MaxResultTabs
-		if maxLabelLen < 15 {
-			maxLabelLen = 15
-		}
-		if len(label) > maxLabelLen {
-			// Try without row count for query results
-			if tab.Type == TabTypeQueryResult {
-				label = fmt.Sprintf("[%d] %s", i+1, tab.Title)
-			}
-			if len(label) > maxLabelLen {
-				label = label[:maxLabelLen-3] + "..."
-			}
-		}
+		label := rt.tabLabel(i, tab, width)
 
 		var style lipgloss.Style
-		if i == rt.activeIdx {
+		switch {
+		case i == rt.activeIdx:
 			style = lipgloss.NewStyle().
 				Foreground(rt.Theme.Background).
 				Background(rt.Theme.Info).
 				Bold(true).
 				Padding(0, 1)
-		} else {
+		case tab == split:
+			// Shown in the other pane of the split view
+			style = lipgloss.NewStyle().
+				Foreground(rt.Theme.Info).
+				Background(rt.Theme.Selection).
+				Bold(true).
+				Padding(0, 1)
+		default:
 			style = lipgloss.NewStyle().
 				Foreground(rt.Theme.Foreground).
 				Background(rt.Theme.Selection).
@@ -502,5 +846,140 @@ func (rt *ResultTabs) RenderTabBar(width int) string {
 		tabViews = append(tabViews, zone.Mark(zoneID, style.Render(label)))
 	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, tabViews...)
+	widths := make([]int, len(tabViews))
+	for i, v := range tabViews {
+		widths[i] = lipgloss.Width(v)
+	}
+	start, end := TabBarWindow(widths, rt.activeIdx, width)
+	if start == 0 && end == len(tabViews) {
+		return lipgloss.JoinHorizontal(lipgloss.Top, tabViews...)
+	}
+
+	// Not all tabs fit: show the ones around the active tab, with how many
+	// are hidden on each side
+	overflowStyle := lipgloss.NewStyle().Foreground(rt.Theme.Metadata).Padding(0, 1)
+	var parts []string
+	if start > 0 {
+		parts = append(parts, overflowStyle.Render(fmt.Sprintf("‹ %d", start)))
+	}
+	parts = append(parts, tabViews[start:end]...)
+	if end < len(tabViews) {
+		parts = append(parts, overflowStyle.Render(fmt.Sprintf("%d ›", len(tabViews)-end)))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+}
+
+// RenderPaneTitle renders the title line of a split view pane showing
+// tab, highlighted when the pane has focus. The pane without focus can be
+// clicked to focus it.
+func (rt *ResultTabs) RenderPaneTitle(tab *ResultTab, focused bool, width int) string {
+	index := 0
+	for i, t := range rt.tabs {
+		if t == tab {
+			index = i
+		}
+	}
+	label := truncateToWidth(rt.tabLabel(index, tab, (width-2)*DefaultMaxResultTabs), max(width-2, 1))
+
+	style := lipgloss.NewStyle().Width(width).Padding(0, 1)
+	if focused {
+		return style.
+			Foreground(rt.Theme.Background).
+			Background(rt.Theme.Info).
+			Bold(true).
+			Render(label)
+	}
+	return zone.Mark(ZoneSplitPane, style.
+		Foreground(rt.Theme.Metadata).
+		Background(rt.Theme.Selection).
+		Render(label))
+}
+
+// tabLabel returns the tab bar label of the tab at index i, shortened to
+// fit a bar width cells wide
+func (rt *ResultTabs) tabLabel(i int, tab *ResultTab, width int) string {
+	// Generate label based on tab type
+	var label string
+	switch tab.Type {
+	case TabTypeQueryResult:
+		// Format: [index] title (rows)
+		rowCount := len(tab.Result.Rows)
+		rowStr := fmt.Sprintf("%d rows", rowCount)
+		if rowCount == 1 {
+			rowStr = "1 row"
+		}
+		if tab.TableView != nil && tab.TableView.MoreRows {
+			rowStr = fmt.Sprintf("%d+ rows", rowCount)
+		}
+		label = fmt.Sprintf("[%d] %s (%s)", i+1, tab.Title, rowStr)
+	case TabTypeTableData:
+		// Format: [index] ▦ title, with ▽ when the rows are filtered
+		// and ↻ with the interval when auto refresh is on
+		label = fmt.Sprintf("[%d] ▦ %s", i+1, tab.Title)
+		if tab.Filter.ConditionCount() > 0 {
+			label += " ▽"
+		}
+		if tab.RefreshInterval > 0 {
+			label += " ↻" + FormatRefreshInterval(tab.RefreshInterval)
+		}
+	case TabTypeCodeEditor:
+		// Format: [index] ƒ title
+		label = fmt.Sprintf("[%d] ƒ %s", i+1, tab.Title)
+	default:
+		label = fmt.Sprintf("[%d] %s", i+1, tab.Title)
+	}
+
+	// Truncate if too long
+	maxLabelLen := width / DefaultMaxResultTabs
+	if maxLabelLen < 15 {
+		maxLabelLen = 15
+	}
+	if lipgloss.Width(label) > maxLabelLen {
+		// Try without row count for query results
+		if tab.Type == TabTypeQueryResult {
+			label = fmt.Sprintf("[%d] %s", i+1, tab.Title)
+		}
+		// Cut by display width so multi-byte names stay intact
+		label = truncateToWidth(label, maxLabelLen)
+	}
+
+	return label
+}
+
+// TabBarWindow returns the range [start, end) of tabs, by rendered width,
+// to show in a bar width cells wide. It always holds active and grows to
+// the right first, then to the left. When tabs are left out, room is kept
+// for the overflow markers on either side.
+func TabBarWindow(widths []int, active, width int) (start, end int) {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	if total <= width || len(widths) == 0 {
+		return 0, len(widths)
+	}
+
+	// Room for "‹ N" and "N ›" with their padding
+	marker := len(fmt.Sprint(len(widths))) + 4
+	avail := width - 2*marker
+
+	active = max(0, min(active, len(widths)-1))
+	start, end = active, active+1
+	used := widths[active]
+	for {
+		grew := false
+		if end < len(widths) && used+widths[end] <= avail {
+			used += widths[end]
+			end++
+			grew = true
+		}
+		if start > 0 && used+widths[start-1] <= avail {
+			start--
+			used += widths[start]
+			grew = true
+		}
+		if !grew {
+			return start, end
+		}
+	}
 }
@@ -0,0 +1,424 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestIsTextExplain(t *testing.T) {
+	plan := models.QueryResult{
+		Columns: []string{"QUERY PLAN"},
+		Rows:    [][]string{{"Seq Scan on users  (cost=0.00..1.01 rows=1 width=4)"}},
+	}
+
+	tests := []struct {
+		sql    string
+		result models.QueryResult
+		want   bool
+	}{
+		{"EXPLAIN SELECT * FROM users", plan, true},
+		{"explain analyze select 1", plan, true},
+		{"-- plan for users\nEXPLAIN (COSTS true) SELECT * FROM users", plan, true},
+		{"/* why slow */ EXPLAIN (FORMAT TEXT) SELECT 1", plan, true},
+		{"EXPLAIN (FORMAT JSON) SELECT 1", plan, false},
+		{"SELECT 'x' AS \"QUERY PLAN\"", plan, false},
+		{"EXPLAIN SELECT 1", models.QueryResult{Columns: []string{"a", "b"}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsTextExplain(tt.sql, tt.result); got != tt.want {
+			t.Errorf("IsTextExplain(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+
+	if !IsJSONExplain("explain (analyze, format json) SELECT 1", plan) {
+		t.Error("IsJSONExplain() = false for FORMAT JSON")
+	}
+	if IsJSONExplain("EXPLAIN (FORMAT YAML) SELECT 1", plan) || IsJSONExplain("EXPLAIN SELECT 1", plan) {
+		t.Error("IsJSONExplain() = true for a YAML or text plan")
+	}
+}
+
+func TestPlanTextPreservesIndentation(t *testing.T) {
+	result := models.QueryResult{
+		Columns: []string{"QUERY PLAN"},
+		Rows: [][]string{
+			{"Hash Join  (cost=1.02..2.05 rows=1 width=8)"},
+			{"  Hash Cond: (a.id = b.id)"},
+			{"  ->  Seq Scan on a  (cost=0.00..1.01 rows=1 width=4)"},
+		},
+	}
+
+	want := "Hash Join  (cost=1.02..2.05 rows=1 width=8)\n  Hash Cond: (a.id = b.id)\n  ->  Seq Scan on a  (cost=0.00..1.01 rows=1 width=4)"
+	if got := PlanText(result); got != want {
+		t.Errorf("PlanText() = %q, want %q", got, want)
+	}
+}
+
+func TestResultTabs_PendingGuardClears(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+
+	id := rt.StartPendingQuery("SELECT 1")
+	if !rt.HasPendingQuery() || !rt.IsPendingQuery(id) {
+		t.Fatal("query should be pending")
+	}
+	rt.CompletePendingQuery("SELECT 1", models.QueryResult{Columns: []string{"?column?"}, Rows: [][]string{{"1"}}})
+	if rt.HasPendingQuery() {
+		t.Error("completing the query should clear the guard")
+	}
+
+	id = rt.StartPendingQuery("SELECT pg_sleep(10)")
+	rt.CancelPendingQuery()
+	if rt.HasPendingQuery() {
+		t.Error("cancelling the query should clear the guard")
+	}
+	// A late result from the cancelled query is not the pending one
+	if rt.IsPendingQuery(id) {
+		t.Error("a cancelled query should not be pending")
+	}
+}
+
+func TestResultTabs_RerunIgnoresCancelledResult(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+
+	cancelled := rt.StartPendingQuery("SELECT now()")
+	rt.CancelPendingQuery()
+	rerun := rt.StartPendingQuery("SELECT now()")
+
+	// The cancelled run's late result has the same SQL but not the same ID
+	if rt.IsPendingQuery(cancelled) {
+		t.Error("the cancelled run's result should not match the rerun")
+	}
+	if !rt.IsPendingQuery(rerun) {
+		t.Fatal("the rerun should be pending")
+	}
+
+	rt.CompletePendingQuery("SELECT now()", models.QueryResult{Columns: []string{"now"}, Rows: [][]string{{"later"}}})
+	tab := rt.GetActiveTab()
+	if tab.ID != rerun || tab.IsPending {
+		t.Errorf("active tab = %d (pending %v), want the rerun %d completed", tab.ID, tab.IsPending, rerun)
+	}
+	for _, other := range rt.tabs {
+		if other.ID == cancelled && !other.IsCancelled {
+			t.Error("the cancelled tab should stay cancelled")
+		}
+	}
+}
+
+func TestResultTabs_SwitchKeepsTablePosition(t *testing.T) {
+	th := theme.DefaultTheme()
+	rt := NewResultTabs(th)
+
+	newTable := func(n int) *TableView {
+		tv := NewTableView(th)
+		rows := make([][]string, n)
+		for i := range rows {
+			rows[i] = []string{fmt.Sprint(i + 1), fmt.Sprintf("row %d", i+1)}
+		}
+		tv.SetData([]string{"id", "name"}, rows, n)
+		tv.Width, tv.Height = 80, 20
+		tv.View() // Sizes VisibleRows
+		return tv
+	}
+
+	usersView, ordersView := newTable(200), newTable(200)
+	rt.AddTableData("public.users", "users", NewStructureView(th, usersView))
+	rt.AddTableData("public.orders", "orders", NewStructureView(th, ordersView))
+	filter := &models.Filter{Schema: "public", TableName: "orders"}
+	rt.GetTabByObjectID("public.orders").Filter = filter
+
+	// Scroll, select and pin in orders (the active tab)
+	ordersView.SetSelectedRow(120)
+	ordersView.SelectedCol = 1
+	if err := ordersView.TogglePin(); err != nil {
+		t.Fatalf("TogglePin() error = %v", err)
+	}
+	ordersView.View() // The pinned row takes a line from the scroll area
+	ordersTop := ordersView.TopRow
+
+	// Move users somewhere else too
+	rt.NextTab()
+	if rt.GetActiveTableView() != usersView {
+		t.Fatal("NextTab() did not switch to users")
+	}
+	usersView.SetSelectedRow(42)
+	usersTop := usersView.TopRow
+
+	// Switch back by every route: prev, next, click, and reselecting in the tree
+	for _, switchToOrders := range []func(){
+		rt.PrevTab,
+		rt.NextTab,
+		func() { rt.SetActiveTab(0) },
+		func() { rt.AddTableData("public.orders", "orders", nil) },
+	} {
+		rt.SetActiveTab(1)
+		switchToOrders()
+
+		tv := rt.GetActiveTableView()
+		if tv != ordersView {
+			t.Fatal("did not switch back to orders")
+		}
+		tv.View()
+		if tv.SelectedRow != 120 || tv.TopRow != ordersTop || tv.SelectedCol != 1 {
+			t.Errorf("orders position = row %d top %d col %d, want row 120 top %d col 1",
+				tv.SelectedRow, tv.TopRow, tv.SelectedCol, ordersTop)
+		}
+		if !tv.IsPinned(120) {
+			t.Error("orders lost its pinned row")
+		}
+		if got := rt.GetActiveTab().Filter; got != filter {
+			t.Errorf("orders filter = %v, want %v", got, filter)
+		}
+	}
+
+	if usersView.SelectedRow != 42 || usersView.TopRow != usersTop || usersView.GetPinnedCount() != 0 {
+		t.Errorf("users position = row %d top %d pins %d, want row 42 top %d pins 0",
+			usersView.SelectedRow, usersView.TopRow, usersView.GetPinnedCount(), usersTop)
+	}
+}
+
+func TestTableView_PositionAfterBackgroundResize(t *testing.T) {
+	th := theme.DefaultTheme()
+	tv := NewTableView(th)
+	rows := make([][]string, 100)
+	for i := range rows {
+		rows[i] = []string{fmt.Sprint(i + 1)}
+	}
+	tv.SetData([]string{"id"}, rows, len(rows))
+	tv.Width, tv.Height = 80, 40
+	tv.View()
+	tv.SetSelectedRow(60)
+
+	// The terminal shrank while the tab was in the background
+	tv.Height = 15
+	tv.View()
+	if tv.SelectedRow != 60 {
+		t.Errorf("SelectedRow = %d, want 60", tv.SelectedRow)
+	}
+	if tv.SelectedRow < tv.TopRow || tv.SelectedRow >= tv.TopRow+tv.VisibleRows {
+		t.Errorf("selected row 60 is outside the visible rows %d-%d", tv.TopRow, tv.TopRow+tv.VisibleRows-1)
+	}
+
+	// Reloading with fewer rows keeps the selection on a real row
+	tv.SetData([]string{"id"}, rows[:10], 10)
+	if tv.SelectedRow != 9 || tv.TopRow > tv.SelectedRow {
+		t.Errorf("after reload SelectedRow = %d TopRow = %d, want 9 and at most 9", tv.SelectedRow, tv.TopRow)
+	}
+}
+
+func TestResultTabs_FilteredTabTitle(t *testing.T) {
+	th := theme.DefaultTheme()
+	rt := NewResultTabs(th)
+	rt.AddTableData("public.orders", "orders", NewStructureView(th, NewTableView(th)))
+
+	if strings.Contains(rt.RenderTabBar(200), "▽") {
+		t.Error("unfiltered tab is marked as filtered")
+	}
+
+	tab := rt.GetActiveTab()
+	tab.Filter = &models.Filter{RootGroup: models.FilterGroup{
+		Conditions: []models.FilterCondition{{Column: "status", Operator: models.OpEqual, Value: "open"}},
+	}}
+	if !strings.Contains(rt.RenderTabBar(200), "orders ▽") {
+		t.Errorf("filtered tab title = %q, want the ▽ marker", rt.RenderTabBar(200))
+	}
+
+	// Clearing the filter drops the marker
+	tab.Filter = nil
+	if strings.Contains(rt.RenderTabBar(200), "▽") {
+		t.Error("cleared tab is still marked as filtered")
+	}
+}
+
+func TestNextRefreshInterval(t *testing.T) {
+	var got []string
+	d := time.Duration(0)
+	for i := 0; i <= len(AutoRefreshIntervals); i++ {
+		d = NextRefreshInterval(d)
+		if d == 0 {
+			got = append(got, "off")
+		} else {
+			got = append(got, FormatRefreshInterval(d))
+		}
+	}
+	if want := "2s 5s 10s 30s 1m off"; strings.Join(got, " ") != want {
+		t.Errorf("refresh interval cycle = %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestResultTabs_GetTabByIDAfterClose(t *testing.T) {
+	th := theme.DefaultTheme()
+	rt := NewResultTabs(th)
+	rt.AddTableData("public.orders", "orders", NewStructureView(th, NewTableView(th)))
+	tab := rt.GetActiveTab()
+	tab.RefreshInterval = 5 * time.Second
+
+	if rt.GetTabByID(tab.ID) != tab {
+		t.Fatal("GetTabByID() did not find the open tab")
+	}
+	if !strings.Contains(rt.RenderTabBar(200), "orders ↻5s") {
+		t.Errorf("tab bar = %q, want the auto refresh marker", rt.RenderTabBar(200))
+	}
+
+	// A closed tab's refresh ticks find nothing and stop
+	rt.CloseActiveTab()
+	if rt.GetTabByID(tab.ID) != nil {
+		t.Error("GetTabByID() found a closed tab")
+	}
+}
+
+func TestResultTabs_MaxTabs(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+	rt.MaxTabs = 3
+	for i := 0; i < 5; i++ {
+		rt.AddResult("SELECT 1", models.QueryResult{})
+	}
+	if got := rt.TabCount(); got != 3 {
+		t.Errorf("TabCount() with MaxTabs 3 = %d, want 3", got)
+	}
+
+	rt.MaxTabs = 0
+	for i := 0; i < 20; i++ {
+		rt.AddResult("SELECT 1", models.QueryResult{})
+	}
+	if got := rt.TabCount(); got != 23 {
+		t.Errorf("TabCount() with no limit = %d, want 23", got)
+	}
+	if bar := rt.RenderTabBar(80); lipgloss.Width(bar) > 80 {
+		t.Errorf("tab bar is %d cells wide, want at most 80", lipgloss.Width(bar))
+	}
+}
+
+func TestTabBarWindow(t *testing.T) {
+	widths := []int{10, 10, 10, 10, 10, 10}
+
+	if start, end := TabBarWindow(widths, 2, 100); start != 0 || end != 6 {
+		t.Errorf("TabBarWindow() with room = %d,%d, want all tabs", start, end)
+	}
+
+	// 40 cells leave 30 after the markers: three tabs, the active one first
+	start, end := TabBarWindow(widths, 4, 40)
+	if start > 4 || end <= 4 || end-start != 3 {
+		t.Errorf("TabBarWindow() = %d,%d, want three tabs around tab 4", start, end)
+	}
+	if start, end := TabBarWindow(widths, 0, 40); start != 0 || end != 3 {
+		t.Errorf("TabBarWindow() at the first tab = %d,%d, want 0,3", start, end)
+	}
+}
+
+func TestResultTabs_Split(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+	rt.AddResult("SELECT 1", models.QueryResult{})
+	if rt.ToggleSplit() {
+		t.Fatal("ToggleSplit() split a single tab")
+	}
+	rt.AddResult("SELECT 2", models.QueryResult{})
+	rt.AddResult("SELECT 3", models.QueryResult{}) // Tabs: 3, 2, 1
+
+	if !rt.ToggleSplit() {
+		t.Fatal("ToggleSplit() didn't split two tabs")
+	}
+	first, second, firstFocused := rt.SplitPanes()
+	if first.SQL != "SELECT 3" || second.SQL != "SELECT 2" || !firstFocused {
+		t.Fatalf("panes = %q, %q (first focused: %v), want the active tab and the next", first.SQL, second.SQL, firstFocused)
+	}
+
+	// Focus moves between panes without moving the tabs
+	rt.SwitchSplitFocus()
+	if first, second, firstFocused := rt.SplitPanes(); first.SQL != "SELECT 3" || second.SQL != "SELECT 2" || firstFocused {
+		t.Errorf("after switching focus: panes = %q, %q (first focused: %v)", first.SQL, second.SQL, firstFocused)
+	}
+	if got := rt.GetActiveSQL(); got != "SELECT 2" {
+		t.Errorf("active tab = %q, want the second pane's", got)
+	}
+
+	// Switching tabs changes the focused pane's tab; landing on the other
+	// pane's tab focuses that pane
+	rt.NextTab()
+	if first, second, _ := rt.SplitPanes(); first.SQL != "SELECT 3" || second.SQL != "SELECT 1" {
+		t.Errorf("after NextTab: panes = %q, %q", first.SQL, second.SQL)
+	}
+	rt.SetActiveTab(0)
+	if _, _, firstFocused := rt.SplitPanes(); !firstFocused || rt.GetActiveSQL() != "SELECT 3" {
+		t.Errorf("selecting the other pane's tab didn't focus it")
+	}
+
+	bar := rt.RenderPaneTitle(rt.GetActiveTab(), true, 30)
+	if w := lipgloss.Width(bar); w != 30 {
+		t.Errorf("pane title is %d cells wide, want 30", w)
+	}
+
+	// Closing a pane's tab shows another tab in it; once only the other
+	// pane's tab is left, it fills the panel
+	rt.SwitchSplitFocus()
+	rt.CloseActiveTab()
+	if first, second, _ := rt.SplitPanes(); first == nil || second == nil || first.SQL != "SELECT 3" || second.SQL != "SELECT 2" {
+		t.Fatal("closing a pane's tab didn't move another tab into it")
+	}
+	rt.CloseActiveTab()
+	if rt.IsSplit() {
+		t.Error("still split with one tab left")
+	}
+	rt.AddResult("SELECT 4", models.QueryResult{})
+
+	rt.ToggleSplit()
+	if !rt.IsSplit() || rt.ToggleSplit() || rt.IsSplit() {
+		t.Error("ToggleSplit() didn't go back to a single tab")
+	}
+}
+
+func TestStripZones(t *testing.T) {
+	if got := StripZones("\x1b[12zcell\x1b[12z \x1b[1mbold\x1b[0m"); got != "cell \x1b[1mbold\x1b[0m" {
+		t.Errorf("StripZones() = %q, want the zone markers gone and styles kept", got)
+	}
+}
+
+func TestResultTabs_CloseConnectionTabs(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+	rt.AddResult("SELECT 1", models.QueryResult{})
+	rt.StartPendingQuery("SELECT 2")
+	rt.SetPendingConnection("staging")
+	rt.CompletePendingQuery("SELECT 2", models.QueryResult{})
+	rt.StartPendingQuery("SELECT 3")
+	rt.SetPendingConnection("staging") // Tabs: 3 (pending), 2, 1
+
+	if closed := rt.CloseConnectionTabs("staging"); closed != 2 {
+		t.Errorf("CloseConnectionTabs() = %d, want 2", closed)
+	}
+	if rt.TabCount() != 1 || rt.GetActiveSQL() != "SELECT 1" {
+		t.Errorf("%d tabs left, active %q: want only the active connection's query", rt.TabCount(), rt.GetActiveSQL())
+	}
+	if rt.HasPendingQuery() {
+		t.Error("closing the pending tab should clear the pending query")
+	}
+}
+
+func TestResultTabs_CloseObjectTabs(t *testing.T) {
+	th := theme.DefaultTheme()
+	rt := NewResultTabs(th)
+	rt.AddResult("SELECT 1", models.QueryResult{})
+	rt.AddTableData("public.users", "users", NewStructureView(th, NewTableView(th)))
+	rt.AddResult("SELECT 2", models.QueryResult{})
+	rt.AddTableData("public.orders", "orders", NewStructureView(th, NewTableView(th))) // Tabs: orders, 2, users, 1
+	rt.SetActiveTab(1)
+	rt.ToggleSplit()
+
+	if closed := rt.CloseObjectTabs(); closed != 2 {
+		t.Errorf("CloseObjectTabs() = %d, want 2", closed)
+	}
+	if rt.TabCount() != 2 {
+		t.Fatalf("%d tabs left, want the 2 query results", rt.TabCount())
+	}
+	if got := rt.GetActiveSQL(); got != "SELECT 2" {
+		t.Errorf("active tab = %q, want it kept", got)
+	}
+	if first, second, _ := rt.SplitPanes(); second != nil {
+		t.Errorf("panes = %v, %v, want the split to the closed tab ended", first, second)
+	}
+}
@@ -0,0 +1,69 @@
+package components
+
+import (
+	"hash/fnv"
+	"slices"
+	"time"
+)
+
+// ChangeHighlightDuration is how long rows that a refresh added or changed
+// stay highlighted
+const ChangeHighlightDuration = 2 * time.Second
+
+// MaxChangeDiffRows caps the rows a refresh is compared over, so the
+// previous set's keys stay small
+const MaxChangeDiffRows = 5000
+
+// ChangedRows returns the indexes in newRows of rows that are new or whose
+// values differ from oldRows. Rows are matched on the primary key columns
+// pk, and the previous set is kept as a hash of each row by key. It returns
+// nil when there is no key, a key column is missing, there are no previous
+// rows, or either set is larger than MaxChangeDiffRows.
+func ChangedRows(columns, pk []string, oldRows, newRows [][]string) map[int]bool {
+	if len(pk) == 0 || len(oldRows) == 0 ||
+		len(oldRows) > MaxChangeDiffRows || len(newRows) > MaxChangeDiffRows {
+		return nil
+	}
+	keyCols := make([]int, len(pk))
+	for i, name := range pk {
+		keyCols[i] = slices.Index(columns, name)
+		if keyCols[i] < 0 {
+			return nil
+		}
+	}
+
+	previous := make(map[string]uint64, len(oldRows))
+	for _, row := range oldRows {
+		previous[rowKey(row, keyCols)] = rowHash(row)
+	}
+
+	changed := make(map[int]bool)
+	for i, row := range newRows {
+		if hash, ok := previous[rowKey(row, keyCols)]; !ok || hash != rowHash(row) {
+			changed[i] = true
+		}
+	}
+	return changed
+}
+
+// rowKey joins a row's key values with a separator no text value contains
+func rowKey(row []string, keyCols []int) string {
+	key := make([]byte, 0, 32)
+	for _, c := range keyCols {
+		if c < len(row) {
+			key = append(key, row[c]...)
+		}
+		key = append(key, 0)
+	}
+	return string(key)
+}
+
+// rowHash hashes all of a row's values
+func rowHash(row []string) uint64 {
+	h := fnv.New64a()
+	for _, v := range row {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
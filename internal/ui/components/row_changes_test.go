@@ -0,0 +1,66 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestChangedRows(t *testing.T) {
+	columns := []string{"id", "name", "total"}
+	oldRows := [][]string{
+		{"1", "alice", "10"},
+		{"2", "bob", "20"},
+		{"3", "carol", "30"},
+	}
+	// bob changed, carol moved up unchanged, dave is new, alice was deleted
+	newRows := [][]string{
+		{"3", "carol", "30"},
+		{"2", "bob", "25"},
+		{"4", "dave", "40"},
+	}
+
+	got := ChangedRows(columns, []string{"id"}, oldRows, newRows)
+	if want := map[int]bool{1: true, 2: true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedRows() = %v, want %v", got, want)
+	}
+
+	// Composite keys match on every key column
+	got = ChangedRows(columns, []string{"id", "name"}, oldRows, [][]string{{"1", "alicia", "10"}})
+	if want := map[int]bool{0: true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedRows() with a composite key = %v, want %v", got, want)
+	}
+
+	if got := ChangedRows(columns, nil, oldRows, newRows); got != nil {
+		t.Errorf("ChangedRows() without a key = %v, want nil", got)
+	}
+	if got := ChangedRows(columns, []string{"uuid"}, oldRows, newRows); got != nil {
+		t.Errorf("ChangedRows() with a missing key column = %v, want nil", got)
+	}
+	if got := ChangedRows(columns, []string{"id"}, nil, newRows); got != nil {
+		t.Errorf("ChangedRows() on a first load = %v, want nil", got)
+	}
+}
+
+func TestTableView_ChangeHighlight(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.SetData([]string{"id"}, [][]string{{"1"}, {"2"}}, 2)
+
+	first := tv.HighlightChanges(map[int]bool{1: true})
+	second := tv.HighlightChanges(map[int]bool{0: true})
+	tv.ClearChangeHighlight(first)
+	if !tv.IsChangedRow(0) {
+		t.Error("clearing an older highlight ended the newer one")
+	}
+	tv.ClearChangeHighlight(second)
+	if tv.IsChangedRow(0) {
+		t.Error("ClearChangeHighlight() left the row highlighted")
+	}
+
+	tv.HighlightChanges(map[int]bool{0: true})
+	tv.SetData([]string{"id"}, [][]string{{"9"}}, 1)
+	if tv.IsChangedRow(0) {
+		t.Error("SetData() kept the highlight of the previous rows")
+	}
+}
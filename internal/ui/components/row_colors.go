@@ -0,0 +1,221 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// rowColorOps are the comparisons a row color rule can use, longest first
+// so "<=" isn't read as "<"
+var rowColorOps = []string{"<=", ">=", "!=", "<>", "=", "<", ">", "~"}
+
+// hexColor matches a #rrggbb color
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// RowColorRule colors the rows whose value in Column compares to Value by
+// Op, e.g. status = 'error' -> red. Values compare as numbers when both
+// sides are numbers, as text otherwise; ~ matches values containing Value,
+// ignoring case. NULL cells only match = NULL and != of a non-NULL value.
+type RowColorRule struct {
+	Column string
+	Op     string
+	Value  string
+	Color  string // red, green, yellow, blue, gray or #rrggbb
+}
+
+// ParseRowColorRule parses a rule written as column op value -> color. The
+// column may be "double quoted" and the value 'single quoted'.
+func ParseRowColorRule(s string) (RowColorRule, error) {
+	arrow := strings.LastIndex(s, "->")
+	if arrow < 0 {
+		return RowColorRule{}, fmt.Errorf("missing -> color in %q", s)
+	}
+	condition, color := strings.TrimSpace(s[:arrow]), strings.ToLower(strings.TrimSpace(s[arrow+2:]))
+	if !validRowColor(color) {
+		return RowColorRule{}, fmt.Errorf("unknown color %q: use red, green, yellow, blue, gray or #rrggbb", color)
+	}
+
+	column, rest, err := parseRuleColumn(condition)
+	if err != nil {
+		return RowColorRule{}, err
+	}
+	rest = strings.TrimSpace(rest)
+	op := ""
+	for _, candidate := range rowColorOps {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return RowColorRule{}, fmt.Errorf("missing comparison after %s: use =, !=, <, <=, >, >= or ~", column)
+	}
+	if op == "<>" {
+		op = "!="
+	}
+
+	value := strings.TrimSpace(rest[len(op):])
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	} else if value == "" {
+		return RowColorRule{}, fmt.Errorf("missing value after %s %s", column, op)
+	}
+
+	return RowColorRule{Column: column, Op: op, Value: value, Color: color}, nil
+}
+
+// parseRuleColumn splits the column name off the start of a condition
+func parseRuleColumn(condition string) (column, rest string, err error) {
+	if strings.HasPrefix(condition, `"`) {
+		end := strings.Index(condition[1:], `"`)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quoted column in %q", condition)
+		}
+		return condition[1 : end+1], condition[end+2:], nil
+	}
+	end := strings.IndexAny(condition, " <>=!~")
+	if end <= 0 {
+		return "", "", fmt.Errorf("missing column in %q", condition)
+	}
+	return condition[:end], condition[end:], nil
+}
+
+// validRowColor reports whether color names a color a rule can use
+func validRowColor(color string) bool {
+	switch color {
+	case "red", "green", "yellow", "blue", "gray", "grey":
+		return true
+	}
+	return hexColor.MatchString(color)
+}
+
+// String writes the rule the way ParseRowColorRule reads it
+func (r RowColorRule) String() string {
+	column := r.Column
+	if strings.ContainsAny(column, " <>=!~-\"") {
+		column = `"` + column + `"`
+	}
+	value := r.Value
+	if _, err := strconv.ParseFloat(value, 64); err != nil && value != "NULL" {
+		value = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return fmt.Sprintf("%s %s %s -> %s", column, r.Op, value, r.Color)
+}
+
+// Matches reports whether a cell value satisfies the rule's comparison
+func (r RowColorRule) Matches(value string) bool {
+	if value == "NULL" || r.Value == "NULL" {
+		switch r.Op {
+		case "=":
+			return value == r.Value
+		case "!=":
+			return value != r.Value && value != "NULL"
+		}
+		return false
+	}
+
+	if r.Op == "~" {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(r.Value))
+	}
+
+	cmp := strings.Compare(value, r.Value)
+	if a, err := strconv.ParseFloat(value, 64); err == nil {
+		if b, err := strconv.ParseFloat(r.Value, 64); err == nil {
+			switch {
+			case a < b:
+				cmp = -1
+			case a > b:
+				cmp = 1
+			default:
+				cmp = 0
+			}
+		}
+	}
+	switch r.Op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// ThemeColor returns the rule's color, with the names taken from the theme
+func (r RowColorRule) ThemeColor(th theme.Theme) lipgloss.Color {
+	switch r.Color {
+	case "red":
+		return th.Error
+	case "green":
+		return th.Success
+	case "yellow":
+		return th.Warning
+	case "blue":
+		return th.Info
+	case "gray", "grey":
+		return th.Metadata
+	}
+	return lipgloss.Color(r.Color)
+}
+
+// RowColorRules color the rows of every grid by the first rule that
+// matches. They only change how rows are drawn, never what is queried. The
+// same rules are shared by all grids, so edits show everywhere at once.
+type RowColorRules struct {
+	Rules []RowColorRule
+}
+
+// NewRowColorRules parses rules, e.g. from the config. Invalid rules are
+// left out and reported in the error; the rest still apply.
+func NewRowColorRules(specs []string) (*RowColorRules, error) {
+	rules := &RowColorRules{}
+	var invalid []string
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		rule, err := ParseRowColorRule(spec)
+		if err != nil {
+			invalid = append(invalid, err.Error())
+			continue
+		}
+		rules.Rules = append(rules.Rules, rule)
+	}
+	if len(invalid) > 0 {
+		return rules, fmt.Errorf("invalid row color rules:\n%s", strings.Join(invalid, "\n"))
+	}
+	return rules, nil
+}
+
+// Empty reports whether there are no rules
+func (rc *RowColorRules) Empty() bool {
+	return rc == nil || len(rc.Rules) == 0
+}
+
+// Match returns the first rule matching a row of a grid with the given
+// columns. Column names match ignoring case.
+func (rc *RowColorRules) Match(columns, row []string) (RowColorRule, bool) {
+	if rc.Empty() {
+		return RowColorRule{}, false
+	}
+	for _, rule := range rc.Rules {
+		for i, col := range columns {
+			if i < len(row) && strings.EqualFold(col, rule.Column) && rule.Matches(row[i]) {
+				return rule, true
+			}
+		}
+	}
+	return RowColorRule{}, false
+}
@@ -0,0 +1,186 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// CloseRowColorsMsg is sent when the row color rule editor closes
+type CloseRowColorsMsg struct{}
+
+// RowColorsDialog edits the row color rules while lazypg runs. Rules are
+// changed in place, so every grid shows the edit as soon as it is made.
+type RowColorsDialog struct {
+	Width int
+	Theme theme.Theme
+	Rules *RowColorRules
+	Err   string
+
+	input    textinput.Model
+	selected int
+	// editing is the position a rule taken back into the input for editing
+	// returns to, -1 when adding a new rule; original is the rule as it was
+	editing  int
+	original RowColorRule
+}
+
+// NewRowColorsDialog creates a new row color rule editor for rules
+func NewRowColorsDialog(th theme.Theme, rules *RowColorRules) *RowColorsDialog {
+	ti := textinput.New()
+	ti.Placeholder = "status = 'error' -> red"
+	ti.CharLimit = 256
+	ti.Width = 50
+
+	return &RowColorsDialog{
+		Width:   70,
+		Theme:   th,
+		Rules:   rules,
+		input:   ti,
+		editing: -1,
+	}
+}
+
+// Open shows the editor with an empty input
+func (d *RowColorsDialog) Open() {
+	d.Err = ""
+	d.editing = -1
+	d.selected = min(d.selected, max(len(d.Rules.Rules)-1, 0))
+	d.input.SetValue("")
+	d.input.Focus()
+}
+
+// Init starts the cursor blinking
+func (d *RowColorsDialog) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles keyboard input. Enter adds the rule typed in the input,
+// ↑↓ select a rule, Ctrl+E takes it back into the input to edit and
+// Ctrl+D deletes it.
+func (d *RowColorsDialog) Update(msg tea.KeyMsg) (*RowColorsDialog, tea.Cmd) {
+	rules := d.Rules.Rules
+	switch msg.String() {
+	case "esc":
+		if d.editing >= 0 {
+			// Put the rule being edited back unchanged
+			d.cancelEdit()
+			return d, nil
+		}
+		return d, func() tea.Msg { return CloseRowColorsMsg{} }
+	case "up":
+		if d.selected > 0 {
+			d.selected--
+		}
+		return d, nil
+	case "down":
+		if d.selected < len(rules)-1 {
+			d.selected++
+		}
+		return d, nil
+	case "ctrl+d":
+		if d.selected < len(rules) {
+			d.Rules.Rules = append(rules[:d.selected:d.selected], rules[d.selected+1:]...)
+			d.selected = min(d.selected, max(len(d.Rules.Rules)-1, 0))
+		}
+		return d, nil
+	case "ctrl+e":
+		if d.editing < 0 && d.selected < len(rules) {
+			d.input.SetValue(rules[d.selected].String())
+			d.input.CursorEnd()
+			d.editing = d.selected
+			d.original = rules[d.selected]
+			d.Rules.Rules = append(rules[:d.selected:d.selected], rules[d.selected+1:]...)
+		}
+		return d, nil
+	case "enter":
+		if strings.TrimSpace(d.input.Value()) == "" {
+			return d, nil
+		}
+		rule, err := ParseRowColorRule(d.input.Value())
+		if err != nil {
+			d.Err = err.Error()
+			return d, nil
+		}
+		d.insert(rule)
+		d.input.SetValue("")
+		return d, nil
+	}
+
+	d.Err = ""
+	var cmd tea.Cmd
+	d.input, cmd = d.input.Update(msg)
+	return d, cmd
+}
+
+// insert adds rule where the rule being edited was, or at the end
+func (d *RowColorsDialog) insert(rule RowColorRule) {
+	at := len(d.Rules.Rules)
+	if d.editing >= 0 {
+		at = min(d.editing, at)
+	}
+	d.Rules.Rules = append(d.Rules.Rules[:at:at], append([]RowColorRule{rule}, d.Rules.Rules[at:]...)...)
+	d.selected = at
+	d.editing = -1
+	d.Err = ""
+}
+
+// cancelEdit puts the rule being edited back as it was
+func (d *RowColorsDialog) cancelEdit() {
+	d.insert(d.original)
+	d.input.SetValue("")
+}
+
+// View renders the editor
+func (d *RowColorsDialog) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(d.Theme.Background).
+		Background(d.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	selectedStyle := lipgloss.NewStyle().Background(d.Theme.Selection).Bold(true)
+	errStyle := lipgloss.NewStyle().Foreground(d.Theme.Error)
+	keyStyle := lipgloss.NewStyle().Foreground(d.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(d.Theme.Metadata)
+
+	sections := []string{titleStyle.Render("Row Colors"), ""}
+	if len(d.Rules.Rules) == 0 {
+		sections = append(sections, metaStyle.Render("No rules yet. Type one below, e.g. status = 'error' -> red"))
+	}
+	for i, rule := range d.Rules.Rules {
+		line := fmt.Sprintf("%d. %s", i+1, rule.String())
+		style := lipgloss.NewStyle().Foreground(rule.ThemeColor(d.Theme))
+		if i == d.selected {
+			style = selectedStyle.Foreground(rule.ThemeColor(d.Theme))
+		}
+		sections = append(sections, style.Render(truncateToWidth(line, d.Width-4)))
+	}
+
+	prompt := "Add rule"
+	if d.editing >= 0 {
+		prompt = "Edit rule"
+	}
+	sections = append(sections, "", metaStyle.Render(prompt+":"), d.input.View())
+	if d.Err != "" {
+		sections = append(sections, errStyle.Render(d.Err))
+	}
+	sections = append(sections, "",
+		metaStyle.Render("column op value -> color · op: = != < <= > >= ~ · colors: red green yellow blue gray #rrggbb"),
+		metaStyle.Render("The first matching rule colors the row"),
+		"",
+		keyStyle.Render("Enter")+metaStyle.Render(": Add   ")+
+			keyStyle.Render("Ctrl+E")+metaStyle.Render(": Edit   ")+
+			keyStyle.Render("Ctrl+D")+metaStyle.Render(": Delete   ")+
+			keyStyle.Render("Esc")+metaStyle.Render(": Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(d.Theme.Info).
+		Width(d.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
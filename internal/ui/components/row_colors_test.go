@@ -0,0 +1,183 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestParseRowColorRule(t *testing.T) {
+	tests := []struct {
+		spec string
+		want RowColorRule
+		err  string
+	}{
+		{"status = 'error' -> red", RowColorRule{"status", "=", "error", "red"}, ""},
+		{"latency_ms>=500->YELLOW", RowColorRule{"latency_ms", ">=", "500", "yellow"}, ""},
+		{`"Last Seen" <> NULL -> gray`, RowColorRule{"Last Seen", "!=", "NULL", "gray"}, ""},
+		{"message ~ 'it''s down' -> #ff0000", RowColorRule{"message", "~", "it's down", "#ff0000"}, ""},
+		{"note = '' -> blue", RowColorRule{"note", "=", "", "blue"}, ""},
+		{"status = error", RowColorRule{}, "missing -> color"},
+		{"status = error -> purple", RowColorRule{}, "unknown color"},
+		{"status error -> red", RowColorRule{}, "missing comparison"},
+		{"status = -> red", RowColorRule{}, "missing value"},
+		{"= 1 -> red", RowColorRule{}, "missing column"},
+	}
+	for _, tt := range tests {
+		got, err := ParseRowColorRule(tt.spec)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseRowColorRule(%q) error = %v, want %q", tt.spec, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRowColorRule(%q) = %+v, %v, want %+v", tt.spec, got, err, tt.want)
+			continue
+		}
+		// Written back, a rule reads the same
+		if again, err := ParseRowColorRule(got.String()); err != nil || again != got {
+			t.Errorf("ParseRowColorRule(%q) = %+v, %v, want %+v", got.String(), again, err, got)
+		}
+	}
+}
+
+func TestRowColorRuleMatches(t *testing.T) {
+	tests := []struct {
+		rule  RowColorRule
+		value string
+		want  bool
+	}{
+		{RowColorRule{Op: "=", Value: "error"}, "error", true},
+		{RowColorRule{Op: "=", Value: "error"}, "Error", false},
+		{RowColorRule{Op: ">", Value: "500"}, "1000", true}, // As numbers, not text
+		{RowColorRule{Op: ">", Value: "500"}, "60", false},
+		{RowColorRule{Op: "<=", Value: "1.5"}, "1.50", true},
+		{RowColorRule{Op: "<", Value: "b"}, "apple", true},
+		{RowColorRule{Op: "!=", Value: "ok"}, "failed", true},
+		{RowColorRule{Op: "~", Value: "TIMEOUT"}, "read timeout after 5s", true},
+		{RowColorRule{Op: "=", Value: "NULL"}, "NULL", true},
+		{RowColorRule{Op: "!=", Value: "NULL"}, "x", true},
+		{RowColorRule{Op: "!=", Value: "NULL"}, "NULL", false},
+		{RowColorRule{Op: "!=", Value: "ok"}, "NULL", false},
+		{RowColorRule{Op: ">", Value: "5"}, "NULL", false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Matches(tt.value); got != tt.want {
+			t.Errorf("%s %q on %q = %v, want %v", tt.rule.Op, tt.rule.Value, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRowColorRules(t *testing.T) {
+	rules, err := NewRowColorRules([]string{
+		"level = 'error' -> red",
+		"not a rule",
+		"",
+		"duration > 100 -> yellow",
+	})
+	if err == nil || !strings.Contains(err.Error(), "not a rule") {
+		t.Errorf("NewRowColorRules() error = %v, want the invalid rule reported", err)
+	}
+	if len(rules.Rules) != 2 {
+		t.Fatalf("NewRowColorRules() kept %d rules, want the 2 valid ones", len(rules.Rules))
+	}
+
+	columns := []string{"id", "Level", "duration"}
+	// The first matching rule wins
+	if rule, ok := rules.Match(columns, []string{"1", "error", "500"}); !ok || rule.Color != "red" {
+		t.Errorf("Match() = %+v, %v, want the red rule", rule, ok)
+	}
+	if rule, ok := rules.Match(columns, []string{"2", "info", "500"}); !ok || rule.Color != "yellow" {
+		t.Errorf("Match() = %+v, %v, want the yellow rule", rule, ok)
+	}
+	if _, ok := rules.Match(columns, []string{"3", "info", "5"}); ok {
+		t.Error("Match() matched a row no rule applies to")
+	}
+	if _, ok := rules.Match([]string{"other"}, []string{"error"}); ok {
+		t.Error("Match() matched a grid without the rule's column")
+	}
+	var none *RowColorRules
+	if _, ok := none.Match(columns, []string{"1", "error", "500"}); ok {
+		t.Error("nil rules matched")
+	}
+}
+
+func TestTableView_RowColors(t *testing.T) {
+	th := theme.DefaultTheme()
+	tv := NewTableView(th)
+	tv.RowColors = &RowColorRules{Rules: []RowColorRule{{Column: "status", Op: "=", Value: "error", Color: "red"}}}
+	tv.SetData([]string{"id", "status"}, [][]string{{"1", "ok"}, {"2", "error"}}, 2)
+
+	if _, ok := tv.rowColor(tv.Rows[0]); ok {
+		t.Error("rowColor() colored a row the rule doesn't match")
+	}
+	if color, ok := tv.rowColor(tv.Rows[1]); !ok || color != th.Error {
+		t.Errorf("rowColor() = %v, %v, want the theme's error color", color, ok)
+	}
+
+	// Rules edited in place apply to the next render
+	tv.RowColors.Rules = nil
+	if _, ok := tv.rowColor(tv.Rows[1]); ok {
+		t.Error("rowColor() still colors after the rules were removed")
+	}
+}
+
+func TestRowColorsDialog(t *testing.T) {
+	rules := &RowColorRules{}
+	d := NewRowColorsDialog(theme.DefaultTheme(), rules)
+	d.Open()
+
+	typeRule := func(s string) {
+		d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+		d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	typeRule("status = 'error' -> red")
+	typeRule("latency > 500 -> yellow")
+	typeRule("bad rule")
+	if d.Err == "" || len(rules.Rules) != 2 {
+		t.Fatalf("rules = %v, Err = %q, want 2 rules and an error for the bad one", rules.Rules, d.Err)
+	}
+	for range "bad rule" {
+		d.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+
+	// Edit the first rule; it goes back to its place
+	d.Update(tea.KeyMsg{Type: tea.KeyUp})
+	d.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	if len(rules.Rules) != 1 {
+		t.Fatalf("rules = %v while editing, want the edited rule taken out", rules.Rules)
+	}
+	for range "red" {
+		d.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	typeRule("blue")
+	if len(rules.Rules) != 2 || rules.Rules[0].Color != "blue" {
+		t.Fatalf("rules = %v, want the first rule now blue", rules.Rules)
+	}
+
+	// Esc while editing puts the rule back unchanged instead of closing
+	d.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	d.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if _, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil {
+		t.Error("Esc while editing closed the dialog")
+	}
+	if len(rules.Rules) != 2 || rules.Rules[0].Color != "blue" {
+		t.Errorf("rules = %v, want the edit cancelled", rules.Rules)
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if len(rules.Rules) != 1 || rules.Rules[0].Color != "yellow" {
+		t.Errorf("rules = %v, want only the yellow rule left", rules.Rules)
+	}
+
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc did not close the dialog")
+	}
+	if _, ok := cmd().(CloseRowColorsMsg); !ok {
+		t.Errorf("Esc sent %#v, want CloseRowColorsMsg", cmd())
+	}
+}
@@ -0,0 +1,528 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/filter"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// EditRowKey opens the selected row of a table tab in the row edit form
+const EditRowKey = "e"
+
+// RowEditSaveMsg asks to run the UPDATE built by the row edit form
+type RowEditSaveMsg struct {
+	ObjectID string
+	SQL      string
+}
+
+// CloseRowEditMsg is sent when the row edit form is cancelled
+type CloseRowEditMsg struct{}
+
+// RowEditField is one column of the row being edited
+type RowEditField struct {
+	Column   string
+	DataType string
+	Nullable bool
+	Key      bool   // Part of the primary key, which finds the row
+	ReadOnly string // Why the column can't be set ("identity", "generated", "masked"), or ""
+	Masked   bool   // Shown as MaskedValue, as in the grid
+
+	Original     string // The value as loaded
+	OriginalNull bool
+	Null         bool
+	Err          string
+
+	input textinput.Model
+}
+
+// Value returns the field's current value; meaningless when Null is set
+func (f *RowEditField) Value() string {
+	return f.input.Value()
+}
+
+// Changed reports whether the field differs from the loaded value
+func (f *RowEditField) Changed() bool {
+	if f.Null || f.OriginalNull {
+		return f.Null != f.OriginalNull
+	}
+	return f.input.Value() != f.Original
+}
+
+// isBool reports whether the field holds a boolean, toggled rather than typed
+func (f *RowEditField) isBool() bool {
+	return filter.BaseType(f.DataType) == "boolean"
+}
+
+// RowEditForm edits every column of one table row and saves the changes
+// as a single UPDATE, finding the row by its primary key. Identity and
+// generated columns are shown but can't be changed, and neither can cells
+// masked in the grid, which stay masked.
+type RowEditForm struct {
+	Width  int
+	Height int
+	Theme  theme.Theme
+
+	Schema   string
+	Table    string
+	ObjectID string
+	Key      []string // Primary key columns, in key order
+	Fields   []*RowEditField
+	Err      string
+
+	selected int
+	offset   int // First visible field
+}
+
+// NewRowEditForm creates a new row edit form
+func NewRowEditForm(th theme.Theme) *RowEditForm {
+	return &RowEditForm{
+		Width:  80,
+		Height: 24,
+		Theme:  th,
+	}
+}
+
+// Open loads a row of the grid into the form. columns describe the
+// table's columns; gridColumns and row are the grid's header and the row
+// as shown, and masked, parallel to them, marks the cells the grid masks.
+// It fails if the row can't be identified by a primary key: a unique key
+// won't do, as it allows any number of rows with NULLs.
+func (f *RowEditForm) Open(schema, table, objectID string, columns []models.ColumnDetail, gridColumns, row []string, masked []bool) error {
+	values := make(map[string]string, len(gridColumns))
+	maskedCols := make(map[string]bool)
+	for i, name := range gridColumns {
+		if i < len(row) {
+			values[name] = row[i]
+		}
+		if i < len(masked) && masked[i] {
+			maskedCols[name] = true
+		}
+	}
+
+	primaryKey := models.PrimaryKey(columns)
+	if len(primaryKey) == 0 {
+		return fmt.Errorf("%s.%s has no primary key, so the row can't be identified for an UPDATE", schema, table)
+	}
+	key := make([]string, len(primaryKey))
+	for i, col := range primaryKey {
+		if _, ok := values[col.Name]; !ok {
+			return fmt.Errorf("primary key column %s is not in the grid", col.Name)
+		}
+		key[i] = col.Name
+	}
+
+	var fields []*RowEditField
+	for _, col := range columns {
+		value, ok := values[col.Name]
+		if !ok {
+			continue
+		}
+		field := &RowEditField{
+			Column:   col.Name,
+			DataType: col.DataType,
+			Nullable: col.IsNullable,
+			Key:      col.IsPrimaryKey,
+			Original: value,
+		}
+		switch {
+		case maskedCols[col.Name]:
+			// Editing would show the value, and saving it unchanged is
+			// no use; reveal the cell in the grid to edit it
+			field.ReadOnly = "masked"
+			field.Masked = true
+		case col.Identity == "a":
+			field.ReadOnly = "identity"
+		case col.IsGenerated:
+			field.ReadOnly = "generated"
+		}
+		if value == filter.NullCellValue {
+			field.Original = ""
+			field.OriginalNull = true
+			field.Null = true
+		}
+		field.input = textinput.New()
+		field.input.Prompt = ""
+		field.input.CharLimit = 0
+		field.input.Placeholder = col.DataType
+		field.input.SetValue(field.Original)
+		fields = append(fields, field)
+	}
+
+	f.Schema = schema
+	f.Table = table
+	f.ObjectID = objectID
+	f.Key = key
+	f.Fields = fields
+	f.Err = ""
+	f.offset = 0
+	f.selected = 0
+	// Start on the first column that can be edited
+	for i, field := range fields {
+		if field.ReadOnly == "" {
+			f.selected = i
+			break
+		}
+	}
+	f.focus()
+	return nil
+}
+
+// focus puts the cursor in the selected field
+func (f *RowEditForm) focus() {
+	for i, field := range f.Fields {
+		if i == f.selected && field.ReadOnly == "" && !field.Null && !field.isBool() {
+			field.input.Focus()
+			field.input.CursorEnd()
+		} else {
+			field.input.Blur()
+		}
+	}
+}
+
+// Selected returns the selected field
+func (f *RowEditForm) Selected() *RowEditField {
+	if f.selected < 0 || f.selected >= len(f.Fields) {
+		return nil
+	}
+	return f.Fields[f.selected]
+}
+
+// Update handles keyboard input. ↑↓ and Tab move between fields, Ctrl+N
+// sets or clears NULL, Ctrl+R restores the loaded value, Space toggles a
+// boolean and Ctrl+S saves.
+func (f *RowEditForm) Update(msg tea.KeyMsg) (*RowEditForm, tea.Cmd) {
+	field := f.Selected()
+	if field == nil {
+		if msg.String() == "esc" {
+			return f, func() tea.Msg { return CloseRowEditMsg{} }
+		}
+		return f, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		return f, func() tea.Msg { return CloseRowEditMsg{} }
+	case "up", "shift+tab":
+		f.move(-1)
+		return f, nil
+	case "down", "tab":
+		f.move(1)
+		return f, nil
+	case "ctrl+s":
+		return f, f.save()
+	case "ctrl+r":
+		field.Null = field.OriginalNull
+		field.input.SetValue(field.Original)
+		field.Err = ""
+		f.focus()
+		return f, nil
+	case "ctrl+n":
+		switch {
+		case field.ReadOnly != "":
+			field.Err = readOnlyError(field)
+		case !field.Null && !field.Nullable:
+			field.Err = "Can't be NULL: the column is NOT NULL"
+		default:
+			field.Null = !field.Null
+			field.Err = ""
+			if !field.Null && field.isBool() && field.input.Value() == "" {
+				field.input.SetValue("true")
+			}
+		}
+		f.focus()
+		return f, nil
+	}
+
+	if field.ReadOnly != "" {
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace || msg.Type == tea.KeyBackspace {
+			field.Err = readOnlyError(field)
+		}
+		return f, nil
+	}
+
+	if field.isBool() {
+		if msg.String() == " " || msg.String() == "enter" {
+			value := "true"
+			if !field.Null && field.input.Value() == "true" {
+				value = "false"
+			}
+			field.Null = false
+			field.input.SetValue(value)
+			field.Err = ""
+		}
+		return f, nil
+	}
+
+	if msg.String() == "enter" {
+		f.move(1)
+		return f, nil
+	}
+
+	// Typing into a NULL field gives it a value
+	if field.Null && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+		field.Null = false
+		field.input.SetValue("")
+		f.focus()
+	}
+	field.Err = ""
+	var cmd tea.Cmd
+	field.input, cmd = field.input.Update(msg)
+	return f, cmd
+}
+
+// readOnlyError explains why a read-only field can't be changed
+func readOnlyError(field *RowEditField) string {
+	if field.Masked {
+		return "Read-only: masked; reveal the cell with " + RevealCellKey + " to edit it"
+	}
+	return "Read-only: " + field.ReadOnly + " column"
+}
+
+// move selects the field delta places away, keeping it in view
+func (f *RowEditForm) move(delta int) {
+	f.selected = max(0, min(f.selected+delta, len(f.Fields)-1))
+	lines := f.visibleFields()
+	if f.selected < f.offset {
+		f.offset = f.selected
+	} else if f.selected >= f.offset+lines {
+		f.offset = f.selected - lines + 1
+	}
+	f.focus()
+}
+
+// visibleFields returns how many fields fit, two lines each
+func (f *RowEditForm) visibleFields() int {
+	return max(3, (f.Height-10)/2)
+}
+
+// Validate checks the changed fields, recording an error on each invalid
+// one, and returns how many are invalid
+func (f *RowEditForm) Validate() int {
+	invalid := 0
+	for _, field := range f.Fields {
+		field.Err = ""
+		if !field.Changed() {
+			continue
+		}
+		if field.Null {
+			if !field.Nullable {
+				field.Err = "Can't be NULL: the column is NOT NULL"
+			}
+		} else if err := ValidateFieldValue(field.DataType, field.input.Value()); err != nil {
+			field.Err = err.Error()
+		}
+		if field.Err != "" {
+			invalid++
+		}
+	}
+	return invalid
+}
+
+// UpdateSQL returns the UPDATE setting the changed fields of the row with
+// the loaded key values, matched in key order
+func (f *RowEditForm) UpdateSQL() (string, error) {
+	var set []filter.ColumnValue
+	key := make([]filter.ColumnValue, 0, len(f.Key))
+	for _, column := range f.Key {
+		for _, field := range f.Fields {
+			if field.Column == column {
+				key = append(key, filter.ColumnValue{Column: field.Column, Value: field.Original, Type: field.DataType, Null: field.OriginalNull})
+				break
+			}
+		}
+	}
+	for _, field := range f.Fields {
+		if field.Changed() && field.ReadOnly == "" {
+			set = append(set, filter.ColumnValue{Column: field.Column, Value: field.input.Value(), Type: field.DataType, Null: field.Null})
+		}
+	}
+	if len(set) == 0 {
+		return "", fmt.Errorf("nothing changed")
+	}
+	return filter.UpdateRowSQL(f.Schema, f.Table, set, key)
+}
+
+// save validates the form and asks to run its UPDATE
+func (f *RowEditForm) save() tea.Cmd {
+	f.Err = ""
+	if invalid := f.Validate(); invalid > 0 {
+		f.Err = fmt.Sprintf("%d field(s) need fixing before saving", invalid)
+		for i, field := range f.Fields {
+			if field.Err != "" {
+				f.move(i - f.selected)
+				break
+			}
+		}
+		return nil
+	}
+	sql, err := f.UpdateSQL()
+	if err != nil {
+		f.Err = err.Error()
+		return nil
+	}
+	objectID := f.ObjectID
+	return func() tea.Msg {
+		return RowEditSaveMsg{ObjectID: objectID, SQL: sql}
+	}
+}
+
+// uuidPattern matches a UUID in its usual hyphenated form
+var uuidPattern = regexp.MustCompile(`^(?i)\{?[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}\}?$`)
+
+// ValidateFieldValue checks value against the column type, for the types
+// whose mistakes are common and easy to spot; anything else is left to the
+// server
+func ValidateFieldValue(dataType, value string) error {
+	v := strings.TrimSpace(value)
+	switch filter.BaseType(dataType) {
+	case "smallint", "int2":
+		if _, err := strconv.ParseInt(v, 10, 16); err != nil {
+			return fmt.Errorf("not a smallint: %q", value)
+		}
+	case "integer", "int", "int4":
+		if _, err := strconv.ParseInt(v, 10, 32); err != nil {
+			return fmt.Errorf("not an integer: %q", value)
+		}
+	case "bigint", "int8":
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("not a bigint: %q", value)
+		}
+	case "numeric", "decimal", "real", "double precision", "float4", "float8":
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return fmt.Errorf("not a number: %q", value)
+		}
+	case "boolean", "bool":
+		switch strings.ToLower(v) {
+		case "true", "false", "t", "f", "yes", "no", "y", "n", "on", "off", "1", "0":
+		default:
+			return fmt.Errorf("not a boolean: %q", value)
+		}
+	case "json", "jsonb":
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("not valid JSON")
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(v) {
+			return fmt.Errorf("not a UUID: %q", value)
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", v); err != nil && !isSpecialDate(v) {
+			return fmt.Errorf("not a date (YYYY-MM-DD): %q", value)
+		}
+	}
+	return nil
+}
+
+// isSpecialDate reports whether v is one of the special date inputs
+// PostgreSQL accepts
+func isSpecialDate(v string) bool {
+	switch strings.ToLower(v) {
+	case "today", "tomorrow", "yesterday", "infinity", "-infinity", "epoch":
+		return true
+	}
+	return false
+}
+
+// View renders the form
+func (f *RowEditForm) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(f.Theme.Background).
+		Background(f.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	selectedStyle := lipgloss.NewStyle().Background(f.Theme.Selection).Bold(true)
+	nameStyle := lipgloss.NewStyle().Foreground(f.Theme.Foreground)
+	keyStyle := lipgloss.NewStyle().Foreground(f.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(f.Theme.Metadata)
+	nullStyle := lipgloss.NewStyle().Foreground(f.Theme.Metadata).Italic(true)
+	changedStyle := lipgloss.NewStyle().Foreground(f.Theme.Warning).Bold(true)
+	errStyle := lipgloss.NewStyle().Foreground(f.Theme.Error)
+	pkStyle := lipgloss.NewStyle().Foreground(f.Theme.PrimaryKey)
+
+	sections := []string{
+		titleStyle.Render(fmt.Sprintf("Edit Row · %s.%s", f.Schema, f.Table)),
+		"",
+	}
+
+	nameWidth := 0
+	for _, field := range f.Fields {
+		nameWidth = max(nameWidth, lipgloss.Width(field.Column))
+	}
+	nameWidth = min(nameWidth, f.Width/3)
+	valueWidth := max(f.Width-nameWidth-8, 10)
+
+	end := min(f.offset+f.visibleFields(), len(f.Fields))
+	for i := f.offset; i < end; i++ {
+		field := f.Fields[i]
+		marker := "  "
+		if field.Changed() {
+			marker = changedStyle.Render("* ")
+		}
+		name := fmt.Sprintf("%-*s", nameWidth, truncateToWidth(field.Column, nameWidth))
+		if i == f.selected {
+			name = selectedStyle.Render(name)
+		} else {
+			name = nameStyle.Render(name)
+		}
+
+		var value string
+		switch {
+		case field.Null:
+			value = nullStyle.Render("NULL")
+		case field.Masked:
+			value = nullStyle.Render(MaskedValue)
+		case field.input.Focused():
+			field.input.Width = valueWidth
+			value = field.input.View()
+		default:
+			value = truncateToWidth(field.input.Value(), valueWidth)
+		}
+
+		var notes []string
+		if field.Key {
+			notes = append(notes, pkStyle.Render("PK"))
+		}
+		notes = append(notes, metaStyle.Render(field.DataType))
+		if !field.Nullable {
+			notes = append(notes, metaStyle.Render("NOT NULL"))
+		}
+		if field.ReadOnly != "" {
+			notes = append(notes, metaStyle.Render("read-only, "+field.ReadOnly))
+		}
+		detail := strings.Repeat(" ", nameWidth+3) + strings.Join(notes, metaStyle.Render(" · "))
+		if field.Err != "" {
+			detail = strings.Repeat(" ", nameWidth+3) + errStyle.Render(field.Err)
+		}
+		sections = append(sections, marker+name+" "+value, detail)
+	}
+	if f.offset > 0 || end < len(f.Fields) {
+		sections = append(sections, metaStyle.Render(fmt.Sprintf("%d-%d of %d columns", f.offset+1, end, len(f.Fields))))
+	}
+
+	if f.Err != "" {
+		sections = append(sections, "", errStyle.Render(f.Err))
+	}
+	sections = append(sections, "",
+		keyStyle.Render("Ctrl+S")+metaStyle.Render(": Save   ")+
+			keyStyle.Render("Ctrl+N")+metaStyle.Render(": NULL   ")+
+			keyStyle.Render("Ctrl+R")+metaStyle.Render(": Reset field   ")+
+			keyStyle.Render("Space")+metaStyle.Render(": Toggle boolean   ")+
+			keyStyle.Render("Esc")+metaStyle.Render(": Cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(f.Theme.Info).
+		Width(f.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,185 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func rowEditColumns() []models.ColumnDetail {
+	return []models.ColumnDetail{
+		{Name: "id", DataType: "integer(32,0)", IsPrimaryKey: true, Identity: "a"},
+		{Name: "name", DataType: "text"},
+		{Name: "age", DataType: "integer(32,0)", IsNullable: true},
+		{Name: "active", DataType: "boolean"},
+		{Name: "slug", DataType: "text", IsGenerated: true},
+		{Name: "note", DataType: "text", IsNullable: true},
+	}
+}
+
+func TestRowEditForm(t *testing.T) {
+	f := NewRowEditForm(theme.DefaultTheme())
+	err := f.Open("public", "users", "public.users", rowEditColumns(),
+		[]string{"id", "name", "age", "active", "slug", "note"},
+		[]string{"7", "Ann", "30", "true", "ann", "NULL"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Selected().Column != "name" {
+		t.Errorf("selected %s, want the first editable column", f.Selected().Column)
+	}
+
+	special := map[string]tea.KeyMsg{
+		"up": {Type: tea.KeyUp}, "down": {Type: tea.KeyDown}, " ": {Type: tea.KeySpace},
+		"ctrl+n": {Type: tea.KeyCtrlN}, "ctrl+r": {Type: tea.KeyCtrlR},
+	}
+	key := func(s string) {
+		if k, ok := special[s]; ok {
+			f.Update(k)
+			return
+		}
+		f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+
+	// NOT NULL columns can't be set to NULL
+	key("ctrl+n")
+	if f.Selected().Null || f.Selected().Err == "" {
+		t.Error("ctrl+n set a NOT NULL column to NULL")
+	}
+
+	key("y") // name = Anny
+	key("down")
+	key("ctrl+n") // age = NULL
+	key("down")
+	key(" ") // active = false
+	key("down")
+	key("x") // slug is generated
+	if f.Selected().Column != "slug" || f.Selected().Err == "" || f.Selected().Changed() {
+		t.Errorf("typed into the generated column: %+v", f.Selected())
+	}
+	key("down")
+	key("hi") // Typing into the NULL note gives it a value
+
+	sql, err := f.UpdateSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `UPDATE "public"."users"
+SET "name" = 'Anny', "age" = NULL, "active" = false, "note" = 'hi'
+WHERE "id" = 7`
+	if sql != want {
+		t.Errorf("UpdateSQL() =\n%s\nwant\n%s", sql, want)
+	}
+
+	// Invalid values are reported on their field and nothing is saved
+	key("up")
+	key("up")
+	key("up")
+	key("ctrl+n") // age back from NULL
+	key("12x")
+	_, cmd := f.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd != nil || f.Err == "" {
+		t.Fatalf("saved an invalid integer (Err %q)", f.Err)
+	}
+	if f.Selected().Column != "age" || !strings.Contains(f.Selected().Err, "not an integer") {
+		t.Errorf("selected %s with error %q, want the invalid age", f.Selected().Column, f.Selected().Err)
+	}
+
+	key("ctrl+r") // age back to 30
+	_, cmd = f.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatalf("ctrl+s didn't save: %s", f.Err)
+	}
+	if msg, ok := cmd().(RowEditSaveMsg); !ok || msg.ObjectID != "public.users" || strings.Contains(msg.SQL, `"age"`) {
+		t.Errorf("ctrl+s sent %#v, want the UPDATE without the reset age", cmd())
+	}
+}
+
+func TestRowEditForm_NoKey(t *testing.T) {
+	f := NewRowEditForm(theme.DefaultTheme())
+	columns := []models.ColumnDetail{{Name: "a", DataType: "text"}}
+	if err := f.Open("public", "log", "public.log", columns, []string{"a"}, []string{"x"}, nil); err == nil || !strings.Contains(err.Error(), "no primary key") {
+		t.Errorf("Open() error = %v, want no primary key", err)
+	}
+}
+
+func TestRowEditForm_CompositeKey(t *testing.T) {
+	// The key is declared (tenant, id), the reverse of the table's order
+	columns := []models.ColumnDetail{
+		{Name: "id", DataType: "bigint(64,0)", IsPrimaryKey: true, KeyPosition: 2},
+		{Name: "tenant", DataType: "text", IsPrimaryKey: true, KeyPosition: 1},
+		{Name: "email", DataType: "text", IsNullable: true, IsUnique: true},
+	}
+	f := NewRowEditForm(theme.DefaultTheme())
+	if err := f.Open("public", "users", "public.users", columns, []string{"id", "email"}, []string{"7", "a@b.c"}, nil); err == nil || !strings.Contains(err.Error(), "tenant") {
+		t.Errorf("Open() without tenant in the grid: error = %v, want it named", err)
+	}
+
+	if err := f.Open("public", "users", "public.users", columns, []string{"id", "tenant", "email"}, []string{"7", "acme", "NULL"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	f.move(2) // email, typed over its NULL
+	f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a@b.c")})
+	sql, err := f.UpdateSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `WHERE "tenant" = 'acme' AND "id" = 7`; !strings.HasSuffix(sql, want) {
+		t.Errorf("UpdateSQL() =\n%s\nwant it to end %s", sql, want)
+	}
+}
+
+func TestRowEditForm_MaskedColumn(t *testing.T) {
+	columns := []models.ColumnDetail{
+		{Name: "id", DataType: "bigint(64,0)", IsPrimaryKey: true, KeyPosition: 1},
+		{Name: "password", DataType: "text"},
+		{Name: "name", DataType: "text"},
+	}
+	f := NewRowEditForm(theme.DefaultTheme())
+	if err := f.Open("public", "users", "public.users", columns,
+		[]string{"id", "password", "name"}, []string{"7", "hunter2", "Ann"}, []bool{false, true, false}); err != nil {
+		t.Fatal(err)
+	}
+	if view := f.View(); strings.Contains(view, "hunter2") || !strings.Contains(view, MaskedValue) {
+		t.Error("the masked value should not be shown")
+	}
+
+	f.move(1)
+	f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if field := f.Selected(); field.Column != "password" || field.Changed() || !strings.Contains(field.Err, "reveal") {
+		t.Errorf("typing in the masked field: changed %v, error %q", field.Changed(), field.Err)
+	}
+}
+
+func TestValidateFieldValue(t *testing.T) {
+	tests := []struct {
+		dataType, value string
+		ok              bool
+	}{
+		{"integer(32,0)", "42", true},
+		{"integer(32,0)", "4.2", false},
+		{"smallint(16,0)", "70000", false},
+		{"bigint(64,0)", "-9000000000", true},
+		{"numeric(10,2)", "3.14", true},
+		{"numeric(10,2)", "pi", false},
+		{"boolean", "yes", true},
+		{"boolean", "maybe", false},
+		{"jsonb", `{"a": [1, 2]}`, true},
+		{"jsonb", `{"a": }`, false},
+		{"uuid", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", true},
+		{"uuid", "a0eebc99", false},
+		{"date", "2024-02-29", true},
+		{"date", "today", true},
+		{"date", "2024-13-01", false},
+		{"text", "anything", true},
+		{"timestamp with time zone", "whenever", true}, // Left to the server
+	}
+	for _, tt := range tests {
+		if err := ValidateFieldValue(tt.dataType, tt.value); (err == nil) != tt.ok {
+			t.Errorf("ValidateFieldValue(%s, %q) = %v, want ok %v", tt.dataType, tt.value, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// RowWindowKey opens the LIMIT/OFFSET editor on a table tab
+const RowWindowKey = "o"
+
+// MaxRowWindowLimit is the largest LIMIT the editor accepts, since the
+// whole window is loaded at once
+const MaxRowWindowLimit = 10000
+
+// RowWindowSubmitMsg is sent when the LIMIT/OFFSET editor is confirmed. A
+// nil Window goes back to loading pages as the grid is scrolled. Clamped is
+// set when the OFFSET asked for was past the last row.
+type RowWindowSubmitMsg struct {
+	Window  *models.RowWindow
+	Clamped bool
+}
+
+// RowWindowCancelMsg is sent when the LIMIT/OFFSET editor is closed
+// without changes
+type RowWindowCancelMsg struct{}
+
+// RowWindowDialog edits the LIMIT and OFFSET of a table tab, e.g. to sample
+// rows from the middle of a large table
+type RowWindowDialog struct {
+	Width int
+	Theme theme.Theme
+
+	// TotalRows is the number of rows the window is taken from, for
+	// clamping the offset
+	TotalRows int
+	Err       string
+
+	limit  textinput.Model
+	offset textinput.Model
+}
+
+// NewRowWindowDialog creates a new LIMIT/OFFSET editor
+func NewRowWindowDialog(th theme.Theme) *RowWindowDialog {
+	newInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.CharLimit = 12
+		input.Width = 14
+		input.Prompt = ""
+		return input
+	}
+	return &RowWindowDialog{
+		Width:  50,
+		Theme:  th,
+		limit:  newInput("100"),
+		offset: newInput("0"),
+	}
+}
+
+// Open shows the editor filled in with the tab's current window, or empty
+// when the tab pages as it is scrolled
+func (d *RowWindowDialog) Open(window *models.RowWindow, totalRows int) {
+	d.TotalRows = totalRows
+	d.Err = ""
+	d.limit.SetValue("")
+	d.offset.SetValue("")
+	if window != nil {
+		d.limit.SetValue(strconv.Itoa(window.Limit))
+		d.offset.SetValue(strconv.Itoa(window.Offset))
+	}
+	d.offset.Blur()
+	d.limit.Focus()
+	d.limit.CursorEnd()
+}
+
+// Init starts the cursor blinking
+func (d *RowWindowDialog) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles keyboard input. Tab and the arrows move between the two
+// fields; Enter validates them and submits the window.
+func (d *RowWindowDialog) Update(msg tea.KeyMsg) (*RowWindowDialog, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return d, func() tea.Msg { return RowWindowCancelMsg{} }
+	case "tab", "shift+tab", "up", "down":
+		if d.limit.Focused() {
+			d.limit.Blur()
+			d.offset.Focus()
+		} else {
+			d.offset.Blur()
+			d.limit.Focus()
+		}
+		return d, nil
+	case "enter":
+		window, clamped, err := ParseRowWindow(d.limit.Value(), d.offset.Value(), d.TotalRows)
+		if err != nil {
+			d.Err = err.Error()
+			return d, nil
+		}
+		return d, func() tea.Msg { return RowWindowSubmitMsg{Window: window, Clamped: clamped} }
+	}
+
+	d.Err = ""
+	var cmd tea.Cmd
+	if d.limit.Focused() {
+		d.limit, cmd = d.limit.Update(msg)
+	} else {
+		d.offset, cmd = d.offset.Update(msg)
+	}
+	return d, cmd
+}
+
+// ParseRowWindow validates the LIMIT and OFFSET typed in the editor. Both
+// empty means no window; an empty LIMIT is 100 and an empty OFFSET 0. An
+// offset at or past totalRows is moved back so the window ends at the last
+// row, and clamped reports it.
+func ParseRowWindow(limitText, offsetText string, totalRows int) (window *models.RowWindow, clamped bool, err error) {
+	limitText, offsetText = strings.TrimSpace(limitText), strings.TrimSpace(offsetText)
+	if limitText == "" && offsetText == "" {
+		return nil, false, nil
+	}
+
+	limit, offset := 100, 0
+	if limitText != "" {
+		if limit, err = parseNonNegative(limitText); err != nil {
+			return nil, false, fmt.Errorf("LIMIT %s", err)
+		}
+		if limit == 0 {
+			return nil, false, fmt.Errorf("LIMIT must be at least 1")
+		}
+		if limit > MaxRowWindowLimit {
+			return nil, false, fmt.Errorf("LIMIT can be at most %d", MaxRowWindowLimit)
+		}
+	}
+	if offsetText != "" {
+		if offset, err = parseNonNegative(offsetText); err != nil {
+			return nil, false, fmt.Errorf("OFFSET %s", err)
+		}
+	}
+
+	if totalRows > 0 && offset >= totalRows {
+		offset = max(totalRows-limit, 0)
+		clamped = true
+	}
+	return &models.RowWindow{Offset: offset, Limit: limit}, clamped, nil
+}
+
+// parseNonNegative parses a whole number of rows
+func parseNonNegative(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("must be a non-negative integer, not %q", s)
+	}
+	return n, nil
+}
+
+// View renders the editor
+func (d *RowWindowDialog) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(d.Theme.Background).
+		Background(d.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(d.Theme.Metadata).Width(8)
+	focusedLabelStyle := labelStyle.Foreground(d.Theme.Info).Bold(true)
+	errStyle := lipgloss.NewStyle().Foreground(d.Theme.Error)
+	keyStyle := lipgloss.NewStyle().Foreground(d.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(d.Theme.Metadata)
+
+	field := func(label string, input textinput.Model) string {
+		style := labelStyle
+		if input.Focused() {
+			style = focusedLabelStyle
+		}
+		return style.Render(label) + input.View()
+	}
+
+	sections := []string{
+		titleStyle.Render("Rows to Load"),
+		"",
+		field("LIMIT", d.limit),
+		field("OFFSET", d.offset),
+		"",
+		metaStyle.Render(fmt.Sprintf("%d rows in total · leave both empty to page as you scroll", d.TotalRows)),
+	}
+	if d.Err != "" {
+		sections = append(sections, errStyle.Render(d.Err))
+	}
+	sections = append(sections, "",
+		keyStyle.Render("Tab")+metaStyle.Render(": Switch field   ")+
+			keyStyle.Render("Enter")+metaStyle.Render(": Load   ")+
+			keyStyle.Render("Esc")+metaStyle.Render(": Cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(d.Theme.Info).
+		Width(d.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
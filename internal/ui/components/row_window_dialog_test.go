@@ -0,0 +1,104 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestParseRowWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit, offset string
+		total         int
+		want          *models.RowWindow
+		clamped       bool
+		err           string
+	}{
+		{"both empty pages as usual", "", " ", 5000, nil, false, ""},
+		{"limit and offset", "50", "1000", 5000, &models.RowWindow{Offset: 1000, Limit: 50}, false, ""},
+		{"empty offset starts at the top", "20", "", 5000, &models.RowWindow{Limit: 20}, false, ""},
+		{"empty limit is a page", "", "300", 5000, &models.RowWindow{Offset: 300, Limit: 100}, false, ""},
+		{"offset past the end ends at the last row", "100", "9000", 5000, &models.RowWindow{Offset: 4900, Limit: 100}, true, ""},
+		{"offset at the end", "10", "5000", 5000, &models.RowWindow{Offset: 4990, Limit: 10}, true, ""},
+		{"limit larger than the table", "100", "70", 50, &models.RowWindow{Offset: 0, Limit: 100}, true, ""},
+		{"unknown total is not clamped", "10", "9000", 0, &models.RowWindow{Offset: 9000, Limit: 10}, false, ""},
+		{"negative offset", "10", "-1", 5000, nil, false, "OFFSET must be a non-negative integer"},
+		{"not a number", "ten", "0", 5000, nil, false, "LIMIT must be a non-negative integer"},
+		{"fraction", "10", "1.5", 5000, nil, false, "OFFSET must be a non-negative integer"},
+		{"zero limit", "0", "0", 5000, nil, false, "LIMIT must be at least 1"},
+		{"limit too large", "10001", "0", 50000, nil, false, "LIMIT can be at most 10000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, clamped, err := ParseRowWindow(tt.limit, tt.offset, tt.total)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseRowWindow() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRowWindow() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) || clamped != tt.clamped {
+				t.Errorf("ParseRowWindow() = %v, %v, want %v, %v", got, clamped, tt.want, tt.clamped)
+			}
+		})
+	}
+}
+
+func TestRowWindowDialog(t *testing.T) {
+	d := NewRowWindowDialog(theme.DefaultTheme())
+	d.Open(&models.RowWindow{Offset: 200, Limit: 50}, 1000)
+
+	// Replace the offset: tab to it, clear it, type a new one
+	d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	for range "200" {
+		d.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if _, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || d.Err == "" {
+		t.Fatalf("Enter with an invalid offset submitted, Err = %q", d.Err)
+	}
+	if !strings.Contains(d.View(), d.Err) {
+		t.Errorf("View() does not show the error %q", d.Err)
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("700")})
+	if d.Err != "" {
+		t.Errorf("Err = %q after editing, want it cleared", d.Err)
+	}
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter did not submit")
+	}
+	msg, ok := cmd().(RowWindowSubmitMsg)
+	if !ok || msg.Window == nil || *msg.Window != (models.RowWindow{Offset: 700, Limit: 50}) {
+		t.Errorf("submitted %#v, want LIMIT 50 OFFSET 700", cmd())
+	}
+}
+
+func TestTableView_RowWindow(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width, tv.Height = 80, 10
+	tv.SetData([]string{"id"}, [][]string{{"1001"}, {"1002"}, {"1003"}}, 5000)
+	tv.Window = &models.RowWindow{Offset: 1000, Limit: 3}
+
+	view := tv.View()
+	if !strings.Contains(view, "1001-1003 of 5000 rows │ LIMIT 3 OFFSET 1000") {
+		t.Errorf("status does not show the window:\n%s", view)
+	}
+	if !strings.Contains(view, "1001 │") {
+		t.Errorf("rows are not numbered from the offset:\n%s", view)
+	}
+
+	tv.PrefetchThreshold = 10
+	if tv.NeedsPrefetch() {
+		t.Error("NeedsPrefetch() = true for a window")
+	}
+}
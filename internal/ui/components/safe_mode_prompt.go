@@ -0,0 +1,97 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// SafeModeDecisionMsg is sent when the user chooses whether to keep a
+// statement safe mode ran in a transaction
+type SafeModeDecisionMsg struct {
+	Commit bool
+}
+
+// SafeModePrompt asks whether to commit or roll back a statement that safe
+// mode left in an open transaction, showing how many rows it changed
+type SafeModePrompt struct {
+	Width int
+	Theme theme.Theme
+
+	SQL          string
+	RowsAffected int64
+}
+
+// NewSafeModePrompt creates a new safe mode prompt
+func NewSafeModePrompt(th theme.Theme) *SafeModePrompt {
+	return &SafeModePrompt{
+		Width: 70,
+		Theme: th,
+	}
+}
+
+// Open shows the prompt for sql, which changed rowsAffected rows
+func (p *SafeModePrompt) Open(sql string, rowsAffected int64) {
+	p.SQL = sql
+	p.RowsAffected = rowsAffected
+}
+
+// Update handles keyboard input. Only an explicit c or y commits; Esc
+// rolls back like n or r, so a stray key never keeps the changes.
+func (p *SafeModePrompt) Update(msg tea.KeyMsg) (*SafeModePrompt, tea.Cmd) {
+	switch msg.String() {
+	case "c", "y":
+		return p, func() tea.Msg { return SafeModeDecisionMsg{Commit: true} }
+	case "r", "n", "esc":
+		return p, func() tea.Msg { return SafeModeDecisionMsg{Commit: false} }
+	}
+	return p, nil
+}
+
+// Summary describes what the statement did
+func (p *SafeModePrompt) Summary() string {
+	if p.RowsAffected == 1 {
+		return "1 row affected"
+	}
+	return fmt.Sprintf("%d rows affected", p.RowsAffected)
+}
+
+// View renders the prompt
+func (p *SafeModePrompt) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(p.Theme.Background).
+		Background(p.Theme.Warning).
+		Padding(0, 1).
+		Bold(true)
+	countStyle := lipgloss.NewStyle().Foreground(p.Theme.Foreground).Bold(true)
+	sqlStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+	keyStyle := lipgloss.NewStyle().Foreground(p.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+
+	textWidth := p.Width - 4 // Border and padding
+	sqlLines := strings.Split(wrapText(strings.TrimSpace(p.SQL), textWidth), "\n")
+	if len(sqlLines) > 5 {
+		sqlLines = append(sqlLines[:4], "…")
+	}
+
+	sections := []string{
+		titleStyle.Render("Safe Mode"),
+		"",
+		sqlStyle.Render(strings.Join(sqlLines, "\n")),
+		"",
+		countStyle.Render(p.Summary()) + metaStyle.Render(" in an open transaction"),
+		"",
+		keyStyle.Render("c") + metaStyle.Render(": Commit   ") +
+			keyStyle.Render("r") + metaStyle.Render("/") + keyStyle.Render("Esc") + metaStyle.Render(": Roll back"),
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Theme.Warning).
+		Width(p.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
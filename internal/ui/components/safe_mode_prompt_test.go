@@ -0,0 +1,48 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestSafeModePrompt_Keys(t *testing.T) {
+	p := NewSafeModePrompt(theme.DefaultTheme())
+	p.Open("DELETE FROM sessions WHERE expires_at < now()", 42)
+
+	tests := []struct {
+		key  tea.KeyMsg
+		want SafeModeDecisionMsg
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")}, SafeModeDecisionMsg{Commit: true}},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}, SafeModeDecisionMsg{Commit: false}},
+		{tea.KeyMsg{Type: tea.KeyEsc}, SafeModeDecisionMsg{Commit: false}},
+	}
+	for _, tt := range tests {
+		_, cmd := p.Update(tt.key)
+		if cmd == nil {
+			t.Fatalf("%s returned no command", tt.key)
+		}
+		if got := cmd(); got != tt.want {
+			t.Errorf("%s sent %#v, want %#v", tt.key, got, tt.want)
+		}
+	}
+
+	// Other keys do nothing, so a slip can't commit
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("enter made a decision")
+	}
+}
+
+func TestSafeModePrompt_View(t *testing.T) {
+	p := NewSafeModePrompt(theme.DefaultTheme())
+	p.Open("UPDATE users SET active = false", 1)
+	if got := p.Summary(); got != "1 row affected" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if view := p.View(); !strings.Contains(view, "1 row affected") || !strings.Contains(view, "UPDATE users") {
+		t.Errorf("view lacks the count or statement:\n%s", view)
+	}
+}
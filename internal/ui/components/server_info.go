@@ -0,0 +1,126 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// CloseServerInfoMsg is sent when the server info panel should close
+type CloseServerInfoMsg struct{}
+
+// ServerInfoPanel shows the version and session settings of the server a
+// connection is on
+type ServerInfoPanel struct {
+	Width int
+	Theme theme.Theme
+
+	connection string // host:port the info is for
+	info       models.ServerInfo
+	loaded     bool
+	err        error
+}
+
+// NewServerInfoPanel creates a new server info panel
+func NewServerInfoPanel(th theme.Theme) *ServerInfoPanel {
+	return &ServerInfoPanel{
+		Width: 70,
+		Theme: th,
+	}
+}
+
+// Open shows the panel loading the info of connection
+func (p *ServerInfoPanel) Open(connection string) {
+	p.connection = connection
+	p.info = models.ServerInfo{}
+	p.loaded = false
+	p.err = nil
+}
+
+// SetInfo shows the loaded info, or why it could not be loaded
+func (p *ServerInfoPanel) SetInfo(info models.ServerInfo, err error) {
+	p.info = info
+	p.err = err
+	p.loaded = true
+}
+
+// Update handles keyboard input
+func (p *ServerInfoPanel) Update(msg tea.KeyMsg) (*ServerInfoPanel, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "enter":
+		return p, func() tea.Msg { return CloseServerInfoMsg{} }
+	}
+	return p, nil
+}
+
+// Fields returns the panel's label/value pairs in display order
+func (p *ServerInfoPanel) Fields(now time.Time) [][2]string {
+	info := p.info
+	user := info.User
+	if info.SessionUser != "" && info.SessionUser != info.User {
+		// SET ROLE or SECURITY DEFINER changed who we act as
+		user += " (session " + info.SessionUser + ")"
+	}
+	encoding := info.ServerEncoding
+	if info.ClientEncoding != "" && info.ClientEncoding != info.ServerEncoding {
+		encoding += " (client " + info.ClientEncoding + ")"
+	}
+	role := "primary"
+	if info.InRecovery {
+		role = "standby (read-only)"
+	}
+
+	fields := [][2]string{
+		{"Server", info.ServerVersion + ", " + role},
+		{"Connection", p.connection},
+		{"Database", info.Database},
+		{"User", user},
+		{"Encoding", encoding},
+		{"Time zone", info.TimeZone},
+	}
+	if !info.StartedAt.IsZero() {
+		fields = append(fields, [2]string{"Started", fmt.Sprintf("%s (up %s)",
+			info.StartedAt.Format("2006-01-02 15:04:05 MST"), FormatWait(now.Sub(info.StartedAt)))})
+	}
+	return fields
+}
+
+// View renders the panel
+func (p *ServerInfoPanel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(p.Theme.Foreground).
+		Background(p.Theme.Info).
+		Padding(0, 1).
+		Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata).Width(12)
+	valueStyle := lipgloss.NewStyle().Foreground(p.Theme.Foreground)
+	metaStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+
+	textWidth := p.Width - 4 // Border and padding
+	sections := []string{titleStyle.Render("Server Info"), ""}
+	switch {
+	case p.err != nil:
+		errorStyle := lipgloss.NewStyle().Foreground(p.Theme.Error)
+		sections = append(sections, errorStyle.Render(wrapText("Could not load server info: "+p.err.Error(), textWidth)))
+	case !p.loaded:
+		sections = append(sections, metaStyle.Render("Loading..."))
+	default:
+		for _, f := range p.Fields(time.Now()) {
+			sections = append(sections, labelStyle.Render(f[0])+valueStyle.Render(truncateToWidth(f[1], textWidth-12)))
+		}
+		sections = append(sections, "", metaStyle.Render(wrapText(p.info.Version, textWidth)))
+	}
+	sections = append(sections, "", metaStyle.Render("Esc: Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Theme.Border).
+		Width(p.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
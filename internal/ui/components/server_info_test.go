@@ -0,0 +1,67 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestServerInfoPanel_Fields(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := NewServerInfoPanel(theme.DefaultTheme())
+	p.Open("db.example.com:5432")
+	p.SetInfo(models.ServerInfo{
+		ServerVersion:  "16.2",
+		User:           "reporting",
+		SessionUser:    "alice",
+		Database:       "app",
+		ServerEncoding: "UTF8",
+		ClientEncoding: "UTF8",
+		TimeZone:       "Europe/Berlin",
+		StartedAt:      now.Add(-90 * time.Minute),
+		InRecovery:     true,
+	}, nil)
+
+	got := make(map[string]string)
+	for _, f := range p.Fields(now) {
+		got[f[0]] = f[1]
+	}
+	want := map[string]string{
+		"Server":     "16.2, standby (read-only)",
+		"Connection": "db.example.com:5432",
+		"User":       "reporting (session alice)",
+		"Encoding":   "UTF8",
+		"Time zone":  "Europe/Berlin",
+	}
+	for label, value := range want {
+		if got[label] != value {
+			t.Errorf("%s = %q, want %q", label, got[label], value)
+		}
+	}
+	if !strings.Contains(got["Started"], "up 1h30m") {
+		t.Errorf("Started = %q, want the uptime", got["Started"])
+	}
+}
+
+func TestServerInfoPanel_View(t *testing.T) {
+	p := NewServerInfoPanel(theme.DefaultTheme())
+	p.Open("localhost:5432")
+	if view := p.View(); !strings.Contains(view, "Loading...") {
+		t.Errorf("panel before loading = %q, want a loading note", view)
+	}
+
+	p.SetInfo(models.ServerInfo{}, errors.New("permission denied"))
+	if view := p.View(); !strings.Contains(view, "permission denied") {
+		t.Errorf("panel after a failure = %q, want the error", view)
+	}
+
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil || cmd() != (CloseServerInfoMsg{}) {
+		t.Error("esc did not close the panel")
+	}
+}
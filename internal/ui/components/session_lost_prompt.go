@@ -0,0 +1,126 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// SessionLostDecisionMsg is sent when the user answers the session lost
+// prompt
+type SessionLostDecisionMsg struct {
+	Reconnect bool
+}
+
+// SessionLostPrompt tells the user that a connection's session is gone,
+// because its backend was terminated or its database dropped, and offers
+// to connect again
+type SessionLostPrompt struct {
+	Width int
+	Theme theme.Theme
+
+	Connection string // The lost connection's ID
+	Database   string
+	Loss       connection.SessionLoss
+	Err        string
+	WasActive  bool // The active connection was lost, so its tree and tabs were closed
+}
+
+// NewSessionLostPrompt creates a new session lost prompt
+func NewSessionLostPrompt(th theme.Theme) *SessionLostPrompt {
+	return &SessionLostPrompt{
+		Width: 70,
+		Theme: th,
+	}
+}
+
+// Open shows the prompt for the lost connection
+func (p *SessionLostPrompt) Open(conn, database string, loss connection.SessionLoss, err error, wasActive bool) {
+	p.Connection = conn
+	p.Database = database
+	p.Loss = loss
+	p.Err = err.Error()
+	p.WasActive = wasActive
+}
+
+// Title names what happened
+func (p *SessionLostPrompt) Title() string {
+	if p.Loss == connection.SessionDatabaseGone {
+		return "Database Unavailable"
+	}
+	return "Session Terminated"
+}
+
+// Message explains what happened and what lazypg did about it
+func (p *SessionLostPrompt) Message() string {
+	var msg string
+	if p.Loss == connection.SessionDatabaseGone {
+		msg = fmt.Sprintf("Database %q on %s is no longer available; it may have been dropped.", p.Database, p.Connection)
+	} else {
+		msg = fmt.Sprintf("Your session on %s was terminated, by an administrator, a server restart or a timeout.", p.Connection)
+	}
+	if p.WasActive {
+		msg += " Its tree and open tables were closed; query results stay open."
+	}
+	return msg
+}
+
+// ReconnectLabel describes what Enter does: reconnecting, or connecting to
+// the postgres database when the database itself is gone
+func (p *SessionLostPrompt) ReconnectLabel() string {
+	if p.Loss == connection.SessionDatabaseGone {
+		return "Connect to postgres"
+	}
+	return "Reconnect"
+}
+
+// Update handles keyboard input
+func (p *SessionLostPrompt) Update(msg tea.KeyMsg) (*SessionLostPrompt, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "r":
+		return p, func() tea.Msg { return SessionLostDecisionMsg{Reconnect: true} }
+	case "esc", "q":
+		return p, func() tea.Msg { return SessionLostDecisionMsg{Reconnect: false} }
+	}
+	return p, nil
+}
+
+// View renders the prompt
+func (p *SessionLostPrompt) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(p.Theme.Background).
+		Background(p.Theme.Error).
+		Padding(0, 1).
+		Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(p.Theme.Foreground)
+	keyStyle := lipgloss.NewStyle().Foreground(p.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(p.Theme.Metadata)
+
+	textWidth := p.Width - 4 // Border and padding
+	errLines := strings.Split(wrapText(p.Err, textWidth), "\n")
+	if len(errLines) > 4 {
+		errLines = append(errLines[:3], "…")
+	}
+
+	sections := []string{
+		titleStyle.Render(p.Title()),
+		"",
+		textStyle.Render(wrapText(p.Message(), textWidth)),
+		"",
+		metaStyle.Render(strings.Join(errLines, "\n")),
+		"",
+		keyStyle.Render("Enter") + metaStyle.Render(": "+p.ReconnectLabel()+"   ") +
+			keyStyle.Render("Esc") + metaStyle.Render(": Close"),
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Theme.Error).
+		Width(p.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,48 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/db/connection"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestSessionLostPrompt(t *testing.T) {
+	p := NewSessionLostPrompt(theme.DefaultTheme())
+	p.Open("prod", "shop", connection.SessionTerminated, errors.New("FATAL: terminating connection due to administrator command (SQLSTATE 57P01)"), true)
+	if p.Title() != "Session Terminated" || p.ReconnectLabel() != "Reconnect" {
+		t.Errorf("Title() = %q, ReconnectLabel() = %q", p.Title(), p.ReconnectLabel())
+	}
+	if view := p.View(); !strings.Contains(view, "prod") || !strings.Contains(view, "57P01") {
+		t.Errorf("view lacks the connection or error:\n%s", view)
+	}
+
+	p.Open("prod", "shop", connection.SessionDatabaseGone, errors.New(`database "shop" does not exist (SQLSTATE 3D000)`), false)
+	if p.Title() != "Database Unavailable" || p.ReconnectLabel() != "Connect to postgres" {
+		t.Errorf("Title() = %q, ReconnectLabel() = %q", p.Title(), p.ReconnectLabel())
+	}
+	if strings.Contains(p.Message(), "closed") {
+		t.Errorf("Message() = %q, mentions closing tabs of an inactive connection", p.Message())
+	}
+
+	tests := []struct {
+		key  tea.KeyMsg
+		want SessionLostDecisionMsg
+	}{
+		{tea.KeyMsg{Type: tea.KeyEnter}, SessionLostDecisionMsg{Reconnect: true}},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}, SessionLostDecisionMsg{Reconnect: true}},
+		{tea.KeyMsg{Type: tea.KeyEsc}, SessionLostDecisionMsg{Reconnect: false}},
+	}
+	for _, tt := range tests {
+		_, cmd := p.Update(tt.key)
+		if cmd == nil {
+			t.Fatalf("%s returned no command", tt.key)
+		}
+		if got := cmd(); got != tt.want {
+			t.Errorf("%s sent %#v, want %#v", tt.key, got, tt.want)
+		}
+	}
+}
@@ -25,7 +25,19 @@ const (
 
 // ExecuteQueryMsg is sent when a query should be executed
 type ExecuteQueryMsg struct {
-	SQL string
+	SQL        string
+	QuickQuery bool // Typed in the SQL editor, so the quick query LIMIT applies
+
+	// ConnectionID runs the query on another open connection without
+	// making it the active one; empty runs it on the active connection
+	ConnectionID string
+}
+
+// GoToDefinitionMsg asks to open the object named under the cursor.
+// Schema is empty for unqualified names, which resolve via search_path.
+type GoToDefinitionMsg struct {
+	Schema string
+	Name   string
 }
 
 // OpenExternalEditorMsg requests opening an external editor
@@ -55,6 +67,15 @@ type SQLEditor struct {
 	heightPreset SQLEditorHeightPreset
 	Focused      bool // Whether the editor has focus
 
+	// Fullscreen editing covers the whole content area; wasExpanded is
+	// whether the editor was expanded before, to restore on exit
+	fullscreen  bool
+	wasExpanded bool
+
+	// First visible line while expanded, kept so the cursor stays in view
+	// as the editor is resized
+	topLine int
+
 	// Theme
 	Theme theme.Theme
 
@@ -85,6 +106,9 @@ func (e *SQLEditor) IsExpanded() bool {
 // Toggle expands or collapses the editor
 func (e *SQLEditor) Toggle() {
 	e.expanded = !e.expanded
+	if !e.expanded {
+		e.fullscreen = false
+	}
 }
 
 // Expand expands the editor
@@ -92,9 +116,36 @@ func (e *SQLEditor) Expand() {
 	e.expanded = true
 }
 
-// Collapse collapses the editor
+// Collapse collapses the editor, leaving fullscreen
 func (e *SQLEditor) Collapse() {
 	e.expanded = false
+	e.fullscreen = false
+}
+
+// IsFullscreen returns whether the editor covers the whole content area
+func (e *SQLEditor) IsFullscreen() bool {
+	return e.fullscreen
+}
+
+// ToggleFullscreen enters or leaves fullscreen editing. Entering expands
+// the editor; leaving restores it as it was, at its height preset.
+func (e *SQLEditor) ToggleFullscreen() {
+	if e.fullscreen {
+		e.ExitFullscreen()
+		return
+	}
+	e.wasExpanded = e.expanded
+	e.expanded = true
+	e.fullscreen = true
+}
+
+// ExitFullscreen leaves fullscreen editing, restoring the previous layout
+func (e *SQLEditor) ExitFullscreen() {
+	if !e.fullscreen {
+		return
+	}
+	e.fullscreen = false
+	e.expanded = e.wasExpanded
 }
 
 // GetHeightPreset returns the current height preset
@@ -116,6 +167,27 @@ func (e *SQLEditor) DecreaseHeight() {
 	}
 }
 
+// CycleHeight steps to the next height preset, from Large back to Small
+func (e *SQLEditor) CycleHeight() {
+	if e.heightPreset >= SQLEditorLarge {
+		e.heightPreset = SQLEditorSmall
+	} else {
+		e.heightPreset++
+	}
+}
+
+// HeightPresetLabel names the current height preset
+func (e *SQLEditor) HeightPresetLabel() string {
+	switch e.heightPreset {
+	case SQLEditorSmall:
+		return "small"
+	case SQLEditorLarge:
+		return "large"
+	default:
+		return "medium"
+	}
+}
+
 // GetHeightRatio returns the height ratio for the current preset
 func (e *SQLEditor) GetHeightRatio() float64 {
 	switch e.heightPreset {
@@ -286,6 +358,106 @@ func (e *SQLEditor) DeleteCharAfter() {
 	}
 }
 
+// isNameChar reports whether c can be part of an unquoted, possibly
+// qualified object name
+func isNameChar(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// ObjectNameAtCursor returns the object name under (or just before) the
+// cursor, split into schema and name. Unquoted parts are folded to lower
+// case and quoted parts kept as written, as PostgreSQL does; a database
+// qualifier is dropped. name is empty when the cursor isn't on a name.
+func (e *SQLEditor) ObjectNameAtCursor() (schema, name string) {
+	line := e.lines[e.cursorRow]
+
+	// Walk the line from the start so quoted names (which may contain
+	// spaces and dots) are kept whole
+	for i := 0; i < len(line); {
+		if !isNameChar(line[i]) && line[i] != '"' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && (isNameChar(line[i]) || line[i] == '"') {
+			if line[i] == '"' {
+				i++
+				for i < len(line) {
+					if line[i] == '"' {
+						if i+1 < len(line) && line[i+1] == '"' {
+							i += 2
+							continue
+						}
+						break
+					}
+					i++
+				}
+			}
+			i++
+		}
+		if i > len(line) {
+			i = len(line) // Unterminated quote
+		}
+		if e.cursorCol < start || e.cursorCol > i {
+			continue
+		}
+
+		word := line[start:i]
+		if unicode.IsDigit(rune(word[0])) {
+			return "", "" // A number, not a name
+		}
+		parts := splitQualifiedName(word)
+		switch len(parts) {
+		case 0:
+			return "", ""
+		case 1:
+			return "", parts[0]
+		default:
+			return parts[len(parts)-2], parts[len(parts)-1]
+		}
+	}
+	return "", ""
+}
+
+// splitQualifiedName splits a dotted name like public."MyTable" into its
+// parts, unquoting quoted parts and lower-casing the rest. Empty parts
+// (e.g. from a trailing dot) are dropped.
+func splitQualifiedName(s string) []string {
+	var parts []string
+	var current strings.Builder
+	quoted, wasQuoted := false, false
+
+	flush := func() {
+		part := current.String()
+		if !wasQuoted {
+			part = strings.ToLower(part)
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+		current.Reset()
+		wasQuoted = false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+			current.WriteByte('"') // Escaped quote inside a quoted name
+			i++
+		case c == '"':
+			quoted = !quoted
+			wasQuoted = true
+		case c == '.' && !quoted:
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return parts
+}
+
 // SQL keywords for syntax highlighting
 var sqlKeywords = map[string]bool{
 	"SELECT": true, "FROM": true, "WHERE": true, "AND": true, "OR": true,
@@ -449,10 +621,9 @@ func (e *SQLEditor) View() string {
 	var startLine int
 
 	if e.expanded {
-		// Show all lines that fit, scroll if needed
-		if e.cursorRow >= contentHeight {
-			startLine = e.cursorRow - contentHeight + 1
-		}
+		// Show all lines that fit, scrolling only as far as needed to keep
+		// the cursor in view, also after the height changed
+		startLine = e.scrollToCursor(contentHeight)
 		endLine := startLine + contentHeight
 		if endLine > len(e.lines) {
 			endLine = len(e.lines)
@@ -498,6 +669,19 @@ func (e *SQLEditor) View() string {
 	return zone.Mark(ZoneSQLEditor, containerStyle.Render(content))
 }
 
+// scrollToCursor moves the first visible line just enough for the cursor
+// to show in height lines, and returns it
+func (e *SQLEditor) scrollToCursor(height int) int {
+	if e.cursorRow < e.topLine {
+		e.topLine = e.cursorRow
+	} else if e.cursorRow >= e.topLine+height {
+		e.topLine = e.cursorRow - height + 1
+	}
+	// Don't leave empty lines below the text when it would fit
+	e.topLine = max(0, min(e.topLine, len(e.lines)-height))
+	return e.topLine
+}
+
 // renderLine renders a single line with line number and syntax highlighting
 func (e *SQLEditor) renderLine(lineNum int, hasCursor bool) string {
 	// Line number
@@ -639,7 +823,15 @@ func (e *SQLEditor) Update(msg tea.KeyMsg) (*SQLEditor, tea.Cmd) {
 		if sql != "" {
 			e.AddToHistory(e.GetContent())
 			return e, func() tea.Msg {
-				return ExecuteQueryMsg{SQL: sql}
+				return ExecuteQueryMsg{SQL: sql, QuickQuery: true}
+			}
+		}
+
+	// Go to definition of the object under the cursor
+	case "f12", "ctrl+]":
+		if schema, name := e.ObjectNameAtCursor(); name != "" {
+			return e, func() tea.Msg {
+				return GoToDefinitionMsg{Schema: schema, Name: name}
 			}
 		}
 
@@ -779,3 +971,216 @@ func splitStatements(content string) []string {
 
 	return statements
 }
+
+// QuickQueryBypassPrefix runs a query from the SQL editor without the quick
+// query LIMIT, e.g. "!SELECT * FROM events"
+const QuickQueryBypassPrefix = "!"
+
+// ApplyQuickQueryLimit appends "LIMIT limit" to a bare SELECT typed in the
+// SQL editor. It returns the SQL to run and the limit it applied (0 if the
+// query was left alone). A leading QuickQueryBypassPrefix is stripped and
+// skips the limit. Queries that already limit their rows at the top level,
+// SELECT ... INTO, and multiple statements are never changed.
+func ApplyQuickQueryLimit(sql string, limit int) (string, int) {
+	sql = strings.TrimSpace(sql)
+	if strings.HasPrefix(sql, QuickQueryBypassPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(sql, QuickQueryBypassPrefix)), 0
+	}
+	if limit <= 0 {
+		return sql, 0
+	}
+
+	// Work on a copy with literals and comments blanked out so keywords
+	// inside them are ignored, then drop trailing semicolons and comments
+	masked := maskSQLLiterals(sql)
+	end := len(strings.TrimRight(masked, " \t\r\n;"))
+	masked = masked[:end]
+	if strings.Contains(masked, ";") {
+		return sql, 0
+	}
+
+	words := topLevelWords(masked)
+	if len(words) == 0 || words[0] != "SELECT" {
+		return sql, 0
+	}
+	for _, w := range words {
+		if w == "LIMIT" || w == "FETCH" || w == "INTO" {
+			return sql, 0
+		}
+	}
+
+	return fmt.Sprintf("%s LIMIT %d", sql[:end], limit), limit
+}
+
+// IsDataModifying reports whether sql is a single INSERT, UPDATE, DELETE or
+// MERGE, including one inside a WITH query. Safe mode runs these in a
+// transaction it can roll back. Scripts of several statements are not
+// included, since they may end the transaction themselves.
+func IsDataModifying(sql string) bool {
+	masked := maskSQLLiterals(strings.TrimSpace(sql))
+	masked = strings.TrimRight(masked, " \t\r\n;")
+	if strings.Contains(masked, ";") {
+		return false
+	}
+
+	words := strings.FieldsFunc(strings.ToUpper(masked), func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 || (words[0] != "WITH" && !dataModifyingKeywords[words[0]]) {
+		return false
+	}
+	for i, w := range words {
+		if !dataModifyingKeywords[w] {
+			continue
+		}
+		// SELECT ... FOR UPDATE and FOR NO KEY UPDATE only lock rows
+		if w == "UPDATE" && i > 0 && (words[i-1] == "FOR" || words[i-1] == "KEY") {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+var dataModifyingKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true,
+}
+
+// QueryNextPageKey loads the next page of a query result that filled its
+// quick query LIMIT
+const QueryNextPageKey = ">"
+
+// QuickQueryPageSQL returns the query for a later page of a result whose SQL
+// ApplyQuickQueryLimit limited: the same query with "LIMIT limit OFFSET
+// offset" in place of the LIMIT, so its ORDER BY still applies. ok is false
+// if sql doesn't end in that LIMIT.
+func QuickQueryPageSQL(sql string, limit, offset int) (pageSQL string, ok bool) {
+	suffix := fmt.Sprintf(" LIMIT %d", limit)
+	if limit <= 0 || !strings.HasSuffix(sql, suffix) {
+		return "", false
+	}
+	return fmt.Sprintf("%s LIMIT %d OFFSET %d", strings.TrimSuffix(sql, suffix), limit, offset), true
+}
+
+// HasTopLevelOrderBy reports whether a query orders its rows, without which
+// LIMIT/OFFSET pages may repeat or skip rows
+func HasTopLevelOrderBy(sql string) bool {
+	words := topLevelWords(maskSQLLiterals(sql))
+	for i := 0; i+1 < len(words); i++ {
+		if words[i] == "ORDER" && words[i+1] == "BY" {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSQLLiterals replaces the contents of string literals, quoted
+// identifiers, dollar-quoted strings and comments with spaces, keeping byte
+// offsets intact
+func maskSQLLiterals(sql string) string {
+	out := []byte(sql)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(out); i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\'' || sql[i] == '"':
+			quote := sql[i]
+			j := i + 1
+			for j < len(sql) {
+				if sql[j] == quote {
+					// Doubled quotes are escapes
+					if j+1 < len(sql) && sql[j+1] == quote {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			blank(i, j+1)
+			i = j
+		case strings.HasPrefix(sql[i:], "--"):
+			j := strings.IndexByte(sql[i:], '\n')
+			if j < 0 {
+				j = len(sql) - i
+			}
+			blank(i, i+j)
+			i += j
+		case strings.HasPrefix(sql[i:], "/*"):
+			j := strings.Index(sql[i+2:], "*/")
+			if j < 0 {
+				j = len(sql) - i - 2
+			} else {
+				j += 2
+			}
+			blank(i, i+2+j)
+			i += 1 + j
+		case sql[i] == '$':
+			// Dollar quote: $$...$$ or $tag$...$tag$
+			tagEnd := strings.IndexByte(sql[i+1:], '$')
+			if tagEnd < 0 || !isDollarTag(sql[i+1:i+1+tagEnd]) {
+				continue
+			}
+			tag := sql[i : i+2+tagEnd]
+			j := strings.Index(sql[i+len(tag):], tag)
+			if j < 0 {
+				j = len(sql) - i - len(tag)
+			} else {
+				j += len(tag)
+			}
+			blank(i, i+len(tag)+j)
+			i += len(tag) + j - 1
+		}
+	}
+	return string(out)
+}
+
+// isDollarTag reports whether s can be the tag of a dollar-quoted string
+// (empty, or an identifier that doesn't start with a digit)
+func isDollarTag(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// topLevelWords returns the upper-cased words of masked SQL that are not
+// inside parentheses
+func topLevelWords(masked string) []string {
+	var words []string
+	var current strings.Builder
+	depth := 0
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, strings.ToUpper(current.String()))
+			current.Reset()
+		}
+	}
+
+	for _, r := range masked {
+		switch {
+		case r == '(':
+			flush()
+			depth++
+		case r == ')':
+			flush()
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && (r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)):
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,206 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestApplyQuickQueryLimit(t *testing.T) {
+	tests := []struct {
+		sql       string
+		wantSQL   string
+		wantLimit int
+	}{
+		{"SELECT * FROM users", "SELECT * FROM users LIMIT 100", 100},
+		{"select id from users;  ", "select id from users LIMIT 100", 100},
+		{"SELECT * FROM users -- all of them", "SELECT * FROM users LIMIT 100", 100},
+		{"-- active\nSELECT * FROM users WHERE active", "-- active\nSELECT * FROM users WHERE active LIMIT 100", 100},
+		{"SELECT * FROM users LIMIT 5", "SELECT * FROM users LIMIT 5", 0},
+		{"SELECT * FROM users FETCH FIRST 5 ROWS ONLY", "SELECT * FROM users FETCH FIRST 5 ROWS ONLY", 0},
+		{"SELECT * INTO backup FROM users", "SELECT * INTO backup FROM users", 0},
+		// Keywords in literals, identifiers, comments and subqueries don't count
+		{"SELECT 'no limit' FROM users", "SELECT 'no limit' FROM users LIMIT 100", 100},
+		{`SELECT "limit" FROM t`, `SELECT "limit" FROM t LIMIT 100`, 100},
+		{"SELECT $$ limit ; $$ FROM t", "SELECT $$ limit ; $$ FROM t LIMIT 100", 100},
+		{"SELECT /* limit */ 1", "SELECT /* limit */ 1 LIMIT 100", 100},
+		{"SELECT * FROM (SELECT * FROM t LIMIT 5) s", "SELECT * FROM (SELECT * FROM t LIMIT 5) s LIMIT 100", 100},
+		{"SELECT 'a;b' FROM t", "SELECT 'a;b' FROM t LIMIT 100", 100},
+		// Only bare, single SELECTs are limited
+		{"UPDATE users SET active = false", "UPDATE users SET active = false", 0},
+		{"WITH x AS (SELECT 1) SELECT * FROM x", "WITH x AS (SELECT 1) SELECT * FROM x", 0},
+		{"SELECT 1; SELECT 2", "SELECT 1; SELECT 2", 0},
+		// The bypass prefix runs the query as typed
+		{"!SELECT * FROM events", "SELECT * FROM events", 0},
+		{"  ! SELECT * FROM events", "SELECT * FROM events", 0},
+	}
+
+	for _, tt := range tests {
+		gotSQL, gotLimit := ApplyQuickQueryLimit(tt.sql, 100)
+		if gotSQL != tt.wantSQL || gotLimit != tt.wantLimit {
+			t.Errorf("ApplyQuickQueryLimit(%q) = (%q, %d), want (%q, %d)", tt.sql, gotSQL, gotLimit, tt.wantSQL, tt.wantLimit)
+		}
+	}
+}
+
+func TestApplyQuickQueryLimitDisabled(t *testing.T) {
+	if sql, limit := ApplyQuickQueryLimit("SELECT * FROM users", 0); sql != "SELECT * FROM users" || limit != 0 {
+		t.Errorf("limit 0 should leave the query alone, got (%q, %d)", sql, limit)
+	}
+	if sql, _ := ApplyQuickQueryLimit("!SELECT 1", 0); sql != "SELECT 1" {
+		t.Errorf("bypass prefix should be stripped even when disabled, got %q", sql)
+	}
+}
+
+func TestIsDataModifying(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"UPDATE users SET active = false WHERE id = 1;", true},
+		{"delete from sessions", true},
+		{"INSERT INTO t VALUES (1) ON CONFLICT DO NOTHING", true},
+		{"WITH gone AS (DELETE FROM t RETURNING id) SELECT count(*) FROM gone", true},
+		{"SELECT * FROM jobs FOR UPDATE SKIP LOCKED", false},
+		{"WITH j AS (SELECT id FROM jobs) SELECT * FROM j FOR NO KEY UPDATE", false},
+		{"SELECT 'delete me'", false},
+		{"UPDATE a SET x = 1; UPDATE b SET y = 2", false},
+		{"CREATE TABLE updates (id int)", false},
+	}
+	for _, tt := range tests {
+		if got := IsDataModifying(tt.sql); got != tt.want {
+			t.Errorf("IsDataModifying(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestQuickQueryPageSQL(t *testing.T) {
+	sql, limit := ApplyQuickQueryLimit("SELECT * FROM events ORDER BY id;", 100)
+	got, ok := QuickQueryPageSQL(sql, limit, 200)
+	if want := "SELECT * FROM events ORDER BY id LIMIT 100 OFFSET 200"; !ok || got != want {
+		t.Errorf("QuickQueryPageSQL() = (%q, %v), want %q", got, ok, want)
+	}
+	if _, ok := QuickQueryPageSQL("SELECT * FROM events LIMIT 5", 100, 100); ok {
+		t.Error("QuickQueryPageSQL() should refuse SQL without the applied LIMIT")
+	}
+
+	if !HasTopLevelOrderBy(got) {
+		t.Errorf("HasTopLevelOrderBy(%q) = false", got)
+	}
+	for _, sql := range []string{
+		"SELECT row_number() OVER (ORDER BY id) FROM events LIMIT 100",
+		"SELECT 'order by' FROM events -- order by id",
+	} {
+		if HasTopLevelOrderBy(sql) {
+			t.Errorf("HasTopLevelOrderBy(%q) = true", sql)
+		}
+	}
+}
+
+func TestObjectNameAtCursor(t *testing.T) {
+	tests := []struct {
+		line       string
+		col        int
+		wantSchema string
+		wantName   string
+	}{
+		{"SELECT * FROM public.users u", 16, "public", "users"},
+		{"SELECT * FROM public.users u", 26, "public", "users"}, // Just past the end
+		{"SELECT * FROM Users", 15, "", "users"},
+		{`SELECT * FROM "Sales"."Order Items"`, 16, "Sales", "Order Items"},
+		{`SELECT * FROM "a""b"`, 16, "", `a"b`},
+		{`SELECT * FROM "Sales"."Orders"`, 20, "Sales", "Orders"},
+		{"SELECT * FROM mydb.public.users", 20, "public", "users"},
+		{"SELECT 1 + 2", 9, "", ""},
+		{"SELECT 42", 9, "", ""},
+	}
+	for _, tt := range tests {
+		e := NewSQLEditor(theme.DefaultTheme())
+		e.SetContent(tt.line)
+		e.cursorCol = tt.col
+		schema, name := e.ObjectNameAtCursor()
+		if schema != tt.wantSchema || name != tt.wantName {
+			t.Errorf("%q at %d = (%q, %q), want (%q, %q)", tt.line, tt.col, schema, name, tt.wantSchema, tt.wantName)
+		}
+	}
+}
+
+func TestSQLEditor_CycleHeight(t *testing.T) {
+	e := NewSQLEditor(theme.DefaultTheme())
+	var got []string
+	for range 4 {
+		e.CycleHeight()
+		got = append(got, e.HeightPresetLabel())
+	}
+	if want := []string{"large", "small", "medium", "large"}; !slices.Equal(got, want) {
+		t.Errorf("CycleHeight() went %v, want %v", got, want)
+	}
+}
+
+func TestSQLEditor_Fullscreen(t *testing.T) {
+	e := NewSQLEditor(theme.DefaultTheme())
+	e.IncreaseHeight()
+
+	// Leaving fullscreen restores a collapsed editor at its preset
+	e.ToggleFullscreen()
+	if !e.IsFullscreen() || !e.IsExpanded() {
+		t.Fatal("ToggleFullscreen() didn't expand the editor to fullscreen")
+	}
+	e.ToggleFullscreen()
+	if e.IsFullscreen() || e.IsExpanded() || e.GetHeightPreset() != SQLEditorLarge {
+		t.Errorf("after fullscreen: fullscreen %v, expanded %v, preset %v, want the collapsed large editor back",
+			e.IsFullscreen(), e.IsExpanded(), e.GetHeightPreset())
+	}
+
+	// Collapsing ends fullscreen too
+	e.Expand()
+	e.ToggleFullscreen()
+	e.Collapse()
+	if e.IsFullscreen() {
+		t.Error("Collapse() left the editor fullscreen")
+	}
+	e.ExitFullscreen()
+	if e.IsExpanded() {
+		t.Error("ExitFullscreen() outside fullscreen changed the layout")
+	}
+}
+
+func TestSQLEditor_CursorVisibleOnResize(t *testing.T) {
+	e := NewSQLEditor(theme.DefaultTheme())
+	e.Expand()
+	e.Width = 60
+	var lines []string
+	for i := range 30 {
+		lines = append(lines, fmt.Sprintf("SELECT %d;", i))
+	}
+	e.SetContent(strings.Join(lines, "\n"))
+	e.cursorRow = 25
+
+	// Tall enough for the whole text, nothing scrolls
+	e.Height = 40
+	e.View()
+	if e.topLine != 0 {
+		t.Errorf("topLine = %d with room for every line, want 0", e.topLine)
+	}
+
+	// Shrinking scrolls just enough to keep the cursor's line on screen
+	for _, height := range []int{12, 7, 3} {
+		e.Height = height
+		if view := e.View(); !strings.Contains(view, "SELECT 25;") {
+			t.Errorf("height %d: the cursor's line is not in view:\n%s", height, view)
+		}
+	}
+
+	// Moving up within the screen doesn't scroll
+	e.Height = 12
+	e.View()
+	top := e.topLine
+	e.MoveCursorUp()
+	e.View()
+	if e.topLine != top {
+		t.Errorf("topLine = %d after moving up on screen, want it kept at %d", e.topLine, top)
+	}
+}
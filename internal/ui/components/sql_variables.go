@@ -0,0 +1,246 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SQLVariables holds psql-style session variables. They are set with
+// \set name value and referenced in queries as :name (inserted as-is),
+// :'name' (as a string literal) or :"name" (as an identifier). They are
+// expanded client-side before the query is sent, so they are unrelated to
+// server-side bind parameters like $1, which are never touched.
+type SQLVariables struct {
+	vars map[string]string
+}
+
+// NewSQLVariables creates an empty variable store
+func NewSQLVariables() *SQLVariables {
+	return &SQLVariables{vars: make(map[string]string)}
+}
+
+// Set defines or replaces a variable
+func (v *SQLVariables) Set(name, value string) error {
+	if !isVariableName(name) {
+		return fmt.Errorf("invalid variable name %q: use letters, digits and underscores", name)
+	}
+	v.vars[name] = value
+	return nil
+}
+
+// Unset removes a variable
+func (v *SQLVariables) Unset(name string) {
+	delete(v.vars, name)
+}
+
+// Get returns a variable's value
+func (v *SQLVariables) Get(name string) (string, bool) {
+	value, ok := v.vars[name]
+	return value, ok
+}
+
+// Names returns the defined variable names, sorted
+func (v *SQLVariables) Names() []string {
+	names := make([]string, 0, len(v.vars))
+	for name := range v.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe lists the variables one per line as name = 'value'
+func (v *SQLVariables) Describe() string {
+	if len(v.vars) == 0 {
+		return "No variables set.\n\nDefine one in the SQL editor with \\set name value."
+	}
+	var lines []string
+	for _, name := range v.Names() {
+		lines = append(lines, fmt.Sprintf("%s = %s", name, quoteSQLLiteral(v.vars[name])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RunMetaCommands runs the \set and \unset lines at the start of sql and
+// returns the SQL that follows them. listed is true when a bare \set asked
+// for the variable list. Other backslash commands are rejected.
+func (v *SQLVariables) RunMetaCommands(sql string) (rest string, listed bool, err error) {
+	lines := strings.Split(sql, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, `\`) {
+			return strings.Join(lines[i:], "\n"), listed, nil
+		}
+
+		command, args := trimmed, ""
+		if j := strings.IndexFunc(trimmed, unicode.IsSpace); j >= 0 {
+			command, args = trimmed[:j], strings.TrimSpace(trimmed[j:])
+		}
+		switch command {
+		case `\set`:
+			if args == "" {
+				listed = true
+				continue
+			}
+			name, value := args, ""
+			if j := strings.IndexFunc(args, unicode.IsSpace); j >= 0 {
+				name, value = args[:j], args[j:]
+			}
+			parsed, err := parseSetValue(value)
+			if err != nil {
+				return "", false, err
+			}
+			if err := v.Set(name, parsed); err != nil {
+				return "", false, err
+			}
+		case `\unset`:
+			if args == "" {
+				return "", false, fmt.Errorf(`\unset: missing variable name`)
+			}
+			v.Unset(args)
+		default:
+			return "", false, fmt.Errorf(`unsupported command %s: only \set and \unset are supported`, command)
+		}
+	}
+	return "", listed, nil
+}
+
+// parseSetValue joins the arguments of \set into one value, as psql does:
+// 'quoted' parts are unquoted (a doubled single quote stands for one quote)
+// and the rest is taken as-is, with the whitespace between arguments dropped
+func parseSetValue(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			continue
+		case c == '\'':
+			closed := false
+			for i++; i < len(s); i++ {
+				if s[i] == '\'' {
+					if i+1 < len(s) && s[i+1] == '\'' {
+						b.WriteByte('\'')
+						i++
+						continue
+					}
+					closed = true
+					break
+				}
+				b.WriteByte(s[i])
+			}
+			if !closed {
+				return "", fmt.Errorf(`\set: unterminated quoted string`)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// Substitute expands :name, :'name' and :"name" references. Strings,
+// quoted identifiers, comments and dollar-quoted bodies are left alone, as
+// are :: casts and references to undefined variables (e.g. array slices
+// like arr[lo:hi]), matching psql.
+func (v *SQLVariables) Substitute(sql string) string {
+	if len(v.vars) == 0 || !strings.Contains(sql, ":") {
+		return sql
+	}
+
+	masked := maskSQLLiterals(sql)
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(sql); i++ {
+		if masked[i] != ':' {
+			continue
+		}
+		if (i > 0 && sql[i-1] == ':') || (i+1 < len(sql) && sql[i+1] == ':') {
+			continue // Cast
+		}
+
+		end, replacement, ok := v.expandAt(sql, i)
+		if !ok {
+			continue
+		}
+		b.WriteString(sql[last:i])
+		b.WriteString(replacement)
+		last = end
+		i = end - 1
+	}
+	if last == 0 {
+		return sql
+	}
+	b.WriteString(sql[last:])
+	return b.String()
+}
+
+// expandAt expands the reference starting at the colon at i, returning the
+// end of the reference and its replacement
+func (v *SQLVariables) expandAt(sql string, i int) (end int, replacement string, ok bool) {
+	start := i + 1
+	if start >= len(sql) {
+		return 0, "", false
+	}
+
+	quote := sql[start]
+	if quote == '\'' || quote == '"' {
+		j := start + 1
+		for j < len(sql) && isVariableChar(sql[j]) {
+			j++
+		}
+		if j >= len(sql) || sql[j] != quote {
+			return 0, "", false
+		}
+		value, defined := v.vars[sql[start+1:j]]
+		if !defined || j == start+1 {
+			return 0, "", false
+		}
+		if quote == '\'' {
+			return j + 1, quoteSQLLiteral(value), true
+		}
+		return j + 1, `"` + strings.ReplaceAll(value, `"`, `""`) + `"`, true
+	}
+
+	// A leading digit would be an array slice bound, not a name
+	if unicode.IsDigit(rune(quote)) {
+		return 0, "", false
+	}
+	j := start
+	for j < len(sql) && isVariableChar(sql[j]) {
+		j++
+	}
+	value, defined := v.vars[sql[start:j]]
+	if !defined || j == start {
+		return 0, "", false
+	}
+	return j, value, true
+}
+
+// quoteSQLLiteral quotes a value as a SQL string literal
+func quoteSQLLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// isVariableChar reports whether c can appear in a variable name
+func isVariableChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isVariableName reports whether name is a valid variable name
+func isVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isVariableChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
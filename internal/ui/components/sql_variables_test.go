@@ -0,0 +1,67 @@
+package components
+
+import "testing"
+
+func TestSQLVariablesSubstitute(t *testing.T) {
+	v := NewSQLVariables()
+	_ = v.Set("region", "us-east")
+	_ = v.Set("tbl", "Order Items")
+	_ = v.Set("n", "10")
+	_ = v.Set("quote", "it's")
+
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT * FROM t LIMIT :n", "SELECT * FROM t LIMIT 10"},
+		{"WHERE region = :'region'", "WHERE region = 'us-east'"},
+		{`SELECT * FROM :"tbl"`, `SELECT * FROM "Order Items"`},
+		{"WHERE note = :'quote'", "WHERE note = 'it''s'"},
+		{"SELECT id::text, :n", "SELECT id::text, 10"},
+		{"SELECT ':n', \":n\" -- :n", "SELECT ':n', \":n\" -- :n"},
+		{"SELECT $$ :n $$, $1", "SELECT $$ :n $$, $1"},
+		{"SELECT :undefined, :'undefined'", "SELECT :undefined, :'undefined'"},
+		{"SELECT arr[1:2]", "SELECT arr[1:2]"},
+		{"SELECT f(x := 1)", "SELECT f(x := 1)"},
+	}
+	for _, tt := range tests {
+		if got := v.Substitute(tt.sql); got != tt.want {
+			t.Errorf("Substitute(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestSQLVariablesRunMetaCommands(t *testing.T) {
+	v := NewSQLVariables()
+
+	rest, listed, err := v.RunMetaCommands("\\set region 'us-east'\n\\set n 1 0\nSELECT :'region'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest != "SELECT :'region'" || listed {
+		t.Errorf("rest = %q, listed = %v", rest, listed)
+	}
+	if got, _ := v.Get("region"); got != "us-east" {
+		t.Errorf("region = %q, want us-east", got)
+	}
+	if got, _ := v.Get("n"); got != "10" {
+		t.Errorf("n = %q, want 10 (arguments are concatenated)", got)
+	}
+
+	if _, listed, _ := v.RunMetaCommands(`\set`); !listed {
+		t.Error("expected a bare \\set to list variables")
+	}
+
+	if _, _, err := v.RunMetaCommands(`\unset region`); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.Get("region"); ok {
+		t.Error("expected region to be unset")
+	}
+
+	for _, bad := range []string{`\d users`, `\set bad-name 1`, `\set x 'open`} {
+		if _, _, err := v.RunMetaCommands(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
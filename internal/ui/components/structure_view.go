@@ -20,6 +20,24 @@ const (
 	ZoneStructureTabPrefix = "structure-tab-"
 )
 
+// ColumnOrderKey switches the Columns sub-tab between logical and storage
+// order
+const ColumnOrderKey = "O"
+
+// ColumnSequenceKey on the Columns sub-tab jumps to the sequence the
+// selected column takes its values from
+const ColumnSequenceKey = "enter"
+
+// Structure sub-tab indexes
+const (
+	StructureTabData = iota
+	StructureTabColumns
+	StructureTabConstraints
+	StructureTabIndexes
+
+	structureTabCount
+)
+
 // StructureView is a tabbed container for viewing table structure
 type StructureView struct {
 	Width  int
@@ -40,20 +58,32 @@ type StructureView struct {
 	constraintsData []models.Constraint
 	indexesData     []models.IndexInfo
 
+	// Columns sub-tab ordering. Storage order numbers columns by attnum and
+	// shows the slots dropped columns still take up; columnRows maps each
+	// row to its columnsData index, or -1 for a dropped slot.
+	columnsStorageOrder bool
+	columnRows          []int
+
 	// Table info
 	schema string
 	table  string
 	pool   *connection.Pool
 
 	// Status
-	loading        bool
-	metadataLoaded bool // Whether columns/constraints/indexes have been loaded
-	errorMessage   string
+	loading      bool // SetTable is loading every section at once
+	errorMessage string
+
+	// Per-section lazy loading, indexed by sub-tab (Data is unused). A
+	// section is queried the first time it is shown and never again once
+	// loaded; a failed load is retried the next time it is shown.
+	sectionLoaded  [structureTabCount]bool
+	sectionLoading [structureTabCount]bool
+	sectionErrors  [structureTabCount]string
 }
 
 // NewStructureView creates a new structure view
 func NewStructureView(th theme.Theme, tableView *TableView) *StructureView {
-	return &StructureView{
+	sv := &StructureView{
 		Theme:            th,
 		activeTab:        0, // Start with Data tab
 		tableView:        tableView,
@@ -61,6 +91,27 @@ func NewStructureView(th theme.Theme, tableView *TableView) *StructureView {
 		constraintsTable: NewTableView(th),
 		indexesTable:     NewTableView(th),
 	}
+
+	// Preview the whole constraint rather than the truncated cell
+	sv.constraintsTable.PreviewContent = func(row int) (string, string) {
+		if row < 0 || row >= len(sv.constraintsData) {
+			return "", ""
+		}
+		con := sv.constraintsData[row]
+		return FormatConstraintDetail(con), con.Name
+	}
+	sv.indexesTable.PreviewContent = func(row int) (string, string) {
+		if row < 0 || row >= len(sv.indexesData) {
+			return "", ""
+		}
+		idx := sv.indexesData[row]
+		return FormatIndexDetail(idx), idx.Name
+	}
+
+	// Definitions are what these rows are for, so a click shows them whole
+	sv.constraintsTable.PreviewOnClick = true
+	sv.indexesTable.PreviewOnClick = true
+	return sv
 }
 
 // HasTableLoaded checks if structure data has been loaded for the given table
@@ -106,15 +157,24 @@ func (sv *StructureView) SetTable(ctx context.Context, pool *connection.Pool, sc
 	sv.indexesData = indexes
 	sv.setIndexesTableData(indexes)
 
+	for tab := StructureTabColumns; tab < structureTabCount; tab++ {
+		sv.sectionLoaded[tab] = true
+	}
 	sv.loading = false
 	return nil
 }
 
-// setColumnsTableData converts column details to TableView format
+// setColumnsTableData converts column details to TableView format, in
+// logical or storage order
 func (sv *StructureView) setColumnsTableData(columns []models.ColumnDetail) {
-	headers := []string{"Name", "Type", "Nullable", "Default", "Constraints", "Comment"}
-	rows := make([][]string, len(columns))
+	headers := []string{"#", "Name", "Type", "Nullable", "Default", "Constraints", "Comment"}
+	if sv.columnsStorageOrder {
+		headers = []string{"attnum", "Name", "Type", "Storage", "Nullable", "Default", "Constraints", "Comment"}
+	}
+	rows := make([][]string, 0, len(columns))
+	sv.columnRows = make([]int, 0, len(columns))
 
+	lastAttNum := 0
 	for i, col := range columns {
 		// Format constraint markers
 		constraints := sv.formatColumnConstraints(col)
@@ -123,19 +183,84 @@ func (sv *StructureView) setColumnsTableData(columns []models.ColumnDetail) {
 			nullable = "YES"
 		}
 
-		rows[i] = []string{
+		if !sv.columnsStorageOrder {
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", col.Position),
+				col.Name,
+				col.DataType,
+				nullable,
+				col.DefaultValue,
+				constraints,
+				col.Comment,
+			})
+			sv.columnRows = append(sv.columnRows, i)
+			continue
+		}
+
+		if gap := col.AttNum - lastAttNum - 1; gap > 0 {
+			// Dropped columns keep their attnum, and old rows their bytes
+			slots := fmt.Sprintf("%d", lastAttNum+1)
+			name := "(dropped column)"
+			if gap > 1 {
+				slots = fmt.Sprintf("%d-%d", lastAttNum+1, col.AttNum-1)
+				name = fmt.Sprintf("(%d dropped columns)", gap)
+			}
+			rows = append(rows, []string{slots, name, "", "", "", "", "", ""})
+			sv.columnRows = append(sv.columnRows, -1)
+		}
+		lastAttNum = col.AttNum
+
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", col.AttNum),
 			col.Name,
 			col.DataType,
+			formatColumnStorage(col.StorageLength, col.StorageAlign),
 			nullable,
 			col.DefaultValue,
 			constraints,
 			col.Comment,
-		}
+		})
+		sv.columnRows = append(sv.columnRows, i)
 	}
 
 	sv.columnsTable.SetData(headers, rows, len(rows))
 }
 
+// ToggleColumnOrder switches the Columns sub-tab between logical and
+// storage order and reports whether it now shows storage order
+func (sv *StructureView) ToggleColumnOrder() bool {
+	sv.columnsStorageOrder = !sv.columnsStorageOrder
+	sv.setColumnsTableData(sv.columnsData)
+	return sv.columnsStorageOrder
+}
+
+// ColumnsInStorageOrder reports whether the Columns sub-tab is in storage
+// order
+func (sv *StructureView) ColumnsInStorageOrder() bool {
+	return sv.columnsStorageOrder
+}
+
+// formatColumnStorage describes how a column's values are laid out on disk,
+// from pg_attribute's attlen and attalign
+func formatColumnStorage(length int, align string) string {
+	alignBytes := map[string]string{"c": "1", "s": "2", "i": "4", "d": "8"}[align]
+	var size string
+	switch {
+	case length > 0:
+		size = fmt.Sprintf("%d bytes", length)
+	case length == -1:
+		size = "variable"
+	case length == -2:
+		size = "cstring"
+	default:
+		return "-"
+	}
+	if alignBytes == "" {
+		return size
+	}
+	return size + ", align " + alignBytes
+}
+
 func (sv *StructureView) formatColumnConstraints(col models.ColumnDetail) string {
 	markers := []string{}
 	if col.IsPrimaryKey {
@@ -150,6 +275,16 @@ func (sv *StructureView) formatColumnConstraints(col models.ColumnDetail) string
 	if col.HasCheck {
 		markers = append(markers, "CK")
 	}
+	// Columns Postgres fills in itself
+	if col.Identity != "" {
+		markers = append(markers, "⚙ IDENTITY")
+	}
+	if col.IsGenerated {
+		markers = append(markers, "⚙ GEN")
+	}
+	if col.Sequence != "" {
+		markers = append(markers, "→ SEQ")
+	}
 	if len(markers) == 0 {
 		return "-"
 	}
@@ -187,6 +322,71 @@ func (sv *StructureView) formatConstraintDefinition(con models.Constraint) strin
 	return con.Definition
 }
 
+// FormatConstraintDetail renders the full definition of a constraint for the
+// preview pane, including every local and referenced column of a foreign key
+func FormatConstraintDetail(con models.Constraint) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Type: %s\n", formatConstraintKind(con.Type)))
+	if len(con.Columns) > 0 {
+		b.WriteString(fmt.Sprintf("Columns: %s\n", strings.Join(con.Columns, ", ")))
+	}
+
+	if con.Type == "f" && con.ForeignTable != "" {
+		b.WriteString(fmt.Sprintf("References: %s (%s)\n", con.ForeignTable, strings.Join(con.ForeignCols, ", ")))
+		for i, col := range con.Columns {
+			if i < len(con.ForeignCols) {
+				b.WriteString(fmt.Sprintf("  %s → %s.%s\n", col, con.ForeignTable, con.ForeignCols[i]))
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(con.Definition)
+	return b.String()
+}
+
+// FormatIndexDetail formats an index for the preview pane: its method,
+// columns and properties, then the full CREATE INDEX statement
+func FormatIndexDetail(idx models.IndexInfo) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Type: %s\n", idx.Type))
+	if len(idx.Columns) > 0 {
+		b.WriteString(fmt.Sprintf("Columns: %s\n", strings.Join(idx.Columns, ", ")))
+	}
+	switch {
+	case idx.IsPrimary:
+		b.WriteString("Primary key\n")
+	case idx.IsUnique:
+		b.WriteString("Unique\n")
+	}
+	if idx.IsPartial && idx.Predicate != "" {
+		b.WriteString(fmt.Sprintf("Partial: WHERE %s\n", idx.Predicate))
+	}
+	b.WriteString(fmt.Sprintf("Size: %s\n", metadata.FormatSize(idx.Size)))
+
+	b.WriteString("\n")
+	b.WriteString(idx.Definition)
+	return b.String()
+}
+
+// formatConstraintKind returns a readable name for a constraint type code
+func formatConstraintKind(conType string) string {
+	switch conType {
+	case "p":
+		return "Primary key constraint"
+	case "f":
+		return "Foreign key constraint"
+	case "u":
+		return "Unique constraint"
+	case "c":
+		return "Check constraint"
+	case "x":
+		return "Exclusion constraint"
+	default:
+		return "Constraint"
+	}
+}
+
 func (sv *StructureView) formatConstraintDescription(con models.Constraint) string {
 	switch con.Type {
 	case "p":
@@ -242,35 +442,91 @@ func (sv *StructureView) formatIndexProperties(idx models.IndexInfo) string {
 	return strings.Join(props, ", ")
 }
 
-// NeedsMetadata returns true if structure metadata has not been loaded yet
-func (sv *StructureView) NeedsMetadata() bool {
-	return !sv.metadataLoaded && !sv.loading
+// NeedsSection returns true if a metadata sub-tab has not been loaded and
+// is not loading
+func (sv *StructureView) NeedsSection(tab int) bool {
+	if tab <= StructureTabData || tab >= structureTabCount {
+		return false
+	}
+	return !sv.sectionLoaded[tab] && !sv.sectionLoading[tab] && !sv.loading
+}
+
+// SetSectionLoading marks a metadata sub-tab as loading
+func (sv *StructureView) SetSectionLoading(tab int) {
+	if tab > StructureTabData && tab < structureTabCount {
+		sv.sectionLoading[tab] = true
+		sv.sectionErrors[tab] = ""
+	}
+}
+
+// IsSectionLoading returns true while any metadata sub-tab is loading
+func (sv *StructureView) IsSectionLoading() bool {
+	for _, loading := range sv.sectionLoading {
+		if loading {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSectionError records a failed load so the sub-tab shows the error and
+// is retried the next time it is shown
+func (sv *StructureView) SetSectionError(tab int, err error) {
+	if tab > StructureTabData && tab < structureTabCount {
+		sv.sectionLoading[tab] = false
+		sv.sectionErrors[tab] = err.Error()
+	}
 }
 
-// SetMetadata populates the columns/constraints/indexes tables with pre-loaded data
-func (sv *StructureView) SetMetadata(columns []models.ColumnDetail, constraints []models.Constraint, indexes []models.IndexInfo) {
+// SetColumns populates the Columns sub-tab
+func (sv *StructureView) SetColumns(columns []models.ColumnDetail) {
 	sv.columnsData = columns
 	sv.setColumnsTableData(columns)
+	sv.markSectionLoaded(StructureTabColumns)
+}
+
+// SetConstraints populates the Constraints sub-tab
+func (sv *StructureView) SetConstraints(constraints []models.Constraint) {
 	sv.constraintsData = constraints
 	sv.setConstraintsTableData(constraints)
+	sv.markSectionLoaded(StructureTabConstraints)
+}
+
+// SetIndexes populates the Indexes sub-tab
+func (sv *StructureView) SetIndexes(indexes []models.IndexInfo) {
 	sv.indexesData = indexes
 	sv.setIndexesTableData(indexes)
-	sv.metadataLoaded = true
-	sv.loading = false
+	sv.markSectionLoaded(StructureTabIndexes)
 }
 
-// SetMetadataLoading marks that metadata is being loaded
-func (sv *StructureView) SetMetadataLoading() {
-	sv.loading = true
+func (sv *StructureView) markSectionLoaded(tab int) {
+	sv.sectionLoaded[tab] = true
+	sv.sectionLoading[tab] = false
+	sv.sectionErrors[tab] = ""
+}
+
+// ActiveTab returns the index of the current sub-tab
+func (sv *StructureView) ActiveTab() int {
+	return sv.activeTab
 }
 
 // SwitchTab switches to a specific tab
 func (sv *StructureView) SwitchTab(tabIndex int) {
-	if tabIndex >= 0 && tabIndex <= 3 {
+	if tabIndex >= 0 && tabIndex < structureTabCount {
 		sv.activeTab = tabIndex
 	}
 }
 
+// NextTab switches to the next sub-tab, wrapping around
+func (sv *StructureView) NextTab() {
+	sv.activeTab = (sv.activeTab + 1) % structureTabCount
+}
+
+// PrevTab switches to the previous sub-tab, wrapping around
+func (sv *StructureView) PrevTab() {
+	sv.activeTab = (sv.activeTab + structureTabCount - 1) % structureTabCount
+}
+
 // HandleMouseClick handles mouse click events on the tab bar
 // Returns (handled, tabIndex) where tabIndex is the clicked tab (-1 if not a tab click)
 func (sv *StructureView) HandleMouseClick(msg tea.MouseMsg) (bool, int) {
@@ -280,7 +536,7 @@ func (sv *StructureView) HandleMouseClick(msg tea.MouseMsg) (bool, int) {
 	}
 
 	// Check each tab zone
-	for i := 0; i <= 3; i++ {
+	for i := 0; i < structureTabCount; i++ {
 		zoneID := fmt.Sprintf("%s%d", ZoneStructureTabPrefix, i)
 		if zone.Get(zoneID).InBounds(msg) {
 			sv.SwitchTab(i)
@@ -388,14 +644,21 @@ func (sv *StructureView) View() string {
 		b.WriteString(sv.tableView.View())
 	case 1, 2, 3:
 		// Show loading state only for metadata tabs
-		if sv.loading {
-			b.WriteString(lipgloss.NewStyle().
+		if sv.loading || sv.sectionLoading[sv.activeTab] {
+			spinnerView := ""
+			if sv.tableView.Spinner != nil {
+				spinnerView = sv.tableView.Spinner.View() + " "
+			}
+			b.WriteString(spinnerView + lipgloss.NewStyle().
 				Foreground(sv.Theme.Metadata).
-				Render("Loading structure..."))
-		} else if sv.errorMessage != "" {
+				Render(fmt.Sprintf("Loading %s...", strings.ToLower(structureTabLabels[sv.activeTab]))))
+		} else if msg := sv.sectionErrors[sv.activeTab]; msg != "" || sv.errorMessage != "" {
+			if msg == "" {
+				msg = sv.errorMessage
+			}
 			b.WriteString(lipgloss.NewStyle().
 				Foreground(sv.Theme.Error).
-				Render(sv.errorMessage))
+				Render(msg))
 		} else {
 			switch sv.activeTab {
 			case 1:
@@ -413,22 +676,18 @@ func (sv *StructureView) View() string {
 	return b.String()
 }
 
-func (sv *StructureView) renderTabBar() string {
-	tabs := []struct {
-		index int
-		label string
-	}{
-		{0, "Data"},
-		{1, "Columns"},
-		{2, "Constraints"},
-		{3, "Indexes"},
-	}
+// structureTabLabels are the sub-tab titles, indexed by sub-tab
+var structureTabLabels = [structureTabCount]string{"Data", "Columns", "Constraints", "Indexes"}
 
+func (sv *StructureView) renderTabBar() string {
 	var parts []string
 
-	for i, tab := range tabs {
+	for i, label := range structureTabLabels {
+		if i == StructureTabColumns && sv.columnsStorageOrder {
+			label += " (storage order)"
+		}
 		var tabContent string
-		if tab.index == sv.activeTab {
+		if i == sv.activeTab {
 			// Active tab - with blue indicator and background
 			indicatorStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#89b4fa")). // Blue
@@ -440,22 +699,22 @@ func (sv *StructureView) renderTabBar() string {
 				Background(lipgloss.Color("#313244")). // Surface0
 				Padding(0, 1)
 
-			tabContent = indicatorStyle.Render("▌") + tabStyle.Render(tab.label)
+			tabContent = indicatorStyle.Render("▌") + tabStyle.Render(label)
 		} else {
 			// Inactive tab
 			tabStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#6c7086")). // Overlay0
 				Padding(0, 1)
 
-			tabContent = tabStyle.Render(tab.label)
+			tabContent = tabStyle.Render(label)
 		}
 
 		// Wrap with zone mark for mouse click
-		zoneID := fmt.Sprintf("%s%d", ZoneStructureTabPrefix, tab.index)
+		zoneID := fmt.Sprintf("%s%d", ZoneStructureTabPrefix, i)
 		parts = append(parts, zone.Mark(zoneID, tabContent))
 
 		// Add separator between tabs (except after last)
-		if i < len(tabs)-1 {
+		if i < len(structureTabLabels)-1 {
 			separator := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#45475a")). // Surface1
 				Render(" │ ")
@@ -475,7 +734,7 @@ func (sv *StructureView) CopyCurrentName() string {
 			name = col.Name
 		}
 	case 2:
-		if con := sv.getSelectedConstraint(); con != nil {
+		if con := sv.GetSelectedConstraint(); con != nil {
 			name = con.Name
 		}
 	case 3:
@@ -503,7 +762,7 @@ func (sv *StructureView) CopyCurrentDefinition() string {
 				col.DefaultValue)
 		}
 	case 2:
-		if con := sv.getSelectedConstraint(); con != nil {
+		if con := sv.GetSelectedConstraint(); con != nil {
 			definition = con.Definition
 		}
 	case 3:
@@ -523,17 +782,51 @@ func (sv *StructureView) CopyCurrentDefinition() string {
 	return ""
 }
 
+// CurrentDDL returns the statement that recreates the selected constraint
+// or index of schema.table, and the item's name. It returns empty strings on
+// the Data and Columns tabs or when nothing is selected.
+func (sv *StructureView) CurrentDDL(schema, table string) (ddl, name string) {
+	switch sv.activeTab {
+	case StructureTabConstraints:
+		if con := sv.GetSelectedConstraint(); con != nil {
+			return metadata.ConstraintDDL(schema, table, *con), con.Name
+		}
+	case StructureTabIndexes:
+		if idx := sv.getSelectedIndex(); idx != nil {
+			return metadata.IndexDDL(*idx), idx.Name
+		}
+	}
+	return "", ""
+}
+
 // getSelectedColumn returns the currently selected column from raw data
 func (sv *StructureView) getSelectedColumn() *models.ColumnDetail {
-	idx := sv.columnsTable.SelectedRow
+	row := sv.columnsTable.SelectedRow
+	if row < 0 || row >= len(sv.columnRows) {
+		return nil
+	}
+	idx := sv.columnRows[row]
 	if idx < 0 || idx >= len(sv.columnsData) {
+		// A dropped column's slot
 		return nil
 	}
 	return &sv.columnsData[idx]
 }
 
-// getSelectedConstraint returns the currently selected constraint from raw data
-func (sv *StructureView) getSelectedConstraint() *models.Constraint {
+// SelectedColumnSequence returns the sequence the selected column takes its
+// values from, or empty strings if it has none
+func (sv *StructureView) SelectedColumnSequence() (schema, name string) {
+	if sv.activeTab != StructureTabColumns {
+		return "", ""
+	}
+	if col := sv.getSelectedColumn(); col != nil {
+		return col.SequenceSchema, col.Sequence
+	}
+	return "", ""
+}
+
+// GetSelectedConstraint returns the currently selected constraint from raw data
+func (sv *StructureView) GetSelectedConstraint() *models.Constraint {
 	idx := sv.constraintsTable.SelectedRow
 	if idx < 0 || idx >= len(sv.constraintsData) {
 		return nil
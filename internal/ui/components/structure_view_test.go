@@ -0,0 +1,211 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestFormatConstraintDetail_ForeignKeyListsAllColumns(t *testing.T) {
+	con := models.Constraint{
+		Name:         "orders_customer_fk",
+		Type:         "f",
+		Definition:   "FOREIGN KEY (tenant_id, customer_id) REFERENCES public.customers(tenant_id, id) ON DELETE CASCADE",
+		Columns:      []string{"tenant_id", "customer_id"},
+		ForeignTable: "public.customers",
+		ForeignCols:  []string{"tenant_id", "id"},
+	}
+
+	detail := FormatConstraintDetail(con)
+
+	for _, want := range []string{
+		"References: public.customers (tenant_id, id)",
+		"customer_id → public.customers.id",
+		"ON DELETE CASCADE",
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail missing %q:\n%s", want, detail)
+		}
+	}
+}
+
+func TestFormatConstraintDetail_CheckKeepsFullDefinition(t *testing.T) {
+	def := "CHECK (((price > (0)::numeric) AND (discount >= (0)::numeric) AND (discount <= price)))"
+	con := models.Constraint{Name: "price_check", Type: "c", Definition: def}
+
+	detail := FormatConstraintDetail(con)
+
+	if !strings.HasSuffix(detail, def) {
+		t.Errorf("expected full definition, got:\n%s", detail)
+	}
+	if !strings.HasPrefix(detail, "Type: Check constraint") {
+		t.Errorf("unexpected type line:\n%s", detail)
+	}
+}
+
+func TestFormatIndexDetail_EndsWithDefinition(t *testing.T) {
+	def := "CREATE UNIQUE INDEX users_email_idx ON public.users USING btree (lower(email)) WHERE (deleted_at IS NULL)"
+	idx := models.IndexInfo{Name: "users_email_idx", Type: "btree", Columns: []string{"lower(email)"},
+		IsUnique: true, IsPartial: true, Predicate: "(deleted_at IS NULL)", Definition: def}
+
+	detail := FormatIndexDetail(idx)
+
+	if !strings.HasSuffix(detail, def) {
+		t.Errorf("expected full definition, got:\n%s", detail)
+	}
+	for _, want := range []string{"Type: btree", "Unique", "Partial: WHERE (deleted_at IS NULL)"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail missing %q:\n%s", want, detail)
+		}
+	}
+}
+
+func TestStructureView_IndexPreview(t *testing.T) {
+	th := theme.DefaultTheme()
+	sv := NewStructureView(th, NewTableView(th))
+	sv.SetIndexes([]models.IndexInfo{{Name: "orders_pkey", Type: "btree", IsPrimary: true,
+		Definition: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)"}})
+
+	if !sv.indexesTable.PreviewOnClick {
+		t.Error("indexes table does not preview on click")
+	}
+	sv.indexesTable.ShowPreviewPane()
+	if !sv.indexesTable.PreviewPane.Visible {
+		t.Fatal("preview pane not shown")
+	}
+	if !sv.indexesTable.HidePreviewPane() || sv.indexesTable.PreviewPane.Visible {
+		t.Error("HidePreviewPane() did not hide the pane")
+	}
+}
+
+func TestStructureView_SectionsLoadOnce(t *testing.T) {
+	th := theme.DefaultTheme()
+	sv := NewStructureView(th, NewTableView(th))
+
+	if sv.NeedsSection(StructureTabData) {
+		t.Error("Data tab should never need metadata")
+	}
+	if !sv.NeedsSection(StructureTabColumns) {
+		t.Fatal("Columns should need loading at first")
+	}
+
+	sv.SetSectionLoading(StructureTabColumns)
+	if sv.NeedsSection(StructureTabColumns) || !sv.IsSectionLoading() {
+		t.Error("a loading section should not be requested again")
+	}
+
+	sv.SetColumns([]models.ColumnDetail{{Name: "id"}})
+	if sv.NeedsSection(StructureTabColumns) || sv.IsSectionLoading() {
+		t.Error("a loaded section should not be requested again")
+	}
+	// Other sections are still pending
+	if !sv.NeedsSection(StructureTabIndexes) {
+		t.Error("Indexes should still need loading")
+	}
+
+	// A failed load is retried
+	sv.SetSectionLoading(StructureTabIndexes)
+	sv.SetSectionError(StructureTabIndexes, errors.New("boom"))
+	if !sv.NeedsSection(StructureTabIndexes) {
+		t.Error("a failed section should be retried")
+	}
+}
+
+func TestStructureView_CycleTabs(t *testing.T) {
+	th := theme.DefaultTheme()
+	sv := NewStructureView(th, NewTableView(th))
+
+	sv.PrevTab()
+	if sv.ActiveTab() != StructureTabIndexes {
+		t.Errorf("PrevTab from Data = %d, want Indexes", sv.ActiveTab())
+	}
+	sv.NextTab()
+	if sv.ActiveTab() != StructureTabData {
+		t.Errorf("NextTab from Indexes = %d, want Data", sv.ActiveTab())
+	}
+}
+
+func TestFormatColumnConstraints_MarksGeneratedColumns(t *testing.T) {
+	th := theme.DefaultTheme()
+	sv := NewStructureView(th, NewTableView(th))
+	tests := []struct {
+		col  models.ColumnDetail
+		want string
+	}{
+		{models.ColumnDetail{IsPrimaryKey: true, Identity: "a"}, "PK, ⚙ IDENTITY"},
+		{models.ColumnDetail{IsGenerated: true}, "⚙ GEN"},
+		{models.ColumnDetail{Identity: "d", SequenceSchema: "public", Sequence: "t_id_seq"}, "⚙ IDENTITY, → SEQ"},
+		{models.ColumnDetail{}, "-"},
+	}
+	for _, tt := range tests {
+		if got := sv.formatColumnConstraints(tt.col); got != tt.want {
+			t.Errorf("formatColumnConstraints(%+v) = %q, want %q", tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestStructureView_SelectedColumnSequence(t *testing.T) {
+	th := theme.DefaultTheme()
+	sv := NewStructureView(th, NewTableView(th))
+	sv.SetColumns([]models.ColumnDetail{
+		{Name: "id", AttNum: 1, DefaultValue: "nextval('sales.order_ids'::regclass)", SequenceSchema: "sales", Sequence: "order_ids"},
+		{Name: "name", AttNum: 3},
+	})
+
+	if _, name := sv.SelectedColumnSequence(); name != "" {
+		t.Errorf("SelectedColumnSequence() = %q on the Data tab", name)
+	}
+	sv.SwitchTab(StructureTabColumns)
+	if schema, name := sv.SelectedColumnSequence(); schema != "sales" || name != "order_ids" {
+		t.Errorf("SelectedColumnSequence() = %q, %q, want sales, order_ids", schema, name)
+	}
+
+	// The dropped slot in storage order has no column, so no sequence
+	sv.ToggleColumnOrder()
+	sv.columnsTable.MoveSelection(1)
+	if _, name := sv.SelectedColumnSequence(); name != "" {
+		t.Errorf("SelectedColumnSequence() = %q on a dropped column's slot", name)
+	}
+}
+
+func TestStructureView_ColumnStorageOrder(t *testing.T) {
+	th := theme.DefaultTheme()
+	sv := NewStructureView(th, NewTableView(th))
+	// b was dropped between id and name
+	sv.SetColumns([]models.ColumnDetail{
+		{Name: "id", DataType: "integer", Position: 1, AttNum: 1, StorageLength: 4, StorageAlign: "i"},
+		{Name: "name", DataType: "text", Position: 2, AttNum: 3, StorageLength: -1, StorageAlign: "i"},
+	})
+
+	if got := sv.columnsTable.Rows[1][0]; got != "2" {
+		t.Errorf("logical position of name = %q, want 2", got)
+	}
+
+	if !sv.ToggleColumnOrder() {
+		t.Fatal("ToggleColumnOrder() did not switch to storage order")
+	}
+	rows := sv.columnsTable.Rows
+	if len(rows) != 3 || rows[1][1] != "(dropped column)" || rows[2][0] != "3" {
+		t.Fatalf("storage order rows = %v, want id, a dropped slot, then name at attnum 3", rows)
+	}
+	if got := rows[0][3]; got != "4 bytes, align 4" {
+		t.Errorf("storage of id = %q", got)
+	}
+	if !strings.Contains(sv.renderTabBar(), "storage order") {
+		t.Error("tab bar does not say which order is shown")
+	}
+
+	// The dropped slot has no column to copy; name is still found
+	sv.SwitchTab(StructureTabColumns)
+	sv.columnsTable.SetSelectedRow(1)
+	if sv.getSelectedColumn() != nil {
+		t.Error("a dropped slot selected a column")
+	}
+	sv.columnsTable.SetSelectedRow(2)
+	if col := sv.getSelectedColumn(); col == nil || col.Name != "name" {
+		t.Errorf("selected column = %v, want name", col)
+	}
+}
@@ -0,0 +1,62 @@
+package components
+
+import "strings"
+
+// TabTitleFields are the values a tab title template can refer to
+type TabTitleFields struct {
+	Schema   string
+	Name     string
+	Type     string // "table", "view", "function", ...
+	Database string
+}
+
+// FormatTabTitle expands a title template such as "{schema}.{name}". The
+// placeholders are {schema}, {name}, {type} and {database}. fallback is
+// returned when the template is empty or malformed, uses an unknown
+// placeholder, or needs a value the object doesn't have (an extension has
+// no schema), so a template never produces a half-filled title.
+func FormatTabTitle(template string, fields TabTitleFields, fallback string) string {
+	if strings.TrimSpace(template) == "" {
+		return fallback
+	}
+
+	var b strings.Builder
+	rest := template
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return fallback
+		}
+		b.WriteString(rest[:open])
+
+		var value string
+		switch rest[open+1 : open+end] {
+		case "schema":
+			value = fields.Schema
+		case "name":
+			value = fields.Name
+		case "type":
+			value = fields.Type
+		case "database":
+			value = fields.Database
+		default:
+			return fallback
+		}
+		if value == "" {
+			return fallback
+		}
+		b.WriteString(value)
+		rest = rest[open+end+1:]
+	}
+
+	title := strings.TrimSpace(b.String())
+	if title == "" {
+		return fallback
+	}
+	return title
+}
@@ -0,0 +1,52 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestFormatTabTitle(t *testing.T) {
+	fields := TabTitleFields{Schema: "sales", Name: "orders", Type: "table", Database: "shop"}
+
+	tests := []struct {
+		name     string
+		template string
+		fields   TabTitleFields
+		want     string
+	}{
+		{"empty template", "", fields, "fallback"},
+		{"schema prefix", "{schema}.{name}", fields, "sales.orders"},
+		{"all placeholders", "{database}/{schema}.{name} [{type}]", fields, "shop/sales.orders [table]"},
+		{"unknown placeholder", "{owner}.{name}", fields, "fallback"},
+		{"unclosed brace", "{schema.{name}", fields, "fallback"},
+		{"missing value", "{schema}.{name}", TabTitleFields{Name: "pgcrypto", Type: "extension"}, "fallback"},
+		{"blank result", "  ", fields, "fallback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTabTitle(tt.template, tt.fields, "fallback"); got != tt.want {
+				t.Errorf("FormatTabTitle(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTabBar_TruncatesLongTitles(t *testing.T) {
+	rt := NewResultTabs(theme.DefaultTheme())
+	rt.AddTableData("a.b", strings.Repeat("très_long_nom_", 10), NewStructureView(theme.DefaultTheme(), NewTableView(theme.DefaultTheme())))
+
+	bar := rt.RenderTabBar(100)
+	if !strings.Contains(bar, "…") {
+		t.Errorf("expected a truncated title, got %q", bar)
+	}
+	// Truncation must not split a multi-byte character
+	if strings.ContainsRune(bar, '�') {
+		t.Errorf("tab bar contains a broken character: %q", bar)
+	}
+	if w := lipgloss.Width(bar); w > 100 {
+		t.Errorf("tab bar width %d exceeds 100", w)
+	}
+}
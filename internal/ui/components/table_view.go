@@ -2,15 +2,19 @@ package components
 
 import (
 	"fmt"
+	"log"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/lipgloss"
 	zone "github.com/lrstanley/bubblezone"
 	"github.com/mattn/go-runewidth"
 	"github.com/rebelice/lazypg/internal/jsonb"
+	"github.com/rebelice/lazypg/internal/models"
 	"github.com/rebelice/lazypg/internal/ui/theme"
 )
 
@@ -20,9 +24,40 @@ const (
 	ZoneTableCellPrefix = "table-cell-" // Format: table-cell-{row}-{col}
 )
 
+// MissingCellValue stands in for the cells of a row that has fewer values
+// than there are columns, which should never happen. It is styled as a
+// warning so it can't be mistaken for a NULL.
+const MissingCellValue = "⚠ missing"
+
+// Keys that turn the selected cell into a column = value condition
+const (
+	CellFilterKey    = "w"      // Narrow the table tab's filter to the cell's value
+	CopyCellWhereKey = "ctrl+w" // Copy the condition as SQL
+)
+
+// Keys for the row number gutter. Rows are numbered from the start of the
+// results, counting the window's OFFSET and every page loaded.
+const (
+	RowNumbersKey      = "ctrl+l" // Show or hide the gutter
+	RelativeNumbersKey = "ctrl+n" // Number rows by distance from the selected one
+)
+
+// CopyRowsTSVKey copies the selected rows, the pinned rows, or every loaded
+// row, as tab-separated values for pasting into a spreadsheet
+const CopyRowsTSVKey = "ctrl+y"
+
+// Keys that build the row selection multi-row operations work on
+const (
+	SelectRowKey       = " " // Select or deselect the current row
+	SelectAllLoadedKey = "A" // Select every loaded row
+	InvertSelectionKey = "I"
+	ClearSelectionKey  = "X"
+)
+
 // TableView displays table data with virtual scrolling
 type TableView struct {
 	Columns      []string
+	ColumnKinds  []models.ColumnKind // Type of each column (nil when unknown)
 	Rows         [][]string
 	Width        int
 	Height       int
@@ -37,9 +72,29 @@ type TableView struct {
 	SelectedCol  int // Currently selected column
 	TotalRows    int
 
+	// MoreRows is set on a query result whose last page filled its LIMIT,
+	// so more rows may follow
+	MoreRows bool
+
+	// UnfilteredRows counts the table's rows when TotalRows are the ones
+	// matching a server-side filter, 0 otherwise. SetData clears it.
+	UnfilteredRows int
+
+	// Window, when set, is the LIMIT and OFFSET the rows were loaded with.
+	// Rows are numbered from the offset and no more are loaded on scrolling.
+	Window *models.RowWindow
+
 	// Column widths (calculated)
 	ColumnWidths []int
 
+	// Widths the columns' content asks for before the caps, to tell which
+	// columns are shown much narrower than their values
+	desiredWidths []int
+
+	// Widths set by hand, by column name, which replace the calculated
+	// width until reset. Keyed by name so they survive reloads.
+	widthOverrides map[string]int
+
 	// Sort state
 	SortColumn    int    // -1 means no sort, otherwise index of sorted column
 	SortDirection string // "ASC" or "DESC"
@@ -59,6 +114,31 @@ type TableView struct {
 	// Preview pane for truncated content
 	PreviewPane *PreviewPane
 
+	// PreviewContent, when set, supplies the preview pane content for a row
+	// instead of the selected cell (e.g. a full constraint definition)
+	PreviewContent func(row int) (content, title string)
+
+	// PreviewOnClick shows the preview pane on the first click on a row,
+	// rather than on a second click on the same cell
+	PreviewOnClick bool
+
+	// Masks hide the values of sensitive columns; set with SetMasks.
+	// Revealed cells show their real value until the data is replaced.
+	Masks      *MaskRules
+	maskedCols []bool
+	revealed   map[MatchPos]bool
+
+	// RowColors color the text of rows matching a rule. The selection,
+	// search matches and the change highlight take precedence.
+	RowColors *RowColorRules
+
+	// BoolGlyphs, when enabled, draw boolean cells as glyphs
+	BoolGlyphs *BoolGlyphs
+
+	// ForeignServer names the remote server a foreign table's rows are read
+	// from; the status line says so
+	ForeignServer string
+
 	// Line number display
 	ShowLineNumbers bool // Whether to show line numbers (default true)
 	RelativeNumbers bool // Whether to use relative line numbers (default false)
@@ -83,6 +163,44 @@ type TableView struct {
 	IsPrefetching     bool // Whether a prefetch is in progress
 	PrefetchThreshold int  // Distance from end to trigger prefetch
 
+	// Rows a refresh added or changed, highlighted until the highlight with
+	// changeSeq is cleared
+	changedRows map[int]bool
+	changeSeq   int
+
+	// The mismatched row warning last logged, so reloading the same result
+	// (e.g. on auto refresh) doesn't log it again
+	mismatchWarning string
+
+	// Rows selected for multi-row operations, by index into Rows. Appending
+	// a page keeps the indexes valid; SetData clears the selection.
+	selection map[int]struct{}
+
+	// Tones color cells of a result comparison, parallel to Rows (nil for
+	// none). SetData clears them.
+	Tones [][]CellTone
+
+	// Quick filter typed on the grid. While it narrows the rows, Rows holds
+	// the matching ones, quickAll every loaded row and quickIndex the index
+	// in quickAll of each row. The selection, pins, reveals, change
+	// highlight and tones are kept by index in quickAll.
+	QuickFilterQuery string
+	quickTyping      bool
+	quickAll         [][]string
+	quickIndex       []int
+
+	// SQL of the last load, page or search, with its $n placeholders
+	GeneratedSQL     string
+	ShowGeneratedSQL bool // Show GeneratedSQL on a line above the status
+
+	// TruncationWarnings colors the headers of columns shown at less than
+	// half the width of their values
+	TruncationWarnings bool
+
+	// Width left for columns at the last render, which caps a column wider
+	// than the panel
+	availableWidth int
+
 	// Cached styles for performance (avoid recreating on every render)
 	cachedStyles *tableViewStyles
 }
@@ -91,6 +209,7 @@ type TableView struct {
 type tableViewStyles struct {
 	headerBg         lipgloss.Style
 	headerText       lipgloss.Style // Bold + foreground color for header text
+	truncatedHeader  lipgloss.Style // Header of a column much narrower than its values
 	headerLineNum    lipgloss.Style
 	headerSep        lipgloss.Style
 	separator        lipgloss.Style
@@ -110,6 +229,15 @@ type tableViewStyles struct {
 	pinnedRow        lipgloss.Style
 	pinnedMarker     lipgloss.Style
 	pinnedSep        lipgloss.Style
+	uuidCell         lipgloss.Style // Foreground for uuid values
+	enumCell         lipgloss.Style // Foreground for enum values
+	relativeTime     lipgloss.Style // "3 days ago" annotation on the selected row
+	longValue        lipgloss.Style // Size marker on values too long to display
+	changedRow       lipgloss.Style // Rows a refresh added or changed
+	inSelection      lipgloss.Style // Rows selected for multi-row operations
+	selectionMarker  lipgloss.Style // Check mark before selected rows
+	missingCell      lipgloss.Style // Placeholder for cells a short row lacks
+	quickFilter      lipgloss.Style // Quick filter line above the status
 }
 
 // MatchPos represents a search match position
@@ -147,6 +275,9 @@ func (tv *TableView) initStyles() {
 		headerText: lipgloss.NewStyle().
 			Bold(true).
 			Foreground(tv.Theme.TableHeader),
+		truncatedHeader: lipgloss.NewStyle().
+			Background(tv.Theme.Selection).
+			Foreground(tv.Theme.Warning),
 		headerLineNum: lipgloss.NewStyle().
 			Background(tv.Theme.Selection).
 			Foreground(tv.Theme.Metadata),
@@ -198,22 +329,422 @@ func (tv *TableView) initStyles() {
 			Bold(true),
 		pinnedSep: lipgloss.NewStyle().
 			Foreground(tv.Theme.Border),
+		uuidCell: lipgloss.NewStyle().
+			Foreground(tv.Theme.Metadata),
+		enumCell: lipgloss.NewStyle().
+			Foreground(tv.Theme.TypeIcon),
+		relativeTime: lipgloss.NewStyle().
+			Foreground(tv.Theme.Metadata).
+			Italic(true),
+		longValue: lipgloss.NewStyle().
+			Foreground(tv.Theme.Metadata).
+			Italic(true),
+		changedRow: lipgloss.NewStyle().
+			Foreground(tv.Theme.Success).
+			Bold(true),
+		missingCell: lipgloss.NewStyle().
+			Foreground(tv.Theme.Warning).
+			Italic(true),
+		inSelection: lipgloss.NewStyle().
+			Foreground(tv.Theme.Info),
+		selectionMarker: lipgloss.NewStyle().
+			Foreground(tv.Theme.Info).
+			Bold(true),
+		quickFilter: lipgloss.NewStyle().
+			Foreground(tv.Theme.Info),
 	}
 }
 
-// SetData sets the table data
+// SetData sets the table data. Column kinds are cleared; call
+// SetColumnKinds afterwards when type information is available. The
+// selection and scroll position are kept, clamped to the new rows.
 func (tv *TableView) SetData(columns []string, rows [][]string, totalRows int) {
 	tv.Columns = columns
+	tv.ColumnKinds = nil
 	tv.Rows = rows
 	tv.TotalRows = totalRows
+	tv.UnfilteredRows = 0
+	tv.clearQuickFilter()
+	tv.changedRows = nil
+	tv.Tones = nil
+	tv.revealed = nil
+	tv.selection = nil
+	tv.updateMaskedColumns()
+	tv.warnMismatchedRows()
+	if tv.SelectedRow >= len(rows) {
+		tv.SelectedRow = max(len(rows)-1, 0)
+	}
+	if tv.TopRow > tv.SelectedRow {
+		tv.TopRow = tv.SelectedRow
+	}
+	if tv.SelectedCol >= len(columns) {
+		tv.SelectedCol = max(len(columns)-1, 0)
+	}
+	if tv.LeftColOffset > tv.SelectedCol {
+		tv.LeftColOffset = tv.SelectedCol
+	}
 	tv.calculateColumnWidths()
 }
 
+// RefreshData replaces the rows with a reload of the same result, as auto
+// refresh and Ctrl+R load it. Unlike SetData it keeps the quick filter,
+// applying it to the new rows, the selected row's place in the grid, and
+// the row selection: matched on the key columns as ChangedRows matches
+// rows, or else by position.
+func (tv *TableView) RefreshData(columns []string, rows [][]string, totalRows int, key []string) {
+	query, typing := tv.QuickFilterQuery, tv.quickTyping
+	selected, top := tv.SelectedRow, tv.TopRow
+	selection := tv.selection
+	oldRows := tv.LoadedRows()
+	tv.SetData(columns, rows, totalRows)
+	tv.selection = remapSelection(selection, columns, key, oldRows, rows)
+
+	tv.quickTyping = typing
+	if query == "" {
+		return
+	}
+	tv.SetQuickFilter(query)
+	tv.SelectedRow = min(selected, max(len(tv.Rows)-1, 0))
+	tv.TopRow = min(top, tv.SelectedRow)
+}
+
+// remapSelection returns the rows of newRows holding the selected rows of
+// oldRows, matched on the key columns. Without a key a row keeps its
+// selection by position.
+func remapSelection(selection map[int]struct{}, columns, key []string, oldRows, newRows [][]string) map[int]struct{} {
+	if len(selection) == 0 {
+		return nil
+	}
+	keyCols := make([]int, len(key))
+	for i, name := range key {
+		if keyCols[i] = slices.Index(columns, name); keyCols[i] < 0 {
+			keyCols = nil
+			break
+		}
+	}
+
+	remapped := make(map[int]struct{}, len(selection))
+	if len(keyCols) == 0 {
+		for row := range selection {
+			if row < len(newRows) {
+				remapped[row] = struct{}{}
+			}
+		}
+		return remapped
+	}
+	selectedKeys := make(map[string]bool, len(selection))
+	for row := range selection {
+		if row < len(oldRows) {
+			selectedKeys[rowKey(oldRows[row], keyCols)] = true
+		}
+	}
+	for i, row := range newRows {
+		if selectedKeys[rowKey(row, keyCols)] {
+			remapped[i] = struct{}{}
+		}
+	}
+	return remapped
+}
+
+// toneColor returns the color of a comparison cell's tone, if it has one
+func (tv *TableView) toneColor(row, col int) (lipgloss.Color, bool) {
+	row = tv.loadedIndex(row)
+	if row >= len(tv.Tones) || col >= len(tv.Tones[row]) {
+		return "", false
+	}
+	switch tv.Tones[row][col] {
+	case CellToneIncrease:
+		return tv.Theme.Success, true
+	case CellToneDecrease:
+		return tv.Theme.Error, true
+	case CellToneChanged:
+		return tv.Theme.Warning, true
+	}
+	return "", false
+}
+
+// centerInWidth pads s with spaces to center it in width cells
+func centerInWidth(s string, width int) string {
+	pad := width - runewidth.StringWidth(s)
+	if pad <= 0 {
+		return runewidth.Truncate(s, width, "")
+	}
+	return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+}
+
+// rowColor returns the color of the first row color rule a row matches
+func (tv *TableView) rowColor(row []string) (lipgloss.Color, bool) {
+	rule, ok := tv.RowColors.Match(tv.Columns, row)
+	if !ok {
+		return "", false
+	}
+	return rule.ThemeColor(tv.Theme), true
+}
+
+// warnMismatchedRows logs rows whose number of values doesn't match the
+// columns, once per result: the same warning isn't logged again until the
+// data stops mismatching. Short rows are drawn with MissingCellValue
+// placeholders; extra values have no column to show in.
+func (tv *TableView) warnMismatchedRows() {
+	short, long := 0, 0
+	for _, row := range tv.Rows {
+		switch {
+		case len(row) < len(tv.Columns):
+			short++
+		case len(row) > len(tv.Columns):
+			long++
+		}
+	}
+	var warnings []string
+	if short > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d of %d rows have fewer than %d values", short, len(tv.Rows), len(tv.Columns)))
+	}
+	if long > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d of %d rows have more than %d values", long, len(tv.Rows), len(tv.Columns)))
+	}
+	warning := strings.Join(warnings, "; ")
+	if warning != "" && warning != tv.mismatchWarning {
+		log.Printf("Warning: %s", warning)
+	}
+	tv.mismatchWarning = warning
+}
+
+// SetMasks sets the rules for which columns are masked
+func (tv *TableView) SetMasks(m *MaskRules) {
+	tv.Masks = m
+	tv.updateMaskedColumns()
+}
+
+func (tv *TableView) updateMaskedColumns() {
+	tv.maskedCols = nil
+	if tv.Masks.Empty() {
+		return
+	}
+	tv.maskedCols = make([]bool, len(tv.Columns))
+	for i, col := range tv.Columns {
+		tv.maskedCols[i] = tv.Masks.Matches(col)
+	}
+}
+
+// IsCellMasked reports whether a cell's value is hidden. NULLs are never
+// masked, since they give nothing away.
+func (tv *TableView) IsCellMasked(row, col int) bool {
+	return tv.isLoadedCellMasked(tv.loadedIndex(row), col)
+}
+
+// isLoadedCellMasked is IsCellMasked for a row counted among the loaded
+// rows, including those the quick filter hides
+func (tv *TableView) isLoadedCellMasked(row, col int) bool {
+	if col < 0 || col >= len(tv.maskedCols) || !tv.maskedCols[col] {
+		return false
+	}
+	rows := tv.LoadedRows()
+	if row < 0 || row >= len(rows) || col >= len(rows[row]) || rows[row][col] == "NULL" {
+		return false
+	}
+	return !tv.revealed[MatchPos{Row: row, Col: col}]
+}
+
+// ToggleReveal shows or hides the real value of the selected cell. It
+// reports false if the cell's column isn't masked.
+func (tv *TableView) ToggleReveal() bool {
+	col := tv.SelectedCol
+	if col < 0 || col >= len(tv.maskedCols) || !tv.maskedCols[col] {
+		return false
+	}
+	pos := MatchPos{Row: tv.loadedIndex(tv.SelectedRow), Col: col}
+	if tv.revealed[pos] {
+		delete(tv.revealed, pos)
+	} else {
+		if tv.revealed == nil {
+			tv.revealed = make(map[MatchPos]bool)
+		}
+		tv.revealed[pos] = true
+	}
+	tv.UpdatePreviewPane()
+	return true
+}
+
+// CopyValue returns what copying a cell puts on the clipboard: its value,
+// or the mask for a masked cell when the rules say so
+func (tv *TableView) CopyValue(row, col int) string {
+	if tv.Masks != nil && tv.Masks.CopyMask && tv.IsCellMasked(row, col) {
+		return MaskedValue
+	}
+	return tv.Rows[row][col]
+}
+
+// CopyRowValues returns the full values of the loaded rows at the given
+// indexes, as SelectionRows and PinnedRows give them, or of every row
+// shown when rows is nil, as CopyValue copies them. Cells missing from a
+// short row are empty.
+func (tv *TableView) CopyRowValues(rows []int) [][]string {
+	if rows == nil {
+		rows = make([]int, len(tv.Rows))
+		for i := range rows {
+			rows[i] = tv.loadedIndex(i)
+		}
+	}
+	loaded := tv.LoadedRows()
+	copyMask := tv.Masks != nil && tv.Masks.CopyMask
+	values := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		if row < 0 || row >= len(loaded) {
+			continue
+		}
+		record := make([]string, len(tv.Columns))
+		for col := range record {
+			switch {
+			case col >= len(loaded[row]):
+			case copyMask && tv.isLoadedCellMasked(row, col):
+				record[col] = MaskedValue
+			default:
+				record[col] = loaded[row][col]
+			}
+		}
+		values = append(values, record)
+	}
+	return values
+}
+
+// HighlightChanges highlights the rows at the given indexes, e.g. from
+// ChangedRows, and returns the sequence number that ClearChangeHighlight
+// takes to end this highlight
+func (tv *TableView) HighlightChanges(rows map[int]bool) int {
+	tv.changeSeq++
+	tv.changedRows = rows
+	return tv.changeSeq
+}
+
+// ClearChangeHighlight ends the highlight with sequence seq, leaving a newer
+// one in place
+func (tv *TableView) ClearChangeHighlight(seq int) {
+	if seq == tv.changeSeq {
+		tv.changedRows = nil
+	}
+}
+
+// IsChangedRow reports whether a row is highlighted as added or changed
+func (tv *TableView) IsChangedRow(row int) bool {
+	return tv.changedRows[tv.loadedIndex(row)]
+}
+
+// ToggleRowSelection adds the current row to the selection, or takes it out
+func (tv *TableView) ToggleRowSelection() {
+	if tv.SelectedRow >= len(tv.Rows) {
+		return
+	}
+	row := tv.loadedIndex(tv.SelectedRow)
+	if tv.InSelection(tv.SelectedRow) {
+		delete(tv.selection, row)
+		return
+	}
+	if tv.selection == nil {
+		tv.selection = make(map[int]struct{})
+	}
+	tv.selection[row] = struct{}{}
+}
+
+// SelectAllLoaded selects every loaded row, or while the quick filter
+// narrows them, the rows it shows. Rows of pages not loaded yet aren't
+// selected, and stay unselected when they arrive.
+func (tv *TableView) SelectAllLoaded() {
+	tv.selection = make(map[int]struct{}, len(tv.Rows))
+	for i := range tv.Rows {
+		tv.selection[tv.loadedIndex(i)] = struct{}{}
+	}
+}
+
+// InvertSelection selects the rows shown that aren't selected and
+// deselects those that are. Rows the quick filter hides keep their state.
+func (tv *TableView) InvertSelection() {
+	inverted := make(map[int]struct{}, len(tv.selection))
+	for row := range tv.selection {
+		inverted[row] = struct{}{}
+	}
+	for i := range tv.Rows {
+		row := tv.loadedIndex(i)
+		if tv.InSelection(i) {
+			delete(inverted, row)
+		} else {
+			inverted[row] = struct{}{}
+		}
+	}
+	tv.selection = inverted
+}
+
+// ClearSelection deselects all rows and returns how many were selected
+func (tv *TableView) ClearSelection() int {
+	n := tv.SelectionCount()
+	tv.selection = nil
+	return n
+}
+
+// InSelection reports whether a row is selected
+func (tv *TableView) InSelection(row int) bool {
+	_, ok := tv.selection[tv.loadedIndex(row)]
+	return ok
+}
+
+// SelectionCount returns the number of selected rows
+func (tv *TableView) SelectionCount() int {
+	return len(tv.selection)
+}
+
+// SelectionRows returns the indexes of the selected rows among the loaded
+// rows, in grid order. Rows the quick filter hides are included.
+func (tv *TableView) SelectionRows() []int {
+	rows := make([]int, 0, len(tv.selection))
+	for i := range tv.selection {
+		if i < len(tv.LoadedRows()) {
+			rows = append(rows, i)
+		}
+	}
+	slices.Sort(rows)
+	return rows
+}
+
+// SetColumnKinds sets the type of each column for type-aware rendering.
+// Kinds that don't line up with the current columns are ignored.
+func (tv *TableView) SetColumnKinds(kinds []models.ColumnKind) {
+	if len(kinds) != len(tv.Columns) {
+		kinds = nil
+	}
+	tv.ColumnKinds = kinds
+	tv.calculateColumnWidths()
+}
+
+// columnKind returns the kind of column col, or plain when unknown
+func (tv *TableView) columnKind(col int) models.ColumnKind {
+	if col < 0 || col >= len(tv.ColumnKinds) {
+		return models.ColumnKindPlain
+	}
+	return tv.ColumnKinds[col]
+}
+
+// SelectedJSON returns the full value of the selected cell if it holds
+// JSON: any non-NULL value of a json/jsonb column, or an object or array in
+// a column of unknown type. Masked cells don't count.
+func (tv *TableView) SelectedJSON() (string, bool) {
+	row, col := tv.SelectedRow, tv.SelectedCol
+	if row < 0 || row >= len(tv.Rows) || col < 0 || col >= len(tv.Rows[row]) || tv.IsCellMasked(row, col) {
+		return "", false
+	}
+	value := tv.Rows[row][col]
+	if value == "NULL" {
+		return "", false
+	}
+	if tv.columnKind(col) == models.ColumnKindJSON || jsonb.IsJSONB(value) {
+		return value, true
+	}
+	return "", false
+}
+
 // getLineNumberDigits returns the number of digits needed for line numbers
 func (tv *TableView) getLineNumberDigits() int {
 	maxRow := tv.TotalRows
-	if maxRow < len(tv.Rows) {
-		maxRow = len(tv.Rows)
+	if maxRow < tv.rowOffset()+len(tv.LoadedRows()) {
+		maxRow = tv.rowOffset() + len(tv.LoadedRows())
 	}
 	if maxRow == 0 {
 		maxRow = 1
@@ -249,8 +780,9 @@ func (tv *TableView) renderLineNumber(rowIndex int, isSelected bool) string {
 			displayNum = -displayNum
 		}
 	} else {
-		// Absolute mode or selected row in relative mode
-		displayNum = rowIndex + 1 // 1-indexed
+		// Absolute mode or selected row in relative mode; rows the quick
+		// filter hides are still counted
+		displayNum = tv.rowOffset() + tv.loadedIndex(rowIndex) + 1 // 1-indexed
 	}
 
 	digits := tv.getLineNumberDigits()
@@ -279,6 +811,12 @@ func (tv *TableView) ToggleRelativeNumbers() {
 	tv.RelativeNumbers = !tv.RelativeNumbers
 }
 
+// ToggleLineNumbers shows or hides the line number gutter, whose width goes
+// to the columns while hidden. The numbers are never copied or exported.
+func (tv *TableView) ToggleLineNumbers() {
+	tv.ShowLineNumbers = !tv.ShowLineNumbers
+}
+
 // calculateColumnWidths calculates optimal column widths
 func (tv *TableView) calculateColumnWidths() {
 	if len(tv.Columns) == 0 {
@@ -326,6 +864,15 @@ func (tv *TableView) calculateColumnWidths() {
 	}
 	minWidth := 10
 
+	// Leave room for the relative-time annotation on timestamp columns
+	for i := range desiredWidths {
+		if tv.columnKind(i).IsTimestamp() {
+			desiredWidths[i] += relativeTimeWidth
+		}
+	}
+
+	tv.desiredWidths = desiredWidths
+
 	for i, w := range desiredWidths {
 		if w > maxWidth {
 			w = maxWidth
@@ -333,10 +880,65 @@ func (tv *TableView) calculateColumnWidths() {
 		if w < minWidth {
 			w = minWidth
 		}
+		if override, ok := tv.widthOverrides[tv.Columns[i]]; ok {
+			w = override
+		}
 		tv.ColumnWidths[i] = w
 	}
 }
 
+// truncatedWidthRatio is the share of its values' width under which a
+// column counts as truncated
+const truncatedWidthRatio = 0.5
+
+// isColumnTruncated reports whether a column is shown at less than half
+// the width of its values, by its calculated or manual width, or by the
+// panel when that is narrower still
+func (tv *TableView) isColumnTruncated(col int) bool {
+	if col < 0 || col >= len(tv.ColumnWidths) || col >= len(tv.desiredWidths) {
+		return false
+	}
+	shown := tv.ColumnWidths[col]
+	if tv.availableWidth > 0 && tv.availableWidth < shown {
+		shown = tv.availableWidth
+	}
+	return float64(shown) < float64(tv.desiredWidths[col])*truncatedWidthRatio
+}
+
+// Limits and step of manual column widths
+const (
+	ColumnWidthStep   = 4
+	minManualColWidth = 3
+	maxManualColWidth = 200
+)
+
+// AdjustColumnWidth widens (delta > 0) or narrows the selected column by
+// delta cells, overriding its calculated width until ResetColumnWidths.
+// It returns false when there is no column to adjust.
+func (tv *TableView) AdjustColumnWidth(delta int) bool {
+	if tv.SelectedCol < 0 || tv.SelectedCol >= len(tv.ColumnWidths) {
+		return false
+	}
+	width := min(max(tv.ColumnWidths[tv.SelectedCol]+delta, minManualColWidth), maxManualColWidth)
+	if tv.widthOverrides == nil {
+		tv.widthOverrides = make(map[string]int)
+	}
+	tv.widthOverrides[tv.Columns[tv.SelectedCol]] = width
+	tv.ColumnWidths[tv.SelectedCol] = width
+	return true
+}
+
+// ResetColumnWidths drops the manual widths, returning every column to its
+// calculated width. It returns false if there were none.
+func (tv *TableView) ResetColumnWidths() bool {
+	if len(tv.widthOverrides) == 0 {
+		return false
+	}
+	tv.widthOverrides = nil
+	tv.calculateColumnWidths()
+	return true
+}
+
 // calculateVisibleCols calculates how many columns fit in the given width
 func (tv *TableView) calculateVisibleCols(width int) {
 	if len(tv.ColumnWidths) == 0 {
@@ -346,6 +948,7 @@ func (tv *TableView) calculateVisibleCols(width int) {
 
 	// Reserve space for edge indicators (2 chars each side) and line numbers
 	availableWidth := width - 4 - tv.getLineNumberWidth()
+	tv.availableWidth = availableWidth
 
 	// Count columns that fit starting from LeftColOffset
 	totalWidth := 0
@@ -475,10 +1078,20 @@ func (tv *TableView) View() string {
 	if pinnedHeight > 0 {
 		pinnedHeight += 1 // Add 1 for pinned separator
 	}
+	sqlLine := tv.ShowGeneratedSQL && tv.GeneratedSQL != ""
+	if sqlLine {
+		pinnedHeight++
+	}
+	quickLine := tv.IsQuickFilterActive()
+	if quickLine {
+		pinnedHeight++
+	}
 	tv.VisibleRows = contentHeight - 3 - pinnedHeight
 	if tv.VisibleRows < 1 {
 		tv.VisibleRows = 1
 	}
+	// The size may have changed while this table was in a background tab
+	tv.ensureRowVisible()
 
 	// Render visible rows
 	endRow := tv.TopRow + tv.VisibleRows
@@ -491,8 +1104,12 @@ func (tv *TableView) View() string {
 		visibleRowIndex := i - tv.TopRow
 
 		// Build row content
+		rowIndicator := leftIndicator
+		if tv.InSelection(i) {
+			rowIndicator = tv.cachedStyles.selectionMarker.Render("✓ ")
+		}
 		rowContent := tv.renderLineNumber(i, isSelected) +
-			leftIndicator +
+			rowIndicator +
 			tv.renderRow(tv.Rows[i], isSelected, i, visibleRowIndex) +
 			rightIndicator
 
@@ -506,7 +1123,15 @@ func (tv *TableView) View() string {
 		}
 	}
 
-	// Render status
+	// Render the generated SQL and the quick filter, then the status
+	if sqlLine {
+		b.WriteString("\n")
+		b.WriteString(tv.cachedStyles.status.Render(formatGeneratedSQL(tv.GeneratedSQL, contentWidth)))
+	}
+	if quickLine {
+		b.WriteString("\n")
+		b.WriteString(tv.cachedStyles.quickFilter.Render(truncateToWidth(tv.quickFilterLine(), contentWidth)))
+	}
 	b.WriteString("\n")
 	b.WriteString(tv.renderStatus())
 
@@ -557,7 +1182,11 @@ func (tv *TableView) renderHeader() string {
 		truncated := runewidth.Truncate(displayCol, width, "…")
 
 		// Render cell with cached header background style and width control
-		renderedCell := tv.cachedStyles.headerBg.Width(width).MaxWidth(width).Inline(true).Render(truncated)
+		headerStyle := tv.cachedStyles.headerBg
+		if tv.TruncationWarnings && tv.isColumnTruncated(i) {
+			headerStyle = tv.cachedStyles.truncatedHeader
+		}
+		renderedCell := headerStyle.Width(width).MaxWidth(width).Inline(true).Render(truncated)
 
 		// Add separator before cell (except first)
 		if colIndex > 0 {
@@ -593,6 +1222,45 @@ func (tv *TableView) renderSeparator() string {
 	return tv.cachedStyles.border.Render(strings.Repeat("─", totalWidth))
 }
 
+// LongValueBytes is the size above which a cell is marked with its value's
+// size, e.g. "⋯97.7 KiB", since the grid only ever shows its start. The
+// full value stays in Rows for the preview pane and copying.
+const LongValueBytes = 4096
+
+// cellDisplayText returns the part of value a cell width wide can show, on
+// one line. Only the first width*4 bytes (room for multi-byte characters)
+// are kept, cut at a character boundary, so a value megabytes long costs no
+// more to render than a short one.
+func cellDisplayText(value string, width int) string {
+	if maxLen := width * 4; len(value) > maxLen {
+		for maxLen > 0 && !utf8.RuneStart(value[maxLen]) {
+			maxLen--
+		}
+		value = value[:maxLen]
+	}
+	// Replace newlines with spaces to keep content on single line
+	value = strings.ReplaceAll(value, "\n", " ")
+	return strings.ReplaceAll(value, "\r", "")
+}
+
+// renderLongValue renders a cell whose value is over LongValueBytes as its
+// start followed by the value's size. ok is false for shorter values, or
+// when the column is too narrow to fit the marker beside some text.
+func (tv *TableView) renderLongValue(style lipgloss.Style, text string, size, width int) (string, bool) {
+	if size <= LongValueBytes {
+		return "", false
+	}
+	marker := " ⋯" + formatBytes(int64(size))
+	markerWidth := runewidth.StringWidth(marker)
+	if width-markerWidth < 4 {
+		return "", false
+	}
+	textWidth := width - markerWidth
+	markerStyle := tv.cachedStyles.longValue.Background(style.GetBackground())
+	return style.Width(textWidth).MaxWidth(textWidth).Inline(true).Render(runewidth.Truncate(text, textWidth, "…")) +
+		markerStyle.Width(markerWidth).Inline(true).Render(marker), true
+}
+
 func (tv *TableView) renderRow(row []string, selected bool, rowIndex int, visibleRowIndex int) string {
 	// Pre-allocate builder with estimated capacity
 	var b strings.Builder
@@ -607,29 +1275,28 @@ func (tv *TableView) renderRow(row []string, selected bool, rowIndex int, visibl
 		endCol = len(tv.ColumnWidths)
 	}
 
+	ruleColor, colored := tv.rowColor(row)
+
 	visibleColIndex := 0
 	for i := tv.LeftColOffset; i < endCol; i++ {
-		if i >= len(row) || i >= len(tv.ColumnWidths) {
-			break
-		}
 		width := tv.ColumnWidths[i]
 		if width <= 0 {
 			continue
 		}
 
-		value := row[i]
+		missing := i >= len(row)
+		value := MissingCellValue
+		if !missing {
+			value = row[i]
+		}
+		if tv.IsCellMasked(rowIndex, i) {
+			value = MaskedValue
+		}
 
 		// CRITICAL: Truncate FIRST before any string processing!
 		// Cells can contain megabytes of data (e.g., JSONB columns)
 		// Processing the full string is O(n) and extremely slow
-		maxProcessLen := width * 4 // Allow some buffer for multi-byte chars
-		if len(value) > maxProcessLen {
-			value = value[:maxProcessLen]
-		}
-
-		// Replace newlines with spaces to keep content on single line
-		cellValue := strings.ReplaceAll(value, "\n", " ")
-		cellValue = strings.ReplaceAll(cellValue, "\r", "")
+		cellValue := cellDisplayText(value, width)
 
 		// Check if this looks like JSONB and format for display
 		if jsonb.IsJSONB(cellValue) {
@@ -639,23 +1306,77 @@ func (tv *TableView) renderRow(row []string, selected bool, rowIndex int, visibl
 		// Use runewidth.Truncate for proper truncation (handles multibyte chars)
 		truncated := runewidth.Truncate(cellValue, width, "…")
 
+		// Booleans can be drawn as a glyph, centered; the value is unchanged
+		kind := tv.columnKind(i)
+		glyph := false
+		if kind == models.ColumnKindBool && !missing {
+			var g string
+			if g, glyph = tv.BoolGlyphs.Glyph(value); glyph {
+				truncated = centerInWidth(g, width)
+			}
+		}
+
 		// Determine cell style based on selection and search
 		// Priority: selected cell > current match > other matches > selected row > normal
+		// A row color rule only changes the text color of plain cells
 		var cellStyle lipgloss.Style
+		plainCell := false
 		if selected && i == tv.SelectedCol {
 			cellStyle = tv.cachedStyles.selectedCell
 		} else if tv.IsCurrentMatch(rowIndex, i) {
 			cellStyle = tv.cachedStyles.currentMatch
 		} else if tv.IsMatch(rowIndex, i) {
 			cellStyle = tv.cachedStyles.otherMatch
+		} else if missing {
+			cellStyle = tv.cachedStyles.missingCell
 		} else if selected {
 			cellStyle = tv.cachedStyles.selectedRow
+			plainCell = true
+		} else if tv.InSelection(rowIndex) {
+			cellStyle = tv.cachedStyles.inSelection
+		} else if tv.IsChangedRow(rowIndex) {
+			cellStyle = tv.cachedStyles.changedRow
 		} else {
 			cellStyle = tv.cachedStyles.normal
+			plainCell = true
+		}
+
+		// Type-aware colors apply only where selection/search don't
+		if plainCell && value != "NULL" {
+			switch kind {
+			case models.ColumnKindUUID:
+				cellStyle = cellStyle.Foreground(tv.cachedStyles.uuidCell.GetForeground())
+			case models.ColumnKindEnum:
+				cellStyle = cellStyle.Foreground(tv.cachedStyles.enumCell.GetForeground())
+			case models.ColumnKindBool:
+				if glyph && strings.HasPrefix(value, "t") {
+					cellStyle = cellStyle.Foreground(tv.Theme.Success)
+				} else if glyph {
+					cellStyle = cellStyle.Foreground(tv.Theme.Metadata)
+				}
+			}
+		}
+		if plainCell && colored {
+			cellStyle = cellStyle.Foreground(ruleColor)
+		}
+		if plainCell {
+			if color, ok := tv.toneColor(rowIndex, i); ok {
+				cellStyle = cellStyle.Foreground(color)
+			}
 		}
 
 		// Render with lipgloss width control for proper padding
-		renderedCell := cellStyle.Width(width).MaxWidth(width).Inline(true).Render(truncated)
+		var renderedCell string
+		if annotation := tv.relativeTimeAnnotation(kind, value, truncated, width, selected); annotation != "" {
+			annWidth := runewidth.StringWidth(annotation)
+			annStyle := tv.cachedStyles.relativeTime.Background(cellStyle.GetBackground())
+			renderedCell = cellStyle.Width(width-annWidth).Inline(true).Render(truncated) +
+				annStyle.Width(annWidth).Inline(true).Render(annotation)
+		} else if long, ok := tv.renderLongValue(cellStyle, cellValue, len(value), width); ok {
+			renderedCell = long
+		} else {
+			renderedCell = cellStyle.Width(width).MaxWidth(width).Inline(true).Render(truncated)
+		}
 
 		// Add separator before cell (except first)
 		if visibleColIndex > 0 {
@@ -687,7 +1408,7 @@ func (tv *TableView) renderPinnedRows() string {
 			continue
 		}
 
-		isSelected := rowIdx == tv.SelectedRow
+		isSelected := rowIdx == tv.loadedIndex(tv.SelectedRow)
 
 		// Render pin marker + line number
 		// Pin marker replaces first char of padding to keep alignment
@@ -696,7 +1417,7 @@ func (tv *TableView) renderPinnedRows() string {
 
 			// Format: "*" + number with (digits-1) width = total digits chars
 			marker := tv.cachedStyles.pinnedMarker.Render("*")
-			numStr := fmt.Sprintf("%*d", digits-1, rowIdx+1)
+			numStr := fmt.Sprintf("%*d", digits-1, tv.rowOffset()+rowIdx+1)
 			b.WriteString(marker)
 			b.WriteString(tv.cachedStyles.lineNumNormal.Render(numStr))
 			b.WriteString(tv.cachedStyles.separator.Render(" │ "))
@@ -740,32 +1461,35 @@ func (tv *TableView) renderPinnedRowCells(row []string, selected bool, rowIndex
 
 	visibleColIndex := 0
 	for i := tv.LeftColOffset; i < endCol; i++ {
-		if i >= len(row) || i >= len(tv.ColumnWidths) {
-			break
-		}
 		width := tv.ColumnWidths[i]
 		if width <= 0 {
 			continue
 		}
 
-		value := row[i]
-		maxProcessLen := width * 4
-		if len(value) > maxProcessLen {
-			value = value[:maxProcessLen]
+		missing := i >= len(row)
+		value := MissingCellValue
+		if !missing {
+			value = row[i]
 		}
-
-		cellValue := strings.ReplaceAll(value, "\n", " ")
-		cellValue = strings.ReplaceAll(cellValue, "\r", "")
+		if tv.isLoadedCellMasked(rowIndex, i) {
+			value = MaskedValue
+		}
+		cellValue := cellDisplayText(value, width)
 		truncated := runewidth.Truncate(cellValue, width, "…")
 
 		var cellStyle lipgloss.Style
 		if selected && i == tv.SelectedCol {
 			cellStyle = tv.cachedStyles.selectedCell
+		} else if missing {
+			cellStyle = tv.cachedStyles.missingCell
 		} else {
 			cellStyle = tv.cachedStyles.pinnedRow
 		}
 
-		renderedCell := cellStyle.Width(width).MaxWidth(width).Inline(true).Render(truncated)
+		renderedCell, ok := tv.renderLongValue(cellStyle, cellValue, len(value), width)
+		if !ok {
+			renderedCell = cellStyle.Width(width).MaxWidth(width).Inline(true).Render(truncated)
+		}
 
 		if visibleColIndex > 0 {
 			b.WriteString(separator)
@@ -809,16 +1533,60 @@ func (tv *TableView) renderStatus() string {
 		colInfo = fmt.Sprintf("Cols %d-%d of %d │ ", tv.LeftColOffset+1, endCol, len(tv.Columns))
 	}
 
-	// Pinned rows info
+	// Selected and pinned rows info
 	pinnedInfo := ""
 	if len(tv.PinnedRows) > 0 {
 		pinnedInfo = fmt.Sprintf("%d pinned │ ", len(tv.PinnedRows))
 	}
+	if n := tv.SelectionCount(); n > 0 {
+		pinnedInfo = fmt.Sprintf("%d selected │ ", n) + pinnedInfo
+	}
+	if tv.ForeignServer != "" {
+		matchInfo = fmt.Sprintf("%s remote: %s │ ", ForeignTableIcon, tv.ForeignServer) + matchInfo
+	}
 
 	showing := fmt.Sprintf(" 󰈙 %s%s%s%d-%d of %d rows", matchInfo, colInfo, pinnedInfo, tv.TopRow+1, endRow, tv.TotalRows)
+	if tv.Window != nil {
+		offset := tv.Window.Offset
+		showing = fmt.Sprintf(" 󰈙 %s%s%s%d-%d of %d rows │ LIMIT %d OFFSET %d",
+			matchInfo, colInfo, pinnedInfo, offset+tv.TopRow+1, offset+endRow, tv.TotalRows, tv.Window.Limit, offset)
+	}
+	if tv.MoreRows {
+		showing = fmt.Sprintf(" 󰈙 %s%s%s%d-%d of %d+ rows │ more rows available, %s loads the next page",
+			matchInfo, colInfo, pinnedInfo, tv.TopRow+1, endRow, tv.TotalRows, QueryNextPageKey)
+	}
+	if filtered := tv.filteredInfo(); filtered != "" {
+		showing += " │ " + filtered
+	}
 	return tv.cachedStyles.status.Render(showing)
 }
 
+// filteredInfo tells how far a search or filter narrowed the rows. A local
+// search counts the loaded rows with a match; a server-side filter counts
+// the matching rows of the whole table.
+func (tv *TableView) filteredInfo() string {
+	if tv.quickIndex != nil {
+		return fmt.Sprintf("showing %d of %d loaded (quick filter)", len(tv.Rows), len(tv.quickAll))
+	}
+	if tv.SearchActive && tv.SearchMode == "local" {
+		rows := make(map[int]bool)
+		for _, m := range tv.Matches {
+			rows[m.Row] = true
+		}
+		return fmt.Sprintf("showing %d of %d (filtered)", len(rows), len(tv.Rows))
+	}
+	if tv.UnfilteredRows > 0 {
+		return fmt.Sprintf("showing %d of %d (filtered)", tv.TotalRows, tv.UnfilteredRows)
+	}
+	return ""
+}
+
+// formatGeneratedSQL puts sql on one line of width cells, collapsing
+// whitespace and ending in "…" when cut
+func formatGeneratedSQL(sql string, width int) string {
+	return truncateToWidth(" SQL "+strings.Join(strings.Fields(sql), " "), width)
+}
+
 // MoveSelection moves the selection up or down
 func (tv *TableView) MoveSelection(delta int) {
 	tv.SelectedRow += delta
@@ -1048,6 +1816,23 @@ func (tv *TableView) GetSortColumn() string {
 	return tv.Columns[tv.SortColumn]
 }
 
+// SetSortByName marks the grid as sorted by the named column, for data that
+// was loaded already sorted. An empty or unknown column clears the sort.
+func (tv *TableView) SetSortByName(column, direction string, nullsFirst bool) {
+	tv.SortColumn = -1
+	for i, col := range tv.Columns {
+		if col == column {
+			tv.SortColumn = i
+			break
+		}
+	}
+	tv.SortDirection = direction
+	tv.NullsFirst = nullsFirst
+	if tv.SortColumn < 0 || tv.SortDirection == "" {
+		tv.SortDirection = "ASC"
+	}
+}
+
 // GetSortDirection returns the current sort direction
 func (tv *TableView) GetSortDirection() string {
 	return tv.SortDirection
@@ -1096,6 +1881,10 @@ func (tv *TableView) SearchLocal(query string) {
 
 	for rowIdx, row := range tv.Rows {
 		for colIdx, cell := range row {
+			// A match would give a masked value away
+			if tv.IsCellMasked(rowIdx, colIdx) {
+				continue
+			}
 			if strings.Contains(strings.ToLower(cell), queryLower) {
 				tv.Matches = append(tv.Matches, MatchPos{Row: rowIdx, Col: colIdx})
 			}
@@ -1216,6 +2005,12 @@ func (tv *TableView) IsCellTruncated() bool {
 	cellValue := tv.Rows[tv.SelectedRow][tv.SelectedCol]
 	colWidth := tv.ColumnWidths[tv.SelectedCol]
 
+	// The grid never shows more than cellDisplayText keeps, so a longer
+	// value is cut short without measuring all of it
+	if len(cellValue) > colWidth*4 {
+		return true
+	}
+
 	// Check if cell content width exceeds column width
 	return runewidth.StringWidth(cellValue) > colWidth
 }
@@ -1245,21 +2040,42 @@ func (tv *TableView) UpdatePreviewPane() {
 		return
 	}
 
+	if tv.PreviewContent != nil && tv.SelectedRow >= 0 && tv.SelectedRow < len(tv.Rows) {
+		content, title := tv.PreviewContent(tv.SelectedRow)
+		tv.PreviewPane.SetContent(content, title, true)
+		return
+	}
+
 	content := tv.GetSelectedCellContent()
 	title := tv.GetSelectedColumnName()
 	isTruncated := tv.IsCellTruncated()
+	if tv.IsCellMasked(tv.SelectedRow, tv.SelectedCol) {
+		content, isTruncated = MaskedValue, false
+	}
 
 	tv.PreviewPane.SetContent(content, title, isTruncated)
 }
 
-// SetPreviewPaneDimensions sets the dimensions for the preview pane
-func (tv *TableView) SetPreviewPaneDimensions(width, maxHeight int) {
-	if tv.PreviewPane != nil {
-		tv.PreviewPane.Width = width
-		tv.PreviewPane.MaxHeight = maxHeight
+// ShowPreviewPane shows the preview pane for the selection
+func (tv *TableView) ShowPreviewPane() {
+	if tv.PreviewPane == nil {
+		return
+	}
+	tv.UpdatePreviewPane()
+	if !tv.PreviewPane.Visible {
+		tv.PreviewPane.Toggle()
 	}
 }
 
+// HidePreviewPane hides the preview pane, reporting whether it was shown
+func (tv *TableView) HidePreviewPane() bool {
+	if tv.PreviewPane == nil || !tv.PreviewPane.Visible {
+		return false
+	}
+	tv.PreviewPane.Toggle()
+	return true
+}
+
 // TogglePreviewPane toggles the preview pane visibility
 func (tv *TableView) TogglePreviewPane() {
 	if tv.PreviewPane != nil {
@@ -1269,14 +2085,6 @@ func (tv *TableView) TogglePreviewPane() {
 	}
 }
 
-// GetPreviewPaneHeight returns the current preview pane height
-func (tv *TableView) GetPreviewPaneHeight() int {
-	if tv.PreviewPane != nil {
-		return tv.PreviewPane.Height()
-	}
-	return 0
-}
-
 // === Vim Motion Support ===
 
 const vimMotionTimeout = 1500 * time.Millisecond
@@ -1465,7 +2273,7 @@ func (tv *TableView) HasPendingVimMotion() bool {
 
 // TogglePin pins or unpins the currently selected row
 func (tv *TableView) TogglePin() error {
-	rowIndex := tv.SelectedRow
+	rowIndex := tv.loadedIndex(tv.SelectedRow)
 
 	// Check if already pinned
 	for i, pinnedIdx := range tv.PinnedRows {
@@ -1482,13 +2290,13 @@ func (tv *TableView) TogglePin() error {
 		return fmt.Errorf("maximum pinned rows (%d) reached", tv.MaxPinnedRows)
 	}
 
-	if rowIndex < 0 || rowIndex >= len(tv.Rows) {
-		return fmt.Errorf("invalid row index: %d", rowIndex)
+	if tv.SelectedRow < 0 || tv.SelectedRow >= len(tv.Rows) {
+		return fmt.Errorf("invalid row index: %d", tv.SelectedRow)
 	}
 
 	// Copy the row data
-	rowData := make([]string, len(tv.Rows[rowIndex]))
-	copy(rowData, tv.Rows[rowIndex])
+	rowData := make([]string, len(tv.Rows[tv.SelectedRow]))
+	copy(rowData, tv.Rows[tv.SelectedRow])
 
 	tv.PinnedRows = append(tv.PinnedRows, rowIndex)
 	tv.PinnedData = append(tv.PinnedData, rowData)
@@ -1497,6 +2305,7 @@ func (tv *TableView) TogglePin() error {
 
 // IsPinned returns true if the given row is pinned
 func (tv *TableView) IsPinned(rowIndex int) bool {
+	rowIndex = tv.loadedIndex(rowIndex)
 	for _, pinnedIdx := range tv.PinnedRows {
 		if pinnedIdx == rowIndex {
 			return true
@@ -1526,21 +2335,26 @@ func (tv *TableView) JumpToNextPinnedRow() {
 
 	// Find current position in pinned rows
 	currentIdx := -1
+	selected := tv.loadedIndex(tv.SelectedRow)
 	for i, pinnedRow := range tv.PinnedRows {
-		if pinnedRow == tv.SelectedRow {
+		if pinnedRow == selected {
 			currentIdx = i
 			break
 		}
 	}
 
-	// Jump to next pinned row (or first if not on pinned row)
-	nextIdx := 0
-	if currentIdx >= 0 {
-		nextIdx = (currentIdx + 1) % len(tv.PinnedRows)
+	// Jump to next pinned row (or first if not on pinned row), skipping
+	// those the quick filter hides
+	next := -1
+	for step := 1; step <= len(tv.PinnedRows) && next < 0; step++ {
+		next = tv.visibleIndex(tv.PinnedRows[(currentIdx+step)%len(tv.PinnedRows)])
+	}
+	if next < 0 {
+		return
 	}
 
 	// Set selected row and ensure it's visible
-	tv.SelectedRow = tv.PinnedRows[nextIdx]
+	tv.SelectedRow = next
 
 	// Adjust visible window if needed
 	if tv.SelectedRow < tv.TopRow {
@@ -1551,9 +2365,18 @@ func (tv *TableView) JumpToNextPinnedRow() {
 	}
 }
 
+// rowOffset returns the table row the first loaded row is, 0 unless a
+// window was loaded
+func (tv *TableView) rowOffset() int {
+	if tv.Window == nil {
+		return 0
+	}
+	return tv.Window.Offset
+}
+
 // NeedsPrefetch returns true if background prefetch should be triggered
 func (tv *TableView) NeedsPrefetch() bool {
-	if tv.IsPaginating || tv.IsPrefetching {
+	if tv.IsPaginating || tv.IsPrefetching || tv.Window != nil || tv.quickIndex != nil {
 		return false
 	}
 	if tv.PrefetchThreshold <= 0 {
@@ -0,0 +1,40 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTableView_CopyRowValues(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width, tv.Height = 80, 10
+	masks, _ := NewMaskRules([]string{"token"}, true)
+	tv.SetMasks(masks)
+	tv.SetData([]string{"id", "body", "token"}, [][]string{
+		{"1", long, "secret"},
+		{"2", "short", "NULL"},
+		{"3"},
+	}, 3)
+
+	all := tv.CopyRowValues(nil)
+	if len(all) != 3 {
+		t.Fatalf("CopyRowValues(nil) returned %d rows, want all 3", len(all))
+	}
+	if all[0][1] != long {
+		t.Errorf("CopyRowValues() cut the long value to %d bytes", len(all[0][1]))
+	}
+	if all[0][2] != MaskedValue || all[1][2] != "NULL" {
+		t.Errorf("CopyRowValues() token column = %q, %q, want the mask and NULL", all[0][2], all[1][2])
+	}
+	if len(all[2]) != 3 || all[2][1] != "" {
+		t.Errorf("CopyRowValues() short row = %q, want empty cells filled in", all[2])
+	}
+
+	some := tv.CopyRowValues([]int{2, 0, 7})
+	if len(some) != 2 || some[0][0] != "3" || some[1][0] != "1" {
+		t.Errorf("CopyRowValues([2 0 7]) = %v, want rows 3 and 1", some)
+	}
+}
@@ -0,0 +1,38 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTableView_FilteredInfo(t *testing.T) {
+	tv := NewTableView(theme.GetTheme("default"))
+	tv.SetData([]string{"id", "name"}, [][]string{
+		{"1", "Alice"},
+		{"2", "Bob"},
+		{"3", "Alina"},
+	}, 3)
+
+	if got := tv.filteredInfo(); got != "" {
+		t.Errorf("filteredInfo() without search or filter = %q, want empty", got)
+	}
+
+	// Alice and Alina match, Bob does not
+	tv.SearchLocal("al")
+	if got, want := tv.filteredInfo(), "showing 2 of 3 (filtered)"; got != want {
+		t.Errorf("filteredInfo() after local search = %q, want %q", got, want)
+	}
+
+	tv.SearchLocal("")
+	tv.TotalRows = 40
+	tv.UnfilteredRows = 1200
+	if got, want := tv.filteredInfo(), "showing 40 of 1200 (filtered)"; got != want {
+		t.Errorf("filteredInfo() with a server filter = %q, want %q", got, want)
+	}
+
+	tv.SetData([]string{"id"}, [][]string{{"1"}}, 1)
+	if got := tv.filteredInfo(); got != "" {
+		t.Errorf("filteredInfo() after unfiltered SetData = %q, want empty", got)
+	}
+}
@@ -0,0 +1,51 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestCellDisplayText(t *testing.T) {
+	if got := cellDisplayText("a\nb\r\nc", 10); got != "a b c" {
+		t.Errorf("cellDisplayText() = %q, want newlines turned into spaces", got)
+	}
+
+	// Cut at a character boundary, never inside one
+	value := strings.Repeat("é", 100) // 2 bytes each
+	got := cellDisplayText(value, 5)
+	if len(got) > 20 || !strings.HasPrefix(value, got) || strings.ContainsRune(got, '�') {
+		t.Errorf("cellDisplayText() = %q, want at most 20 bytes of whole characters", got)
+	}
+	if got := cellDisplayText("ab"+strings.Repeat("€", 10), 1); got != "ab" {
+		t.Errorf("cellDisplayText() = %q, want the cut moved back before the € it splits", got)
+	}
+}
+
+func TestTableView_LongValue(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width, tv.Height = 80, 10
+	tv.SetData([]string{"id", "body"}, [][]string{{"1", long}, {"2", "short"}}, 2)
+
+	view := tv.View()
+	if !strings.Contains(view, "⋯100.0 KiB") {
+		t.Errorf("view has no size marker on the long value:\n%s", view)
+	}
+	if strings.Count(view, "⋯") != 1 {
+		t.Errorf("view marks a short value:\n%s", view)
+	}
+
+	// The full value is still there for the preview pane and copying
+	tv.SelectedRow, tv.SelectedCol = 0, 1
+	if got := tv.GetSelectedCellContent(); got != long {
+		t.Errorf("GetSelectedCellContent() has %d bytes, want the full %d", len(got), len(long))
+	}
+	if got := tv.CopyValue(0, 1); got != long {
+		t.Errorf("CopyValue() has %d bytes, want the full %d", len(got), len(long))
+	}
+	if !tv.IsCellTruncated() {
+		t.Error("IsCellTruncated() = false for a long value")
+	}
+}
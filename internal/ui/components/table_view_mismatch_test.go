@@ -0,0 +1,68 @@
+package components
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTableView_ShortRows(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width, tv.Height = 100, 10
+	tv.SetData([]string{"id", "name", "email"}, [][]string{
+		{"1", "alice", "alice@example.com"},
+		{"2"},
+		{"3", "carol", "carol@example.com", "extra"},
+	}, 3)
+
+	view := tv.View()
+	if got := strings.Count(view, MissingCellValue); got != 2 {
+		t.Errorf("view shows %d placeholders, want 2 for the short row:\n%s", got, view)
+	}
+	if strings.Contains(view, "extra") {
+		t.Errorf("value without a column was shown:\n%s", view)
+	}
+
+	// Pinning and selecting a short row's missing cell doesn't crash
+	tv.SelectedRow, tv.SelectedCol = 1, 2
+	if err := tv.TogglePin(); err != nil {
+		t.Fatal(err)
+	}
+	_ = tv.View()
+	if got := tv.GetSelectedCellContent(); got != "" {
+		t.Errorf("GetSelectedCellContent() of a missing cell = %q, want empty", got)
+	}
+}
+
+func TestTableView_MismatchWarnedOncePerResult(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	tv := NewTableView(theme.DefaultTheme())
+	columns := []string{"id", "name"}
+	short := [][]string{{"1", "alice"}, {"2"}}
+
+	// Reloading the same result, as auto refresh does, warns once
+	tv.SetData(columns, short, 2)
+	tv.SetData(columns, short, 2)
+	if got := strings.Count(buf.String(), "Warning:"); got != 1 {
+		t.Errorf("logged %d warnings for the same result, want 1:\n%s", got, buf.String())
+	}
+
+	// A different mismatch is a different result
+	tv.SetData(columns, [][]string{{"1"}, {"2"}}, 2)
+	if got := strings.Count(buf.String(), "Warning:"); got != 2 {
+		t.Errorf("logged %d warnings, want a new one for the new mismatch", got)
+	}
+
+	// Once the rows match, the same mismatch coming back warns again
+	tv.SetData(columns, [][]string{{"1", "alice"}}, 1)
+	tv.SetData(columns, short, 2)
+	if got := strings.Count(buf.String(), "Warning:"); got != 3 {
+		t.Errorf("logged %d warnings, want the returning mismatch logged", got)
+	}
+}
@@ -0,0 +1,187 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// QuickFilterKey opens the data grid's quick filter empty. Printable keys
+// that have no action in the grid open it with the key already typed.
+const QuickFilterKey = "`"
+
+// StartQuickFilter opens the quick filter for a key pressed on the grid:
+// QuickFilterKey opens it empty, and other printable keys open it with the
+// key typed. It reports false for any other key, which is left to the grid.
+func (tv *TableView) StartQuickFilter(msg tea.KeyMsg) bool {
+	if msg.String() == QuickFilterKey {
+		tv.quickTyping = true
+		return true
+	}
+	if msg.Type != tea.KeyRunes || msg.Alt {
+		return false
+	}
+	tv.quickTyping = true
+	tv.SetQuickFilter(tv.QuickFilterQuery + string(msg.Runes))
+	return true
+}
+
+// UpdateQuickFilter handles a key while the quick filter is typed, or Esc
+// once it is applied. Enter keeps the filter and returns the keys to the
+// grid; Esc clears it and shows every loaded row again.
+func (tv *TableView) UpdateQuickFilter(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		tv.quickTyping = false
+		tv.SetQuickFilter("")
+	case tea.KeyEnter:
+		tv.quickTyping = false
+	case tea.KeyBackspace:
+		if runes := []rune(tv.QuickFilterQuery); len(runes) > 0 {
+			tv.SetQuickFilter(string(runes[:len(runes)-1]))
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		tv.SetQuickFilter(tv.QuickFilterQuery + string(msg.Runes))
+	}
+}
+
+// IsQuickFilterTyping reports whether keys go to the quick filter's input
+func (tv *TableView) IsQuickFilterTyping() bool {
+	return tv.quickTyping
+}
+
+// IsQuickFilterActive reports whether the quick filter is typed or narrows
+// the rows
+func (tv *TableView) IsQuickFilterActive() bool {
+	return tv.quickTyping || tv.quickIndex != nil
+}
+
+// SetQuickFilter narrows Rows to the loaded rows with a cell containing
+// query, ignoring case, and marks the matches with SearchLocal, which
+// selects the first. An empty query shows every loaded row again, keeping
+// the selected row selected. Masked cells never match.
+func (tv *TableView) SetQuickFilter(query string) {
+	selected := tv.loadedIndex(tv.SelectedRow)
+	all := tv.LoadedRows()
+
+	tv.QuickFilterQuery = query
+	if query != "" {
+		queryLower := strings.ToLower(query)
+		rows := make([][]string, 0, len(all))
+		index := make([]int, 0, len(all))
+		for i, row := range all {
+			if tv.quickFilterMatch(i, row, queryLower) {
+				rows = append(rows, row)
+				index = append(index, i)
+			}
+		}
+		tv.Rows, tv.quickAll, tv.quickIndex = rows, all, index
+		tv.SelectedRow, tv.TopRow = 0, 0
+		tv.SearchLocal(query)
+		return
+	}
+
+	tv.Rows, tv.quickAll, tv.quickIndex = all, nil, nil
+	tv.ClearSearch()
+	if selected >= 0 && selected < len(tv.Rows) {
+		tv.SelectedRow = selected
+	}
+	tv.ensureRowVisible()
+}
+
+// quickFilterMatch reports whether a loaded row has an unmasked cell
+// containing queryLower
+func (tv *TableView) quickFilterMatch(loaded int, row []string, queryLower string) bool {
+	for col, cell := range row {
+		if tv.isLoadedCellMasked(loaded, col) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(cell), queryLower) {
+			return true
+		}
+	}
+	return false
+}
+
+// clearQuickFilter drops the quick filter without touching Rows, for when
+// they are replaced
+func (tv *TableView) clearQuickFilter() {
+	tv.QuickFilterQuery = ""
+	tv.quickTyping = false
+	tv.quickAll = nil
+	tv.quickIndex = nil
+}
+
+// LoadedRows returns every loaded row, including those the quick filter
+// hides. Pages are loaded from len(LoadedRows()).
+func (tv *TableView) LoadedRows() [][]string {
+	if tv.quickIndex != nil {
+		return tv.quickAll
+	}
+	return tv.Rows
+}
+
+// AppendRows adds a page of rows after the loaded ones. While the quick
+// filter narrows the rows, only the new rows it matches are shown.
+func (tv *TableView) AppendRows(rows [][]string) {
+	if tv.quickIndex == nil {
+		tv.Rows = append(tv.Rows, rows...)
+		return
+	}
+	queryLower := strings.ToLower(tv.QuickFilterQuery)
+	for _, row := range rows {
+		loaded := len(tv.quickAll)
+		tv.quickAll = append(tv.quickAll, row)
+		if tv.quickFilterMatch(loaded, row, queryLower) {
+			tv.Rows = append(tv.Rows, row)
+			tv.quickIndex = append(tv.quickIndex, loaded)
+		}
+	}
+}
+
+// loadedIndex maps a row of Rows to its index among the loaded rows, which
+// the selection, pins and other per-row state are kept by so they survive
+// the quick filter
+func (tv *TableView) loadedIndex(row int) int {
+	if tv.quickIndex != nil && row >= 0 && row < len(tv.quickIndex) {
+		return tv.quickIndex[row]
+	}
+	return row
+}
+
+// SelectedLoadedRow returns the selected row's index among the loaded rows
+func (tv *TableView) SelectedLoadedRow() int {
+	return tv.loadedIndex(tv.SelectedRow)
+}
+
+// visibleIndex maps a loaded row to its row in Rows, -1 if the quick filter
+// hides it
+func (tv *TableView) visibleIndex(loaded int) int {
+	if tv.quickIndex == nil {
+		return loaded
+	}
+	for i, idx := range tv.quickIndex {
+		if idx == loaded {
+			return i
+		}
+	}
+	return -1
+}
+
+// quickFilterLine returns the quick filter's line above the status: the
+// query, a cursor while typing, and how many loaded rows match
+func (tv *TableView) quickFilterLine() string {
+	cursor := ""
+	if tv.quickTyping {
+		cursor = "▏"
+	}
+	line := fmt.Sprintf(" Filter: %s%s", tv.QuickFilterQuery, cursor)
+	if tv.quickIndex != nil {
+		line += fmt.Sprintf("  %d of %d loaded rows", len(tv.Rows), len(tv.quickAll))
+	}
+	if tv.quickTyping {
+		return line + " │ Enter: keep │ Esc: clear"
+	}
+	return line + " │ Esc: clear"
+}
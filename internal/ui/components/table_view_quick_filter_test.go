@@ -0,0 +1,136 @@
+package components
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTableView_QuickFilter(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width = 100
+	tv.Height = 20
+	tv.SetData([]string{"id", "name"}, [][]string{
+		{"1", "alice"}, {"2", "bob"}, {"3", "Alicia"}, {"4", "carol"},
+	}, 10)
+	typeKey := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// A key the grid has no action for starts the filter with it typed
+	if !tv.StartQuickFilter(typeKey("a")) || !tv.IsQuickFilterTyping() {
+		t.Fatal("a printable key did not start the quick filter")
+	}
+	if tv.StartQuickFilter(tea.KeyMsg{Type: tea.KeyCtrlN}) {
+		t.Error("a control key started the quick filter")
+	}
+	tv.UpdateQuickFilter(typeKey("l"))
+	tv.UpdateQuickFilter(typeKey("i"))
+	if got := names(tv.Rows); !slices.Equal(got, []string{"alice", "Alicia"}) {
+		t.Fatalf("rows filtered by %q = %v, want alice and Alicia", tv.QuickFilterQuery, got)
+	}
+	if !tv.SearchActive || len(tv.Matches) != 2 {
+		t.Errorf("matches = %v, want the two name cells marked", tv.Matches)
+	}
+	view := tv.View()
+	if !strings.Contains(view, "Filter: ali") || !strings.Contains(view, "2 of 4 loaded rows") {
+		t.Errorf("View() does not show the query and counts:\n%s", view)
+	}
+
+	// Per-row state is kept by loaded row: selecting and pinning Alicia
+	// (shown second) marks row 2 of the loaded rows
+	tv.UpdateQuickFilter(tea.KeyMsg{Type: tea.KeyEnter})
+	if tv.IsQuickFilterTyping() || !tv.IsQuickFilterActive() {
+		t.Fatal("Enter did not keep the filter and return the keys to the grid")
+	}
+	tv.SelectedRow = 1
+	tv.ToggleRowSelection()
+	if err := tv.TogglePin(); err != nil {
+		t.Fatal(err)
+	}
+	if got := tv.SelectionRows(); !slices.Equal(got, []int{2}) {
+		t.Errorf("SelectionRows() = %v, want [2]", got)
+	}
+	if !slices.Equal(tv.PinnedRows, []int{2}) {
+		t.Errorf("PinnedRows = %v, want [2]", tv.PinnedRows)
+	}
+
+	// A page arriving meanwhile adds the rows it matches
+	tv.AppendRows([][]string{{"5", "dave"}, {"6", "Malik"}})
+	if got := names(tv.Rows); !slices.Equal(got, []string{"alice", "Alicia", "Malik"}) {
+		t.Errorf("rows after a page = %v, want Malik added", got)
+	}
+	if got := len(tv.LoadedRows()); got != 6 {
+		t.Errorf("loaded rows = %d, want 6", got)
+	}
+	if tv.NeedsPrefetch() {
+		t.Error("NeedsPrefetch() while filtered")
+	}
+
+	// Backspace widens the filter; Esc clears it, keeping the selected row
+	tv.quickTyping = true
+	tv.UpdateQuickFilter(tea.KeyMsg{Type: tea.KeyBackspace})
+	if tv.QuickFilterQuery != "al" || len(tv.Rows) != 3 {
+		t.Errorf("after backspace query = %q with %d rows, want \"al\" with 3", tv.QuickFilterQuery, len(tv.Rows))
+	}
+	tv.SelectedRow = 1 // Alicia again
+	tv.UpdateQuickFilter(tea.KeyMsg{Type: tea.KeyEsc})
+	if tv.IsQuickFilterActive() || len(tv.Rows) != 6 || tv.SearchActive {
+		t.Fatalf("Esc left the filter: active=%v rows=%d", tv.IsQuickFilterActive(), len(tv.Rows))
+	}
+	if tv.SelectedRow != 2 || !tv.InSelection(2) || !tv.IsPinned(2) {
+		t.Errorf("after clearing, row %d is selected; want Alicia (2) still selected and pinned", tv.SelectedRow)
+	}
+
+	// New data drops the filter
+	tv.StartQuickFilter(typeKey(QuickFilterKey))
+	tv.UpdateQuickFilter(typeKey("bob"))
+	tv.SetData([]string{"id"}, [][]string{{"1"}}, 1)
+	if tv.IsQuickFilterActive() || tv.QuickFilterQuery != "" {
+		t.Error("SetData() kept the quick filter")
+	}
+}
+
+func TestTableView_QuickFilterSurvivesRefresh(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.SetData([]string{"id", "name"}, [][]string{{"1", "alice"}, {"2", "bob"}, {"3", "Alicia"}}, 3)
+	tv.SetQuickFilter("ali")
+	tv.SelectedRow = 1
+
+	// A refresh brings a new matching row; the filter applies to it
+	tv.RefreshData([]string{"id", "name"}, [][]string{{"1", "alice"}, {"2", "bob"}, {"3", "Alicia"}, {"4", "Malia"}}, 4, nil)
+	if tv.QuickFilterQuery != "ali" || !slices.Equal(names(tv.Rows), []string{"alice", "Alicia", "Malia"}) {
+		t.Errorf("after refresh query = %q, rows = %v", tv.QuickFilterQuery, names(tv.Rows))
+	}
+	if tv.SelectedRow != 1 {
+		t.Errorf("SelectedRow = %d after refresh, want it kept at 1", tv.SelectedRow)
+	}
+	if got := len(tv.LoadedRows()); got != 4 {
+		t.Errorf("loaded rows = %d, want 4", got)
+	}
+}
+
+func TestTableView_QuickFilterSkipsMaskedCells(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	masks, err := NewMaskRules([]string{"password"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tv.SetMasks(masks)
+	tv.SetData([]string{"name", "password"}, [][]string{{"alice", "hunter2"}, {"bob", "secret"}}, 2)
+
+	tv.SetQuickFilter("hunter")
+	if len(tv.Rows) != 0 {
+		t.Errorf("a masked value matched: %v", tv.Rows)
+	}
+}
+
+// names returns the second value of each row
+func names(rows [][]string) []string {
+	out := make([]string, len(rows))
+	for i, row := range rows {
+		out[i] = row[1]
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package components
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/models"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTableView_RowNumbers(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width = 60
+	tv.Height = 12
+	tv.SetData([]string{"name"}, [][]string{{"alice"}, {"bob"}}, 500)
+	tv.Window = &models.RowWindow{Limit: 50, Offset: 200}
+
+	// Numbers count the window's OFFSET and each page appended
+	tv.AppendRows([][]string{{"carol"}})
+	view := tv.View()
+	for _, want := range []string{"201 │", "202 │", "203 │"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() lacks row number %q:\n%s", want, view)
+		}
+	}
+	width := tv.getLineNumberWidth()
+
+	tv.ToggleLineNumbers()
+	if tv.getLineNumberWidth() != 0 || strings.Contains(tv.View(), "201 │") {
+		t.Error("hidden row numbers still take the gutter")
+	}
+	tv.ToggleLineNumbers()
+	if tv.getLineNumberWidth() != width {
+		t.Errorf("gutter width %d after showing again, want %d", tv.getLineNumberWidth(), width)
+	}
+
+	// The gutter is display only
+	if got := tv.CopyRowValues([]int{0}); !slices.Equal(got[0], []string{"alice"}) {
+		t.Errorf("CopyRowValues() = %v, want the values alone", got)
+	}
+}
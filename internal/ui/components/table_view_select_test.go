@@ -0,0 +1,85 @@
+package components
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTableView_Selection(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width = 80
+	tv.Height = 20
+	tv.SetData([]string{"id"}, [][]string{{"1"}, {"2"}, {"3"}, {"4"}}, 6)
+
+	tv.SelectedRow = 2
+	tv.ToggleRowSelection()
+	tv.SelectedRow = 0
+	tv.ToggleRowSelection()
+	if got := tv.SelectionRows(); !slices.Equal(got, []int{0, 2}) {
+		t.Fatalf("SelectionRows() = %v, want [0 2] in grid order", got)
+	}
+	if view := tv.View(); !strings.Contains(view, "2 selected") || !strings.Contains(view, "✓") {
+		t.Errorf("View() does not show the selection:\n%s", view)
+	}
+
+	tv.InvertSelection()
+	if got := tv.SelectionRows(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("SelectionRows() after invert = %v, want [1 3]", got)
+	}
+
+	// Scrolling and appending a page keep the selection; new rows stay
+	// unselected
+	tv.MoveSelection(3)
+	tv.Rows = append(tv.Rows, []string{"5"}, []string{"6"})
+	if got := tv.SelectionRows(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("SelectionRows() after append = %v, want [1 3]", got)
+	}
+
+	tv.SelectAllLoaded()
+	if tv.SelectionCount() != 6 {
+		t.Errorf("SelectionCount() = %d after selecting all, want 6", tv.SelectionCount())
+	}
+	tv.SelectedRow = 5
+	tv.ToggleRowSelection()
+	if tv.InSelection(5) || tv.SelectionCount() != 5 {
+		t.Errorf("ToggleRowSelection() did not deselect row 5")
+	}
+
+	if n := tv.ClearSelection(); n != 5 || tv.SelectionCount() != 0 {
+		t.Errorf("ClearSelection() = %d, leaving %d, want 5 and none", n, tv.SelectionCount())
+	}
+
+	// New data clears the selection
+	tv.SelectAllLoaded()
+	tv.SetData([]string{"id"}, [][]string{{"9"}}, 1)
+	if tv.SelectionCount() != 0 {
+		t.Errorf("SetData() kept %d selected rows", tv.SelectionCount())
+	}
+}
+
+func TestTableView_SelectionSurvivesRefresh(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.SetData([]string{"id", "name"}, [][]string{{"1", "a"}, {"2", "b"}, {"3", "c"}}, 3)
+	tv.SelectedRow = 1
+	tv.ToggleRowSelection()
+
+	// Row 2 moves to the end and a new row takes its place
+	tv.RefreshData([]string{"id", "name"}, [][]string{{"1", "a"}, {"4", "d"}, {"3", "c"}, {"2", "B"}}, 4, []string{"id"})
+	if got := tv.SelectionRows(); !slices.Equal(got, []int{3}) {
+		t.Errorf("SelectionRows() = %v after a refresh, want [3], the row with id 2", got)
+	}
+
+	// Without a key rows keep their selection by position
+	tv.RefreshData([]string{"id", "name"}, [][]string{{"1", "a"}, {"2", "b"}, {"3", "c"}, {"4", "d"}}, 4, nil)
+	if got := tv.SelectionRows(); !slices.Equal(got, []int{3}) {
+		t.Errorf("SelectionRows() = %v after a refresh without a key, want [3]", got)
+	}
+
+	tv.SetData([]string{"other"}, [][]string{{"x"}}, 1)
+	if tv.SelectionCount() != 0 {
+		t.Error("loading other data kept the selection")
+	}
+}
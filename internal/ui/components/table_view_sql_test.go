@@ -0,0 +1,44 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestFormatGeneratedSQL(t *testing.T) {
+	sql := "SELECT * FROM public.orders WHERE\n\t\"status\" = $1 ORDER BY \"id\" ASC NULLS LAST LIMIT 100 OFFSET 0"
+
+	got := formatGeneratedSQL(sql, 200)
+	want := ` SQL SELECT * FROM public.orders WHERE "status" = $1 ORDER BY "id" ASC NULLS LAST LIMIT 100 OFFSET 0`
+	if got != want {
+		t.Errorf("formatGeneratedSQL() = %q, want %q", got, want)
+	}
+
+	got = formatGeneratedSQL(sql, 30)
+	if lipgloss.Width(got) > 30 || !strings.HasSuffix(got, "…") {
+		t.Errorf("formatGeneratedSQL() at width 30 = %q", got)
+	}
+}
+
+func TestTableView_GeneratedSQLLine(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.Width, tv.Height = 80, 12
+	tv.SetData([]string{"id"}, [][]string{{"1"}, {"2"}}, 2)
+	tv.GeneratedSQL = "SELECT * FROM public.t LIMIT 100 OFFSET 0"
+
+	if strings.Contains(tv.View(), "SELECT") {
+		t.Error("generated SQL shown while ShowGeneratedSQL is off")
+	}
+	rows := tv.VisibleRows
+
+	tv.ShowGeneratedSQL = true
+	if !strings.Contains(tv.View(), "SELECT * FROM public.t") {
+		t.Error("generated SQL not shown while ShowGeneratedSQL is on")
+	}
+	if tv.VisibleRows != rows-1 {
+		t.Errorf("VisibleRows = %d, want %d (one line for the SQL)", tv.VisibleRows, rows-1)
+	}
+}
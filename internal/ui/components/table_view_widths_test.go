@@ -0,0 +1,83 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTableView_ColumnWidthOverrides(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	tv.SetData([]string{"id", "name"}, [][]string{{"1", "alice"}}, 1)
+	auto := tv.ColumnWidths[1]
+
+	tv.SelectedCol = 1
+	tv.AdjustColumnWidth(ColumnWidthStep)
+	tv.AdjustColumnWidth(ColumnWidthStep)
+	if got, want := tv.ColumnWidths[1], auto+2*ColumnWidthStep; got != want {
+		t.Fatalf("width after widening twice = %d, want %d", got, want)
+	}
+
+	// A reload that reorders the columns keeps the width with its column
+	tv.SetData([]string{"name", "id", "email"}, [][]string{{"bob", "2", "bob@example.com"}}, 1)
+	if got, want := tv.ColumnWidths[0], auto+2*ColumnWidthStep; got != want {
+		t.Errorf("width after reload = %d, want %d", got, want)
+	}
+
+	tv.SelectedCol = 1
+	for i := 0; i < 10; i++ {
+		tv.AdjustColumnWidth(-ColumnWidthStep)
+	}
+	if got := tv.ColumnWidths[1]; got != minManualColWidth {
+		t.Errorf("narrowed width = %d, want the minimum %d", got, minManualColWidth)
+	}
+
+	if !tv.ResetColumnWidths() {
+		t.Fatal("ResetColumnWidths() found nothing to reset")
+	}
+	if got := tv.ColumnWidths[0]; got != auto {
+		t.Errorf("width after reset = %d, want the calculated %d", got, auto)
+	}
+	if tv.ResetColumnWidths() {
+		t.Error("ResetColumnWidths() reported a reset with no manual widths")
+	}
+}
+
+func TestTableView_TruncatedColumns(t *testing.T) {
+	tv := NewTableView(theme.DefaultTheme())
+	long := strings.Repeat("x", 120)
+	tv.SetData([]string{"id", "note", "body"}, [][]string{{"1", "short note", long}}, 1)
+
+	// Capped at 50 of its 120 cells, body is truncated; the others fit
+	if tv.isColumnTruncated(0) || tv.isColumnTruncated(1) {
+		t.Error("columns that fit are flagged as truncated")
+	}
+	if !tv.isColumnTruncated(2) {
+		t.Error("a column capped at under half its width is not flagged")
+	}
+
+	// Narrowing by hand flags a column too, and a reset clears it
+	tv.SelectedCol = 1
+	for i := 0; i < 3; i++ {
+		tv.AdjustColumnWidth(-ColumnWidthStep)
+	}
+	if !tv.isColumnTruncated(1) {
+		t.Error("a column narrowed by hand is not flagged")
+	}
+	tv.ResetColumnWidths()
+	if tv.isColumnTruncated(1) {
+		t.Error("a column reset to its calculated width is still flagged")
+	}
+
+	// A panel narrower than a column flags it, and widening the panel
+	// clears the flag
+	tv.calculateVisibleCols(12)
+	if !tv.isColumnTruncated(1) {
+		t.Error("a column cut by a narrow panel is not flagged")
+	}
+	tv.calculateVisibleCols(200)
+	if tv.isColumnTruncated(1) {
+		t.Error("the flag stayed after the panel widened")
+	}
+}
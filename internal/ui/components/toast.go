@@ -0,0 +1,130 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+const (
+	// DefaultToastDuration is how long a toast stays on screen
+	DefaultToastDuration = 3 * time.Second
+
+	// maxVisibleToasts caps the stack; older toasts are dropped first
+	maxVisibleToasts = 4
+
+	// maxToastWidth keeps long messages (e.g. file paths) from covering the screen
+	maxToastWidth = 60
+)
+
+// ToastExpiredMsg is sent when a toast's time is up
+type ToastExpiredMsg struct {
+	ID int
+}
+
+// Toast is a short-lived notice for a non-error event
+type Toast struct {
+	ID      int
+	Message string
+}
+
+// ToastStack holds the toasts currently on screen. Toasts never take focus;
+// each one removes itself when its ToastExpiredMsg arrives.
+type ToastStack struct {
+	Theme    theme.Theme
+	Duration time.Duration
+
+	toasts []Toast
+	nextID int
+}
+
+// NewToastStack creates an empty toast stack
+func NewToastStack(th theme.Theme) *ToastStack {
+	return &ToastStack{
+		Theme:    th,
+		Duration: DefaultToastDuration,
+	}
+}
+
+// Push adds a toast and returns the command that expires it
+func (s *ToastStack) Push(message string) tea.Cmd {
+	s.nextID++
+	id := s.nextID
+	s.toasts = append(s.toasts, Toast{ID: id, Message: message})
+	if len(s.toasts) > maxVisibleToasts {
+		s.toasts = s.toasts[len(s.toasts)-maxVisibleToasts:]
+	}
+	return tea.Tick(s.Duration, func(time.Time) tea.Msg {
+		return ToastExpiredMsg{ID: id}
+	})
+}
+
+// Dismiss removes a toast. Unknown IDs (already dropped) are ignored.
+func (s *ToastStack) Dismiss(id int) {
+	for i, t := range s.toasts {
+		if t.ID == id {
+			s.toasts = append(s.toasts[:i], s.toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Toasts returns the visible toasts, oldest first
+func (s *ToastStack) Toasts() []Toast {
+	return s.toasts
+}
+
+// Len returns the number of visible toasts
+func (s *ToastStack) Len() int {
+	return len(s.toasts)
+}
+
+// View renders the toasts stacked vertically, newest at the bottom, right
+// aligned so the stack sits neatly in a corner
+func (s *ToastStack) View(maxWidth int) string {
+	if len(s.toasts) == 0 {
+		return ""
+	}
+	width := maxToastWidth
+	if maxWidth < width {
+		width = maxWidth
+	}
+	// Border (2) + padding (2)
+	textWidth := width - 4
+	if textWidth < 1 {
+		return ""
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(s.Theme.Success).
+		Background(s.Theme.Background).
+		Foreground(s.Theme.Foreground).
+		Padding(0, 1)
+	iconStyle := lipgloss.NewStyle().Foreground(s.Theme.Success).Background(s.Theme.Background)
+
+	boxes := make([]string, 0, len(s.toasts))
+	for _, t := range s.toasts {
+		// One line per toast: the first line of the message, truncated
+		text := strings.SplitN(t.Message, "\n", 2)[0]
+		text = truncateToWidth("✓ "+text, textWidth)
+		text = iconStyle.Render("✓") + strings.TrimPrefix(text, "✓")
+		boxes = append(boxes, style.Render(text))
+	}
+	return lipgloss.JoinVertical(lipgloss.Right, boxes...)
+}
+
+// truncateToWidth shortens s to fit width cells, ending in "…" when cut
+func truncateToWidth(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
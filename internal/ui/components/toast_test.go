@@ -0,0 +1,59 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestToastStack_PushAndDismiss(t *testing.T) {
+	s := NewToastStack(theme.DefaultTheme())
+	if cmd := s.Push("Copied to clipboard"); cmd == nil {
+		t.Fatal("Push() returned no expiry command")
+	}
+	s.Push("Favorite saved")
+
+	first := s.Toasts()[0].ID
+	s.Dismiss(first)
+	if s.Len() != 1 || s.Toasts()[0].Message != "Favorite saved" {
+		t.Fatalf("after Dismiss, toasts = %+v", s.Toasts())
+	}
+
+	// Expiring an already-dismissed toast is a no-op
+	s.Dismiss(first)
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", s.Len())
+	}
+}
+
+func TestToastStack_CapsVisibleToasts(t *testing.T) {
+	s := NewToastStack(theme.DefaultTheme())
+	for i := 0; i < maxVisibleToasts+2; i++ {
+		s.Push("toast")
+	}
+	if s.Len() != maxVisibleToasts {
+		t.Fatalf("Len() = %d, want %d", s.Len(), maxVisibleToasts)
+	}
+	// The oldest are dropped first
+	if got := s.Toasts()[0].ID; got != 3 {
+		t.Errorf("oldest visible ID = %d, want 3", got)
+	}
+}
+
+func TestToastStack_ViewFitsWidth(t *testing.T) {
+	s := NewToastStack(theme.DefaultTheme())
+	s.Push("Exported favorites to " + strings.Repeat("/very/long/path", 10))
+	s.Push("Copied")
+
+	view := s.View(40)
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("line width %d exceeds 40: %q", w, line)
+		}
+	}
+	if !strings.Contains(view, "Copied") {
+		t.Errorf("View() is missing a toast:\n%s", view)
+	}
+}
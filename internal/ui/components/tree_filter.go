@@ -95,7 +95,7 @@ func FuzzyMatch(pattern, target string) (bool, []int) {
 
 // nodeTypeMapping maps type filter strings to TreeNodeTypes
 var nodeTypeMapping = map[string][]models.TreeNodeType{
-	"table":     {models.TreeNodeTypeTable},
+	"table":     {models.TreeNodeTypeTable, models.TreeNodeTypeForeignTable},
 	"view":      {models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView},
 	"function":  {models.TreeNodeTypeFunction, models.TreeNodeTypeTriggerFunction},
 	"schema":    {models.TreeNodeTypeSchema},
@@ -131,6 +131,7 @@ func isSearchableNode(node *models.TreeNode) bool {
 	case models.TreeNodeTypeTable,
 		models.TreeNodeTypeView,
 		models.TreeNodeTypeMaterializedView,
+		models.TreeNodeTypeForeignTable,
 		models.TreeNodeTypeFunction,
 		models.TreeNodeTypeProcedure,
 		models.TreeNodeTypeTriggerFunction,
@@ -73,6 +73,9 @@ type TreeView struct {
 	LoadingNodeID  string         // ID of node currently loading children (for inline spinner)
 	LoadingStart   time.Time      // When loading started (for elapsed time)
 	Spinner        *spinner.Model // Shared spinner instance
+
+	// ShowCounts shows table/column counts on collapsed schema and relation nodes
+	ShowCounts bool
 }
 
 // TreeNodeSelectedMsg is sent when a node is selected (Enter key)
@@ -359,6 +362,12 @@ func (tv *TreeView) Update(msg tea.KeyMsg) (*TreeView, tea.Cmd) {
 		// Jump to bottom
 		tv.CursorIndex = len(visibleNodes) - 1
 
+	case "E":
+		tv.ExpandAll()
+
+	case "C":
+		tv.CollapseAll()
+
 	case "right", "l", " ":
 		// Expand node or move into expanded node
 		currentNode := visibleNodes[tv.CursorIndex]
@@ -499,6 +508,7 @@ func (tv *TreeView) getNodeIcon(node *models.TreeNode) string {
 	case models.TreeNodeTypeTableGroup,
 		models.TreeNodeTypeViewGroup,
 		models.TreeNodeTypeMaterializedViewGroup,
+		models.TreeNodeTypeForeignTableGroup,
 		models.TreeNodeTypeFunctionGroup,
 		models.TreeNodeTypeProcedureGroup,
 		models.TreeNodeTypeTriggerFunctionGroup,
@@ -524,6 +534,8 @@ func (tv *TreeView) getNodeIcon(node *models.TreeNode) string {
 			iconColor = tv.Theme.ViewIcon
 		case models.TreeNodeTypeMaterializedViewGroup:
 			iconColor = tv.Theme.MaterializedViewIcon
+		case models.TreeNodeTypeForeignTableGroup:
+			iconColor = tv.Theme.ExtensionIcon
 		case models.TreeNodeTypeFunctionGroup:
 			iconColor = tv.Theme.FunctionIcon
 		case models.TreeNodeTypeProcedureGroup:
@@ -560,6 +572,10 @@ func (tv *TreeView) getNodeIcon(node *models.TreeNode) string {
 		icon = "◉"
 		iconColor = tv.Theme.MaterializedViewIcon
 
+	case models.TreeNodeTypeForeignTable:
+		icon = ForeignTableIcon
+		iconColor = tv.Theme.ExtensionIcon
+
 	case models.TreeNodeTypeFunction:
 		icon = "ƒ"
 		iconColor = tv.Theme.FunctionIcon
@@ -608,6 +624,10 @@ func (tv *TreeView) getNodeIcon(node *models.TreeNode) string {
 		icon = "•"
 		iconColor = tv.Theme.ColumnIcon
 
+	case models.TreeNodeTypeEnumValue:
+		icon = "◦"
+		iconColor = tv.Theme.Metadata
+
 	default:
 		// Generic expandable/collapsible
 		if node.Expanded {
@@ -655,6 +675,8 @@ func (tv *TreeView) buildNodeLabelWithHighlight(node *models.TreeNode, selected
 		case models.TreeNodeTypeSchema:
 			if node.Loaded && len(node.Children) == 0 {
 				suffix = " " + metaStyle.Render("∅")
+			} else if count := tv.nodeCount(node); count != "" {
+				suffix = " " + metaStyle.Render(count)
 			}
 		case models.TreeNodeTypeTable:
 			if meta, ok := node.Metadata.(map[string]interface{}); ok {
@@ -662,6 +684,20 @@ func (tv *TreeView) buildNodeLabelWithHighlight(node *models.TreeNode, selected
 					suffix = " " + metaStyle.Render(formatNumber(rowCount))
 				}
 			}
+			if count := tv.nodeCount(node); count != "" {
+				suffix += " " + metaStyle.Render(count)
+			}
+		case models.TreeNodeTypeView, models.TreeNodeTypeMaterializedView:
+			if count := tv.nodeCount(node); count != "" {
+				suffix = " " + metaStyle.Render(count)
+			}
+		case models.TreeNodeTypeForeignTable:
+			if server := ForeignServerOf(node); server != "" {
+				suffix = " " + metaStyle.Render("@"+server)
+			}
+			if count := tv.nodeCount(node); count != "" {
+				suffix += " " + metaStyle.Render(count)
+			}
 		case models.TreeNodeTypeColumn:
 			if meta, ok := node.Metadata.(models.ColumnInfo); ok {
 				if meta.PrimaryKey {
@@ -675,6 +711,33 @@ func (tv *TreeView) buildNodeLabelWithHighlight(node *models.TreeNode, selected
 	return labelPart + suffix
 }
 
+// nodeCount returns the "(42 tables)" / "(12 cols)" label suffix for a
+// collapsed node, or "" when counts are off or the node has none
+func (tv *TreeView) nodeCount(node *models.TreeNode) string {
+	if !tv.ShowCounts || node.Expanded {
+		return ""
+	}
+	meta, ok := node.Metadata.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if n, ok := meta["table_count"].(int); ok {
+		return "(" + pluralize(n, "table", "tables") + ")"
+	}
+	if n, ok := meta["column_count"].(int); ok {
+		return "(" + pluralize(n, "col", "cols") + ")"
+	}
+	return ""
+}
+
+// pluralize formats a count with the singular or plural noun
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
 // renderHighlightedText renders text with specific positions highlighted
 func (tv *TreeView) renderHighlightedText(text string, positions []int, highlightStyle lipgloss.Style) string {
 	if len(positions) == 0 {
@@ -876,6 +939,82 @@ func (tv *TreeView) GetCurrentNode() *models.TreeNode {
 	return visibleNodes[tv.CursorIndex]
 }
 
+// SetSystemSchemasVisible shows or hides system schemas, keeping the cursor
+// on the current node when it is still in the tree
+func (tv *TreeView) SetSystemSchemasVisible(visible bool) {
+	if tv.Root == nil {
+		return
+	}
+	current := tv.GetCurrentNode()
+	tv.Root.SetSystemSchemasVisible(visible)
+	tv.applyFilter()
+
+	nodes := tv.getVisibleNodes()
+	if idx := tv.findNodeIndex(nodes, current); idx >= 0 {
+		tv.CursorIndex = idx
+	} else if tv.CursorIndex >= len(nodes) {
+		tv.CursorIndex = len(nodes) - 1
+	}
+	if tv.CursorIndex < 0 {
+		tv.CursorIndex = 0
+	}
+}
+
+// ExpandAll expands every node whose children are already loaded. Nodes
+// that would need a lazy load stay collapsed, so nothing is queried.
+func (tv *TreeView) ExpandAll() {
+	if tv.Root == nil {
+		return
+	}
+	current := tv.GetCurrentNode()
+	var expand func(node *models.TreeNode)
+	expand = func(node *models.TreeNode) {
+		if len(node.Children) > 0 {
+			node.Expanded = true
+		}
+		for _, child := range node.Children {
+			expand(child)
+		}
+	}
+	expand(tv.Root)
+	tv.keepCursorOn(current)
+}
+
+// CollapseAll collapses every node, leaving only the top level showing
+func (tv *TreeView) CollapseAll() {
+	if tv.Root == nil {
+		return
+	}
+	current := tv.GetCurrentNode()
+	var collapse func(node *models.TreeNode)
+	collapse = func(node *models.TreeNode) {
+		node.Expanded = false
+		for _, child := range node.Children {
+			collapse(child)
+		}
+	}
+	for _, child := range tv.Root.Children {
+		collapse(child)
+	}
+	tv.keepCursorOn(current)
+}
+
+// keepCursorOn moves the cursor to node after the visible nodes changed, or
+// to its nearest ancestor that is still visible
+func (tv *TreeView) keepCursorOn(node *models.TreeNode) {
+	tv.applyFilter()
+	nodes := tv.getVisibleNodes()
+	for ; node != nil; node = node.Parent {
+		if idx := tv.findNodeIndex(nodes, node); idx >= 0 {
+			tv.CursorIndex = idx
+			tv.adjustScrollOffset(len(nodes), tv.Height)
+			return
+		}
+	}
+	tv.CursorIndex = max(min(tv.CursorIndex, len(nodes)-1), 0)
+	tv.adjustScrollOffset(len(nodes), tv.Height)
+}
+
 // SetCursorToNode sets the cursor to a specific node (by ID)
 func (tv *TreeView) SetCursorToNode(nodeID string) bool {
 	if tv.Root == nil {
@@ -1045,6 +1184,21 @@ func (tv *TreeView) HandleClick(clickedRow int) (*TreeView, tea.Cmd) {
 	return tv, nil
 }
 
+// NodeAtRow moves the cursor to the node on a visible row without acting on
+// it, and returns the node, or nil when the row is empty
+func (tv *TreeView) NodeAtRow(row int) *models.TreeNode {
+	if tv.Root == nil {
+		return nil
+	}
+	visibleNodes := tv.getVisibleNodes()
+	index := tv.ScrollOffset + row
+	if index < 0 || index >= len(visibleNodes) {
+		return nil
+	}
+	tv.CursorIndex = index
+	return visibleNodes[index]
+}
+
 // IsSearchInputting returns true if the TreeView is in search input mode
 // Used by app to route all keys to TreeView during search
 func (tv *TreeView) IsSearchInputting() bool {
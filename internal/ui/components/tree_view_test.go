@@ -252,6 +252,44 @@ func TestTreeView_ExpandAndNavigateToParent(t *testing.T) {
 	}
 }
 
+func TestTreeView_ExpandAllCollapseAll(t *testing.T) {
+	root := models.NewTreeNode("root", models.TreeNodeTypeRoot, "Databases")
+	db := models.NewTreeNode("db:app", models.TreeNodeTypeDatabase, "app")
+	root.AddChild(db)
+	schema := models.NewTreeNode("schema:app.public", models.TreeNodeTypeSchema, "public")
+	db.AddChild(schema)
+	loaded := models.NewTreeNode("table:app.public.users", models.TreeNodeTypeTable, "users")
+	loaded.Loaded = true
+	loaded.AddChild(models.NewTreeNode("column:app.public.users.id", models.TreeNodeTypeColumn, "id"))
+	schema.AddChild(loaded)
+	unloaded := models.NewTreeNode("table:app.public.orders", models.TreeNodeTypeTable, "orders")
+	schema.AddChild(unloaded)
+
+	tv := NewTreeView(root, theme.DefaultTheme())
+	tv.Height = 20
+	tv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+
+	if !db.Expanded || !schema.Expanded || !loaded.Expanded {
+		t.Error("E did not expand the loaded nodes")
+	}
+	if unloaded.Expanded {
+		t.Error("E expanded a node whose children are not loaded")
+	}
+
+	// Put the cursor on the column, then collapse: it moves up to the database
+	if !tv.SetCursorToNode("column:app.public.users.id") {
+		t.Fatal("column not visible after expanding all")
+	}
+	tv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+
+	if db.Expanded || schema.Expanded || loaded.Expanded {
+		t.Error("C left nodes expanded")
+	}
+	if got := tv.GetCurrentNode(); got != db {
+		t.Errorf("cursor on %v after collapsing all, want the database", got)
+	}
+}
+
 func TestTreeView_SelectNode(t *testing.T) {
 	root := models.BuildDatabaseTree([]string{"postgres"}, "postgres")
 	testTheme := theme.DefaultTheme()
@@ -778,3 +816,29 @@ func TestTreeView_SchemaPathInFilterMode(t *testing.T) {
 		t.Error("expected schema path '(public)' in filter results")
 	}
 }
+
+func TestTreeView_NodeCounts(t *testing.T) {
+	schema := models.NewTreeNode("schema:db.public", models.TreeNodeTypeSchema, "public")
+	schema.Metadata = map[string]interface{}{"table_count": 42}
+	table := models.NewTreeNode("table:db.public.users", models.TreeNodeTypeTable, "users")
+	table.Metadata = map[string]interface{}{"column_count": 1}
+
+	tv := NewTreeView(models.NewTreeNode("root", models.TreeNodeTypeRoot, "Databases"), theme.DefaultTheme())
+
+	if got := tv.buildNodeLabelWithHighlight(schema, false); strings.Contains(got, "tables") {
+		t.Errorf("expected no counts while disabled, got %q", got)
+	}
+
+	tv.ShowCounts = true
+	if got := tv.buildNodeLabelWithHighlight(schema, false); !strings.Contains(got, "(42 tables)") {
+		t.Errorf("expected schema table count, got %q", got)
+	}
+	if got := tv.buildNodeLabelWithHighlight(table, false); !strings.Contains(got, "(1 col)") {
+		t.Errorf("expected singular column count, got %q", got)
+	}
+
+	schema.Expanded = true
+	if got := tv.buildNodeLabelWithHighlight(schema, false); strings.Contains(got, "tables") {
+		t.Errorf("expected no count on expanded node, got %q", got)
+	}
+}
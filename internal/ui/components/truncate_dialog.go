@@ -0,0 +1,194 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rebelice/lazypg/internal/db/metadata"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+// TruncateConfirmMsg is sent when the table's name was typed to confirm
+// truncating it
+type TruncateConfirmMsg struct {
+	Schema  string
+	Table   string
+	Cascade bool
+	SQL     string
+}
+
+// CloseTruncateMsg is sent when the truncate dialog is closed without
+// truncating
+type CloseTruncateMsg struct{}
+
+// Focus positions of the truncate dialog
+const (
+	truncateNameFocus = iota
+	truncateCascadeFocus
+	truncateRestartFocus
+	truncateFocusCount
+)
+
+// TruncateDialog confirms emptying a table. The table's name must be typed
+// exactly, so a stray Enter can't truncate the wrong one.
+type TruncateDialog struct {
+	Width int
+	Theme theme.Theme
+
+	Schema          string
+	Table           string
+	Cascade         bool // Also truncate tables referencing it by foreign key
+	RestartIdentity bool // Reset the sequences its columns own
+	Err             string
+
+	name  textinput.Model
+	focus int
+}
+
+// NewTruncateDialog creates a new truncate dialog
+func NewTruncateDialog(th theme.Theme) *TruncateDialog {
+	name := textinput.New()
+	name.Prompt = ""
+	name.CharLimit = 128
+	name.Width = 40
+	return &TruncateDialog{
+		Width: 64,
+		Theme: th,
+		name:  name,
+	}
+}
+
+// Open shows the dialog for schema.table with the options off
+func (d *TruncateDialog) Open(schema, table string) {
+	d.Schema = schema
+	d.Table = table
+	d.Cascade = false
+	d.RestartIdentity = false
+	d.Err = ""
+	d.focus = truncateNameFocus
+	d.name.SetValue("")
+	d.name.Placeholder = table
+	d.name.Focus()
+}
+
+// Init starts the cursor blinking
+func (d *TruncateDialog) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// SQL returns the statement confirming runs
+func (d *TruncateDialog) SQL() string {
+	return metadata.TruncateSQL(d.Schema, d.Table, d.Cascade, d.RestartIdentity)
+}
+
+// Update handles keyboard input. Tab moves between the name and the two
+// options, which Space toggles; Enter truncates once the name matches.
+func (d *TruncateDialog) Update(msg tea.KeyMsg) (*TruncateDialog, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return d, func() tea.Msg { return CloseTruncateMsg{} }
+	case "tab", "down":
+		d.setFocus((d.focus + 1) % truncateFocusCount)
+		return d, nil
+	case "shift+tab", "up":
+		d.setFocus((d.focus + truncateFocusCount - 1) % truncateFocusCount)
+		return d, nil
+	case "enter":
+		if d.name.Value() != d.Table {
+			d.Err = "Type " + d.Table + " exactly to confirm"
+			d.setFocus(truncateNameFocus)
+			return d, nil
+		}
+		confirm := TruncateConfirmMsg{Schema: d.Schema, Table: d.Table, Cascade: d.Cascade, SQL: d.SQL()}
+		return d, func() tea.Msg { return confirm }
+	case " ":
+		switch d.focus {
+		case truncateCascadeFocus:
+			d.Cascade = !d.Cascade
+			return d, nil
+		case truncateRestartFocus:
+			d.RestartIdentity = !d.RestartIdentity
+			return d, nil
+		}
+	}
+
+	if d.focus != truncateNameFocus {
+		return d, nil
+	}
+	d.Err = ""
+	var cmd tea.Cmd
+	d.name, cmd = d.name.Update(msg)
+	return d, cmd
+}
+
+// setFocus moves the focus, giving the name input the cursor when on it
+func (d *TruncateDialog) setFocus(focus int) {
+	d.focus = focus
+	if focus == truncateNameFocus {
+		d.name.Focus()
+	} else {
+		d.name.Blur()
+	}
+}
+
+// View renders the dialog
+func (d *TruncateDialog) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(d.Theme.Background).
+		Background(d.Theme.Error).
+		Padding(0, 1).
+		Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(d.Theme.Foreground)
+	focusedStyle := lipgloss.NewStyle().Foreground(d.Theme.Info).Bold(true)
+	errStyle := lipgloss.NewStyle().Foreground(d.Theme.Error)
+	keyStyle := lipgloss.NewStyle().Foreground(d.Theme.Info).Bold(true)
+	metaStyle := lipgloss.NewStyle().Foreground(d.Theme.Metadata)
+
+	option := func(focus int, on bool, label, help string) string {
+		box := "[ ] "
+		if on {
+			box = "[x] "
+		}
+		style := textStyle
+		if d.focus == focus {
+			style = focusedStyle
+		}
+		return style.Render(box+label) + metaStyle.Render("  "+help)
+	}
+
+	textWidth := d.Width - 4 // Border and padding
+	nameLabel := metaStyle
+	if d.focus == truncateNameFocus {
+		nameLabel = focusedStyle
+	}
+	sections := []string{
+		titleStyle.Render("Truncate Table"),
+		"",
+		textStyle.Render(wrapText("Every row of "+d.Schema+"."+d.Table+" will be deleted. This can't be undone once committed.", textWidth)),
+		"",
+		metaStyle.Render("Type the table name to confirm:"),
+		nameLabel.Render("> ") + d.name.View(),
+		"",
+		option(truncateCascadeFocus, d.Cascade, "CASCADE", "also empty tables referencing it"),
+		option(truncateRestartFocus, d.RestartIdentity, "RESTART IDENTITY", "reset its sequences"),
+		"",
+		metaStyle.Render(wrapText(d.SQL(), textWidth)),
+	}
+	if d.Err != "" {
+		sections = append(sections, "", errStyle.Render(d.Err))
+	}
+	sections = append(sections, "",
+		keyStyle.Render("Tab")+metaStyle.Render(": Next   ")+
+			keyStyle.Render("Space")+metaStyle.Render(": Toggle   ")+
+			keyStyle.Render("Enter")+metaStyle.Render(": Truncate   ")+
+			keyStyle.Render("Esc")+metaStyle.Render(": Cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(d.Theme.Error).
+		Width(d.Width).
+		Padding(1).
+		Render(strings.Join(sections, "\n"))
+}
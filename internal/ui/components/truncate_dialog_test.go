@@ -0,0 +1,75 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rebelice/lazypg/internal/ui/theme"
+)
+
+func TestTruncateDialog(t *testing.T) {
+	d := NewTruncateDialog(theme.DefaultTheme())
+	d.Open("public", "orders")
+
+	typeText := func(s string) {
+		d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+	enter := func() tea.Cmd {
+		_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		return cmd
+	}
+
+	// A name that is not exactly the table's does not confirm
+	for _, name := range []string{"", "order", "Orders", "public.orders"} {
+		d.name.SetValue(name)
+		if cmd := enter(); cmd != nil {
+			t.Errorf("Enter with %q confirmed", name)
+		}
+		if d.Err == "" {
+			t.Errorf("Enter with %q set no error", name)
+		}
+	}
+
+	d.name.SetValue("")
+	typeText("orders")
+	if d.Err != "" {
+		t.Errorf("typing left the error %q", d.Err)
+	}
+
+	// Tab to the options; Space toggles them and types nothing
+	d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	d.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	d.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if !d.Cascade || !d.RestartIdentity {
+		t.Fatalf("Cascade = %v, RestartIdentity = %v, want both on", d.Cascade, d.RestartIdentity)
+	}
+	if d.name.Value() != "orders" {
+		t.Errorf("name = %q, want it unchanged by the toggles", d.name.Value())
+	}
+
+	cmd := enter()
+	if cmd == nil {
+		t.Fatal("Enter with the exact name did not confirm")
+	}
+	msg, ok := cmd().(TruncateConfirmMsg)
+	if !ok {
+		t.Fatalf("Enter sent %#v, want TruncateConfirmMsg", cmd())
+	}
+	if want := `TRUNCATE TABLE "public"."orders" RESTART IDENTITY CASCADE`; msg.SQL != want {
+		t.Errorf("SQL = %q, want %q", msg.SQL, want)
+	}
+
+	// Reopening starts over with the options off
+	d.Open("public", "items")
+	if d.Cascade || d.RestartIdentity || d.name.Value() != "" {
+		t.Error("Open() kept the previous table's input")
+	}
+	_, cmd = d.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc did not close the dialog")
+	}
+	if _, ok := cmd().(CloseTruncateMsg); !ok {
+		t.Errorf("Esc sent %#v, want CloseTruncateMsg", cmd())
+	}
+}
@@ -19,8 +19,14 @@ func GetGlobalKeys() []KeyBinding {
 		{"q, Ctrl+C", "Quit application"},
 		{"Esc/Enter", "Dismiss error"},
 		{"Ctrl+K", "Open command palette"},
+		{"Ctrl+G", "Jump to recent objects"},
+		{"Ctrl+O", "Switch database on the same server"},
 		{"Ctrl+P", "Quick query"},
+		{"F2", "Cycle SQL editor height"},
+		{"F11", "Fullscreen SQL editor"},
 		{"Tab", "Switch panel focus"},
+		{"Alt+1/2/3", "Focus tree/data/SQL editor"},
+		{"Ctrl+T", "Switch bottom-bar key hints"},
 		{"c", "Open connection dialog"},
 		{"r, F5", "Refresh current view"},
 	}
@@ -44,6 +50,10 @@ func GetNavigationKeys() []KeyBinding {
 		{"→/l", "Expand or move right"},
 		{"Enter", "Select item"},
 		{"Backspace", "Go to parent"},
+		{"E / C", "Expand all loaded / collapse all (tree)"},
+		{".", "Show/hide system schemas (tree)"},
+		{"b", "Bookmark object in favorites (tree)"},
+		{"T", "Truncate table (tree)"},
 	}
 }
 
@@ -52,16 +62,38 @@ func GetDataViewKeys() []KeyBinding {
 	return []KeyBinding{
 		{"f", "Open filter builder"},
 		{"Ctrl+F", "Quick filter from cell"},
-		{"Ctrl+R", "Clear filter"},
-		{"J", "Open JSONB viewer (on JSONB cell)"},
+		{"w", "Add cell as a filter condition"},
+		{"Ctrl+W", "Copy cell as a WHERE condition"},
+		{"Ctrl+Y", "Copy selected, pinned or loaded rows as TSV"},
+		{"Space", "Select/deselect row"},
+		{"A/I/X", "Select all loaded rows/invert/clear selection"},
+		{"#", "Count rows matching the active filter"},
+		{"Ctrl+X", "Clear the filter and reload"},
+		{"Ctrl+R", "Re-run query, or refresh a table tab"},
+		{"m", "Show/hide query messages (query result tab)"},
+		{">", "Load the next page of a limited query result"},
+		{"o", "Set LIMIT/OFFSET (table tab)"},
+		{"e", "Edit the selected row in a form (table tab)"},
+		{"Enter/v", "Open JSONB viewer (on JSON cell)"},
 		{"s", "Toggle sort on column (ASC/DESC)"},
 		{"S", "Toggle NULLS FIRST/LAST"},
 		{"h/l", "Move column left/right"},
+		{"Ctrl+←/→", "Narrow/widen column"},
+		{"W", "Reset column widths"},
 		{"H/L", "Jump scroll half screen"},
 		{"0", "Jump to first column"},
 		{"$", "Jump to last column"},
 		{"/", "Open search (Tab to toggle mode)"},
 		{"n/N", "Next/Previous search match"},
+		{"`", "Quick filter loaded rows (or start typing)"},
+		{"p", "Toggle preview pane"},
+		{"+/-", "Grow/shrink preview pane"},
+		{"P", "Dock preview pane bottom/right"},
+		{"u", "Reveal/hide a masked cell"},
+		{"Ctrl+L", "Show/hide row numbers"},
+		{"Ctrl+N", "Relative/absolute row numbers"},
+		{"|", "Split view: show two tabs at once"},
+		{"\\", "Switch split view pane"},
 	}
 }
 
@@ -69,9 +101,15 @@ func GetDataViewKeys() []KeyBinding {
 func GetStructureViewKeys() []KeyBinding {
 	return []KeyBinding{
 		{"1/2/3/4", "Switch tabs (Data/Columns/Constraints/Indexes)"},
+		{"{ / }", "Previous/next structure tab"},
 		{"↑↓ or j/k", "Navigate rows"},
 		{"y", "Copy name"},
 		{"Y", "Copy definition"},
+		{"D", "Copy constraint/index DDL"},
+		{"E", "Open constraint/index DDL in a tab"},
+		{"E", "Open a view's definition (no DDL selected)"},
+		{"O", "Columns in logical/storage order"},
+		{"Enter", "Go to a column's sequence (→ SEQ)"},
 	}
 }
 
@@ -0,0 +1,122 @@
+package theme
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/termenv"
+)
+
+// Color modes accepted by the ui.color_mode setting
+const (
+	ColorModeAuto      = "auto"
+	ColorModeTrueColor = "truecolor"
+	ColorMode256       = "256"
+	ColorMode16        = "16"
+)
+
+// Profile returns the color profile for a color mode. forced is false for
+// "auto" (or an unknown mode), which uses what the terminal reports.
+func Profile(mode string) (profile termenv.Profile, forced bool) {
+	switch mode {
+	case ColorModeTrueColor:
+		return termenv.TrueColor, true
+	case ColorMode256:
+		return termenv.ANSI256, true
+	case ColorMode16:
+		return termenv.ANSI, true
+	default:
+		return lipgloss.ColorProfile(), false
+	}
+}
+
+// ForProfile returns the theme with its colors mapped to what a terminal
+// with the given profile can show. 256-color terminals get the nearest
+// palette entry. 16-color terminals get colors picked by hue rather than
+// nearest RGB, so the pastel accents keep their identity (blue stays blue)
+// instead of washing out to white or gray.
+func (t Theme) ForProfile(p termenv.Profile) Theme {
+	if p == termenv.TrueColor {
+		return t
+	}
+
+	v := reflect.ValueOf(&t).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if c, ok := field.Interface().(lipgloss.Color); ok {
+			field.Set(reflect.ValueOf(degrade(c, p)))
+		}
+	}
+
+	if p != termenv.ANSI256 {
+		// With so few grays, the selection would vanish into the background
+		// and zebra rows would flicker between black and gray
+		t.Selection = lipgloss.Color("8")
+		t.TableRowSelected = lipgloss.Color("8")
+		t.TableRowOdd = t.TableRowEven
+	}
+	return t
+}
+
+// degrade maps one color to the profile
+func degrade(c lipgloss.Color, p termenv.Profile) lipgloss.Color {
+	tc := termenv.TrueColor.Color(string(c))
+	if tc == nil {
+		return c
+	}
+
+	if p == termenv.ANSI256 {
+		switch v := termenv.ANSI256.Convert(tc).(type) {
+		case termenv.ANSI256Color:
+			return lipgloss.Color(strconv.Itoa(int(v)))
+		case termenv.ANSIColor:
+			return lipgloss.Color(strconv.Itoa(int(v)))
+		}
+		return c
+	}
+
+	if _, ok := tc.(termenv.ANSIColor); ok {
+		return c
+	}
+	return ansi16(termenv.ConvertToRGB(tc))
+}
+
+// ansi16 picks the ANSI color for an RGB color. Colors with little chroma
+// become black, gray or white by lightness; the rest take the bright
+// variant of the nearest hue, which reads best on a dark background.
+func ansi16(rgb colorful.Color) lipgloss.Color {
+	hi := max(rgb.R, rgb.G, rgb.B)
+	lo := min(rgb.R, rgb.G, rgb.B)
+	lightness := (hi + lo) / 2
+
+	if hi-lo < 0.2 {
+		switch {
+		case lightness < 0.2:
+			return lipgloss.Color("0")
+		case lightness < 0.5:
+			return lipgloss.Color("8")
+		case lightness < 0.8:
+			return lipgloss.Color("7")
+		default:
+			return lipgloss.Color("15")
+		}
+	}
+
+	hue, _, _ := rgb.Hsv()
+	switch {
+	case hue < 20 || hue >= 330:
+		return lipgloss.Color("9") // Red
+	case hue < 70:
+		return lipgloss.Color("11") // Yellow
+	case hue < 160:
+		return lipgloss.Color("10") // Green
+	case hue < 200:
+		return lipgloss.Color("14") // Cyan
+	case hue < 260:
+		return lipgloss.Color("12") // Blue
+	default:
+		return lipgloss.Color("13") // Magenta
+	}
+}
@@ -0,0 +1,60 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestForProfile_16Colors(t *testing.T) {
+	th := CatppuccinMochaTheme().ForProfile(termenv.ANSI)
+
+	tests := map[string]struct {
+		got, want lipgloss.Color
+	}{
+		"background":     {th.Background, "0"},
+		"text":           {th.Foreground, "15"},
+		"border":         {th.Border, "8"},
+		"focused border": {th.BorderFocused, "12"},
+		"success":        {th.Success, "10"},
+		"warning":        {th.Warning, "11"},
+		"error":          {th.Error, "9"},
+		"info":           {th.Info, "14"},
+		"keyword":        {th.Keyword, "13"},
+		"selection":      {th.Selection, "8"},
+	}
+	for name, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", name, tt.got, tt.want)
+		}
+	}
+	if th.Selection == th.Background {
+		t.Error("selection is indistinguishable from the background")
+	}
+	if th.TableRowOdd != th.TableRowEven {
+		t.Error("zebra rows should share the background in 16 colors")
+	}
+
+	// 256-color themes degrade the same way
+	if got := DefaultTheme().ForProfile(termenv.ANSI).Error; got != "9" {
+		t.Errorf("default theme error = %q, want 9", got)
+	}
+}
+
+func TestForProfile_256Colors(t *testing.T) {
+	th := CatppuccinMochaTheme().ForProfile(termenv.ANSI256)
+	if th.Background == CatppuccinMochaTheme().Background {
+		t.Errorf("background %q was not mapped to the palette", th.Background)
+	}
+	if th.Selection == th.Background {
+		t.Error("selection is indistinguishable from the background")
+	}
+	// Palette colors are kept as they are
+	if got := DefaultTheme().ForProfile(termenv.ANSI256); got != DefaultTheme() {
+		t.Error("a 256-color theme changed in 256 colors")
+	}
+	if got := CatppuccinMochaTheme().ForProfile(termenv.TrueColor); got != CatppuccinMochaTheme() {
+		t.Error("truecolor changed the theme")
+	}
+}